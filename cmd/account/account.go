@@ -1,9 +1,13 @@
 package account
 
 import (
+	"context"
+	"os"
+
 	"github.com/spf13/cobra"
 
 	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/extension"
 )
 
 var accountRootCmd = &cobra.Command{
@@ -17,11 +21,37 @@ type ServiceContainer struct {
 
 var services *ServiceContainer
 
-func Register(rootCmd *cobra.Command, onInit func(commandName string) (*ServiceContainer, error)) {
+func Register(rootCmd *cobra.Command, onInit func(ctx context.Context, commandName string) (*ServiceContainer, error)) {
 	accountRootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
-		ser, err := onInit(cmd.Name())
+		if err := selectProfile(cmd); err != nil {
+			return err
+		}
+
+		ser, err := onInit(cmd.Context(), cmd.Name())
 		services = ser
 		return err
 	}
+	accountRootCmd.PersistentFlags().String("profile", "", "Shopware Account profile to use, defaults to $"+account_api.ProfileEnv+", the profile of .shopware-extension.yml or the profile selected with account profile use")
 	rootCmd.AddCommand(accountRootCmd)
 }
+
+// selectProfile stores the profile of the --profile flag or the extension
+// config of the current directory in the context of the command.
+func selectProfile(cmd *cobra.Command) error {
+	ctx := cmd.Context()
+
+	profile, _ := cmd.Flags().GetString("profile")
+	if profile != "" {
+		if err := account_api.ValidateProfileName(profile); err != nil {
+			return err
+		}
+
+		ctx = account_api.WithProfile(ctx, profile)
+	} else if cwd, err := os.Getwd(); err == nil {
+		ctx = account_api.WithDirectoryProfile(ctx, extension.AccountProfileForDirectory(ctx, cwd))
+	}
+
+	cmd.SetContext(ctx)
+
+	return nil
+}
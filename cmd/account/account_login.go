@@ -21,9 +21,18 @@ var loginCmd = &cobra.Command{
 			return err
 		}
 
+		profile, source := accountApi.ActiveProfile(cmd.Context())
+
 		fmt.Println()
-		fmt.Println(tui.GreenText.Render("  Login successful!"))
-		fmt.Println(tui.DimText.Render("  To logout, run: shopware-cli account logout"))
+		fmt.Println(tui.GreenText.Render(fmt.Sprintf("  Login successful for profile %s!", profile)))
+
+		if source == accountApi.ProfileSourceFlag {
+			fmt.Println(tui.DimText.Render(fmt.Sprintf("  To use it by default, run: shopware-cli account profile use %s", profile)))
+			fmt.Println(tui.DimText.Render(fmt.Sprintf("  To logout, run: shopware-cli account logout --profile %s", profile)))
+		} else {
+			fmt.Println(tui.DimText.Render("  To logout, run: shopware-cli account logout"))
+		}
+
 		fmt.Println()
 
 		return nil
@@ -14,12 +14,13 @@ var logoutCmd = &cobra.Command{
 	Short: "Logout from Shopware Account",
 	Long:  ``,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		err := accountApi.InvalidateTokenCache()
+		err := accountApi.InvalidateTokenCache(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("cannot invalidate token cache: %w", err)
 		}
 
-		logging.FromContext(cmd.Context()).Infof("You have been logged out")
+		profile, _ := accountApi.ActiveProfile(cmd.Context())
+		logging.FromContext(cmd.Context()).Infof("You have been logged out from profile %s", profile)
 
 		return nil
 	},
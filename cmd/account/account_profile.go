@@ -0,0 +1,91 @@
+package account
+
+import (
+	"fmt"
+
+	"charm.land/lipgloss/v2"
+	liplogtable "charm.land/lipgloss/v2/table"
+	"github.com/spf13/cobra"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/tui"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var accountProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage Shopware Account profiles",
+	Long: `Profiles keep separate logins for multiple Shopware Accounts. Create one with account login --profile <name>.
+
+The profile is selected by the --profile flag, the ` + account_api.ProfileEnv + ` environment variable, account.profile in the .shopware-extension.yml of the current directory or its parents, or the profile selected with account profile use, in this order.`,
+	// Managing profiles does not need a login
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		return selectProfile(cmd)
+	},
+}
+
+var accountProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all profiles",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		profiles, err := account_api.ListProfiles()
+		if err != nil {
+			return err
+		}
+
+		active, source := account_api.ActiveProfile(cmd.Context())
+
+		cellStyle := lipgloss.NewStyle().Padding(0, 1)
+
+		t := liplogtable.New().
+			Border(lipgloss.NormalBorder()).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				return cellStyle
+			}).
+			Headers("Profile", "Active")
+
+		for _, profile := range profiles {
+			status := ""
+			if profile == active {
+				status = tui.GreenText.Render(fmt.Sprintf("Yes (%s)", source))
+			}
+
+			t.Row(profile, status)
+		}
+
+		if _, err := fmt.Fprintln(cmd.OutOrStdout(), t.Render()); err != nil {
+			return err
+		}
+
+		if len(profiles) == 0 {
+			logging.FromContext(cmd.Context()).Infof("No profiles found, create one with: shopware-cli account login --profile <name>")
+		}
+
+		return nil
+	},
+}
+
+var accountProfileUseCmd = &cobra.Command{
+	Use:   "use [profile]",
+	Short: "Selects the profile for the following account commands",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := account_api.UseProfile(args[0]); err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Using profile %s", args[0])
+
+		if active, source := account_api.ActiveProfile(cmd.Context()); active != args[0] {
+			logging.FromContext(cmd.Context()).Warnf("Profile %s stays active here, it is selected by %s", active, source)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	accountRootCmd.AddCommand(accountProfileCmd)
+	accountProfileCmd.AddCommand(accountProfileListCmd)
+	accountProfileCmd.AddCommand(accountProfileUseCmd)
+}
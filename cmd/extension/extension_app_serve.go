@@ -0,0 +1,273 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/appserver"
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionAppServeCmd = &cobra.Command{
+	Use:   "app-serve [path]",
+	Short: "Serve a local app backend for developing apps",
+	Long: `Serve a local stand-in for the backend of an app.
+
+The server reads the manifest.xml and answers on the paths of its URLs:
+
+  - the registration and confirmation handshake, signed with the setup secret of the manifest
+  - webhooks, action buttons, payment, tax and gateway calls, verifying the shopware-shop-signature
+  - admin modules, showing the query the Administration sent
+
+Every request is shown in a terminal UI, or printed as one JSON object per line with --output json.
+Action buttons answer with a notification by default. Use --responses to configure other answers:
+
+  default_action:
+    type: notification
+    message: Done
+  actions:
+    open-details:
+      type: openModal
+      url: https://example.com/modal
+  routes:
+    /payment/pay:
+      body:
+        status: paid
+
+The shop has to reach the server at --public-url. --install-into copies the app into
+custom/apps of a local shop and rewrites all URLs of the manifest to the public URL,
+like extension zip --overwrite-app-backend-url does.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		publicURL, _ := cmd.Flags().GetString("public-url")
+		secret, _ := cmd.Flags().GetString("secret")
+		responsesFile, _ := cmd.Flags().GetString("responses")
+		output, _ := cmd.Flags().GetString("output")
+		installInto, _ := cmd.Flags().GetString("install-into")
+
+		extPath := "."
+		if len(args) == 1 {
+			extPath = args[0]
+		}
+
+		extPath, err := filepath.Abs(extPath)
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(cmd.Context(), extPath)
+		if err != nil {
+			return err
+		}
+
+		app, ok := ext.(*extension.App)
+		if !ok {
+			return fmt.Errorf("app-serve only works with apps, %s is a %s", extPath, ext.GetType())
+		}
+
+		manifest := app.GetManifest()
+
+		if manifest.Setup == nil || manifest.Setup.RegistrationUrl == "" {
+			return fmt.Errorf("the manifest.xml has no setup:registrationUrl, the app does not need a backend")
+		}
+
+		if secret == "" {
+			secret = manifest.Setup.Secret
+		}
+
+		if secret == "" {
+			return fmt.Errorf("the manifest.xml has no setup:secret, add one or pass --secret")
+		}
+
+		if publicURL == "" {
+			publicURL = "http://" + listen
+		}
+
+		if output == "" {
+			output = "json"
+			if isatty.IsTerminal(os.Stdout.Fd()) {
+				output = "tui"
+			}
+		}
+
+		if output != "tui" && output != "json" {
+			return fmt.Errorf("unknown output %q, expected tui or json", output)
+		}
+
+		var responses *appserver.Responses
+		if responsesFile != "" {
+			if responses, err = appserver.ReadResponses(responsesFile); err != nil {
+				return err
+			}
+		}
+
+		if installInto != "" {
+			if err := installAppForServe(cmd.Context(), app, extPath, installInto, publicURL, secret); err != nil {
+				return err
+			}
+		}
+
+		store, err := appserver.NewShopStore(filepath.Join(system.GetShopwareCliCacheDir(), "app-serve", manifest.Meta.Name+".json"))
+		if err != nil {
+			return err
+		}
+
+		listener, err := net.Listen("tcp", listen)
+		if err != nil {
+			return fmt.Errorf("listen on %s: %w", listen, err)
+		}
+
+		if output == "json" {
+			return serveApp(cmd.Context(), listener, appserver.Options{
+				AppName:   manifest.Meta.Name,
+				AppSecret: secret,
+				Manifest:  manifest,
+				PublicURL: publicURL,
+				Responses: responses,
+				Store:     store,
+				OnEvent: func(event appserver.Event) {
+					_ = json.NewEncoder(os.Stdout).Encode(event)
+				},
+			}, func(server *appserver.Server) {
+				logAppServePaths(cmd.Context(), server, publicURL)
+			})
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		program := tea.NewProgram(appserver.NewModel(publicURL), tea.WithContext(ctx))
+
+		serveErr := make(chan error, 1)
+
+		go func() {
+			serveErr <- serveApp(ctx, listener, appserver.Options{
+				AppName:   manifest.Meta.Name,
+				AppSecret: secret,
+				Manifest:  manifest,
+				PublicURL: publicURL,
+				Responses: responses,
+				Store:     store,
+				OnEvent: func(event appserver.Event) {
+					program.Send(appserver.EventMsg(event))
+				},
+			}, nil)
+		}()
+
+		if _, err := program.Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+			return err
+		}
+
+		cancel()
+
+		return <-serveErr
+	},
+}
+
+func serveApp(ctx context.Context, listener net.Listener, opts appserver.Options, started func(*appserver.Server)) error {
+	server, err := appserver.New(opts)
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if started != nil {
+		started(server)
+	}
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func logAppServePaths(ctx context.Context, server *appserver.Server, publicURL string) {
+	paths := server.Paths()
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+
+	slices.Sort(sorted)
+
+	logging.FromContext(ctx).Infof("Serving app backend on %s", publicURL)
+
+	for _, path := range sorted {
+		logging.FromContext(ctx).Infof("  %s: %s", path, strings.Join(paths[path], ", "))
+	}
+}
+
+// installAppForServe copies the app into custom/apps of a local shop and points
+// all URLs of the manifest to the app-serve server.
+func installAppForServe(ctx context.Context, app *extension.App, extPath, shopRoot, publicURL, secret string) error {
+	name, err := app.GetName()
+	if err != nil {
+		return err
+	}
+
+	target := filepath.Join(shopRoot, "custom", "apps", name)
+
+	if target == extPath {
+		return fmt.Errorf("--install-into would overwrite the app itself")
+	}
+
+	if err := os.RemoveAll(target); err != nil {
+		return fmt.Errorf("remove previous copy: %w", err)
+	}
+
+	if err := system.CopyFiles(extPath, target); err != nil {
+		return fmt.Errorf("copy app: %w", err)
+	}
+
+	if err := extension.BuildModifier(app, target, extension.BuildModifierConfig{
+		AppBackendUrl:    publicURL,
+		AppBackendSecret: secret,
+	}); err != nil {
+		return fmt.Errorf("build modifier: %w", err)
+	}
+
+	logging.FromContext(ctx).Infof("Installed %s into %s, run bin/console app:install --activate %s or app:refresh", name, target, name)
+
+	return nil
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionAppServeCmd)
+	extensionAppServeCmd.Flags().String("listen", "127.0.0.1:8000", "Address to listen on")
+	extensionAppServeCmd.Flags().String("public-url", "", "URL the shop reaches the server at, defaults to http://<listen>")
+	extensionAppServeCmd.Flags().String("secret", "", "App secret, defaults to setup:secret of the manifest")
+	extensionAppServeCmd.Flags().String("responses", "", "YAML file with the responses for action buttons and routes")
+	extensionAppServeCmd.Flags().String("output", "", "Output format: tui or json, defaults to tui in a terminal")
+	extensionAppServeCmd.Flags().String("install-into", "", "Copy the app with rewritten URLs into custom/apps of this Shopware project")
+}
@@ -0,0 +1,48 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/extension"
+)
+
+func TestInstallAppForServeRewritesManifest(t *testing.T) {
+	appDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "manifest.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<manifest>
+    <meta>
+        <name>MyApp</name>
+        <label>My App</label>
+        <version>1.0.0</version>
+        <license>MIT</license>
+    </meta>
+    <setup>
+        <registrationUrl>https://my-app.test/app/register</registrationUrl>
+    </setup>
+    <webhooks>
+        <webhook name="orderPlaced" url="https://my-app.test/app/webhook" event="checkout.order.placed"/>
+    </webhooks>
+</manifest>`), 0o644))
+
+	ext, err := extension.GetExtensionByFolder(t.Context(), appDir)
+	require.NoError(t, err)
+
+	shopRoot := t.TempDir()
+	require.NoError(t, installAppForServe(t.Context(), ext.(*extension.App), appDir, shopRoot, "http://host.docker.internal:8000", "s3cr3t"))
+
+	manifest, err := os.ReadFile(filepath.Join(shopRoot, "custom", "apps", "MyApp", "manifest.xml"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(manifest), "http://host.docker.internal:8000/app/register")
+	assert.Contains(t, string(manifest), `url="http://host.docker.internal:8000/app/webhook"`)
+	assert.Contains(t, string(manifest), "<secret>s3cr3t</secret>")
+
+	original, err := os.ReadFile(filepath.Join(appDir, "manifest.xml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(original), "host.docker.internal")
+}
@@ -1,6 +1,7 @@
 package extension
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,14 +15,30 @@ import (
 var extensionAssetBundleCmd = &cobra.Command{
 	Use:   "build [path]",
 	Short: "Builds assets for extensions",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Builds assets for extensions.
+
+With --all or a glob pattern as path, the assets of every found extension are
+built separately in the order of their composer dependencies.`,
+	Args: bulkArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		assetCfg := extension.AssetBuildConfig{
-			ShopwareRoot: os.Getenv("SHOPWARE_PROJECT_ROOT"),
-		}
-		if assetCfg.ShopwareRoot != "" {
-			assetCfg.Executor = executor.NewLocal(assetCfg.ShopwareRoot)
+		if isBulkRun(cmd, args) {
+			extensions, err := resolveBulkExtensions(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			results, err := extension.RunBulk(cmd.Context(), extensions, bulkConcurrency(cmd), func(ctx context.Context, ext extension.Extension) error {
+				return buildExtensionAssets(ctx, []extension.Extension{ext})
+			})
+			if err != nil {
+				return err
+			}
+
+			printBulkResults(cmd.OutOrStdout(), results)
+
+			return bulkError(cmd, results)
 		}
+
 		validatedExtensions := make([]extension.Extension, 0)
 
 		for _, arg := range args {
@@ -38,29 +55,43 @@ var extensionAssetBundleCmd = &cobra.Command{
 			validatedExtensions = append(validatedExtensions, ext)
 		}
 
-		if assetCfg.ShopwareRoot != "" {
-			constraint, err := extension.GetShopwareProjectConstraint(assetCfg.ShopwareRoot)
-			if err != nil {
-				return fmt.Errorf("cannot get shopware version constraint from project %s: %w", assetCfg.ShopwareRoot, err)
-			}
-			assetCfg.ShopwareVersion = constraint
-		} else {
-			constraint, err := extension.GetShopwareVersionConstraintForBuild(validatedExtensions[0])
-			if err != nil {
-				return fmt.Errorf("cannot get shopware version constraint: %w", err)
-			}
+		return buildExtensionAssets(cmd.Context(), validatedExtensions)
+	},
+}
 
-			assetCfg.ShopwareVersion = constraint
-		}
+// buildExtensionAssets builds the assets of the extensions together, for the
+// Shopware version of SHOPWARE_PROJECT_ROOT or of the first extension.
+func buildExtensionAssets(ctx context.Context, extensions []extension.Extension) error {
+	assetCfg := extension.AssetBuildConfig{
+		ShopwareRoot: os.Getenv("SHOPWARE_PROJECT_ROOT"),
+	}
+	if assetCfg.ShopwareRoot != "" {
+		assetCfg.Executor = executor.NewLocal(assetCfg.ShopwareRoot)
+	}
 
-		if err := extension.BuildAssetsForExtensions(cmd.Context(), extension.ConvertExtensionsToSources(cmd.Context(), validatedExtensions), assetCfg); err != nil {
-			return fmt.Errorf("cannot build assets: %w", err)
+	if assetCfg.ShopwareRoot != "" {
+		constraint, err := extension.GetShopwareProjectConstraint(assetCfg.ShopwareRoot)
+		if err != nil {
+			return fmt.Errorf("cannot get shopware version constraint from project %s: %w", assetCfg.ShopwareRoot, err)
+		}
+		assetCfg.ShopwareVersion = constraint
+	} else {
+		constraint, err := extension.GetShopwareVersionConstraintForBuild(extensions[0])
+		if err != nil {
+			return fmt.Errorf("cannot get shopware version constraint: %w", err)
 		}
 
-		return nil
-	},
+		assetCfg.ShopwareVersion = constraint
+	}
+
+	if err := extension.BuildAssetsForExtensions(ctx, extension.ConvertExtensionsToSources(ctx, extensions), assetCfg); err != nil {
+		return fmt.Errorf("cannot build assets: %w", err)
+	}
+
+	return nil
 }
 
 func init() {
 	extensionRootCmd.AddCommand(extensionAssetBundleCmd)
+	addBulkFlags(extensionAssetBundleCmd)
 }
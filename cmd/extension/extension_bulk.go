@@ -0,0 +1,156 @@
+package extension
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	liplogtable "charm.land/lipgloss/v2/table"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/tui"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// zipManifestFileName is written next to the zips of a bulk package run.
+const zipManifestFileName = "extensions-manifest.json"
+
+// addBulkFlags adds the flags to run a command for many extensions.
+func addBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, "Run for all extensions of the project or below the given directory")
+	cmd.Flags().Int("parallel", 4, "How many extensions are processed at the same time with --all or a glob pattern")
+}
+
+// isBulkRun reports whether the command runs for many extensions, either with
+// --all or a glob pattern as argument.
+func isBulkRun(cmd *cobra.Command, args []string) bool {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return true
+	}
+
+	return slices.ContainsFunc(args, func(arg string) bool {
+		return strings.ContainsAny(arg, "*?[")
+	})
+}
+
+// bulkArgs allows no path with --all and requires one otherwise.
+func bulkArgs(cmd *cobra.Command, args []string) error {
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		return cobra.MaximumNArgs(1)(cmd, args)
+	}
+
+	return cobra.MinimumNArgs(1)(cmd, args)
+}
+
+func resolveBulkExtensions(cmd *cobra.Command, args []string) ([]extension.Extension, error) {
+	all, _ := cmd.Flags().GetBool("all")
+
+	extensions, err := extension.ResolveExtensions(cmd.Context(), args, all)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(cmd.Context()).Infof("Found %d extensions", len(extensions))
+
+	return extensions, nil
+}
+
+func bulkConcurrency(cmd *cobra.Command) int {
+	parallel, _ := cmd.Flags().GetInt("parallel")
+
+	return parallel
+}
+
+// bulkError prints the failed extensions and returns an error when any failed.
+func bulkError(cmd *cobra.Command, results []extension.BulkResult) error {
+	failed := 0
+
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			logging.FromContext(cmd.Context()).Errorf("%s: %v", result.Name, result.Err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d extensions failed", failed, len(results))
+	}
+
+	return nil
+}
+
+// printBulkResults prints a table with the outcome of every extension.
+func printBulkResults(w io.Writer, results []extension.BulkResult) {
+	t := liplogtable.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("Extension", "Status")
+
+	for _, result := range results {
+		status := tui.GreenText.Render("ok")
+		if result.Err != nil {
+			status = tui.RedText.Render("failed")
+		}
+
+		t.Row(result.Name, status)
+	}
+
+	_, _ = fmt.Fprintln(w, t.Render())
+}
+
+// zipManifest lists the zips created by a bulk package run.
+type zipManifest struct {
+	Extensions []zipManifestEntry `json:"extensions"`
+}
+
+type zipManifestEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	File    string `json:"file"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+func newZipManifestEntry(name, version, file string) (zipManifestEntry, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return zipManifestEntry{}, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return zipManifestEntry{}, err
+	}
+
+	return zipManifestEntry{
+		Name:    name,
+		Version: version,
+		File:    filepath.Base(file),
+		Size:    size,
+		SHA256:  hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func writeZipManifest(dir string, manifest zipManifest) (string, error) {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	manifestPath := filepath.Join(dir, zipManifestFileName)
+
+	return manifestPath, os.WriteFile(manifestPath, content, 0o644)
+}
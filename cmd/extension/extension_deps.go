@@ -0,0 +1,165 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	liplogtable "charm.land/lipgloss/v2/table"
+	cp "github.com/otiai10/copy"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/tui"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var extensionDepsCmd = &cobra.Command{
+	Use:   "deps [path]",
+	Short: "Report the composer packages bundled into the zip of an extension",
+	Long: `Install the composer dependencies like extension package does and list the
+bundled vendor packages with their versions and licenses.
+
+Packages which the targeted Shopware versions already ship and packages whose
+license does not allow bundling them into the extension are flagged.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputJSON, _ := cmd.Flags().GetBool("json")
+		sbomFile, _ := cmd.Flags().GetString("sbom")
+
+		extPath, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(cmd.Context(), extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		tempDir, err := os.MkdirTemp("", "extension-deps")
+		if err != nil {
+			return fmt.Errorf("create temp directory: %w", err)
+		}
+
+		defer func() {
+			_ = os.RemoveAll(tempDir)
+		}()
+
+		if err := cp.Copy(extPath, tempDir, copyOptions()); err != nil {
+			return fmt.Errorf("copy files: %w", err)
+		}
+
+		// an existing vendor folder would hide what the zip contains
+		if err := os.RemoveAll(filepath.Join(tempDir, "vendor")); err != nil {
+			return err
+		}
+
+		if err := extension.PrepareFolderForZipping(cmd.Context(), tempDir, ext, ext.GetExtensionConfig()); err != nil {
+			return fmt.Errorf("prepare package: %w", err)
+		}
+
+		report, err := extension.BuildDependencyReport(cmd.Context(), tempDir, ext)
+		if err != nil {
+			return err
+		}
+
+		if sbomFile != "" {
+			if err := report.WriteSBOM(ext, sbomFile, cmd.Root().Version); err != nil {
+				return fmt.Errorf("write SBOM: %w", err)
+			}
+
+			logging.FromContext(cmd.Context()).Infof("Wrote SBOM to %s", sbomFile)
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+
+			if err := encoder.Encode(report); err != nil {
+				return err
+			}
+		} else {
+			printDependencyReport(cmd.OutOrStdout(), report)
+		}
+
+		if report.HasIssues() {
+			return fmt.Errorf("the bundled packages have conflicts or license issues")
+		}
+
+		return nil
+	},
+}
+
+func printDependencyReport(w io.Writer, report *extension.DependencyReport) {
+	if len(report.Packages) == 0 {
+		_, _ = fmt.Fprintln(w, "The zip does not bundle any composer packages")
+		return
+	}
+
+	t := liplogtable.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("Package", "Version", "License", "Issues")
+
+	for _, pkg := range report.Packages {
+		t.Row(pkg.Name, pkg.Version, strings.Join(pkg.Licenses, ", "), strings.Join(dependencyIssues(pkg), "\n"))
+	}
+
+	_, _ = fmt.Fprintln(w, t.Render())
+
+	if len(report.ShopwareVersions) == 0 {
+		_, _ = fmt.Fprintf(w, "Not compared against Shopware, the extension is licensed under %s\n", report.License)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Compared against Shopware %s, the extension is licensed under %s\n", strings.Join(report.ShopwareVersions, " and "), report.License)
+}
+
+// dependencyIssues returns the rendered conflicts and license issue of the package.
+func dependencyIssues(pkg extension.BundledPackage) []string {
+	var issues []string
+
+	for _, conflict := range pkg.Conflicts {
+		if conflict.Compatible {
+			issues = append(issues, tui.YellowText.Render(fmt.Sprintf("Shopware %s ships %s as well", conflict.ShopwareVersion, conflict.Shipped)))
+		} else {
+			issues = append(issues, tui.RedText.Render(fmt.Sprintf("conflicts with %s of Shopware %s", conflict.Shipped, conflict.ShopwareVersion)))
+		}
+	}
+
+	if pkg.LicenseIssue != "" {
+		issues = append(issues, tui.RedText.Render(pkg.LicenseIssue))
+	}
+
+	return issues
+}
+
+// logDependencyReport logs the issues of the bundled packages while packaging.
+func logDependencyReport(ctx context.Context, report *extension.DependencyReport) {
+	logging.FromContext(ctx).Infof("Bundling %d composer packages", len(report.Packages))
+
+	for _, pkg := range report.Packages {
+		for _, conflict := range pkg.Conflicts {
+			if conflict.Compatible {
+				logging.FromContext(ctx).Warnf("%s %s is shipped by Shopware %s as well", pkg.Name, pkg.Version, conflict.ShopwareVersion)
+			} else {
+				logging.FromContext(ctx).Warnf("%s %s conflicts with %s of Shopware %s", pkg.Name, pkg.Version, conflict.Shipped, conflict.ShopwareVersion)
+			}
+		}
+
+		if pkg.LicenseIssue != "" {
+			logging.FromContext(ctx).Warnf("%s: %s", pkg.Name, pkg.LicenseIssue)
+		}
+	}
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionDepsCmd)
+	extensionDepsCmd.Flags().Bool("json", false, "Output the report as json")
+	extensionDepsCmd.Flags().String("sbom", "", "Write a CycloneDX SBOM of the bundled packages to this file")
+}
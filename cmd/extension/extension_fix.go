@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/shopware/shopware-cli/internal/extension"
 	"github.com/shopware/shopware-cli/internal/verifier"
@@ -46,8 +45,6 @@ var extensionFixCmd = &cobra.Command{
 
 		logging.FromContext(cmd.Context()).Debugf("Running fixes for Shopware version: %s", toolCfg.MinShopwareVersion)
 
-		var gr errgroup.Group
-
 		tools := verifier.GetTools()
 		only, _ := cmd.Flags().GetString("only")
 
@@ -56,14 +53,7 @@ var extensionFixCmd = &cobra.Command{
 			return err
 		}
 
-		for _, tool := range tools {
-			tool := tool
-			gr.Go(func() error {
-				return tool.Fix(cmd.Context(), *toolCfg)
-			})
-		}
-
-		if err := gr.Wait(); err != nil {
+		if err := tools.Fix(cmd.Context(), *toolCfg); err != nil {
 			return err
 		}
 
@@ -5,7 +5,6 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/shopware/shopware-cli/internal/extension"
 	"github.com/shopware/shopware-cli/internal/verifier"
@@ -38,8 +37,6 @@ var extensionFormat = &cobra.Command{
 
 		logging.FromContext(cmd.Context()).Debugf("Running fixes for Shopware version: %s", toolCfg.MinShopwareVersion)
 
-		var gr errgroup.Group
-
 		tools := verifier.GetTools()
 		only, _ := cmd.Flags().GetString("only")
 
@@ -48,14 +45,7 @@ var extensionFormat = &cobra.Command{
 			return err
 		}
 
-		for _, tool := range tools {
-			tool := tool
-			gr.Go(func() error {
-				return tool.Format(cmd.Context(), *toolCfg, dryRun)
-			})
-		}
-
-		if err := gr.Wait(); err != nil {
+		if err := tools.Format(cmd.Context(), *toolCfg, dryRun); err != nil {
 			return err
 		}
 
@@ -0,0 +1,69 @@
+package extension
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/mailpreview"
+	"github.com/shopware/shopware-cli/internal/mjml"
+	"github.com/shopware/shopware-cli/internal/shop"
+)
+
+var extensionMailPreviewCmd = &cobra.Command{
+	Use:   "mail-preview [path]",
+	Short: "Preview the mail templates of an extension with sample data",
+	Long: `Serve a live-reloading gallery of all mail templates in the Resources directory of the extension.
+
+A mail template is a directory named after the technical name of the mail template type containing
+html.mjml or html.twig, plain.twig and subject.twig, optionally in a locale directory like
+order_confirmation_mail/de-DE. MJML is compiled on every change and the Twig placeholders are rendered
+with sample data of the mail type. The sample data can be extended with a fixture.json next to the
+template or <type>.json files in the --fixtures directories.
+
+The push button writes the compiled templates to the mail templates of the shop configured by
+SHOPWARE_CLI_API_URL and SHOPWARE_CLI_API_CLIENT_ID/SHOPWARE_CLI_API_CLIENT_SECRET. Use --push to push
+all templates without starting the server.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		fixtureDirs, _ := cmd.Flags().GetStringSlice("fixtures")
+		pushOnly, _ := cmd.Flags().GetBool("push")
+
+		extPath := "."
+		if len(args) == 1 {
+			extPath = args[0]
+		}
+
+		extPath, err := filepath.Abs(extPath)
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(cmd.Context(), extPath)
+		if err != nil {
+			return err
+		}
+
+		renderer := mailpreview.Renderer{
+			FixtureDirs: fixtureDirs,
+			MJMLOptions: func(searchPath string) mjml.CompileOptions {
+				return mjml.NewCompileOptions(searchPath, true, nil)
+			},
+		}
+
+		return mailpreview.Run(cmd.Context(), ext.GetResourcesDirs(), renderer, listen, pushOnly, func(ctx context.Context) (*adminSdk.Client, error) {
+			return shop.NewShopClient(ctx, &shop.Config{})
+		})
+	},
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionMailPreviewCmd)
+	extensionMailPreviewCmd.Flags().String("listen", "127.0.0.1:8025", "Address to serve the preview on")
+	extensionMailPreviewCmd.Flags().StringSlice("fixtures", nil, "Directories with <mail type>.json sample data")
+	extensionMailPreviewCmd.Flags().Bool("push", false, "Push all templates to the shop and exit")
+}
@@ -0,0 +1,164 @@
+package extension
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	liplogtable "charm.land/lipgloss/v2/table"
+	"github.com/spf13/cobra"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/tui"
+	"github.com/shopware/shopware-cli/internal/verifier"
+)
+
+var extensionMatrixCmd = &cobra.Command{
+	Use:   "matrix [path]",
+	Short: "Run the linters against every Shopware version the extension supports",
+	Long: `Run the linters against the latest release of every Shopware minor matching
+the Shopware constraint of the extension.
+
+The dependencies of each version are installed in a working copy in the cache
+directory and reused until the composer.json changes. The narrowest constraint
+covering only the passing versions is printed at the end.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		only, _ := cmd.Flags().GetString("only")
+		versionList, _ := cmd.Flags().GetString("versions")
+		outputJSON, _ := cmd.Flags().GetBool("json")
+
+		extPath, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+
+		ext, err := extension.GetExtensionByFolder(cmd.Context(), extPath)
+		if err != nil {
+			return err
+		}
+
+		constraint, err := ext.GetShopwareVersionConstraint()
+		if err != nil {
+			return err
+		}
+
+		var versions []string
+
+		if versionList != "" {
+			for _, v := range strings.Split(versionList, ",") {
+				versions = append(versions, strings.TrimSpace(v))
+			}
+		} else {
+			available, err := extension.GetShopwareVersions(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			softwareVersions := make(account_api.SoftwareVersionList, 0, len(available))
+			for _, v := range available {
+				softwareVersions = append(softwareVersions, account_api.SoftwareVersion{Name: v, Selectable: true})
+			}
+
+			versions = verifier.MatrixVersions(softwareVersions.FilterOnVersionStringList(constraint))
+		}
+
+		if len(versions) == 0 {
+			return fmt.Errorf("no Shopware version matches the constraint %s", constraint.String())
+		}
+
+		tools, err := verifier.GetTools().Only(only)
+		if err != nil {
+			return err
+		}
+
+		matrix, err := verifier.RunMatrix(cmd.Context(), ext, versions, tools)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+
+			if err := encoder.Encode(struct {
+				*verifier.Matrix
+				Constraint        string `json:"constraint"`
+				PassingConstraint string `json:"passingConstraint"`
+			}{matrix, constraint.String(), matrix.PassingConstraint()}); err != nil {
+				return err
+			}
+		} else {
+			printMatrix(cmd, matrix, constraint.String())
+		}
+
+		for _, v := range matrix.Versions {
+			if !matrix.Passed(v) {
+				return fmt.Errorf("the extension does not pass on every version of its constraint %s", constraint.String())
+			}
+		}
+
+		return nil
+	},
+}
+
+func printMatrix(cmd *cobra.Command, matrix *verifier.Matrix, constraint string) {
+	t := liplogtable.New().
+		Border(lipgloss.NormalBorder()).
+		Headers(append([]string{"Shopware"}, matrix.Tools...)...)
+
+	for _, v := range matrix.Versions {
+		row := []string{v}
+
+		for _, tool := range matrix.Tools {
+			cell, _ := matrix.Cell(v, tool)
+
+			switch {
+			case cell.Failure != "":
+				row = append(row, tui.RedText.Render("failed"))
+			case cell.Errors > 0:
+				row = append(row, tui.RedText.Render(fmt.Sprintf("%d errors", cell.Errors)))
+			case cell.Warnings > 0:
+				row = append(row, tui.YellowText.Render(fmt.Sprintf("%d warnings", cell.Warnings)))
+			default:
+				row = append(row, tui.GreenText.Render("ok"))
+			}
+		}
+
+		t.Row(row...)
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintln(out, t.Render())
+
+	for _, cell := range matrix.Cells {
+		if cell.Failure != "" && cell.Tool == verifier.MatrixComposerTool {
+			_, _ = fmt.Fprintf(out, "%s on Shopware %s: %s\n", cell.Tool, cell.Version, cell.Failure)
+		}
+	}
+
+	passing := matrix.PassingConstraint()
+
+	switch {
+	case passing == "":
+		_, _ = fmt.Fprintln(out, tui.RedText.Render("No tested version passes"))
+	case !slices.ContainsFunc(matrix.Versions, func(v string) bool { return !matrix.Passed(v) }):
+		_, _ = fmt.Fprintf(out, "All tested versions of %s pass\n", constraint)
+	default:
+		_, _ = fmt.Fprintf(out, "Constraint: %s\nNarrowest passing constraint: %s\n", constraint, tui.YellowText.Render(passing))
+	}
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionMatrixCmd)
+	extensionMatrixCmd.Flags().String("only", "phpstan,storefront-twig,admin-twig,admin-js,deprecated-blocks", "Tools to run on each version (comma-separated)")
+	extensionMatrixCmd.Flags().String("versions", "", "Shopware versions to test (comma-separated), defaults to the latest release of every minor matching the constraint")
+	extensionMatrixCmd.Flags().Bool("json", false, "Output the matrix as json")
+	extensionMatrixCmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return verifier.SetupTools(cmd.Context(), cmd.Root().Version)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	cp "github.com/otiai10/copy"
 	"github.com/spf13/cobra"
@@ -25,205 +27,338 @@ var (
 )
 
 var extensionPackageCmd = &cobra.Command{
-	Use:     "package [path] [branch]",
-	Short:   "Package an extension",
+	Use:   "package [path] [branch]",
+	Short: "Package an extension",
+	Long: `Package an extension.
+
+With --all or a glob pattern as path, all found extensions are packaged in
+the order of their composer dependencies. A manifest with the version and
+checksum of every zip is written next to them.`,
 	Aliases: []string{"zip"},
-	Args:    cobra.MinimumNArgs(1),
+	Args:    bulkArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cmd.CalledAs() == "zip" {
 			logging.FromContext(cmd.Context()).Warnf("`extension zip` is deprecated, use `extension package` instead")
 		}
 
+		opts := packageOptions{
+			disableGit:                  disableGit,
+			releaseMode:                 extensionReleaseMode,
+			appBackendUrl:               getStringOnStringError(cmd.Flags().GetString("overwrite-app-backend-url")),
+			appBackendSecret:            getStringOnStringError(cmd.Flags().GetString("overwrite-app-backend-secret")),
+			appBackendSecretOverwritten: cmd.Flags().Changed("overwrite-app-backend-secret"),
+			version:                     getStringOnStringError(cmd.Flags().GetString("overwrite-version")),
+			useGitTagAsVersion:          cmd.Flags().Changed("use-git-tag-as-version"),
+			outputDirectory:             getStringOnStringError(cmd.Flags().GetString("output-directory")),
+			gitCommit:                   getStringOnStringError(cmd.Flags().GetString("git-commit")),
+			fileName:                    getStringOnStringError(cmd.Flags().GetString("filename")),
+			cliVersion:                  cmd.Root().Version,
+		}
+
+		if isBulkRun(cmd, args) {
+			return packageExtensions(cmd, args, opts)
+		}
+
 		extPath, err := filepath.Abs(args[0])
 		if err != nil {
 			return err
 		}
 
-		var branch string
 		if len(args) == 2 {
-			branch = args[1]
+			opts.branch = args[1]
 		}
 
-		ext, err := extension.GetExtensionByFolder(cmd.Context(), extPath)
-		if err != nil {
-			return fmt.Errorf("detect extension type: %w", err)
-		}
+		_, _, err = packageExtension(cmd.Context(), extPath, opts)
 
-		extCfg := ext.GetExtensionConfig()
+		return err
+	},
+}
 
-		name, err := ext.GetName()
+type packageOptions struct {
+	branch                      string
+	disableGit                  bool
+	releaseMode                 bool
+	appBackendUrl               string
+	appBackendSecret            string
+	appBackendSecretOverwritten bool
+	version                     string
+	useGitTagAsVersion          bool
+	outputDirectory             string
+	gitCommit                   string
+	fileName                    string
+	cliVersion                  string
+}
+
+// packageExtensions packages every extension of the bulk run and writes the
+// manifest of the created zips.
+func packageExtensions(cmd *cobra.Command, args []string, opts packageOptions) error {
+	if opts.fileName != "" {
+		return fmt.Errorf("--filename cannot be used when packaging several extensions")
+	}
+
+	extensions, err := resolveBulkExtensions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	outputDir := opts.outputDirectory
+	if outputDir == "" {
+		outputDir = "."
+	}
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	opts.outputDirectory = outputDir
+
+	var mu sync.Mutex
+	manifest := zipManifest{Extensions: []zipManifestEntry{}}
+
+	results, err := extension.RunBulk(cmd.Context(), extensions, bulkConcurrency(cmd), func(ctx context.Context, ext extension.Extension) error {
+		file, version, err := packageExtension(ctx, ext.GetPath(), opts)
 		if err != nil {
-			return fmt.Errorf("get name: %w", err)
+			return err
 		}
 
-		// Create temp dir
-		tempDir, err := os.MkdirTemp("", "extension")
+		name, err := ext.GetName()
 		if err != nil {
-			return fmt.Errorf("create temp directory: %w", err)
+			return err
 		}
 
-		extName, err := ext.GetName()
+		entry, err := newZipManifestEntry(name, version, file)
 		if err != nil {
-			return fmt.Errorf("get extension name: %w", err)
+			return err
 		}
 
-		extDir := fmt.Sprintf("%s/%s/", tempDir, extName)
+		mu.Lock()
+		manifest.Extensions = append(manifest.Extensions, entry)
+		mu.Unlock()
 
-		err = os.Mkdir(extDir, 0o755)
-		if err != nil {
-			return fmt.Errorf("create temp directory: %w", err)
-		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		tempDir += "/"
+	slices.SortFunc(manifest.Extensions, func(a, b zipManifestEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
 
-		defer func(path string) {
-			_ = os.RemoveAll(path)
-		}(tempDir)
+	manifestPath, err := writeZipManifest(outputDir, manifest)
+	if err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
 
-		var tag string
+	printBulkResults(cmd.OutOrStdout(), results)
+	logging.FromContext(cmd.Context()).Infof("Created manifest %s", manifestPath)
 
-		// Extract files using strategy
-		if disableGit {
-			err = cp.Copy(extPath, extDir, copyOptions())
-			if err != nil {
-				return fmt.Errorf("copy files: %w", err)
-			}
-		} else {
-			gitCommit, _ := cmd.Flags().GetString("git-commit")
+	return bulkError(cmd, results)
+}
 
-			tag, err = extension.GitCopyFolder(cmd.Context(), extPath, extDir, gitCommit)
-			if err != nil {
-				return fmt.Errorf("copy via git: %w", err)
-			}
+// packageExtension builds the zip of the extension at extPath and returns the path and version of the created file.
+func packageExtension(ctx context.Context, extPath string, opts packageOptions) (string, string, error) {
+	ext, err := extension.GetExtensionByFolder(ctx, extPath)
+	if err != nil {
+		return "", "", fmt.Errorf("detect extension type: %w", err)
+	}
 
-			logging.FromContext(cmd.Context()).Infof("Checking out %s using Git", tag)
-		}
+	extCfg := ext.GetExtensionConfig()
 
-		// User input wins
-		if len(branch) > 0 {
-			tag = branch
-		}
+	name, err := ext.GetName()
+	if err != nil {
+		return "", "", fmt.Errorf("get name: %w", err)
+	}
 
-		if extCfg.Build.Zip.Composer.Enabled {
-			if err := executeHooks(cmd.Context(), ext, extCfg.Build.Zip.Composer.BeforeHooks, extDir); err != nil {
-				return fmt.Errorf("before hooks composer: %w", err)
-			}
+	// Create temp dir
+	tempDir, err := os.MkdirTemp("", "extension")
+	if err != nil {
+		return "", "", fmt.Errorf("create temp directory: %w", err)
+	}
 
-			if err := extension.PrepareFolderForZipping(cmd.Context(), extDir, ext, extCfg); err != nil {
-				return fmt.Errorf("prepare package: %w", err)
-			}
+	extName, err := ext.GetName()
+	if err != nil {
+		return "", "", fmt.Errorf("get extension name: %w", err)
+	}
 
-			if err := executeHooks(cmd.Context(), ext, extCfg.Build.Zip.Composer.AfterHooks, extDir); err != nil {
-				return fmt.Errorf("after hooks composer: %w", err)
-			}
+	extDir := fmt.Sprintf("%s/%s/", tempDir, extName)
+
+	err = os.Mkdir(extDir, 0o755)
+	if err != nil {
+		return "", "", fmt.Errorf("create temp directory: %w", err)
+	}
+
+	tempDir += "/"
+
+	defer func(path string) {
+		_ = os.RemoveAll(path)
+	}(tempDir)
+
+	var tag string
+
+	// Extract files using strategy
+	if opts.disableGit {
+		err = cp.Copy(extPath, extDir, copyOptions())
+		if err != nil {
+			return "", "", fmt.Errorf("copy files: %w", err)
 		}
-		var tempExt extension.Extension
-		if tempExt, err = extension.GetExtensionByFolder(cmd.Context(), extDir); err != nil {
-			return err
+	} else {
+		tag, err = extension.GitCopyFolder(ctx, extPath, extDir, opts.gitCommit)
+		if err != nil {
+			return "", "", fmt.Errorf("copy via git: %w", err)
 		}
 
-		if extCfg.Build.Zip.Assets.Enabled {
-			if err := executeHooks(cmd.Context(), ext, extCfg.Build.Zip.Assets.BeforeHooks, extDir); err != nil {
-				return fmt.Errorf("before hooks assets: %w", err)
-			}
+		logging.FromContext(ctx).Infof("Checking out %s using Git", tag)
+	}
 
-			shopwareConstraint, err := extension.GetShopwareVersionConstraintForBuild(tempExt)
-			if err != nil {
-				return fmt.Errorf("get shopware version constraint: %w", err)
-			}
+	// User input wins
+	if len(opts.branch) > 0 {
+		tag = opts.branch
+	}
 
-			assetBuildConfig := extension.AssetBuildConfig{
-				EnableAssetCaching: extCfg.Build.Zip.Assets.EnableAssetCaching,
-				CleanupNodeModules: true,
-				ShopwareRoot:       os.Getenv("SHOPWARE_PROJECT_ROOT"),
-				ShopwareVersion:    shopwareConstraint,
-			}
-			if assetBuildConfig.ShopwareRoot != "" {
-				assetBuildConfig.Executor = executor.NewLocal(assetBuildConfig.ShopwareRoot)
-			}
+	var dependencyReport *extension.DependencyReport
 
-			if err := extension.BuildAssetsForExtensions(cmd.Context(), extension.ConvertExtensionsToSources(cmd.Context(), []extension.Extension{tempExt}), assetBuildConfig); err != nil {
-				return fmt.Errorf("building assets: %w", err)
-			}
+	if extCfg.Build.Zip.Composer.Enabled {
+		if err := executeHooks(ctx, ext, extCfg.Build.Zip.Composer.BeforeHooks, extDir); err != nil {
+			return "", "", fmt.Errorf("before hooks composer: %w", err)
+		}
 
-			if err := executeHooks(cmd.Context(), ext, extCfg.Build.Zip.Assets.AfterHooks, extDir); err != nil {
-				return fmt.Errorf("after hooks assets: %w", err)
-			}
+		if err := extension.PrepareFolderForZipping(ctx, extDir, ext, extCfg); err != nil {
+			return "", "", fmt.Errorf("prepare package: %w", err)
 		}
 
-		if cmd.Flags().Changed("overwrite-app-backend-secret") {
-			extCfg.Validation.Ignore = append(extCfg.Validation.Ignore, validation.ToolConfigIgnore{Identifier: "metadata.setup"})
-			if err := extCfg.Dump(extDir); err != nil {
-				return fmt.Errorf("dump extension config: %w", err)
+		if err := executeHooks(ctx, ext, extCfg.Build.Zip.Composer.AfterHooks, extDir); err != nil {
+			return "", "", fmt.Errorf("after hooks composer: %w", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(extDir, "vendor")); err == nil {
+			if dependencyReport, err = extension.BuildDependencyReport(ctx, extDir, ext); err != nil {
+				return "", "", fmt.Errorf("dependency report: %w", err)
 			}
+
+			logDependencyReport(ctx, dependencyReport)
 		}
+	}
+	var tempExt extension.Extension
+	if tempExt, err = extension.GetExtensionByFolder(ctx, extDir); err != nil {
+		return "", "", err
+	}
 
-		// Cleanup not wanted files
-		if err := extension.CleanupExtensionFolder(extDir, extCfg.Build.Zip.Pack.Excludes.Paths); err != nil {
-			return fmt.Errorf("cleanup package: %w", err)
+	if extCfg.Build.Zip.Assets.Enabled {
+		if err := executeHooks(ctx, ext, extCfg.Build.Zip.Assets.BeforeHooks, extDir); err != nil {
+			return "", "", fmt.Errorf("before hooks assets: %w", err)
 		}
 
-		if extensionReleaseMode {
-			if err := extension.PrepareExtensionForRelease(cmd.Context(), extPath, extDir, ext); err != nil {
-				return fmt.Errorf("prepare for release: %w", err)
-			}
+		shopwareConstraint, err := extension.GetShopwareVersionConstraintForBuild(tempExt)
+		if err != nil {
+			return "", "", fmt.Errorf("get shopware version constraint: %w", err)
 		}
 
-		if err := extension.ResizeExtensionIcon(cmd.Context(), tempExt); err != nil {
-			return fmt.Errorf("resize extension icon: %w", err)
+		assetBuildConfig := extension.AssetBuildConfig{
+			EnableAssetCaching: extCfg.Build.Zip.Assets.EnableAssetCaching,
+			CleanupNodeModules: true,
+			ShopwareRoot:       os.Getenv("SHOPWARE_PROJECT_ROOT"),
+			ShopwareVersion:    shopwareConstraint,
+		}
+		if assetBuildConfig.ShopwareRoot != "" {
+			assetBuildConfig.Executor = executor.NewLocal(assetBuildConfig.ShopwareRoot)
 		}
 
-		version := getStringOnStringError(cmd.Flags().GetString("overwrite-version"))
-		if version == "" && cmd.Flags().Changed("use-git-tag-as-version") {
-			version = strings.TrimPrefix(tag, "v")
+		if err := extension.BuildAssetsForExtensions(ctx, extension.ConvertExtensionsToSources(ctx, []extension.Extension{tempExt}), assetBuildConfig); err != nil {
+			return "", "", fmt.Errorf("building assets: %w", err)
 		}
 
-		if err := extension.BuildModifier(ext, extDir, extension.BuildModifierConfig{
-			AppBackendUrl:    getStringOnStringError(cmd.Flags().GetString("overwrite-app-backend-url")),
-			AppBackendSecret: getStringOnStringError(cmd.Flags().GetString("overwrite-app-backend-secret")),
-			Version:          version,
-		}); err != nil {
-			return fmt.Errorf("build modifier: %w", err)
+		if err := executeHooks(ctx, ext, extCfg.Build.Zip.Assets.AfterHooks, extDir); err != nil {
+			return "", "", fmt.Errorf("after hooks assets: %w", err)
 		}
+	}
 
-		fileName, _ := cmd.Flags().GetString("filename")
+	if opts.appBackendSecretOverwritten {
+		extCfg.Validation.Ignore = append(extCfg.Validation.Ignore, validation.ToolConfigIgnore{Identifier: "metadata.setup"})
+		if err := extCfg.Dump(extDir); err != nil {
+			return "", "", fmt.Errorf("dump extension config: %w", err)
+		}
+	}
 
-		if len(fileName) == 0 {
-			fileName = fmt.Sprintf("%s-%s.zip", name, tag)
-			if len(tag) == 0 {
-				fileName = fmt.Sprintf("%s.zip", name)
-			}
+	// Cleanup not wanted files
+	if err := extension.CleanupExtensionFolder(extDir, extCfg.Build.Zip.Pack.Excludes.Paths); err != nil {
+		return "", "", fmt.Errorf("cleanup package: %w", err)
+	}
+
+	if opts.releaseMode {
+		if err := extension.PrepareExtensionForRelease(ctx, extPath, extDir, ext); err != nil {
+			return "", "", fmt.Errorf("prepare for release: %w", err)
 		}
+	}
+
+	if err := extension.ResizeExtensionIcon(ctx, tempExt); err != nil {
+		return "", "", fmt.Errorf("resize extension icon: %w", err)
+	}
 
-		outputDir, _ := cmd.Flags().GetString("output-directory")
+	version := opts.version
+	if version == "" && opts.useGitTagAsVersion {
+		version = strings.TrimPrefix(tag, "v")
+	}
 
-		if len(outputDir) > 0 {
-			if _, err := os.Stat(outputDir); os.IsNotExist(err) {
-				if err := os.MkdirAll(outputDir, 0o755); err != nil {
-					return fmt.Errorf("create output directory: %w", err)
-				}
-			}
+	if err := extension.BuildModifier(ext, extDir, extension.BuildModifierConfig{
+		AppBackendUrl:    opts.appBackendUrl,
+		AppBackendSecret: opts.appBackendSecret,
+		Version:          version,
+	}); err != nil {
+		return "", "", fmt.Errorf("build modifier: %w", err)
+	}
 
-			fileName = path.Join(outputDir, fileName)
+	if version == "" {
+		if extVersion, err := tempExt.GetVersion(); err == nil {
+			version = extVersion.String()
 		}
+	}
+
+	fileName := opts.fileName
 
-		if err := executeHooks(cmd.Context(), ext, extCfg.Build.Zip.Pack.BeforeHooks, extDir); err != nil {
-			return fmt.Errorf("before hooks pack: %w", err)
+	if len(fileName) == 0 {
+		fileName = fmt.Sprintf("%s-%s.zip", name, tag)
+		if len(tag) == 0 {
+			fileName = fmt.Sprintf("%s.zip", name)
 		}
+	}
 
-		// Generate checksums.json file before creating the zip
-		if err := extension.GenerateChecksumJSON(cmd.Context(), extDir, ext); err != nil {
-			return fmt.Errorf("generate checksum.json: %w", err)
+	if len(opts.outputDirectory) > 0 {
+		if _, err := os.Stat(opts.outputDirectory); os.IsNotExist(err) {
+			if err := os.MkdirAll(opts.outputDirectory, 0o755); err != nil {
+				return "", "", fmt.Errorf("create output directory: %w", err)
+			}
 		}
 
-		if err := archiver.CreateZip(tempDir, fileName); err != nil {
-			return fmt.Errorf("create zip file: %w", err)
+		fileName = path.Join(opts.outputDirectory, fileName)
+	}
+
+	if err := executeHooks(ctx, ext, extCfg.Build.Zip.Pack.BeforeHooks, extDir); err != nil {
+		return "", "", fmt.Errorf("before hooks pack: %w", err)
+	}
+
+	// Generate checksums.json file before creating the zip
+	if err := extension.GenerateChecksumJSON(ctx, extDir, ext); err != nil {
+		return "", "", fmt.Errorf("generate checksum.json: %w", err)
+	}
+
+	if err := archiver.CreateZip(tempDir, fileName); err != nil {
+		return "", "", fmt.Errorf("create zip file: %w", err)
+	}
+
+	logging.FromContext(ctx).Infof("Created file %s", fileName)
+
+	if dependencyReport != nil && len(dependencyReport.Packages) > 0 {
+		sbomFile := strings.TrimSuffix(fileName, ".zip") + ".cdx.json"
+
+		if err := dependencyReport.WriteSBOM(tempExt, sbomFile, opts.cliVersion); err != nil {
+			return "", "", fmt.Errorf("write SBOM: %w", err)
 		}
 
-		logging.FromContext(cmd.Context()).Infof("Created file %s", fileName)
+		logging.FromContext(ctx).Infof("Created SBOM %s", sbomFile)
+	}
 
-		return nil
-	},
+	return fileName, version, nil
 }
 
 func init() {
@@ -239,6 +374,7 @@ func init() {
 	extensionPackageCmd.Flags().String("output-directory", "", "Output directory for the zip file")
 	extensionPackageCmd.Flags().String("git-commit", "", "Commit Hash / Tag to use")
 	extensionPackageCmd.Flags().String("filename", "", "Name of the zip file, if not set it will be generated from the extension name and tag")
+	addBulkFlags(extensionPackageCmd)
 }
 
 func getStringOnStringError(val string, _ error) string {
@@ -1,6 +1,7 @@
 package extension
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -53,3 +54,54 @@ func TestPackageDoesNotDeleteUnrelatedZipsInWorkingDirectory(t *testing.T) {
 	assert.FileExists(t, decoyRelease)
 	assert.FileExists(t, filepath.Join(outputDir, "custom-name.zip"))
 }
+
+func TestPackageAllWritesManifest(t *testing.T) {
+	root := t.TempDir()
+
+	for _, name := range []string{"FroshBase", "FroshAddon"} {
+		extDir := filepath.Join(root, "plugins", name)
+		require.NoError(t, os.MkdirAll(extDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(extDir, "composer.json"), []byte(`{
+			"name": "frosh/`+name+`",
+			"type": "shopware-platform-plugin",
+			"license": "MIT",
+			"version": "1.2.0",
+			"require": { "shopware/core": "~6.6.0" },
+			"autoload": { "psr-4": { "`+name+`\\": "src/" } },
+			"extra": {
+				"shopware-plugin-class": "`+name+`\\`+name+`",
+				"label": { "de-DE": "Test", "en-GB": "Test" }
+			}
+		}`), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(extDir, ".shopware-extension.yml"), []byte(
+			"build:\n  zip:\n    composer:\n      enabled: false\n    assets:\n      enabled: false\n",
+		), 0o644))
+	}
+
+	disableGit = true
+	t.Cleanup(func() { disableGit = false })
+
+	outputDir := filepath.Join(t.TempDir(), "dist")
+	extensionPackageCmd.SetContext(t.Context())
+	require.NoError(t, extensionPackageCmd.Flags().Set("output-directory", outputDir))
+	require.NoError(t, extensionPackageCmd.Flags().Set("all", "true"))
+	t.Cleanup(func() {
+		_ = extensionPackageCmd.Flags().Set("output-directory", "")
+		_ = extensionPackageCmd.Flags().Set("all", "false")
+	})
+
+	require.NoError(t, extensionPackageCmd.RunE(extensionPackageCmd, []string{root}))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, zipManifestFileName))
+	require.NoError(t, err)
+
+	var manifest zipManifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	require.Len(t, manifest.Extensions, 2)
+
+	assert.Equal(t, "FroshAddon", manifest.Extensions[0].Name)
+	assert.Equal(t, "1.2.0", manifest.Extensions[0].Version)
+	assert.Equal(t, "FroshAddon.zip", manifest.Extensions[0].File)
+	assert.Len(t, manifest.Extensions[0].SHA256, 64)
+	assert.FileExists(t, filepath.Join(outputDir, "FroshBase.zip"))
+}
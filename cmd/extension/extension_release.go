@@ -0,0 +1,256 @@
+package extension
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	account_api "github.com/shopware/shopware-cli/internal/account-api"
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/git"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+type releaseStep struct {
+	description string
+	run         func(ctx context.Context) error
+	// rollback undoes the step when a later step fails
+	rollback func(ctx context.Context) error
+}
+
+var extensionReleaseCmd = &cobra.Command{
+	Use:   "release <major|minor|patch|version> [path]",
+	Short: "Release a new version of an extension",
+	Long: `Release a new version of an extension in one go:
+
+  1. Bump the version in composer.json (plugins) or manifest.xml (apps)
+  2. Prepend the changelog generated from the git history to CHANGELOG_en-GB.md
+  3. Commit both files and tag the commit with the new version
+  4. Package the tagged commit in release mode
+  5. Upload the zip to the Shopware Account and wait for the code review result
+
+The git working tree has to be clean. When packaging or uploading fails, the
+commit and the tag are removed again. Pushing the commit and the tag is left to you.
+
+Examples:
+  # Release the next minor version of the extension in the current directory
+  shopware-cli extension release minor
+
+  # Show what would happen when releasing 2.0.0
+  shopware-cli extension release 2.0.0 ./MyPlugin --dry-run`,
+	Args: cobra.RangeArgs(1, 2),
+	ValidArgs: []string{
+		extension.ReleaseBumpMajor,
+		extension.ReleaseBumpMinor,
+		extension.ReleaseBumpPatch,
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipUpload, _ := cmd.Flags().GetBool("skip-upload")
+		skipReviewWait, _ := cmd.Flags().GetBool("skip-for-review-result")
+		reviewTimeout, _ := cmd.Flags().GetDuration("review-timeout")
+		outputDir, _ := cmd.Flags().GetString("output-directory")
+
+		extPath := "."
+		if len(args) == 2 {
+			extPath = args[1]
+		}
+
+		extPath, err := filepath.Abs(extPath)
+		if err != nil {
+			return fmt.Errorf("cannot find path: %w", err)
+		}
+
+		ext, err := extension.GetExtensionByFolder(cmd.Context(), extPath)
+		if err != nil {
+			return fmt.Errorf("detect extension type: %w", err)
+		}
+
+		currentVersion, err := ext.GetVersion()
+		if err != nil {
+			return fmt.Errorf("get current version: %w", err)
+		}
+
+		nextVersion, err := extension.NextReleaseVersion(currentVersion, args[0])
+		if err != nil {
+			return err
+		}
+
+		newVersion := nextVersion.String()
+
+		dirty, isRepository, err := git.IsWorkingTreeDirty(cmd.Context(), extPath)
+		if err != nil {
+			return err
+		}
+
+		if !isRepository {
+			return fmt.Errorf("%s is not inside a git repository", extPath)
+		}
+
+		if dirty && !dryRun {
+			return fmt.Errorf("the git working tree has uncommitted changes, commit or stash them before releasing")
+		}
+
+		tagExists, err := git.TagExists(cmd.Context(), extPath, newVersion)
+		if err != nil {
+			return err
+		}
+
+		if tagExists {
+			return fmt.Errorf("tag %s already exists", newVersion)
+		}
+
+		versionFile := extension.VersionFile(ext)
+		changedFiles := []string{versionFile}
+		var zipPath string
+
+		steps := []releaseStep{
+			{
+				description: fmt.Sprintf("Bump version in %s from %s to %s", versionFile, currentVersion.String(), newVersion),
+				run: func(_ context.Context) error {
+					return extension.SetExtensionVersion(ext, newVersion)
+				},
+			},
+			{
+				description: fmt.Sprintf("Prepend changelog for %s to CHANGELOG_en-GB.md", newVersion),
+				run: func(ctx context.Context) error {
+					written, err := extension.PrependReleaseChangelog(ctx, ext, newVersion)
+					if err != nil {
+						return err
+					}
+
+					if written {
+						changedFiles = append(changedFiles, "CHANGELOG_en-GB.md")
+					} else {
+						logging.FromContext(ctx).Infof("CHANGELOG_en-GB.md already contains %s, keeping it", newVersion)
+					}
+
+					return nil
+				},
+			},
+			{
+				description: fmt.Sprintf("Commit the changes as \"Release %s\"", newVersion),
+				run: func(ctx context.Context) error {
+					return git.Commit(ctx, extPath, fmt.Sprintf("Release %s", newVersion), changedFiles...)
+				},
+				rollback: func(ctx context.Context) error {
+					return git.ResetLastCommit(ctx, extPath)
+				},
+			},
+			{
+				description: fmt.Sprintf("Create tag %s", newVersion),
+				run: func(ctx context.Context) error {
+					return git.CreateTag(ctx, extPath, newVersion, fmt.Sprintf("Release %s", newVersion))
+				},
+				rollback: func(ctx context.Context) error {
+					return git.DeleteTag(ctx, extPath, newVersion)
+				},
+			},
+			{
+				description: fmt.Sprintf("Package tag %s in release mode", newVersion),
+				run: func(ctx context.Context) error {
+					zipPath, _, err = packageExtension(ctx, extPath, packageOptions{
+						gitCommit:       newVersion,
+						releaseMode:     true,
+						outputDirectory: outputDir,
+					})
+
+					return err
+				},
+			},
+		}
+
+		if !skipUpload {
+			description := "Upload the zip to the Shopware Account and wait for the code review result"
+			if skipReviewWait {
+				description = "Upload the zip to the Shopware Account"
+			}
+
+			steps = append(steps, releaseStep{
+				description: description,
+				run: func(ctx context.Context) error {
+					ctx = account_api.WithDirectoryProfile(ctx, ext.GetExtensionConfig().Account.Profile)
+
+					return uploadRelease(ctx, zipPath, skipReviewWait, reviewTimeout)
+				},
+			})
+		}
+
+		if dryRun {
+			fmt.Printf("Release plan for %s:\n", extPath)
+			for i, step := range steps {
+				fmt.Printf("  %d. %s\n", i+1, step.description)
+			}
+
+			return nil
+		}
+
+		for i, step := range steps {
+			logging.FromContext(cmd.Context()).Infof("%s", step.description)
+
+			if err := step.run(cmd.Context()); err != nil {
+				rollbackRelease(cmd.Context(), steps[:i])
+
+				return err
+			}
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Released %s. Don't forget to push the commit and the tag %s", newVersion, newVersion)
+
+		return nil
+	},
+}
+
+// rollbackRelease undoes the completed steps in reverse order, so the commit and
+// tag of a failed release do not block running it again.
+func rollbackRelease(ctx context.Context, completed []releaseStep) {
+	for i := len(completed) - 1; i >= 0; i-- {
+		if completed[i].rollback == nil {
+			continue
+		}
+
+		if err := completed[i].rollback(ctx); err != nil {
+			logging.FromContext(ctx).Warnf("Could not undo %q: %s", completed[i].description, err)
+		}
+	}
+}
+
+func uploadRelease(ctx context.Context, zipPath string, skipReviewWait bool, reviewTimeout time.Duration) error {
+	client, err := account_api.NewApi(ctx)
+	if err != nil {
+		return err
+	}
+
+	producer, err := client.Producer(ctx)
+	if err != nil {
+		return err
+	}
+
+	zipExt, err := extension.GetExtensionByZip(ctx, zipPath)
+	if err != nil {
+		return err
+	}
+
+	if reviewTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, reviewTimeout)
+		defer cancel()
+	}
+
+	return account_api.UploadExtension(ctx, producer, zipExt, zipPath, account_api.UploadOptions{
+		SkipReviewWait: skipReviewWait,
+		MaxReviewPolls: -1,
+	})
+}
+
+func init() {
+	extensionRootCmd.AddCommand(extensionReleaseCmd)
+	extensionReleaseCmd.Flags().Bool("dry-run", false, "Print the release plan without changing anything")
+	extensionReleaseCmd.Flags().Bool("skip-upload", false, "Only bump, commit, tag and package the release")
+	extensionReleaseCmd.Flags().Bool("skip-for-review-result", false, "Skips waiting for Code review result")
+	extensionReleaseCmd.Flags().Duration("review-timeout", 30*time.Minute, "Maximum time to upload and wait for the code review result")
+	extensionReleaseCmd.Flags().String("output-directory", "", "Output directory for the zip file")
+}
@@ -1,13 +1,13 @@
 package extension
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/shopware/shopware-cli/internal/extension"
 	"github.com/shopware/shopware-cli/internal/system"
@@ -19,16 +19,27 @@ import (
 var extensionValidateCmd = &cobra.Command{
 	Use:   "validate [path]",
 	Short: "Validate an extension",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Validate an extension.
+
+With --all or a glob pattern as path, every found extension is validated and
+the findings are reported together, prefixed with the extension path.`,
+	Args: bulkArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		isFull, _ := cmd.Flags().GetBool("full")
 		storeCompliance, _ := cmd.Flags().GetBool("store-compliance")
 		reportingFormat, _ := cmd.Flags().GetString("reporter")
 		checkAgainst, _ := cmd.Flags().GetString("check-against")
-		tmpDir, err := os.MkdirTemp(os.TempDir(), "analyse-extension-*")
 		only, _ := cmd.Flags().GetString("only")
 		exclude, _ := cmd.Flags().GetString("exclude")
 		noCopy, _ := cmd.Flags().GetBool("no-copy")
+		changedSince, _ := cmd.Flags().GetString("changed-since")
+		watch, _ := cmd.Flags().GetBool("watch")
+		watchPoll, _ := cmd.Flags().GetBool("watch-poll")
+		manifestSchema, _ := cmd.Flags().GetBool("manifest-schema")
+
+		if manifestSchema {
+			cmd.SetContext(extension.WithManifestSchemaSource(cmd.Context(), extension.DownloadManifestSchema))
+		}
 
 		// If the user does not want to run full validation, only run shopware-cli
 		if !isFull {
@@ -39,6 +50,33 @@ var extensionValidateCmd = &cobra.Command{
 			reportingFormat = validation.DetectDefaultReporter()
 		}
 
+		tools, err := verifier.GetTools().Only(only)
+		if err != nil {
+			return err
+		}
+
+		tools, err = tools.Exclude(exclude)
+		if err != nil {
+			return err
+		}
+
+		storeCompliance = storeCompliance || os.Getenv("SHOPWARE_CLI_STORE_COMPLIANCE") == "1"
+
+		if isBulkRun(cmd, args) {
+			if watch || changedSince != "" {
+				return fmt.Errorf("--watch and --changed-since cannot be used when validating several extensions")
+			}
+
+			return validateExtensions(cmd, args, tools, bulkValidateOptions{
+				full:            isFull,
+				noCopy:          noCopy,
+				storeCompliance: storeCompliance,
+				checkAgainst:    checkAgainst,
+				reportingFormat: reportingFormat,
+			})
+		}
+
+		tmpDir, err := os.MkdirTemp(os.TempDir(), "analyse-extension-*")
 		if err != nil {
 			return fmt.Errorf("cannot create temporary directory: %w", err)
 		}
@@ -54,8 +92,12 @@ var extensionValidateCmd = &cobra.Command{
 		}
 		var toolCfg *verifier.ToolConfig
 
+		if watch && (!stat.IsDir() || changedSince != "") {
+			return fmt.Errorf("--watch requires an extension directory and cannot be combined with --changed-since")
+		}
+
 		if stat.IsDir() {
-			if noCopy {
+			if noCopy || watch {
 				tmpDir = path
 				logging.FromContext(cmd.Context()).Debugf("Skipping copying extension files to temporary directory due to --no-copy flag")
 			} else if isFull {
@@ -88,7 +130,20 @@ var extensionValidateCmd = &cobra.Command{
 			}
 
 			toolCfg.InputWasDirectory = true
+
+			if changedSince != "" {
+				changedFiles, err := verifier.ChangedFilesSince(cmd.Context(), path, changedSince)
+				if err != nil {
+					return err
+				}
+
+				toolCfg.LimitToChangedFiles(path, changedFiles)
+			}
 		} else {
+			if changedSince != "" {
+				return fmt.Errorf("--changed-since requires an extension directory")
+			}
+
 			ext, err := extension.GetExtensionByZip(cmd.Context(), args[0])
 			if err != nil {
 				return err
@@ -100,41 +155,22 @@ var extensionValidateCmd = &cobra.Command{
 			}
 		}
 
-		if storeCompliance || os.Getenv("SHOPWARE_CLI_STORE_COMPLIANCE") == "1" {
-			toolCfg.Extension.GetExtensionConfig().Validation.StoreCompliance = true
-			// The user is not allowed to provide a custom ignore list when store compliance is enabled
-			toolCfg.Extension.GetExtensionConfig().Validation.Ignore = extension.ConfigValidationList{}
+		if storeCompliance {
+			enableStoreCompliance(toolCfg)
 		}
 
 		toolCfg.CheckAgainst = checkAgainst
 		result := verifier.NewCheck()
 
-		var gr errgroup.Group
-
-		tools := verifier.GetTools()
-
-		tools, err = tools.Only(only)
-		if err != nil {
-			return err
+		if watch {
+			return verifier.Watch(cmd.Context(), tools, *toolCfg, path, watchPoll)
 		}
 
-		tools, err = tools.Exclude(exclude)
-		if err != nil {
-			return err
-		}
-
-		for _, tool := range tools {
-			tool := tool
-			gr.Go(func() error {
-				return tool.Check(cmd.Context(), result, *toolCfg)
-			})
-		}
-
-		if err := gr.Wait(); err != nil {
+		if err := tools.Check(cmd.Context(), result, *toolCfg); err != nil {
 			return err
 		}
 
-		return validation.DoCheckReport(result.RemoveByIdentifier(toolCfg.ValidationIgnores), reportingFormat)
+		return validation.DoCheckReport(result.RemoveUnchangedFiles(*toolCfg).ApplySeverityOverrides(toolCfg.SeverityOverrides).RemoveByIdentifier(toolCfg.ValidationIgnores), reportingFormat)
 	},
 }
 
@@ -142,15 +178,20 @@ func init() {
 	extensionRootCmd.AddCommand(extensionValidateCmd)
 	extensionValidateCmd.PersistentFlags().Bool("full", false, "Run full validation including PHPStan, ESLint and Stylelint")
 	extensionValidateCmd.PersistentFlags().Bool("store-compliance", false, "Runs specific store compliance checks")
-	extensionValidateCmd.PersistentFlags().String("reporter", "", "Reporting format (summary, json, github, gitlab, junit, markdown)")
+	extensionValidateCmd.PersistentFlags().String("reporter", "", "Reporting format (summary, json, github, gitlab, junit, markdown, sarif)")
 	extensionValidateCmd.PersistentFlags().String("check-against", "highest", "Check against Shopware Version (highest, lowest)")
 	extensionValidateCmd.PersistentFlags().String("only", "", "Run only specific tools by name (comma-separated, e.g. phpstan,eslint)")
 	extensionValidateCmd.PersistentFlags().String("exclude", "", "Exclude specific tools by name (comma-separated, e.g. phpstan,eslint)")
 	extensionValidateCmd.PersistentFlags().Bool("no-copy", false, "Do not copy extension files to temporary directory")
+	extensionValidateCmd.PersistentFlags().String("changed-since", "", "Only validate files changed since the git ref, use auto to detect the base of the pull request in CI")
+	extensionValidateCmd.PersistentFlags().Bool("watch", false, "Validate again when files change, only the tools checking the changed file types run")
+	extensionValidateCmd.PersistentFlags().Bool("watch-poll", false, "Poll for changes with --watch, for mounted directories without file system notifications")
+	extensionValidateCmd.PersistentFlags().Bool("manifest-schema", false, "Validate the manifest.xml of apps against the XSD of the lowest supported Shopware version, downloaded from GitHub")
+	addBulkFlags(extensionValidateCmd)
 	extensionValidateCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
 		reporter, _ := cmd.Flags().GetString("reporter")
-		if reporter != "summary" && reporter != "json" && reporter != "github" && reporter != "gitlab" && reporter != "junit" && reporter != "markdown" && reporter != "" {
-			return fmt.Errorf("invalid reporter format: %s. Must be either 'summary', 'json', 'github', 'gitlab', 'junit' or 'markdown'", reporter)
+		if reporter != "summary" && reporter != "json" && reporter != "github" && reporter != "gitlab" && reporter != "junit" && reporter != "markdown" && reporter != "sarif" && reporter != "" {
+			return fmt.Errorf("invalid reporter format: %s. Must be either 'summary', 'json', 'github', 'gitlab', 'junit', 'markdown' or 'sarif'", reporter)
 		}
 
 		mode, _ := cmd.Flags().GetString("check-against")
@@ -167,3 +208,101 @@ func init() {
 		return verifier.SetupTools(cmd.Context(), cmd.Root().Version)
 	}
 }
+
+// enableStoreCompliance runs the store checks, the user is not allowed to
+// provide a custom ignore list then.
+func enableStoreCompliance(toolCfg *verifier.ToolConfig) {
+	toolCfg.Extension.GetExtensionConfig().Validation.StoreCompliance = true
+	toolCfg.Extension.GetExtensionConfig().Validation.Ignore = extension.ConfigValidationList{}
+}
+
+type bulkValidateOptions struct {
+	full            bool
+	noCopy          bool
+	storeCompliance bool
+	checkAgainst    string
+	reportingFormat string
+}
+
+// validateExtensions validates every extension of the bulk run and reports
+// the findings together. The paths are prefixed with the extension directory
+// relative to the working directory.
+func validateExtensions(cmd *cobra.Command, args []string, tools verifier.ToolList, opts bulkValidateOptions) error {
+	extensions, err := resolveBulkExtensions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	combined := verifier.NewCheck()
+
+	results, err := extension.RunBulk(cmd.Context(), extensions, bulkConcurrency(cmd), func(ctx context.Context, ext extension.Extension) error {
+		dir := ext.GetPath()
+
+		if opts.full && !opts.noCopy {
+			tmpDir, err := os.MkdirTemp(os.TempDir(), "analyse-extension-*")
+			if err != nil {
+				return fmt.Errorf("cannot create temporary directory: %w", err)
+			}
+
+			defer func() {
+				_ = os.RemoveAll(tmpDir)
+			}()
+
+			if err := system.CopyFiles(dir, tmpDir); err != nil {
+				return err
+			}
+
+			dir = tmpDir
+		}
+
+		target, err := extension.GetExtensionByFolder(ctx, dir)
+		if err != nil {
+			return err
+		}
+
+		toolCfg, err := verifier.ConvertExtensionToToolConfig(target)
+		if err != nil {
+			return err
+		}
+
+		toolCfg.InputWasDirectory = true
+		toolCfg.CheckAgainst = opts.checkAgainst
+
+		if opts.storeCompliance {
+			enableStoreCompliance(toolCfg)
+		}
+
+		check := verifier.NewCheck()
+		if err := tools.Check(ctx, check, *toolCfg); err != nil {
+			return err
+		}
+
+		prefix, err := filepath.Rel(workingDir, ext.GetPath())
+		if err != nil {
+			prefix = ext.GetPath()
+		}
+
+		for _, result := range check.ApplySeverityOverrides(toolCfg.SeverityOverrides).RemoveByIdentifier(toolCfg.ValidationIgnores).GetResults() {
+			result.Path = filepath.ToSlash(filepath.Join(prefix, result.Path))
+			combined.AddResult(result)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	reportErr := validation.DoCheckReport(combined, opts.reportingFormat)
+
+	if err := bulkError(cmd, results); err != nil {
+		return err
+	}
+
+	return reportErr
+}
@@ -8,10 +8,13 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/shopware/shopware-cli/internal/asset"
 	"github.com/shopware/shopware-cli/internal/ci"
 	"github.com/shopware/shopware-cli/internal/executor"
 	"github.com/shopware/shopware-cli/internal/extension"
@@ -20,6 +23,7 @@ import (
 	"github.com/shopware/shopware-cli/internal/shop"
 	"github.com/shopware/shopware-cli/internal/system"
 	"github.com/shopware/shopware-cli/internal/tui"
+	"github.com/shopware/shopware-cli/internal/validation"
 	"github.com/shopware/shopware-cli/logging"
 )
 
@@ -38,7 +42,23 @@ var cleanupPaths = []string{
 var projectCI = &cobra.Command{
 	Use:   "ci",
 	Short: "Build Shopware in the CI",
-	Args:  cobra.ExactArgs(1),
+	Long: `Build Shopware in the CI. The build runs the following steps:
+
+  composer                 Install the composer dependencies
+  sbom                     Generate the SBOM of the composer dependencies
+  audit                    Check the dependencies against the advisory database (audit.database)
+  assets                   Build the administration and storefront assets
+  optimize                 Remove files not needed in production
+  warmup                   Warm up the container cache and install the assets
+  mjml                     Compile MJML mail templates (build.mjml.enabled)
+  remove-extension-assets  Delete the extension assets (build.remove_extension_assets)
+  checksums                Generate the checksum.json of the extensions
+
+Use --from to resume a failed build at a step or --only to run a single step.
+With build.step_caching enabled, vendor/ is cached keyed by composer.json and
+composer.lock and restored instead of running composer install again. Hooks can
+be attached to every step with build.hooks.steps.<step>.pre and .post.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var err error
 		args[0], err = filepath.Abs(args[0])
@@ -85,266 +105,393 @@ var projectCI = &cobra.Command{
 
 		cleanupPaths = append(cleanupPaths, shopCfg.Build.CleanupPaths...)
 
+		withDev, _ := cmd.Flags().GetBool("with-dev-dependencies")
+		from, _ := cmd.Flags().GetString("from")
+		only, _ := cmd.Flags().GetString("only")
+		reportPath, _ := cmd.Flags().GetString("report")
+
+		pipeline := newProjectCIPipeline(args[0], shopCfg, cmdExecutor, withDev)
+
 		if shopCfg.Build.Hooks != nil && len(shopCfg.Build.Hooks.Pre) > 0 {
 			if err := executeCIHooks(cmd.Context(), "Running pre hooks", shopCfg.Build.Hooks.Pre, args[0]); err != nil {
 				return err
 			}
 		}
 
-		if !shopCfg.DisableComposerInstall {
-			composerFlags := []string{"install", "--no-interaction", "--no-progress", "--optimize-autoloader", "--classmap-authoritative"}
-
-			if withDev, _ := cmd.Flags().GetBool("with-dev-dependencies"); !withDev {
-				composerFlags = append(composerFlags, "--no-dev")
-			}
-
-			if shopCfg.DisableComposerScripts {
-				composerFlags = append(composerFlags, "--no-scripts")
-			}
-
-			token, err := prepareComposerAuth(cmd.Context(), args[0])
-			if err != nil {
-				return err
-			}
+		report, err := pipeline.Run(cmd.Context(), ci.RunOptions{From: from, Only: only})
+		if report != nil {
+			logCIReport(cmd.Context(), report)
 
-			if shopCfg.Build.Hooks != nil && len(shopCfg.Build.Hooks.PreComposer) > 0 {
-				if err := executeCIHooks(cmd.Context(), "Running pre-composer hooks", shopCfg.Build.Hooks.PreComposer, args[0]); err != nil {
-					return err
+			if reportPath != "" {
+				if writeErr := report.WriteFile(reportPath); writeErr != nil {
+					return fmt.Errorf("failed to write step report: %w", writeErr)
 				}
 			}
+		}
 
-			composerInstallSection := ci.Default.Section(cmd.Context(), "Composer Installation")
-
-			composer := cmdExecutor.ComposerCommand(cmd.Context(), composerFlags...)
-			composer.Cmd.Stdin = os.Stdin
-			composer.Cmd.Stdout = os.Stdout
-			composer.Cmd.Stderr = os.Stderr
-			composer.Cmd.Env = append(os.Environ(),
-				"COMPOSER_AUTH="+token,
-			)
-
-			if err := composer.Run(); err != nil {
-				return err
-			}
-
-			composerInstallSection.End(cmd.Context())
-
-			if shopCfg.Build.Hooks != nil && len(shopCfg.Build.Hooks.PostComposer) > 0 {
-				if err := executeCIHooks(cmd.Context(), "Running post-composer hooks", shopCfg.Build.Hooks.PostComposer, args[0]); err != nil {
-					return err
+		if err != nil {
+			if report != nil {
+				for _, step := range report.Steps {
+					if step.Status == ci.StepStatusFailed {
+						logging.FromContext(cmd.Context()).Infof("Fix the problem and resume the build with: shopware-cli project ci --from %s %s", step.Name, args[0])
+					}
 				}
 			}
-		} else {
-			logging.FromContext(cmd.Context()).Infof("Skipping composer install")
-		}
 
-		if err := generateProjectSBOM(cmd.Context(), args[0]); err != nil {
-			return fmt.Errorf("failed to generate SBOM: %w", err)
+			return err
 		}
 
-		if _, err := os.Stat(path.Join(args[0], "var", "cache")); err == nil {
-			logging.FromContext(cmd.Context()).Infof("Removing var/cache")
-			if err := os.RemoveAll(path.Join(args[0], "var", "cache")); err != nil {
+		if shopCfg.Build.Hooks != nil && len(shopCfg.Build.Hooks.Post) > 0 {
+			if err := executeCIHooks(cmd.Context(), "Running post hooks", shopCfg.Build.Hooks.Post, args[0]); err != nil {
 				return err
 			}
 		}
 
-		lookingForExtensionsSection := ci.Default.Section(cmd.Context(), "Looking for extensions")
+		return nil
+	},
+}
 
-		sources := extension.FindAssetSourcesOfProject(cmd.Context(), args[0], shopCfg)
+// newProjectCIPipeline declares the steps of project ci. Steps are skipped when
+// their inputs are unchanged and build.step_caching is enabled.
+func newProjectCIPipeline(root string, shopCfg *shop.Config, cmdExecutor executor.Executor, withDev bool) *ci.Pipeline { //nolint:gocyclo
+	var sources []asset.Source
+	sourcesLoaded := false
 
-		shopwareConstraint, err := extension.GetShopwareProjectConstraint(args[0])
-		if err != nil {
-			return err
+	// findSources is shared by all steps touching extension assets, so it also works with --from.
+	findSources := func(ctx context.Context) []asset.Source {
+		if sourcesLoaded {
+			return sources
 		}
 
-		lookingForExtensionsSection.End(cmd.Context())
-
-		assetCfg := extension.AssetBuildConfig{
-			EnableAssetCaching:           shopCfg.Build.AssetCaching,
-			CleanupNodeModules:           true,
-			ShopwareRoot:                 args[0],
-			ShopwareVersion:              shopwareConstraint,
-			Browserslist:                 shopCfg.Build.Browserslist,
-			SkipExtensionsWithBuildFiles: true,
-			DisableStorefrontBuild:       shopCfg.Build.DisableStorefrontBuild,
-			ForceExtensionBuild:          convertForceExtensionBuild(shopCfg.Build.ForceExtensionBuild),
-			ForceAdminBuild:              shopCfg.Build.ForceAdminBuild,
-			KeepNodeModules:              shopCfg.Build.KeepNodeModules,
-			Executor:                     cmdExecutor,
-		}
+		ctx, lookingForExtensionsSection := ci.Default.Section(ctx, "Looking for extensions")
+		sources = extension.FindAssetSourcesOfProject(ctx, root, shopCfg)
+		sourcesLoaded = true
+		lookingForExtensionsSection.End(ctx, nil)
 
-		if shopCfg.Build.Hooks != nil && len(shopCfg.Build.Hooks.PreAssets) > 0 {
-			if err := executeCIHooks(cmd.Context(), "Running pre-assets hooks", shopCfg.Build.Hooks.PreAssets, args[0]); err != nil {
-				return err
-			}
-		}
+		return sources
+	}
 
-		if err := extension.BuildAssetsForExtensions(cmd.Context(), sources, assetCfg); err != nil {
-			return err
-		}
+	composerFlags := []string{"install", "--no-interaction", "--no-progress", "--optimize-autoloader", "--classmap-authoritative"}
 
-		if shopCfg.Build.Hooks != nil && len(shopCfg.Build.Hooks.PostAssets) > 0 {
-			if err := executeCIHooks(cmd.Context(), "Running post-assets hooks", shopCfg.Build.Hooks.PostAssets, args[0]); err != nil {
-				return err
-			}
-		}
+	if !withDev {
+		composerFlags = append(composerFlags, "--no-dev")
+	}
 
-		optimizeSection := ci.Default.Section(cmd.Context(), "Optimizing Administration Assets")
+	if shopCfg.DisableComposerScripts {
+		composerFlags = append(composerFlags, "--no-scripts")
+	}
 
-		if err := extension.CleanupAdministrationFiles(cmd.Context(), path.Join(args[0], "vendor", "shopware", "administration")); err != nil {
-			return err
-		}
+	steps := []ci.Step{
+		{
+			Name:     "composer",
+			Inputs:   []string{"composer.json", "composer.lock"},
+			CacheKey: composerFlags,
+			Outputs:  []string{"vendor"},
+			Run: func(ctx context.Context) (err error) {
+				token, err := prepareComposerAuth(ctx, root)
+				if err != nil {
+					return err
+				}
 
-		if err := createEmptySnippetFolder(path.Join(args[0], "vendor", "shopware", "administration")); err != nil {
-			return err
-		}
+				ctx, composerInstallSection := ci.Default.Section(ctx, "Composer Installation")
+				defer func() { composerInstallSection.End(ctx, err) }()
+
+				composer := cmdExecutor.ComposerCommand(ctx, composerFlags...)
+				composer.Cmd.Stdin = os.Stdin
+				composer.Cmd.Stdout = os.Stdout
+				composer.Cmd.Stderr = os.Stderr
+				composer.Cmd.Env = append(os.Environ(),
+					"COMPOSER_AUTH="+token,
+				)
+
+				return composer.Run()
+			},
+		},
+		{
+			Name:  "sbom",
+			Needs: []string{"composer"},
+			Run: func(ctx context.Context) error {
+				if err := generateProjectSBOM(ctx, root); err != nil {
+					return fmt.Errorf("failed to generate SBOM: %w", err)
+				}
 
-		if !shopCfg.Build.KeepExtensionSource {
-			for _, source := range sources {
-				if err := extension.CleanupAdministrationFiles(cmd.Context(), source.Path); err != nil {
-					return err
+				return nil
+			},
+		},
+		{
+			Name:  "audit",
+			Needs: []string{"composer"},
+			Run: func(ctx context.Context) (err error) {
+				ctx, section := ci.Default.Section(ctx, "Auditing dependencies")
+				defer func() { section.End(ctx, err) }()
+
+				return runProjectAudit(ctx, root, shopCfg, "", shopCfg.Audit.IncludeDev, validation.DetectDefaultReporter())
+			},
+		},
+		{
+			Name:  "assets",
+			Needs: []string{"composer"},
+			Run: func(ctx context.Context) error {
+				if _, err := os.Stat(path.Join(root, "var", "cache")); err == nil {
+					logging.FromContext(ctx).Infof("Removing var/cache")
+					if err := os.RemoveAll(path.Join(root, "var", "cache")); err != nil {
+						return err
+					}
 				}
-			}
-		}
 
-		if !shopCfg.Build.KeepSourceMaps {
-			if err := extension.CleanupJavaScriptSourceMaps(path.Join(args[0], "vendor", "shopware", "administration", "Resources", "public")); err != nil {
-				return err
-			}
+				sources := findSources(ctx)
 
-			for _, source := range sources {
-				if err := extension.CleanupJavaScriptSourceMaps(path.Join(source.Path, "Resources", "public")); err != nil {
+				shopwareConstraint, err := extension.GetShopwareProjectConstraint(root)
+				if err != nil {
 					return err
 				}
-			}
-		}
 
-		for _, removePath := range cleanupPaths {
-			logging.FromContext(cmd.Context()).Infof("Removing %s", removePath)
-			fullPath := path.Join(args[0], removePath)
-			if err := os.RemoveAll(fullPath); err != nil {
-				return err
-			}
-		}
+				assetCfg := extension.AssetBuildConfig{
+					EnableAssetCaching:           shopCfg.Build.AssetCaching,
+					CleanupNodeModules:           true,
+					ShopwareRoot:                 root,
+					ShopwareVersion:              shopwareConstraint,
+					Browserslist:                 shopCfg.Build.Browserslist,
+					SkipExtensionsWithBuildFiles: true,
+					DisableStorefrontBuild:       shopCfg.Build.DisableStorefrontBuild,
+					ForceExtensionBuild:          convertForceExtensionBuild(shopCfg.Build.ForceExtensionBuild),
+					ForceAdminBuild:              shopCfg.Build.ForceAdminBuild,
+					KeepNodeModules:              shopCfg.Build.KeepNodeModules,
+					Executor:                     cmdExecutor,
+				}
 
-		if err := cleanupTcpdf(args[0], cmd.Context()); err != nil {
-			return err
-		}
+				return extension.BuildAssetsForExtensions(ctx, sources, assetCfg)
+			},
+		},
+		{
+			Name:  "optimize",
+			Needs: []string{"assets"},
+			Run: func(ctx context.Context) (err error) {
+				ctx, optimizeSection := ci.Default.Section(ctx, "Optimizing Administration Assets")
+				defer func() { optimizeSection.End(ctx, err) }()
 
-		optimizeSection.End(cmd.Context())
+				sources := findSources(ctx)
 
-		warumupSection := ci.Default.Section(cmd.Context(), "Warming up container cache")
+				if err := extension.CleanupAdministrationFiles(ctx, path.Join(root, "vendor", "shopware", "administration")); err != nil {
+					return err
+				}
 
-		if err := runTransparentCommand(binCICommand(cmd.Context(), cmdExecutor, "--version")); err != nil { //nolint: gosec
-			return fmt.Errorf("failed to warmup container cache (php bin/ci --version): %w", err)
-		}
+				if err := createEmptySnippetFolder(path.Join(root, "vendor", "shopware", "administration")); err != nil {
+					return err
+				}
+
+				if !shopCfg.Build.KeepExtensionSource {
+					for _, source := range sources {
+						if err := extension.CleanupAdministrationFiles(ctx, source.Path); err != nil {
+							return err
+						}
+					}
+				}
+
+				if !shopCfg.Build.KeepSourceMaps {
+					if err := extension.CleanupJavaScriptSourceMaps(path.Join(root, "vendor", "shopware", "administration", "Resources", "public")); err != nil {
+						return err
+					}
+
+					for _, source := range sources {
+						if err := extension.CleanupJavaScriptSourceMaps(path.Join(source.Path, "Resources", "public")); err != nil {
+							return err
+						}
+					}
+				}
 
-		if !shopCfg.Build.DisableAssetCopy {
-			logging.FromContext(cmd.Context()).Infof("Copying extension assets to final public/bundles folder")
+				for _, removePath := range cleanupPaths {
+					logging.FromContext(ctx).Infof("Removing %s", removePath)
+					fullPath := path.Join(root, removePath)
+					if err := os.RemoveAll(fullPath); err != nil {
+						return err
+					}
+				}
 
-			// Delete asset manifest to force a new build
-			manifestPath := path.Join(args[0], "public", "asset-manifest.json")
-			if _, err := os.Stat(manifestPath); err == nil {
-				if err := os.Remove(manifestPath); err != nil {
+				if err := cleanupTcpdf(root, ctx); err != nil {
 					return err
 				}
-			}
 
-			if err := runTransparentCommand(binCICommand(cmd.Context(), cmdExecutor, "asset:install")); err != nil { //nolint: gosec
-				return fmt.Errorf("failed to install assets (php bin/ci asset:install): %w", err)
-			}
-		}
+				return nil
+			},
+		},
+		{
+			Name:  "warmup",
+			Needs: []string{"optimize"},
+			Run: func(ctx context.Context) (err error) {
+				ctx, warumupSection := ci.Default.Section(ctx, "Warming up container cache")
+				defer func() { warumupSection.End(ctx, err) }()
+
+				if err := runTransparentCommand(binCICommand(ctx, cmdExecutor, "--version")); err != nil { //nolint: gosec
+					return fmt.Errorf("failed to warmup container cache (php bin/ci --version): %w", err)
+				}
 
-		warumupSection.End(cmd.Context())
+				if !shopCfg.Build.DisableAssetCopy {
+					logging.FromContext(ctx).Infof("Copying extension assets to final public/bundles folder")
 
-		if shopCfg.Build.IsMjmlEnabled() {
-			mjmlSection := ci.Default.Section(cmd.Context(), "Compiling MJML templates")
+					// Delete asset manifest to force a new build
+					manifestPath := path.Join(root, "public", "asset-manifest.json")
+					if _, err := os.Stat(manifestPath); err == nil {
+						if err := os.Remove(manifestPath); err != nil {
+							return err
+						}
+					}
 
-			extraIncludePaths := shopCfg.Build.MJML.ResolveIncludePaths(args[0])
+					if err := runTransparentCommand(binCICommand(ctx, cmdExecutor, "asset:install")); err != nil { //nolint: gosec
+						return fmt.Errorf("failed to install assets (php bin/ci asset:install): %w", err)
+					}
+				}
 
-			for _, searchPath := range shopCfg.Build.MJML.GetPaths(args[0]) {
-				if _, err := os.Stat(searchPath); !os.IsNotExist(err) {
-					logging.FromContext(cmd.Context()).Infof("Processing MJML files in: %s", searchPath)
-					mjmlOpts := mjml.NewCompileOptions(searchPath, shopCfg.Build.MJML.AllowIncludes, extraIncludePaths)
-					if err := mjml.ProcessDirectory(cmd.Context(), searchPath, mjmlOpts); err != nil {
-						logging.FromContext(cmd.Context()).Warnf("MJML compilation had issues in %s: %v", searchPath, err)
+				return nil
+			},
+		},
+		{
+			Name:  "mjml",
+			Needs: []string{"warmup"},
+			Run: func(ctx context.Context) error {
+				ctx, mjmlSection := ci.Default.Section(ctx, "Compiling MJML templates")
+
+				extraIncludePaths := shopCfg.Build.MJML.ResolveIncludePaths(root)
+
+				for _, searchPath := range shopCfg.Build.MJML.GetPaths(root) {
+					if _, err := os.Stat(searchPath); !os.IsNotExist(err) {
+						logging.FromContext(ctx).Infof("Processing MJML files in: %s", searchPath)
+						mjmlOpts := mjml.NewCompileOptions(searchPath, shopCfg.Build.MJML.AllowIncludes, extraIncludePaths)
+						if err := mjml.ProcessDirectory(ctx, searchPath, mjmlOpts); err != nil {
+							logging.FromContext(ctx).Warnf("MJML compilation had issues in %s: %v", searchPath, err)
+						}
+					} else {
+						logging.FromContext(ctx).Debugf("MJML search path does not exist: %s", searchPath)
 					}
-				} else {
-					logging.FromContext(cmd.Context()).Debugf("MJML search path does not exist: %s", searchPath)
 				}
-			}
 
-			mjmlSection.End(cmd.Context())
-		}
+				mjmlSection.End(ctx, nil)
+
+				return nil
+			},
+		},
+		{
+			Name:  "remove-extension-assets",
+			Needs: []string{"warmup"},
+			Run: func(ctx context.Context) (err error) {
+				ctx, deleteAssetsSection := ci.Default.Section(ctx, "Deleting assets of extensions")
+				defer func() { deleteAssetsSection.End(ctx, err) }()
+
+				for _, source := range findSources(ctx) {
+					if _, err := os.Stat(path.Join(source.Path, "Resources", "public", "administration", "css")); err == nil {
+						if err := os.WriteFile(path.Join(source.Path, "Resources", ".administration-css"), []byte{}, 0o644); err != nil {
+							return err
+						}
+					}
 
-		if shopCfg.Build.RemoveExtensionAssets {
-			deleteAssetsSection := ci.Default.Section(cmd.Context(), "Deleting assets of extensions")
+					if _, err := os.Stat(path.Join(source.Path, "Resources", "public", "administration", "js")); err == nil {
+						if err := os.WriteFile(path.Join(source.Path, "Resources", ".administration-js"), []byte{}, 0o644); err != nil {
+							return err
+						}
+					}
 
-			for _, source := range sources {
-				if _, err := os.Stat(path.Join(source.Path, "Resources", "public", "administration", "css")); err == nil {
-					if err := os.WriteFile(path.Join(source.Path, "Resources", ".administration-css"), []byte{}, 0o644); err != nil {
+					if err := os.RemoveAll(path.Join(source.Path, "Resources", "public")); err != nil {
 						return err
 					}
 				}
 
-				if _, err := os.Stat(path.Join(source.Path, "Resources", "public", "administration", "js")); err == nil {
-					if err := os.WriteFile(path.Join(source.Path, "Resources", ".administration-js"), []byte{}, 0o644); err != nil {
-						return err
-					}
+				if err := os.RemoveAll(path.Join(root, "vendor", "shopware", "administration", "Resources", "public")); err != nil {
+					return err
 				}
 
-				if err := os.RemoveAll(path.Join(source.Path, "Resources", "public")); err != nil {
+				if err := os.WriteFile(path.Join(root, "vendor", "shopware", "administration", "Resources", ".administration-js"), []byte{}, 0o644); err != nil {
 					return err
 				}
-			}
 
-			if err := os.RemoveAll(path.Join(args[0], "vendor", "shopware", "administration", "Resources", "public")); err != nil {
-				return err
-			}
+				if err := os.WriteFile(path.Join(root, "vendor", "shopware", "administration", "Resources", ".administration-css"), []byte{}, 0o644); err != nil {
+					return err
+				}
 
-			if err := os.WriteFile(path.Join(args[0], "vendor", "shopware", "administration", "Resources", ".administration-js"), []byte{}, 0o644); err != nil {
-				return err
-			}
+				return nil
+			},
+		},
+		{
+			Name:  "checksums",
+			Needs: []string{"mjml", "remove-extension-assets"},
+			Run: func(ctx context.Context) error {
+				ctx, checksumSection := ci.Default.Section(ctx, "Generating extension checksums")
+
+				extensions := extension.FindExtensionsFromProject(ctx, root, false)
+
+				for _, ext := range extensions {
+					extPath := ext.GetPath()
+
+					if shopCfg.Build.KeepExistingChecksums {
+						if _, err := os.Stat(path.Join(extPath, "checksum.json")); err == nil {
+							logging.FromContext(ctx).Infof("Keeping existing checksum.json for %s", extPath)
+							continue
+						}
+					}
 
-			if err := os.WriteFile(path.Join(args[0], "vendor", "shopware", "administration", "Resources", ".administration-css"), []byte{}, 0o644); err != nil {
-				return err
-			}
+					if err := extension.GenerateChecksumJSON(ctx, extPath, ext); err != nil {
+						logging.FromContext(ctx).Warnf("Failed to generate checksum for %s: %v", extPath, err)
+					}
+				}
 
-			deleteAssetsSection.End(cmd.Context())
-		}
+				checksumSection.End(ctx, nil)
 
-		if !shopCfg.Build.DisableChecksums {
-			checksumSection := ci.Default.Section(cmd.Context(), "Generating extension checksums")
+				return nil
+			},
+		},
+	}
 
-			extensions := extension.FindExtensionsFromProject(cmd.Context(), args[0], false)
+	for i := range steps {
+		switch {
+		case steps[i].Name == "composer" && shopCfg.DisableComposerInstall:
+			steps[i].Disabled = "disabled by disable_composer_install"
+		case steps[i].Name == "audit" && (shopCfg.Audit == nil || shopCfg.Audit.Database == ""):
+			steps[i].Disabled = "audit.database is not configured"
+		case steps[i].Name == "mjml" && !shopCfg.Build.IsMjmlEnabled():
+			steps[i].Disabled = "MJML compilation is not enabled"
+		case steps[i].Name == "remove-extension-assets" && !shopCfg.Build.RemoveExtensionAssets:
+			steps[i].Disabled = "remove_extension_assets is not enabled"
+		case steps[i].Name == "checksums" && shopCfg.Build.DisableChecksums:
+			steps[i].Disabled = "disabled by disable_checksums"
+		}
+	}
 
-			for _, ext := range extensions {
-				extPath := ext.GetPath()
+	pipeline := &ci.Pipeline{
+		Root:  root,
+		Steps: steps,
+		Hooks: map[string]ci.StepHooks{},
+		RunHooks: func(ctx context.Context, sectionName string, hooks []string) error {
+			return executeCIHooks(ctx, sectionName, hooks, root)
+		},
+	}
 
-				if shopCfg.Build.KeepExistingChecksums {
-					if _, err := os.Stat(path.Join(extPath, "checksum.json")); err == nil {
-						logging.FromContext(cmd.Context()).Infof("Keeping existing checksum.json for %s", extPath)
-						continue
-					}
-				}
+	if shopCfg.Build.StepCaching {
+		pipeline.Cache = system.GetDefaultCache()
+	}
 
-				if err := extension.GenerateChecksumJSON(cmd.Context(), extPath, ext); err != nil {
-					logging.FromContext(cmd.Context()).Warnf("Failed to generate checksum for %s: %v", extPath, err)
-				}
-			}
+	if hooks := shopCfg.Build.Hooks; hooks != nil {
+		pipeline.Hooks["composer"] = ci.StepHooks{Pre: hooks.PreComposer, Post: hooks.PostComposer}
+		pipeline.Hooks["assets"] = ci.StepHooks{Pre: hooks.PreAssets, Post: hooks.PostAssets}
 
-			checksumSection.End(cmd.Context())
+		for name, stepHooks := range hooks.Steps {
+			merged := pipeline.Hooks[name]
+			merged.Pre = append(slices.Clone(merged.Pre), stepHooks.Pre...)
+			merged.Post = append(slices.Clone(merged.Post), stepHooks.Post...)
+			pipeline.Hooks[name] = merged
 		}
+	}
 
-		if shopCfg.Build.Hooks != nil && len(shopCfg.Build.Hooks.Post) > 0 {
-			if err := executeCIHooks(cmd.Context(), "Running post hooks", shopCfg.Build.Hooks.Post, args[0]); err != nil {
-				return err
-			}
+	return pipeline
+}
+
+func logCIReport(ctx context.Context, report *ci.Report) {
+	for _, step := range report.Steps {
+		message := fmt.Sprintf("Step %s: %s (%s)", step.Name, step.Status, time.Duration(step.DurationMS)*time.Millisecond)
+		if step.Reason != "" {
+			message += ", " + step.Reason
 		}
 
-		return nil
-	},
+		logging.FromContext(ctx).Infof("%s", message)
+	}
 }
 
 func createEmptySnippetFolder(root string) error {
@@ -374,9 +521,9 @@ func createEmptySnippetFolder(root string) error {
 // composer.lock is absent (for example when composer install was skipped on a
 // project without PHP dependencies) the step is a no-op so the rest of project
 // ci can continue. Output path and format match the defaults of `project sbom`.
-func generateProjectSBOM(ctx context.Context, root string) error {
-	section := ci.Default.Section(ctx, "Generating SBOM")
-	defer section.End(ctx)
+func generateProjectSBOM(ctx context.Context, root string) (err error) {
+	ctx, section := ci.Default.Section(ctx, "Generating SBOM")
+	defer func() { section.End(ctx, err) }()
 
 	return shop.WriteProjectSBOM(ctx, root, shop.ProjectSBOMOptions{
 		// CI historically skips when lock is missing rather than failing the build.
@@ -405,6 +552,9 @@ func init() {
 	projectRootCmd.AddCommand(projectCI)
 	projectCI.PersistentFlags().Bool("with-dev-dependencies", false, "Install dev dependencies")
 	projectCI.PersistentFlags().Bool("force", false, "Run project ci outside CI even when the git working tree has local changes")
+	projectCI.PersistentFlags().String("from", "", "Resume the build at the given step and skip all steps before it")
+	projectCI.PersistentFlags().String("only", "", "Run only the given step")
+	projectCI.PersistentFlags().String("report", "", "Write a JSON report of the executed steps to the given file")
 }
 
 func projectCISafetyCheck(ctx context.Context, root string, force bool, getenv func(string) string) error {
@@ -495,8 +645,9 @@ func convertForceExtensionBuild(configExtensions []shop.ConfigBuildExtension) []
 	return extensionConfigs
 }
 
-func executeCIHooks(ctx context.Context, sectionName string, hooks []string, root string) error {
-	section := ci.Default.Section(ctx, sectionName)
+func executeCIHooks(ctx context.Context, sectionName string, hooks []string, root string) (err error) {
+	ctx, section := ci.Default.Section(ctx, sectionName)
+	defer func() { section.End(ctx, err) }()
 
 	for _, hook := range hooks {
 		logging.FromContext(ctx).Infof("Running hook: %s", hook)
@@ -511,7 +662,5 @@ func executeCIHooks(ctx context.Context, sectionName string, hooks []string, roo
 		}
 	}
 
-	section.End(ctx)
-
 	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/shopware/shopware-cli/internal/executor"
+	"github.com/shopware/shopware-cli/internal/shop"
 )
 
 func TestBinCICommand(t *testing.T) {
@@ -121,3 +122,47 @@ func mapGetenv(env map[string]string) func(string) string {
 		return env[key]
 	}
 }
+
+func TestNewProjectCIPipeline(t *testing.T) {
+	shopCfg := &shop.Config{
+		Build: &shop.ConfigBuild{
+			DisableChecksums: true,
+			Hooks: &shop.ConfigBuildHooks{
+				PreComposer: []string{"echo legacy"},
+				Steps: map[string]shop.ConfigBuildStepHooks{
+					"composer": {Pre: []string{"echo step"}},
+					"warmup":   {Post: []string{"echo warmup"}},
+				},
+			},
+		},
+	}
+
+	pipeline := newProjectCIPipeline("/project", shopCfg, executor.NewLocal("/project"), false)
+
+	names, err := pipeline.StepNames()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"composer", "sbom", "audit", "assets", "optimize", "warmup", "mjml", "remove-extension-assets", "checksums"}, names)
+
+	assert.Equal(t, []string{"echo legacy", "echo step"}, pipeline.Hooks["composer"].Pre)
+	assert.Equal(t, []string{"echo warmup"}, pipeline.Hooks["warmup"].Post)
+	assert.Nil(t, pipeline.Cache)
+
+	disabled := map[string]string{}
+	for _, step := range pipeline.Steps {
+		if step.Disabled != "" {
+			disabled[step.Name] = step.Disabled
+		}
+
+		if step.Name == "composer" {
+			assert.Contains(t, step.CacheKey, "--no-dev")
+			assert.Equal(t, []string{"vendor"}, step.Outputs)
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"audit":                   "audit.database is not configured",
+		"mjml":                    "MJML compilation is not enabled",
+		"remove-extension-assets": "remove_extension_assets is not enabled",
+		"checksums":               "disabled by disable_checksums",
+	}, disabled)
+}
@@ -0,0 +1,193 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	cp "github.com/otiai10/copy"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/audit"
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectAuditCmd = &cobra.Command{
+	Use:   "audit [path]",
+	Short: "Check the composer and npm dependencies against an advisory database",
+	Long: `Check the packages of composer.lock and of the package-lock.json files of the
+project and its extensions against an offline advisory database.
+
+The database is a checkout of FriendsOfPHP/security-advisories (YAML) or
+github/advisory-database (OSV JSON), a directory mixing both or a single file.
+It is taken from --database, audit.database of the project config or the
+database imported with project audit import.
+
+Advisories can be ignored until a date with audit.ignore in the project config:
+
+  audit:
+    ignore:
+      - id: CVE-2024-12345
+        until: 2026-12-31
+        reason: Not reachable, the feature is disabled`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportingFormat, _ := cmd.Flags().GetString("reporter")
+		database, _ := cmd.Flags().GetString("database")
+
+		var projectRoot string
+		var err error
+
+		if len(args) > 0 {
+			projectRoot, err = filepath.Abs(args[0])
+		} else {
+			projectRoot, err = findClosestShopwareProject()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		shopCfg, err := shop.ReadConfig(cmd.Context(), projectConfigPath, true)
+		if err != nil {
+			return err
+		}
+
+		if reportingFormat == "" {
+			reportingFormat = validation.DetectDefaultReporter()
+		}
+
+		includeDev := shopCfg.Audit != nil && shopCfg.Audit.IncludeDev
+		if cmd.Flags().Changed("include-dev") {
+			includeDev, _ = cmd.Flags().GetBool("include-dev")
+		}
+
+		return runProjectAudit(cmd.Context(), projectRoot, shopCfg, database, includeDev, reportingFormat)
+	},
+}
+
+var projectAuditImportCmd = &cobra.Command{
+	Use:   "import [path]",
+	Short: "Import an advisory database for offline use",
+	Long: `Copy an advisory database file or directory into the cache directory. It is
+used by project audit when neither --database nor audit.database is set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := audit.LoadDatabase(args[0])
+		if err != nil {
+			return err
+		}
+
+		target := importedAdvisoryDatabase()
+
+		if err := os.RemoveAll(target); err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			return err
+		}
+
+		source := args[0]
+		destination := target
+
+		if stat, err := os.Stat(source); err == nil && !stat.IsDir() {
+			destination = filepath.Join(target, filepath.Base(source))
+		}
+
+		if err := cp.Copy(source, destination, cp.Options{
+			Skip: func(_ os.FileInfo, src, _ string) (bool, error) {
+				return filepath.Base(src) == ".git", nil
+			},
+		}); err != nil {
+			return fmt.Errorf("copy advisory database: %w", err)
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Imported %d advisories to %s", db.Len(), target)
+
+		return nil
+	},
+}
+
+// importedAdvisoryDatabase is the directory project audit import copies the database to.
+func importedAdvisoryDatabase() string {
+	return filepath.Join(system.GetShopwareCliCacheDir(), "advisories")
+}
+
+// resolveAdvisoryDatabase returns the database of the flag, the project config or the imported one.
+func resolveAdvisoryDatabase(projectRoot string, shopCfg *shop.Config, database string) (string, error) {
+	if database != "" {
+		return database, nil
+	}
+
+	if shopCfg.Audit != nil && shopCfg.Audit.Database != "" {
+		if filepath.IsAbs(shopCfg.Audit.Database) {
+			return shopCfg.Audit.Database, nil
+		}
+
+		return filepath.Join(projectRoot, shopCfg.Audit.Database), nil
+	}
+
+	if _, err := os.Stat(importedAdvisoryDatabase()); err == nil {
+		return importedAdvisoryDatabase(), nil
+	}
+
+	return "", fmt.Errorf("no advisory database found, pass --database, set audit.database in the project config or run project audit import")
+}
+
+// runProjectAudit audits the dependencies of the project and reports the findings.
+func runProjectAudit(ctx context.Context, projectRoot string, shopCfg *shop.Config, database string, includeDev bool, reportingFormat string) error {
+	databasePath, err := resolveAdvisoryDatabase(projectRoot, shopCfg, database)
+	if err != nil {
+		return err
+	}
+
+	db, err := audit.LoadDatabase(databasePath)
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Infof("Loaded %d advisories from %s", db.Len(), databasePath)
+
+	dependencies, err := audit.CollectDependencies(ctx, projectRoot, includeDev)
+	if err != nil {
+		return err
+	}
+
+	var ignores []audit.Ignore
+
+	if shopCfg.Audit != nil {
+		for _, ignore := range shopCfg.Audit.Ignore {
+			ignores = append(ignores, audit.Ignore{ID: ignore.ID, Package: ignore.Package, Until: ignore.Until})
+		}
+	}
+
+	findings, err := db.Audit(dependencies, ignores, time.Now())
+	if err != nil {
+		return err
+	}
+
+	logging.FromContext(ctx).Infof("Checked %d dependencies, found %d vulnerabilities", len(dependencies), len(findings))
+
+	check := verifier.NewCheck()
+
+	for _, finding := range findings {
+		check.AddResult(finding.CheckResult())
+	}
+
+	return validation.DoCheckReport(check, reportingFormat)
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectAuditCmd)
+	projectAuditCmd.AddCommand(projectAuditImportCmd)
+	projectAuditCmd.Flags().String("reporter", "", "Reporting format (summary, json, github, gitlab, junit, markdown, sarif)")
+	projectAuditCmd.Flags().String("database", "", "Path to the advisory database file or directory")
+	projectAuditCmd.Flags().Bool("include-dev", false, "Include dev dependencies, overrides audit.include_dev")
+}
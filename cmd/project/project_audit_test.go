@@ -0,0 +1,56 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/shop"
+)
+
+func TestResolveAdvisoryDatabase(t *testing.T) {
+	t.Setenv("SHOPWARE_CLI_CACHE_DIR", t.TempDir())
+
+	database, err := resolveAdvisoryDatabase("/project", &shop.Config{}, "/flag")
+	require.NoError(t, err)
+	assert.Equal(t, "/flag", database)
+
+	database, err = resolveAdvisoryDatabase("/project", &shop.Config{Audit: &shop.ConfigAudit{Database: "advisories"}}, "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/project", "advisories"), database)
+
+	_, err = resolveAdvisoryDatabase("/project", &shop.Config{}, "")
+	assert.ErrorContains(t, err, "no advisory database found")
+
+	require.NoError(t, os.MkdirAll(importedAdvisoryDatabase(), 0o755))
+
+	database, err = resolveAdvisoryDatabase("/project", &shop.Config{}, "")
+	require.NoError(t, err)
+	assert.Equal(t, importedAdvisoryDatabase(), database)
+}
+
+func TestRunProjectAudit(t *testing.T) {
+	root := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package-lock.json"), []byte(`{
+		"lockfileVersion": 3,
+		"packages": {"node_modules/lodash": {"version": "4.17.20"}}
+	}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "advisories.json"), []byte(`{
+		"id": "GHSA-35jh-r3h4-6jhm",
+		"summary": "Command Injection in lodash",
+		"affected": [{"package": {"ecosystem": "npm", "name": "lodash"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]}],
+		"database_specific": {"severity": "HIGH"}
+	}`), 0o644))
+
+	shopCfg := &shop.Config{Audit: &shop.ConfigAudit{Database: "advisories.json"}}
+
+	assert.ErrorContains(t, runProjectAudit(t.Context(), root, shopCfg, "", false, "json"), "found errors")
+
+	shopCfg.Audit.Ignore = []shop.ConfigAuditIgnore{{ID: "GHSA-35jh-r3h4-6jhm", Until: "2999-01-01"}}
+
+	assert.NoError(t, runProjectAudit(t.Context(), root, shopCfg, "", false, "json"))
+}
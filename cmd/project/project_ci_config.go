@@ -0,0 +1,12 @@
+package project
+
+import "github.com/spf13/cobra"
+
+var projectCIConfigCmd = &cobra.Command{
+	Use:   "ci-config",
+	Short: "Manage the CI pipeline configuration of the project",
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectCIConfigCmd)
+}
@@ -0,0 +1,137 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shyim/go-composer"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/cigen"
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectCIConfigGenerateCmd = &cobra.Command{
+	Use:   "generate [path]",
+	Short: "Generate or update the CI pipeline of the project",
+	Long: `Generate a CI pipeline which validates the project, builds it with project ci,
+publishes the SBOM and deploys to the chosen target on the deployment branch.
+
+The command can be run again at any time, for example after upgrading
+shopware-cli. A copy of the generated files is kept in the .shopware-ci
+directory, which should be committed. It is used to 3-way merge the new output
+with changes made to the pipeline since it was generated. Conflicting changes
+are written with conflict markers. Existing files which were not generated by
+this command are only replaced with --force.
+
+Supported systems: github, gitlab, bitbucket, azure, jenkins
+Supported targets: none, deployer, platformsh, k8s
+
+Examples:
+  shopware-cli project ci-config generate --system github --target deployer
+  shopware-cli project ci-config generate --system gitlab --target k8s --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := resolveProjectSbomRoot(args)
+		if err != nil {
+			return err
+		}
+
+		system, _ := cmd.Flags().GetString("system")
+		target, _ := cmd.Flags().GetString("target")
+		phpVersion, _ := cmd.Flags().GetString("php-version")
+		branch, _ := cmd.Flags().GetString("branch")
+		k8sDeployment, _ := cmd.Flags().GetString("k8s-deployment")
+		force, _ := cmd.Flags().GetBool("force")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if target == "" {
+			target = detectCIConfigTarget(root)
+		}
+
+		if phpVersion == "" {
+			phpVersion = projectPHPVersion(root)
+		}
+
+		files, err := cigen.Generate(cigen.Options{
+			System:        system,
+			Target:        target,
+			PHPVersion:    phpVersion,
+			Branch:        branch,
+			K8sDeployment: k8sDeployment,
+		})
+		if err != nil {
+			return err
+		}
+
+		results, err := cigen.Write(root, files, cigen.WriteOptions{Force: force, DryRun: dryRun})
+		if err != nil {
+			return err
+		}
+
+		var conflicts []string
+
+		for _, result := range results {
+			switch result.Status {
+			case cigen.StatusSkipped:
+				logging.FromContext(cmd.Context()).Warnf("%s exists and was not generated by shopware-cli, use --force to replace it", result.Path)
+			case cigen.StatusConflict:
+				conflicts = append(conflicts, result.Path)
+				logging.FromContext(cmd.Context()).Warnf("%s: local changes conflict with the generated pipeline", result.Path)
+			default:
+				logging.FromContext(cmd.Context()).Infof("%s: %s", result.Path, result.Status)
+			}
+		}
+
+		if len(conflicts) > 0 && !dryRun {
+			return fmt.Errorf("resolve the conflict markers in %s", strings.Join(conflicts, ", "))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	projectCIConfigCmd.AddCommand(projectCIConfigGenerateCmd)
+	projectCIConfigGenerateCmd.Flags().String("system", "", "CI system ("+strings.Join(cigen.Systems, ", ")+")")
+	projectCIConfigGenerateCmd.Flags().String("target", "", "Deployment target ("+strings.Join(cigen.Targets, ", ")+"), detected from the project when empty")
+	projectCIConfigGenerateCmd.Flags().String("php-version", "", "PHP version of the shopware-cli image (default: highest version allowed by composer.lock)")
+	projectCIConfigGenerateCmd.Flags().String("branch", "main", "Branch which is deployed")
+	projectCIConfigGenerateCmd.Flags().String("k8s-deployment", "shopware", "Kubernetes deployment updated by the k8s target")
+	projectCIConfigGenerateCmd.Flags().Bool("force", false, "Overwrite existing files and discard local changes")
+	projectCIConfigGenerateCmd.Flags().Bool("dry-run", false, "Only report what would change")
+	_ = projectCIConfigGenerateCmd.MarkFlagRequired("system")
+}
+
+// detectCIConfigTarget guesses the deployment target from the files scaffolded by project create.
+func detectCIConfigTarget(root string) string {
+	if _, err := os.Stat(filepath.Join(root, "deploy.php")); err == nil {
+		return cigen.TargetDeployer
+	}
+
+	for _, name := range []string{".platform.app.yaml", ".platform"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			return cigen.TargetPlatformSH
+		}
+	}
+
+	return cigen.TargetNone
+}
+
+// projectPHPVersion returns the highest PHP version allowed by shopware/core in
+// composer.lock, or an empty string when it cannot be determined.
+func projectPHPVersion(root string) string {
+	lock, err := composer.ReadLock(filepath.Join(root, "composer.lock"))
+	if err != nil || lock == nil {
+		return ""
+	}
+
+	if constraint := shop.ShopwarePHPConstraint(lock); constraint != nil {
+		return constraint.HighestSupported()
+	}
+
+	return ""
+}
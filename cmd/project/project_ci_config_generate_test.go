@@ -0,0 +1,23 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/cigen"
+)
+
+func TestDetectCIConfigTarget(t *testing.T) {
+	root := t.TempDir()
+	assert.Equal(t, cigen.TargetNone, detectCIConfigTarget(root))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".platform.app.yaml"), []byte{}, 0o644))
+	assert.Equal(t, cigen.TargetPlatformSH, detectCIConfigTarget(root))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "deploy.php"), []byte("<?php"), 0o644))
+	assert.Equal(t, cigen.TargetDeployer, detectCIConfigTarget(root))
+}
@@ -0,0 +1,118 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/compatibility"
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectCompatCmd = &cobra.Command{
+	Use:   "compat",
+	Short: "Show and adopt the behavior changes of newer compatibility dates",
+}
+
+var projectCompatStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Lists the behavior changes between the configured compatibility date and the target date",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		outputJSON, _ := cmd.Flags().GetBool("json")
+
+		projectRoot, cfg, pending, err := pendingProjectChanges(cmd, to)
+		if err != nil {
+			return err
+		}
+
+		results, err := compatibility.Check(cmd.Context(), projectRoot, pending, false)
+		if err != nil {
+			return err
+		}
+
+		if outputJSON {
+			return compatibility.WriteReportJSON(cmd.OutOrStdout(), cfg.CompatibilityDate, to, results)
+		}
+
+		return compatibility.WriteReport(cmd.OutOrStdout(), cfg.CompatibilityDate, to, results)
+	},
+}
+
+var projectCompatUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Applies the migrations of the behavior changes and sets compatibility_date",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+
+		projectRoot, cfg, pending, err := pendingProjectChanges(cmd, to)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			results, err := compatibility.Check(cmd.Context(), projectRoot, pending, false)
+			if err != nil {
+				return err
+			}
+
+			return compatibility.WriteReport(cmd.OutOrStdout(), cfg.CompatibilityDate, to, results)
+		}
+
+		results, upgradeErr := compatibility.Upgrade(cmd.Context(), projectRoot, projectConfigPath, pending, to, force)
+
+		if err := compatibility.WriteReport(cmd.OutOrStdout(), cfg.CompatibilityDate, to, results); err != nil {
+			return err
+		}
+
+		if upgradeErr != nil {
+			return upgradeErr
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Set compatibility_date to %s in %s", to, projectConfigPath)
+
+		return nil
+	},
+}
+
+func pendingProjectChanges(cmd *cobra.Command, to string) (string, *shop.Config, []compatibility.Change, error) {
+	projectRoot, err := findClosestShopwareProject()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	cfg, err := shop.ReadConfig(cmd.Context(), projectConfigPath, true)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if err := compatibility.ValidateDate(to); err != nil {
+		return "", nil, nil, err
+	}
+
+	if compatibility.IsBefore(to, cfg.CompatibilityDate) {
+		return "", nil, nil, fmt.Errorf("the target date %s is before the configured compatibility date %s", to, cfg.CompatibilityDate)
+	}
+
+	pending, err := compatibility.Pending(compatibility.TargetProject, cfg.IsCompatibilityDateAtLeast, to)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return projectRoot, cfg, pending, nil
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectCompatCmd)
+	projectCompatCmd.AddCommand(projectCompatStatusCmd)
+	projectCompatCmd.AddCommand(projectCompatUpgradeCmd)
+
+	projectCompatStatusCmd.Flags().String("to", compatibility.TodayDate(), "Target compatibility date")
+	projectCompatStatusCmd.Flags().Bool("json", false, "Output as json")
+	projectCompatUpgradeCmd.Flags().String("to", compatibility.TodayDate(), "Target compatibility date")
+	projectCompatUpgradeCmd.Flags().Bool("dry-run", false, "Only show the changes without applying them")
+	projectCompatUpgradeCmd.Flags().Bool("force", false, "Set compatibility_date even when changes need manual work")
+}
@@ -0,0 +1,128 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+	liplogtable "charm.land/lipgloss/v2/table"
+	"github.com/spf13/cobra"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectExtensionApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Reconcile the extensions of the shop with the desired state",
+	Long: `Reconcile the extensions of the shop with deployment.extension-management
+of the project config, or with a dedicated file containing exclude and overrides.
+
+Extensions without override are installed, activated and updated. The states
+inactive, installed, remove and ignore change this per extension.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		outputAsJson, _ := cmd.Flags().GetBool("json")
+		stateFile, _ := cmd.Flags().GetString("file")
+
+		cfg, err := shop.ReadConfig(cmd.Context(), projectConfigPath, true)
+		if err != nil {
+			return err
+		}
+
+		desired := shop.DesiredExtensionStateFromConfig(cfg)
+		if stateFile != "" {
+			if desired, err = shop.ReadDesiredExtensionState(stateFile); err != nil {
+				return err
+			}
+		}
+
+		client, err := shop.NewShopClient(cmd.Context(), cfg)
+		if err != nil {
+			return err
+		}
+
+		if !dryRun {
+			if _, err := client.ExtensionManager.Refresh(adminSdk.NewApiContext(cmd.Context())); err != nil {
+				return err
+			}
+		}
+
+		installed, _, err := client.ExtensionManager.ListAvailableExtensions(adminSdk.NewApiContext(cmd.Context()))
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(installed))
+		for _, ext := range installed {
+			names = append(names, ext.Name)
+		}
+
+		order, err := extension.SortByDependencies(names, localExtensionDependencies(cmd))
+		if err != nil {
+			return err
+		}
+
+		plan := shop.PlanExtensionState(installed, desired, order)
+
+		if outputAsJson {
+			content, err := json.MarshalIndent(plan, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(content))
+		} else {
+			printExtensionStatePlan(plan)
+		}
+
+		if len(plan.Missing) > 0 {
+			return fmt.Errorf("the shop does not know the extensions %s", strings.Join(plan.Missing, ", "))
+		}
+
+		if dryRun {
+			return nil
+		}
+
+		return shop.ApplyExtensionState(cmd.Context(), client, plan)
+	},
+}
+
+func init() {
+	projectExtensionCmd.AddCommand(projectExtensionApplyCmd)
+	projectExtensionApplyCmd.Flags().Bool("dry-run", false, "Only print the plan")
+	projectExtensionApplyCmd.Flags().Bool("json", false, "Output the plan as json")
+	projectExtensionApplyCmd.Flags().String("file", "", "Read the desired state from this file instead of deployment.extension-management")
+}
+
+// localExtensionDependencies reads the dependencies between the extensions
+// from the project, the shop does not expose them.
+func localExtensionDependencies(cmd *cobra.Command) map[string][]string {
+	projectRoot, err := findClosestShopwareProject()
+	if err != nil {
+		logging.FromContext(cmd.Context()).Debugf("No project found, applying the extensions without dependency order: %v", err)
+		return nil
+	}
+
+	return extension.Dependencies(extension.FindExtensionsFromProject(logging.DisableLogger(cmd.Context()), projectRoot, false))
+}
+
+func printExtensionStatePlan(plan *shop.ExtensionStatePlan) {
+	if len(plan.Actions) == 0 {
+		fmt.Println("The extensions are up to date")
+		return
+	}
+
+	t := liplogtable.New().
+		Border(lipgloss.NormalBorder()).
+		Headers("#", "Extension", "Type", "Action")
+
+	for i, action := range plan.Actions {
+		t.Row(fmt.Sprintf("%d", i+1), action.Extension, action.Type, string(action.Action))
+	}
+
+	fmt.Println(t.Render())
+}
@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/shopware/shopware-cli/internal/verifier"
 )
@@ -50,8 +49,6 @@ var projectFixCmd = &cobra.Command{
 			return err
 		}
 
-		var gr errgroup.Group
-
 		tools := verifier.GetTools()
 
 		tools, err = tools.Only(only)
@@ -59,14 +56,7 @@ var projectFixCmd = &cobra.Command{
 			return err
 		}
 
-		for _, tool := range tools {
-			tool := tool
-			gr.Go(func() error {
-				return tool.Fix(cmd.Context(), *toolCfg)
-			})
-		}
-
-		return gr.Wait()
+		return tools.Fix(cmd.Context(), *toolCfg)
 	},
 }
 
@@ -5,7 +5,6 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/shopware/shopware-cli/internal/verifier"
 )
@@ -42,8 +41,6 @@ var projectFormatCmd = &cobra.Command{
 			return err
 		}
 
-		var gr errgroup.Group
-
 		tools := verifier.GetTools()
 
 		tools, err = tools.Only(only)
@@ -51,14 +48,7 @@ var projectFormatCmd = &cobra.Command{
 			return err
 		}
 
-		for _, tool := range tools {
-			tool := tool
-			gr.Go(func() error {
-				return tool.Format(cmd.Context(), *toolCfg, dryRun)
-			})
-		}
-
-		return gr.Wait()
+		return tools.Format(cmd.Context(), *toolCfg, dryRun)
 	},
 }
 
@@ -0,0 +1,12 @@
+package project
+
+import "github.com/spf13/cobra"
+
+var projectImageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Build container images of the Shopware project",
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectImageCmd)
+}
@@ -0,0 +1,363 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/oci"
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/internal/tui"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const projectImageDefaultBase = "ghcr.io/shopware/docker-base:%s-caddy"
+
+// projectImageDefaultExcludes are never part of the image, they are either
+// secrets, caches or only needed while building.
+var projectImageDefaultExcludes = []string{
+	".git",
+	".github",
+	".shopware-ci",
+	".cache",
+	".gitlab-ci.yml",
+	".env.local",
+	"auth.json",
+	"node_modules",
+	"**/node_modules",
+	"var/cache",
+	"var/log",
+	"files/theme-config",
+}
+
+var projectImageBuildCmd = &cobra.Command{
+	Use:   "build [path]",
+	Short: "Build an OCI image of the project without a Docker daemon",
+	Long: `Build an OCI image from a project prepared by project ci.
+
+The project files are added on top of a Shopware base image in three layers:
+vendor, public and the remaining code. All files get the same owner and
+modification time, so a layer only changes when its content changes and
+rebuilding an unchanged tree results in the same image digest. The timestamp
+is taken from SOURCE_DATE_EPOCH and defaults to the unix epoch.
+
+The base image defaults to ghcr.io/shopware/docker-base with the highest PHP
+version allowed by shopware/core in composer.lock. A CycloneDX SBOM of
+composer.lock is attached as in-toto attestation.
+
+The image is written as OCI image layout tarball which can be loaded with
+"docker load" or pushed with tools like skopeo or crane.
+
+Examples:
+  shopware-cli project ci .
+  shopware-cli project image build . --tag ghcr.io/acme/shop:1.0.0 --output shop.tar
+  skopeo copy oci-archive:shop.tar docker://ghcr.io/acme/shop:1.0.0`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, err := resolveProjectSbomRoot(args)
+		if err != nil {
+			return err
+		}
+
+		base, _ := cmd.Flags().GetString("base")
+		phpVersion, _ := cmd.Flags().GetString("php-version")
+		platformFlag, _ := cmd.Flags().GetString("platform")
+		tag, _ := cmd.Flags().GetString("tag")
+		output, _ := cmd.Flags().GetString("output")
+		owner, _ := cmd.Flags().GetString("owner")
+		excludes, _ := cmd.Flags().GetStringSlice("exclude")
+		labels, _ := cmd.Flags().GetStringToString("label")
+		withSBOM, _ := cmd.Flags().GetBool("sbom")
+
+		if base == "" {
+			base = projectImageBaseImage(root, phpVersion)
+		}
+
+		ref, err := oci.ParseReference(base)
+		if err != nil {
+			return err
+		}
+
+		platform, err := oci.ParsePlatform(platformFlag)
+		if err != nil {
+			return err
+		}
+
+		uid, gid, err := parseImageOwner(owner)
+		if err != nil {
+			return err
+		}
+
+		if tag != "" {
+			if _, err := oci.ParseReference(tag); err != nil {
+				return err
+			}
+		}
+
+		modTime, err := sourceDateEpoch()
+		if err != nil {
+			return err
+		}
+
+		baseStore, err := oci.NewBlobStore(filepath.Join(system.GetShopwareCliCacheDir(), "oci"))
+		if err != nil {
+			return err
+		}
+
+		buildDir, err := os.MkdirTemp("", "shopware-cli-image-")
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = os.RemoveAll(buildDir)
+		}()
+
+		store, err := oci.NewBlobStore(filepath.Join(buildDir, "blobs"))
+		if err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Pulling base image %s for %s", ref, platform)
+
+		baseImage, err := oci.NewRegistry().Pull(cmd.Context(), ref, platform, baseStore)
+		if err != nil {
+			return fmt.Errorf("pull base image: %w", err)
+		}
+
+		var sbom []byte
+		if withSBOM {
+			sbom, err = projectImageSBOM(cmd, root, buildDir)
+			if err != nil {
+				return err
+			}
+		}
+
+		excludes = append(slices.Clone(projectImageDefaultExcludes), excludes...)
+
+		if outputExclude := projectImageOutputExclude(root, output); outputExclude != "" {
+			excludes = append(excludes, outputExclude)
+		}
+
+		layers, err := projectImageLayers(root, excludes)
+		if err != nil {
+			return err
+		}
+
+		imageLabels := map[string]string{
+			oci.AnnotationCreated:  modTime.UTC().Format(time.RFC3339),
+			oci.AnnotationBaseName: ref.String(),
+		}
+
+		for key, value := range labels {
+			imageLabels[key] = value
+		}
+
+		result, err := oci.Build(cmd.Context(), oci.BuildOptions{
+			Base:      baseImage,
+			BaseStore: baseStore,
+			Store:     store,
+			Root:      root,
+			Layers:    layers,
+			LayerOptions: oci.LayerOptions{
+				Prefix:  "var/www/html",
+				UID:     uid,
+				GID:     gid,
+				ModTime: modTime,
+				Exclude: projectImageExcludeMatcher(excludes),
+			},
+			Labels: imageLabels,
+			Name:   tag,
+			SBOM:   sbom,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, layer := range result.Layers {
+			logging.FromContext(cmd.Context()).Infof("Layer %s: %d files, %s", layer.Name, layer.Files, layer.Descriptor.Digest)
+		}
+
+		file, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+
+		if err := result.WriteLayout(file); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("write %s: %w", output, err)
+		}
+
+		if err := file.Close(); err != nil {
+			return err
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Wrote image %s to %s", result.ImageDigest, output)
+
+		return nil
+	},
+}
+
+func init() {
+	projectImageCmd.AddCommand(projectImageBuildCmd)
+	projectImageBuildCmd.Flags().String("base", "", "Base image (default: ghcr.io/shopware/docker-base matching the PHP requirement of composer.lock)")
+	projectImageBuildCmd.Flags().String("php-version", "", "PHP version of the default base image, e.g. 8.3")
+	projectImageBuildCmd.Flags().String("platform", "linux/amd64", "Platform of the image as os/arch[/variant]")
+	projectImageBuildCmd.Flags().StringP("tag", "t", "", "Image name and tag stored in the OCI layout, e.g. ghcr.io/acme/shop:1.0.0")
+	projectImageBuildCmd.Flags().StringP("output", "o", "image.tar", "Path of the OCI image layout tarball")
+	projectImageBuildCmd.Flags().String("owner", "82:82", "uid:gid owning the project files (82 is www-data in the Alpine based images)")
+	projectImageBuildCmd.Flags().StringSlice("exclude", nil, "Additional paths or glob patterns relative to the project root to leave out of the image")
+	projectImageBuildCmd.Flags().StringToString("label", nil, "Additional image labels as key=value")
+	projectImageBuildCmd.Flags().Bool("sbom", true, "Attach a CycloneDX SBOM of composer.lock as attestation")
+}
+
+// projectImageBaseImage picks the docker-base tag for the requested PHP version,
+// or the highest version allowed by shopware/core in composer.lock.
+func projectImageBaseImage(root, phpVersion string) string {
+	if phpVersion == "" {
+		phpVersion = projectPHPVersion(root)
+	}
+
+	if phpVersion == "" {
+		phpVersion = shop.SupportedPHPVersions[len(shop.SupportedPHPVersions)-1]
+	}
+
+	return fmt.Sprintf(projectImageDefaultBase, phpVersion)
+}
+
+// projectImageLayers splits the top level entries of the project into the
+// vendor, public and code layers, ordered from least to most frequently changing.
+func projectImageLayers(root string, excludes []string) ([]oci.LayerSpec, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := projectImageExcludeMatcher(excludes)
+	vendor := oci.LayerSpec{Name: "vendor"}
+	public := oci.LayerSpec{Name: "public"}
+	code := oci.LayerSpec{Name: "code"}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if exclude(name) {
+			continue
+		}
+
+		switch name {
+		case "vendor":
+			vendor.Paths = append(vendor.Paths, name)
+		case "public":
+			public.Paths = append(public.Paths, name)
+		default:
+			code.Paths = append(code.Paths, name)
+		}
+	}
+
+	return []oci.LayerSpec{vendor, public, code}, nil
+}
+
+// projectImageOutputExclude returns the output path relative to the project
+// root, so a previous build is not added to the next image.
+func projectImageOutputExclude(root, output string) string {
+	absOutput, err := filepath.Abs(output)
+	if err != nil {
+		return ""
+	}
+
+	rel, err := filepath.Rel(root, absOutput)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// projectImageExcludeMatcher matches slash separated paths against the patterns.
+// A pattern matches the path itself and everything below it.
+func projectImageExcludeMatcher(patterns []string) func(rel string) bool {
+	return func(rel string) bool {
+		for _, pattern := range patterns {
+			pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+
+			if rel == pattern || strings.HasPrefix(rel, pattern+"/") {
+				return true
+			}
+
+			if matched, _ := path.Match(pattern, rel); matched {
+				return true
+			}
+
+			if rest, found := strings.CutPrefix(pattern, "**/"); found {
+				if matched, _ := path.Match(rest, path.Base(rel)); matched {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+}
+
+func parseImageOwner(owner string) (int, int, error) {
+	uidValue, gidValue, found := strings.Cut(owner, ":")
+	if !found {
+		gidValue = uidValue
+	}
+
+	uid, err := strconv.Atoi(uidValue)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid owner %q, expected uid:gid", owner)
+	}
+
+	gid, err := strconv.Atoi(gidValue)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid owner %q, expected uid:gid", owner)
+	}
+
+	return uid, gid, nil
+}
+
+// sourceDateEpoch returns the timestamp of SOURCE_DATE_EPOCH, see https://reproducible-builds.org/specs/source-date-epoch/
+func sourceDateEpoch() (time.Time, error) {
+	value := os.Getenv("SOURCE_DATE_EPOCH")
+	if value == "" {
+		return time.Unix(0, 0).UTC(), nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %w", value, err)
+	}
+
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+func projectImageSBOM(cmd *cobra.Command, root, buildDir string) ([]byte, error) {
+	sbomPath := filepath.Join(buildDir, shop.DefaultProjectSBOMOutput)
+
+	if err := shop.WriteProjectSBOM(cmd.Context(), root, shop.ProjectSBOMOptions{
+		OutputPath:      sbomPath,
+		SkipMissingLock: true,
+		ToolVersion:     tui.AppVersion,
+	}); err != nil {
+		return nil, fmt.Errorf("generate SBOM: %w", err)
+	}
+
+	data, err := os.ReadFile(sbomPath)
+	if os.IsNotExist(err) {
+		logging.FromContext(cmd.Context()).Warnf("No composer.lock found, building the image without SBOM")
+		return nil, nil
+	}
+
+	return data, err
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/oci"
+)
+
+func TestProjectImageLayers(t *testing.T) {
+	root := t.TempDir()
+
+	for _, dir := range []string{"vendor", "public", "src", "config", "node_modules", ".git", "var/cache"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), os.ModePerm))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "composer.json"), []byte("{}"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "auth.json"), []byte("{}"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "image.tar"), []byte("previous build"), 0o644))
+
+	excludes := append(slices.Clone(projectImageDefaultExcludes), "config", projectImageOutputExclude(root, filepath.Join(root, "image.tar")))
+
+	layers, err := projectImageLayers(root, excludes)
+	require.NoError(t, err)
+
+	assert.Equal(t, []oci.LayerSpec{
+		{Name: "vendor", Paths: []string{"vendor"}},
+		{Name: "public", Paths: []string{"public"}},
+		{Name: "code", Paths: []string{"composer.json", "src", "var"}},
+	}, layers)
+}
+
+func TestProjectImageExcludeMatcher(t *testing.T) {
+	exclude := projectImageExcludeMatcher(projectImageDefaultExcludes)
+
+	assert.True(t, exclude("var/cache"))
+	assert.True(t, exclude("var/cache/prod/file.php"))
+	assert.True(t, exclude("custom/plugins/Foo/node_modules"))
+	assert.False(t, exclude("var/cache-warmup"))
+	assert.False(t, exclude("custom/plugins/Foo/src"))
+	assert.False(t, exclude("image.tar"), "only the actual output path is excluded")
+}
+
+func TestProjectImageOutputExclude(t *testing.T) {
+	root := t.TempDir()
+
+	assert.Equal(t, "build/shop.tar", projectImageOutputExclude(root, filepath.Join(root, "build", "shop.tar")))
+	assert.Equal(t, "", projectImageOutputExclude(root, filepath.Join(filepath.Dir(root), "shop.tar")))
+	assert.Equal(t, "", projectImageOutputExclude(root, root))
+}
+
+func TestProjectImageBaseImage(t *testing.T) {
+	root := t.TempDir()
+
+	assert.Equal(t, "ghcr.io/shopware/docker-base:8.3-caddy", projectImageBaseImage(root, "8.3"))
+
+	lock := `{"packages":[{"name":"shopware/core","version":"6.6.0.0","require":{"php":"~8.2.0 || ~8.3.0"}}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(root, "composer.lock"), []byte(lock), 0o644))
+
+	assert.Equal(t, "ghcr.io/shopware/docker-base:8.3-caddy", projectImageBaseImage(root, ""))
+}
+
+func TestParseImageOwner(t *testing.T) {
+	uid, gid, err := parseImageOwner("82:83")
+	require.NoError(t, err)
+	assert.Equal(t, 82, uid)
+	assert.Equal(t, 83, gid)
+
+	uid, gid, err = parseImageOwner("1000")
+	require.NoError(t, err)
+	assert.Equal(t, 1000, uid)
+	assert.Equal(t, 1000, gid)
+
+	_, _, err = parseImageOwner("www-data")
+	assert.Error(t, err)
+}
+
+func TestSourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+
+	modTime, err := sourceDateEpoch()
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(0, 0).UTC(), modTime)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	modTime, err = sourceDateEpoch()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000), modTime.Unix())
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+
+	_, err = sourceDateEpoch()
+	assert.Error(t, err)
+}
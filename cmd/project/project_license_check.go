@@ -0,0 +1,172 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shyim/go-spdx"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/license"
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectLicenseCheckCmd = &cobra.Command{
+	Use:   "license-check [path]",
+	Short: "Check the licenses of the shipped composer and npm packages",
+	Long: `Check the licenses of the packages shipped with the shop against the
+license_policy of the project config. The packages are read from composer.lock,
+the vendor directories bundled by the extensions in custom/plugins and
+custom/static-plugins and the package-lock.json files of the project and of
+the administration and storefront of its extensions. Dev dependencies are
+skipped unless license_policy.include_dev is set.
+
+License expressions like "MIT OR GPL-3.0-only" are evaluated, a package passes
+when one of its alternatives is allowed. The licenses of a composer package
+are alternatives as well.
+
+  license_policy:
+    allow: [MIT, BSD-*, Apache-2.0, ISC]
+    deny: [AGPL-*]
+    exceptions:
+      - package: acme/internal-sdk
+        reason: Licensed to us by contract
+
+Pass --notice to write an attribution file with the license texts of the
+installed packages.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reportingFormat, _ := cmd.Flags().GetString("reporter")
+		noticeFile, _ := cmd.Flags().GetString("notice")
+
+		var projectRoot string
+		var err error
+
+		if len(args) > 0 {
+			projectRoot, err = filepath.Abs(args[0])
+		} else {
+			projectRoot, err = findClosestShopwareProject()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		shopCfg, err := shop.ReadConfig(cmd.Context(), projectConfigPath, true)
+		if err != nil {
+			return err
+		}
+
+		if reportingFormat == "" {
+			reportingFormat = validation.DetectDefaultReporter()
+		}
+
+		return runProjectLicenseCheck(cmd.Context(), projectRoot, shopCfg, noticeFile, reportingFormat)
+	},
+}
+
+// runProjectLicenseCheck evaluates the license policy, writes the notice file and reports the violations.
+func runProjectLicenseCheck(ctx context.Context, projectRoot string, shopCfg *shop.Config, noticeFile string, reportingFormat string) error {
+	includeDev := shopCfg.LicensePolicy != nil && shopCfg.LicensePolicy.IncludeDev
+
+	packages, err := license.CollectPackages(ctx, projectRoot, includeDev)
+	if err != nil {
+		return err
+	}
+
+	if shopCfg.LicensePolicy == nil {
+		logging.FromContext(ctx).Warnf("No license_policy is configured, every license is allowed")
+	}
+
+	violations := license.NewPolicy(shopCfg.LicensePolicy).Evaluate(packages)
+
+	logging.FromContext(ctx).Infof("Checked %d packages, found %d license violations", len(packages), len(violations))
+
+	if noticeFile != "" {
+		f, err := os.Create(noticeFile)
+		if err != nil {
+			return fmt.Errorf("create notice file: %w", err)
+		}
+
+		if err := license.WriteNotice(f, packages); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("write notice file: %w", err)
+		}
+
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		logging.FromContext(ctx).Infof("Wrote the attribution notice to %s", noticeFile)
+	}
+
+	check := verifier.NewCheck()
+
+	for _, result := range validateLicensePolicy(shopCfg.LicensePolicy) {
+		check.AddResult(result)
+	}
+
+	for _, violation := range violations {
+		check.AddResult(violation.CheckResult())
+	}
+
+	return validation.DoCheckReport(check, reportingFormat)
+}
+
+// validateLicensePolicy warns about licenses of the policy which are no SPDX identifiers, they would never match.
+func validateLicensePolicy(policy *shop.ConfigLicensePolicy) []validation.CheckResult {
+	if policy == nil {
+		return nil
+	}
+
+	spdxList, err := spdx.NewSpdxLicenses()
+	if err != nil {
+		return []validation.CheckResult{{
+			Path:       projectConfigPath,
+			Identifier: "license-policy/config",
+			Message:    fmt.Sprintf("Could not load the SPDX license list: %s", err.Error()),
+			Severity:   validation.SeverityWarning,
+		}}
+	}
+
+	licenses := append(append([]string{}, policy.Allow...), policy.Deny...)
+
+	for _, exception := range policy.Exceptions {
+		if exception.License != "" {
+			licenses = append(licenses, exception.License)
+		}
+	}
+
+	var results []validation.CheckResult
+
+	for _, configured := range licenses {
+		if strings.HasSuffix(configured, "*") || strings.EqualFold(configured, "proprietary") {
+			continue
+		}
+
+		if valid, err := spdxList.Validate(configured); err == nil && valid {
+			continue
+		}
+
+		results = append(results, validation.CheckResult{
+			Path:       projectConfigPath,
+			Identifier: "license-policy/config",
+			Message:    fmt.Sprintf("The license %q of the license_policy is no SPDX license identifier", configured),
+			Severity:   validation.SeverityWarning,
+		})
+	}
+
+	return results
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectLicenseCheckCmd)
+	projectLicenseCheckCmd.Flags().String("reporter", "", "Reporting format (summary, json, github, gitlab, junit, markdown, sarif)")
+	projectLicenseCheckCmd.Flags().String("notice", "", "Write an attribution notice of the shipped packages to this file")
+}
@@ -0,0 +1,49 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/shop"
+)
+
+func TestRunProjectLicenseCheck(t *testing.T) {
+	root := t.TempDir()
+	notice := filepath.Join(root, "NOTICE")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package-lock.json"), []byte(`{
+		"lockfileVersion": 3,
+		"packages": {
+			"node_modules/lodash": {"version": "4.17.21", "license": "MIT"},
+			"node_modules/copyleft": {"version": "1.0.0", "license": "AGPL-3.0-only"}
+		}
+	}`), 0o644))
+
+	shopCfg := &shop.Config{LicensePolicy: &shop.ConfigLicensePolicy{Allow: []string{"MIT"}, Deny: []string{"AGPL-*"}}}
+
+	assert.ErrorContains(t, runProjectLicenseCheck(t.Context(), root, shopCfg, notice, "json"), "found errors")
+
+	content, err := os.ReadFile(notice)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "lodash 4.17.21 (npm)")
+
+	shopCfg.LicensePolicy.Exceptions = []shop.ConfigLicensePolicyException{{Package: "copyleft", Reason: "Only used for the build"}}
+
+	assert.NoError(t, runProjectLicenseCheck(t.Context(), root, shopCfg, "", "json"))
+}
+
+func TestValidateLicensePolicy(t *testing.T) {
+	results := validateLicensePolicy(&shop.ConfigLicensePolicy{
+		Allow:      []string{"MIT", "BSD-*", "proprietary", "MIT-ish"},
+		Exceptions: []shop.ConfigLicensePolicyException{{Package: "acme/sdk", License: "Apache-2.0"}},
+	})
+
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0].Message, `"MIT-ish"`)
+
+	assert.Empty(t, validateLicensePolicy(nil))
+}
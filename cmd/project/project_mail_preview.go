@@ -0,0 +1,69 @@
+package project
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+	"github.com/shopware/shopware-cli/internal/mailpreview"
+	"github.com/shopware/shopware-cli/internal/mjml"
+	"github.com/shopware/shopware-cli/internal/shop"
+)
+
+var projectMailPreviewCmd = &cobra.Command{
+	Use:   "mail-preview",
+	Short: "Preview the mail templates of the project with sample data",
+	Long: `Serve a live-reloading gallery of all mail templates in the MJML search paths (build.mjml.search_paths,
+custom/plugins and custom/static-plugins by default).
+
+A mail template is a directory named after the technical name of the mail template type containing
+html.mjml or html.twig, plain.twig and subject.twig, optionally in a locale directory like
+order_confirmation_mail/de-DE. MJML is compiled on every change with the include settings of
+build.mjml and the Twig placeholders are rendered with sample data of the mail type. The sample
+data can be extended with a fixture.json next to the template or <type>.json files in the
+--fixtures directories.
+
+The push button writes the compiled templates to the mail templates of the shop configured in
+admin_api. Use --push to push all templates without starting the server.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		fixtureDirs, _ := cmd.Flags().GetStringSlice("fixtures")
+		pushOnly, _ := cmd.Flags().GetBool("push")
+
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := shop.ReadConfig(cmd.Context(), projectConfigPath, true)
+		if err != nil {
+			return err
+		}
+
+		mjmlCfg := shop.ConfigBuildMJML{}
+		if cfg.Build != nil && cfg.Build.MJML != nil {
+			mjmlCfg = *cfg.Build.MJML
+		}
+
+		extraIncludePaths := mjmlCfg.ResolveIncludePaths(projectRoot)
+
+		renderer := mailpreview.Renderer{
+			FixtureDirs: fixtureDirs,
+			MJMLOptions: func(searchPath string) mjml.CompileOptions {
+				return mjml.NewCompileOptions(searchPath, mjmlCfg.AllowIncludes, extraIncludePaths)
+			},
+		}
+
+		return mailpreview.Run(cmd.Context(), mjmlCfg.GetPaths(projectRoot), renderer, listen, pushOnly, func(ctx context.Context) (*adminSdk.Client, error) {
+			return shop.NewShopClient(ctx, cfg)
+		})
+	},
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectMailPreviewCmd)
+	projectMailPreviewCmd.Flags().String("listen", "127.0.0.1:8025", "Address to serve the preview on")
+	projectMailPreviewCmd.Flags().StringSlice("fixtures", nil, "Directories with <mail type>.json sample data")
+	projectMailPreviewCmd.Flags().Bool("push", false, "Push all templates to the shop and exit")
+}
@@ -0,0 +1,388 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shyim/go-composer"
+	"github.com/spf13/cobra"
+
+	"github.com/shopware/shopware-cli/internal/executor"
+	"github.com/shopware/shopware-cli/internal/git"
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/internal/upgrade"
+	"github.com/shopware/shopware-cli/internal/verifier"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+var projectUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Plan and run the upgrade of the project to another Shopware version",
+}
+
+var projectUpgradePlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Shows the composer, extension, PHP and template changes of the upgrade",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		format, _ := cmd.Flags().GetString("format")
+
+		if err := validateUpgradeFormat(format); err != nil {
+			return err
+		}
+
+		projectRoot, cfg, err := readUpgradeProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		plan, err := buildUpgradePlan(cmd, projectRoot, cfg, to)
+		if err != nil {
+			return err
+		}
+
+		if plan == nil {
+			fmt.Println("You are on the latest version of Shopware")
+			return nil
+		}
+
+		if format == "json" {
+			return writeUpgradeJSON(cmd.OutOrStdout(), plan)
+		}
+
+		return plan.WriteMarkdown(cmd.OutOrStdout())
+	},
+}
+
+var projectUpgradeRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Applies the composer changes of the plan in a new git branch and runs composer update",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		branch, _ := cmd.Flags().GetString("branch")
+		force, _ := cmd.Flags().GetBool("force")
+
+		projectRoot, cfg, err := readUpgradeProject(cmd)
+		if err != nil {
+			return err
+		}
+
+		dirty, isRepo, err := git.IsWorkingTreeDirty(cmd.Context(), projectRoot)
+		if err != nil {
+			return err
+		}
+
+		if !isRepo {
+			return fmt.Errorf("the project has to be a git repository, the upgrade is applied in a new branch")
+		}
+
+		if dirty {
+			return fmt.Errorf("the working tree has uncommitted changes, commit or stash them before upgrading")
+		}
+
+		plan, err := buildUpgradePlan(cmd, projectRoot, cfg, to)
+		if err != nil {
+			return err
+		}
+
+		if plan == nil {
+			fmt.Println("You are on the latest version of Shopware")
+			return nil
+		}
+
+		if plan.HasBlockers() && !force {
+			if err := plan.WriteMarkdown(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+
+			return fmt.Errorf("the upgrade is blocked, resolve the blockers or use --force")
+		}
+
+		if branch == "" {
+			branch = "shopware-upgrade-" + plan.TargetVersion
+		}
+
+		if err := git.CreateBranch(cmd.Context(), projectRoot, branch); err != nil {
+			return err
+		}
+
+		report := &upgrade.Report{
+			From:   plan.CurrentVersion,
+			To:     plan.TargetVersion,
+			Branch: branch,
+			Time:   time.Now(),
+		}
+
+		runErr := runUpgrade(cmd, projectRoot, cfg, plan, report)
+		if runErr != nil {
+			report.Error = runErr.Error()
+		}
+
+		if err := upgrade.WriteReport(projectRoot, report); err != nil {
+			return err
+		}
+
+		if err := report.WriteMarkdown(cmd.OutOrStdout()); err != nil {
+			return err
+		}
+
+		if runErr != nil {
+			return runErr
+		}
+
+		logging.FromContext(cmd.Context()).Infof("Committed the upgrade to branch %s", branch)
+
+		return nil
+	},
+}
+
+var projectUpgradeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the report of the last upgrade run",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		format, _ := cmd.Flags().GetString("format")
+
+		if err := validateUpgradeFormat(format); err != nil {
+			return err
+		}
+
+		projectRoot, err := findClosestShopwareProject()
+		if err != nil {
+			return err
+		}
+
+		report, err := upgrade.ReadReport(projectRoot)
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			return writeUpgradeJSON(cmd.OutOrStdout(), report)
+		}
+
+		return report.WriteMarkdown(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	projectRootCmd.AddCommand(projectUpgradeCmd)
+	projectUpgradeCmd.AddCommand(projectUpgradePlanCmd)
+	projectUpgradeCmd.AddCommand(projectUpgradeRunCmd)
+	projectUpgradeCmd.AddCommand(projectUpgradeStatusCmd)
+
+	projectUpgradePlanCmd.Flags().String("to", "", "Shopware version to upgrade to, asks when empty")
+	projectUpgradePlanCmd.Flags().String("format", "markdown", "Output format (markdown, json)")
+
+	projectUpgradeRunCmd.Flags().String("to", "", "Shopware version to upgrade to, asks when empty")
+	projectUpgradeRunCmd.Flags().String("branch", "", "Git branch to apply the upgrade in (default \"shopware-upgrade-<version>\")")
+	projectUpgradeRunCmd.Flags().Bool("force", false, "Run the upgrade even when extensions or the PHP version block it")
+
+	projectUpgradeStatusCmd.Flags().String("format", "markdown", "Output format (markdown, json)")
+}
+
+func validateUpgradeFormat(format string) error {
+	if format != "markdown" && format != "json" {
+		return fmt.Errorf("unsupported format %q, use markdown or json", format)
+	}
+
+	return nil
+}
+
+func writeUpgradeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+func readUpgradeProject(cmd *cobra.Command) (string, *shop.Config, error) {
+	projectRoot, err := findClosestShopwareProject()
+	if err != nil {
+		return "", nil, err
+	}
+
+	cfg, err := shop.ReadConfig(cmd.Context(), projectConfigPath, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return projectRoot, cfg, nil
+}
+
+// buildUpgradePlan collects the changes of the upgrade to the target version.
+// Without target version it is selected like in upgrade-check, nil is
+// returned when the project is on the latest version.
+func buildUpgradePlan(cmd *cobra.Command, projectRoot string, cfg *shop.Config, to string) (*upgrade.Plan, error) {
+	ctx := cmd.Context()
+
+	shopwareVersion, extensions, err := lookupInstalledExtensions(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == "" {
+		if to, err = selectUpgradeVersion(ctx, shopwareVersion); err != nil {
+			return nil, err
+		}
+
+		if to == "" {
+			return nil, nil
+		}
+	}
+
+	composerJson, err := composer.ReadJson(filepath.Join(projectRoot, "composer.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read composer.json: %w", err)
+	}
+
+	plan := &upgrade.Plan{
+		CurrentVersion: shopwareVersion.String(),
+		TargetVersion:  to,
+		Composer:       upgrade.ComposerConstraintChanges(composerJson.Require, to),
+	}
+
+	if plan.Recipes, err = upgrade.ReadRecipes(projectRoot); err != nil {
+		return nil, err
+	}
+
+	updates, err := extensionCompatibility(ctx, plan.CurrentVersion, to, extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, update := range updates {
+		plan.Extensions = append(plan.Extensions, upgrade.ExtensionUpdate{
+			Name:    update.Name,
+			Version: extensions[update.Name],
+			Status:  update.Status.Label,
+			Blocker: update.Status.IsBlocker(),
+		})
+	}
+
+	var currentPHP *shop.PHPConstraint
+	if lock, err := composer.ReadLock(filepath.Join(projectRoot, "composer.lock")); err == nil && lock != nil {
+		currentPHP = shop.ShopwarePHPConstraint(lock)
+	}
+
+	targetPHP, err := shop.GetPHPConstraintForShopwareVersion(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+
+	configuredPHP := ""
+	if cfg.Docker != nil && cfg.Docker.PHP != nil {
+		configuredPHP = cfg.Docker.PHP.Version
+	}
+
+	plan.PHP = upgrade.NewPHPRequirement(currentPHP, targetPHP, configuredPHP)
+
+	toolCfg, err := verifier.GetConfigFromProject(projectRoot, true)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Skipping the deprecated template check: %v", err)
+		return plan, nil
+	}
+
+	toolCfg.TemplateIndexVersion = to
+
+	tools, err := verifier.GetTools().Only("deprecated-blocks")
+	if err != nil {
+		return nil, err
+	}
+
+	check := verifier.NewCheck()
+	if err := tools.Check(ctx, check, *toolCfg); err != nil {
+		return nil, err
+	}
+
+	plan.DeprecatedTemplates = check.GetResults()
+
+	return plan, nil
+}
+
+// runUpgrade changes the constraints, runs composer update and commits the
+// result. The package results are recorded in the report.
+func runUpgrade(cmd *cobra.Command, projectRoot string, cfg *shop.Config, plan *upgrade.Plan, report *upgrade.Report) error {
+	ctx := cmd.Context()
+
+	lock, err := composer.ReadLock(filepath.Join(projectRoot, "composer.lock"))
+	if err != nil {
+		return fmt.Errorf("failed to read composer.lock: %w", err)
+	}
+
+	before := upgrade.LockedVersions(lock)
+
+	composerJson, err := composer.ReadJson(filepath.Join(projectRoot, "composer.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read composer.json: %w", err)
+	}
+
+	snapshot, err := upgrade.SnapshotFiles(filepath.Join(projectRoot, "composer.json"), filepath.Join(projectRoot, "composer.lock"))
+	if err != nil {
+		return err
+	}
+
+	// a failed upgrade leaves the branch with the composer files of before
+	restore := func(err error) error {
+		if restoreErr := snapshot.Restore(); restoreErr != nil {
+			return fmt.Errorf("%w, restoring composer.json and composer.lock failed: %v", err, restoreErr)
+		}
+
+		return fmt.Errorf("%w, composer.json and composer.lock were restored", err)
+	}
+
+	planned := upgrade.UpdatePackages(composerJson.Require)
+
+	upgrade.ApplyConstraintChanges(composerJson.Require, plan.Composer)
+
+	if err := composerJson.Save(); err != nil {
+		return restore(err)
+	}
+
+	envCfg, err := cfg.ResolveEnvironment(environmentName)
+	if err != nil {
+		return restore(err)
+	}
+
+	cmdExecutor, err := executor.New(projectRoot, envCfg, cfg)
+	if err != nil {
+		return restore(err)
+	}
+
+	args := append([]string{"update"}, planned...)
+	args = append(args, "--with-all-dependencies", "--no-interaction")
+
+	composerUpdate := cmdExecutor.ComposerCommand(ctx, args...)
+	composerUpdate.Cmd.Stdout = os.Stdout
+	composerUpdate.Cmd.Stderr = os.Stderr
+
+	if err := composerUpdate.Run(); err != nil {
+		err = fmt.Errorf("composer update failed: %w", err)
+		report.Packages = upgrade.FailedPackages(before, planned, err)
+
+		return restore(err)
+	}
+
+	lock, err = composer.ReadLock(filepath.Join(projectRoot, "composer.lock"))
+	if err != nil {
+		return fmt.Errorf("failed to read composer.lock: %w", err)
+	}
+
+	skipped := map[string]string{}
+	for _, ext := range plan.Extensions {
+		if ext.Blocker {
+			skipped[ext.Name] = fmt.Sprintf("not compatible with Shopware %s: %s", plan.TargetVersion, ext.Status)
+		}
+	}
+
+	for _, recipe := range plan.Recipes {
+		skipped[recipe.Package+" (recipe)"] = "review with composer recipes:update"
+	}
+
+	report.Packages = upgrade.ComparePackages(before, upgrade.LockedVersions(lock), planned, skipped)
+
+	return git.Commit(ctx, projectRoot, fmt.Sprintf("Upgrade Shopware to %s", plan.TargetVersion), ".")
+}
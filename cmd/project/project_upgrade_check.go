@@ -28,129 +28,31 @@ var projectUpgradeCheckCmd = &cobra.Command{
 	Use:   "upgrade-check",
 	Short: "Check that installed extensions are compatible with a future Shopware version",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var cfg *shop.Config
-		var err error
-		var shopwareVersion *version.Version
-		var extensions map[string]string
-
-		if cfg, err = shop.ReadConfig(cmd.Context(), projectConfigPath, true); err != nil {
+		cfg, err := shop.ReadConfig(cmd.Context(), projectConfigPath, true)
+		if err != nil {
 			return err
 		}
 
-		if cfg.IsAdminAPIConfigured() {
-			logging.FromContext(cmd.Context()).Debugf("Using Shopware Admin API to lookup for available extensions")
-			client, err := shop.NewShopClient(cmd.Context(), cfg)
-			if err != nil {
-				return err
-			}
-
-			remoteExtensions, _, err := client.ExtensionManager.ListAvailableExtensions(adminSdk.NewApiContext(cmd.Context()))
-
-			if err != nil {
-				return fmt.Errorf("failed to list available extensions: %w", err)
-			}
-
-			extensions = make(map[string]string, 0)
-
-			for _, ext := range remoteExtensions {
-				extensions[ext.Name] = ext.Version
-			}
-
-			shopwareVersion = client.ShopwareVersion
-		} else {
-			logging.FromContext(cmd.Context()).Debugf("Using local composer.lock to lookup for available extensions")
-			shopwareVersion, extensions, err = getLocalExtensions()
-
-			if err != nil {
-				return fmt.Errorf("failed to get local extensions: %w", err)
-			}
-		}
-
-		versions, err := extension.GetShopwareVersions(cmd.Context())
+		shopwareVersion, extensions, err := lookupInstalledExtensions(cmd.Context(), cfg)
 		if err != nil {
 			return err
 		}
 
-		var possibleVersions []string
-
-		for _, v := range versions {
-			ver, err := version.NewVersion(v)
-			if err != nil {
-				continue
-			}
-
-			if strings.Contains(v, "RC") {
-				continue
-			}
-
-			if ver.LessThan(shopwareVersion) {
-				continue
-			}
-
-			possibleVersions = append(possibleVersions, v)
+		selectedVersion, err := selectUpgradeVersion(cmd.Context(), shopwareVersion)
+		if err != nil {
+			return err
 		}
 
-		if len(possibleVersions) == 0 {
+		if selectedVersion == "" {
 			fmt.Println("You are on the latest version of Shopware")
 			return nil
 		}
 
-		var selectedVersion string
-
-		if !system.IsInteractionEnabled(cmd.Context()) {
-			selectedVersion = possibleVersions[0]
-			logging.FromContext(cmd.Context()).Infof("Auto selected version %s", selectedVersion)
-		} else {
-			prompt := huh.NewSelect[string]().
-				Height(10).
-				Title("Select a Shopware version to check compatibility").
-				Options(
-					huh.NewOptions(possibleVersions...)...,
-				).
-				Value(&selectedVersion)
-
-			if err := prompt.Run(); err != nil {
-				return err
-			}
-		}
-
-		if selectedVersion == "" {
-			return fmt.Errorf("no version selected")
-		}
-
-		extensionNames := make([]account_api.UpdateCheckExtension, 0)
-		for extName, extVersion := range extensions {
-
-			extensionNames = append(extensionNames, account_api.UpdateCheckExtension{
-				Name:    extName,
-				Version: extVersion,
-			})
-		}
-
-		updates, err := account_api.GetFutureExtensionUpdates(cmd.Context(), shopwareVersion.String(), selectedVersion, extensionNames)
+		updates, err := extensionCompatibility(cmd.Context(), shopwareVersion.String(), selectedVersion, extensions)
 		if err != nil {
 			return err
 		}
 
-		for _, name := range extensionNames {
-			found := false
-			for _, update := range updates {
-				if update.Name == name.Name {
-					found = true
-					break
-				}
-			}
-
-			if !found {
-				updates = append(updates, account_api.UpdateCheckExtensionCompatibility{
-					Name: name.Name,
-					Status: account_api.UpdateCheckExtensionCompatibilityStatus{
-						Label: "Not available in Store",
-					},
-				})
-			}
-		}
-
 		t := table.New().Border(lipgloss.NormalBorder()).Headers("Extension Name", "Compatible")
 		for _, update := range updates {
 			t.Row(update.Name, update.Status.Label)
@@ -181,6 +83,138 @@ func init() {
 	projectRootCmd.AddCommand(projectUpgradeCheckCmd)
 }
 
+// lookupInstalledExtensions returns the Shopware version and the installed
+// extensions with their versions, using the Admin API when configured and the
+// composer.lock otherwise.
+func lookupInstalledExtensions(ctx context.Context, cfg *shop.Config) (*version.Version, map[string]string, error) {
+	if !cfg.IsAdminAPIConfigured() {
+		logging.FromContext(ctx).Debugf("Using local composer.lock to lookup for available extensions")
+
+		shopwareVersion, extensions, err := getLocalExtensions()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get local extensions: %w", err)
+		}
+
+		return shopwareVersion, extensions, nil
+	}
+
+	logging.FromContext(ctx).Debugf("Using Shopware Admin API to lookup for available extensions")
+	client, err := shop.NewShopClient(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remoteExtensions, _, err := client.ExtensionManager.ListAvailableExtensions(adminSdk.NewApiContext(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list available extensions: %w", err)
+	}
+
+	extensions := make(map[string]string, 0)
+
+	for _, ext := range remoteExtensions {
+		extensions[ext.Name] = ext.Version
+	}
+
+	return client.ShopwareVersion, extensions, nil
+}
+
+// extensionCompatibility asks the Shopware Account about the compatibility of
+// the extensions with the future version. Extensions unknown to the store are
+// added as not available.
+func extensionCompatibility(ctx context.Context, currentVersion, futureVersion string, extensions map[string]string) ([]account_api.UpdateCheckExtensionCompatibility, error) {
+	extensionNames := make([]account_api.UpdateCheckExtension, 0)
+	for extName, extVersion := range extensions {
+		extensionNames = append(extensionNames, account_api.UpdateCheckExtension{
+			Name:    extName,
+			Version: extVersion,
+		})
+	}
+
+	updates, err := account_api.GetFutureExtensionUpdates(ctx, currentVersion, futureVersion, extensionNames)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range extensionNames {
+		found := false
+		for _, update := range updates {
+			if update.Name == name.Name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			updates = append(updates, account_api.UpdateCheckExtensionCompatibility{
+				Name: name.Name,
+				Status: account_api.UpdateCheckExtensionCompatibilityStatus{
+					Label: "Not available in Store",
+				},
+			})
+		}
+	}
+
+	return updates, nil
+}
+
+// selectUpgradeVersion asks for the Shopware version to upgrade to, without
+// interaction the first possible version is used. An empty version is
+// returned when the project is on the latest version.
+func selectUpgradeVersion(ctx context.Context, shopwareVersion *version.Version) (string, error) {
+	versions, err := extension.GetShopwareVersions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var possibleVersions []string
+
+	for _, v := range versions {
+		ver, err := version.NewVersion(v)
+		if err != nil {
+			continue
+		}
+
+		if strings.Contains(v, "RC") {
+			continue
+		}
+
+		if ver.LessThan(shopwareVersion) {
+			continue
+		}
+
+		possibleVersions = append(possibleVersions, v)
+	}
+
+	if len(possibleVersions) == 0 {
+		return "", nil
+	}
+
+	var selectedVersion string
+
+	if !system.IsInteractionEnabled(ctx) {
+		selectedVersion = possibleVersions[0]
+		logging.FromContext(ctx).Infof("Auto selected version %s", selectedVersion)
+	} else {
+		prompt := huh.NewSelect[string]().
+			Height(10).
+			Title("Select a Shopware version to check compatibility").
+			Options(
+				huh.NewOptions(possibleVersions...)...,
+			).
+			Value(&selectedVersion)
+
+		if err := prompt.Run(); err != nil {
+			return "", err
+		}
+	}
+
+	if selectedVersion == "" {
+		return "", fmt.Errorf("no version selected")
+	}
+
+	return selectedVersion, nil
+}
+
 func getLocalExtensions() (*version.Version, map[string]string, error) {
 	project, err := findClosestShopwareProject()
 	if err != nil {
@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/shopware/shopware-cli/internal/system"
 	"github.com/shopware/shopware-cli/internal/validation"
@@ -27,6 +26,9 @@ var projectValidateCmd = &cobra.Command{
 		tmpDir, err := os.MkdirTemp(os.TempDir(), "analyse-project-*")
 		noCopy, _ := cmd.Flags().GetBool("no-copy")
 		localOnly, _ := cmd.Flags().GetBool("local-only")
+		changedSince, _ := cmd.Flags().GetString("changed-since")
+		watch, _ := cmd.Flags().GetBool("watch")
+		watchPoll, _ := cmd.Flags().GetBool("watch-poll")
 		if err != nil {
 			return fmt.Errorf("cannot create temporary directory: %w", err)
 		}
@@ -51,7 +53,11 @@ var projectValidateCmd = &cobra.Command{
 			reportingFormat = validation.DetectDefaultReporter()
 		}
 
-		if !noCopy {
+		if watch && changedSince != "" {
+			return fmt.Errorf("--watch cannot be combined with --changed-since")
+		}
+
+		if !noCopy && !watch {
 			if err := system.CopyFiles(projectPath, tmpDir); err != nil {
 				return err
 			}
@@ -70,9 +76,16 @@ var projectValidateCmd = &cobra.Command{
 			return err
 		}
 
-		result := verifier.NewCheck()
+		if changedSince != "" {
+			changedFiles, err := verifier.ChangedFilesSince(cmd.Context(), projectPath, changedSince)
+			if err != nil {
+				return err
+			}
+
+			toolCfg.LimitToChangedFiles(projectPath, changedFiles)
+		}
 
-		var gr errgroup.Group
+		result := verifier.NewCheck()
 
 		tools := verifier.GetTools()
 
@@ -86,18 +99,15 @@ var projectValidateCmd = &cobra.Command{
 			return err
 		}
 
-		for _, tool := range tools {
-			tool := tool
-			gr.Go(func() error {
-				return tool.Check(cmd.Context(), result, *toolCfg)
-			})
+		if watch {
+			return verifier.Watch(cmd.Context(), tools, *toolCfg, projectPath, watchPoll)
 		}
 
-		if err := gr.Wait(); err != nil {
+		if err := tools.Check(cmd.Context(), result, *toolCfg); err != nil {
 			return err
 		}
 
-		filtered := result.RemoveByIdentifier(toolCfg.ValidationIgnores)
+		filtered := result.RemoveUnchangedFiles(*toolCfg).ApplySeverityOverrides(toolCfg.SeverityOverrides).RemoveByIdentifier(toolCfg.ValidationIgnores)
 
 		return validation.DoCheckReport(filtered, reportingFormat)
 	},
@@ -105,9 +115,12 @@ var projectValidateCmd = &cobra.Command{
 
 func init() {
 	projectRootCmd.AddCommand(projectValidateCmd)
-	projectValidateCmd.PersistentFlags().String("reporter", "", "Reporting format (summary, json, github, gitlab, junit, markdown)")
+	projectValidateCmd.PersistentFlags().String("reporter", "", "Reporting format (summary, json, github, gitlab, junit, markdown, sarif)")
 	projectValidateCmd.PersistentFlags().String("only", "", "Run only specific tools by name (comma-separated, e.g. phpstan,eslint)")
 	projectValidateCmd.PersistentFlags().String("exclude", "", "Exclude specific tools by name (comma-separated, e.g. phpstan,eslint)")
 	projectValidateCmd.PersistentFlags().Bool("no-copy", false, "Do not copy project files to temporary directory")
 	projectValidateCmd.PersistentFlags().Bool("local-only", false, "Only read plugins in custom/* folders")
+	projectValidateCmd.PersistentFlags().Bool("watch", false, "Validate again when files change, only the tools checking the changed file types run")
+	projectValidateCmd.PersistentFlags().Bool("watch-poll", false, "Poll for changes with --watch, for mounted directories without file system notifications")
+	projectValidateCmd.PersistentFlags().String("changed-since", "", "Only validate files changed since the git ref, use auto to detect the base of the pull request in CI")
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/shopware/shopware-cli/cmd/project"
 	accountApi "github.com/shopware/shopware-cli/internal/account-api"
 	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/internal/tracing"
 	"github.com/shopware/shopware-cli/internal/tracking"
 	"github.com/shopware/shopware-cli/internal/tui"
 	"github.com/shopware/shopware-cli/logging"
@@ -55,10 +56,31 @@ func run(ctx context.Context) int {
 	accountApi.SetUserAgent("shopware-cli/" + version)
 	rootCmd.SetArgs(args)
 
+	if endpoint, enabled := traceEndpointFromArgs(args); enabled {
+		if err := tracing.Setup(endpoint, version); err != nil {
+			logging.FromContext(ctx).Warnf("Tracing is disabled: %v", err)
+		}
+	}
+
+	ctx, commandSpan := tracing.Start(ctx, rootCmd.Use,
+		tracing.StringSlice("process.command_args", args),
+		tracing.String("cli.version", version),
+	)
+	defer shutdownTracing(ctx)
+
 	start := time.Now()
 	err := rootCmd.ExecuteContext(ctx)
 
+	exitCode := 0
+	if err != nil {
+		exitCode = 1
+	}
+
+	commandSpan.SetAttributes(tracing.Int("process.exit_code", exitCode))
+
 	if cmd, _, findErr := rootCmd.Find(os.Args[1:]); findErr == nil && cmd != rootCmd && cmd.RunE != nil {
+		commandSpan.SetName(cmd.CommandPath())
+
 		result := tracking.ResultSuccess
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
@@ -67,6 +89,8 @@ func run(ctx context.Context) int {
 				result = tracking.ResultFailure
 			}
 		}
+		commandSpan.SetAttributes(tracing.String("cli.result", result))
+
 		name := strings.TrimPrefix(cmd.CommandPath(), "shopware-cli ")
 		name = strings.ReplaceAll(name, " ", ".")
 		name = strings.ReplaceAll(name, "-", "_")
@@ -82,6 +106,8 @@ func run(ctx context.Context) int {
 		})
 	}
 
+	commandSpan.End(err)
+
 	if errors.Is(err, project.ErrEnvironmentDown) {
 		// The command already printed a human-readable status; exit 1 without
 		// logging an error.
@@ -96,6 +122,38 @@ func run(ctx context.Context) int {
 	return 0
 }
 
+// traceEndpointFromArgs resolves where traces are exported to. A bare --trace uses
+// SHOPWARE_CLI_OTEL_ENDPOINT or the local collector, --trace=<endpoint> wins over the
+// environment and without the flag tracing is only enabled by the environment variable.
+func traceEndpointFromArgs(args []string) (string, bool) {
+	envEndpoint := os.Getenv(tracing.EnvEndpoint)
+
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+
+		if arg == "--trace" {
+			return envEndpoint, true
+		}
+
+		if endpoint, ok := strings.CutPrefix(arg, "--trace="); ok {
+			return endpoint, true
+		}
+	}
+
+	return envEndpoint, envEndpoint != ""
+}
+
+func shutdownTracing(ctx context.Context) {
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := tracing.Shutdown(shutdownCtx); err != nil {
+		logging.FromContext(ctx).Warnf("%v", err)
+	}
+}
+
 func mapAliasArgs(argv []string) []string {
 	if len(argv) == 0 {
 		return nil
@@ -167,16 +225,18 @@ func init() {
 
 	rootCmd.PersistentFlags().Bool("verbose", false, "show debug output")
 	rootCmd.PersistentFlags().BoolP("no-interaction", "n", false, "do not ask any interactive questions")
+	rootCmd.PersistentFlags().String("trace", "", "export OpenTelemetry traces to an OTLP/HTTP endpoint or file:// path (defaults to $"+tracing.EnvEndpoint+" or "+tracing.DefaultEndpoint+")")
+	rootCmd.PersistentFlags().Lookup("trace").NoOptDefVal = tracing.DefaultEndpoint
 
 	project.Register(rootCmd)
 	extension.Register(rootCmd)
-	account.Register(rootCmd, func(commandName string) (*account.ServiceContainer, error) {
+	account.Register(rootCmd, func(ctx context.Context, commandName string) (*account.ServiceContainer, error) {
 		if commandName == "login" || commandName == "logout" {
 			return &account.ServiceContainer{
 				AccountClient: nil,
 			}, nil
 		}
-		client, err := accountApi.NewApi(rootCmd.Context())
+		client, err := accountApi.NewApi(ctx)
 		if err != nil {
 			return nil, err
 		}
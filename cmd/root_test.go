@@ -85,3 +85,35 @@ func TestCommandNameFromArgs(t *testing.T) {
 	assert.Equal(t, "swx", commandNameFromArgs([]string{"C:\\tools\\swx.exe"}))
 	assert.Equal(t, "shopware-cli", commandNameFromArgs(nil))
 }
+
+func TestTraceEndpointFromArgs(t *testing.T) {
+	t.Setenv("SHOPWARE_CLI_OTEL_ENDPOINT", "")
+
+	endpoint, enabled := traceEndpointFromArgs([]string{"project", "ci"})
+	assert.False(t, enabled)
+	assert.Empty(t, endpoint)
+
+	endpoint, enabled = traceEndpointFromArgs([]string{"project", "ci", "--trace"})
+	assert.True(t, enabled)
+	assert.Empty(t, endpoint)
+
+	endpoint, enabled = traceEndpointFromArgs([]string{"--trace=file:///tmp/traces.jsonl", "project", "ci"})
+	assert.True(t, enabled)
+	assert.Equal(t, "file:///tmp/traces.jsonl", endpoint)
+
+	endpoint, enabled = traceEndpointFromArgs([]string{"project", "console", "--", "--trace"})
+	assert.False(t, enabled)
+	assert.Empty(t, endpoint)
+}
+
+func TestTraceEndpointFromEnv(t *testing.T) {
+	t.Setenv("SHOPWARE_CLI_OTEL_ENDPOINT", "http://collector:4318")
+
+	endpoint, enabled := traceEndpointFromArgs([]string{"project", "ci"})
+	assert.True(t, enabled)
+	assert.Equal(t, "http://collector:4318", endpoint)
+
+	endpoint, enabled = traceEndpointFromArgs([]string{"project", "ci", "--trace=http://other:4318"})
+	assert.True(t, enabled)
+	assert.Equal(t, "http://other:4318", endpoint)
+}
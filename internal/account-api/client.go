@@ -12,6 +12,7 @@ import (
 
 	"golang.org/x/oauth2"
 
+	"github.com/shopware/shopware-cli/internal/secret"
 	"github.com/shopware/shopware-cli/internal/system"
 	"github.com/shopware/shopware-cli/logging"
 )
@@ -96,25 +97,77 @@ func (c *Client) isTokenValid() bool {
 	return false
 }
 
-func getCacheFileName() string {
+func getCacheFileName(profile string) string {
+	name := "shopware-api-token"
 	if isStaging() {
-		return "shopware-api-token-staging.json"
+		name += "-staging"
 	}
-	return "shopware-api-token.json"
+
+	if profile != DefaultProfile {
+		name += "-" + profile
+	}
+
+	return name + ".json"
+}
+
+// tokenCacheProfile returns the active profile, which becomes part of the token
+// cache file name. The directory config and the environment are not trusted to
+// contain a valid name.
+func tokenCacheProfile(ctx context.Context) (string, error) {
+	profile, source := ActiveProfile(ctx)
+	if err := ValidateProfileName(profile); err != nil {
+		return "", fmt.Errorf("profile of %s: %w", source, err)
+	}
+
+	return profile, nil
 }
 
-func getApiTokenCacheFilePath() string {
-	return filepath.Join(system.GetShopwareCliCacheDir(), getCacheFileName())
+func getApiTokenCacheFilePath(profile string) string {
+	return filepath.Join(system.GetShopwareCliCacheDir(), getCacheFileName(profile))
+}
+
+func getEncryptedApiTokenCacheFilePath(profile string) string {
+	return getApiTokenCacheFilePath(profile) + ".enc"
+}
+
+// tokenStoreKey returns the passphrase of the encrypted token cache. It is
+// empty when the token cache is stored unencrypted.
+func tokenStoreKey(ctx context.Context) (string, error) {
+	value := os.Getenv("SHOPWARE_CLI_TOKEN_STORE_KEY")
+	if value == "" {
+		return "", nil
+	}
+
+	return secret.Resolve(ctx, value, getApiUrl())
 }
 
 func createApiFromTokenCache(ctx context.Context) (*Client, error) {
-	tokenFilePath := getApiTokenCacheFilePath()
+	key, err := tokenStoreKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := tokenCacheProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenFilePath := getApiTokenCacheFilePath(profile)
+	if key != "" {
+		tokenFilePath = getEncryptedApiTokenCacheFilePath(profile)
+	}
 
 	if _, err := os.Stat(tokenFilePath); os.IsNotExist(err) {
 		return nil, err
 	}
 
-	content, err := os.ReadFile(tokenFilePath)
+	var content []byte
+	if key != "" {
+		content, err = secret.ReadEncryptedFile(tokenFilePath, key)
+	} else {
+		content, err = os.ReadFile(tokenFilePath)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -134,14 +187,41 @@ func createApiFromTokenCache(ctx context.Context) (*Client, error) {
 	return client, nil
 }
 
-func saveApiTokenToTokenCache(client *Client) error {
-	tokenFilePath := getApiTokenCacheFilePath()
+func saveApiTokenToTokenCache(ctx context.Context, client *Client) error {
+	key, err := tokenStoreKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	profile, err := tokenCacheProfile(ctx)
+	if err != nil {
+		return err
+	}
 
 	content, err := json.Marshal(client)
 	if err != nil {
 		return err
 	}
 
+	if err := addProfile(profile); err != nil {
+		return err
+	}
+
+	if key != "" {
+		if err := secret.WriteEncryptedFile(getEncryptedApiTokenCacheFilePath(profile), key, content); err != nil {
+			return err
+		}
+
+		// Don't keep an unencrypted token from before the encryption was enabled
+		if err := os.Remove(getApiTokenCacheFilePath(profile)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		return nil
+	}
+
+	tokenFilePath := getApiTokenCacheFilePath(profile)
+
 	tokenFileDirectory := filepath.Dir(tokenFilePath)
 	if _, err := os.Stat(tokenFileDirectory); os.IsNotExist(err) {
 		err := os.MkdirAll(tokenFileDirectory, 0o750)
@@ -158,12 +238,18 @@ func saveApiTokenToTokenCache(client *Client) error {
 	return nil
 }
 
-func InvalidateTokenCache() error {
-	tokenFilePath := getApiTokenCacheFilePath()
+// InvalidateTokenCache removes the token of the active profile.
+func InvalidateTokenCache(ctx context.Context) error {
+	profile, err := tokenCacheProfile(ctx)
+	if err != nil {
+		return err
+	}
 
-	if _, err := os.Stat(tokenFilePath); os.IsNotExist(err) {
-		return nil
+	for _, tokenFilePath := range []string{getApiTokenCacheFilePath(profile), getEncryptedApiTokenCacheFilePath(profile)} {
+		if err := os.Remove(tokenFilePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 	}
 
-	return os.Remove(tokenFilePath)
+	return removeProfile(profile)
 }
@@ -122,10 +122,10 @@ func TestIsTokenValidLegacy(t *testing.T) {
 
 func TestGetCacheFileName(t *testing.T) {
 	t.Setenv("SHOPWARE_CLI_ACCOUNT_STAGING", "")
-	assert.Equal(t, "shopware-api-token.json", getCacheFileName())
+	assert.Equal(t, "shopware-api-token.json", getCacheFileName(DefaultProfile))
 
 	t.Setenv("SHOPWARE_CLI_ACCOUNT_STAGING", "1")
-	assert.Equal(t, "shopware-api-token-staging.json", getCacheFileName())
+	assert.Equal(t, "shopware-api-token-staging.json", getCacheFileName(DefaultProfile))
 }
 
 func TestCreateApiFromTokenCache(t *testing.T) {
@@ -140,7 +140,7 @@ func TestCreateApiFromTokenCache(t *testing.T) {
 	})
 
 	t.Run("invalid json", func(t *testing.T) {
-		require.NoError(t, os.WriteFile(getApiTokenCacheFilePath(), []byte("not-json"), 0o600))
+		require.NoError(t, os.WriteFile(getApiTokenCacheFilePath(DefaultProfile), []byte("not-json"), 0o600))
 		_, err := createApiFromTokenCache(t.Context())
 		require.Error(t, err)
 	})
@@ -149,7 +149,7 @@ func TestCreateApiFromTokenCache(t *testing.T) {
 		client := &Client{Token: &oauth2.Token{AccessToken: "x", Expiry: time.Now().Add(-time.Hour)}}
 		content, err := json.Marshal(client)
 		require.NoError(t, err)
-		require.NoError(t, os.WriteFile(getApiTokenCacheFilePath(), content, 0o600))
+		require.NoError(t, os.WriteFile(getApiTokenCacheFilePath(DefaultProfile), content, 0o600))
 
 		_, err = createApiFromTokenCache(t.Context())
 		require.Error(t, err)
@@ -160,7 +160,7 @@ func TestCreateApiFromTokenCache(t *testing.T) {
 		client := &Client{Token: &oauth2.Token{AccessToken: "cached", Expiry: time.Now().Add(time.Hour)}}
 		content, err := json.Marshal(client)
 		require.NoError(t, err)
-		require.NoError(t, os.WriteFile(getApiTokenCacheFilePath(), content, 0o600))
+		require.NoError(t, os.WriteFile(getApiTokenCacheFilePath(DefaultProfile), content, 0o600))
 
 		got, err := createApiFromTokenCache(t.Context())
 		require.NoError(t, err)
@@ -179,15 +179,47 @@ func TestSaveAndInvalidateTokenCache(t *testing.T) {
 	t.Setenv("SHOPWARE_CLI_CACHE_DIR", nested)
 
 	client := &Client{Token: &oauth2.Token{AccessToken: "save-me", Expiry: time.Now().Add(time.Hour)}}
-	require.NoError(t, saveApiTokenToTokenCache(client))
+	require.NoError(t, saveApiTokenToTokenCache(t.Context(), client))
 
-	_, err := os.Stat(getApiTokenCacheFilePath())
+	_, err := os.Stat(getApiTokenCacheFilePath(DefaultProfile))
 	require.NoError(t, err)
 
-	require.NoError(t, InvalidateTokenCache())
-	_, err = os.Stat(getApiTokenCacheFilePath())
+	require.NoError(t, InvalidateTokenCache(t.Context()))
+	_, err = os.Stat(getApiTokenCacheFilePath(DefaultProfile))
 	assert.True(t, os.IsNotExist(err))
 
 	// missing file is a no-op
-	require.NoError(t, InvalidateTokenCache())
+	require.NoError(t, InvalidateTokenCache(t.Context()))
+}
+
+func TestEncryptedTokenCache(t *testing.T) {
+	t.Setenv("SHOPWARE_CLI_CACHE_DIR", t.TempDir())
+	t.Setenv("SHOPWARE_CLI_ACCOUNT_STAGING", "")
+
+	plain := &Client{Token: &oauth2.Token{AccessToken: "plain", Expiry: time.Now().Add(time.Hour)}}
+	require.NoError(t, saveApiTokenToTokenCache(t.Context(), plain))
+
+	t.Setenv("SHOPWARE_CLI_TOKEN_STORE_KEY", "passphrase")
+
+	client := &Client{Token: &oauth2.Token{AccessToken: "encrypted", Expiry: time.Now().Add(time.Hour)}}
+	require.NoError(t, saveApiTokenToTokenCache(t.Context(), client))
+
+	_, err := os.Stat(getApiTokenCacheFilePath(DefaultProfile))
+	assert.True(t, os.IsNotExist(err), "unencrypted token cache should be removed")
+
+	content, err := os.ReadFile(getEncryptedApiTokenCacheFilePath(DefaultProfile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "encrypted")
+
+	got, err := createApiFromTokenCache(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, "encrypted", got.Token.AccessToken)
+
+	t.Setenv("SHOPWARE_CLI_TOKEN_STORE_KEY", "wrong")
+	_, err = createApiFromTokenCache(t.Context())
+	assert.ErrorContains(t, err, "cannot decrypt")
+
+	require.NoError(t, InvalidateTokenCache(t.Context()))
+	_, err = os.Stat(getEncryptedApiTokenCacheFilePath(DefaultProfile))
+	assert.True(t, os.IsNotExist(err))
 }
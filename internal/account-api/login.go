@@ -12,6 +12,7 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 
+	"github.com/shopware/shopware-cli/internal/secret"
 	"github.com/shopware/shopware-cli/logging"
 )
 
@@ -30,6 +31,12 @@ func NewApi(ctx context.Context) (*Client, error) {
 		if clientID == "" || clientSecret == "" {
 			return nil, fmt.Errorf("both SHOPWARE_CLI_ACCOUNT_CLIENT_ID and SHOPWARE_CLI_ACCOUNT_CLIENT_SECRET must be set")
 		}
+
+		clientSecret, err := secret.Resolve(ctx, clientSecret, getApiUrl())
+		if err != nil {
+			return nil, err
+		}
+
 		return loginWithClientCredentials(ctx, clientID, clientSecret)
 	}
 
@@ -38,6 +45,11 @@ func NewApi(ctx context.Context) (*Client, error) {
 	password := os.Getenv("SHOPWARE_CLI_ACCOUNT_PASSWORD")
 
 	if email != "" && password != "" {
+		password, err := secret.Resolve(ctx, password, getApiUrl())
+		if err != nil {
+			return nil, err
+		}
+
 		logging.FromContext(ctx).Warnf("authentication with username/password is deprecated and will be removed in future. Please switch to OAuth2 client credentials, see https://developer.shopware.com/docs/products/cli/shopware-account-commands/authentication.html")
 		return loginWithCredentials(ctx, email, password)
 	}
@@ -50,7 +62,7 @@ func NewApi(ctx context.Context) (*Client, error) {
 
 	client = &Client{Token: token}
 
-	if err := saveApiTokenToTokenCache(client); err != nil {
+	if err := saveApiTokenToTokenCache(ctx, client); err != nil {
 		logging.FromContext(ctx).Errorf(fmt.Sprintf("Cannot save token cache: %v", err))
 	}
 
@@ -73,7 +85,7 @@ func loginWithClientCredentials(ctx context.Context, clientID, clientSecret stri
 
 	client := &Client{Token: token}
 
-	if err := saveApiTokenToTokenCache(client); err != nil {
+	if err := saveApiTokenToTokenCache(ctx, client); err != nil {
 		logging.FromContext(ctx).Errorf("Cannot save token cache: %v", err)
 	}
 
@@ -131,7 +143,7 @@ func loginWithCredentials(ctx context.Context, email, password string) (*Client,
 		LegacyToken: &tokenResp,
 	}
 
-	if err := saveApiTokenToTokenCache(client); err != nil {
+	if err := saveApiTokenToTokenCache(ctx, client); err != nil {
 		logging.FromContext(ctx).Errorf(fmt.Sprintf("Cannot save token cache: %v", err))
 	}
 
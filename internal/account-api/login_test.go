@@ -38,7 +38,8 @@ func TestNewApiUsesClientCredentialsFromEnv(t *testing.T) {
 
 	t.Setenv("SHOPWARE_CLI_CACHE_DIR", t.TempDir())
 	t.Setenv("SHOPWARE_CLI_ACCOUNT_CLIENT_ID", "test-client-id")
-	t.Setenv("SHOPWARE_CLI_ACCOUNT_CLIENT_SECRET", "test-client-secret")
+	t.Setenv("SHOPWARE_CLI_ACCOUNT_CLIENT_SECRET", "env:TEST_ACCOUNT_SECRET")
+	t.Setenv("TEST_ACCOUNT_SECRET", "test-client-secret")
 	t.Setenv("SHOPWARE_CLI_OIDC_ENDPOINT", srv.URL)
 
 	client, err := NewApi(t.Context())
@@ -69,7 +70,7 @@ func TestNewApiUsesValidTokenCache(t *testing.T) {
 	t.Setenv("SHOPWARE_CLI_ACCOUNT_PASSWORD", "")
 
 	cached := &Client{Token: &oauth2.Token{AccessToken: "from-cache", Expiry: time.Now().Add(time.Hour)}}
-	require.NoError(t, saveApiTokenToTokenCache(cached))
+	require.NoError(t, saveApiTokenToTokenCache(t.Context(), cached))
 
 	client, err := NewApi(t.Context())
 	require.NoError(t, err)
@@ -13,6 +13,9 @@ import (
 type UploadOptions struct {
 	// SkipReviewWait skips waiting for the code review result after the upload.
 	SkipReviewWait bool
+	// MaxReviewPolls limits how often the code review result is polled. Defaults to 10, a
+	// negative value keeps polling until the review has finished or the context is cancelled.
+	MaxReviewPolls int
 	// Sleep is used while waiting for the code review result. Defaults to time.Sleep and can be
 	// replaced in tests.
 	Sleep func(time.Duration)
@@ -170,10 +173,17 @@ func UploadExtension(ctx context.Context, producer ProducerAPI, zipExt extension
 		return nil
 	}
 
-	return waitForCodeReviewResult(ctx, producer, ext.Id, foundBinary.Id, len(beforeReviews), sleep)
+	maxTries := opts.MaxReviewPolls
+	if maxTries == 0 {
+		maxTries = defaultMaxReviewPolls
+	}
+
+	return waitForCodeReviewResult(ctx, producer, ext.Id, foundBinary.Id, len(beforeReviews), maxTries, sleep)
 }
 
-func waitForCodeReviewResult(ctx context.Context, producer ProducerAPI, extensionId, binaryId, previousReviewCount int, sleep func(time.Duration)) error {
+const defaultMaxReviewPolls = 10
+
+func waitForCodeReviewResult(ctx context.Context, producer ProducerAPI, extensionId, binaryId, previousReviewCount, maxTries int, sleep func(time.Duration)) error {
 	logging.FromContext(ctx).Infof("Waiting for code review result")
 	logging.FromContext(ctx).Debugf("Initial wait of 10 seconds before first poll")
 
@@ -181,10 +191,9 @@ func waitForCodeReviewResult(ctx context.Context, producer ProducerAPI, extensio
 		return err
 	}
 
-	maxTries := 10
 	tried := 0
 	for {
-		logging.FromContext(ctx).Debugf("Polling for code review result (attempt %d/%d)", tried+1, maxTries)
+		logging.FromContext(ctx).Debugf("Polling for code review result (attempt %d)", tried+1)
 
 		reviews, err := producer.GetBinaryReviewResults(ctx, extensionId, binaryId)
 		if err != nil {
@@ -31,7 +31,7 @@ func TestWaitForCodeReviewResultPassed(t *testing.T) {
 		},
 	}
 
-	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, noopSleep)
+	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, defaultMaxReviewPolls, noopSleep)
 
 	require.NoError(t, err)
 }
@@ -52,7 +52,7 @@ func TestWaitForCodeReviewResultFailed(t *testing.T) {
 		},
 	}
 
-	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, noopSleep)
+	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, defaultMaxReviewPolls, noopSleep)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "code review has not passed")
@@ -72,7 +72,7 @@ func TestWaitForCodeReviewResultPollsUntilReviewAppears(t *testing.T) {
 		},
 	}
 
-	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, noopSleep)
+	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, defaultMaxReviewPolls, noopSleep)
 
 	require.NoError(t, err)
 	assert.Equal(t, 3, calls)
@@ -87,12 +87,30 @@ func TestWaitForCodeReviewResultGivesUpAfterMaxTries(t *testing.T) {
 		},
 	}
 
-	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, noopSleep)
+	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, defaultMaxReviewPolls, noopSleep)
 
 	require.NoError(t, err)
 	assert.Equal(t, 10, calls)
 }
 
+func TestWaitForCodeReviewResultUnlimitedPolls(t *testing.T) {
+	calls := 0
+	producer := &fakeProducer{
+		getBinaryReviewResultsFn: func(_ context.Context, _, _ int) ([]BinaryReviewResult, error) {
+			calls++
+			if calls < 15 {
+				return []BinaryReviewResult{reviewWithType(1)}, nil
+			}
+			return []BinaryReviewResult{reviewWithType(1), reviewWithType(2)}, nil
+		},
+	}
+
+	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 1, -1, noopSleep)
+
+	require.Error(t, err)
+	assert.Equal(t, 15, calls)
+}
+
 func TestWaitForCodeReviewResultShrinkingListDoesNotPanic(t *testing.T) {
 	producer := &fakeProducer{
 		getBinaryReviewResultsFn: func(_ context.Context, _, _ int) ([]BinaryReviewResult, error) {
@@ -101,7 +119,7 @@ func TestWaitForCodeReviewResultShrinkingListDoesNotPanic(t *testing.T) {
 		},
 	}
 
-	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 3, noopSleep)
+	err := waitForCodeReviewResult(t.Context(), producer, 1, 1, 3, defaultMaxReviewPolls, noopSleep)
 
 	require.NoError(t, err)
 }
@@ -110,7 +128,7 @@ func TestWaitForCodeReviewResultContextCancelled(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
 	cancel()
 
-	err := waitForCodeReviewResult(ctx, &fakeProducer{}, 1, 1, 0, noopSleep)
+	err := waitForCodeReviewResult(ctx, &fakeProducer{}, 1, 1, 0, defaultMaxReviewPolls, noopSleep)
 
 	require.ErrorIs(t, err, context.Canceled)
 }
@@ -0,0 +1,194 @@
+package account_api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+// DefaultProfile is used when no profile is selected. Its token cache keeps
+// the file name from before profiles existed.
+const DefaultProfile = "default"
+
+// ProfileEnv selects the profile, it takes precedence over the directory
+// config and the profile selected with account profile use.
+const ProfileEnv = "SHOPWARE_CLI_ACCOUNT_PROFILE"
+
+// ProfileSource tells why a profile is active.
+type ProfileSource string
+
+const (
+	ProfileSourceFlag      ProfileSource = "--profile flag"
+	ProfileSourceEnv       ProfileSource = ProfileEnv
+	ProfileSourceDirectory ProfileSource = ".shopware-extension.yml"
+	ProfileSourceSelected  ProfileSource = "account profile use"
+	ProfileSourceDefault   ProfileSource = "default"
+)
+
+var profileNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+type profileContextKey struct{}
+
+type directoryProfileContextKey struct{}
+
+// WithProfile uses the profile for all account requests of the context, e.g.
+// from the --profile flag.
+func WithProfile(ctx context.Context, profile string) context.Context {
+	if profile == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, profileContextKey{}, profile)
+}
+
+// WithDirectoryProfile uses the profile configured for the current directory
+// when no profile is passed with WithProfile or the environment.
+func WithDirectoryProfile(ctx context.Context, profile string) context.Context {
+	if profile == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, directoryProfileContextKey{}, profile)
+}
+
+// ActiveProfile returns the profile used for the context and why it is used.
+func ActiveProfile(ctx context.Context) (string, ProfileSource) {
+	if profile, ok := ctx.Value(profileContextKey{}).(string); ok {
+		return profile, ProfileSourceFlag
+	}
+
+	if profile := os.Getenv(ProfileEnv); profile != "" {
+		return profile, ProfileSourceEnv
+	}
+
+	if profile, ok := ctx.Value(directoryProfileContextKey{}).(string); ok {
+		return profile, ProfileSourceDirectory
+	}
+
+	if state, err := readProfileState(); err == nil && state.Current != "" {
+		return state.Current, ProfileSourceSelected
+	}
+
+	return DefaultProfile, ProfileSourceDefault
+}
+
+// ValidateProfileName makes sure the name can be used in a file name.
+func ValidateProfileName(profile string) error {
+	if !profileNameRegex.MatchString(profile) {
+		return fmt.Errorf("invalid profile name %q, only letters, numbers, dots, dashes and underscores are allowed", profile)
+	}
+
+	return nil
+}
+
+type profileState struct {
+	// Current is the profile selected with account profile use
+	Current string `json:"current,omitempty"`
+	// Profiles are the profiles logged in before
+	Profiles []string `json:"profiles"`
+}
+
+func getProfileStateFilePath() string {
+	return filepath.Join(system.GetShopwareCliCacheDir(), "account-profiles.json")
+}
+
+func readProfileState() (*profileState, error) {
+	state := &profileState{}
+
+	content, err := os.ReadFile(getProfileStateFilePath())
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", getProfileStateFilePath(), err)
+	}
+
+	return state, nil
+}
+
+func writeProfileState(state *profileState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(getProfileStateFilePath()), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(getProfileStateFilePath(), content, 0o600)
+}
+
+func addProfile(profile string) error {
+	state, err := readProfileState()
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(state.Profiles, profile) {
+		return nil
+	}
+
+	state.Profiles = append(state.Profiles, profile)
+	slices.Sort(state.Profiles)
+
+	return writeProfileState(state)
+}
+
+func removeProfile(profile string) error {
+	state, err := readProfileState()
+	if err != nil {
+		return err
+	}
+
+	state.Profiles = slices.DeleteFunc(state.Profiles, func(p string) bool {
+		return p == profile
+	})
+
+	if state.Current == profile {
+		state.Current = ""
+	}
+
+	return writeProfileState(state)
+}
+
+// ListProfiles returns the profiles logged in before.
+func ListProfiles() ([]string, error) {
+	state, err := readProfileState()
+	if err != nil {
+		return nil, err
+	}
+
+	return state.Profiles, nil
+}
+
+// UseProfile selects the profile for the following account commands.
+func UseProfile(profile string) error {
+	if err := ValidateProfileName(profile); err != nil {
+		return err
+	}
+
+	state, err := readProfileState()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(state.Profiles, profile) {
+		return fmt.Errorf("profile %q does not exist, create it with: shopware-cli account login --profile %s", profile, profile)
+	}
+
+	state.Current = profile
+
+	return writeProfileState(state)
+}
@@ -0,0 +1,109 @@
+package account_api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestActiveProfilePrecedence(t *testing.T) {
+	t.Setenv("SHOPWARE_CLI_CACHE_DIR", t.TempDir())
+	t.Setenv(ProfileEnv, "")
+
+	profile, source := ActiveProfile(t.Context())
+	assert.Equal(t, DefaultProfile, profile)
+	assert.Equal(t, ProfileSourceDefault, source)
+
+	require.NoError(t, addProfile("selected"))
+	require.NoError(t, UseProfile("selected"))
+
+	profile, source = ActiveProfile(t.Context())
+	assert.Equal(t, "selected", profile)
+	assert.Equal(t, ProfileSourceSelected, source)
+
+	ctx := WithDirectoryProfile(t.Context(), "directory")
+	profile, source = ActiveProfile(ctx)
+	assert.Equal(t, "directory", profile)
+	assert.Equal(t, ProfileSourceDirectory, source)
+
+	t.Setenv(ProfileEnv, "env")
+	profile, source = ActiveProfile(ctx)
+	assert.Equal(t, "env", profile)
+	assert.Equal(t, ProfileSourceEnv, source)
+
+	profile, source = ActiveProfile(WithProfile(ctx, "flag"))
+	assert.Equal(t, "flag", profile)
+	assert.Equal(t, ProfileSourceFlag, source)
+}
+
+func TestProfilesHaveSeparateTokenCaches(t *testing.T) {
+	t.Setenv("SHOPWARE_CLI_CACHE_DIR", t.TempDir())
+	t.Setenv("SHOPWARE_CLI_ACCOUNT_STAGING", "")
+	t.Setenv("SHOPWARE_CLI_TOKEN_STORE_KEY", "")
+	t.Setenv(ProfileEnv, "")
+
+	clientA := WithProfile(t.Context(), "client-a")
+	clientB := WithProfile(t.Context(), "client-b")
+
+	require.NoError(t, saveApiTokenToTokenCache(clientA, &Client{Token: &oauth2.Token{AccessToken: "a", Expiry: time.Now().Add(time.Hour)}}))
+	require.NoError(t, saveApiTokenToTokenCache(clientB, &Client{Token: &oauth2.Token{AccessToken: "b", Expiry: time.Now().Add(time.Hour)}}))
+
+	assert.Equal(t, "shopware-api-token-client-a.json", getCacheFileName("client-a"))
+
+	got, err := createApiFromTokenCache(clientA)
+	require.NoError(t, err)
+	assert.Equal(t, "a", got.Token.AccessToken)
+
+	got, err = createApiFromTokenCache(clientB)
+	require.NoError(t, err)
+	assert.Equal(t, "b", got.Token.AccessToken)
+
+	_, err = createApiFromTokenCache(t.Context())
+	assert.True(t, os.IsNotExist(err))
+
+	profiles, err := ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"client-a", "client-b"}, profiles)
+
+	require.NoError(t, UseProfile("client-b"))
+	require.NoError(t, InvalidateTokenCache(clientB))
+
+	profiles, err = ListProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"client-a"}, profiles)
+
+	profile, _ := ActiveProfile(t.Context())
+	assert.Equal(t, DefaultProfile, profile)
+
+	assert.ErrorContains(t, UseProfile("client-b"), "does not exist")
+	assert.Error(t, UseProfile("../evil"))
+}
+
+func TestTokenCacheRejectsInvalidProfiles(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("SHOPWARE_CLI_CACHE_DIR", cacheDir)
+	t.Setenv("SHOPWARE_CLI_ACCOUNT_STAGING", "")
+	t.Setenv("SHOPWARE_CLI_TOKEN_STORE_KEY", "")
+	t.Setenv(ProfileEnv, "")
+
+	// a token outside the cache directory, which the profile name tries to reach
+	content := []byte(`{"token": {"access_token": "stolen", "expiry": "2999-01-01T00:00:00Z"}}`)
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(cacheDir), "x.json"), content, 0o600))
+
+	ctx := WithDirectoryProfile(t.Context(), "../x")
+
+	_, err := createApiFromTokenCache(ctx)
+	assert.ErrorContains(t, err, "profile of .shopware-extension.yml: invalid profile name")
+	assert.ErrorContains(t, InvalidateTokenCache(ctx), "invalid profile name")
+
+	t.Setenv(ProfileEnv, "../x")
+
+	_, err = createApiFromTokenCache(t.Context())
+	assert.ErrorContains(t, err, "profile of SHOPWARE_CLI_ACCOUNT_PROFILE: invalid profile name")
+	assert.ErrorContains(t, saveApiTokenToTokenCache(t.Context(), &Client{}), "invalid profile name")
+}
@@ -29,6 +29,7 @@ type Client struct {
 	ExtensionManager *ExtensionManagerService
 	CacheManager     *CacheManagerService
 	SalesChannel     *SalesChannelService
+	MailTemplate     *MailTemplateService
 }
 
 type ClientService struct {
@@ -43,6 +44,7 @@ func NewApiClient(ctx context.Context, shopUrl string, credentials OAuthCredenti
 	shopClient.ExtensionManager = (*ExtensionManagerService)(&shopClient.common)
 	shopClient.CacheManager = (*CacheManagerService)(&shopClient.common)
 	shopClient.SalesChannel = (*SalesChannelService)(&shopClient.common)
+	shopClient.MailTemplate = (*MailTemplateService)(&shopClient.common)
 
 	if err := shopClient.authorize(ctx, shopUrl, credentials); err != nil {
 		return nil, err
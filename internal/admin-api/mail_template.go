@@ -0,0 +1,94 @@
+package admin_sdk
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type MailTemplateService ClientService
+
+type MailTemplate struct {
+	Id                 string `json:"id"`
+	MailTemplateTypeId string `json:"mailTemplateTypeId"`
+	SystemDefault      bool   `json:"systemDefault"`
+	Subject            string `json:"subject"`
+	ContentHtml        string `json:"contentHtml"`
+	ContentPlain       string `json:"contentPlain"`
+}
+
+// MailTemplateUpdate holds the translated fields of a mail template. Empty
+// fields are not changed.
+type MailTemplateUpdate struct {
+	Subject      string `json:"subject,omitempty"`
+	ContentHtml  string `json:"contentHtml,omitempty"`
+	ContentPlain string `json:"contentPlain,omitempty"`
+}
+
+// ListByType returns the mail templates of the mail template type with the technical name.
+func (s MailTemplateService) ListByType(ctx ApiContext, technicalName string) ([]MailTemplate, error) {
+	body := map[string]any{
+		"filter": []map[string]any{
+			{"type": "equals", "field": "mailTemplateType.technicalName", "value": technicalName},
+		},
+		"limit": 100,
+	}
+
+	r, err := s.Client.NewRequest(ctx, http.MethodPost, "/api/search/mail-template", body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot search mail templates %w", err)
+	}
+
+	var out searchResponse[MailTemplate]
+	resp, err := s.Client.Do(ctx.Context, r, &out)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	return out.Data, nil
+}
+
+// Update writes the translated fields in the language of the context.
+func (s MailTemplateService) Update(ctx ApiContext, id string, update MailTemplateUpdate) error {
+	r, err := s.Client.NewRequest(ctx, http.MethodPatch, "/api/mail-template/"+id, update)
+	if err != nil {
+		return fmt.Errorf("cannot update mail template %w", err)
+	}
+
+	resp, err := s.Client.Do(ctx.Context, r, nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// LanguageIdByLocale returns the id of the language using the locale, like en-GB.
+func (s MailTemplateService) LanguageIdByLocale(ctx ApiContext, locale string) (string, error) {
+	body := map[string]any{
+		"filter": []map[string]any{
+			{"type": "equals", "field": "locale.code", "value": locale},
+		},
+		"limit": 1,
+	}
+
+	r, err := s.Client.NewRequest(ctx, http.MethodPost, "/api/search/language", body)
+	if err != nil {
+		return "", fmt.Errorf("cannot search languages %w", err)
+	}
+
+	var out searchResponse[struct {
+		Id string `json:"id"`
+	}]
+	resp, err := s.Client.Do(ctx.Context, r, &out)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if len(out.Data) == 0 {
+		return "", ErrNotFound
+	}
+
+	return out.Data[0].Id, nil
+}
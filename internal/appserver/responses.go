@@ -0,0 +1,143 @@
+package appserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Action response types understood by the Administration.
+const (
+	ActionNotification = "notification"
+	ActionModal        = "openModal"
+	ActionNewTab       = "openNewTab"
+	ActionReload       = "reload"
+)
+
+// Responses configures what the server answers. Actions are keyed by the action
+// name of the action button, routes by the request path.
+type Responses struct {
+	DefaultAction *ActionResponse           `yaml:"default_action,omitempty"`
+	Actions       map[string]ActionResponse `yaml:"actions,omitempty"`
+	Routes        map[string]RouteResponse  `yaml:"routes,omitempty"`
+}
+
+// ActionResponse is the answer to an action button click.
+type ActionResponse struct {
+	// Type is one of notification, openModal, openNewTab or reload.
+	Type string `yaml:"type"`
+	// Status of a notification: success, error, info or warning.
+	Status  string `yaml:"status,omitempty"`
+	Message string `yaml:"message,omitempty"`
+	// URL of the modal iframe or the new tab.
+	URL string `yaml:"url,omitempty"`
+	// Size of the modal: small, medium, large or fullscreen.
+	Size   string `yaml:"size,omitempty"`
+	Expand bool   `yaml:"expand,omitempty"`
+}
+
+// RouteResponse is a fixed answer for a path, e.g. a payment or gateway URL.
+type RouteResponse struct {
+	Status int `yaml:"status,omitempty"`
+	Body   any `yaml:"body,omitempty"`
+}
+
+// ReadResponses reads a responses YAML file.
+func ReadResponses(path string) (*Responses, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses Responses
+	if err := yaml.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	for name, action := range responses.Actions {
+		if err := action.validate(); err != nil {
+			return nil, fmt.Errorf("action %s: %w", name, err)
+		}
+	}
+
+	if responses.DefaultAction != nil {
+		if err := responses.DefaultAction.validate(); err != nil {
+			return nil, fmt.Errorf("default_action: %w", err)
+		}
+	}
+
+	return &responses, nil
+}
+
+func (r ActionResponse) validate() error {
+	switch r.Type {
+	case ActionNotification, ActionReload:
+		return nil
+	case ActionModal, ActionNewTab:
+		if r.URL == "" {
+			return fmt.Errorf("%s requires an url", r.Type)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown type %q, expected %s, %s, %s or %s", r.Type, ActionNotification, ActionModal, ActionNewTab, ActionReload)
+}
+
+func (r *Responses) action(name string) ActionResponse {
+	if r != nil {
+		if action, ok := r.Actions[name]; ok {
+			return action
+		}
+
+		if r.DefaultAction != nil {
+			return *r.DefaultAction
+		}
+	}
+
+	return ActionResponse{Type: ActionNotification, Status: "success", Message: fmt.Sprintf("Action %s received by shopware-cli", name)}
+}
+
+func (r *Responses) route(path string) (RouteResponse, bool) {
+	if r == nil {
+		return RouteResponse{}, false
+	}
+
+	route, ok := r.Routes[path]
+
+	return route, ok
+}
+
+// MarshalJSON returns the body the Administration expects.
+func (r ActionResponse) MarshalJSON() ([]byte, error) {
+	payload := map[string]any{}
+
+	switch r.Type {
+	case ActionNotification:
+		status := r.Status
+		if status == "" {
+			status = "success"
+		}
+
+		payload["status"] = status
+		payload["message"] = r.Message
+	case ActionModal:
+		size := r.Size
+		if size == "" {
+			size = "medium"
+		}
+
+		payload["iframeUrl"] = r.URL
+		payload["size"] = size
+		payload["expand"] = r.Expand
+	case ActionNewTab:
+		payload["redirectUrl"] = r.URL
+	}
+
+	return json.Marshal(map[string]any{
+		"actionType": r.Type,
+		"payload":    payload,
+	})
+}
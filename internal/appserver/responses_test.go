@@ -0,0 +1,59 @@
+package appserver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadResponses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+default_action:
+  type: reload
+actions:
+  open-details:
+    type: openModal
+    url: https://example.com/modal
+    size: large
+routes:
+  /payment/pay:
+    status: 200
+    body:
+      status: paid
+`), 0o644))
+
+	responses, err := ReadResponses(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, ActionModal, responses.action("open-details").Type)
+	assert.Equal(t, ActionReload, responses.action("other").Type)
+
+	route, ok := responses.route("/payment/pay")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]any{"status": "paid"}, route.Body)
+}
+
+func TestReadResponsesRejectsInvalidAction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "responses.yml")
+	require.NoError(t, os.WriteFile(path, []byte("actions:\n  foo:\n    type: openNewTab\n"), 0o644))
+
+	_, err := ReadResponses(path)
+	assert.ErrorContains(t, err, "action foo: openNewTab requires an url")
+}
+
+func TestActionResponseJSON(t *testing.T) {
+	var nilResponses *Responses
+
+	data, err := json.Marshal(nilResponses.action("sync"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"actionType":"notification","payload":{"status":"success","message":"Action sync received by shopware-cli"}}`, string(data))
+
+	data, err = json.Marshal(ActionResponse{Type: ActionModal, URL: "https://example.com"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"actionType":"openModal","payload":{"iframeUrl":"https://example.com","size":"medium","expand":false}}`, string(data))
+}
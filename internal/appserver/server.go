@@ -0,0 +1,561 @@
+package appserver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/extension"
+)
+
+// ConfirmationPath is where the shop confirms the registration.
+const ConfirmationPath = "/shopware-cli/app-serve/confirm"
+
+// Kind is the kind of request the shop sent.
+type Kind string
+
+const (
+	KindRegistration Kind = "registration"
+	KindConfirmation Kind = "confirmation"
+	KindWebhook      Kind = "webhook"
+	KindAction       Kind = "action"
+	KindModule       Kind = "module"
+	KindPayment      Kind = "payment"
+	KindTax          Kind = "tax"
+	KindGateway      Kind = "gateway"
+	KindRoute        Kind = "route"
+	KindUnknown      Kind = "unknown"
+)
+
+// Event describes a handled request.
+type Event struct {
+	Time     time.Time       `json:"time"`
+	Kind     Kind            `json:"kind"`
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Name     string          `json:"name,omitempty"`
+	ShopID   string          `json:"shopId,omitempty"`
+	ShopURL  string          `json:"shopUrl,omitempty"`
+	Verified bool            `json:"verified"`
+	Status   int             `json:"status"`
+	Error    string          `json:"error,omitempty"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// Options configures the Server.
+type Options struct {
+	// AppName is the technical name of the app from the manifest.
+	AppName string
+	// AppSecret is the setup secret from the manifest.
+	AppSecret string
+	Manifest  extension.Manifest
+	// PublicURL is the URL the shop reaches the server at.
+	PublicURL string
+	Responses *Responses
+	Store     *ShopStore
+	// OnEvent is called for every handled request.
+	OnEvent func(Event)
+}
+
+type route struct {
+	kind Kind
+	name string
+	// match is the webhook event or action name used to tell apart routes
+	// sharing a path
+	match string
+}
+
+// Server is a local stand-in for an app backend. It answers the registration
+// handshake and verifies and logs all requests the shop sends to the URLs of
+// the manifest.
+type Server struct {
+	opts   Options
+	routes map[string][]route
+	mu     sync.Mutex
+}
+
+// New creates a Server for the URLs of the manifest.
+func New(opts Options) (*Server, error) {
+	if opts.AppSecret == "" {
+		return nil, fmt.Errorf("the app secret is required for the registration")
+	}
+
+	if opts.Store == nil {
+		store, err := NewShopStore("")
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Store = store
+	}
+
+	if opts.OnEvent == nil {
+		opts.OnEvent = func(Event) {}
+	}
+
+	return &Server{opts: opts, routes: manifestRoutes(opts.Manifest)}, nil
+}
+
+// Paths returns the paths served, for display.
+func (s *Server) Paths() map[string][]string {
+	paths := map[string][]string{}
+
+	for path, routes := range s.routes {
+		for _, r := range routes {
+			label := string(r.kind)
+			if r.name != "" {
+				label += " " + r.name
+			}
+
+			paths[path] = append(paths[path], label)
+		}
+	}
+
+	return paths
+}
+
+func manifestRoutes(manifest extension.Manifest) map[string][]route {
+	routes := map[string][]route{}
+
+	add := func(rawURL string, r route) {
+		if rawURL == "" {
+			return
+		}
+
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return
+		}
+
+		path := parsed.Path
+		if path == "" {
+			path = "/"
+		}
+
+		routes[path] = append(routes[path], r)
+	}
+
+	if manifest.Setup != nil {
+		add(manifest.Setup.RegistrationUrl, route{kind: KindRegistration})
+	}
+
+	if manifest.Admin != nil {
+		for _, button := range manifest.Admin.ActionButtons {
+			add(button.Url, route{kind: KindAction, name: button.Action, match: button.Action})
+		}
+
+		for _, module := range manifest.Admin.Modules {
+			add(module.Source, route{kind: KindModule, name: module.Name})
+		}
+
+		if manifest.Admin.MainModule != nil {
+			add(manifest.Admin.MainModule.Source, route{kind: KindModule, name: "main-module"})
+		}
+
+		add(manifest.Admin.BaseAppUrl, route{kind: KindModule, name: "base-app-url"})
+	}
+
+	if manifest.Webhooks != nil {
+		for _, webhook := range manifest.Webhooks.Webhooks {
+			add(webhook.Url, route{kind: KindWebhook, name: webhook.Name, match: webhook.Event})
+		}
+	}
+
+	if manifest.Payments != nil {
+		for _, method := range manifest.Payments.PaymentMethods {
+			add(method.PayUrl, route{kind: KindPayment, name: method.Identifier + " pay"})
+			add(method.FinalizeUrl, route{kind: KindPayment, name: method.Identifier + " finalize"})
+			add(method.ValidateUrl, route{kind: KindPayment, name: method.Identifier + " validate"})
+			add(method.CaptureUrl, route{kind: KindPayment, name: method.Identifier + " capture"})
+			add(method.RefundUrl, route{kind: KindPayment, name: method.Identifier + " refund"})
+			add(method.RecurringUrl, route{kind: KindPayment, name: method.Identifier + " recurring"})
+		}
+	}
+
+	if manifest.Tax != nil {
+		for _, provider := range manifest.Tax.Providers {
+			add(provider.ProcessUrl, route{kind: KindTax, name: provider.Identifier})
+		}
+	}
+
+	if manifest.Gateways != nil {
+		add(manifest.Gateways.Checkout, route{kind: KindGateway, name: "checkout"})
+		add(manifest.Gateways.Context, route{kind: KindGateway, name: "context"})
+		add(manifest.Gateways.InAppPurchases, route{kind: KindGateway, name: "in-app-purchases"})
+	}
+
+	return routes
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == ConfirmationPath {
+		s.handleConfirmation(w, r)
+		return
+	}
+
+	routes := s.routes[r.URL.Path]
+
+	if len(routes) > 0 && routes[0].kind == KindRegistration {
+		s.handleRegistration(w, r)
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		s.handleIframe(w, r, routes)
+		return
+	}
+
+	s.handleSigned(w, r, routes)
+}
+
+func (s *Server) handleRegistration(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	event := Event{
+		Kind:    KindRegistration,
+		ShopID:  query.Get("shop-id"),
+		ShopURL: query.Get("shop-url"),
+		Payload: queryPayload(query),
+	}
+
+	event.Verified = Verify(s.opts.AppSecret, []byte(r.URL.RawQuery), r.Header.Get(HeaderAppSignature))
+
+	if !event.Verified {
+		s.fail(w, r, event, http.StatusUnauthorized, "invalid shopware-app-signature, does the shop use the same app secret as the manifest?")
+		return
+	}
+
+	if event.ShopID == "" || event.ShopURL == "" {
+		s.fail(w, r, event, http.StatusBadRequest, "shop-id and shop-url are required")
+		return
+	}
+
+	secret, err := randomSecret()
+	if err != nil {
+		s.fail(w, r, event, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := s.opts.Store.Put(Shop{ID: event.ShopID, URL: event.ShopURL, Secret: secret}); err != nil {
+		s.fail(w, r, event, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := map[string]string{
+		"proof":            Sign(s.opts.AppSecret, []byte(event.ShopID+event.ShopURL+s.opts.AppName)),
+		"confirmation_url": strings.TrimRight(s.opts.PublicURL, "/") + ConfirmationPath,
+	}
+
+	// the shop secret is not logged
+	event.Status = http.StatusOK
+	event.Response, _ = json.Marshal(response)
+	s.emit(r, event)
+
+	response["secret"] = secret
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (s *Server) handleConfirmation(w http.ResponseWriter, r *http.Request) {
+	event := Event{Kind: KindConfirmation}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.fail(w, r, event, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var confirmation struct {
+		APIKey    string `json:"apiKey"`
+		SecretKey string `json:"secretKey"`
+		ShopURL   string `json:"shopUrl"`
+		ShopID    string `json:"shopId"`
+	}
+
+	if err := json.Unmarshal(body, &confirmation); err != nil {
+		s.fail(w, r, event, http.StatusBadRequest, fmt.Sprintf("invalid confirmation: %s", err))
+		return
+	}
+
+	// the credentials are secret, do not log them
+	event.Payload, _ = json.Marshal(map[string]string{"shopId": confirmation.ShopID, "shopUrl": confirmation.ShopURL})
+	event.ShopID = confirmation.ShopID
+	event.ShopURL = confirmation.ShopURL
+
+	shop, ok := s.opts.Store.Get(confirmation.ShopID)
+	if !ok {
+		s.fail(w, r, event, http.StatusUnauthorized, "the shop did not register before")
+		return
+	}
+
+	event.Verified = Verify(shop.Secret, body, r.Header.Get(HeaderShopSignature))
+	if !event.Verified {
+		s.fail(w, r, event, http.StatusUnauthorized, "invalid shopware-shop-signature")
+		return
+	}
+
+	shop.APIKey = confirmation.APIKey
+	shop.SecretKey = confirmation.SecretKey
+	shop.Confirmed = true
+
+	if confirmation.ShopURL != "" {
+		shop.URL = confirmation.ShopURL
+	}
+
+	if err := s.opts.Store.Put(shop); err != nil {
+		s.fail(w, r, event, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	event.Status = http.StatusNoContent
+	s.emit(r, event)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSigned handles webhooks, action buttons, payments, tax providers and
+// gateways. They all post JSON signed with the shop secret.
+func (s *Server) handleSigned(w http.ResponseWriter, r *http.Request, routes []route) {
+	event := Event{Kind: KindUnknown}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.fail(w, r, event, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	event.Payload = jsonPayload(body)
+
+	var payload struct {
+		Source struct {
+			ShopID string `json:"shopId"`
+			URL    string `json:"url"`
+		} `json:"source"`
+		Data struct {
+			Event  string `json:"event"`
+			Action string `json:"action"`
+		} `json:"data"`
+	}
+
+	_ = json.Unmarshal(body, &payload)
+
+	event.ShopID = payload.Source.ShopID
+	event.ShopURL = payload.Source.URL
+
+	matched, ok := matchRoute(routes, payload.Data.Event, payload.Data.Action)
+	if ok {
+		event.Kind = matched.kind
+		event.Name = matched.name
+	}
+
+	if event.Kind == KindAction && payload.Data.Action != "" {
+		event.Name = payload.Data.Action
+	}
+
+	if event.Kind == KindWebhook && payload.Data.Event != "" {
+		event.Name = payload.Data.Event
+	}
+
+	configured, hasConfigured := s.opts.Responses.route(r.URL.Path)
+	if !ok && hasConfigured {
+		event.Kind = KindRoute
+	}
+
+	if event.Kind == KindUnknown {
+		s.fail(w, r, event, http.StatusNotFound, "no URL of the manifest uses this path")
+		return
+	}
+
+	shop, known := s.opts.Store.Get(event.ShopID)
+	if !known {
+		s.fail(w, r, event, http.StatusUnauthorized, "unknown shop, reinstall the app to register it")
+		return
+	}
+
+	event.Verified = Verify(shop.Secret, body, r.Header.Get(HeaderShopSignature))
+	if !event.Verified {
+		s.fail(w, r, event, http.StatusUnauthorized, "invalid shopware-shop-signature")
+		return
+	}
+
+	switch {
+	case hasConfigured:
+		event.Status = configured.Status
+		s.respondJSON(w, r, event, shop.Secret, configured.Body)
+	case event.Kind == KindAction:
+		s.respondJSON(w, r, event, shop.Secret, s.opts.Responses.action(event.Name))
+	case event.Kind == KindWebhook:
+		event.Status = http.StatusOK
+		s.emit(r, event)
+		w.WriteHeader(http.StatusOK)
+	case event.Kind == KindGateway:
+		s.respondJSON(w, r, event, shop.Secret, []any{})
+	default:
+		s.respondJSON(w, r, event, shop.Secret, map[string]any{})
+	}
+}
+
+// handleIframe handles admin modules and other pages the Administration embeds.
+// The query string is signed with the shop secret.
+func (s *Server) handleIframe(w http.ResponseWriter, r *http.Request, routes []route) {
+	query := r.URL.Query()
+
+	event := Event{
+		Kind:    KindUnknown,
+		ShopID:  query.Get("shop-id"),
+		ShopURL: query.Get("shop-url"),
+		Payload: queryPayload(query),
+	}
+
+	if len(routes) > 0 {
+		event.Kind = routes[0].kind
+		event.Name = routes[0].name
+	}
+
+	configured, hasConfigured := s.opts.Responses.route(r.URL.Path)
+	if event.Kind == KindUnknown && hasConfigured {
+		event.Kind = KindRoute
+	}
+
+	if event.Kind == KindUnknown {
+		s.fail(w, r, event, http.StatusNotFound, "no URL of the manifest uses this path")
+		return
+	}
+
+	if shop, ok := s.opts.Store.Get(event.ShopID); ok {
+		event.Verified = VerifyQuery(shop.Secret, r.URL.RawQuery, query.Get(HeaderShopSignature))
+	}
+
+	if !event.Verified {
+		event.Error = "invalid or missing shopware-shop-signature"
+	}
+
+	if hasConfigured {
+		event.Status = configured.Status
+		s.respondJSON(w, r, event, "", configured.Body)
+
+		return
+	}
+
+	event.Status = http.StatusOK
+	s.emit(r, event)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = iframeTemplate.Execute(w, map[string]any{
+		"Name":     event.Name,
+		"Verified": event.Verified,
+		"Query":    query,
+	})
+}
+
+var iframeTemplate = template.Must(template.New("iframe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>{{ .Name }}</title></head>
+<body style="font-family: sans-serif">
+<h1>{{ .Name }}</h1>
+<p>Served by shopware-cli extension app-serve. Signature: {{ if .Verified }}valid{{ else }}invalid{{ end }}</p>
+<table>{{ range $key, $values := .Query }}<tr><th align="left">{{ $key }}</th><td>{{ range $values }}{{ . }} {{ end }}</td></tr>{{ end }}</table>
+</body>
+</html>
+`))
+
+func matchRoute(routes []route, event, action string) (route, bool) {
+	for _, r := range routes {
+		if r.match != "" && (r.match == event || r.match == action) {
+			return r, true
+		}
+	}
+
+	if len(routes) > 0 {
+		return routes[0], true
+	}
+
+	return route{}, false
+}
+
+// respondJSON writes the body and signs it with the shop secret, if given.
+func (s *Server) respondJSON(w http.ResponseWriter, r *http.Request, event Event, secret string, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		s.fail(w, r, event, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if event.Status == 0 {
+		event.Status = http.StatusOK
+	}
+
+	event.Response = data
+	s.emit(r, event)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if secret != "" {
+		w.Header().Set(HeaderAppSignature, Sign(secret, data))
+	}
+
+	w.WriteHeader(event.Status)
+	_, _ = w.Write(data)
+}
+
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, event Event, status int, message string) {
+	event.Status = status
+	event.Error = message
+	s.emit(r, event)
+
+	http.Error(w, message, status)
+}
+
+func (s *Server) emit(r *http.Request, event Event) {
+	event.Time = time.Now()
+	event.Method = r.Method
+	event.Path = r.URL.Path
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.opts.OnEvent(event)
+}
+
+func jsonPayload(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if json.Valid(body) {
+		return body
+	}
+
+	encoded, _ := json.Marshal(string(body))
+
+	return encoded
+}
+
+func queryPayload(query url.Values) json.RawMessage {
+	flat := make(map[string]string, len(query))
+	for key := range query {
+		flat[key] = query.Get(key)
+	}
+
+	encoded, _ := json.Marshal(flat)
+
+	return encoded
+}
+
+func randomSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
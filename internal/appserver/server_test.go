@@ -0,0 +1,226 @@
+package appserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/extension"
+)
+
+const testAppSecret = "app-secret"
+
+func newTestServer(t *testing.T, responses *Responses) (*Server, *[]Event, *ShopStore) {
+	t.Helper()
+
+	store, err := NewShopStore(filepath.Join(t.TempDir(), "shops.json"))
+	require.NoError(t, err)
+
+	var events []Event
+
+	server, err := New(Options{
+		AppName:   "MyApp",
+		AppSecret: testAppSecret,
+		PublicURL: "http://localhost:8000",
+		Responses: responses,
+		Store:     store,
+		OnEvent: func(event Event) {
+			events = append(events, event)
+		},
+		Manifest: extension.Manifest{
+			Setup: &extension.Setup{RegistrationUrl: "https://my-app.test/app/register"},
+			Admin: &extension.Admin{
+				ActionButtons: []extension.ActionButton{{Action: "sync", Url: "https://my-app.test/app/action"}},
+				Modules:       []extension.AdminModule{{Name: "settings", Source: "https://my-app.test/app/module"}},
+			},
+			Webhooks: &extension.Webhooks{Webhooks: []extension.Webhook{
+				{Name: "orderPlaced", Url: "https://my-app.test/app/webhook", Event: "checkout.order.placed"},
+				{Name: "productWritten", Url: "https://my-app.test/app/webhook", Event: "product.written"},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	return server, &events, store
+}
+
+func register(t *testing.T, server *Server) string {
+	t.Helper()
+
+	query := "shop-id=shop1&shop-url=" + url.QueryEscape("https://shop.test") + "&timestamp=1700000000"
+
+	req := httptest.NewRequest(http.MethodGet, "/app/register?"+query, nil)
+	req.Header.Set(HeaderAppSignature, Sign(testAppSecret, []byte(query)))
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Proof           string `json:"proof"`
+		Secret          string `json:"secret"`
+		ConfirmationURL string `json:"confirmation_url"`
+	}
+
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, Sign(testAppSecret, []byte("shop1https://shop.testMyApp")), response.Proof)
+	assert.Equal(t, "http://localhost:8000"+ConfirmationPath, response.ConfirmationURL)
+
+	body := []byte(`{"apiKey":"key","secretKey":"secret-key","timestamp":"1700000000","shopUrl":"https://shop.test","shopId":"shop1"}`)
+	req = httptest.NewRequest(http.MethodPost, ConfirmationPath, bytes.NewReader(body))
+	req.Header.Set(HeaderShopSignature, Sign(response.Secret, body))
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	return response.Secret
+}
+
+func TestRegistrationHandshake(t *testing.T) {
+	server, events, store := newTestServer(t, nil)
+
+	secret := register(t, server)
+
+	shop, ok := store.Get("shop1")
+	require.True(t, ok)
+	assert.True(t, shop.Confirmed)
+	assert.Equal(t, "key", shop.APIKey)
+	assert.Equal(t, secret, shop.Secret)
+
+	require.Len(t, *events, 2)
+	assert.Equal(t, KindRegistration, (*events)[0].Kind)
+	assert.True(t, (*events)[0].Verified)
+	assert.NotContains(t, string((*events)[0].Response), secret)
+	assert.Equal(t, KindConfirmation, (*events)[1].Kind)
+	assert.NotContains(t, string((*events)[1].Payload), "secret-key")
+
+	// the shops survive a restart
+	reloaded, err := NewShopStore(store.path)
+	require.NoError(t, err)
+	_, ok = reloaded.Get("shop1")
+	assert.True(t, ok)
+}
+
+func TestRegistrationRejectsInvalidSignature(t *testing.T) {
+	server, events, _ := newTestServer(t, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/app/register?shop-id=shop1&shop-url=https://shop.test&timestamp=1", nil)
+	req.Header.Set(HeaderAppSignature, Sign("wrong", []byte(req.URL.RawQuery)))
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Len(t, *events, 1)
+	assert.False(t, (*events)[0].Verified)
+}
+
+func TestWebhookVerification(t *testing.T) {
+	server, events, _ := newTestServer(t, nil)
+	secret := register(t, server)
+	*events = nil
+
+	body := []byte(`{"data":{"payload":[],"event":"product.written"},"source":{"url":"https://shop.test","shopId":"shop1"}}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/app/webhook", bytes.NewReader(body))
+	req.Header.Set(HeaderShopSignature, Sign(secret, body))
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/app/webhook", bytes.NewReader(body))
+	req.Header.Set(HeaderShopSignature, Sign("wrong", body))
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	require.Len(t, *events, 2)
+	assert.Equal(t, KindWebhook, (*events)[0].Kind)
+	assert.Equal(t, "product.written", (*events)[0].Name)
+	assert.True(t, (*events)[0].Verified)
+	assert.JSONEq(t, string(body), string((*events)[0].Payload))
+	assert.False(t, (*events)[1].Verified)
+}
+
+func TestActionButtonResponse(t *testing.T) {
+	server, events, _ := newTestServer(t, &Responses{Actions: map[string]ActionResponse{
+		"sync": {Type: ActionNotification, Status: "error", Message: "Sync failed"},
+	}})
+	secret := register(t, server)
+	*events = nil
+
+	body := []byte(`{"source":{"url":"https://shop.test","shopId":"shop1"},"data":{"ids":["1"],"entity":"product","action":"sync"}}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/app/action", bytes.NewReader(body))
+	req.Header.Set(HeaderShopSignature, Sign(secret, body))
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"actionType":"notification","payload":{"status":"error","message":"Sync failed"}}`, rec.Body.String())
+	assert.True(t, Verify(secret, rec.Body.Bytes(), rec.Header().Get(HeaderAppSignature)))
+
+	require.Len(t, *events, 1)
+	assert.Equal(t, KindAction, (*events)[0].Kind)
+	assert.Equal(t, "sync", (*events)[0].Name)
+}
+
+func TestUnknownShopIsRejected(t *testing.T) {
+	server, _, _ := newTestServer(t, nil)
+
+	body := []byte(`{"source":{"shopId":"unknown"},"data":{"event":"product.written"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/app/webhook", bytes.NewReader(body))
+	req.Header.Set(HeaderShopSignature, Sign("secret", body))
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestUnknownPath(t *testing.T) {
+	server, events, _ := newTestServer(t, nil)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/foo", bytes.NewReader([]byte(`{}`))))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	require.Len(t, *events, 1)
+	assert.Equal(t, KindUnknown, (*events)[0].Kind)
+}
+
+func TestModuleIframe(t *testing.T) {
+	server, events, _ := newTestServer(t, nil)
+	secret := register(t, server)
+	*events = nil
+
+	query := "shop-id=shop1&shop-url=https%3A%2F%2Fshop.test&timestamp=1700000000&sw-version=6.6.0.0"
+	req := httptest.NewRequest(http.MethodGet, "/app/module?"+query+"&shopware-shop-signature="+Sign(secret, []byte(query)), nil)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Signature: valid")
+
+	require.Len(t, *events, 1)
+	assert.Equal(t, KindModule, (*events)[0].Kind)
+	assert.Equal(t, "settings", (*events)[0].Name)
+	assert.True(t, (*events)[0].Verified)
+}
+
+func TestNewRequiresSecret(t *testing.T) {
+	_, err := New(Options{})
+	assert.Error(t, err)
+}
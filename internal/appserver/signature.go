@@ -0,0 +1,60 @@
+package appserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+const (
+	// HeaderShopSignature is sent by the shop, signed with the shop secret.
+	HeaderShopSignature = "shopware-shop-signature"
+	// HeaderAppSignature is sent by the shop during registration signed with
+	// the app secret, and by the app on responses signed with the shop secret.
+	HeaderAppSignature = "shopware-app-signature"
+)
+
+// Sign returns the hex encoded HMAC-SHA256 of payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a hex encoded HMAC-SHA256 signature in constant time.
+func Verify(secret string, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || signature == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// VerifyQuery checks the signature of iframe and registration requests, which
+// sign the raw query string. The signature parameter itself is not signed.
+func VerifyQuery(secret, rawQuery, signature string) bool {
+	return Verify(secret, []byte(stripQueryParameter(rawQuery, HeaderShopSignature)), signature)
+}
+
+func stripQueryParameter(rawQuery, name string) string {
+	parts := strings.Split(rawQuery, "&")
+	kept := parts[:0]
+
+	for _, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if decoded, err := url.QueryUnescape(key); err == nil && decoded == name {
+			continue
+		}
+
+		kept = append(kept, part)
+	}
+
+	return strings.Join(kept, "&")
+}
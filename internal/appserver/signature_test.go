@@ -0,0 +1,26 @@
+package appserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	signature := Sign("secret", []byte(`{"foo":"bar"}`))
+
+	assert.Len(t, signature, 64)
+	assert.True(t, Verify("secret", []byte(`{"foo":"bar"}`), signature))
+	assert.False(t, Verify("other", []byte(`{"foo":"bar"}`), signature))
+	assert.False(t, Verify("secret", []byte(`{"foo":"baz"}`), signature))
+	assert.False(t, Verify("secret", []byte(`{"foo":"bar"}`), ""))
+	assert.False(t, Verify("secret", []byte(`{"foo":"bar"}`), "not-hex"))
+}
+
+func TestVerifyQueryIgnoresSignatureParameter(t *testing.T) {
+	query := "shop-id=123&shop-url=https%3A%2F%2Fshop.test&timestamp=1700000000"
+	signature := Sign("secret", []byte(query))
+
+	assert.True(t, VerifyQuery("secret", query+"&shopware-shop-signature="+signature, signature))
+	assert.False(t, VerifyQuery("secret", query+"&foo=bar&shopware-shop-signature="+signature, signature))
+}
@@ -0,0 +1,86 @@
+package appserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Shop is a shop that registered the app.
+type Shop struct {
+	ID        string `json:"id"`
+	URL       string `json:"url"`
+	Secret    string `json:"secret"`
+	APIKey    string `json:"apiKey,omitempty"`
+	SecretKey string `json:"secretKey,omitempty"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+// ShopStore keeps the registered shops, optionally persisted to a JSON file so
+// a restart of the server does not require reinstalling the app.
+type ShopStore struct {
+	mu    sync.Mutex
+	path  string
+	shops map[string]Shop
+}
+
+// NewShopStore loads the shops from path. An empty path keeps them in memory.
+func NewShopStore(path string) (*ShopStore, error) {
+	store := &ShopStore{path: path, shops: map[string]Shop{}}
+
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store.shops); err != nil {
+		return nil, fmt.Errorf("parse shop store %s: %w", path, err)
+	}
+
+	return store, nil
+}
+
+// Get returns the shop with the given id.
+func (s *ShopStore) Get(id string) (Shop, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shop, ok := s.shops[id]
+
+	return shop, ok
+}
+
+// Put stores the shop and persists the store.
+func (s *ShopStore) Put(shop Shop) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shops[shop.ID] = shop
+
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.shops, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	// contains the API credentials of the shops
+	return os.WriteFile(s.path, data, 0o600)
+}
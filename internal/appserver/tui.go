@@ -0,0 +1,166 @@
+package appserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	"github.com/shopware/shopware-cli/internal/tui"
+)
+
+// EventMsg delivers an Event to the TUI.
+type EventMsg Event
+
+// Model shows the requests of the server as a list with the payload of the
+// selected request.
+type Model struct {
+	title    string
+	events   []Event
+	selected int
+	follow   bool
+	width    int
+	height   int
+}
+
+// NewModel creates the TUI model. The title is shown in the header, e.g. the
+// URL the server listens on.
+func NewModel(title string) *Model {
+	return &Model{title: title, follow: true}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case EventMsg:
+		m.events = append(m.events, Event(msg))
+
+		if m.follow {
+			m.selected = len(m.events) - 1
+		}
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+				m.follow = false
+			}
+		case "down", "j":
+			if m.selected < len(m.events)-1 {
+				m.selected++
+				m.follow = m.selected == len(m.events)-1
+			}
+		case "end", "G":
+			m.selected = max(len(m.events)-1, 0)
+			m.follow = true
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Model) View() tea.View {
+	var b strings.Builder
+
+	b.WriteString(tui.SectionTitleStyle.Render("App backend") + " " + tui.DimStyle.Render(m.title) + "\n\n")
+
+	if len(m.events) == 0 {
+		b.WriteString(tui.DimStyle.Render("  Waiting for requests of the shop. Install or reinstall the app to start the registration.") + "\n")
+	}
+
+	listHeight := 10
+	if m.height > 0 {
+		listHeight = max(m.height/3, 3)
+	}
+
+	start := max(0, m.selected-listHeight+1)
+	end := min(len(m.events), start+listHeight)
+
+	for i := start; i < end; i++ {
+		line := formatEventLine(m.events[i])
+
+		if i == m.selected {
+			line = lipgloss.NewStyle().Background(tui.SelectedBgColor).Bold(true).Render("› " + line)
+		} else {
+			line = "  " + line
+		}
+
+		b.WriteString(line + "\n")
+	}
+
+	if len(m.events) > 0 {
+		b.WriteString("\n" + m.renderDetail(m.events[m.selected]) + "\n")
+	}
+
+	b.WriteString("\n" + tui.DimStyle.Render("↑/↓ select • G follow • q quit"))
+
+	return tea.NewView(b.String())
+}
+
+func formatEventLine(event Event) string {
+	status := lipgloss.NewStyle().Foreground(tui.SuccessColor).Render("✓")
+	if !event.Verified || event.Error != "" {
+		status = lipgloss.NewStyle().Foreground(tui.ErrorColor).Render("✗")
+	}
+
+	name := event.Name
+	if name == "" {
+		name = event.Path
+	}
+
+	return fmt.Sprintf("%s %s %-12s %-4s %-40s %d", event.Time.Format("15:04:05"), status, event.Kind, event.Method, name, event.Status)
+}
+
+func (m *Model) renderDetail(event Event) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s\n", tui.BoldStyle.Render(event.Method), event.Path)
+
+	if event.ShopID != "" {
+		fmt.Fprintf(&b, "Shop: %s %s\n", event.ShopID, tui.DimStyle.Render(event.ShopURL))
+	}
+
+	if event.Error != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(tui.ErrorColor).Render(event.Error) + "\n")
+	}
+
+	maxLines := 20
+	if m.height > 0 {
+		maxLines = max(m.height/2-6, 5)
+	}
+
+	if len(event.Payload) > 0 {
+		b.WriteString("\n" + tui.BoldStyle.Render("Payload") + "\n" + indentJSON(event.Payload, maxLines) + "\n")
+	}
+
+	if len(event.Response) > 0 {
+		b.WriteString("\n" + tui.BoldStyle.Render("Response") + "\n" + indentJSON(event.Response, maxLines) + "\n")
+	}
+
+	return tui.RenderPanel(string(event.Kind), b.String(), tui.BrandColor)
+}
+
+func indentJSON(data json.RawMessage, maxLines int) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return string(data)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines], tui.DimStyle.Render(fmt.Sprintf("… %d more lines", len(lines)-maxLines)))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,233 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shyim/go-composer"
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/npm"
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	// SeverityUnknown is used by the FriendsOfPHP database, which has no severities
+	SeverityUnknown = "unknown"
+)
+
+var severityRank = map[string]int{
+	SeverityCritical: 4,
+	SeverityHigh:     3,
+	SeverityUnknown:  2,
+	SeverityMedium:   2,
+	SeverityLow:      1,
+}
+
+// ignoreDateFormat is the format of the until date of an ignore.
+const ignoreDateFormat = "2006-01-02"
+
+// Dependency is an installed package of a lock file.
+type Dependency struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	// Source is the lock file relative to the project root
+	Source string `json:"source"`
+}
+
+// Ignore suppresses an advisory until a date.
+type Ignore struct {
+	// ID matches the advisory ID or one of its aliases
+	ID string
+	// Package limits the ignore to a package, empty matches every package
+	Package string
+	// Until is the last day the advisory is ignored, formatted as YYYY-MM-DD, empty never expires
+	Until string
+}
+
+// Finding is a dependency affected by an advisory.
+type Finding struct {
+	Advisory   Advisory   `json:"advisory"`
+	Dependency Dependency `json:"dependency"`
+	// IgnoreExpired is the date an ignore of the finding expired
+	IgnoreExpired string `json:"ignoreExpired,omitempty"`
+}
+
+// CheckResult converts the finding for the validation reporters. Low
+// severities are warnings, everything else is an error.
+func (f Finding) CheckResult() validation.CheckResult {
+	severity := validation.SeverityError
+	if f.Advisory.Severity == SeverityLow {
+		severity = validation.SeverityWarning
+	}
+
+	message := fmt.Sprintf("%s %s: %s", f.Dependency.Name, f.Dependency.Version, f.Advisory.Title)
+	if f.Advisory.Severity != SeverityUnknown {
+		message = fmt.Sprintf("[%s] %s", f.Advisory.Severity, message)
+	}
+
+	var tips []string
+
+	if len(f.Advisory.Fixed) > 0 {
+		tips = append(tips, fmt.Sprintf("Fixed in %s", strings.Join(f.Advisory.Fixed, ", ")))
+	}
+
+	if f.Advisory.Link != "" {
+		tips = append(tips, f.Advisory.Link)
+	}
+
+	if f.IgnoreExpired != "" {
+		tips = append(tips, fmt.Sprintf("The ignore of this advisory expired on %s", f.IgnoreExpired))
+	}
+
+	return validation.CheckResult{
+		Path:       f.Dependency.Source,
+		Identifier: f.Advisory.ID,
+		Message:    message,
+		Severity:   severity,
+		Tip:        strings.Join(tips, ". "),
+	}
+}
+
+// Audit returns the findings of the dependencies sorted by severity, the
+// ignores apply until their date has passed.
+func (d *Database) Audit(dependencies []Dependency, ignores []Ignore, now time.Time) ([]Finding, error) {
+	today := now.Format(ignoreDateFormat)
+
+	for _, ignore := range ignores {
+		if ignore.ID == "" {
+			return nil, fmt.Errorf("an audit ignore has no id")
+		}
+
+		if ignore.Until != "" {
+			if _, err := time.Parse(ignoreDateFormat, ignore.Until); err != nil {
+				return nil, fmt.Errorf("the until date %q of the audit ignore %s is no YYYY-MM-DD date", ignore.Until, ignore.ID)
+			}
+		}
+	}
+
+	var findings []Finding
+
+	for _, dependency := range dependencies {
+		installed, err := version.NewVersion(dependency.Version)
+		if err != nil {
+			// branches like dev-main cannot be matched
+			continue
+		}
+
+		for _, advisory := range d.Advisories(dependency.Ecosystem, dependency.Name) {
+			if !isAffected(advisory, installed) {
+				continue
+			}
+
+			finding := Finding{Advisory: advisory, Dependency: dependency}
+			ignored := false
+
+			for _, ignore := range ignores {
+				if !slices.Contains(advisory.Identifiers(), ignore.ID) || (ignore.Package != "" && ignore.Package != dependency.Name) {
+					continue
+				}
+
+				if ignore.Until == "" || ignore.Until >= today {
+					ignored = true
+					break
+				}
+
+				finding.IgnoreExpired = ignore.Until
+			}
+
+			if !ignored {
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	slices.SortFunc(findings, func(a, b Finding) int {
+		if rank := severityRank[b.Advisory.Severity] - severityRank[a.Advisory.Severity]; rank != 0 {
+			return rank
+		}
+
+		if a.Dependency.Name != b.Dependency.Name {
+			return strings.Compare(a.Dependency.Name, b.Dependency.Name)
+		}
+
+		if a.Dependency.Source != b.Dependency.Source {
+			return strings.Compare(a.Dependency.Source, b.Dependency.Source)
+		}
+
+		return strings.Compare(a.Advisory.ID, b.Advisory.ID)
+	})
+
+	return findings, nil
+}
+
+func isAffected(advisory Advisory, installed *version.Version) bool {
+	for _, affected := range advisory.Affected {
+		constraint, err := version.NewConstraint(affected)
+		if err != nil {
+			continue
+		}
+
+		if constraint.Check(installed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CollectDependencies reads the composer.lock and the package-lock.json files
+// of the project and of the administration and storefront of its extensions.
+func CollectDependencies(ctx context.Context, root string, includeDev bool) ([]Dependency, error) {
+	var dependencies []Dependency
+
+	lockPath := filepath.Join(root, "composer.lock")
+
+	if _, err := os.Stat(lockPath); err == nil {
+		lock, err := composer.ReadLock(lockPath)
+		if err != nil {
+			return nil, fmt.Errorf("read composer.lock: %w", err)
+		}
+
+		if lock != nil {
+			packages := lock.Packages
+			if includeDev {
+				packages = append(packages, lock.PackagesDev...)
+			}
+
+			for _, pkg := range packages {
+				dependencies = append(dependencies, Dependency{Ecosystem: EcosystemComposer, Name: pkg.Name, Version: pkg.Version, Source: "composer.lock"})
+			}
+		}
+	}
+
+	for _, packageLock := range extension.FindPackageLockFilesOfProject(ctx, root) {
+		packages, err := npm.ReadPackageLock(packageLock)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", packageLock, err)
+		}
+
+		source, _ := filepath.Rel(root, packageLock)
+
+		for _, pkg := range packages {
+			if pkg.Dev && !includeDev {
+				continue
+			}
+
+			dependencies = append(dependencies, Dependency{Ecosystem: EcosystemNpm, Name: pkg.Name, Version: pkg.Version, Source: filepath.ToSlash(source)})
+		}
+	}
+
+	return dependencies, nil
+}
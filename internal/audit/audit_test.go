@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+func testDatabase() *Database {
+	db := &Database{advisories: map[string][]Advisory{}}
+
+	db.add(Advisory{ID: "CVE-2022-24894", Title: "Cookie headers", Severity: SeverityUnknown, Ecosystem: EcosystemComposer, Package: "symfony/http-kernel", Affected: []string{">=5.0.0,<5.4.20"}, Fixed: []string{"5.4.20"}})
+	db.add(Advisory{ID: "GHSA-low", Title: "Minor", Severity: SeverityLow, Ecosystem: EcosystemNpm, Package: "lodash", Affected: []string{">=0,<4.17.21"}})
+	db.add(Advisory{ID: "GHSA-35jh-r3h4-6jhm", Aliases: []string{"CVE-2021-23337"}, Title: "Command Injection", Severity: SeverityCritical, Ecosystem: EcosystemNpm, Package: "lodash", Affected: []string{">=0,<4.17.21"}, Link: "https://example.com"})
+
+	return db
+}
+
+func TestAuditSortsBySeverity(t *testing.T) {
+	dependencies := []Dependency{
+		{Ecosystem: EcosystemComposer, Name: "symfony/http-kernel", Version: "v5.4.1", Source: "composer.lock"},
+		{Ecosystem: EcosystemComposer, Name: "symfony/yaml", Version: "v5.4.1", Source: "composer.lock"},
+		{Ecosystem: EcosystemNpm, Name: "lodash", Version: "4.17.20", Source: "custom/plugins/Foo/src/Resources/app/storefront/package-lock.json"},
+		{Ecosystem: EcosystemNpm, Name: "lodash", Version: "4.17.21", Source: "package-lock.json"},
+		{Ecosystem: EcosystemComposer, Name: "shopware/core", Version: "dev-trunk", Source: "composer.lock"},
+	}
+
+	findings, err := testDatabase().Audit(dependencies, nil, time.Now())
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		ids = append(ids, finding.Advisory.ID)
+	}
+
+	assert.Equal(t, []string{"GHSA-35jh-r3h4-6jhm", "CVE-2022-24894", "GHSA-low"}, ids)
+
+	result := findings[1].CheckResult()
+	assert.Equal(t, validation.SeverityError, result.Severity)
+	assert.Equal(t, "composer.lock", result.Path)
+	assert.Equal(t, "symfony/http-kernel v5.4.1: Cookie headers", result.Message)
+	assert.Equal(t, "Fixed in 5.4.20", result.Tip)
+
+	assert.Equal(t, validation.SeverityWarning, findings[2].CheckResult().Severity)
+	assert.Equal(t, "[critical] lodash 4.17.20: Command Injection", findings[0].CheckResult().Message)
+}
+
+func TestAuditIgnores(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	dependencies := []Dependency{
+		{Ecosystem: EcosystemNpm, Name: "lodash", Version: "4.17.20", Source: "package-lock.json"},
+		{Ecosystem: EcosystemComposer, Name: "symfony/http-kernel", Version: "v5.4.1", Source: "composer.lock"},
+	}
+
+	findings, err := testDatabase().Audit(dependencies, []Ignore{
+		// an alias matches
+		{ID: "CVE-2021-23337", Until: "2026-05-01"},
+		{ID: "GHSA-low", Package: "other"},
+		{ID: "CVE-2022-24894", Until: "2026-04-30"},
+	}, now)
+	require.NoError(t, err)
+
+	require.Len(t, findings, 2)
+	assert.Equal(t, "CVE-2022-24894", findings[0].Advisory.ID)
+	assert.Equal(t, "2026-04-30", findings[0].IgnoreExpired)
+	assert.Contains(t, findings[0].CheckResult().Tip, "expired on 2026-04-30")
+	assert.Equal(t, "GHSA-low", findings[1].Advisory.ID)
+
+	_, err = testDatabase().Audit(dependencies, []Ignore{{ID: "GHSA-low", Until: "next week"}}, now)
+	assert.ErrorContains(t, err, "no YYYY-MM-DD date")
+}
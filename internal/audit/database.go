@@ -0,0 +1,359 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EcosystemComposer = "composer"
+	EcosystemNpm      = "npm"
+)
+
+// osvEcosystems maps the ecosystems of the GitHub advisory database.
+var osvEcosystems = map[string]string{
+	"Packagist": EcosystemComposer,
+	"npm":       EcosystemNpm,
+}
+
+// Advisory is a vulnerability of a package.
+type Advisory struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Title     string   `json:"title"`
+	Link      string   `json:"link,omitempty"`
+	Severity  string   `json:"severity"`
+	Ecosystem string   `json:"ecosystem"`
+	Package   string   `json:"package"`
+	// Affected are version constraints, the package is vulnerable when one matches
+	Affected []string `json:"affected"`
+	Fixed    []string `json:"fixed,omitempty"`
+}
+
+// Identifiers returns the ID and the aliases of the advisory.
+func (a Advisory) Identifiers() []string {
+	return append([]string{a.ID}, a.Aliases...)
+}
+
+// Database holds advisories by ecosystem and package.
+type Database struct {
+	advisories map[string][]Advisory
+}
+
+// Len returns the number of advisories.
+func (d *Database) Len() int {
+	count := 0
+	for _, advisories := range d.advisories {
+		count += len(advisories)
+	}
+
+	return count
+}
+
+// Advisories returns the advisories of the package.
+func (d *Database) Advisories(ecosystem, name string) []Advisory {
+	return d.advisories[ecosystem+":"+strings.ToLower(name)]
+}
+
+// add stores the advisory unless an advisory of the package shares an identifier,
+// the FriendsOfPHP and GitHub databases describe the same vulnerabilities.
+func (d *Database) add(advisory Advisory) {
+	key := advisory.Ecosystem + ":" + strings.ToLower(advisory.Package)
+
+	for _, existing := range d.advisories[key] {
+		for _, id := range advisory.Identifiers() {
+			if slices.Contains(existing.Identifiers(), id) {
+				return
+			}
+		}
+	}
+
+	d.advisories[key] = append(d.advisories[key], advisory)
+}
+
+// LoadDatabase reads the advisories of a file or a directory. YAML files use
+// the FriendsOfPHP security-advisories format, JSON files the OSV format of the
+// GitHub advisory database, either a single advisory or a list of them.
+func LoadDatabase(path string) (*Database, error) {
+	db := &Database{advisories: map[string][]Advisory{}}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open advisory database: %w", err)
+	}
+
+	if !stat.IsDir() {
+		if err := db.loadFile(path); err != nil {
+			return nil, err
+		}
+
+		return db, nil
+	}
+
+	err = filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if file != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		return db.loadFile(file)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (d *Database) loadFile(file string) error {
+	var advisories []Advisory
+	var err error
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		advisories, err = readFriendsOfPHPAdvisory(file)
+	case ".json":
+		advisories, err = readOSVAdvisories(file)
+	default:
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot read advisory %s: %w", file, err)
+	}
+
+	for _, advisory := range advisories {
+		d.add(advisory)
+	}
+
+	return nil
+}
+
+type friendsOfPHPAdvisory struct {
+	Title     string `yaml:"title"`
+	Link      string `yaml:"link"`
+	CVE       string `yaml:"cve"`
+	Reference string `yaml:"reference"`
+	Branches  map[string]struct {
+		Versions []string `yaml:"versions"`
+	} `yaml:"branches"`
+}
+
+func readFriendsOfPHPAdvisory(file string) ([]Advisory, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed friendsOfPHPAdvisory
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		return nil, err
+	}
+
+	// config files like .php-cs-fixer.yml live next to the advisories
+	if !strings.HasPrefix(parsed.Reference, "composer://") {
+		return nil, nil
+	}
+
+	advisory := Advisory{
+		ID:        parsed.CVE,
+		Title:     parsed.Title,
+		Link:      parsed.Link,
+		Severity:  SeverityUnknown,
+		Ecosystem: EcosystemComposer,
+		Package:   strings.TrimPrefix(parsed.Reference, "composer://"),
+	}
+
+	if advisory.ID == "" {
+		advisory.ID = advisory.Package + "/" + strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+
+	for _, branch := range parsed.Branches {
+		constraints := make([]string, 0, len(branch.Versions))
+
+		for _, v := range branch.Versions {
+			v = strings.ReplaceAll(v, " ", "")
+			constraints = append(constraints, v)
+
+			if strings.HasPrefix(v, "<") && !strings.HasPrefix(v, "<=") {
+				advisory.Fixed = append(advisory.Fixed, strings.TrimPrefix(v, "<"))
+			}
+		}
+
+		if len(constraints) > 0 {
+			advisory.Affected = append(advisory.Affected, strings.Join(constraints, ","))
+		}
+	}
+
+	slices.Sort(advisory.Affected)
+	slices.Sort(advisory.Fixed)
+
+	return []Advisory{advisory}, nil
+}
+
+type osvAdvisory struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases"`
+	Summary   string   `json:"summary"`
+	Withdrawn string   `json:"withdrawn"`
+	Affected  []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string              `json:"type"`
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+		Versions []string `json:"versions"`
+	} `json:"affected"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+func readOSVAdvisories(file string) ([]Advisory, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed []osvAdvisory
+
+	if strings.HasPrefix(strings.TrimSpace(string(content)), "[") {
+		err = json.Unmarshal(content, &parsed)
+	} else {
+		var single osvAdvisory
+		err = json.Unmarshal(content, &single)
+		parsed = append(parsed, single)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var advisories []Advisory
+
+	for _, osv := range parsed {
+		if osv.ID == "" || osv.Withdrawn != "" {
+			continue
+		}
+
+		link := ""
+		for _, reference := range osv.References {
+			if link == "" || reference.Type == "ADVISORY" {
+				link = reference.URL
+			}
+		}
+
+		for _, affected := range osv.Affected {
+			ecosystem, ok := osvEcosystems[affected.Package.Ecosystem]
+			if !ok {
+				continue
+			}
+
+			advisory := Advisory{
+				ID:        osv.ID,
+				Aliases:   osv.Aliases,
+				Title:     osv.Summary,
+				Link:      link,
+				Severity:  normalizeSeverity(osv.DatabaseSpecific.Severity),
+				Ecosystem: ecosystem,
+				Package:   affected.Package.Name,
+			}
+
+			for _, r := range affected.Ranges {
+				if r.Type != "ECOSYSTEM" && r.Type != "SEMVER" {
+					continue
+				}
+
+				affectedRanges, fixed := osvRangeConstraints(r.Events)
+				advisory.Affected = append(advisory.Affected, affectedRanges...)
+				advisory.Fixed = append(advisory.Fixed, fixed...)
+			}
+
+			for _, v := range affected.Versions {
+				advisory.Affected = append(advisory.Affected, "=="+v)
+			}
+
+			if len(advisory.Affected) > 0 {
+				advisories = append(advisories, advisory)
+			}
+		}
+	}
+
+	return advisories, nil
+}
+
+// osvRangeConstraints converts the introduced, fixed and last_affected events
+// of a range into constraints.
+func osvRangeConstraints(events []map[string]string) ([]string, []string) {
+	var constraints, fixed []string
+
+	lower := ""
+	open := false
+
+	for _, event := range events {
+		if introduced, ok := event["introduced"]; ok {
+			lower = ">=0"
+			if introduced != "0" {
+				lower = ">=" + introduced
+			}
+
+			open = true
+
+			continue
+		}
+
+		if v, ok := event["fixed"]; ok && open {
+			constraints = append(constraints, lower+",<"+v)
+			fixed = append(fixed, v)
+			open = false
+		}
+
+		if v, ok := event["last_affected"]; ok && open {
+			constraints = append(constraints, lower+",<="+v)
+			open = false
+		}
+	}
+
+	if open {
+		constraints = append(constraints, lower)
+	}
+
+	return constraints, fixed
+}
+
+// normalizeSeverity returns low, medium, high, critical or unknown.
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "low":
+		return SeverityLow
+	case "moderate", "medium":
+		return SeverityMedium
+	case "high":
+		return SeverityHigh
+	case "critical":
+		return SeverityCritical
+	default:
+		return SeverityUnknown
+	}
+}
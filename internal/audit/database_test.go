@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const friendsOfPHPAdvisoryYAML = `title: 'CVE-2022-24894: Prevent storing cookie headers in HttpCache'
+link: https://symfony.com/cve-2022-24894
+cve: CVE-2022-24894
+branches:
+    4.4.x:
+        time: 2023-02-01 08:00:00
+        versions: ['>=2.0.0', '<4.4.50']
+    5.4.x:
+        time: 2023-02-01 08:00:00
+        versions: ['>=5.0.0', '<5.4.20']
+reference: composer://symfony/http-kernel
+`
+
+const osvAdvisoryJSON = `{
+  "id": "GHSA-h75v-3vvj-5mfj",
+  "aliases": ["CVE-2022-24894"],
+  "summary": "Symfony storing cookie headers in HttpCache",
+  "affected": [
+    {
+      "package": {"ecosystem": "Packagist", "name": "symfony/http-kernel"},
+      "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "5.0.0"}, {"fixed": "5.4.20"}]}]
+    }
+  ],
+  "database_specific": {"severity": "MODERATE"}
+}`
+
+const osvNpmAdvisories = `[
+  {
+    "id": "GHSA-35jh-r3h4-6jhm",
+    "aliases": ["CVE-2021-23337"],
+    "summary": "Command Injection in lodash",
+    "references": [{"type": "WEB", "url": "https://example.com"}, {"type": "ADVISORY", "url": "https://nvd.nist.gov/vuln/detail/CVE-2021-23337"}],
+    "affected": [
+      {
+        "package": {"ecosystem": "npm", "name": "lodash"},
+        "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+      },
+      {
+        "package": {"ecosystem": "PyPI", "name": "lodash"},
+        "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}]}]
+      }
+    ],
+    "database_specific": {"severity": "HIGH"}
+  },
+  {
+    "id": "GHSA-withdrawn",
+    "withdrawn": "2023-01-01T00:00:00Z",
+    "affected": [{"package": {"ecosystem": "npm", "name": "lodash"}, "versions": ["1.0.0"]}]
+  }
+]`
+
+func TestLoadDatabaseDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "symfony", "http-kernel"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "github", "2023"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "symfony", "http-kernel", "CVE-2022-24894.yaml"), []byte(friendsOfPHPAdvisoryYAML), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "github", "2023", "GHSA-h75v-3vvj-5mfj.json"), []byte(osvAdvisoryJSON), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".php-cs-fixer.yml"), []byte("rules: []"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Advisories"), 0o644))
+
+	db, err := LoadDatabase(dir)
+	require.NoError(t, err)
+
+	// both files describe CVE-2022-24894, the directories are read in lexical order
+	advisories := db.Advisories(EcosystemComposer, "symfony/http-kernel")
+	require.Len(t, advisories, 1)
+	assert.Equal(t, 1, db.Len())
+	assert.Equal(t, "GHSA-h75v-3vvj-5mfj", advisories[0].ID)
+	assert.Equal(t, []string{">=5.0.0,<5.4.20"}, advisories[0].Affected)
+	assert.Equal(t, SeverityMedium, advisories[0].Severity)
+}
+
+func TestReadFriendsOfPHPAdvisory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "CVE-2022-24894.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(friendsOfPHPAdvisoryYAML), 0o644))
+
+	advisories, err := readFriendsOfPHPAdvisory(file)
+	require.NoError(t, err)
+	require.Len(t, advisories, 1)
+
+	assert.Equal(t, "CVE-2022-24894", advisories[0].ID)
+	assert.Equal(t, "symfony/http-kernel", advisories[0].Package)
+	assert.Equal(t, []string{">=2.0.0,<4.4.50", ">=5.0.0,<5.4.20"}, advisories[0].Affected)
+	assert.Equal(t, []string{"4.4.50", "5.4.20"}, advisories[0].Fixed)
+	assert.Equal(t, SeverityUnknown, advisories[0].Severity)
+}
+
+func TestLoadDatabaseFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "npm.json")
+	require.NoError(t, os.WriteFile(file, []byte(osvNpmAdvisories), 0o644))
+
+	db, err := LoadDatabase(file)
+	require.NoError(t, err)
+
+	advisories := db.Advisories(EcosystemNpm, "lodash")
+	require.Len(t, advisories, 1)
+
+	assert.Equal(t, "GHSA-35jh-r3h4-6jhm", advisories[0].ID)
+	assert.Equal(t, SeverityHigh, advisories[0].Severity)
+	assert.Equal(t, []string{">=0,<4.17.21"}, advisories[0].Affected)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2021-23337", advisories[0].Link)
+}
+
+func TestLoadDatabaseMissing(t *testing.T) {
+	_, err := LoadDatabase(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "cannot open advisory database")
+}
+
+func TestOSVRangeConstraints(t *testing.T) {
+	constraints, fixed := osvRangeConstraints([]map[string]string{
+		{"introduced": "1.0.0"},
+		{"fixed": "1.2.0"},
+		{"introduced": "2.0.0"},
+		{"last_affected": "2.1.0"},
+		{"introduced": "3.0.0"},
+	})
+
+	assert.Equal(t, []string{">=1.0.0,<1.2.0", ">=2.0.0,<=2.1.0", ">=3.0.0"}, constraints)
+	assert.Equal(t, []string{"1.2.0"}, fixed)
+}
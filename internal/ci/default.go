@@ -16,15 +16,15 @@ type DefaultCiSection struct {
 }
 
 // Section starts a new log section.
-func (d *DefaultCi) Section(ctx context.Context, name string) Section {
+func (d *DefaultCi) Section(ctx context.Context, name string) (context.Context, Section) {
 	logging.FromContext(ctx).Infof("Starting %s", name)
-	return DefaultCiSection{
+	return ctx, DefaultCiSection{
 		name:  name,
 		start: time.Now(),
 	}
 }
 
 // SectionEnd ends the current log section.
-func (d DefaultCiSection) End(ctx context.Context) {
+func (d DefaultCiSection) End(ctx context.Context, _ error) {
 	logging.FromContext(ctx).Infof("%s ended after %s", d.name, time.Since(d.start))
 }
@@ -17,15 +17,15 @@ type GithubActionsSection struct {
 }
 
 // SectionStart starts a new log section.
-func (g *GithubActions) Section(ctx context.Context, name string) Section {
+func (g *GithubActions) Section(ctx context.Context, name string) (context.Context, Section) {
 	fmt.Printf("::group::%s\n", name)
-	return GithubActionsSection{
+	return ctx, GithubActionsSection{
 		name:  name,
 		start: time.Now(),
 	}
 }
 
-func (s GithubActionsSection) End(ctx context.Context) {
+func (s GithubActionsSection) End(ctx context.Context, _ error) {
 	duration := time.Since(s.start)
 	logging.FromContext(ctx).Infof("%s took %s", s.name, duration)
 	fmt.Printf("::endgroup::\n")
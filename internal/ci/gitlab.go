@@ -25,17 +25,17 @@ func gitlabSectionId(name string) string {
 }
 
 // SectionStart starts a new log section.
-func (g *GitlabCi) Section(ctx context.Context, name string) Section {
+func (g *GitlabCi) Section(ctx context.Context, name string) (context.Context, Section) {
 	sectionId := gitlabSectionId(name)
 	fmt.Printf("section_start:%d:%s\r\x1b[0K%s\n", time.Now().Unix(), sectionId, name)
-	return GitlabCiSection{
+	return ctx, GitlabCiSection{
 		name:  name,
 		start: time.Now(),
 	}
 }
 
 // SectionEnd ends the current log section.
-func (g GitlabCiSection) End(ctx context.Context) {
+func (g GitlabCiSection) End(ctx context.Context, _ error) {
 	sectionId := gitlabSectionId(g.name)
 	logging.FromContext(ctx).Infof("%s took %s", g.name, time.Since(g.start))
 	fmt.Printf("section_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), sectionId)
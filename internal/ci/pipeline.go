@@ -0,0 +1,396 @@
+package ci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/internal/tracing"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const (
+	StepStatusSucceeded = "succeeded"
+	StepStatusSkipped   = "skipped"
+	StepStatusFailed    = "failed"
+)
+
+// Step is a named unit of work of a Pipeline.
+type Step struct {
+	// Name identifies the step for --from, --only, hooks and the report
+	Name string
+	// Needs lists the steps which have to run before this step
+	Needs []string
+	// Inputs are files or directories relative to the pipeline root. When all of
+	// them exist, their content forms the cache key of the step outputs.
+	Inputs []string
+	// CacheKey contains additional values which change the outputs, like command flags
+	CacheKey []string
+	// Outputs are directories relative to the pipeline root which are restored
+	// from the cache instead of running the step when the inputs are unchanged
+	Outputs []string
+	// Disabled contains the reason why the step does not run, e.g. the configuration turned it off
+	Disabled string
+	Run      func(ctx context.Context) error
+}
+
+// StepHooks are shell commands running around a step.
+type StepHooks struct {
+	Pre  []string
+	Post []string
+}
+
+// Pipeline runs steps in dependency order and skips steps whose inputs did not change.
+type Pipeline struct {
+	Root  string
+	Steps []Step
+	// Cache stores the outputs of the steps, nil disables step caching
+	Cache system.Cache
+	// Hooks are keyed by step name
+	Hooks map[string]StepHooks
+	// RunHooks executes the given hook commands
+	RunHooks func(ctx context.Context, sectionName string, hooks []string) error
+}
+
+// RunOptions select which steps of the pipeline run.
+type RunOptions struct {
+	// From skips all steps before the given step
+	From string
+	// Only runs just the given step
+	Only string
+}
+
+type StepReport struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Reason     string `json:"reason,omitempty"`
+	Error      string `json:"error,omitempty"`
+	CacheKey   string `json:"cache_key,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is the structured result of a pipeline run.
+type Report struct {
+	Status     string       `json:"status"`
+	DurationMS int64        `json:"duration_ms"`
+	Steps      []StepReport `json:"steps"`
+}
+
+// StepNames returns the names of all steps in execution order.
+func (p *Pipeline) StepNames() ([]string, error) {
+	steps, err := p.sortedSteps()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(steps))
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+
+	return names, nil
+}
+
+// Run executes the pipeline. The returned report is also filled when a step fails.
+func (p *Pipeline) Run(ctx context.Context, opts RunOptions) (*Report, error) {
+	steps, err := p.sortedSteps()
+	if err != nil {
+		return nil, err
+	}
+
+	names, _ := p.StepNames()
+
+	if opts.From != "" && opts.Only != "" {
+		return nil, fmt.Errorf("--from and --only cannot be used together")
+	}
+
+	for _, name := range []string{opts.From, opts.Only} {
+		if name != "" && !slices.Contains(names, name) {
+			return nil, fmt.Errorf("unknown step %q, possible steps: %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	for name := range p.Hooks {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("hooks configured for unknown step %q, possible steps: %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	start := time.Now()
+	report := &Report{Status: StepStatusSucceeded}
+	selected := opts.From == ""
+
+	var runErr error
+
+	for _, step := range steps {
+		if step.Name == opts.From {
+			selected = true
+		}
+
+		stepReport := StepReport{Name: step.Name, Status: StepStatusSkipped}
+
+		switch {
+		case runErr != nil:
+			stepReport.Reason = "a previous step failed"
+		case opts.Only != "" && step.Name != opts.Only:
+			stepReport.Reason = "not selected by --only"
+		case !selected:
+			stepReport.Reason = "before --from " + opts.From
+		case step.Disabled != "":
+			stepReport.Reason = step.Disabled
+		default:
+			stepReport, runErr = p.runStep(ctx, step)
+			if runErr != nil {
+				report.Status = StepStatusFailed
+			}
+		}
+
+		if stepReport.Status == StepStatusSkipped {
+			logging.FromContext(ctx).Debugf("Skipping step %s: %s", step.Name, stepReport.Reason)
+		}
+
+		report.Steps = append(report.Steps, stepReport)
+	}
+
+	report.DurationMS = time.Since(start).Milliseconds()
+
+	return report, runErr
+}
+
+func (p *Pipeline) runStep(ctx context.Context, step Step) (StepReport, error) {
+	stepReport := StepReport{Name: step.Name}
+	start := time.Now()
+
+	ctx, span := tracing.Start(ctx, "step "+step.Name, tracing.String("ci.step", step.Name))
+
+	cacheKey, err := p.cacheKey(step)
+	if err != nil {
+		return p.failStep(span, stepReport, start, err)
+	}
+
+	stepReport.CacheKey = cacheKey
+
+	if cacheKey != "" {
+		restored, err := p.restoreOutputs(ctx, step, cacheKey)
+		if err != nil {
+			return p.failStep(span, stepReport, start, err)
+		}
+
+		if restored {
+			logging.FromContext(ctx).Infof("Inputs of step %s are unchanged, restored %s from cache", step.Name, strings.Join(step.Outputs, ", "))
+
+			stepReport.Status = StepStatusSkipped
+			stepReport.Reason = "inputs unchanged, outputs restored from cache"
+			stepReport.DurationMS = time.Since(start).Milliseconds()
+			span.SetAttributes(tracing.Bool("ci.step.cached", true))
+			span.End(nil)
+
+			return stepReport, nil
+		}
+	}
+
+	hooks := p.Hooks[step.Name]
+
+	if len(hooks.Pre) > 0 {
+		if err := p.RunHooks(ctx, fmt.Sprintf("Running pre-%s hooks", step.Name), hooks.Pre); err != nil {
+			return p.failStep(span, stepReport, start, err)
+		}
+	}
+
+	if err := step.Run(ctx); err != nil {
+		return p.failStep(span, stepReport, start, err)
+	}
+
+	if len(hooks.Post) > 0 {
+		if err := p.RunHooks(ctx, fmt.Sprintf("Running post-%s hooks", step.Name), hooks.Post); err != nil {
+			return p.failStep(span, stepReport, start, err)
+		}
+	}
+
+	if cacheKey != "" {
+		for _, output := range step.Outputs {
+			if err := p.Cache.StoreFolderCache(ctx, outputCacheKey(cacheKey, output), filepath.Join(p.Root, output)); err != nil {
+				logging.FromContext(ctx).Warnf("Could not cache %s of step %s: %v", output, step.Name, err)
+			}
+		}
+	}
+
+	stepReport.Status = StepStatusSucceeded
+	stepReport.DurationMS = time.Since(start).Milliseconds()
+	span.End(nil)
+
+	return stepReport, nil
+}
+
+func (p *Pipeline) failStep(span *tracing.Span, stepReport StepReport, start time.Time, err error) (StepReport, error) {
+	stepReport.Status = StepStatusFailed
+	stepReport.Error = err.Error()
+	stepReport.DurationMS = time.Since(start).Milliseconds()
+	span.End(err)
+
+	return stepReport, fmt.Errorf("step %s: %w", stepReport.Name, err)
+}
+
+// restoreOutputs replaces the outputs of the step with the cached ones. It only
+// touches the project when all outputs are cached.
+func (p *Pipeline) restoreOutputs(ctx context.Context, step Step, cacheKey string) (bool, error) {
+	cachedPaths := make([]string, 0, len(step.Outputs))
+
+	for _, output := range step.Outputs {
+		cachedPath, err := p.Cache.GetFolderCachePath(ctx, outputCacheKey(cacheKey, output))
+		if errors.Is(err, system.ErrCacheNotFound) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		cachedPaths = append(cachedPaths, cachedPath)
+	}
+
+	for i, output := range step.Outputs {
+		target := filepath.Join(p.Root, output)
+
+		if err := os.RemoveAll(target); err != nil {
+			return false, err
+		}
+
+		if err := system.CopyFiles(cachedPaths[i], target); err != nil {
+			return false, fmt.Errorf("restore %s from cache: %w", output, err)
+		}
+	}
+
+	return true, nil
+}
+
+// cacheKey hashes the inputs of the step. It returns an empty key when the step
+// is not cacheable or one of the inputs does not exist.
+func (p *Pipeline) cacheKey(step Step) (string, error) {
+	if p.Cache == nil || len(step.Outputs) == 0 || len(step.Inputs) == 0 {
+		return "", nil
+	}
+
+	hash := sha256.New()
+
+	for _, value := range step.CacheKey {
+		_, _ = io.WriteString(hash, value+"\x00")
+	}
+
+	for _, input := range step.Inputs {
+		inputPath := filepath.Join(p.Root, input)
+
+		if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+			return "", nil
+		}
+
+		err := filepath.WalkDir(inputPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			rel, err := filepath.Rel(p.Root, path)
+			if err != nil {
+				return err
+			}
+
+			_, _ = io.WriteString(hash, filepath.ToSlash(rel)+"\x00")
+
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+
+			defer func() {
+				_ = file.Close()
+			}()
+
+			_, err = io.Copy(hash, file)
+
+			return err
+		})
+		if err != nil {
+			return "", fmt.Errorf("hash input %s of step %s: %w", input, step.Name, err)
+		}
+	}
+
+	return fmt.Sprintf("ci-step-%s-%s", step.Name, hex.EncodeToString(hash.Sum(nil))[:32]), nil
+}
+
+func outputCacheKey(cacheKey, output string) string {
+	return cacheKey + "-" + strings.ReplaceAll(filepath.ToSlash(output), "/", "_")
+}
+
+// sortedSteps orders the steps so each step runs after the steps it needs,
+// keeping the declaration order otherwise.
+func (p *Pipeline) sortedSteps() ([]Step, error) {
+	byName := make(map[string]Step, len(p.Steps))
+
+	for _, step := range p.Steps {
+		if _, ok := byName[step.Name]; ok {
+			return nil, fmt.Errorf("step %q is declared twice", step.Name)
+		}
+
+		byName[step.Name] = step
+	}
+
+	sorted := make([]Step, 0, len(p.Steps))
+	state := make(map[string]int, len(p.Steps))
+
+	var visit func(step Step) error
+	visit = func(step Step) error {
+		switch state[step.Name] {
+		case 1:
+			return fmt.Errorf("step %q has a circular dependency", step.Name)
+		case 2:
+			return nil
+		}
+
+		state[step.Name] = 1
+
+		for _, need := range step.Needs {
+			dependency, ok := byName[need]
+			if !ok {
+				return fmt.Errorf("step %q needs unknown step %q", step.Name, need)
+			}
+
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		state[step.Name] = 2
+		sorted = append(sorted, step)
+
+		return nil
+	}
+
+	for _, step := range p.Steps {
+		if err := visit(step); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// WriteFile writes the report as JSON.
+func (r *Report) WriteFile(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o644)
+}
@@ -0,0 +1,169 @@
+package ci
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/system"
+)
+
+func recordingStep(name string, ran *[]string, needs ...string) Step {
+	return Step{
+		Name:  name,
+		Needs: needs,
+		Run: func(_ context.Context) error {
+			*ran = append(*ran, name)
+			return nil
+		},
+	}
+}
+
+func TestPipelineRunsStepsInDependencyOrder(t *testing.T) {
+	var ran []string
+
+	p := &Pipeline{Steps: []Step{
+		recordingStep("assets", &ran, "composer"),
+		recordingStep("composer", &ran),
+		recordingStep("checksums", &ran, "assets"),
+	}}
+
+	report, err := p.Run(t.Context(), RunOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"composer", "assets", "checksums"}, ran)
+	assert.Equal(t, StepStatusSucceeded, report.Status)
+	assert.Len(t, report.Steps, 3)
+}
+
+func TestPipelineRejectsInvalidGraph(t *testing.T) {
+	var ran []string
+
+	_, err := (&Pipeline{Steps: []Step{recordingStep("a", &ran, "b"), recordingStep("b", &ran, "a")}}).Run(t.Context(), RunOptions{})
+	assert.ErrorContains(t, err, "circular dependency")
+
+	_, err = (&Pipeline{Steps: []Step{recordingStep("a", &ran, "missing")}}).Run(t.Context(), RunOptions{})
+	assert.ErrorContains(t, err, "unknown step")
+
+	_, err = (&Pipeline{Steps: []Step{recordingStep("a", &ran)}}).Run(t.Context(), RunOptions{Only: "b"})
+	assert.ErrorContains(t, err, "possible steps: a")
+
+	_, err = (&Pipeline{Steps: []Step{recordingStep("a", &ran)}, Hooks: map[string]StepHooks{"b": {}}}).Run(t.Context(), RunOptions{})
+	assert.ErrorContains(t, err, "hooks configured for unknown step")
+
+	assert.Empty(t, ran)
+}
+
+func TestPipelineFromAndOnly(t *testing.T) {
+	var ran []string
+
+	p := &Pipeline{Steps: []Step{
+		recordingStep("composer", &ran),
+		recordingStep("assets", &ran),
+		recordingStep("checksums", &ran),
+	}}
+
+	report, err := p.Run(t.Context(), RunOptions{From: "assets"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"assets", "checksums"}, ran)
+	assert.Equal(t, StepStatusSkipped, report.Steps[0].Status)
+	assert.Equal(t, "before --from assets", report.Steps[0].Reason)
+
+	ran = nil
+
+	report, err = p.Run(t.Context(), RunOptions{Only: "assets"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"assets"}, ran)
+	assert.Equal(t, StepStatusSkipped, report.Steps[2].Status)
+
+	_, err = p.Run(t.Context(), RunOptions{From: "assets", Only: "assets"})
+	assert.Error(t, err)
+}
+
+func TestPipelineStopsAtFailedStep(t *testing.T) {
+	var ran []string
+
+	p := &Pipeline{Steps: []Step{
+		recordingStep("composer", &ran),
+		{Name: "assets", Run: func(_ context.Context) error { return errors.New("npm failed") }},
+		recordingStep("checksums", &ran),
+		{Name: "mjml", Disabled: "disabled by configuration"},
+	}}
+
+	report, err := p.Run(t.Context(), RunOptions{})
+	assert.ErrorContains(t, err, "step assets: npm failed")
+
+	assert.Equal(t, []string{"composer"}, ran)
+	assert.Equal(t, StepStatusFailed, report.Status)
+	assert.Equal(t, StepStatusFailed, report.Steps[1].Status)
+	assert.Equal(t, "npm failed", report.Steps[1].Error)
+	assert.Equal(t, "a previous step failed", report.Steps[2].Reason)
+
+	reportFile := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, report.WriteFile(reportFile))
+	content, err := os.ReadFile(reportFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"status": "failed"`)
+}
+
+func TestPipelineRunsHooksAroundStep(t *testing.T) {
+	var ran []string
+
+	p := &Pipeline{
+		Steps: []Step{recordingStep("composer", &ran)},
+		Hooks: map[string]StepHooks{"composer": {Pre: []string{"echo pre"}, Post: []string{"echo post"}}},
+		RunHooks: func(_ context.Context, sectionName string, hooks []string) error {
+			ran = append(ran, sectionName+": "+hooks[0])
+			return nil
+		},
+	}
+
+	_, err := p.Run(t.Context(), RunOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Running pre-composer hooks: echo pre", "composer", "Running post-composer hooks: echo post"}, ran)
+}
+
+func TestPipelineRestoresUnchangedStepFromCache(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "composer.lock"), []byte(`{"packages": []}`), 0o644))
+
+	runs := 0
+	step := Step{
+		Name:    "composer",
+		Inputs:  []string{"composer.lock"},
+		Outputs: []string{"vendor"},
+		Run: func(_ context.Context) error {
+			runs++
+			require.NoError(t, os.MkdirAll(filepath.Join(root, "vendor"), os.ModePerm))
+			return os.WriteFile(filepath.Join(root, "vendor", "autoload.php"), []byte("<?php"), 0o644)
+		},
+	}
+
+	p := &Pipeline{Root: root, Steps: []Step{step}, Cache: system.NewDiskCache(t.TempDir())}
+
+	report, err := p.Run(t.Context(), RunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, StepStatusSucceeded, report.Steps[0].Status)
+
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "vendor")))
+
+	report, err = p.Run(t.Context(), RunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, StepStatusSkipped, report.Steps[0].Status)
+	assert.FileExists(t, filepath.Join(root, "vendor", "autoload.php"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "composer.lock"), []byte(`{"packages": [{}]}`), 0o644))
+
+	report, err = p.Run(t.Context(), RunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs)
+	assert.Equal(t, StepStatusSucceeded, report.Steps[0].Status)
+}
@@ -8,16 +8,19 @@ import (
 var Default CiHelper
 
 func init() {
-	Default = NewCiHelper()
+	Default = &tracedCi{inner: NewCiHelper()}
 }
 
 // CiHelper is an interface for CI log formatting.
 type CiHelper interface {
-	Section(ctx context.Context, name string) Section
+	// Section starts a log section, work done with the returned context is
+	// nested under the section in traces.
+	Section(ctx context.Context, name string) (context.Context, Section)
 }
 
 type Section interface {
-	End(ctx context.Context)
+	// End ends the section, err is the error of the work done in it or nil.
+	End(ctx context.Context, err error)
 }
 
 // NewCiHelper returns a CiHelper for the current CI environment.
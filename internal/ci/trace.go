@@ -0,0 +1,32 @@
+package ci
+
+import (
+	"context"
+
+	"github.com/shopware/shopware-cli/internal/tracing"
+)
+
+// tracedCi records a trace span for every section of the wrapped CiHelper.
+type tracedCi struct {
+	inner CiHelper
+}
+
+type tracedSection struct {
+	inner Section
+	span  *tracing.Span
+}
+
+func (t *tracedCi) Section(ctx context.Context, name string) (context.Context, Section) {
+	ctx, span := tracing.Start(ctx, "section "+name, tracing.String("ci.section", name))
+	ctx, inner := t.inner.Section(ctx, name)
+
+	return ctx, tracedSection{
+		inner: inner,
+		span:  span,
+	}
+}
+
+func (s tracedSection) End(ctx context.Context, err error) {
+	s.inner.End(ctx, err)
+	s.span.End(err)
+}
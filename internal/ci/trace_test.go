@@ -0,0 +1,80 @@
+package ci
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/tracing"
+)
+
+type exportedSpan struct {
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Status       struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+func readExportedSpans(t *testing.T, file string) map[string]exportedSpan {
+	t.Helper()
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	var request struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []exportedSpan `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	require.NoError(t, json.Unmarshal(content, &request))
+
+	spans := map[string]exportedSpan{}
+	for _, resourceSpans := range request.ResourceSpans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				spans[span.Name] = span
+			}
+		}
+	}
+
+	return spans
+}
+
+func TestTracedSectionNestsSpans(t *testing.T) {
+	t.Setenv("TRACEPARENT", "")
+	file := filepath.Join(t.TempDir(), "traces.jsonl")
+	require.NoError(t, tracing.Setup("file://"+file, "1.0.0"))
+
+	ctx, step := tracing.Start(t.Context(), "step")
+	sectionCtx, section := (&tracedCi{inner: &DefaultCi{}}).Section(ctx, "Composer Installation")
+
+	_, work := tracing.Start(sectionCtx, "work")
+	work.End(nil)
+
+	section.End(sectionCtx, errors.New("composer install failed"))
+	step.End(nil)
+
+	require.NoError(t, tracing.Shutdown(t.Context()))
+
+	spans := readExportedSpans(t, file)
+	require.Len(t, spans, 3)
+
+	stepSpan := spans["step"]
+	sectionSpan := spans["section Composer Installation"]
+
+	assert.Equal(t, stepSpan.SpanID, sectionSpan.ParentSpanID)
+	assert.Equal(t, sectionSpan.SpanID, spans["work"].ParentSpanID)
+	assert.Equal(t, 2, sectionSpan.Status.Code)
+	assert.Equal(t, "composer install failed", sectionSpan.Status.Message)
+	assert.Zero(t, stepSpan.Status.Code)
+}
@@ -0,0 +1,193 @@
+// Package cigen renders CI pipelines for Shopware projects. The pipelines
+// validate the project, build it with project ci, publish the SBOM and deploy to
+// the chosen target. Regenerating merges the new output with edits made to the
+// previously generated files, see Write.
+package cigen
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+const (
+	SystemGitHub    = shop.CIGitHub
+	SystemGitLab    = shop.CIGitLab
+	SystemBitbucket = "bitbucket"
+	SystemAzure     = "azure"
+	SystemJenkins   = "jenkins"
+
+	TargetNone       = shop.DeploymentNone
+	TargetDeployer   = shop.DeploymentDeployer
+	TargetPlatformSH = shop.DeploymentPlatformSH
+	TargetK8s        = "k8s"
+)
+
+// Systems lists all supported CI systems.
+var Systems = []string{SystemGitHub, SystemGitLab, SystemBitbucket, SystemAzure, SystemJenkins}
+
+// Targets lists all supported deployment targets.
+var Targets = []string{TargetNone, TargetDeployer, TargetPlatformSH, TargetK8s}
+
+var pipelineFiles = map[string]string{
+	SystemGitHub:    ".github/workflows/shopware.yml",
+	SystemGitLab:    ".gitlab-ci.yml",
+	SystemBitbucket: "bitbucket-pipelines.yml",
+	SystemAzure:     "azure-pipelines.yml",
+	SystemJenkins:   "Jenkinsfile",
+}
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Options configure the generated pipeline.
+type Options struct {
+	System string
+	Target string
+	// PHPVersion selects the shopware-cli image, e.g. 8.3
+	PHPVersion string
+	// Branch deployments run on
+	Branch string
+	// K8sDeployment is the name of the Kubernetes deployment updated by the k8s target
+	K8sDeployment string
+}
+
+// File is a generated file relative to the project root.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+type deployStep struct {
+	// NeedsBuild is set when the target deploys the tree prepared by project ci
+	NeedsBuild bool
+	Script     []string
+	// Secrets are environment variables the CI system has to provide
+	Secrets []string
+}
+
+type templateData struct {
+	Options
+	Image      string
+	Reporter   string
+	ReportFile string
+	Deploy     *deployStep
+}
+
+// ValidateCommand returns the project validate invocation for the reporter of the system.
+func (d templateData) ValidateCommand() string {
+	command := "shopware-cli project validate --reporter " + d.Reporter
+	if d.ReportFile != "" {
+		command += " > " + d.ReportFile
+	}
+
+	return command
+}
+
+// Generate renders the pipeline for the system and target.
+func Generate(opts Options) ([]File, error) {
+	if !slices.Contains(Systems, opts.System) {
+		return nil, fmt.Errorf("unsupported CI system %q, supported are: %s", opts.System, strings.Join(Systems, ", "))
+	}
+
+	if opts.Target == "" {
+		opts.Target = TargetNone
+	}
+
+	if !slices.Contains(Targets, opts.Target) {
+		return nil, fmt.Errorf("unsupported deployment target %q, supported are: %s", opts.Target, strings.Join(Targets, ", "))
+	}
+
+	if opts.PHPVersion == "" {
+		opts.PHPVersion = shop.SupportedPHPVersions[len(shop.SupportedPHPVersions)-1]
+	}
+
+	if opts.Branch == "" {
+		opts.Branch = "main"
+	}
+
+	if opts.K8sDeployment == "" {
+		opts.K8sDeployment = "shopware"
+	}
+
+	data := templateData{
+		Options:  opts,
+		Image:    fmt.Sprintf("ghcr.io/shopware/shopware-cli:latest-php-%s", opts.PHPVersion),
+		Reporter: validation.ReporterForCISystem(opts.System),
+		Deploy:   deployStepFor(opts),
+	}
+
+	// only the github reporter prints annotations, the others write a report file
+	switch data.Reporter {
+	case "junit":
+		data.ReportFile = "validation-report.xml"
+	case "gitlab":
+		data.ReportFile = "gl-code-quality-report.json"
+	}
+
+	tmpl, err := template.New(opts.System).Funcs(template.FuncMap{
+		"quote": yamlQuote,
+	}).ParseFS(templateFS, "templates/"+opts.System+".tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, opts.System+".tmpl", data); err != nil {
+		return nil, fmt.Errorf("render %s pipeline: %w", opts.System, err)
+	}
+
+	return []File{{Path: pipelineFiles[opts.System], Content: buf.Bytes()}}, nil
+}
+
+// deployStepFor returns the shell commands of the target. They are shared by all
+// systems, so they must not contain single quotes, which some templates use to
+// pass the script to a container.
+func deployStepFor(opts Options) *deployStep {
+	switch opts.Target {
+	case TargetDeployer:
+		return &deployStep{
+			NeedsBuild: true,
+			Script: []string{
+				`eval "$(ssh-agent -s)"`,
+				`echo "$SSH_PRIVATE_KEY" | tr -d "\r" | ssh-add -`,
+				`mkdir -p ~/.ssh && echo "$SSH_KNOWN_HOSTS" >> ~/.ssh/known_hosts`,
+				`vendor/bin/dep deploy`,
+			},
+			Secrets: []string{"SSH_PRIVATE_KEY", "SSH_KNOWN_HOSTS"},
+		}
+	case TargetPlatformSH:
+		return &deployStep{
+			Script: []string{
+				`curl -fsSL https://raw.githubusercontent.com/platformsh/cli/main/installer.sh | VENDOR=platformsh bash`,
+				fmt.Sprintf(`platform push --yes --project "$PLATFORMSH_PROJECT" --target %s`, opts.Branch),
+			},
+			Secrets: []string{"PLATFORMSH_CLI_TOKEN", "PLATFORMSH_PROJECT"},
+		}
+	case TargetK8s:
+		return &deployStep{
+			NeedsBuild: true,
+			Script: []string{
+				`shopware-cli project image build . --tag "$IMAGE_REPOSITORY:$IMAGE_TAG" --output image.tar`,
+				`apk add --no-cache skopeo`,
+				`skopeo copy --dest-creds "$REGISTRY_USERNAME:$REGISTRY_PASSWORD" oci-archive:image.tar "docker://$IMAGE_REPOSITORY:$IMAGE_TAG"`,
+				`curl -fsSLo /usr/local/bin/kubectl "https://dl.k8s.io/release/$(curl -fsSL https://dl.k8s.io/release/stable.txt)/bin/linux/amd64/kubectl" && chmod +x /usr/local/bin/kubectl`,
+				`echo "$KUBE_CONFIG" | base64 -d > "$HOME/kubeconfig"`,
+				fmt.Sprintf(`KUBECONFIG="$HOME/kubeconfig" kubectl set image "deployment/%s" "*=$IMAGE_REPOSITORY:$IMAGE_TAG"`, opts.K8sDeployment),
+			},
+			Secrets: []string{"IMAGE_REPOSITORY", "REGISTRY_USERNAME", "REGISTRY_PASSWORD", "KUBE_CONFIG"},
+		}
+	default:
+		return nil
+	}
+}
+
+func yamlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
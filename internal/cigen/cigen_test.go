@@ -0,0 +1,114 @@
+package cigen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateAllCombinations(t *testing.T) {
+	for _, system := range Systems {
+		for _, target := range Targets {
+			t.Run(system+"/"+target, func(t *testing.T) {
+				files, err := Generate(Options{System: system, Target: target, PHPVersion: "8.3"})
+				require.NoError(t, err)
+				require.Len(t, files, 1)
+
+				content := string(files[0].Content)
+				assert.Contains(t, content, "ghcr.io/shopware/shopware-cli:latest-php-8.3")
+				assert.Contains(t, content, "shopware-cli project validate --reporter")
+				assert.Contains(t, content, "shopware-cli project ci .")
+				assert.Contains(t, content, "sbom.cdx.json")
+
+				if target == TargetNone {
+					assert.NotContains(t, content, "deploy:")
+				}
+
+				if target == TargetK8s {
+					assert.Contains(t, content, "project image build")
+				}
+
+				if system == SystemJenkins {
+					return
+				}
+
+				var parsed map[string]any
+				assert.NoError(t, yaml.Unmarshal(files[0].Content, &parsed), content)
+			})
+		}
+	}
+}
+
+func TestGenerateUsesReporterOfSystem(t *testing.T) {
+	github, err := Generate(Options{System: SystemGitHub})
+	require.NoError(t, err)
+	assert.Contains(t, string(github[0].Content), "run: shopware-cli project validate --reporter github\n")
+
+	gitlab, err := Generate(Options{System: SystemGitLab})
+	require.NoError(t, err)
+	assert.Contains(t, string(gitlab[0].Content), "--reporter gitlab > gl-code-quality-report.json")
+
+	jenkins, err := Generate(Options{System: SystemJenkins})
+	require.NoError(t, err)
+	assert.Contains(t, string(jenkins[0].Content), "--reporter junit > validation-report.xml")
+}
+
+func TestGenerateRejectsUnknownValues(t *testing.T) {
+	_, err := Generate(Options{System: "travis"})
+	assert.ErrorContains(t, err, "unsupported CI system")
+
+	_, err = Generate(Options{System: SystemGitHub, Target: "ftp"})
+	assert.ErrorContains(t, err, "unsupported deployment target")
+}
+
+func TestWriteKeepsUserEdits(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "ci.yml")
+
+	results, err := Write(root, []File{{Path: "ci.yml", Content: []byte("a\nb\nc\n")}}, WriteOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []WriteResult{{Path: "ci.yml", Status: StatusCreated}}, results)
+
+	require.NoError(t, os.WriteFile(path, []byte("a\nb\nc\nuser\n"), 0o644))
+
+	results, err = Write(root, []File{{Path: "ci.yml", Content: []byte("A\nb\nc\n")}}, WriteOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, StatusMerged, results[0].Status)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "A\nb\nc\nuser\n", string(content))
+
+	base, err := os.ReadFile(filepath.Join(root, StateDir, "ci.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "A\nb\nc\n", string(base))
+}
+
+func TestWriteSkipsForeignFiles(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "ci.yml")
+	require.NoError(t, os.WriteFile(path, []byte("custom\n"), 0o644))
+
+	results, err := Write(root, []File{{Path: "ci.yml", Content: []byte("generated\n")}}, WriteOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, StatusSkipped, results[0].Status)
+
+	results, err = Write(root, []File{{Path: "ci.yml", Content: []byte("generated\n")}}, WriteOptions{DryRun: true, Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, StatusUpdated, results[0].Status)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "custom\n", string(content))
+
+	_, err = Write(root, []File{{Path: "ci.yml", Content: []byte("generated\n")}}, WriteOptions{Force: true})
+	require.NoError(t, err)
+
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "generated\n", string(content))
+}
@@ -0,0 +1,125 @@
+package cigen
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	conflictOurs   = "<<<<<<< current\n"
+	conflictBase   = "||||||| previously generated\n"
+	conflictSep    = "=======\n"
+	conflictTheirs = ">>>>>>> generated\n"
+)
+
+// Merge3 merges the changes from base to ours and from base to theirs line by
+// line, like diff3. Hunks changed on only one side are taken from that side.
+// Hunks changed differently on both sides are written with conflict markers and
+// reported through the returned flag.
+func Merge3(base, ours, theirs string) (string, bool) {
+	baseLines := splitLines(base)
+	ourLines := splitLines(ours)
+	theirLines := splitLines(theirs)
+
+	ourMatch := matchLines(baseLines, ourLines)
+	theirMatch := matchLines(baseLines, theirLines)
+
+	var out []string
+	conflict := false
+	i, j, k := 0, 0, 0
+
+	for {
+		// find the next base line kept on both sides
+		next := i
+		for next < len(baseLines) && (ourMatch[next] < j || theirMatch[next] < k) {
+			next++
+		}
+
+		baseEnd, ourEnd, theirEnd := len(baseLines), len(ourLines), len(theirLines)
+		if next < len(baseLines) {
+			baseEnd, ourEnd, theirEnd = next, ourMatch[next], theirMatch[next]
+		}
+
+		hunkBase := baseLines[i:baseEnd]
+		hunkOurs := ourLines[j:ourEnd]
+		hunkTheirs := theirLines[k:theirEnd]
+
+		switch {
+		case slices.Equal(hunkOurs, hunkBase):
+			out = append(out, hunkTheirs...)
+		case slices.Equal(hunkTheirs, hunkBase), slices.Equal(hunkOurs, hunkTheirs):
+			out = append(out, hunkOurs...)
+		default:
+			conflict = true
+			out = append(out, conflictOurs)
+			out = append(out, hunkOurs...)
+			out = append(out, conflictBase)
+			out = append(out, hunkBase...)
+			out = append(out, conflictSep)
+			out = append(out, hunkTheirs...)
+			out = append(out, conflictTheirs)
+		}
+
+		if next >= len(baseLines) {
+			break
+		}
+
+		out = append(out, baseLines[next])
+		i, j, k = next+1, ourEnd+1, theirEnd+1
+	}
+
+	return strings.Join(out, ""), conflict
+}
+
+// splitLines splits after each newline and keeps it, so a missing trailing
+// newline survives the merge.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// matchLines returns for every line of a the index of the matching line in b of
+// a longest common subsequence, or -1.
+func matchLines(a, b []string) []int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	match := make([]int, len(a))
+	i, j := 0, 0
+
+	for i < len(a) {
+		switch {
+		case j < len(b) && a[i] == b[j]:
+			match[i] = j
+			i++
+			j++
+		case j < len(b) && lcs[i+1][j] < lcs[i][j+1]:
+			j++
+		default:
+			match[i] = -1
+			i++
+		}
+	}
+
+	return match
+}
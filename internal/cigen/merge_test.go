@@ -0,0 +1,41 @@
+package cigen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge3(t *testing.T) {
+	base := "a\nb\nc\nd\n"
+
+	cases := []struct {
+		name     string
+		ours     string
+		theirs   string
+		expected string
+		conflict bool
+	}{
+		{"only ours changed", "a\nB\nc\nd\n", base, "a\nB\nc\nd\n", false},
+		{"only theirs changed", base, "a\nb\nC\nd\n", "a\nb\nC\nd\n", false},
+		{"both changed different lines", "a\nB\nc\nd\n", "a\nb\nc\nD\n", "a\nB\nc\nD\n", false},
+		{"both made the same change", "a\nX\nc\nd\n", "a\nX\nc\nd\n", "a\nX\nc\nd\n", false},
+		{"ours added, theirs removed", "a\nb\nnew\nc\nd\n", "a\nb\nc\n", "a\nb\nnew\nc\n", false},
+		{"missing trailing newline", "a\nb\nc\nd", "A\nb\nc\nd\n", "A\nb\nc\nd", false},
+		{
+			"conflict",
+			"a\nours\nc\nd\n",
+			"a\ntheirs\nc\nd\n",
+			"a\n<<<<<<< current\nours\n||||||| previously generated\nb\n=======\ntheirs\n>>>>>>> generated\nc\nd\n",
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			merged, conflict := Merge3(base, tc.ours, tc.theirs)
+			assert.Equal(t, tc.expected, merged)
+			assert.Equal(t, tc.conflict, conflict)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package cigen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// StateDir keeps a copy of the last generated version of every file relative to
+// the project root. It is the base of the 3-way merge and should be committed.
+const StateDir = ".shopware-ci"
+
+const (
+	StatusCreated   = "created"
+	StatusUpdated   = "updated"
+	StatusMerged    = "merged"
+	StatusUnchanged = "unchanged"
+	StatusConflict  = "conflict"
+	StatusSkipped   = "skipped"
+)
+
+// WriteOptions control how generated files are written.
+type WriteOptions struct {
+	// Force overwrites files, discarding local changes
+	Force bool
+	// DryRun computes the results without touching the disk
+	DryRun bool
+}
+
+// WriteResult reports what happened with a generated file.
+type WriteResult struct {
+	Path   string
+	Status string
+}
+
+// Write stores the generated files in the project. Files that were edited since
+// they were last generated are 3-way merged with the new output. Merges with
+// conflicts are written with conflict markers, while files that exist but were
+// never generated are skipped unless Force is set.
+func Write(root string, files []File, opts WriteOptions) ([]WriteResult, error) {
+	results := make([]WriteResult, 0, len(files))
+
+	for _, file := range files {
+		target := filepath.Join(root, filepath.FromSlash(file.Path))
+		basePath := filepath.Join(root, StateDir, filepath.FromSlash(file.Path))
+
+		current, err := readOptional(target)
+		if err != nil {
+			return nil, err
+		}
+
+		base, err := readOptional(basePath)
+		if err != nil {
+			return nil, err
+		}
+
+		content, status := mergeFile(current, base, file.Content, opts.Force)
+		results = append(results, WriteResult{Path: file.Path, Status: status})
+
+		if opts.DryRun || status == StatusSkipped {
+			continue
+		}
+
+		if status != StatusUnchanged {
+			if err := writeFile(target, content); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := writeFile(basePath, file.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+func mergeFile(current, base, generated []byte, force bool) ([]byte, string) {
+	switch {
+	case current == nil:
+		return generated, StatusCreated
+	case bytes.Equal(current, generated):
+		return current, StatusUnchanged
+	case force:
+		return generated, StatusUpdated
+	case base == nil:
+		return nil, StatusSkipped
+	case bytes.Equal(current, base):
+		return generated, StatusUpdated
+	}
+
+	merged, conflict := Merge3(string(base), string(current), string(generated))
+	if conflict {
+		return []byte(merged), StatusConflict
+	}
+
+	if merged == string(current) {
+		return current, StatusUnchanged
+	}
+
+	return []byte(merged), StatusMerged
+}
+
+func readOptional(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	return data, nil
+}
+
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o644)
+}
@@ -0,0 +1,82 @@
+package compatibility
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetDateInFile sets compatibility_date in the YAML config file and keeps the
+// rest of the file including comments. A missing file is created.
+func SetDateInFile(path, date string) error {
+	if err := ValidateDate(date); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return os.WriteFile(path, fmt.Appendf(nil, "compatibility_date: %s\n", date), 0o644)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return fmt.Errorf("file: %s: %w", path, err)
+	}
+
+	if len(document.Content) == 0 {
+		return os.WriteFile(path, append(content, fmt.Appendf(nil, "compatibility_date: %s\n", date)...), 0o644)
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("file: %s: expected a mapping", path)
+	}
+
+	// Keep the date unquoted like in hand written configs
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: date}
+
+	found := false
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "compatibility_date" {
+			value.Style = root.Content[i+1].Style
+			root.Content[i+1] = value
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "compatibility_date"}
+
+		// Keep a leading comment like the schema comment at the top
+		if len(root.Content) > 0 {
+			key.HeadComment = root.Content[0].HeadComment
+			root.Content[0].HeadComment = ""
+		}
+
+		root.Content = append([]*yaml.Node{key, value}, root.Content...)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,44 @@
+package compatibility
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDateInFileKeepsContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".shopware-project.yml")
+	require.NoError(t, os.WriteFile(path, []byte("# yaml-language-server: $schema=schema.json\nurl: http://localhost # the shop\ncompatibility_date: 2026-02-11\n"), 0o644))
+
+	require.NoError(t, SetDateInFile(path, "2026-03-01"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# yaml-language-server: $schema=schema.json\nurl: http://localhost # the shop\ncompatibility_date: 2026-03-01\n", string(content))
+}
+
+func TestSetDateInFileAddsDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".shopware-extension.yml")
+	require.NoError(t, os.WriteFile(path, []byte("# yaml-language-server: $schema=schema.json\nbuild:\n  zip:\n    assets:\n      enabled: false\n"), 0o644))
+
+	require.NoError(t, SetDateInFile(path, "2026-03-01"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# yaml-language-server: $schema=schema.json\ncompatibility_date: 2026-03-01\nbuild:\n  zip:\n    assets:\n      enabled: false\n", string(content))
+}
+
+func TestSetDateInFileCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".shopware-extension.yml")
+
+	require.NoError(t, SetDateInFile(path, "2026-03-01"))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "compatibility_date: 2026-03-01\n", string(content))
+
+	assert.Error(t, SetDateInFile(path, "01-03-2026"))
+}
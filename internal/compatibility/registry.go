@@ -0,0 +1,160 @@
+package compatibility
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Target is the kind of config a change applies to.
+type Target string
+
+const (
+	TargetProject Target = "project"
+	// TargetExtension changes apply to the extension config, none are registered yet
+	TargetExtension Target = "extension"
+)
+
+// Finding is a place which has to be changed to adopt a change.
+type Finding struct {
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+// Change is a behavior change enabled by a compatibility date.
+type Change struct {
+	// Date is the compatibility date enabling the change
+	Date   string
+	Target Target
+	Title  string
+	// Description explains how the behavior changes
+	Description string
+	// Detect reports the places affected by the change, optional
+	Detect func(ctx context.Context, dir string) ([]Finding, error)
+	// Migrate adopts the change automatically and returns what it did, optional
+	Migrate func(ctx context.Context, dir string) ([]string, error)
+}
+
+var changes []Change
+
+// Register adds a change to the registry, it is called from init functions.
+func Register(change Change) {
+	if _, err := parseDate(change.Date); err != nil {
+		panic(fmt.Sprintf("compatibility change %q has invalid date %q", change.Title, change.Date))
+	}
+
+	changes = append(changes, change)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Date < changes[j].Date
+	})
+}
+
+// Changes returns the registered changes of the target ordered by date.
+func Changes(target Target) []Change {
+	var result []Change
+
+	for _, change := range changes {
+		if change.Target == target {
+			result = append(result, change)
+		}
+	}
+
+	return result
+}
+
+// Pending returns the changes of the target which are not enabled by the
+// current compatibility date, but by the date to. isAtLeast is the
+// IsCompatibilityDateAtLeast of the config.
+func Pending(target Target, isAtLeast func(date string) (bool, error), to string) ([]Change, error) {
+	if err := ValidateDate(to); err != nil {
+		return nil, err
+	}
+
+	var pending []Change
+
+	for _, change := range Changes(target) {
+		enabled, err := isAtLeast(change.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		if !enabled && change.Date <= to {
+			pending = append(pending, change)
+		}
+	}
+
+	return pending, nil
+}
+
+// ChangeResult is the outcome of a change for a directory.
+type ChangeResult struct {
+	Change   Change    `json:"-"`
+	Date     string    `json:"date"`
+	Title    string    `json:"title"`
+	Migrated []string  `json:"migrated,omitempty"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Check runs the detectors of the changes for the directory. With migrate the
+// migrations run before, so only the findings needing manual work remain.
+func Check(ctx context.Context, dir string, pending []Change, migrate bool) ([]ChangeResult, error) {
+	results := make([]ChangeResult, 0, len(pending))
+
+	for _, change := range pending {
+		result := ChangeResult{Change: change, Date: change.Date, Title: change.Title}
+
+		if migrate && change.Migrate != nil {
+			migrated, err := change.Migrate(ctx, dir)
+			if err != nil {
+				return nil, fmt.Errorf("migrate %s (%s): %w", change.Title, change.Date, err)
+			}
+
+			result.Migrated = migrated
+		}
+
+		if change.Detect != nil {
+			findings, err := change.Detect(ctx, dir)
+			if err != nil {
+				return nil, fmt.Errorf("detect %s (%s): %w", change.Title, change.Date, err)
+			}
+
+			result.Findings = findings
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// HasFindings reports whether any change still needs manual work.
+func HasFindings(results []ChangeResult) bool {
+	for _, result := range results {
+		if len(result.Findings) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Upgrade migrates the directory to the compatibility date to and sets it in
+// the config file. Without force the date is only set when no findings
+// remain, the results are returned in both cases.
+func Upgrade(ctx context.Context, dir, configPath string, pending []Change, to string, force bool) ([]ChangeResult, error) {
+	results, err := Check(ctx, dir, pending, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if HasFindings(results) && !force {
+		return results, fmt.Errorf("some changes need manual work, fix the findings and run the upgrade again or pass --force to set compatibility_date %s anyway", to)
+	}
+
+	if err := SetDateInFile(configPath, to); err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
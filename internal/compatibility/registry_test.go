@@ -0,0 +1,102 @@
+package compatibility
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withChanges(t *testing.T, registered ...Change) {
+	t.Helper()
+
+	previous := changes
+	changes = nil
+
+	t.Cleanup(func() {
+		changes = previous
+	})
+
+	for _, change := range registered {
+		Register(change)
+	}
+}
+
+func TestPending(t *testing.T) {
+	withChanges(t,
+		Change{Date: "2026-05-01", Target: TargetProject, Title: "later"},
+		Change{Date: "2026-03-01", Target: TargetProject, Title: "earlier"},
+		Change{Date: "2026-03-01", Target: TargetExtension, Title: "extension"},
+		Change{Date: "2026-01-01", Target: TargetProject, Title: "enabled"},
+	)
+
+	isAtLeast := func(date string) (bool, error) {
+		return IsAtLeast("2026-02-11", date)
+	}
+
+	pending, err := Pending(TargetProject, isAtLeast, "2026-04-01")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "earlier", pending[0].Title)
+
+	pending, err = Pending(TargetProject, isAtLeast, "2026-06-01")
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "earlier", pending[0].Title)
+	assert.Equal(t, "later", pending[1].Title)
+
+	_, err = Pending(TargetProject, isAtLeast, "invalid")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() {
+		Register(Change{Date: "invalid"})
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".shopware-project.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("compatibility_date: 2026-02-11\n"), 0o644))
+
+	migrated := false
+
+	change := Change{
+		Date:  "2026-03-01",
+		Title: "test",
+		Detect: func(context.Context, string) ([]Finding, error) {
+			if migrated {
+				return nil, nil
+			}
+
+			return []Finding{{Path: "composer.json", Message: "needs migration"}}, nil
+		},
+	}
+
+	results, err := Check(t.Context(), dir, []Change{change}, true)
+	require.NoError(t, err)
+	assert.True(t, HasFindings(results))
+
+	_, err = Upgrade(t.Context(), dir, configPath, []Change{change}, "2026-03-01", false)
+	assert.ErrorContains(t, err, "manual work")
+
+	content, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "2026-02-11")
+
+	change.Migrate = func(context.Context, string) ([]string, error) {
+		migrated = true
+		return []string{"migrated"}, nil
+	}
+
+	results, err = Upgrade(t.Context(), dir, configPath, []Change{change}, "2026-03-01", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"migrated"}, results[0].Migrated)
+	assert.False(t, HasFindings(results))
+
+	content, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "compatibility_date: 2026-03-01\n", string(content))
+}
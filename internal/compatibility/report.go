@@ -0,0 +1,77 @@
+package compatibility
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/tui"
+)
+
+// WriteReport prints the changes with their migrations and findings.
+func WriteReport(w io.Writer, current, to string, results []ChangeResult) error {
+	var b strings.Builder
+
+	if len(results) == 0 {
+		fmt.Fprintf(&b, "%s No behavior changes between compatibility date %s and %s\n", tui.GreenText.Render("✓"), current, to)
+		_, err := io.WriteString(w, b.String())
+
+		return err
+	}
+
+	fmt.Fprintf(&b, "%s\n\n", tui.BoldText.Render(fmt.Sprintf("%d behavior changes between compatibility date %s and %s", len(results), current, to)))
+
+	for _, result := range results {
+		status := tui.GreenText.Render("ready")
+		if len(result.Findings) > 0 {
+			status = tui.YellowText.Render("needs changes")
+		}
+
+		fmt.Fprintf(&b, "%s  %s  %s\n", tui.BlueText.Render(result.Date), tui.BoldText.Render(result.Title), status)
+
+		for _, line := range strings.Split(result.Change.Description, "\n") {
+			fmt.Fprintf(&b, "  %s\n", tui.DimText.Render(line))
+		}
+
+		for _, migrated := range result.Migrated {
+			fmt.Fprintf(&b, "  %s %s\n", tui.GreenText.Render("✓"), migrated)
+		}
+
+		for _, finding := range result.Findings {
+			if finding.Path != "" {
+				fmt.Fprintf(&b, "  %s %s: %s\n", tui.YellowText.Render("!"), finding.Path, finding.Message)
+			} else {
+				fmt.Fprintf(&b, "  %s %s\n", tui.YellowText.Render("!"), finding.Message)
+			}
+		}
+
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteReportJSON prints the changes as JSON.
+func WriteReportJSON(w io.Writer, current, to string, results []ChangeResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	type jsonResult struct {
+		ChangeResult
+		Description string `json:"description"`
+	}
+
+	changes := make([]jsonResult, 0, len(results))
+	for _, result := range results {
+		changes = append(changes, jsonResult{ChangeResult: result, Description: result.Change.Description})
+	}
+
+	return encoder.Encode(struct {
+		Current string       `json:"current"`
+		To      string       `json:"to"`
+		Changes []jsonResult `json:"changes"`
+	}{current, to, changes})
+}
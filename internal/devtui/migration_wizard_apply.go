@@ -1,13 +1,6 @@
 package devtui
 
-import (
-	"os"
-	"path/filepath"
-
-	"github.com/shyim/go-composer"
-
-	"github.com/shopware/shopware-cli/internal/shop"
-)
+import "github.com/shopware/shopware-cli/internal/shop"
 
 func (sg *migrationWizard) applyToConfig(cfg *shop.Config) {
 	c := sg.currentConfig()
@@ -45,37 +38,3 @@ func (sg *migrationWizard) applyToConfig(cfg *shop.Config) {
 	}
 	cfg.Docker.PHP.Version = c.phpVersion
 }
-
-// ensureDeploymentHelper adds shopware/deployment-helper to the project's
-// composer.json require block when it's missing. New projects created via
-// `shopware-cli project create` pin this package; older projects being
-// migrated to dev mode need it added so devtui can run
-// `vendor/bin/shopware-deployment-helper`.
-//
-// Returns true when composer.json was changed and the user should re-run
-// `composer install` (or `composer update`) to pull the package in.
-// Errors reading or writing composer.json are returned to the caller;
-// a missing composer.json is treated as nothing-to-do (returns false, nil).
-func ensureDeploymentHelper(projectRoot string) (changed bool, err error) {
-	composerPath := filepath.Join(projectRoot, "composer.json")
-	if _, statErr := os.Stat(composerPath); statErr != nil {
-		if os.IsNotExist(statErr) {
-			return false, nil
-		}
-		return false, statErr
-	}
-
-	cj, err := composer.ReadJson(composerPath)
-	if err != nil {
-		return false, err
-	}
-
-	if !cj.EnsurePackage("shopware/deployment-helper", "*") {
-		return false, nil
-	}
-
-	if err := cj.Save(); err != nil {
-		return false, err
-	}
-	return true, nil
-}
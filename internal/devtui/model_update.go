@@ -311,7 +311,7 @@ func (m Model) saveMigrationWizard() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	changed, err := ensureDeploymentHelper(m.projectRoot)
+	changed, err := shop.EnsureDeploymentHelper(m.projectRoot)
 	if err != nil {
 		m.migrationWizard.err = err
 		m.migrationWizard.step = migrationStepDone
@@ -29,7 +29,7 @@ func (d *DockerExecutor) ConsoleCommand(ctx context.Context, args ...string) *Pr
 	cmd := exec.CommandContext(ctx, "docker", dockerArgs...)
 	applyDir(d.projectRoot, cmd)
 	logCmd(ctx, cmd)
-	return d.newProcess(cmd, append([]string{"php", consoleCommandName(ctx)}, args...))
+	return d.newProcess(ctx, cmd, append([]string{"php", consoleCommandName(ctx)}, args...))
 }
 
 func (d *DockerExecutor) ComposerCommand(ctx context.Context, args ...string) *Process {
@@ -40,7 +40,7 @@ func (d *DockerExecutor) ComposerCommand(ctx context.Context, args ...string) *P
 	cmd := exec.CommandContext(ctx, "docker", dockerArgs...)
 	applyDir(d.projectRoot, cmd)
 	logCmd(ctx, cmd)
-	return d.newProcess(cmd, append([]string{"composer"}, args...))
+	return d.newProcess(ctx, cmd, append([]string{"composer"}, args...))
 }
 
 func (d *DockerExecutor) PHPCommand(ctx context.Context, args ...string) *Process {
@@ -51,7 +51,7 @@ func (d *DockerExecutor) PHPCommand(ctx context.Context, args ...string) *Proces
 	cmd := exec.CommandContext(ctx, "docker", dockerArgs...)
 	applyDir(d.projectRoot, cmd)
 	logCmd(ctx, cmd)
-	return d.newProcess(cmd, append([]string{"php"}, args...))
+	return d.newProcess(ctx, cmd, append([]string{"php"}, args...))
 }
 
 func (d *DockerExecutor) NPMCommand(ctx context.Context, args ...string) *Process {
@@ -62,7 +62,7 @@ func (d *DockerExecutor) NPMCommand(ctx context.Context, args ...string) *Proces
 	cmd := exec.CommandContext(ctx, "docker", dockerArgs...)
 	applyDir(d.projectRoot, cmd)
 	logCmd(ctx, cmd)
-	return d.newProcess(cmd, append([]string{"npm"}, args...))
+	return d.newProcess(ctx, cmd, append([]string{"npm"}, args...))
 }
 
 func (d *DockerExecutor) NormalizePath(hostPath string) string {
@@ -112,12 +112,13 @@ func (d *DockerExecutor) containerWorkdir() string {
 	return filepath.Join("/var/www/html", d.relDir)
 }
 
-func (d *DockerExecutor) newProcess(cmd *exec.Cmd, innerArgs []string) *Process {
+func (d *DockerExecutor) newProcess(ctx context.Context, cmd *exec.Cmd, innerArgs []string) *Process {
 	projectRoot := d.projectRoot
 	pattern := strings.Join(innerArgs, " ")
 
 	return &Process{
 		Cmd: cmd,
+		ctx: ctx,
 		stop: func(ctx context.Context) error {
 			killCmd := exec.CommandContext(ctx, "docker", "compose", "exec", "-T", "web",
 				"pkill", "-INT", "-f", pattern,
@@ -25,7 +25,7 @@ func (l *LocalExecutor) ConsoleCommand(ctx context.Context, args ...string) *Pro
 	applyLocalEnv(l.projectRoot, l.env, cmd)
 	applyDir(resolveDir(l.projectRoot, l.relDir), cmd)
 	logCmd(ctx, cmd)
-	return newProcess(cmd)
+	return newProcess(ctx, cmd)
 }
 
 func (l *LocalExecutor) ComposerCommand(ctx context.Context, args ...string) *Process {
@@ -33,7 +33,7 @@ func (l *LocalExecutor) ComposerCommand(ctx context.Context, args ...string) *Pr
 	applyLocalEnv(l.projectRoot, l.env, cmd)
 	applyDir(resolveDir(l.projectRoot, l.relDir), cmd)
 	logCmd(ctx, cmd)
-	return newProcess(cmd)
+	return newProcess(ctx, cmd)
 }
 
 func (l *LocalExecutor) PHPCommand(ctx context.Context, args ...string) *Process {
@@ -41,7 +41,7 @@ func (l *LocalExecutor) PHPCommand(ctx context.Context, args ...string) *Process
 	applyLocalEnv(l.projectRoot, l.env, cmd)
 	applyDir(resolveDir(l.projectRoot, l.relDir), cmd)
 	logCmd(ctx, cmd)
-	return newProcess(cmd)
+	return newProcess(ctx, cmd)
 }
 
 func (l *LocalExecutor) NPMCommand(ctx context.Context, args ...string) *Process {
@@ -49,7 +49,7 @@ func (l *LocalExecutor) NPMCommand(ctx context.Context, args ...string) *Process
 	applyLocalEnv(l.projectRoot, l.env, cmd)
 	applyDir(resolveDir(l.projectRoot, l.relDir), cmd)
 	logCmd(ctx, cmd)
-	return newProcess(cmd)
+	return newProcess(ctx, cmd)
 }
 
 func (l *LocalExecutor) NormalizePath(hostPath string) string {
@@ -2,14 +2,24 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"syscall"
+
+	"github.com/shopware/shopware-cli/internal/tracing"
 )
 
 type Process struct {
 	Cmd  *exec.Cmd
 	stop func(ctx context.Context) error
+
+	// ctx is the context the command was created with, used as parent for the
+	// span recorded while the process runs. It is nil for processes built as literal.
+	ctx  context.Context
+	span *tracing.Span
 }
 
 func (p *Process) Stop(ctx context.Context) error {
@@ -25,14 +35,15 @@ func (p *Process) Stop(ctx context.Context) error {
 }
 
 func (p *Process) Run() error {
-	return p.Cmd.Run()
+	p.startSpan()
+	return p.endSpan(p.Cmd.Run())
 }
 
 // RunWithOutput runs the command and streams its combined stdout/stderr to w.
 func (p *Process) RunWithOutput(w io.Writer) error {
 	p.Cmd.Stdout = w
 	p.Cmd.Stderr = w
-	return p.Cmd.Run()
+	return p.Run()
 }
 
 // StartCombined starts the command with its stdout and stderr merged into a
@@ -46,7 +57,7 @@ func (p *Process) StartCombined() (io.ReadCloser, error) {
 	}
 	p.Cmd.Stderr = p.Cmd.Stdout
 
-	if err := p.Cmd.Start(); err != nil {
+	if err := p.Start(); err != nil {
 		return nil, err
 	}
 
@@ -54,19 +65,29 @@ func (p *Process) StartCombined() (io.ReadCloser, error) {
 }
 
 func (p *Process) Output() ([]byte, error) {
-	return p.Cmd.Output()
+	p.startSpan()
+	out, err := p.Cmd.Output()
+	return out, p.endSpan(err)
 }
 
 func (p *Process) CombinedOutput() ([]byte, error) {
-	return p.Cmd.CombinedOutput()
+	p.startSpan()
+	out, err := p.Cmd.CombinedOutput()
+	return out, p.endSpan(err)
 }
 
 func (p *Process) Start() error {
-	return p.Cmd.Start()
+	p.startSpan()
+
+	if err := p.Cmd.Start(); err != nil {
+		return p.endSpan(err)
+	}
+
+	return nil
 }
 
 func (p *Process) Wait() error {
-	return p.Cmd.Wait()
+	return p.endSpan(p.Cmd.Wait())
 }
 
 func (p *Process) StdoutPipe() (io.ReadCloser, error) {
@@ -77,6 +98,46 @@ func (p *Process) StderrPipe() (io.ReadCloser, error) {
 	return p.Cmd.StderrPipe()
 }
 
-func newProcess(cmd *exec.Cmd) *Process {
-	return &Process{Cmd: cmd}
+// startSpan opens the trace span of the process and hands it to the child over TRACEPARENT.
+func (p *Process) startSpan() {
+	if p.ctx == nil || p.span != nil || !tracing.Enabled() {
+		return
+	}
+
+	ctx, span := tracing.Start(p.ctx, "exec "+filepath.Base(p.Cmd.Path),
+		tracing.StringSlice("process.command_args", p.Cmd.Args),
+		tracing.String("process.working_directory", p.Cmd.Dir),
+	)
+	p.span = span
+
+	if p.Cmd.Env == nil {
+		p.Cmd.Env = os.Environ()
+	}
+
+	p.Cmd.Env = append(p.Cmd.Env, tracing.Environ(ctx)...)
+}
+
+func (p *Process) endSpan(err error) error {
+	if p.span == nil {
+		return err
+	}
+
+	exitCode := -1
+	if p.Cmd.ProcessState != nil {
+		exitCode = p.Cmd.ProcessState.ExitCode()
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+
+	p.span.SetAttributes(tracing.Int("process.exit_code", exitCode))
+	p.span.End(err)
+
+	return err
+}
+
+func newProcess(ctx context.Context, cmd *exec.Cmd) *Process {
+	return &Process{Cmd: cmd, ctx: ctx}
 }
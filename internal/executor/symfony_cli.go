@@ -24,7 +24,7 @@ func (s *SymfonyCLIExecutor) ConsoleCommand(ctx context.Context, args ...string)
 	applyLocalEnv(s.projectRoot, s.env, cmd)
 	applyDir(resolveDir(s.projectRoot, s.relDir), cmd)
 	logCmd(ctx, cmd)
-	return newProcess(cmd)
+	return newProcess(ctx, cmd)
 }
 
 func (s *SymfonyCLIExecutor) ComposerCommand(ctx context.Context, args ...string) *Process {
@@ -34,7 +34,7 @@ func (s *SymfonyCLIExecutor) ComposerCommand(ctx context.Context, args ...string
 	applyLocalEnv(s.projectRoot, s.env, cmd)
 	applyDir(resolveDir(s.projectRoot, s.relDir), cmd)
 	logCmd(ctx, cmd)
-	return newProcess(cmd)
+	return newProcess(ctx, cmd)
 }
 
 func (s *SymfonyCLIExecutor) PHPCommand(ctx context.Context, args ...string) *Process {
@@ -44,7 +44,7 @@ func (s *SymfonyCLIExecutor) PHPCommand(ctx context.Context, args ...string) *Pr
 	applyLocalEnv(s.projectRoot, s.env, cmd)
 	applyDir(resolveDir(s.projectRoot, s.relDir), cmd)
 	logCmd(ctx, cmd)
-	return newProcess(cmd)
+	return newProcess(ctx, cmd)
 }
 
 func (s *SymfonyCLIExecutor) NPMCommand(ctx context.Context, args ...string) *Process {
@@ -52,7 +52,7 @@ func (s *SymfonyCLIExecutor) NPMCommand(ctx context.Context, args ...string) *Pr
 	applyLocalEnv(s.projectRoot, s.env, cmd)
 	applyDir(resolveDir(s.projectRoot, s.relDir), cmd)
 	logCmd(ctx, cmd)
-	return newProcess(cmd)
+	return newProcess(ctx, cmd)
 }
 
 func (s *SymfonyCLIExecutor) NormalizePath(hostPath string) string {
@@ -84,6 +84,11 @@ func (a App) GetLicense() (string, error) {
 	return a.manifest.Meta.License, nil
 }
 
+// GetManifest returns the parsed manifest.xml.
+func (a App) GetManifest() Manifest {
+	return a.manifest
+}
+
 func (a App) GetExtensionConfig() *Config {
 	return a.config
 }
@@ -226,7 +231,7 @@ func updateTranslatableXMLElement(parent *xmlpath.Element, name string, translat
 	}
 }
 
-func (a App) Validate(_ context.Context, check validation.Check) {
+func (a App) Validate(ctx context.Context, check validation.Check) {
 	validateTheme(a, check)
 
 	validateExtensionIcon(a, check)
@@ -290,4 +295,6 @@ func (a App) Validate(_ context.Context, check validation.Check) {
 			Severity:   validation.SeverityError,
 		})
 	}
+
+	validateManifest(ctx, a, check)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
@@ -16,6 +17,7 @@ import (
 	"github.com/shopware/shopware-cli/internal/ci"
 	"github.com/shopware/shopware-cli/internal/esbuild"
 	"github.com/shopware/shopware-cli/internal/npm"
+	"github.com/shopware/shopware-cli/internal/tracing"
 	"github.com/shopware/shopware-cli/logging"
 )
 
@@ -64,15 +66,14 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 		defer deletePaths(ctx, shopwareRoot)
 	}
 
-	nodeInstallSection := ci.Default.Section(ctx, "Installing node_modules for extensions")
+	nodeInstallCtx, nodeInstallSection := ci.Default.Section(ctx, "Installing node_modules for extensions")
 
-	paths, err := InstallNodeModulesOfConfigs(ctx, cfgs, assetConfig)
+	paths, err := InstallNodeModulesOfConfigs(nodeInstallCtx, cfgs, assetConfig)
+	nodeInstallSection.End(nodeInstallCtx, err)
 	if err != nil {
 		return err
 	}
 
-	nodeInstallSection.End(ctx)
-
 	if shopwareRoot != "" && len(assetConfig.KeepNodeModules) > 0 {
 		paths = slices.DeleteFunc(paths, func(path string) bool {
 			rel, err := filepath.Rel(shopwareRoot, path)
@@ -87,146 +88,52 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 	defer deletePaths(ctx, paths...)
 
 	if !assetConfig.DisableAdminBuild && cfgs.RequiresAdminBuild() {
-		administrationSection := ci.Default.Section(ctx, "Building administration assets")
-
-		// Build all extensions compatible with esbuild first
-		for name, entry := range cfgs.FilterByAdminAndEsBuild(true) {
-			options := esbuild.NewAssetCompileOptionsAdmin(name, entry.BasePath)
-			options.DisableSass = entry.DisableSass
-
-			res, err := esbuild.CompileExtensionAsset(ctx, options)
-			if err != nil {
-				return err
-			}
-
-			if err := esbuild.DumpViteConfig(options, res); err != nil {
-				return err
-			}
-
-			logging.FromContext(ctx).Infof("Building administration assets for %s using ESBuild", name)
+		if err := buildAdministrationAssets(ctx, cfgs, assetConfig, shopwareRoot); err != nil {
+			return err
 		}
+	}
 
-		nonCompatibleExtensions := cfgs.FilterByAdminAndEsBuild(false)
-
-		if len(nonCompatibleExtensions) != 0 {
-			if projectRequiresBuild(shopwareRoot) {
-				// add the storefront itself as plugin into json
-				var basePath string
-				if shopwareRoot == "" {
-					basePath = "src/Storefront/"
-				} else {
-					basePath = strings.TrimLeft(
-						strings.Replace(PlatformPath(shopwareRoot, "Storefront", ""), shopwareRoot, "", 1),
-						"/",
-					) + "/"
-				}
-
-				storefrontEntryPath := "Resources/app/storefront/src/main.js"
-				adminEntryPath := "Resources/app/administration/src/main.js"
-				nonCompatibleExtensions["Storefront"] = &ExtensionAssetConfigEntry{
-					BasePath:      basePath,
-					Views:         []string{"Resources/views"},
-					TechnicalName: "storefront",
-					Storefront: ExtensionAssetConfigStorefront{
-						Path:          "Resources/app/storefront/src",
-						EntryFilePath: &storefrontEntryPath,
-						StyleFiles:    []string{},
-					},
-					Administration: ExtensionAssetConfigAdmin{
-						Path:          "Resources/app/administration/src",
-						EntryFilePath: &adminEntryPath,
-					},
-				}
-			}
-
-			if err := prepareShopwareForAsset(shopwareRoot, nonCompatibleExtensions, assetConfig); err != nil {
-				return err
-			}
-
-			administrationRoot := PlatformPath(shopwareRoot, "Administration", "Resources/app/administration")
-			adminRelPath := PlatformRelPath(shopwareRoot, "Administration", "Resources/app/administration")
-
-			if assetConfig.NPMForceInstall || !npm.NodeModulesExists(administrationRoot) {
-				var additionalNpmParameters []string
-
-				npmPackage, err := npm.ReadPackage(administrationRoot)
-				if err != nil {
-					return err
-				}
-
-				if npmPackage.HasDevDependency("puppeteer") {
-					additionalNpmParameters = []string{"--production"}
-				}
-
-				if err := npm.InstallDependencies(ctx, assetConfig.ExecutorWithRelDir(adminRelPath), npmPackage, additionalNpmParameters...); err != nil {
-					return err
-				}
-			}
-
-			envMap := map[string]string{
-				"PROJECT_ROOT": assetConfig.NormalizePath(shopwareRoot),
-				"ADMIN_ROOT":   assetConfig.NormalizePath(PlatformPath(shopwareRoot, "Administration", "")),
-			}
-
-			if !projectRequiresBuild(shopwareRoot) && !assetConfig.ForceAdminBuild {
-				logging.FromContext(ctx).Debugf("Building only administration assets for plugins")
-				envMap["SHOPWARE_ADMIN_BUILD_ONLY_EXTENSIONS"] = "1"
-				envMap["SHOPWARE_ADMIN_SKIP_SOURCEMAP_GENERATION"] = "1"
-			} else {
-				logging.FromContext(ctx).Debugf("Building also the administration itself")
-			}
-
-			adminExec := assetConfig.ExecutorWithRelDir(adminRelPath).WithEnv(envMap)
-			npmBuild := adminExec.NPMCommand(ctx, "run", "build")
-			npmBuild.Cmd.Stdout = os.Stdout
-			npmBuild.Cmd.Stderr = os.Stderr
-			err = npmBuild.Run()
-
-			if assetConfig.CleanupNodeModules {
-				defer deletePaths(ctx, path.Join(administrationRoot, "node_modules"), path.Join(administrationRoot, "twigVuePlugin"))
-			}
-
-			if err != nil {
-				return err
-			}
-
-			for name, entry := range nonCompatibleExtensions {
-				options := esbuild.NewAssetCompileOptionsAdmin(name, entry.BasePath)
-				if err := esbuild.DumpViteConfig(options); err != nil {
-					return err
-				}
-			}
+	if !assetConfig.DisableStorefrontBuild && cfgs.RequiresStorefrontBuild() {
+		if err := buildStorefrontAssets(ctx, cfgs, assetConfig, shopwareRoot, getMinVersion); err != nil {
+			return err
 		}
+	}
 
-		administrationSection.End(ctx)
+	if err := storeAssetCaches(ctx, cfgs, assetConfig); err != nil {
+		return err
 	}
 
-	if !assetConfig.DisableStorefrontBuild && cfgs.RequiresStorefrontBuild() {
-		storefrontSection := ci.Default.Section(ctx, "Building storefront assets")
-		// Build all extensions compatible with esbuild first
-		for name, entry := range cfgs.FilterByStorefrontAndEsBuild(true) {
-			isNewLayout := false
+	return nil
+}
 
-			mv, err := getMinVersion()
-			if err != nil {
-				return err
-			}
+// buildAdministrationAssets builds the administration assets of the extensions with esbuild or webpack.
+func buildAdministrationAssets(ctx context.Context, cfgs ExtensionAssetConfig, assetConfig AssetBuildConfig, shopwareRoot string) (err error) {
+	ctx, section := ci.Default.Section(ctx, "Building administration assets")
+	defer func() { section.End(ctx, err) }()
 
-			if mv == DevVersionNumber || version.Must(version.NewVersion(mv)).GreaterThanOrEqual(version.Must(version.NewVersion("6.6.0.0"))) {
-				isNewLayout = true
-			}
+	// Build all extensions compatible with esbuild first
+	for name, entry := range cfgs.FilterByAdminAndEsBuild(true) {
+		options := esbuild.NewAssetCompileOptionsAdmin(name, entry.BasePath)
+		options.DisableSass = entry.DisableSass
 
-			options := esbuild.NewAssetCompileOptionsStorefront(name, entry.BasePath, isNewLayout)
+		buildCtx, buildSpan := startAssetBuildSpan(ctx, "administration", "esbuild", name)
+		res, err := esbuild.CompileExtensionAsset(buildCtx, options)
+		buildSpan.End(err)
+		if err != nil {
+			return err
+		}
 
-			if _, err := esbuild.CompileExtensionAsset(ctx, options); err != nil {
-				return err
-			}
-			logging.FromContext(ctx).Infof("Building storefront assets for %s using ESBuild", name)
+		if err := esbuild.DumpViteConfig(options, res); err != nil {
+			return err
 		}
 
-		nonCompatibleExtensions := cfgs.FilterByStorefrontAndEsBuild(false)
+		logging.FromContext(ctx).Infof("Building administration assets for %s using ESBuild", name)
+	}
+
+	nonCompatibleExtensions := cfgs.FilterByAdminAndEsBuild(false)
 
-		if len(nonCompatibleExtensions) != 0 {
+	if len(nonCompatibleExtensions) != 0 {
+		if projectRequiresBuild(shopwareRoot) {
 			// add the storefront itself as plugin into json
 			var basePath string
 			if shopwareRoot == "" {
@@ -238,114 +145,242 @@ func BuildAssetsForExtensions(ctx context.Context, sources []asset.Source, asset
 				) + "/"
 			}
 
-			entryPath := "Resources/app/storefront/src/main.js"
+			storefrontEntryPath := "Resources/app/storefront/src/main.js"
+			adminEntryPath := "Resources/app/administration/src/main.js"
 			nonCompatibleExtensions["Storefront"] = &ExtensionAssetConfigEntry{
 				BasePath:      basePath,
 				Views:         []string{"Resources/views"},
 				TechnicalName: "storefront",
 				Storefront: ExtensionAssetConfigStorefront{
 					Path:          "Resources/app/storefront/src",
-					EntryFilePath: &entryPath,
+					EntryFilePath: &storefrontEntryPath,
 					StyleFiles:    []string{},
 				},
 				Administration: ExtensionAssetConfigAdmin{
-					Path: "Resources/app/administration/src",
+					Path:          "Resources/app/administration/src",
+					EntryFilePath: &adminEntryPath,
 				},
 			}
+		}
 
-			if err := prepareShopwareForAsset(shopwareRoot, nonCompatibleExtensions, assetConfig); err != nil {
+		if err := prepareShopwareForAsset(shopwareRoot, nonCompatibleExtensions, assetConfig); err != nil {
+			return err
+		}
+
+		administrationRoot := PlatformPath(shopwareRoot, "Administration", "Resources/app/administration")
+		adminRelPath := PlatformRelPath(shopwareRoot, "Administration", "Resources/app/administration")
+
+		if assetConfig.NPMForceInstall || !npm.NodeModulesExists(administrationRoot) {
+			var additionalNpmParameters []string
+
+			npmPackage, err := npm.ReadPackage(administrationRoot)
+			if err != nil {
 				return err
 			}
 
-			storefrontRoot := PlatformPath(shopwareRoot, "Storefront", "Resources/app/storefront")
-			storefrontRelPath := PlatformRelPath(shopwareRoot, "Storefront", "Resources/app/storefront")
-			sfExec := assetConfig.ExecutorWithRelDir(storefrontRelPath)
+			if npmPackage.HasDevDependency("puppeteer") {
+				additionalNpmParameters = []string{"--production"}
+			}
 
-			npmPackage, err := npm.ReadPackage(storefrontRoot)
-			if err != nil {
+			if err := npm.InstallDependencies(ctx, assetConfig.ExecutorWithRelDir(adminRelPath), npmPackage, additionalNpmParameters...); err != nil {
 				return err
 			}
+		}
 
-			if assetConfig.NPMForceInstall || !npm.NodeModulesExists(storefrontRoot) {
-				if err := npm.PatchPackageLockToRemoveCanIUse(path.Join(storefrontRoot, "package-lock.json")); err != nil {
-					return err
-				}
+		envMap := map[string]string{
+			"PROJECT_ROOT": assetConfig.NormalizePath(shopwareRoot),
+			"ADMIN_ROOT":   assetConfig.NormalizePath(PlatformPath(shopwareRoot, "Administration", "")),
+		}
 
-				additionalNpmParameters := []string{"caniuse-lite"}
+		if !projectRequiresBuild(shopwareRoot) && !assetConfig.ForceAdminBuild {
+			logging.FromContext(ctx).Debugf("Building only administration assets for plugins")
+			envMap["SHOPWARE_ADMIN_BUILD_ONLY_EXTENSIONS"] = "1"
+			envMap["SHOPWARE_ADMIN_SKIP_SOURCEMAP_GENERATION"] = "1"
+		} else {
+			logging.FromContext(ctx).Debugf("Building also the administration itself")
+		}
 
-				if npmPackage.HasDevDependency("puppeteer") {
-					additionalNpmParameters = append(additionalNpmParameters, "--production")
-				}
+		adminExec := assetConfig.ExecutorWithRelDir(adminRelPath).WithEnv(envMap)
+		buildCtx, buildSpan := startAssetBuildSpan(ctx, "administration", "webpack", slices.Sorted(maps.Keys(nonCompatibleExtensions))...)
+		npmBuild := adminExec.NPMCommand(buildCtx, "run", "build")
+		npmBuild.Cmd.Stdout = os.Stdout
+		npmBuild.Cmd.Stderr = os.Stderr
+		err = npmBuild.Run()
+		buildSpan.End(err)
 
-				if err := npm.InstallDependencies(ctx, sfExec, npmPackage, additionalNpmParameters...); err != nil {
-					return err
-				}
+		if assetConfig.CleanupNodeModules {
+			defer deletePaths(ctx, path.Join(administrationRoot, "node_modules"), path.Join(administrationRoot, "twigVuePlugin"))
+		}
 
-				// As we call npm install caniuse-lite, we need to run the postinstall script manually.
-				if npmPackage.HasScript("postinstall") {
-					npmRunPostInstall := sfExec.NPMCommand(ctx, "run", "postinstall")
-					npmRunPostInstall.Cmd.Stdout = os.Stdout
-					npmRunPostInstall.Cmd.Stderr = os.Stderr
+		if err != nil {
+			return err
+		}
 
-					if err := npmRunPostInstall.Run(); err != nil {
-						return err
-					}
-				}
+		for name, entry := range nonCompatibleExtensions {
+			options := esbuild.NewAssetCompileOptionsAdmin(name, entry.BasePath)
+			if err := esbuild.DumpViteConfig(options); err != nil {
+				return err
+			}
+		}
+	}
 
-				if _, err := os.Stat(path.Join(storefrontRoot, "vendor/bootstrap")); os.IsNotExist(err) {
-					npmVendor := sfExec.NPMCommand(ctx, "exec", "--", "node", "copy-to-vendor.js")
-					npmVendor.Cmd.Stdout = os.Stdout
-					npmVendor.Cmd.Stderr = os.Stderr
-					if err := npmVendor.Run(); err != nil {
-						return err
-					}
-				}
+	return nil
+}
+
+// buildStorefrontAssets builds the storefront assets of the extensions with esbuild or webpack.
+func buildStorefrontAssets(ctx context.Context, cfgs ExtensionAssetConfig, assetConfig AssetBuildConfig, shopwareRoot string, getMinVersion func() (string, error)) (err error) {
+	ctx, section := ci.Default.Section(ctx, "Building storefront assets")
+	defer func() { section.End(ctx, err) }()
+
+	// Build all extensions compatible with esbuild first
+	for name, entry := range cfgs.FilterByStorefrontAndEsBuild(true) {
+		isNewLayout := false
+
+		mv, err := getMinVersion()
+		if err != nil {
+			return err
+		}
+
+		if mv == DevVersionNumber || version.Must(version.NewVersion(mv)).GreaterThanOrEqual(version.Must(version.NewVersion("6.6.0.0"))) {
+			isNewLayout = true
+		}
+
+		options := esbuild.NewAssetCompileOptionsStorefront(name, entry.BasePath, isNewLayout)
+
+		buildCtx, buildSpan := startAssetBuildSpan(ctx, "storefront", "esbuild", name)
+		_, err = esbuild.CompileExtensionAsset(buildCtx, options)
+		buildSpan.End(err)
+		if err != nil {
+			return err
+		}
+		logging.FromContext(ctx).Infof("Building storefront assets for %s using ESBuild", name)
+	}
+
+	nonCompatibleExtensions := cfgs.FilterByStorefrontAndEsBuild(false)
+
+	if len(nonCompatibleExtensions) != 0 {
+		// add the storefront itself as plugin into json
+		var basePath string
+		if shopwareRoot == "" {
+			basePath = "src/Storefront/"
+		} else {
+			basePath = strings.TrimLeft(
+				strings.Replace(PlatformPath(shopwareRoot, "Storefront", ""), shopwareRoot, "", 1),
+				"/",
+			) + "/"
+		}
+
+		entryPath := "Resources/app/storefront/src/main.js"
+		nonCompatibleExtensions["Storefront"] = &ExtensionAssetConfigEntry{
+			BasePath:      basePath,
+			Views:         []string{"Resources/views"},
+			TechnicalName: "storefront",
+			Storefront: ExtensionAssetConfigStorefront{
+				Path:          "Resources/app/storefront/src",
+				EntryFilePath: &entryPath,
+				StyleFiles:    []string{},
+			},
+			Administration: ExtensionAssetConfigAdmin{
+				Path: "Resources/app/administration/src",
+			},
+		}
+
+		if err := prepareShopwareForAsset(shopwareRoot, nonCompatibleExtensions, assetConfig); err != nil {
+			return err
+		}
+
+		storefrontRoot := PlatformPath(shopwareRoot, "Storefront", "Resources/app/storefront")
+		storefrontRelPath := PlatformRelPath(shopwareRoot, "Storefront", "Resources/app/storefront")
+		sfExec := assetConfig.ExecutorWithRelDir(storefrontRelPath)
+
+		npmPackage, err := npm.ReadPackage(storefrontRoot)
+		if err != nil {
+			return err
+		}
+
+		if assetConfig.NPMForceInstall || !npm.NodeModulesExists(storefrontRoot) {
+			if err := npm.PatchPackageLockToRemoveCanIUse(path.Join(storefrontRoot, "package-lock.json")); err != nil {
+				return err
 			}
 
-			sfEnvMap := map[string]string{
-				"NODE_ENV":        "production",
-				"PROJECT_ROOT":    assetConfig.NormalizePath(shopwareRoot),
-				"STOREFRONT_ROOT": assetConfig.NormalizePath(storefrontRoot),
+			additionalNpmParameters := []string{"caniuse-lite"}
+
+			if npmPackage.HasDevDependency("puppeteer") {
+				additionalNpmParameters = append(additionalNpmParameters, "--production")
 			}
 
-			if assetConfig.Browserslist != "" {
-				sfEnvMap["BROWSERSLIST"] = assetConfig.Browserslist
+			if err := npm.InstallDependencies(ctx, sfExec, npmPackage, additionalNpmParameters...); err != nil {
+				return err
 			}
 
-			storefrontBuildExec := sfExec.WithEnv(sfEnvMap)
-			if npmPackage.HasScript("production") {
-				npmProduction := storefrontBuildExec.NPMCommand(ctx, "run", "production")
-				npmProduction.Cmd.Stdout = os.Stdout
-				npmProduction.Cmd.Stderr = os.Stderr
+			// As we call npm install caniuse-lite, we need to run the postinstall script manually.
+			if npmPackage.HasScript("postinstall") {
+				npmRunPostInstall := sfExec.NPMCommand(ctx, "run", "postinstall")
+				npmRunPostInstall.Cmd.Stdout = os.Stdout
+				npmRunPostInstall.Cmd.Stderr = os.Stderr
 
-				if err := npmProduction.Run(); err != nil {
+				if err := npmRunPostInstall.Run(); err != nil {
 					return err
 				}
-			} else {
-				nodeWebpackCmd := storefrontBuildExec.NPMCommand(ctx, "exec", "--", "webpack", "--config", "webpack.config.js")
-				nodeWebpackCmd.Cmd.Stdout = os.Stdout
-				nodeWebpackCmd.Cmd.Stderr = os.Stderr
+			}
 
-				if err := nodeWebpackCmd.Run(); err != nil {
+			if _, err := os.Stat(path.Join(storefrontRoot, "vendor/bootstrap")); os.IsNotExist(err) {
+				npmVendor := sfExec.NPMCommand(ctx, "exec", "--", "node", "copy-to-vendor.js")
+				npmVendor.Cmd.Stdout = os.Stdout
+				npmVendor.Cmd.Stderr = os.Stderr
+				if err := npmVendor.Run(); err != nil {
 					return err
 				}
 			}
+		}
 
-			if assetConfig.CleanupNodeModules {
-				defer deletePaths(ctx, path.Join(storefrontRoot, "node_modules"))
-			}
+		sfEnvMap := map[string]string{
+			"NODE_ENV":        "production",
+			"PROJECT_ROOT":    assetConfig.NormalizePath(shopwareRoot),
+			"STOREFRONT_ROOT": assetConfig.NormalizePath(storefrontRoot),
 		}
 
-		storefrontSection.End(ctx)
-	}
+		if assetConfig.Browserslist != "" {
+			sfEnvMap["BROWSERSLIST"] = assetConfig.Browserslist
+		}
 
-	if err := storeAssetCaches(ctx, cfgs, assetConfig); err != nil {
-		return err
+		storefrontBuildExec := sfExec.WithEnv(sfEnvMap)
+		buildCtx, buildSpan := startAssetBuildSpan(ctx, "storefront", "webpack", slices.Sorted(maps.Keys(nonCompatibleExtensions))...)
+		if npmPackage.HasScript("production") {
+			npmProduction := storefrontBuildExec.NPMCommand(buildCtx, "run", "production")
+			npmProduction.Cmd.Stdout = os.Stdout
+			npmProduction.Cmd.Stderr = os.Stderr
+
+			err = npmProduction.Run()
+		} else {
+			nodeWebpackCmd := storefrontBuildExec.NPMCommand(buildCtx, "exec", "--", "webpack", "--config", "webpack.config.js")
+			nodeWebpackCmd.Cmd.Stdout = os.Stdout
+			nodeWebpackCmd.Cmd.Stderr = os.Stderr
+
+			err = nodeWebpackCmd.Run()
+		}
+		buildSpan.End(err)
+
+		if err != nil {
+			return err
+		}
+
+		if assetConfig.CleanupNodeModules {
+			defer deletePaths(ctx, path.Join(storefrontRoot, "node_modules"))
+		}
 	}
 
 	return nil
 }
 
+func startAssetBuildSpan(ctx context.Context, kind, builder string, extensions ...string) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, fmt.Sprintf("build %s assets", kind),
+		tracing.String("asset.type", kind),
+		tracing.String("asset.builder", builder),
+		tracing.StringSlice("asset.extensions", extensions),
+	)
+}
+
 func prepareShopwareForAsset(shopwareRoot string, cfgs ExtensionAssetConfig, assetConfig AssetBuildConfig) error {
 	varFolder := fmt.Sprintf("%s/var", shopwareRoot)
 	if _, err := os.Stat(varFolder); os.IsNotExist(err) {
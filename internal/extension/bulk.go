@@ -0,0 +1,241 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// discoverDepth is how deep DiscoverExtensions looks for extensions below
+// the root, monorepos usually keep them in plugins/* or src/plugins/*.
+const discoverDepth = 3
+
+// ResolveExtensions returns the extensions of the paths and glob patterns.
+// Glob matches which are no extension are skipped, explicit paths have to be
+// extensions. With all, every extension below the first path or the working
+// directory is returned.
+func ResolveExtensions(ctx context.Context, patterns []string, all bool) ([]Extension, error) {
+	if all {
+		root := "."
+		if len(patterns) > 0 {
+			root = patterns[0]
+		}
+
+		return DiscoverExtensions(ctx, root)
+	}
+
+	var extensions []Extension
+	seen := map[string]bool{}
+
+	for _, pattern := range patterns {
+		paths := []string{pattern}
+		isGlob := strings.ContainsAny(pattern, "*?[")
+
+		if isGlob {
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+			}
+
+			paths = matches
+		}
+
+		for _, path := range paths {
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return nil, err
+			}
+
+			if seen[absPath] {
+				continue
+			}
+
+			if stat, err := os.Stat(absPath); isGlob && (err != nil || !stat.IsDir()) {
+				continue
+			}
+
+			ext, err := GetExtensionByFolder(ctx, absPath)
+			if err != nil {
+				if isGlob {
+					logging.FromContext(ctx).Debugf("Skipping %s: %v", absPath, err)
+					continue
+				}
+
+				return nil, fmt.Errorf("cannot open extension %s: %w", path, err)
+			}
+
+			seen[absPath] = true
+			extensions = append(extensions, ext)
+		}
+	}
+
+	if len(extensions) == 0 {
+		return nil, fmt.Errorf("no extensions found for %s", strings.Join(patterns, ", "))
+	}
+
+	return extensions, nil
+}
+
+// DiscoverExtensions returns the local extensions of a Shopware project, or
+// scans the directory for extensions when it is no project.
+func DiscoverExtensions(ctx context.Context, root string) ([]Extension, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if isProjectDirectory(root) {
+		extensions := FindExtensionsFromProject(logging.DisableLogger(ctx), root, true)
+		if len(extensions) == 0 {
+			return nil, fmt.Errorf("no extensions found in project %s", root)
+		}
+
+		return extensions, nil
+	}
+
+	var extensions []Extension
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		if rel != "." && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor" || d.Name() == "node_modules") {
+			return filepath.SkipDir
+		}
+
+		if ext, err := GetExtensionByFolder(ctx, path); err == nil {
+			extensions = append(extensions, ext)
+			return filepath.SkipDir
+		}
+
+		if rel != "." && strings.Count(rel, string(filepath.Separator))+1 >= discoverDepth {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(extensions) == 0 {
+		return nil, fmt.Errorf("no extensions found in %s", root)
+	}
+
+	return extensions, nil
+}
+
+func isProjectDirectory(root string) bool {
+	content, err := os.ReadFile(filepath.Join(root, "composer.json"))
+	if err != nil {
+		return false
+	}
+
+	var composerJson struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(content, &composerJson); err != nil {
+		return false
+	}
+
+	return composerJson.Type == "project"
+}
+
+// BulkResult is the outcome of an extension in RunBulk.
+type BulkResult struct {
+	Name      string
+	Extension Extension
+	Err       error
+}
+
+// RunBulk runs f for the extensions with at most concurrency at the same
+// time. An extension starts after the extensions it depends on finished, it
+// is skipped when one of them failed. The results are in dependency order.
+func RunBulk(ctx context.Context, extensions []Extension, concurrency int, f func(ctx context.Context, ext Extension) error) ([]BulkResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	byName := map[string]Extension{}
+	names := make([]string, 0, len(extensions))
+
+	for _, ext := range extensions {
+		name, err := ext.GetName()
+		if err != nil {
+			return nil, fmt.Errorf("get name of %s: %w", ext.GetPath(), err)
+		}
+
+		if _, ok := byName[name]; ok {
+			return nil, fmt.Errorf("the extension %s was found twice", name)
+		}
+
+		byName[name] = ext
+		names = append(names, name)
+	}
+
+	dependencies := Dependencies(extensions)
+
+	order, err := SortByDependencies(names, dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(order))
+	done := make(map[string]chan struct{}, len(order))
+	index := make(map[string]int, len(order))
+
+	for i, name := range order {
+		done[name] = make(chan struct{})
+		index[name] = i
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i, name := range order {
+		wg.Go(func() {
+			defer close(done[name])
+
+			results[i] = BulkResult{Name: name, Extension: byName[name]}
+
+			for _, dependency := range dependencies[name] {
+				<-done[dependency]
+
+				if results[index[dependency]].Err != nil {
+					results[i].Err = fmt.Errorf("skipped, the dependency %s failed", dependency)
+					return
+				}
+			}
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+
+			results[i].Err = f(ctx, byName[name])
+
+			<-semaphore
+		})
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
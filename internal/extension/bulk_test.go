@@ -0,0 +1,137 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBulkTestPlugin(t *testing.T, dir, name string, dependencies map[string]string) {
+	t.Helper()
+
+	requires := `"shopware/core": "~6.6.0"`
+	for composerName, constraint := range dependencies {
+		requires += fmt.Sprintf(`, %q: %q`, composerName, constraint)
+	}
+
+	assert.NoError(t, os.MkdirAll(dir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), fmt.Appendf(nil, `{
+		"name": "swag/%s",
+		"type": "shopware-platform-plugin",
+		"version": "1.0.0",
+		"require": {%s},
+		"autoload": {"psr-4": {"Swag\\%s\\": "src/"}},
+		"extra": {"shopware-plugin-class": "Swag\\%s\\%s", "label": {"en-GB": "%s"}}
+	}`, name, requires, name, name, name, name), 0o644))
+}
+
+func bulkTestNames(t *testing.T, extensions []Extension) []string {
+	t.Helper()
+
+	var names []string
+	for _, ext := range extensions {
+		name, err := ext.GetName()
+		require.NoError(t, err)
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func TestDiscoverExtensions(t *testing.T) {
+	root := t.TempDir()
+	writeBulkTestPlugin(t, filepath.Join(root, "plugins", "Base"), "Base", nil)
+	writeBulkTestPlugin(t, filepath.Join(root, "plugins", "Addon"), "Addon", nil)
+	writeBulkTestPlugin(t, filepath.Join(root, "plugins", "Addon", "vendor", "Nested"), "Nested", nil)
+	writeBulkTestPlugin(t, filepath.Join(root, "node_modules", "Ignored"), "Ignored", nil)
+
+	extensions, err := DiscoverExtensions(t.Context(), root)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Base", "Addon"}, bulkTestNames(t, extensions))
+
+	_, err = DiscoverExtensions(t.Context(), t.TempDir())
+	assert.ErrorContains(t, err, "no extensions found")
+}
+
+func TestResolveExtensions(t *testing.T) {
+	root := t.TempDir()
+	writeBulkTestPlugin(t, filepath.Join(root, "plugins", "Base"), "Base", nil)
+	writeBulkTestPlugin(t, filepath.Join(root, "plugins", "Addon"), "Addon", nil)
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "plugins", "docs"), 0o755))
+
+	extensions, err := ResolveExtensions(t.Context(), []string{filepath.Join(root, "plugins", "*"), filepath.Join(root, "plugins", "Base")}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Addon", "Base"}, bulkTestNames(t, extensions))
+
+	_, err = ResolveExtensions(t.Context(), []string{filepath.Join(root, "plugins", "docs")}, false)
+	assert.ErrorContains(t, err, "cannot open extension")
+
+	extensions, err = ResolveExtensions(t.Context(), []string{root}, true)
+	require.NoError(t, err)
+	assert.Len(t, extensions, 2)
+}
+
+func TestRunBulk(t *testing.T) {
+	root := t.TempDir()
+	writeBulkTestPlugin(t, filepath.Join(root, "Base"), "Base", nil)
+	writeBulkTestPlugin(t, filepath.Join(root, "Addon"), "Addon", map[string]string{"swag/Base": "*"})
+	writeBulkTestPlugin(t, filepath.Join(root, "Other"), "Other", nil)
+
+	extensions, err := DiscoverExtensions(t.Context(), root)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var finished []string
+	var running, maxRunning atomic.Int32
+
+	results, err := RunBulk(t.Context(), extensions, 1, func(_ context.Context, ext Extension) error {
+		if current := running.Add(1); current > maxRunning.Load() {
+			maxRunning.Store(current)
+		}
+		defer running.Add(-1)
+
+		name, _ := ext.GetName()
+
+		mu.Lock()
+		finished = append(finished, name)
+		mu.Unlock()
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), maxRunning.Load())
+	assert.Less(t, slices.Index(finished, "Base"), slices.Index(finished, "Addon"))
+	assert.Equal(t, []string{"Base", "Other", "Addon"}, bulkResultNames(results))
+
+	results, err = RunBulk(t.Context(), extensions, 2, func(_ context.Context, ext Extension) error {
+		if name, _ := ext.GetName(); name == "Base" {
+			return errors.New("broken")
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.EqualError(t, results[0].Err, "broken")
+	assert.NoError(t, results[1].Err)
+	assert.EqualError(t, results[2].Err, "skipped, the dependency Base failed")
+}
+
+func bulkResultNames(results []BulkResult) []string {
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+
+	return names
+}
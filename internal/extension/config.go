@@ -217,6 +217,9 @@ type ConfigValidation struct {
 	// Ignore items from the validation.
 	Ignore          ConfigValidationList `yaml:"ignore,omitempty"`
 	StoreCompliance bool                 `yaml:"store_compliance,omitempty"`
+	// Severity overrides the severity of results by identifier, like twig-linter/a11y-positive-tabindex: error.
+	// An identifier ending with * matches all identifiers with that prefix, off drops the results.
+	Severity map[string]string `yaml:"severity,omitempty"`
 	// PhpVersion overrides the PHP version used for linting (e.g. "8.4").
 	// When set, this takes precedence over the version derived from composer.json or the static Shopware-to-PHP mapping.
 	PhpVersion string `yaml:"php_version,omitempty"`
@@ -244,6 +247,13 @@ type Config struct {
 	Changelog changelog.Config `yaml:"changelog,omitempty"`
 	// Validation is the validation configuration of the extension.
 	Validation ConfigValidation `yaml:"validation,omitempty"`
+	// Account is the Shopware Account configuration of the extension.
+	Account ConfigAccount `yaml:"account,omitempty"`
+}
+
+type ConfigAccount struct {
+	// Profile of the Shopware Account used by the account commands inside this directory, see account profile list
+	Profile string `yaml:"profile,omitempty"`
 }
 
 func (c *Config) HasCompatibilityDate() bool {
@@ -258,6 +268,35 @@ func (c *Config) IsCompatibilityDateBefore(requiredDate string) bool {
 	return compatibility.IsBefore(c.CompatibilityDate, requiredDate)
 }
 
+// AccountProfileForDirectory returns the account profile of the nearest
+// extension config in the directory or its parents.
+func AccountProfileForDirectory(ctx context.Context, dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".shopware-extension.yml")); err == nil {
+			break
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, ".shopware-extension.yaml")); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+
+	config, err := readExtensionConfig(ctx, dir)
+	if err != nil {
+		logging.FromContext(ctx).Debugf("Cannot read extension config for the account profile: %v", err)
+		return ""
+	}
+
+	return config.Account.Profile
+}
+
 func readExtensionConfig(ctx context.Context, dir string) (*Config, error) {
 	config := &Config{}
 	config.Build.Zip.Assets.Enabled = true
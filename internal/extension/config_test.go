@@ -563,3 +563,16 @@ func TestValidateExtensionConfig(t *testing.T) {
 		assert.Contains(t, err.Error(), "must not escape")
 	})
 }
+
+func TestAccountProfileForDirectory(t *testing.T) {
+	tmpDir := t.TempDir()
+	nested := filepath.Join(tmpDir, "src", "Resources")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	assert.Equal(t, "", AccountProfileForDirectory(t.Context(), nested))
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".shopware-extension.yml"), []byte("compatibility_date: 2025-01-01\naccount:\n  profile: client-a\n"), 0o644))
+
+	assert.Equal(t, "client-a", AccountProfileForDirectory(t.Context(), nested))
+	assert.Equal(t, "client-a", AccountProfileForDirectory(t.Context(), tmpDir))
+}
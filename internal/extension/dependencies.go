@@ -0,0 +1,109 @@
+package extension
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// composerRequires returns the composer requirements of plugins and bundles,
+// apps cannot depend on other extensions.
+func composerRequires(ext Extension) map[string]string {
+	switch e := ext.(type) {
+	case *PlatformPlugin:
+		return e.Composer.Require
+	case PlatformPlugin:
+		return e.Composer.Require
+	case *ShopwareBundle:
+		return e.Composer.Require
+	case ShopwareBundle:
+		return e.Composer.Require
+	}
+
+	return nil
+}
+
+// Dependencies maps the name of every extension to the names of the given
+// extensions it requires with composer.
+func Dependencies(extensions []Extension) map[string][]string {
+	byComposerName := map[string]string{}
+
+	for _, ext := range extensions {
+		name, err := ext.GetName()
+		if err != nil {
+			continue
+		}
+
+		if composerName, err := ext.GetComposerName(); err == nil && composerName != "" {
+			byComposerName[composerName] = name
+		}
+	}
+
+	dependencies := map[string][]string{}
+
+	for _, ext := range extensions {
+		name, err := ext.GetName()
+		if err != nil {
+			continue
+		}
+
+		var requires []string
+
+		for composerName := range composerRequires(ext) {
+			if dependency, ok := byComposerName[composerName]; ok && dependency != name {
+				requires = append(requires, dependency)
+			}
+		}
+
+		slices.Sort(requires)
+		dependencies[name] = requires
+	}
+
+	return dependencies
+}
+
+// SortByDependencies orders the names so every extension comes after the
+// extensions it depends on. Independent names stay in alphabetical order,
+// dependencies outside of names are ignored.
+func SortByDependencies(names []string, dependencies map[string][]string) ([]string, error) {
+	remaining := slices.Clone(names)
+	slices.Sort(remaining)
+	remaining = slices.Compact(remaining)
+
+	sorted := make([]string, 0, len(remaining))
+	done := map[string]bool{}
+
+	for len(remaining) > 0 {
+		var next []string
+
+		for _, name := range remaining {
+			ready := true
+
+			for _, dependency := range dependencies[name] {
+				if !done[dependency] && slices.Contains(remaining, dependency) {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				next = append(next, name)
+			}
+		}
+
+		if len(next) == 0 {
+			return nil, fmt.Errorf("circular dependency between the extensions %s", strings.Join(remaining, ", "))
+		}
+
+		for _, name := range next {
+			done[name] = true
+		}
+
+		sorted = append(sorted, next...)
+		remaining = slices.DeleteFunc(remaining, func(name string) bool {
+			return done[name]
+		})
+	}
+
+	return sorted, nil
+}
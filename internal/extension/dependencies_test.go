@@ -0,0 +1,44 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPluginWithRequires(class, composerName string, require map[string]string) *PlatformPlugin {
+	plugin := &PlatformPlugin{}
+	plugin.Composer.Name = composerName
+	plugin.Composer.Extra.ShopwarePluginClass = class
+	plugin.Composer.Require = require
+
+	return plugin
+}
+
+func TestDependencies(t *testing.T) {
+	extensions := []Extension{
+		testPluginWithRequires("Swag\\Base\\SwagBase", "swag/base", map[string]string{"shopware/core": "*"}),
+		testPluginWithRequires("Swag\\Addon\\SwagAddon", "swag/addon", map[string]string{"swag/base": "*", "shopware/core": "*"}),
+	}
+
+	assert.Equal(t, map[string][]string{
+		"SwagBase":  nil,
+		"SwagAddon": {"SwagBase"},
+	}, Dependencies(extensions))
+}
+
+func TestSortByDependencies(t *testing.T) {
+	dependencies := map[string][]string{
+		"SwagAddon":   {"SwagBase"},
+		"SwagBase":    {"SwagCore"},
+		"SwagPayPal":  nil,
+		"SwagUnknown": {"NotInList"},
+	}
+
+	sorted, err := SortByDependencies([]string{"SwagAddon", "SwagPayPal", "SwagBase", "SwagCore", "SwagUnknown"}, dependencies)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SwagCore", "SwagPayPal", "SwagUnknown", "SwagBase", "SwagAddon"}, sorted)
+
+	_, err = SortByDependencies([]string{"A", "B"}, map[string][]string{"A": {"B"}, "B": {"A"}})
+	assert.ErrorContains(t, err, "circular dependency between the extensions A, B")
+}
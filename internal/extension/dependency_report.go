@@ -0,0 +1,323 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shyim/go-composer"
+	"github.com/shyim/go-composer/repository"
+	"github.com/shyim/go-composer/sbom"
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// shopwareComponents are the packages whose dependencies a shop always has installed.
+var shopwareComponents = []string{"core", "administration", "storefront", "elasticsearch"}
+
+// copyleftLicensePrefixes are licenses which require the bundling extension
+// to be distributed under a compatible license.
+var copyleftLicensePrefixes = []string{"GPL-", "AGPL-"}
+
+// DependencyReport lists the vendor packages bundled into the zip of an extension.
+type DependencyReport struct {
+	License          string           `json:"license"`
+	ShopwareVersions []string         `json:"shopwareVersions"`
+	Packages         []BundledPackage `json:"packages"`
+	lock             *composer.Lock
+}
+
+// BundledPackage is a vendor package which ships in the zip.
+type BundledPackage struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Licenses  []string          `json:"licenses"`
+	Conflicts []PackageConflict `json:"conflicts,omitempty"`
+	// LicenseIssue is set when the license does not allow bundling the package
+	LicenseIssue string `json:"licenseIssue,omitempty"`
+}
+
+// PackageConflict is a bundled package which Shopware ships as well.
+type PackageConflict struct {
+	ShopwareVersion string `json:"shopwareVersion"`
+	// Shipped is the version or constraint Shopware uses
+	Shipped string `json:"shipped"`
+	// Compatible is false when the bundled version does not match Shopware's
+	Compatible bool `json:"compatible"`
+}
+
+// HasIssues reports whether a package conflicts with Shopware or has a license issue.
+func (r DependencyReport) HasIssues() bool {
+	return slices.ContainsFunc(r.Packages, func(pkg BundledPackage) bool {
+		return pkg.LicenseIssue != "" || slices.ContainsFunc(pkg.Conflicts, func(c PackageConflict) bool { return !c.Compatible })
+	})
+}
+
+// BuildDependencyReport reports the vendor packages installed in dir, which is
+// the folder prepared by PrepareFolderForZipping. The packages are compared
+// against the lowest and highest Shopware version of the extension constraint.
+// When the Shopware versions or their packages cannot be looked up, the report
+// is built without conflicts and a warning is logged.
+func BuildDependencyReport(ctx context.Context, dir string, ext Extension) (*DependencyReport, error) {
+	license, err := ext.GetLicense()
+	if err != nil {
+		return nil, fmt.Errorf("get license: %w", err)
+	}
+
+	lock := &composer.Lock{}
+
+	if _, err := os.Stat(filepath.Join(dir, "vendor")); err == nil {
+		if lock, err = composer.ReadLock(filepath.Join(dir, "composer.lock")); err != nil {
+			return nil, fmt.Errorf("read composer.lock: %w", err)
+		}
+	}
+
+	if lock == nil {
+		lock = &composer.Lock{}
+	}
+
+	constraint, err := GetShopwareVersionConstraintForBuild(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	shopwareVersions, shipped, err := shippedPackagesForConstraint(ctx, constraint)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Cannot compare the bundled packages with Shopware: %s", err)
+	}
+
+	return newDependencyReport(lock, license, shopwareVersions, shipped), nil
+}
+
+// getShopwareVersionsFn can be overridden in tests to not query packagist.
+var getShopwareVersionsFn = GetShopwareVersions
+
+// shippedPackagesForConstraint returns the targeted Shopware versions and the packages each of them ships.
+func shippedPackagesForConstraint(ctx context.Context, constraint *version.Constraints) ([]string, map[string]map[string]string, error) {
+	available, err := getShopwareVersionsFn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shopwareVersions := targetedShopwareVersions(constraint, available)
+	shipped := map[string]map[string]string{}
+
+	for _, shopwareVersion := range shopwareVersions {
+		if shipped[shopwareVersion], err = ShopwareShippedPackages(ctx, shopwareVersion); err != nil {
+			return nil, nil, fmt.Errorf("get packages of Shopware %s: %w", shopwareVersion, err)
+		}
+	}
+
+	return shopwareVersions, shipped, nil
+}
+
+func newDependencyReport(lock *composer.Lock, license string, shopwareVersions []string, shipped map[string]map[string]string) *DependencyReport {
+	report := &DependencyReport{License: license, ShopwareVersions: shopwareVersions, Packages: []BundledPackage{}, lock: lock}
+
+	for _, pkg := range lock.Packages {
+		bundled := BundledPackage{
+			Name:         pkg.Name,
+			Version:      pkg.Version,
+			Licenses:     pkg.License,
+			LicenseIssue: licenseIssue(license, pkg.License),
+		}
+
+		for _, shopwareVersion := range shopwareVersions {
+			shippedVersion, ok := shipped[shopwareVersion][pkg.Name]
+			if !ok {
+				continue
+			}
+
+			bundled.Conflicts = append(bundled.Conflicts, PackageConflict{
+				ShopwareVersion: shopwareVersion,
+				Shipped:         shippedVersion,
+				Compatible:      versionMatches(pkg.Version, shippedVersion),
+			})
+		}
+
+		report.Packages = append(report.Packages, bundled)
+	}
+
+	slices.SortFunc(report.Packages, func(a, b BundledPackage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return report
+}
+
+// licenseIssue returns why a package with the licenses cannot be bundled into
+// an extension with the license. Several package licenses are alternatives.
+func licenseIssue(extensionLicense string, packageLicenses []string) string {
+	if len(packageLicenses) == 0 {
+		return "the package has no license"
+	}
+
+	for _, license := range packageLicenses {
+		if !isCopyleftLicense(license) || isCopyleftLicense(extensionLicense) {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("%s requires the extension to be licensed under a compatible license, it is %s", strings.Join(packageLicenses, " or "), extensionLicense)
+}
+
+func isCopyleftLicense(license string) bool {
+	return slices.ContainsFunc(copyleftLicensePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(license)), prefix)
+	})
+}
+
+// versionMatches reports whether the version satisfies the version or constraint Shopware uses.
+func versionMatches(bundled, shipped string) bool {
+	bundledVersion, err := version.NewVersion(strings.TrimPrefix(bundled, "v"))
+	if err != nil {
+		return bundled == shipped
+	}
+
+	constraint, err := version.NewConstraint(strings.TrimPrefix(shipped, "v"))
+	if err != nil {
+		return bundled == shipped
+	}
+
+	return constraint.Check(bundledVersion)
+}
+
+// targetedShopwareVersions returns the lowest and highest stable version matching the constraint.
+func targetedShopwareVersions(constraint *version.Constraints, available []string) []string {
+	var matching version.Collection
+
+	for _, v := range available {
+		parsed, err := version.NewVersion(v)
+		if err != nil || parsed.IsPrerelease() || (constraint != nil && !constraint.Check(parsed)) {
+			continue
+		}
+
+		matching = append(matching, parsed)
+	}
+
+	if len(matching) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(matching, func(a, b *version.Version) int {
+		return a.Compare(b)
+	})
+
+	lowest, highest := matching[0].String(), matching[len(matching)-1].String()
+	if lowest == highest {
+		return []string{lowest}
+	}
+
+	return []string{lowest, highest}
+}
+
+// ShopwareShippedPackages returns the packages a shop of the version has
+// installed. Versions before 6.5 use the embedded lock information with exact
+// versions, newer versions the requirements of the Shopware packages on packagist.
+func ShopwareShippedPackages(ctx context.Context, shopwareVersion string) (map[string]string, error) {
+	composerInfo, err := getComposerInfoFS()
+	if err != nil {
+		return nil, fmt.Errorf("get composer info fs: %w", err)
+	}
+
+	shipped := map[string]string{}
+
+	for _, component := range shopwareComponents {
+		file, err := composerInfo.Open(fmt.Sprintf("%s/%s.json", shopwareVersion, component))
+		if err != nil {
+			continue
+		}
+
+		content, err := io.ReadAll(file)
+		_ = file.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		var packages map[string]string
+		if err := json.Unmarshal(content, &packages); err != nil {
+			return nil, fmt.Errorf("unmarshal component version: %w", err)
+		}
+
+		for name, v := range packages {
+			shipped[name] = v
+		}
+	}
+
+	if len(shipped) > 0 {
+		return shipped, nil
+	}
+
+	client := repository.New(repository.PackagistURL, nil)
+
+	for _, component := range shopwareComponents {
+		pkg, err := client.GetPackage(ctx, "shopware/"+component)
+		if err != nil {
+			return nil, err
+		}
+
+		if pkg == nil {
+			continue
+		}
+
+		for _, v := range pkg.Versions {
+			if v.VersionNormalized != shopwareVersion {
+				continue
+			}
+
+			for name, constraint := range v.Require {
+				if strings.Contains(name, "/") && !strings.HasPrefix(name, "shopware/") {
+					shipped[name] = constraint
+				}
+			}
+		}
+	}
+
+	logging.FromContext(ctx).Debugf("Shopware %s ships %d packages", shopwareVersion, len(shipped))
+
+	return shipped, nil
+}
+
+// WriteSBOM writes the CycloneDX SBOM of the bundled packages.
+func (r DependencyReport) WriteSBOM(ext Extension, file, toolVersion string) error {
+	name, err := ext.GetComposerName()
+	if err != nil || name == "" {
+		if name, err = ext.GetName(); err != nil {
+			return err
+		}
+	}
+
+	extVersion := ""
+	if v, err := ext.GetVersion(); err == nil {
+		extVersion = v.String()
+	}
+
+	if toolVersion == "" {
+		toolVersion = "dev"
+	}
+
+	bom, err := sbom.Generate(r.lock, sbom.Options{
+		ApplicationName:    name,
+		ApplicationVersion: extVersion,
+		ToolGroup:          "shopware",
+		ToolName:           "shopware-cli",
+		ToolVersion:        toolVersion,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := sbom.Marshal(bom)
+	if err != nil {
+		return fmt.Errorf("marshal SBOM: %w", err)
+	}
+
+	return os.WriteFile(file, data, 0o644)
+}
@@ -0,0 +1,118 @@
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shyim/go-composer"
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyReport(t *testing.T) {
+	lock := &composer.Lock{
+		Packages: []composer.LockPackage{
+			{Name: "symfony/yaml", Version: "v5.4.1", License: []string{"MIT"}},
+			{Name: "guzzlehttp/guzzle", Version: "7.4.5", License: []string{"MIT"}},
+			{Name: "foo/gpl", Version: "1.0.0", License: []string{"GPL-3.0-or-later"}},
+			{Name: "foo/dual", Version: "1.0.0", License: []string{"GPL-2.0-only", "MIT"}},
+			{Name: "foo/unlicensed", Version: "1.0.0"},
+		},
+	}
+
+	shipped := map[string]map[string]string{
+		"6.4.0.0":  {"symfony/yaml": "v5.4.1", "guzzlehttp/guzzle": "7.4.5"},
+		"6.4.20.2": {"symfony/yaml": "v5.4.1", "guzzlehttp/guzzle": "~7.5.0"},
+	}
+
+	report := newDependencyReport(lock, "MIT", []string{"6.4.0.0", "6.4.20.2"}, shipped)
+
+	names := make([]string, 0, len(report.Packages))
+	for _, pkg := range report.Packages {
+		names = append(names, pkg.Name)
+	}
+
+	assert.Equal(t, []string{"foo/dual", "foo/gpl", "foo/unlicensed", "guzzlehttp/guzzle", "symfony/yaml"}, names)
+
+	assert.Empty(t, report.Packages[0].LicenseIssue)
+	assert.Contains(t, report.Packages[1].LicenseIssue, "GPL-3.0-or-later requires")
+	assert.Equal(t, "the package has no license", report.Packages[2].LicenseIssue)
+
+	assert.Equal(t, []PackageConflict{
+		{ShopwareVersion: "6.4.0.0", Shipped: "7.4.5", Compatible: true},
+		{ShopwareVersion: "6.4.20.2", Shipped: "~7.5.0", Compatible: false},
+	}, report.Packages[3].Conflicts)
+
+	assert.True(t, report.Packages[4].Conflicts[0].Compatible)
+	assert.True(t, report.HasIssues())
+
+	assert.Empty(t, newDependencyReport(lock, "GPL-3.0-or-later", nil, nil).Packages[1].LicenseIssue)
+}
+
+func TestBuildDependencyReportWithoutShopwareVersions(t *testing.T) {
+	original := getShopwareVersionsFn
+	t.Cleanup(func() {
+		getShopwareVersionsFn = original
+	})
+
+	getShopwareVersionsFn = func(context.Context) ([]string, error) {
+		return []string{"6.4.0.0", "6.5.0.0"}, nil
+	}
+
+	report, err := BuildDependencyReport(t.Context(), t.TempDir(), getTestPlugin(t.TempDir()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6.4.0.0"}, report.ShopwareVersions)
+
+	getShopwareVersionsFn = func(context.Context) ([]string, error) {
+		return nil, errors.New("packagist is not reachable")
+	}
+
+	report, err = BuildDependencyReport(t.Context(), t.TempDir(), getTestPlugin(t.TempDir()))
+	require.NoError(t, err, "packaging must not fail when the Shopware versions cannot be looked up")
+	assert.Empty(t, report.ShopwareVersions)
+	assert.Equal(t, "mit", report.License)
+}
+
+func TestTargetedShopwareVersions(t *testing.T) {
+	constraint, err := version.NewConstraint("~6.4.0")
+	require.NoError(t, err)
+
+	versions := targetedShopwareVersions(&constraint, []string{"6.3.5.1", "6.4.0.0", "6.4.20.2", "6.4.21.0-RC1", "6.5.0.0"})
+
+	assert.Equal(t, []string{"6.4.0.0", "6.4.20.2"}, versions)
+}
+
+func TestShopwareShippedPackagesFromComposerInfo(t *testing.T) {
+	shipped, err := ShopwareShippedPackages(t.Context(), "6.4.18.1")
+	require.NoError(t, err)
+
+	assert.Contains(t, shipped, "symfony/http-kernel")
+}
+
+func TestDependencyReportWriteSBOM(t *testing.T) {
+	report := newDependencyReport(&composer.Lock{
+		Packages: []composer.LockPackage{{Name: "symfony/yaml", Version: "v5.4.1", License: []string{"MIT"}}},
+	}, "MIT", nil, nil)
+
+	file := filepath.Join(t.TempDir(), "sbom.cdx.json")
+	require.NoError(t, report.WriteSBOM(&mockExtension{}, file, "1.0.0"))
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	var bom struct {
+		BOMFormat  string `json:"bomFormat"`
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+	}
+
+	require.NoError(t, json.Unmarshal(content, &bom))
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Len(t, bom.Components, 1)
+}
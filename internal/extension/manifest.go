@@ -1,20 +1,35 @@
 package extension
 
+import "encoding/xml"
+
+// Manifest is the manifest.xml of an app.
 type Manifest struct {
-	Meta  Meta   `xml:"meta"`
-	Setup *Setup `xml:"setup,omitempty"`
+	Meta         Meta          `xml:"meta"`
+	Setup        *Setup        `xml:"setup,omitempty"`
+	Storefront   *Storefront   `xml:"storefront,omitempty"`
+	Admin        *Admin        `xml:"admin,omitempty"`
+	Permissions  *Permissions  `xml:"permissions,omitempty"`
+	AllowedHosts *AllowedHosts `xml:"allowed-hosts,omitempty"`
+	Webhooks     *Webhooks     `xml:"webhooks,omitempty"`
+	CustomFields *CustomFields `xml:"custom-fields,omitempty"`
+	Cookies      *Cookies      `xml:"cookies,omitempty"`
+	Payments     *Payments     `xml:"payments,omitempty"`
+	Tax          *Tax          `xml:"tax,omitempty"`
+	Gateways     *Gateways     `xml:"gateways,omitempty"`
 }
 
 type Meta struct {
-	Name          string             `xml:"name"`
-	Label         TranslatableString `xml:"label"`
-	Description   TranslatableString `xml:"description,omitempty"`
-	Author        string             `xml:"author,omitempty"`
-	Copyright     string             `xml:"copyright,omitempty"`
-	Version       string             `xml:"version"`
-	Icon          string             `xml:"icon,omitempty"`
-	License       string             `xml:"license"`
-	Compatibility string             `xml:"compatibility,omitempty"`
+	Name                    string             `xml:"name"`
+	Label                   TranslatableString `xml:"label"`
+	Description             TranslatableString `xml:"description,omitempty"`
+	Author                  string             `xml:"author,omitempty"`
+	Copyright               string             `xml:"copyright,omitempty"`
+	Version                 string             `xml:"version"`
+	Icon                    string             `xml:"icon,omitempty"`
+	License                 string             `xml:"license"`
+	Compatibility           string             `xml:"compatibility,omitempty"`
+	Privacy                 string             `xml:"privacy,omitempty"`
+	PrivacyPolicyExtensions TranslatableString `xml:"privacyPolicyExtensions,omitempty"`
 }
 
 type Setup struct {
@@ -22,6 +37,163 @@ type Setup struct {
 	Secret          string `xml:"secret,omitempty"`
 }
 
+type Storefront struct {
+	TemplateLoadPriority int `xml:"template-load-priority,omitempty"`
+}
+
+type Admin struct {
+	ActionButtons []ActionButton `xml:"action-button"`
+	Modules       []AdminModule  `xml:"module"`
+	MainModule    *MainModule    `xml:"main-module,omitempty"`
+	BaseAppUrl    string         `xml:"base-app-url,omitempty"`
+}
+
+type ActionButton struct {
+	Action string             `xml:"action,attr"`
+	Entity string             `xml:"entity,attr"`
+	View   string             `xml:"view,attr"`
+	Url    string             `xml:"url,attr"`
+	Label  TranslatableString `xml:"label"`
+}
+
+type AdminModule struct {
+	Name     string             `xml:"name,attr"`
+	Source   string             `xml:"source,attr,omitempty"`
+	Parent   string             `xml:"parent,attr"`
+	Position int                `xml:"position,attr,omitempty"`
+	Label    TranslatableString `xml:"label"`
+}
+
+type MainModule struct {
+	Source string `xml:"source,attr"`
+}
+
+// Permissions lists the entities an app may access. Permission holds
+// additional privileges like system:cache:info.
+type Permissions struct {
+	Read       []string `xml:"read"`
+	Create     []string `xml:"create"`
+	Update     []string `xml:"update"`
+	Delete     []string `xml:"delete"`
+	Permission []string `xml:"permission"`
+}
+
+// Entities returns all entities referenced by read, create, update and delete.
+func (p Permissions) Entities() []string {
+	entities := make([]string, 0, len(p.Read)+len(p.Create)+len(p.Update)+len(p.Delete))
+	entities = append(entities, p.Read...)
+	entities = append(entities, p.Create...)
+	entities = append(entities, p.Update...)
+	entities = append(entities, p.Delete...)
+
+	return entities
+}
+
+type AllowedHosts struct {
+	Hosts []string `xml:"host"`
+}
+
+type Webhooks struct {
+	Webhooks []Webhook `xml:"webhook"`
+}
+
+type Webhook struct {
+	Name            string `xml:"name,attr"`
+	Url             string `xml:"url,attr"`
+	Event           string `xml:"event,attr"`
+	OnlyLiveVersion bool   `xml:"onlyLiveVersion,attr,omitempty"`
+}
+
+type CustomFields struct {
+	Sets []CustomFieldSet `xml:"custom-field-set"`
+}
+
+type CustomFieldSet struct {
+	Name            string             `xml:"name"`
+	Label           TranslatableString `xml:"label"`
+	Global          bool               `xml:"global,attr,omitempty"`
+	RelatedEntities struct {
+		Entities []xmlElementName `xml:",any"`
+	} `xml:"related-entities"`
+	Fields struct {
+		Fields []CustomField `xml:",any"`
+	} `xml:"fields"`
+}
+
+type xmlElementName struct {
+	XMLName xml.Name
+}
+
+// CustomField is a field of a custom field set. The element name is the type of
+// the field, like text, int or single-select.
+type CustomField struct {
+	XMLName  xml.Name
+	Name     string             `xml:"name,attr"`
+	Label    TranslatableString `xml:"label"`
+	Required bool               `xml:"required"`
+	Position int                `xml:"position"`
+}
+
+// Type returns the type of the custom field.
+func (f CustomField) Type() string {
+	return f.XMLName.Local
+}
+
+type Cookies struct {
+	Cookies []Cookie      `xml:"cookie"`
+	Groups  []CookieGroup `xml:"group"`
+}
+
+type Cookie struct {
+	Cookie             string `xml:"cookie"`
+	SnippetName        string `xml:"snippet-name"`
+	SnippetDescription string `xml:"snippet-description,omitempty"`
+	Value              string `xml:"value,omitempty"`
+	Expiration         int    `xml:"expiration,omitempty"`
+}
+
+type CookieGroup struct {
+	SnippetName        string `xml:"snippet-name"`
+	SnippetDescription string `xml:"snippet-description,omitempty"`
+	Entries            struct {
+		Cookies []Cookie `xml:"cookie"`
+	} `xml:"entries"`
+}
+
+type Payments struct {
+	PaymentMethods []PaymentMethod `xml:"payment-method"`
+}
+
+type PaymentMethod struct {
+	Identifier   string             `xml:"identifier"`
+	Name         TranslatableString `xml:"name"`
+	Description  TranslatableString `xml:"description,omitempty"`
+	PayUrl       string             `xml:"pay-url,omitempty"`
+	FinalizeUrl  string             `xml:"finalize-url,omitempty"`
+	ValidateUrl  string             `xml:"validate-url,omitempty"`
+	CaptureUrl   string             `xml:"capture-url,omitempty"`
+	RefundUrl    string             `xml:"refund-url,omitempty"`
+	RecurringUrl string             `xml:"recurring-url,omitempty"`
+	Icon         string             `xml:"icon,omitempty"`
+}
+
+type Tax struct {
+	Providers []TaxProvider `xml:"tax-provider"`
+}
+
+type TaxProvider struct {
+	Identifier string `xml:"identifier"`
+	Name       string `xml:"name"`
+	Priority   int    `xml:"priority"`
+	ProcessUrl string `xml:"process-url"`
+}
+
+type Gateways struct {
+	Checkout       string `xml:"checkout,omitempty"`
+	Context        string `xml:"context,omitempty"`
+	InAppPurchases string `xml:"inAppPurchases,omitempty"`
+}
+
 type TranslatableString []struct {
 	Value string `xml:",chardata"`
 	Lang  string `xml:"lang,attr,omitempty"`
@@ -0,0 +1,107 @@
+package extension
+
+// shopwareEntities are the entities of Shopware core which apps can request
+// permissions for and subscribe to with entity written and deleted webhooks.
+// Translation entities are derived by appending _translation.
+var shopwareEntities = map[string]bool{}
+
+func init() {
+	for _, entity := range []string{
+		"acl_role", "acl_user_role",
+		"app", "app_action_button", "app_administration_snippet", "app_cms_block", "app_flow_action", "app_flow_event",
+		"app_payment_method", "app_script_condition", "app_shipping_method", "app_template",
+		"category", "category_tag",
+		"cms_block", "cms_page", "cms_section", "cms_slot",
+		"country", "country_state",
+		"currency", "currency_country_rounding",
+		"custom_entity", "custom_field", "custom_field_set", "custom_field_set_relation",
+		"customer", "customer_address", "customer_group", "customer_group_registration_sales_channels",
+		"customer_recovery", "customer_tag", "customer_wishlist", "customer_wishlist_product",
+		"delivery_time",
+		"document", "document_base_config", "document_base_config_sales_channel", "document_type",
+		"flow", "flow_sequence", "flow_template",
+		"import_export_file", "import_export_log", "import_export_profile",
+		"integration", "integration_role",
+		"landing_page", "landing_page_sales_channel", "landing_page_tag",
+		"language", "locale", "log_entry",
+		"mail_header_footer", "mail_template", "mail_template_media", "mail_template_type",
+		"main_category",
+		"media", "media_default_folder", "media_folder", "media_folder_configuration",
+		"media_folder_configuration_media_thumbnail_size", "media_tag", "media_thumbnail", "media_thumbnail_size",
+		"newsletter_recipient", "newsletter_recipient_tag",
+		"notification",
+		"number_range", "number_range_sales_channel", "number_range_state", "number_range_type",
+		"order", "order_address", "order_customer", "order_delivery", "order_delivery_position",
+		"order_line_item", "order_line_item_download", "order_tag", "order_transaction",
+		"order_transaction_capture", "order_transaction_capture_refund", "order_transaction_capture_refund_position",
+		"payment_method", "plugin",
+		"product", "product_category", "product_category_tree", "product_configurator_setting",
+		"product_cross_selling", "product_cross_selling_assigned_products", "product_custom_field_set",
+		"product_download", "product_export", "product_feature_set", "product_keyword_dictionary",
+		"product_manufacturer", "product_media", "product_option", "product_price", "product_property",
+		"product_review", "product_search_config", "product_search_config_field", "product_search_keyword",
+		"product_sorting", "product_stream", "product_stream_filter", "product_stream_mapping", "product_tag",
+		"product_visibility",
+		"promotion", "promotion_cart_rule", "promotion_discount", "promotion_discount_prices",
+		"promotion_discount_rule", "promotion_individual_code", "promotion_orders_rule",
+		"promotion_persona_customer", "promotion_persona_rule", "promotion_sales_channel",
+		"promotion_setgroup", "promotion_setgroup_rule",
+		"property_group", "property_group_option",
+		"rule", "rule_condition", "rule_tag",
+		"sales_channel", "sales_channel_analytics", "sales_channel_country", "sales_channel_currency",
+		"sales_channel_domain", "sales_channel_language", "sales_channel_payment_method",
+		"sales_channel_shipping_method", "sales_channel_type",
+		"salutation", "scheduled_task", "script",
+		"seo_url", "seo_url_template",
+		"shipping_method", "shipping_method_price", "shipping_method_tag",
+		"snippet", "snippet_set",
+		"state_machine", "state_machine_history", "state_machine_state", "state_machine_transition",
+		"system_config",
+		"tag",
+		"tax", "tax_provider", "tax_rule", "tax_rule_type",
+		"theme", "theme_child", "theme_media", "theme_sales_channel",
+		"unit",
+		"user", "user_access_key", "user_config", "user_recovery",
+		"version", "version_commit", "version_commit_data",
+		"webhook", "webhook_event_log",
+	} {
+		shopwareEntities[entity] = true
+	}
+}
+
+// shopwareWebhookEvents are the events apps can subscribe to besides entity
+// written and deleted events and state machine transitions.
+var shopwareWebhookEvents = map[string]bool{
+	"app.activated":   true,
+	"app.deactivated": true,
+	"app.deleted":     true,
+	"app.installed":   true,
+	"app.updated":     true,
+
+	"shopware.updated": true,
+
+	"checkout.customer.before.login":               true,
+	"checkout.customer.changed-payment-method":     true,
+	"checkout.customer.deleted":                    true,
+	"checkout.customer.double_opt_in_guest_order":  true,
+	"checkout.customer.double_opt_in_registration": true,
+	"checkout.customer.guest_register":             true,
+	"checkout.customer.login":                      true,
+	"checkout.customer.logout":                     true,
+	"checkout.customer.register":                   true,
+	"checkout.order.payment_method.changed":        true,
+	"checkout.order.placed":                        true,
+	"contact_form.send":                            true,
+	"customer.group.registration.accepted":         true,
+	"customer.group.registration.declined":         true,
+	"customer.recovery.request":                    true,
+	"mail.after.create.message":                    true,
+	"mail.before.send":                             true,
+	"mail.sent":                                    true,
+	"newsletter.confirm":                           true,
+	"newsletter.register":                          true,
+	"newsletter.unsubscribe":                       true,
+	"product_export.log":                           true,
+	"review_form.send":                             true,
+	"user.recovery.request":                        true,
+}
@@ -0,0 +1,432 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const manifestSchemaURL = "https://raw.githubusercontent.com/shopware/shopware/%s/src/Core/Framework/App/Manifest/Schema/manifest-2.0.xsd"
+
+// ManifestSchemaSource returns the lowest Shopware version matching the
+// constraint and its manifest XSD.
+type ManifestSchemaSource func(ctx context.Context, constraint *version.Constraints) (string, []byte, error)
+
+// DownloadManifestSchema is the ManifestSchemaSource downloading the XSD from GitHub.
+var DownloadManifestSchema ManifestSchemaSource = loadManifestSchemaForConstraint
+
+type manifestSchemaSourceKey struct{}
+
+// WithManifestSchemaSource enables the validation of manifest.xml against the
+// XSD of the source, without it the schema validation is skipped.
+func WithManifestSchemaSource(ctx context.Context, source ManifestSchemaSource) context.Context {
+	return context.WithValue(ctx, manifestSchemaSourceKey{}, source)
+}
+
+func manifestSchemaSourceFromContext(ctx context.Context) ManifestSchemaSource {
+	source, _ := ctx.Value(manifestSchemaSourceKey{}).(ManifestSchemaSource)
+	return source
+}
+
+// xmlNode is a generic XML element with the line it starts at.
+type xmlNode struct {
+	Name     string
+	Attrs    map[string]string
+	Children []*xmlNode
+	Line     int
+}
+
+func (n *xmlNode) attr(name string) string {
+	return n.Attrs[name]
+}
+
+func parseXMLNodes(data []byte) (*xmlNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root *xmlNode
+	var stack []*xmlNode
+
+	for {
+		line, _ := decoder.InputPos()
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{Name: t.Name.Local, Attrs: map[string]string{}, Line: line}
+
+			for _, attr := range t.Attr {
+				// namespace declarations and xsi:* attributes are not part of the schema
+				if attr.Name.Space != "" || attr.Name.Local == "xmlns" {
+					continue
+				}
+
+				node.Attrs[attr.Name.Local] = attr.Value
+			}
+
+			if len(stack) == 0 {
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			}
+
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("document has no root element")
+	}
+
+	return root, nil
+}
+
+// xsdSchema supports the subset of XML Schema used by the Shopware manifest:
+// global and local elements, named and inline complex types, sequence, choice,
+// all, groups, attributes and simple or complex content extensions. Ordering,
+// occurrence limits and simple type facets are not checked.
+type xsdSchema struct {
+	elements        map[string]*xmlNode
+	complexTypes    map[string]*xmlNode
+	groups          map[string]*xmlNode
+	attributeGroups map[string]*xmlNode
+}
+
+type xsdTypeInfo struct {
+	children      map[string]*xmlNode
+	required      []string
+	attributes    map[string]bool
+	anyChildren   bool
+	anyAttributes bool
+	simple        bool
+}
+
+// schemaViolation is a difference between a document and the schema.
+type schemaViolation struct {
+	Line    int
+	Message string
+}
+
+func parseXSD(data []byte) (*xsdSchema, error) {
+	root, err := parseXMLNodes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if root.Name != "schema" {
+		return nil, fmt.Errorf("expected xs:schema root element, got %s", root.Name)
+	}
+
+	schema := &xsdSchema{
+		elements:        map[string]*xmlNode{},
+		complexTypes:    map[string]*xmlNode{},
+		groups:          map[string]*xmlNode{},
+		attributeGroups: map[string]*xmlNode{},
+	}
+
+	for _, child := range root.Children {
+		name := child.attr("name")
+
+		switch child.Name {
+		case "element":
+			schema.elements[name] = child
+		case "complexType":
+			schema.complexTypes[name] = child
+		case "group":
+			schema.groups[name] = child
+		case "attributeGroup":
+			schema.attributeGroups[name] = child
+		}
+	}
+
+	return schema, nil
+}
+
+func localName(qualified string) string {
+	if idx := strings.LastIndex(qualified, ":"); idx != -1 {
+		return qualified[idx+1:]
+	}
+
+	return qualified
+}
+
+// resolveElement follows element references to the declaration.
+func (s *xsdSchema) resolveElement(decl *xmlNode) *xmlNode {
+	if ref := decl.attr("ref"); ref != "" {
+		if global, ok := s.elements[localName(ref)]; ok {
+			return global
+		}
+	}
+
+	return decl
+}
+
+func (s *xsdSchema) typeInfo(decl *xmlNode) xsdTypeInfo {
+	info := xsdTypeInfo{children: map[string]*xmlNode{}, attributes: map[string]bool{}}
+
+	if typeName := decl.attr("type"); typeName != "" {
+		complexType, ok := s.complexTypes[localName(typeName)]
+		if !ok {
+			info.simple = true
+			return info
+		}
+
+		s.collectComplexType(complexType, &info, map[*xmlNode]bool{})
+
+		return info
+	}
+
+	for _, child := range decl.Children {
+		if child.Name == "complexType" {
+			s.collectComplexType(child, &info, map[*xmlNode]bool{})
+			return info
+		}
+	}
+
+	// elements without type are simple (or xs:anyType, which we treat as simple)
+	info.simple = true
+
+	return info
+}
+
+func (s *xsdSchema) collectComplexType(node *xmlNode, info *xsdTypeInfo, seen map[*xmlNode]bool) {
+	if seen[node] {
+		return
+	}
+
+	seen[node] = true
+
+	for _, child := range node.Children {
+		switch child.Name {
+		case "sequence", "all":
+			s.collectParticle(child, info, child.attr("minOccurs") != "0", seen)
+		case "choice":
+			s.collectParticle(child, info, false, seen)
+		case "group":
+			if group, ok := s.groups[localName(child.attr("ref"))]; ok {
+				s.collectComplexType(group, info, seen)
+			}
+		case "attribute":
+			s.collectAttribute(child, info)
+		case "attributeGroup":
+			if group, ok := s.attributeGroups[localName(child.attr("ref"))]; ok {
+				s.collectComplexType(group, info, seen)
+			}
+		case "anyAttribute":
+			info.anyAttributes = true
+		case "simpleContent", "complexContent":
+			for _, derivation := range child.Children {
+				if derivation.Name != "extension" && derivation.Name != "restriction" {
+					continue
+				}
+
+				if base, ok := s.complexTypes[localName(derivation.attr("base"))]; ok {
+					s.collectComplexType(base, info, seen)
+				}
+
+				s.collectComplexType(derivation, info, seen)
+			}
+		}
+	}
+}
+
+func (s *xsdSchema) collectParticle(node *xmlNode, info *xsdTypeInfo, required bool, seen map[*xmlNode]bool) {
+	for _, child := range node.Children {
+		switch child.Name {
+		case "element":
+			decl := s.resolveElement(child)
+			name := decl.attr("name")
+			info.children[name] = decl
+
+			if required && child.attr("minOccurs") != "0" && !slices.Contains(info.required, name) {
+				info.required = append(info.required, name)
+			}
+		case "sequence", "all":
+			s.collectParticle(child, info, required && child.attr("minOccurs") != "0", seen)
+		case "choice":
+			s.collectParticle(child, info, false, seen)
+		case "group":
+			if group, ok := s.groups[localName(child.attr("ref"))]; ok && !seen[group] {
+				seen[group] = true
+				s.collectParticle(group, info, required && child.attr("minOccurs") != "0", seen)
+			}
+		case "any":
+			info.anyChildren = true
+		}
+	}
+}
+
+func (s *xsdSchema) collectAttribute(node *xmlNode, info *xsdTypeInfo) {
+	name := node.attr("name")
+	if name == "" {
+		name = localName(node.attr("ref"))
+	}
+
+	info.attributes[name] = node.attr("use") == "required"
+}
+
+// Validate checks the element and attribute structure of the document.
+func (s *xsdSchema) Validate(document *xmlNode) []schemaViolation {
+	decl, ok := s.elements[document.Name]
+	if !ok {
+		return []schemaViolation{{Line: document.Line, Message: fmt.Sprintf("unknown root element <%s>", document.Name)}}
+	}
+
+	var violations []schemaViolation
+
+	s.validateNode(document, decl, &violations)
+
+	return violations
+}
+
+func (s *xsdSchema) validateNode(node, decl *xmlNode, violations *[]schemaViolation) {
+	info := s.typeInfo(decl)
+
+	if info.simple {
+		if len(node.Children) > 0 {
+			*violations = append(*violations, schemaViolation{Line: node.Line, Message: fmt.Sprintf("element <%s> must not contain child elements", node.Name)})
+		}
+
+		return
+	}
+
+	attrNames := make([]string, 0, len(node.Attrs))
+	for name := range node.Attrs {
+		attrNames = append(attrNames, name)
+	}
+
+	slices.Sort(attrNames)
+
+	for _, name := range attrNames {
+		if _, ok := info.attributes[name]; !ok && !info.anyAttributes {
+			*violations = append(*violations, schemaViolation{Line: node.Line, Message: fmt.Sprintf("attribute %q is not allowed on <%s>", name, node.Name)})
+		}
+	}
+
+	requiredAttrs := make([]string, 0)
+	for name, required := range info.attributes {
+		if _, ok := node.Attrs[name]; required && !ok {
+			requiredAttrs = append(requiredAttrs, name)
+		}
+	}
+
+	slices.Sort(requiredAttrs)
+
+	for _, name := range requiredAttrs {
+		*violations = append(*violations, schemaViolation{Line: node.Line, Message: fmt.Sprintf("attribute %q is required on <%s>", name, node.Name)})
+	}
+
+	present := map[string]bool{}
+
+	for _, child := range node.Children {
+		present[child.Name] = true
+
+		childDecl, ok := info.children[child.Name]
+		if !ok {
+			if !info.anyChildren {
+				*violations = append(*violations, schemaViolation{Line: child.Line, Message: fmt.Sprintf("element <%s> is not allowed in <%s>", child.Name, node.Name)})
+			}
+
+			continue
+		}
+
+		s.validateNode(child, childDecl, violations)
+	}
+
+	for _, name := range info.required {
+		if !present[name] {
+			*violations = append(*violations, schemaViolation{Line: node.Line, Message: fmt.Sprintf("element <%s> is missing required child <%s>", node.Name, name)})
+		}
+	}
+}
+
+func loadManifestSchemaForConstraint(ctx context.Context, constraint *version.Constraints) (string, []byte, error) {
+	shopwareVersion, err := lookupForMinMatchingVersion(ctx, constraint)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, err := loadManifestSchema(ctx, shopwareVersion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return shopwareVersion, data, nil
+}
+
+// loadManifestSchema downloads the manifest XSD of the Shopware version and
+// keeps it in the cache, as released schemas never change.
+func loadManifestSchema(ctx context.Context, shopwareVersion string) ([]byte, error) {
+	ref := "v" + shopwareVersion
+	if shopwareVersion == DevVersionNumber {
+		ref = "trunk"
+	}
+
+	cache := system.GetCacheWithPrefix("manifest-schema")
+	cacheKey := "manifest-2.0-" + ref
+
+	if reader, err := cache.Get(ctx, cacheKey); err == nil {
+		defer func() {
+			_ = reader.Close()
+		}()
+
+		return io.ReadAll(reader)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(manifestSchemaURL, ref), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download manifest schema for %s: unexpected status %d", ref, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// trunk moves, only released versions are cached
+	if ref != "trunk" {
+		if err := cache.Set(ctx, cacheKey, bytes.NewReader(data)); err != nil {
+			logging.FromContext(ctx).Debugf("Could not cache manifest schema: %v", err)
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,118 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testManifestSchema = `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" elementFormDefault="qualified">
+    <xs:element name="manifest">
+        <xs:complexType>
+            <xs:sequence>
+                <xs:element name="meta" type="meta"/>
+                <xs:element name="setup" type="setup" minOccurs="0"/>
+                <xs:element name="webhooks" minOccurs="0">
+                    <xs:complexType>
+                        <xs:choice maxOccurs="unbounded">
+                            <xs:element name="webhook" type="webhook"/>
+                        </xs:choice>
+                    </xs:complexType>
+                </xs:element>
+            </xs:sequence>
+        </xs:complexType>
+    </xs:element>
+    <xs:complexType name="meta">
+        <xs:sequence>
+            <xs:element name="name" type="xs:string"/>
+            <xs:element name="label" type="translatableString"/>
+            <xs:element name="version" type="xs:string"/>
+            <xs:element name="author" type="xs:string" minOccurs="0"/>
+        </xs:sequence>
+    </xs:complexType>
+    <xs:complexType name="translatableString">
+        <xs:simpleContent>
+            <xs:extension base="xs:string">
+                <xs:attribute name="lang" type="xs:string"/>
+            </xs:extension>
+        </xs:simpleContent>
+    </xs:complexType>
+    <xs:complexType name="setup">
+        <xs:sequence>
+            <xs:element name="registrationUrl" type="xs:string"/>
+            <xs:element name="secret" type="xs:string" minOccurs="0"/>
+        </xs:sequence>
+    </xs:complexType>
+    <xs:complexType name="webhook">
+        <xs:attribute name="name" type="xs:string" use="required"/>
+        <xs:attribute name="url" type="xs:string" use="required"/>
+        <xs:attribute name="event" type="xs:string" use="required"/>
+    </xs:complexType>
+</xs:schema>`
+
+func TestManifestSchemaValidDocument(t *testing.T) {
+	schema, err := parseXSD([]byte(testManifestSchema))
+	require.NoError(t, err)
+
+	document, err := parseXMLNodes([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<manifest xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+    <meta>
+        <name>MyApp</name>
+        <label>My App</label>
+        <label lang="de-DE">Meine App</label>
+        <version>1.0.0</version>
+    </meta>
+    <webhooks>
+        <webhook name="orderPlaced" url="https://example.com/order" event="checkout.order.placed"/>
+    </webhooks>
+</manifest>`))
+	require.NoError(t, err)
+
+	assert.Empty(t, schema.Validate(document))
+}
+
+func TestManifestSchemaViolations(t *testing.T) {
+	schema, err := parseXSD([]byte(testManifestSchema))
+	require.NoError(t, err)
+
+	document, err := parseXMLNodes([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<manifest>
+    <meta>
+        <name>MyApp</name>
+        <label unknown="1">My App</label>
+        <version>1.0.0</version>
+        <licence>MIT</licence>
+    </meta>
+    <setup>
+        <secret>foo</secret>
+    </setup>
+    <webhooks>
+        <webhook name="orderPlaced" url="https://example.com/order"/>
+    </webhooks>
+</manifest>`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []schemaViolation{
+		{Line: 5, Message: `attribute "unknown" is not allowed on <label>`},
+		{Line: 7, Message: "element <licence> is not allowed in <meta>"},
+		{Line: 9, Message: "element <setup> is missing required child <registrationUrl>"},
+		{Line: 13, Message: `attribute "event" is required on <webhook>`},
+	}, schema.Validate(document))
+}
+
+func TestManifestSchemaUnknownRoot(t *testing.T) {
+	schema, err := parseXSD([]byte(testManifestSchema))
+	require.NoError(t, err)
+
+	document, err := parseXMLNodes([]byte(`<plugin/>`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []schemaViolation{{Line: 1, Message: "unknown root element <plugin>"}}, schema.Validate(document))
+}
+
+func TestManifestSchemaRequiresSchemaRoot(t *testing.T) {
+	_, err := parseXSD([]byte(`<manifest/>`))
+	assert.Error(t, err)
+}
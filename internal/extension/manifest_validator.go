@@ -0,0 +1,303 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const manifestFile = "manifest.xml"
+
+// validateManifest checks the manifest.xml against the XSD of the lowest
+// Shopware version the app supports, when a schema source is set with
+// WithManifestSchemaSource, and runs semantic checks the schema can't express.
+func validateManifest(ctx context.Context, a App, check validation.Check) {
+	raw, err := os.ReadFile(filepath.Join(a.GetRootDir(), manifestFile))
+	if err != nil {
+		return
+	}
+
+	validateManifestSchema(ctx, a, raw, check)
+	validateManifestSemantics(a.manifest, raw, check)
+}
+
+func validateManifestSchema(ctx context.Context, a App, raw []byte, check validation.Check) {
+	manifestSchemaSource := manifestSchemaSourceFromContext(ctx)
+	if manifestSchemaSource == nil {
+		return
+	}
+
+	constraint, err := a.GetShopwareVersionConstraint()
+	if err != nil {
+		return
+	}
+
+	shopwareVersion, schemaData, err := manifestSchemaSource(ctx, constraint)
+	if err != nil {
+		logging.FromContext(ctx).Debugf("Skipping manifest schema validation: %v", err)
+		return
+	}
+
+	schema, err := parseXSD(schemaData)
+	if err != nil {
+		logging.FromContext(ctx).Debugf("Skipping manifest schema validation, cannot parse schema: %v", err)
+		return
+	}
+
+	document, err := parseXMLNodes(raw)
+	if err != nil {
+		check.AddResult(validation.CheckResult{
+			Path:       manifestFile,
+			Identifier: "manifest.schema",
+			Message:    fmt.Sprintf("Could not parse manifest.xml: %s", err),
+			Severity:   validation.SeverityError,
+		})
+
+		return
+	}
+
+	for _, violation := range schema.Validate(document) {
+		check.AddResult(validation.CheckResult{
+			Path:       manifestFile,
+			Line:       violation.Line,
+			Identifier: "manifest.schema",
+			Message:    fmt.Sprintf("%s (manifest schema of Shopware %s)", violation.Message, shopwareVersion),
+			Severity:   validation.SeverityError,
+		})
+	}
+}
+
+func validateManifestSemantics(manifest Manifest, raw []byte, check validation.Check) {
+	if manifest.Webhooks != nil {
+		for _, webhook := range manifest.Webhooks.Webhooks {
+			if !isKnownWebhookEvent(webhook.Event) {
+				check.AddResult(validation.CheckResult{
+					Path:       manifestFile,
+					Line:       lineOf(raw, `"`+webhook.Event+`"`),
+					Identifier: "manifest.webhook.event",
+					Message:    fmt.Sprintf("The webhook %s listens to the unknown event %s", webhook.Name, webhook.Event),
+					Severity:   validation.SeverityWarning,
+				})
+			}
+		}
+	}
+
+	if manifest.Permissions != nil {
+		reported := map[string]bool{}
+
+		for _, entity := range manifest.Permissions.Entities() {
+			if isKnownEntity(entity) || reported[entity] {
+				continue
+			}
+
+			reported[entity] = true
+
+			check.AddResult(validation.CheckResult{
+				Path:       manifestFile,
+				Line:       lineOf(raw, ">"+entity+"<"),
+				Identifier: "manifest.permission.entity",
+				Message:    fmt.Sprintf("The permission references the entity %s, which is not a Shopware entity", entity),
+				Severity:   validation.SeverityWarning,
+			})
+		}
+	}
+
+	if manifest.CustomFields != nil {
+		sets := map[string]bool{}
+		fields := map[string]string{}
+
+		for _, set := range manifest.CustomFields.Sets {
+			if sets[set.Name] {
+				check.AddResult(validation.CheckResult{
+					Path:       manifestFile,
+					Line:       lineOf(raw, ">"+set.Name+"<"),
+					Identifier: "manifest.custom_field.duplicate",
+					Message:    fmt.Sprintf("The custom field set %s is defined multiple times", set.Name),
+					Severity:   validation.SeverityError,
+				})
+			}
+
+			sets[set.Name] = true
+
+			for _, field := range set.Fields.Fields {
+				if otherSet, ok := fields[field.Name]; ok {
+					check.AddResult(validation.CheckResult{
+						Path:       manifestFile,
+						Line:       lineOfAfter(raw, ">"+set.Name+"<", `"`+field.Name+`"`),
+						Identifier: "manifest.custom_field.duplicate",
+						Message:    fmt.Sprintf("The custom field %s of set %s is already defined in set %s, custom field names must be unique", field.Name, set.Name, otherSet),
+						Severity:   validation.SeverityError,
+					})
+
+					continue
+				}
+
+				fields[field.Name] = set.Name
+			}
+		}
+	}
+
+	for _, u := range manifestURLs(manifest) {
+		validateManifestURL(u.element, u.value, raw, check)
+	}
+}
+
+type manifestURL struct {
+	element string
+	value   string
+}
+
+// manifestURLs returns all URLs Shopware calls or embeds.
+func manifestURLs(manifest Manifest) []manifestURL {
+	var urls []manifestURL
+
+	add := func(element, value string) {
+		if value != "" {
+			urls = append(urls, manifestURL{element: element, value: value})
+		}
+	}
+
+	if manifest.Setup != nil {
+		add("setup:registrationUrl", manifest.Setup.RegistrationUrl)
+	}
+
+	if manifest.Admin != nil {
+		add("admin:base-app-url", manifest.Admin.BaseAppUrl)
+
+		for _, button := range manifest.Admin.ActionButtons {
+			add("admin:action-button "+button.Action, button.Url)
+		}
+
+		for _, module := range manifest.Admin.Modules {
+			add("admin:module "+module.Name, module.Source)
+		}
+
+		if manifest.Admin.MainModule != nil {
+			add("admin:main-module", manifest.Admin.MainModule.Source)
+		}
+	}
+
+	if manifest.Webhooks != nil {
+		for _, webhook := range manifest.Webhooks.Webhooks {
+			add("webhook "+webhook.Name, webhook.Url)
+		}
+	}
+
+	if manifest.Payments != nil {
+		for _, method := range manifest.Payments.PaymentMethods {
+			prefix := "payment-method " + method.Identifier + " "
+			add(prefix+"pay-url", method.PayUrl)
+			add(prefix+"finalize-url", method.FinalizeUrl)
+			add(prefix+"validate-url", method.ValidateUrl)
+			add(prefix+"capture-url", method.CaptureUrl)
+			add(prefix+"refund-url", method.RefundUrl)
+			add(prefix+"recurring-url", method.RecurringUrl)
+		}
+	}
+
+	if manifest.Tax != nil {
+		for _, provider := range manifest.Tax.Providers {
+			add("tax-provider "+provider.Identifier+" process-url", provider.ProcessUrl)
+		}
+	}
+
+	if manifest.Gateways != nil {
+		add("gateways:checkout", manifest.Gateways.Checkout)
+		add("gateways:context", manifest.Gateways.Context)
+		add("gateways:inAppPurchases", manifest.Gateways.InAppPurchases)
+	}
+
+	return urls
+}
+
+// validateManifestURL requires HTTPS. Plain HTTP to the local machine is only a
+// warning, as it is common during development.
+func validateManifestURL(element, value string, raw []byte, check validation.Check) {
+	parsed, err := url.Parse(value)
+	if err == nil && parsed.Scheme == "https" && parsed.Host != "" {
+		return
+	}
+
+	severity := validation.SeverityError
+	if err == nil && parsed.Scheme == "http" {
+		switch parsed.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+			severity = validation.SeverityWarning
+		}
+	}
+
+	check.AddResult(validation.CheckResult{
+		Path:       manifestFile,
+		Line:       lineOf(raw, value),
+		Identifier: "manifest.url.https",
+		Message:    fmt.Sprintf("The URL %s of %s must be an absolute HTTPS URL", value, element),
+		Severity:   severity,
+	})
+}
+
+func isKnownEntity(entity string) bool {
+	if shopwareEntities[entity] {
+		return true
+	}
+
+	if base, ok := strings.CutSuffix(entity, "_translation"); ok && shopwareEntities[base] {
+		return true
+	}
+
+	// custom entities of apps
+	return strings.HasPrefix(entity, "custom_entity_") || strings.HasPrefix(entity, "ce_")
+}
+
+func isKnownWebhookEvent(event string) bool {
+	if shopwareWebhookEvents[event] {
+		return true
+	}
+
+	if strings.HasPrefix(event, "state_enter.") || strings.HasPrefix(event, "state_leave.") {
+		return true
+	}
+
+	idx := strings.LastIndex(event, ".")
+	if idx == -1 {
+		return false
+	}
+
+	switch event[idx+1:] {
+	case "written", "deleted":
+		return isKnownEntity(event[:idx])
+	}
+
+	return false
+}
+
+// lineOf returns the first line containing needle, or 0.
+func lineOf(raw []byte, needle string) int {
+	idx := bytes.Index(raw, []byte(needle))
+	if idx == -1 {
+		return 0
+	}
+
+	return bytes.Count(raw[:idx], []byte("\n")) + 1
+}
+
+// lineOfAfter returns the first line containing needle after anchor, or 0.
+func lineOfAfter(raw []byte, anchor, needle string) int {
+	start := bytes.Index(raw, []byte(anchor))
+	if start == -1 {
+		return lineOf(raw, needle)
+	}
+
+	idx := bytes.Index(raw[start:], []byte(needle))
+	if idx == -1 {
+		return 0
+	}
+
+	return bytes.Count(raw[:start+idx], []byte("\n")) + 1
+}
@@ -0,0 +1,175 @@
+package extension
+
+import (
+	"context"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+const testManifestSemantics = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest>
+    <meta>
+        <name>MyApp</name>
+        <label>My App</label>
+        <version>1.0.0</version>
+    </meta>
+    <setup>
+        <registrationUrl>http://example.com/register</registrationUrl>
+    </setup>
+    <admin>
+        <action-button action="test" entity="product" view="detail" url="http://localhost:8000/action">
+            <label>Test</label>
+        </action-button>
+    </admin>
+    <permissions>
+        <read>product</read>
+        <read>product_translation</read>
+        <read>custom_entity_blog</read>
+        <update>prodcut</update>
+        <delete>prodcut</delete>
+        <permission>system:cache:info</permission>
+    </permissions>
+    <webhooks>
+        <webhook name="orderPlaced" url="https://example.com/order" event="checkout.order.placed"/>
+        <webhook name="productWritten" url="https://example.com/product" event="product.written"/>
+        <webhook name="stateEnter" url="https://example.com/state" event="state_enter.order.state.completed"/>
+        <webhook name="typo" url="https://example.com/typo" event="checkout.order.plcaed"/>
+    </webhooks>
+    <custom-fields>
+        <custom-field-set>
+            <name>my_set</name>
+            <label>My set</label>
+            <related-entities>
+                <product/>
+            </related-entities>
+            <fields>
+                <text name="my_field">
+                    <label>My field</label>
+                </text>
+            </fields>
+        </custom-field-set>
+        <custom-field-set>
+            <name>my_other_set</name>
+            <label>My other set</label>
+            <fields>
+                <int name="my_field">
+                    <label>My field</label>
+                </int>
+            </fields>
+        </custom-field-set>
+    </custom-fields>
+</manifest>`
+
+func TestManifestSemantics(t *testing.T) {
+	var manifest Manifest
+	require.NoError(t, xml.Unmarshal([]byte(testManifestSemantics), &manifest))
+
+	check := &testCheck{}
+	validateManifestSemantics(manifest, []byte(testManifestSemantics), check)
+
+	results := map[string][]validation.CheckResult{}
+	for _, result := range check.Results {
+		results[result.Identifier] = append(results[result.Identifier], result)
+	}
+
+	assert.Len(t, check.Results, 5)
+
+	require.Len(t, results["manifest.webhook.event"], 1)
+	assert.Equal(t, 28, results["manifest.webhook.event"][0].Line)
+	assert.Contains(t, results["manifest.webhook.event"][0].Message, "checkout.order.plcaed")
+	assert.Equal(t, validation.SeverityWarning, results["manifest.webhook.event"][0].Severity)
+
+	require.Len(t, results["manifest.permission.entity"], 1)
+	assert.Equal(t, 20, results["manifest.permission.entity"][0].Line)
+	assert.Contains(t, results["manifest.permission.entity"][0].Message, "prodcut")
+
+	require.Len(t, results["manifest.custom_field.duplicate"], 1)
+	assert.Equal(t, 47, results["manifest.custom_field.duplicate"][0].Line)
+	assert.Contains(t, results["manifest.custom_field.duplicate"][0].Message, "already defined in set my_set")
+	assert.Equal(t, validation.SeverityError, results["manifest.custom_field.duplicate"][0].Severity)
+
+	require.Len(t, results["manifest.url.https"], 2)
+	assert.Equal(t, 9, results["manifest.url.https"][0].Line)
+	assert.Equal(t, validation.SeverityError, results["manifest.url.https"][0].Severity)
+	assert.Equal(t, validation.SeverityWarning, results["manifest.url.https"][1].Severity)
+}
+
+func TestManifestSemanticsDuplicateCustomFieldSet(t *testing.T) {
+	manifest := Manifest{CustomFields: &CustomFields{Sets: []CustomFieldSet{{Name: "my_set"}, {Name: "my_set"}}}}
+
+	check := &testCheck{}
+	validateManifestSemantics(manifest, nil, check)
+
+	require.Len(t, check.Results, 1)
+	assert.Equal(t, "The custom field set my_set is defined multiple times", check.Results[0].Message)
+}
+
+func TestManifestCustomFieldTypes(t *testing.T) {
+	var manifest Manifest
+	require.NoError(t, xml.Unmarshal([]byte(testManifestSemantics), &manifest))
+
+	require.Len(t, manifest.CustomFields.Sets, 2)
+	assert.Equal(t, "text", manifest.CustomFields.Sets[0].Fields.Fields[0].Type())
+	assert.Equal(t, "int", manifest.CustomFields.Sets[1].Fields.Fields[0].Type())
+	assert.Equal(t, "product", manifest.CustomFields.Sets[0].RelatedEntities.Entities[0].XMLName.Local)
+	assert.Equal(t, []string{"product", "product_translation", "custom_entity_blog", "prodcut", "prodcut"}, manifest.Permissions.Entities())
+	assert.Equal(t, []string{"system:cache:info"}, manifest.Permissions.Permission)
+}
+
+func TestAppValidateManifestSchema(t *testing.T) {
+	var requested string
+
+	ctx := WithManifestSchemaSource(getTestContext(), func(_ context.Context, constraint *version.Constraints) (string, []byte, error) {
+		requested = constraint.String()
+		return "6.5.0.0", []byte(testManifestSchema), nil
+	})
+
+	appPath := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "manifest.xml"), []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<manifest>
+    <meta>
+        <name>MyApp</name>
+        <label>My App</label>
+        <version>1.0.0</version>
+        <author>Shopware</author>
+        <copyright>Shopware</copyright>
+        <license>MIT</license>
+        <compatibility>~6.5.0</compatibility>
+    </meta>
+</manifest>`), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(appPath, "Resources/config"), 0o755))
+	require.NoError(t, createTestImage(filepath.Join(appPath, "Resources/config/plugin.png")))
+
+	app, err := newApp(t.Context(), appPath)
+	require.NoError(t, err)
+
+	check := &testCheck{}
+	app.Validate(getTestContext(), check)
+
+	assert.Empty(t, requested, "the schema is only validated with a source")
+	assert.Empty(t, check.Results)
+
+	app.Validate(ctx, check)
+
+	assert.Equal(t, "~6.5.0", requested)
+
+	var schemaResults []validation.CheckResult
+	for _, result := range check.Results {
+		if result.Identifier == "manifest.schema" {
+			schemaResults = append(schemaResults, result)
+		}
+	}
+
+	require.Len(t, schemaResults, 3)
+	assert.Equal(t, "element <copyright> is not allowed in <meta> (manifest schema of Shopware 6.5.0.0)", schemaResults[0].Message)
+	assert.Equal(t, 8, schemaResults[0].Line)
+}
@@ -6,6 +6,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sync"
 
 	"github.com/shopware/shopware-cli/internal/executor"
@@ -151,3 +152,28 @@ func deletePaths(ctx context.Context, nodeModulesPaths ...string) {
 		}
 	}
 }
+
+// FindPackageLockFilesOfProject returns the package-lock.json files of the
+// project and of the administration and storefront of its extensions.
+func FindPackageLockFilesOfProject(ctx context.Context, project string) []string {
+	var files []string
+
+	candidates := []string{filepath.Join(project, "package-lock.json")}
+
+	for _, ext := range FindExtensionsFromProject(logging.DisableLogger(ctx), project, false) {
+		for _, resourcesDir := range ext.GetResourcesDirs() {
+			candidates = append(candidates,
+				filepath.Join(resourcesDir, "app", "administration", "package-lock.json"),
+				filepath.Join(resourcesDir, "app", "storefront", "package-lock.json"),
+			)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil && !slices.Contains(files, candidate) {
+			files = append(files, candidate)
+		}
+	}
+
+	return files
+}
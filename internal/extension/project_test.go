@@ -242,3 +242,24 @@ func TestFindAssetSourcesOfProjectYAMLBundleDeduplication(t *testing.T) {
 
 	assert.Equal(t, 1, count, "bundle declared in both composer.json and YAML config should only appear once")
 }
+
+func TestFindPackageLockFilesOfProject(t *testing.T) {
+	project := t.TempDir()
+	pluginDir := filepath.Join(project, "custom", "plugins", "FroshTools")
+	storefrontLock := filepath.Join(pluginDir, "src", "Resources", "app", "storefront", "package-lock.json")
+
+	assert.NoError(t, os.WriteFile(filepath.Join(project, "composer.json"), []byte(`{"type": "project"}`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(project, "package-lock.json"), []byte(`{}`), 0o644))
+	assert.NoError(t, os.MkdirAll(filepath.Dir(storefrontLock), 0o755))
+	assert.NoError(t, os.WriteFile(storefrontLock, []byte(`{}`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(pluginDir, "composer.json"), []byte(`{
+		"name": "frosh/tools",
+		"type": "shopware-platform-plugin",
+		"extra": {"shopware-plugin-class": "Frosh\\Tools\\FroshTools"},
+		"autoload": {"psr-4": {"Frosh\\Tools\\": "src/"}}
+	}`), 0o644))
+
+	files := FindPackageLockFilesOfProject(t.Context(), project)
+
+	assert.Equal(t, []string{filepath.Join(project, "package-lock.json"), storefrontLock}, files)
+}
@@ -0,0 +1,216 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/changelog"
+)
+
+const (
+	ReleaseBumpMajor = "major"
+	ReleaseBumpMinor = "minor"
+	ReleaseBumpPatch = "patch"
+)
+
+// NextReleaseVersion resolves the version of the next release. bump is either one of
+// major, minor or patch, which increases the according segment of current, or an
+// explicit version which must be greater than current.
+func NextReleaseVersion(current *version.Version, bump string) (*version.Version, error) {
+	var next string
+
+	switch bump {
+	case ReleaseBumpMajor:
+		next = fmt.Sprintf("%d.0.0", current.Major()+1)
+	case ReleaseBumpMinor:
+		next = fmt.Sprintf("%d.%d.0", current.Major(), current.Minor()+1)
+	case ReleaseBumpPatch:
+		next = fmt.Sprintf("%d.%d.%d", current.Major(), current.Minor(), current.Patch()+1)
+	default:
+		next = strings.TrimPrefix(bump, "v")
+	}
+
+	nextVersion, err := version.NewVersion(next)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release version %q: %w", bump, err)
+	}
+
+	if !nextVersion.GreaterThan(current) {
+		return nil, fmt.Errorf("release version %s must be greater than the current version %s", nextVersion.String(), current.String())
+	}
+
+	return nextVersion, nil
+}
+
+// VersionFile returns the file holding the version of the extension, relative to its root.
+func VersionFile(ext Extension) string {
+	if ext.GetType() == TypePlatformApp {
+		return "manifest.xml"
+	}
+
+	return "composer.json"
+}
+
+// SetExtensionVersion writes the given version into the manifest.xml of apps or the
+// composer.json of plugins and bundles. Unlike BuildModifier it keeps the formatting and
+// key order of the source files, as the result is committed.
+func SetExtensionVersion(ext Extension, newVersion string) error {
+	filePath := filepath.Join(ext.GetPath(), VersionFile(ext))
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", VersionFile(ext), err)
+	}
+
+	if ext.GetType() == TypePlatformApp {
+		content, err = setManifestVersion(content, newVersion)
+		if err != nil {
+			return fmt.Errorf("could not update manifest.xml: %w", err)
+		}
+	} else {
+		content, err = setComposerJSONVersion(content, newVersion)
+		if err != nil {
+			return fmt.Errorf("could not update composer.json: %w", err)
+		}
+	}
+
+	return os.WriteFile(filePath, content, 0o644)
+}
+
+// setManifestVersion replaces the text of the meta/version element in place.
+func setManifestVersion(content []byte, newVersion string) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	var path []string
+	var valueStart int64
+
+	for {
+		tokenStart := decoder.InputOffset()
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no meta/version element found")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			path = append(path, element.Name.Local)
+			valueStart = decoder.InputOffset()
+		case xml.EndElement:
+			if strings.Join(path, "/") == "manifest/meta/version" {
+				if bytes.HasSuffix(content[:valueStart], []byte("/>")) {
+					return nil, fmt.Errorf("the version element is empty")
+				}
+
+				var escaped bytes.Buffer
+				if err := xml.EscapeText(&escaped, []byte(newVersion)); err != nil {
+					return nil, err
+				}
+
+				var result bytes.Buffer
+				result.Write(content[:valueStart])
+				result.Write(escaped.Bytes())
+				result.Write(content[tokenStart:])
+
+				return result.Bytes(), nil
+			}
+
+			path = path[:len(path)-1]
+		}
+	}
+}
+
+// setComposerJSONVersion replaces the value of the top-level version key in place.
+func setComposerJSONVersion(content []byte, newVersion string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("composer.json must contain an object")
+	}
+
+	for decoder.More() {
+		keyToken, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		keyEnd := decoder.InputOffset()
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if key, _ := keyToken.(string); key != "version" {
+			continue
+		}
+
+		valueEnd := decoder.InputOffset()
+		valueStart := keyEnd + int64(bytes.Index(content[keyEnd:valueEnd], value))
+
+		encoded, err := json.Marshal(newVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		var result bytes.Buffer
+		result.Write(content[:valueStart])
+		result.Write(encoded)
+		result.Write(content[valueStart+int64(len(value)):])
+
+		return result.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("no top-level version field found")
+}
+
+// PrependReleaseChangelog generates the changelog for the given version from the git history
+// and prepends it to the CHANGELOG_en-GB.md of the extension. It returns false when the
+// changelog already contains an entry for the version.
+func PrependReleaseChangelog(ctx context.Context, ext Extension, newVersion string) (bool, error) {
+	changelogPath := filepath.Join(ext.GetPath(), "CHANGELOG_en-GB.md")
+
+	existing, err := os.ReadFile(changelogPath)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	existingVersions, err := parseMarkdownChangelog(string(existing))
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := existingVersions[newVersion]; ok {
+		return false, nil
+	}
+
+	content, err := changelog.GenerateChangelog(ctx, newVersion, ext.GetPath(), ext.GetExtensionConfig().Changelog)
+	if err != nil {
+		return false, fmt.Errorf("generate changelog: %w", err)
+	}
+
+	entry := fmt.Sprintf("# %s\n%s\n", newVersion, content)
+	if len(existing) > 0 {
+		entry += "\n" + string(existing)
+	}
+
+	if err := os.WriteFile(changelogPath, []byte(entry), 0o644); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,103 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shyim/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextReleaseVersion(t *testing.T) {
+	current := version.Must(version.NewVersion("1.2.3"))
+
+	cases := map[string]string{
+		ReleaseBumpMajor: "2.0.0",
+		ReleaseBumpMinor: "1.3.0",
+		ReleaseBumpPatch: "1.2.4",
+		"1.5.0":          "1.5.0",
+		"v1.5.0":         "1.5.0",
+	}
+
+	for bump, expected := range cases {
+		next, err := NextReleaseVersion(current, bump)
+		require.NoError(t, err, bump)
+		assert.Equal(t, expected, next.String(), bump)
+	}
+}
+
+func TestNextReleaseVersionRejectsOlderVersions(t *testing.T) {
+	current := version.Must(version.NewVersion("1.2.3"))
+
+	_, err := NextReleaseVersion(current, "1.2.3")
+	assert.ErrorContains(t, err, "must be greater")
+
+	_, err = NextReleaseVersion(current, "foo")
+	assert.ErrorContains(t, err, "invalid release version")
+}
+
+func TestSetComposerJSONVersionKeepsFormatting(t *testing.T) {
+	content := []byte(`{
+    "name": "frosh/test",
+    "extra": {"version": "keep"},
+    "version" : "1.0.0",
+    "require": {}
+}
+`)
+
+	updated, err := setComposerJSONVersion(content, "1.1.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, `{
+    "name": "frosh/test",
+    "extra": {"version": "keep"},
+    "version" : "1.1.0",
+    "require": {}
+}
+`, string(updated))
+}
+
+func TestSetComposerJSONVersionMissing(t *testing.T) {
+	_, err := setComposerJSONVersion([]byte(`{"name": "frosh/test"}`), "1.1.0")
+	assert.ErrorContains(t, err, "no top-level version field")
+}
+
+func TestSetExtensionVersionApp(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "manifest.xml"), []byte(exampleManifest), 0o644))
+
+	app := &App{path: tmpDir}
+	require.NoError(t, SetExtensionVersion(app, "2.0.0"))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "manifest.xml"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(exampleManifest, "<version>1.0.0</version>", "<version>2.0.0</version>", 1), string(content))
+}
+
+func TestSetManifestVersionKeepsFormatting(t *testing.T) {
+	manifest := `<?xml version="1.0" encoding="UTF-8"?>
+<!-- my app -->
+<manifest xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+    <meta>
+        <name>MyApp</name>
+        <version>
+            1.0.0
+        </version>
+    </meta>
+    <setup><version>unrelated</version></setup>
+</manifest>
+`
+
+	updated, err := setManifestVersion([]byte(manifest), "1.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(manifest, "\n            1.0.0\n        ", "1.1.0", 1), string(updated))
+
+	_, err = setManifestVersion([]byte(`<manifest><meta><name>MyApp</name></meta></manifest>`), "1.1.0")
+	assert.ErrorContains(t, err, "no meta/version element found")
+
+	_, err = setManifestVersion([]byte(`<manifest><meta><version/></meta></manifest>`), "1.1.0")
+	assert.ErrorContains(t, err, "the version element is empty")
+}
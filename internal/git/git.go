@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -226,3 +227,104 @@ func Init(ctx context.Context, repo string) error {
 	_, err := runGit(ctx, repo, "init")
 	return err
 }
+
+// Commit stages the given files and records them in a new commit.
+func Commit(ctx context.Context, repo, message string, files ...string) error {
+	if _, err := runGit(ctx, repo, append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+
+	_, err := runGit(ctx, repo, "commit", "-m", message)
+
+	return err
+}
+
+// CreateTag creates an annotated tag pointing at HEAD.
+func CreateTag(ctx context.Context, repo, tag, message string) error {
+	_, err := runGit(ctx, repo, "tag", "-a", tag, "-m", message)
+
+	return err
+}
+
+// DeleteTag removes the tag from the repository.
+func DeleteTag(ctx context.Context, repo, tag string) error {
+	_, err := runGit(ctx, repo, "tag", "-d", tag)
+
+	return err
+}
+
+// ResetLastCommit removes the last commit and restores the files it changed,
+// uncommitted changes to other files are kept.
+func ResetLastCommit(ctx context.Context, repo string) error {
+	_, err := runGit(ctx, repo, "reset", "--keep", "HEAD~1")
+
+	return err
+}
+
+// CreateBranch creates the branch at HEAD and checks it out.
+func CreateBranch(ctx context.Context, repo, name string) error {
+	_, err := runGit(ctx, repo, "checkout", "-b", name)
+
+	return err
+}
+
+// TagExists reports whether the given tag exists in the repository.
+func TagExists(ctx context.Context, repo, tag string) (bool, error) {
+	output, err := runGit(ctx, repo, "tag", "--list", tag)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(output) != "", nil
+}
+
+// ChangedFiles returns the absolute paths of the files added, modified or
+// renamed since the merge base of ref and HEAD. Uncommitted and untracked
+// files are included, deleted files are not.
+func ChangedFiles(ctx context.Context, repo, ref string) ([]string, error) {
+	topLevel, err := runGit(ctx, repo, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	topLevel = strings.TrimSpace(topLevel)
+
+	base, err := runGit(ctx, repo, "merge-base", ref, "HEAD")
+	if err != nil {
+		// CI checkouts are often shallow and miss the merge base
+		if err := unshallowRepository(ctx, repo); err != nil {
+			return nil, err
+		}
+
+		if base, err = runGit(ctx, repo, "merge-base", ref, "HEAD"); err != nil {
+			return nil, fmt.Errorf("cannot find the merge base of %s: %w", ref, err)
+		}
+	}
+
+	diff, err := runGit(ctx, repo, "diff", "--name-only", "--diff-filter=ACMR", strings.TrimSpace(base))
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := runGit(ctx, repo, "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	files := []string{}
+
+	for _, name := range strings.Split(diff+"\n"+untracked, "\n") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		files = append(files, filepath.Join(topLevel, filepath.FromSlash(name)))
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
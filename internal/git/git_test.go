@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -127,6 +128,109 @@ func TestIsWorkingTreeDirty(t *testing.T) {
 	})
 }
 
+func TestCommitAndCreateTag(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	prepareRepository(t, tmpDir)
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte(""), 0o644)
+	runCommand(t, tmpDir, "add", "a")
+	runCommand(t, tmpDir, "commit", "-m", "initial commit", "--no-verify", "--no-gpg-sign")
+
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte("changed"), 0o644)
+	_ = os.WriteFile(filepath.Join(tmpDir, "b"), []byte("untouched"), 0o644)
+
+	assert.NoError(t, Commit(t.Context(), tmpDir, "Release 1.0.0", "a"))
+	assert.NoError(t, CreateTag(t.Context(), tmpDir, "1.0.0", "Release 1.0.0"))
+
+	exists, err := TagExists(t.Context(), tmpDir, "1.0.0")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = TagExists(t.Context(), tmpDir, "2.0.0")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	dirty, isRepo, err := IsWorkingTreeDirty(t.Context(), tmpDir)
+	assert.NoError(t, err)
+	assert.True(t, isRepo)
+	assert.True(t, dirty, "files not passed to Commit must stay uncommitted")
+}
+
+func TestDeleteTagAndResetLastCommit(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	prepareRepository(t, tmpDir)
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte("initial"), 0o644)
+	runCommand(t, tmpDir, "add", "a")
+	runCommand(t, tmpDir, "commit", "-m", "initial commit", "--no-verify", "--no-gpg-sign")
+
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte("released"), 0o644)
+	assert.NoError(t, Commit(t.Context(), tmpDir, "Release 1.0.0", "a"))
+	assert.NoError(t, CreateTag(t.Context(), tmpDir, "1.0.0", "Release 1.0.0"))
+
+	assert.NoError(t, DeleteTag(t.Context(), tmpDir, "1.0.0"))
+	assert.NoError(t, ResetLastCommit(t.Context(), tmpDir))
+
+	exists, err := TagExists(t.Context(), tmpDir, "1.0.0")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "a"))
+	assert.NoError(t, err)
+	assert.Equal(t, "initial", string(content))
+
+	dirty, _, err := IsWorkingTreeDirty(t.Context(), tmpDir)
+	assert.NoError(t, err)
+	assert.False(t, dirty)
+}
+
+func TestCreateBranch(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	prepareRepository(t, tmpDir)
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte(""), 0o644)
+	runCommand(t, tmpDir, "add", "a")
+	runCommand(t, tmpDir, "commit", "-m", "initial commit", "--no-verify", "--no-gpg-sign")
+
+	assert.NoError(t, CreateBranch(t.Context(), tmpDir, "upgrade"))
+
+	branch, err := runGit(t.Context(), tmpDir, "rev-parse", "--abbrev-ref", "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, "upgrade", strings.TrimSpace(branch))
+
+	assert.Error(t, CreateBranch(t.Context(), tmpDir, "upgrade"))
+}
+
+func TestChangedFiles(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	prepareRepository(t, tmpDir)
+	runCommand(t, tmpDir, "checkout", "-b", "main")
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte(""), 0o644)
+	_ = os.WriteFile(filepath.Join(tmpDir, "b"), []byte(""), 0o644)
+	_ = os.WriteFile(filepath.Join(tmpDir, "c"), []byte(""), 0o644)
+	runCommand(t, tmpDir, "add", ".")
+	runCommand(t, tmpDir, "commit", "-m", "initial commit", "--no-verify", "--no-gpg-sign")
+
+	runCommand(t, tmpDir, "checkout", "-b", "feature")
+	_ = os.WriteFile(filepath.Join(tmpDir, "a"), []byte("committed"), 0o644)
+	runCommand(t, tmpDir, "commit", "-am", "change a", "--no-verify", "--no-gpg-sign")
+	_ = os.WriteFile(filepath.Join(tmpDir, "b"), []byte("uncommitted"), 0o644)
+	_ = os.Remove(filepath.Join(tmpDir, "c"))
+	_ = os.MkdirAll(filepath.Join(tmpDir, "src"), 0o755)
+	_ = os.WriteFile(filepath.Join(tmpDir, "src", "d"), []byte("untracked"), 0o644)
+
+	files, err := ChangedFiles(t.Context(), filepath.Join(tmpDir, "src"), "main")
+	assert.NoError(t, err)
+
+	topLevel, err := filepath.EvalSymlinks(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(topLevel, "a"), filepath.Join(topLevel, "b"), filepath.Join(topLevel, "src", "d")}, files)
+
+	_, err = ChangedFiles(t.Context(), tmpDir, "unknown")
+	assert.Error(t, err)
+}
+
 func runCommand(t *testing.T, tmpDir string, args ...string) {
 	t.Helper()
 
@@ -0,0 +1,205 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expression is a parsed SPDX license expression.
+type Expression interface {
+	// Licenses returns the license identifiers of the expression.
+	Licenses() []string
+	// Satisfied reports whether the expression holds when only the licenses accepted by the function can be chosen.
+	Satisfied(accept func(license string) bool) bool
+	String() string
+}
+
+type licenseRef struct {
+	id        string
+	exception string
+}
+
+func (l licenseRef) Licenses() []string {
+	return []string{l.id}
+}
+
+func (l licenseRef) Satisfied(accept func(license string) bool) bool {
+	return accept(l.id)
+}
+
+func (l licenseRef) String() string {
+	if l.exception != "" {
+		return l.id + " WITH " + l.exception
+	}
+
+	return l.id
+}
+
+type compound struct {
+	operator string
+	left     Expression
+	right    Expression
+}
+
+func (c compound) Licenses() []string {
+	return append(c.left.Licenses(), c.right.Licenses()...)
+}
+
+func (c compound) Satisfied(accept func(license string) bool) bool {
+	if c.operator == "OR" {
+		return c.left.Satisfied(accept) || c.right.Satisfied(accept)
+	}
+
+	return c.left.Satisfied(accept) && c.right.Satisfied(accept)
+}
+
+func (c compound) String() string {
+	return fmt.Sprintf("(%s %s %s)", c.left, c.operator, c.right)
+}
+
+// ParseExpression parses an SPDX license expression like "MIT OR (Apache-2.0 AND BSD-3-Clause)".
+// AND binds stronger than OR, the exception of WITH is kept but not evaluated.
+func ParseExpression(expression string) (Expression, error) {
+	p := &parser{tokens: tokenize(expression)}
+
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+
+	parsed, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %w", expression, err)
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression %q: unexpected %q", expression, p.tokens[p.pos])
+	}
+
+	return parsed, nil
+}
+
+// ParseLicenses parses the license list of a composer package, its entries are alternatives.
+func ParseLicenses(licenses []string) (Expression, error) {
+	var combined Expression
+
+	for _, license := range licenses {
+		parsed, err := ParseExpression(license)
+		if err != nil {
+			return nil, err
+		}
+
+		if combined == nil {
+			combined = parsed
+		} else {
+			combined = compound{operator: "OR", left: combined, right: parsed}
+		}
+	}
+
+	if combined == nil {
+		return nil, fmt.Errorf("empty license expression")
+	}
+
+	return combined, nil
+}
+
+func tokenize(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+
+	return strings.Fields(expression)
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peekOperator(operator string) bool {
+	return p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], operator)
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekOperator("OR") {
+		p.pos++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = compound{operator: "OR", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peekOperator("AND") {
+		p.pos++
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		left = compound{operator: "AND", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Expression, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	if token == "(" {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+
+		p.pos++
+
+		return inner, nil
+	}
+
+	if token == ")" || isOperator(token) {
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+
+	ref := licenseRef{id: token}
+
+	if p.peekOperator("WITH") {
+		p.pos++
+
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] == "(" || p.tokens[p.pos] == ")" || isOperator(p.tokens[p.pos]) {
+			return nil, fmt.Errorf("missing exception after WITH")
+		}
+
+		ref.exception = p.tokens[p.pos]
+		p.pos++
+	}
+
+	return ref, nil
+}
+
+func isOperator(token string) bool {
+	return strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR") || strings.EqualFold(token, "WITH")
+}
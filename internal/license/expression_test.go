@@ -0,0 +1,47 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	cases := map[string]string{
+		"MIT":                                "MIT",
+		"MIT OR Apache-2.0":                  "(MIT OR Apache-2.0)",
+		"MIT or Apache-2.0 AND BSD-3-Clause": "(MIT OR (Apache-2.0 AND BSD-3-Clause))",
+		"(MIT OR Apache-2.0) AND ISC":        "((MIT OR Apache-2.0) AND ISC)",
+		"GPL-2.0-or-later WITH Classpath-exception-2.0": "GPL-2.0-or-later WITH Classpath-exception-2.0",
+	}
+
+	for input, expected := range cases {
+		expression, err := ParseExpression(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, expression.String(), input)
+	}
+
+	for _, invalid := range []string{"", "MIT OR", "(MIT", "MIT Apache-2.0", "AND MIT", "MIT WITH"} {
+		_, err := ParseExpression(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestExpressionSatisfied(t *testing.T) {
+	onlyMIT := func(license string) bool { return license == "MIT" }
+
+	expression, err := ParseExpression("MIT OR GPL-3.0-only")
+	require.NoError(t, err)
+	assert.True(t, expression.Satisfied(onlyMIT))
+	assert.Equal(t, []string{"MIT", "GPL-3.0-only"}, expression.Licenses())
+
+	expression, err = ParseExpression("MIT AND GPL-3.0-only")
+	require.NoError(t, err)
+	assert.False(t, expression.Satisfied(onlyMIT))
+
+	// composer license lists are alternatives
+	expression, err = ParseLicenses([]string{"LGPL-2.1-only", "MIT"})
+	require.NoError(t, err)
+	assert.True(t, expression.Satisfied(onlyMIT))
+}
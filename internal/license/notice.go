@@ -0,0 +1,91 @@
+package license
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// licenseFileNames are the files checked in order for the license text of a package.
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "LICENCE.md", "LICENSE-MIT", "COPYING", "COPYING.md"}
+
+const noticeSeparator = "================================================================================"
+
+// WriteNotice writes an attribution file of the packages grouped by license,
+// with the license text of each package when it is installed.
+func WriteNotice(w io.Writer, packages []Package) error {
+	groups := map[string][]Package{}
+	seen := map[string]bool{}
+
+	for _, pkg := range packages {
+		key := pkg.Ecosystem + "/" + pkg.Name + "@" + pkg.Version
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		license := strings.Join(pkg.Licenses, " OR ")
+		if license == "" {
+			license = "Unknown"
+		}
+
+		groups[license] = append(groups[license], pkg)
+	}
+
+	licenses := make([]string, 0, len(groups))
+	for license := range groups {
+		licenses = append(licenses, license)
+	}
+
+	slices.Sort(licenses)
+
+	var sb strings.Builder
+
+	sb.WriteString("THIRD-PARTY SOFTWARE NOTICES\n\n")
+	sb.WriteString("This software includes the following third-party packages.\n")
+
+	for _, license := range licenses {
+		group := groups[license]
+
+		slices.SortFunc(group, func(a, b Package) int {
+			if a.Name != b.Name {
+				return strings.Compare(a.Name, b.Name)
+			}
+
+			return strings.Compare(a.Version, b.Version)
+		})
+
+		fmt.Fprintf(&sb, "\n%s\n%s\n%s\n", noticeSeparator, license, noticeSeparator)
+
+		for _, pkg := range group {
+			fmt.Fprintf(&sb, "\n%s %s (%s)\n", pkg.Name, pkg.Version, pkg.Ecosystem)
+
+			if text := readLicenseText(pkg.Dir); text != "" {
+				fmt.Fprintf(&sb, "\n%s\n", text)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+func readLicenseText(dir string) string {
+	if dir == "" {
+		return ""
+	}
+
+	for _, name := range licenseFileNames {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return strings.TrimSpace(string(content))
+		}
+	}
+
+	return ""
+}
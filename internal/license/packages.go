@@ -0,0 +1,172 @@
+package license
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/shyim/go-composer"
+
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/npm"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const (
+	EcosystemComposer = "composer"
+	EcosystemNpm      = "npm"
+)
+
+// Package is a shipped package with its declared licenses.
+type Package struct {
+	Ecosystem string   `json:"ecosystem"`
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Licenses  []string `json:"licenses"`
+	// Source is the lock file relative to the project root
+	Source string `json:"source"`
+	// Dir is the installation directory, used to find the license text
+	Dir string `json:"-"`
+}
+
+// CollectPackages reads the composer.lock of the project, the vendor
+// directories bundled by local extensions and the package-lock.json files of
+// the project and of the administration and storefront of its extensions.
+func CollectPackages(ctx context.Context, root string, includeDev bool) ([]Package, error) {
+	var packages []Package
+
+	lockPath := filepath.Join(root, "composer.lock")
+
+	if _, err := os.Stat(lockPath); err == nil {
+		lock, err := composer.ReadLock(lockPath)
+		if err != nil {
+			return nil, fmt.Errorf("read composer.lock: %w", err)
+		}
+
+		packages = append(packages, composerLockPackages(root, lock, includeDev)...)
+	}
+
+	for _, ext := range extension.FindExtensionsFromProject(logging.DisableLogger(ctx), root, true) {
+		installedPath := filepath.Join(ext.GetPath(), "vendor", "composer", "installed.json")
+
+		if _, err := os.Stat(installedPath); err != nil {
+			continue
+		}
+
+		bundled, err := readInstalledJSON(installedPath, includeDev)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", installedPath, err)
+		}
+
+		source, _ := filepath.Rel(root, installedPath)
+
+		for _, pkg := range bundled {
+			pkg.Source = filepath.ToSlash(source)
+			pkg.Dir = filepath.Join(ext.GetPath(), "vendor", filepath.FromSlash(pkg.Name))
+			packages = append(packages, pkg)
+		}
+	}
+
+	for _, packageLock := range extension.FindPackageLockFilesOfProject(ctx, root) {
+		lockPackages, err := npm.ReadPackageLock(packageLock)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", packageLock, err)
+		}
+
+		source, _ := filepath.Rel(root, packageLock)
+
+		for _, pkg := range lockPackages {
+			if pkg.Dev && !includeDev {
+				continue
+			}
+
+			var licenses []string
+			if pkg.License != "" {
+				licenses = []string{pkg.License}
+			}
+
+			packages = append(packages, Package{
+				Ecosystem: EcosystemNpm,
+				Name:      pkg.Name,
+				Version:   pkg.Version,
+				Licenses:  licenses,
+				Source:    filepath.ToSlash(source),
+				Dir:       filepath.Join(filepath.Dir(packageLock), "node_modules", filepath.FromSlash(pkg.Name)),
+			})
+		}
+	}
+
+	return packages, nil
+}
+
+func composerLockPackages(root string, lock *composer.Lock, includeDev bool) []Package {
+	if lock == nil {
+		return nil
+	}
+
+	lockPackages := lock.Packages
+	if includeDev {
+		lockPackages = append(lockPackages, lock.PackagesDev...)
+	}
+
+	packages := make([]Package, 0, len(lockPackages))
+
+	for _, pkg := range lockPackages {
+		packages = append(packages, Package{
+			Ecosystem: EcosystemComposer,
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Licenses:  pkg.License,
+			Source:    "composer.lock",
+			Dir:       filepath.Join(root, "vendor", filepath.FromSlash(pkg.Name)),
+		})
+	}
+
+	return packages
+}
+
+// readInstalledJSON reads the packages of a vendor/composer/installed.json.
+func readInstalledJSON(path string, includeDev bool) ([]Package, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	type installedPackage struct {
+		Name    string   `json:"name"`
+		Version string   `json:"version"`
+		License []string `json:"license"`
+	}
+
+	var installed struct {
+		Packages        []installedPackage `json:"packages"`
+		DevPackageNames []string           `json:"dev-package-names"`
+	}
+
+	// Composer 1 writes a plain list of packages
+	if err := json.Unmarshal(data, &installed); err != nil {
+		if err := json.Unmarshal(data, &installed.Packages); err != nil {
+			return nil, err
+		}
+	}
+
+	packages := make([]Package, 0, len(installed.Packages))
+
+	for _, pkg := range installed.Packages {
+		if !includeDev && slices.Contains(installed.DevPackageNames, pkg.Name) {
+			continue
+		}
+
+		packages = append(packages, Package{
+			Ecosystem: EcosystemComposer,
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Licenses:  pkg.License,
+		})
+	}
+
+	return packages, nil
+}
@@ -0,0 +1,106 @@
+package license
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shyim/go-composer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadInstalledJSON(t *testing.T) {
+	dir := t.TempDir()
+	composer2 := filepath.Join(dir, "installed.json")
+	composer1 := filepath.Join(dir, "installed-v1.json")
+
+	require.NoError(t, os.WriteFile(composer2, []byte(`{
+		"packages": [
+			{"name": "league/csv", "version": "9.16.0", "license": ["MIT"]},
+			{"name": "phpunit/phpunit", "version": "10.5.0", "license": ["BSD-3-Clause"]}
+		],
+		"dev-package-names": ["phpunit/phpunit"]
+	}`), 0o644))
+	require.NoError(t, os.WriteFile(composer1, []byte(`[{"name": "league/csv", "version": "9.0.0", "license": ["MIT"]}]`), 0o644))
+
+	packages, err := readInstalledJSON(composer2, false)
+	require.NoError(t, err)
+	assert.Equal(t, []Package{{Ecosystem: EcosystemComposer, Name: "league/csv", Version: "9.16.0", Licenses: []string{"MIT"}}}, packages)
+
+	packages, err = readInstalledJSON(composer2, true)
+	require.NoError(t, err)
+	assert.Len(t, packages, 2)
+
+	packages, err = readInstalledJSON(composer1, false)
+	require.NoError(t, err)
+	assert.Equal(t, "9.0.0", packages[0].Version)
+}
+
+func TestCollectPackages(t *testing.T) {
+	root := t.TempDir()
+	pluginDir := filepath.Join(root, "custom", "plugins", "FroshTools")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "composer.json"), []byte(`{"type": "project"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "package-lock.json"), []byte(`{
+		"lockfileVersion": 3,
+		"packages": {
+			"node_modules/lodash": {"version": "4.17.21", "license": "MIT"},
+			"node_modules/jest": {"version": "29.0.0", "license": "MIT", "dev": true}
+		}
+	}`), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "vendor", "composer"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "composer.json"), []byte(`{
+		"name": "frosh/tools",
+		"type": "shopware-platform-plugin",
+		"extra": {"shopware-plugin-class": "Frosh\\Tools\\FroshTools"},
+		"autoload": {"psr-4": {"Frosh\\Tools\\": "src/"}}
+	}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "vendor", "composer", "installed.json"), []byte(`{"packages": [{"name": "league/csv", "version": "9.16.0", "license": ["MIT"]}]}`), 0o644))
+
+	packages, err := CollectPackages(t.Context(), root, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Package{
+		{Ecosystem: EcosystemComposer, Name: "league/csv", Version: "9.16.0", Licenses: []string{"MIT"}, Source: "custom/plugins/FroshTools/vendor/composer/installed.json", Dir: filepath.Join(pluginDir, "vendor", "league", "csv")},
+		{Ecosystem: EcosystemNpm, Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}, Source: "package-lock.json", Dir: filepath.Join(root, "node_modules", "lodash")},
+	}, packages)
+}
+
+func TestComposerLockPackages(t *testing.T) {
+	lock := &composer.Lock{
+		Packages:    []composer.LockPackage{{Name: "symfony/console", Version: "v7.1.0", License: []string{"MIT"}}},
+		PackagesDev: []composer.LockPackage{{Name: "phpunit/phpunit", Version: "10.5.0", License: []string{"BSD-3-Clause"}}},
+	}
+
+	packages := composerLockPackages("/shop", lock, false)
+	require.Len(t, packages, 1)
+	assert.Equal(t, filepath.Join("/shop", "vendor", "symfony", "console"), packages[0].Dir)
+
+	assert.Len(t, composerLockPackages("/shop", lock, true), 2)
+	assert.Empty(t, composerLockPackages("/shop", nil, true))
+}
+
+func TestWriteNotice(t *testing.T) {
+	vendorDir := filepath.Join(t.TempDir(), "symfony", "console")
+	require.NoError(t, os.MkdirAll(vendorDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(vendorDir, "LICENSE"), []byte("Copyright (c) Fabien Potencier\n"), 0o644))
+
+	var buf bytes.Buffer
+
+	require.NoError(t, WriteNotice(&buf, []Package{
+		{Ecosystem: EcosystemNpm, Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}},
+		{Ecosystem: EcosystemComposer, Name: "symfony/console", Version: "v7.1.0", Licenses: []string{"MIT"}, Dir: vendorDir},
+		{Ecosystem: EcosystemNpm, Name: "lodash", Version: "4.17.21", Licenses: []string{"MIT"}},
+		{Ecosystem: EcosystemComposer, Name: "league/csv", Version: "9.16.0", Licenses: []string{"Apache-2.0", "MIT"}},
+	}))
+
+	notice := buf.String()
+
+	assert.Contains(t, notice, "THIRD-PARTY SOFTWARE NOTICES")
+	assert.Contains(t, notice, "symfony/console v7.1.0 (composer)\n\nCopyright (c) Fabien Potencier\n")
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("lodash 4.17.21 (npm)")))
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("Apache-2.0 OR MIT")), bytes.Index(buf.Bytes(), []byte("\nMIT\n")))
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("lodash")), bytes.Index(buf.Bytes(), []byte("symfony/console")))
+}
@@ -0,0 +1,152 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+const (
+	// ViolationDenied is a package which can only be used under a denied license
+	ViolationDenied = "license-policy/denied"
+	// ViolationNotAllowed is a package whose licenses are all missing in the allow list
+	ViolationNotAllowed = "license-policy/not-allowed"
+	// ViolationUnknown is a package without license or with an unparsable license expression
+	ViolationUnknown = "license-policy/unknown"
+)
+
+// Exception excepts a package from the policy.
+type Exception struct {
+	// Package is the package name, a trailing * matches a prefix
+	Package string
+	// License limits the exception to a license, empty excepts every license of the package
+	License string
+}
+
+// Policy decides which licenses may be shipped.
+type Policy struct {
+	// Allow lists the allowed licenses, empty allows every license not denied
+	Allow []string
+	// Deny lists the denied licenses
+	Deny       []string
+	Exceptions []Exception
+}
+
+// NewPolicy creates the policy of the project config, a missing section allows everything.
+func NewPolicy(cfg *shop.ConfigLicensePolicy) Policy {
+	if cfg == nil {
+		return Policy{}
+	}
+
+	policy := Policy{Allow: cfg.Allow, Deny: cfg.Deny}
+
+	for _, exception := range cfg.Exceptions {
+		policy.Exceptions = append(policy.Exceptions, Exception{Package: exception.Package, License: exception.License})
+	}
+
+	return policy
+}
+
+// Violation is a package which breaks the policy.
+type Violation struct {
+	Package    Package `json:"package"`
+	Identifier string  `json:"identifier"`
+	Message    string  `json:"message"`
+}
+
+// CheckResult converts the violation for the validation reporters. Unknown
+// licenses are warnings, everything else is an error.
+func (v Violation) CheckResult() validation.CheckResult {
+	severity := validation.SeverityError
+	if v.Identifier == ViolationUnknown {
+		severity = validation.SeverityWarning
+	}
+
+	return validation.CheckResult{
+		Path:       v.Package.Source,
+		Identifier: v.Identifier,
+		Message:    fmt.Sprintf("%s %s: %s", v.Package.Name, v.Package.Version, v.Message),
+		Severity:   severity,
+		Tip:        "Replace the package or add an exception to license_policy.exceptions in the project config",
+	}
+}
+
+// Evaluate returns the packages breaking the policy.
+func (p Policy) Evaluate(packages []Package) []Violation {
+	var violations []Violation
+
+	for _, pkg := range packages {
+		if violation, ok := p.evaluatePackage(pkg); ok {
+			violations = append(violations, violation)
+		}
+	}
+
+	return violations
+}
+
+func (p Policy) evaluatePackage(pkg Package) (Violation, bool) {
+	excepted := func(license string) bool {
+		for _, exception := range p.Exceptions {
+			if matchPattern(exception.Package, pkg.Name) && (exception.License == "" || strings.EqualFold(exception.License, license)) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if excepted("") {
+		return Violation{}, false
+	}
+
+	if len(pkg.Licenses) == 0 {
+		return Violation{Package: pkg, Identifier: ViolationUnknown, Message: "the package declares no license"}, true
+	}
+
+	expression, err := ParseLicenses(pkg.Licenses)
+	if err != nil {
+		return Violation{Package: pkg, Identifier: ViolationUnknown, Message: err.Error()}, true
+	}
+
+	notDenied := func(license string) bool {
+		return excepted(license) || !matchesAny(p.Deny, license)
+	}
+
+	allowed := func(license string) bool {
+		return notDenied(license) && (excepted(license) || len(p.Allow) == 0 || matchesAny(p.Allow, license))
+	}
+
+	if expression.Satisfied(allowed) {
+		return Violation{}, false
+	}
+
+	if !expression.Satisfied(notDenied) {
+		return Violation{Package: pkg, Identifier: ViolationDenied, Message: fmt.Sprintf("the license %s is denied", expression)}, true
+	}
+
+	return Violation{Package: pkg, Identifier: ViolationNotAllowed, Message: fmt.Sprintf("the license %s is not allowed", expression)}, true
+}
+
+func matchesAny(patterns []string, license string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, license) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchPattern compares case-insensitive, a trailing * matches a prefix.
+func matchPattern(pattern, value string) bool {
+	pattern = strings.ToLower(pattern)
+	value = strings.ToLower(value)
+
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(value, prefix)
+	}
+
+	return pattern == value
+}
@@ -0,0 +1,75 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy := NewPolicy(&shop.ConfigLicensePolicy{
+		Allow: []string{"MIT", "BSD-*", "Apache-2.0", "LGPL-2.1-only"},
+		Deny:  []string{"AGPL-*", "GPL-*"},
+		Exceptions: []shop.ConfigLicensePolicyException{
+			{Package: "shopware/*", License: "MIT"},
+			{Package: "acme/internal"},
+			{Package: "acme/mixed", License: "gpl-3.0-only"},
+		},
+	})
+
+	packages := []Package{
+		{Name: "symfony/console", Version: "v7.1.0", Licenses: []string{"MIT"}, Source: "composer.lock"},
+		{Name: "dual/licensed", Version: "1.0.0", Licenses: []string{"GPL-3.0-only", "mit"}, Source: "composer.lock"},
+		{Name: "copyleft/only", Version: "1.0.0", Licenses: []string{"AGPL-3.0-or-later"}, Source: "composer.lock"},
+		{Name: "mixed/and", Version: "1.0.0", Licenses: []string{"MIT AND GPL-2.0-only"}, Source: "composer.lock"},
+		{Name: "isc/package", Version: "2.0.0", Licenses: []string{"ISC"}, Source: "package-lock.json"},
+		{Name: "no/license", Version: "1.0.0", Source: "package-lock.json"},
+		{Name: "broken/license", Version: "1.0.0", Licenses: []string{"(MIT"}, Source: "package-lock.json"},
+		{Name: "acme/internal", Version: "1.0.0", Licenses: []string{"proprietary"}, Source: "composer.lock"},
+		{Name: "acme/mixed", Version: "1.0.0", Licenses: []string{"MIT AND GPL-3.0-only"}, Source: "composer.lock"},
+		{Name: "shopware/core", Version: "6.6.0.0", Licenses: []string{"MIT"}, Source: "composer.lock"},
+	}
+
+	violations := policy.Evaluate(packages)
+
+	identifiers := map[string]string{}
+	for _, violation := range violations {
+		identifiers[violation.Package.Name] = violation.Identifier
+	}
+
+	assert.Equal(t, map[string]string{
+		"copyleft/only":  ViolationDenied,
+		"mixed/and":      ViolationDenied,
+		"isc/package":    ViolationNotAllowed,
+		"no/license":     ViolationUnknown,
+		"broken/license": ViolationUnknown,
+	}, identifiers)
+
+	require.Len(t, violations, 5)
+
+	result := violations[0].CheckResult()
+	assert.Equal(t, "composer.lock", result.Path)
+	assert.Equal(t, ViolationDenied, result.Identifier)
+	assert.Equal(t, "copyleft/only 1.0.0: the license AGPL-3.0-or-later is denied", result.Message)
+	assert.Equal(t, validation.SeverityError, result.Severity)
+
+	assert.Equal(t, validation.SeverityWarning, violations[3].CheckResult().Severity)
+}
+
+func TestPolicyWithoutAllowList(t *testing.T) {
+	policy := NewPolicy(&shop.ConfigLicensePolicy{Deny: []string{"AGPL-3.0-only"}})
+
+	violations := policy.Evaluate([]Package{
+		{Name: "a", Licenses: []string{"ISC"}},
+		{Name: "b", Licenses: []string{"AGPL-3.0-only"}},
+	})
+
+	require.Len(t, violations, 1)
+	assert.Equal(t, "b", violations[0].Package.Name)
+
+	assert.Empty(t, NewPolicy(nil).Evaluate([]Package{{Name: "c", Licenses: []string{"AGPL-3.0-only"}}}))
+}
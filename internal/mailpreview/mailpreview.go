@@ -0,0 +1,364 @@
+// Package mailpreview renders the mail templates of extensions and projects
+// with sample data, so they can be designed without sending mails.
+package mailpreview
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/mjml"
+)
+
+//go:embed fixtures/*.json
+var embeddedFixtures embed.FS
+
+const (
+	htmlMJML    = "html.mjml"
+	htmlTwig    = "html.twig"
+	plainTwig   = "plain.twig"
+	subjectTwig = "subject.twig"
+	fixtureJSON = "fixture.json"
+)
+
+var localeRegExp = regexp.MustCompile(`^[a-z]{2,3}-[A-Z]{2}$`)
+
+// Template is a directory with the parts of a mail template. The directory is
+// named after the technical name of the mail template type, optionally with a
+// locale directory below, like email/order_confirmation_mail/de-DE/html.mjml.
+type Template struct {
+	Type    string
+	Locale  string
+	Dir     string
+	Root    string
+	HTML    string
+	Plain   string
+	Subject string
+}
+
+// ID identifies the template in URLs.
+func (t Template) ID() string {
+	if t.Locale != "" {
+		return t.Type + "/" + t.Locale
+	}
+
+	return t.Type
+}
+
+// Files returns the files the preview depends on.
+func (t Template) Files() []string {
+	files := []string{filepath.Join(t.Dir, fixtureJSON)}
+
+	for _, file := range []string{t.HTML, t.Plain, t.Subject} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// Discover finds all mail templates below the search paths. Missing search
+// paths are skipped.
+func Discover(searchPaths []string) ([]Template, error) {
+	var templates []Template
+
+	for _, root := range searchPaths {
+		if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() {
+				return nil
+			}
+
+			switch d.Name() {
+			case "node_modules", "vendor", ".git":
+				return filepath.SkipDir
+			}
+
+			if template, ok := templateInDir(root, path); ok {
+				templates = append(templates, template)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(templates, func(a, b Template) int {
+		return strings.Compare(a.ID()+a.Dir, b.ID()+b.Dir)
+	})
+
+	return templates, nil
+}
+
+func templateInDir(root, dir string) (Template, bool) {
+	exists := func(name string) string {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+
+		return ""
+	}
+
+	template := Template{Dir: dir, Root: root, Plain: exists(plainTwig), Subject: exists(subjectTwig)}
+
+	// html.twig next to html.mjml is the compiled output
+	template.HTML = exists(htmlMJML)
+	if template.HTML == "" {
+		template.HTML = exists(htmlTwig)
+	}
+
+	if template.HTML == "" && template.Plain == "" {
+		return Template{}, false
+	}
+
+	template.Type = filepath.Base(dir)
+
+	if localeRegExp.MatchString(template.Type) {
+		template.Locale = template.Type
+		template.Type = filepath.Base(filepath.Dir(dir))
+	}
+
+	return template, true
+}
+
+// Rendered is a template rendered with fixture data. The Source fields hold
+// the templates as the shop stores them, with MJML compiled.
+type Rendered struct {
+	Template      Template
+	Subject       string
+	HTML          string
+	Plain         string
+	SourceSubject string
+	SourceHTML    string
+	SourcePlain   string
+	Warnings      []string
+	Err           error
+}
+
+// Renderer compiles and renders templates.
+type Renderer struct {
+	// FixtureDirs contain <type>.json files which are merged over the
+	// built-in sample data
+	FixtureDirs []string
+	// MJMLOptions returns the compile options for a file of the search path
+	MJMLOptions func(searchPath string) mjml.CompileOptions
+}
+
+// compileMJML is a variable so tests do not need node.
+var compileMJML = mjml.Compile
+
+// Compile returns the sources of the template with MJML compiled to HTML.
+func (r Renderer) Compile(ctx context.Context, t Template) (Rendered, error) {
+	rendered := Rendered{Template: t}
+
+	if t.HTML != "" {
+		if strings.HasSuffix(t.HTML, ".mjml") {
+			opts := mjml.CompileOptions{}
+			if r.MJMLOptions != nil {
+				opts = r.MJMLOptions(t.Root)
+			}
+
+			compiled, err := compileMJML(ctx, t.HTML, opts)
+			if err != nil {
+				return rendered, err
+			}
+
+			rendered.SourceHTML = compiled
+		} else {
+			data, err := os.ReadFile(t.HTML)
+			if err != nil {
+				return rendered, err
+			}
+
+			rendered.SourceHTML = string(data)
+		}
+	}
+
+	for _, part := range []struct {
+		file   string
+		target *string
+	}{{t.Plain, &rendered.SourcePlain}, {t.Subject, &rendered.SourceSubject}} {
+		if part.file == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(part.file)
+		if err != nil {
+			return rendered, err
+		}
+
+		*part.target = string(data)
+	}
+
+	rendered.SourceSubject = strings.TrimSpace(rendered.SourceSubject)
+
+	return rendered, nil
+}
+
+// Render compiles the template and renders it with the fixture of its type.
+// Errors are returned in Rendered.Err, so the gallery can show them.
+func (r Renderer) Render(ctx context.Context, t Template) Rendered {
+	rendered, err := r.Compile(ctx, t)
+	if err != nil {
+		rendered.Err = err
+		return rendered
+	}
+
+	vars, err := r.Fixture(t)
+	if err != nil {
+		rendered.Err = err
+		return rendered
+	}
+
+	warnings := map[string]bool{}
+
+	for _, part := range []struct {
+		name   string
+		source string
+		target *string
+		escape bool
+	}{
+		{"subject", rendered.SourceSubject, &rendered.Subject, false},
+		{"html", rendered.SourceHTML, &rendered.HTML, true},
+		{"plain", rendered.SourcePlain, &rendered.Plain, false},
+	} {
+		if part.source == "" {
+			continue
+		}
+
+		out, partWarnings, err := renderTemplate(part.source, cloneVars(vars), part.escape)
+		if err != nil {
+			rendered.Err = fmt.Errorf("%s: %w", part.name, err)
+			return rendered
+		}
+
+		*part.target = out
+
+		for _, warning := range partWarnings {
+			warnings[part.name+": "+warning] = true
+		}
+	}
+
+	for warning := range warnings {
+		rendered.Warnings = append(rendered.Warnings, warning)
+	}
+
+	slices.Sort(rendered.Warnings)
+
+	return rendered
+}
+
+// Fixture returns the sample data for the template: the built-in defaults,
+// the built-in data of the mail type, <type>.json of the fixture directories
+// and fixture.json next to the template, merged in this order.
+func (r Renderer) Fixture(t Template) (map[string]any, error) {
+	vars := map[string]any{}
+
+	for _, name := range []string{"_default", builtinFixtureName(t.Type)} {
+		data, err := embeddedFixtures.ReadFile("fixtures/" + name + ".json")
+		if err != nil {
+			continue
+		}
+
+		if err := mergeFixture(vars, data); err != nil {
+			return nil, fmt.Errorf("built-in fixture %s: %w", name, err)
+		}
+	}
+
+	files := make([]string, 0, len(r.FixtureDirs)+1)
+	for _, dir := range r.FixtureDirs {
+		files = append(files, filepath.Join(dir, t.Type+".json"))
+	}
+
+	files = append(files, filepath.Join(t.Dir, fixtureJSON))
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := mergeFixture(vars, data); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return vars, nil
+}
+
+// builtinFixtureName maps a mail template type to the built-in sample data.
+// All order state mails share the order fixture.
+func builtinFixtureName(mailType string) string {
+	if strings.HasPrefix(mailType, "order") || strings.HasPrefix(mailType, "delivery_mail") || strings.HasPrefix(mailType, "invoice_mail") || strings.HasPrefix(mailType, "credit_note_mail") || strings.HasPrefix(mailType, "cancellation_mail") {
+		return "order"
+	}
+
+	if strings.HasPrefix(mailType, "customer_register") || mailType == "customer.group.registration.accepted" || mailType == "customer.group.registration.declined" {
+		return "customer_register"
+	}
+
+	if strings.HasPrefix(mailType, "newsletter") {
+		return "newsletterDoubleOptIn"
+	}
+
+	return mailType
+}
+
+func mergeFixture(vars map[string]any, data []byte) error {
+	var fixture map[string]any
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return err
+	}
+
+	deepMerge(vars, fixture)
+
+	return nil
+}
+
+func deepMerge(target, source map[string]any) {
+	for key, value := range source {
+		sourceMap, sourceIsMap := value.(map[string]any)
+		targetMap, targetIsMap := target[key].(map[string]any)
+
+		if sourceIsMap && targetIsMap {
+			deepMerge(targetMap, sourceMap)
+			continue
+		}
+
+		target[key] = value
+	}
+}
+
+// cloneVars copies the top level, so set in one part does not leak into the
+// next.
+func cloneVars(vars map[string]any) map[string]any {
+	clone := make(map[string]any, len(vars))
+	for key, value := range vars {
+		clone[key] = value
+	}
+
+	return clone
+}
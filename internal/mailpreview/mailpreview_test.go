@@ -0,0 +1,265 @@
+package mailpreview
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+	"github.com/shopware/shopware-cli/internal/mjml"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func stubMJML(t *testing.T) {
+	t.Helper()
+
+	original := compileMJML
+	compileMJML = func(_ context.Context, path string, _ mjml.CompileOptions) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.ReplaceAll(strings.ReplaceAll(string(data), "<mjml>", "<html>"), "</mjml>", "</html>"), nil
+	}
+
+	t.Cleanup(func() { compileMJML = original })
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "email", "order_confirmation_mail", "html.mjml"), "<mjml></mjml>")
+	writeFile(t, filepath.Join(root, "email", "order_confirmation_mail", "html.twig"), "compiled")
+	writeFile(t, filepath.Join(root, "email", "order_confirmation_mail", "plain.twig"), "plain")
+	writeFile(t, filepath.Join(root, "email", "contact_form", "de-DE", "html.twig"), "html")
+	writeFile(t, filepath.Join(root, "email", "contact_form", "de-DE", "subject.twig"), "subject")
+	writeFile(t, filepath.Join(root, "email", "only_subject", "subject.twig"), "subject")
+	writeFile(t, filepath.Join(root, "node_modules", "pkg", "password_change", "html.twig"), "html")
+
+	templates, err := Discover([]string{root, filepath.Join(root, "missing")})
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+
+	assert.Equal(t, "contact_form/de-DE", templates[0].ID())
+	assert.Equal(t, "contact_form", templates[0].Type)
+	assert.Equal(t, "de-DE", templates[0].Locale)
+	assert.Empty(t, templates[0].Plain)
+
+	assert.Equal(t, "order_confirmation_mail", templates[1].ID())
+	assert.Equal(t, "html.mjml", filepath.Base(templates[1].HTML))
+	assert.Equal(t, "plain.twig", filepath.Base(templates[1].Plain))
+}
+
+func TestFixtureMerging(t *testing.T) {
+	root := t.TempDir()
+	fixtures := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "order_confirmation_mail", "html.twig"), "html")
+	writeFile(t, filepath.Join(root, "order_confirmation_mail", "fixture.json"), `{"order": {"orderNumber": "TEMPLATE"}}`)
+	writeFile(t, filepath.Join(fixtures, "order_confirmation_mail.json"), `{"order": {"orderNumber": "DIR", "custom": true}}`)
+
+	templates, err := Discover([]string{root})
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+
+	vars, err := Renderer{FixtureDirs: []string{fixtures}}.Fixture(templates[0])
+	require.NoError(t, err)
+
+	order, ok := vars["order"].(map[string]any)
+	require.True(t, ok)
+
+	assert.Equal(t, "TEMPLATE", order["orderNumber"])
+	assert.Equal(t, true, order["custom"])
+	// from the built-in order fixture
+	assert.NotEmpty(t, order["lineItems"])
+	// from the built-in defaults
+	assert.NotNil(t, vars["salesChannel"])
+}
+
+func TestRender(t *testing.T) {
+	stubMJML(t)
+
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "contact_form", "html.mjml"), "<mjml>{{ contactFormData.firstName }} {{ unknown }}</mjml>")
+	writeFile(t, filepath.Join(root, "contact_form", "plain.twig"), "Hello {{ contactFormData.firstName }}")
+	writeFile(t, filepath.Join(root, "contact_form", "subject.twig"), "  Contact from {{ contactFormData.firstName }}\n")
+	writeFile(t, filepath.Join(root, "contact_form", "fixture.json"), `{"contactFormData": {"firstName": "<Jane>"}}`)
+
+	templates, err := Discover([]string{root})
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+
+	rendered := Renderer{}.Render(t.Context(), templates[0])
+	require.NoError(t, rendered.Err)
+
+	assert.Equal(t, "<html>{{ contactFormData.firstName }} {{ unknown }}</html>", rendered.SourceHTML)
+	assert.Equal(t, "<html>&lt;Jane&gt; </html>", rendered.HTML)
+	assert.Equal(t, "Hello <Jane>", rendered.Plain)
+	assert.Equal(t, "Contact from <Jane>", rendered.Subject)
+	assert.Equal(t, []string{"html: the variable unknown is not defined in the fixture"}, rendered.Warnings)
+}
+
+func TestRenderMJMLError(t *testing.T) {
+	original := compileMJML
+	compileMJML = func(context.Context, string, mjml.CompileOptions) (string, error) {
+		return "", errors.New("mjml failed")
+	}
+	t.Cleanup(func() { compileMJML = original })
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "contact_form", "html.mjml"), "<mjml></mjml>")
+
+	templates, err := Discover([]string{root})
+	require.NoError(t, err)
+
+	rendered := Renderer{}.Render(t.Context(), templates[0])
+	assert.EqualError(t, rendered.Err, "mjml failed")
+}
+
+func TestServer(t *testing.T) {
+	stubMJML(t)
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "contact_form", "html.mjml"), "<mjml>{{ contactFormData.firstName }}</mjml>")
+	writeFile(t, filepath.Join(root, "contact_form", "plain.twig"), "Plain {{ contactFormData.firstName }}")
+	writeFile(t, filepath.Join(root, "contact_form", "fixture.json"), `{"contactFormData": {"firstName": "Jane"}}`)
+
+	var pushed []Rendered
+
+	server, err := NewServer(ServerOptions{
+		SearchPaths: []string{root},
+		Push: func(_ context.Context, rendered Rendered) error {
+			pushed = append(pushed, rendered)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.Templates())
+
+	request := func(method, url string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := request(http.MethodGet, "/")
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/template/contact_form", rec.Header().Get("Location"))
+
+	rec = request(http.MethodGet, "/template/contact_form")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Plain Jane")
+	assert.Contains(t, rec.Body.String(), `src="/html/contact_form"`)
+	assert.Contains(t, rec.Body.String(), "Push to shop")
+	assert.Contains(t, rec.Body.String(), `data-token="`+server.pushToken+`"`)
+
+	rec = request(http.MethodGet, "/html/contact_form")
+	assert.Equal(t, "<html>Jane</html>", rec.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/template/unknown").Code)
+
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/push/contact_form").Code)
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/push/contact_form", pushTokenHeader, "guessed").Code)
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/push/contact_form", pushTokenHeader, server.pushToken, "Origin", "https://evil.example").Code)
+	assert.Empty(t, pushed)
+
+	rec = request(http.MethodPost, "/push/contact_form", pushTokenHeader, server.pushToken, "Origin", "http://example.com")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, pushed, 1)
+	assert.Equal(t, "<html>{{ contactFormData.firstName }}</html>", pushed[0].SourceHTML)
+}
+
+func TestServerRefreshOnChange(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "contact_form", "plain.twig"), "first")
+
+	server, err := NewServer(ServerOptions{SearchPaths: []string{root}})
+	require.NoError(t, err)
+
+	before := server.currentVersion()
+	assert.Equal(t, before, server.snapshot())
+
+	writeFile(t, filepath.Join(root, "password_change", "plain.twig"), "second")
+	assert.NotEqual(t, before, server.snapshot())
+
+	require.NoError(t, server.refresh())
+	assert.Equal(t, 2, server.Templates())
+}
+
+type fakeMailTemplateAPI struct {
+	templates []adminSdk.MailTemplate
+	languages map[string]string
+	updates   map[string]adminSdk.MailTemplateUpdate
+	language  string
+}
+
+func (f *fakeMailTemplateAPI) ListByType(_ adminSdk.ApiContext, technicalName string) ([]adminSdk.MailTemplate, error) {
+	if technicalName != "contact_form" {
+		return nil, nil
+	}
+
+	return f.templates, nil
+}
+
+func (f *fakeMailTemplateAPI) Update(ctx adminSdk.ApiContext, id string, update adminSdk.MailTemplateUpdate) error {
+	f.language = ctx.LanguageId
+	f.updates[id] = update
+
+	return nil
+}
+
+func (f *fakeMailTemplateAPI) LanguageIdByLocale(_ adminSdk.ApiContext, locale string) (string, error) {
+	if id, ok := f.languages[locale]; ok {
+		return id, nil
+	}
+
+	return "", adminSdk.ErrNotFound
+}
+
+func TestPush(t *testing.T) {
+	api := &fakeMailTemplateAPI{
+		templates: []adminSdk.MailTemplate{{Id: "a"}, {Id: "b"}},
+		languages: map[string]string{"de-DE": "german"},
+		updates:   map[string]adminSdk.MailTemplateUpdate{},
+	}
+
+	rendered := Rendered{
+		Template:      Template{Type: "contact_form", Locale: "de-DE"},
+		SourceSubject: "Subject",
+		SourceHTML:    "<p>{{ name }}</p>",
+	}
+
+	require.NoError(t, push(t.Context(), api, rendered))
+	assert.Equal(t, "german", api.language)
+	assert.Len(t, api.updates, 2)
+	assert.Equal(t, adminSdk.MailTemplateUpdate{Subject: "Subject", ContentHtml: "<p>{{ name }}</p>"}, api.updates["a"])
+
+	rendered.Template.Locale = "fr-FR"
+	assert.EqualError(t, push(t.Context(), api, rendered), "the shop has no language with the locale fr-FR")
+
+	rendered.Template = Template{Type: "password_change"}
+	assert.EqualError(t, push(t.Context(), api, rendered), "the shop has no mail template of the type password_change")
+}
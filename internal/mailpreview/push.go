@@ -0,0 +1,81 @@
+package mailpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+type mailTemplateAPI interface {
+	ListByType(ctx adminSdk.ApiContext, technicalName string) ([]adminSdk.MailTemplate, error)
+	Update(ctx adminSdk.ApiContext, id string, update adminSdk.MailTemplateUpdate) error
+	LanguageIdByLocale(ctx adminSdk.ApiContext, locale string) (string, error)
+}
+
+// Push writes the compiled sources to all mail templates of the type. The
+// sources keep their Twig placeholders, the shop renders them when sending.
+// Templates without a locale directory update the default language.
+func Push(ctx context.Context, client *adminSdk.Client, rendered Rendered) error {
+	return push(ctx, client.MailTemplate, rendered)
+}
+
+// PushAll compiles and pushes all templates and stops at the first error.
+func PushAll(ctx context.Context, templates []Template, renderer Renderer, pushFunc PushFunc) error {
+	for _, template := range templates {
+		rendered, err := renderer.Compile(ctx, template)
+		if err != nil {
+			return fmt.Errorf("%s: %w", template.ID(), err)
+		}
+
+		if err := pushFunc(ctx, rendered); err != nil {
+			return fmt.Errorf("%s: %w", template.ID(), err)
+		}
+
+		logging.FromContext(ctx).Infof("Pushed %s", template.ID())
+	}
+
+	return nil
+}
+
+func push(ctx context.Context, api mailTemplateAPI, rendered Rendered) error {
+	apiCtx := adminSdk.NewApiContext(ctx)
+
+	if rendered.Template.Locale != "" {
+		languageId, err := api.LanguageIdByLocale(apiCtx, rendered.Template.Locale)
+		if errors.Is(err, adminSdk.ErrNotFound) {
+			return fmt.Errorf("the shop has no language with the locale %s", rendered.Template.Locale)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot resolve language %s: %w", rendered.Template.Locale, err)
+		}
+
+		apiCtx.LanguageId = languageId
+	}
+
+	templates, err := api.ListByType(apiCtx, rendered.Template.Type)
+	if err != nil {
+		return err
+	}
+
+	if len(templates) == 0 {
+		return fmt.Errorf("the shop has no mail template of the type %s", rendered.Template.Type)
+	}
+
+	update := adminSdk.MailTemplateUpdate{
+		Subject:      rendered.SourceSubject,
+		ContentHtml:  rendered.SourceHTML,
+		ContentPlain: rendered.SourcePlain,
+	}
+
+	for _, template := range templates {
+		if err := api.Update(apiCtx, template.Id, update); err != nil {
+			return fmt.Errorf("cannot update mail template %s: %w", template.Id, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package mailpreview
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// Run pushes all templates or serves the gallery. The client is created on
+// the first push, so the gallery works without a shop.
+func Run(ctx context.Context, searchPaths []string, renderer Renderer, listen string, pushOnly bool, newClient func(context.Context) (*adminSdk.Client, error)) error {
+	var (
+		clientMu sync.Mutex
+		client   *adminSdk.Client
+	)
+
+	// pushes of the gallery run in concurrent requests, a failed login is retried on the next push
+	getClient := func(ctx context.Context) (*adminSdk.Client, error) {
+		clientMu.Lock()
+		defer clientMu.Unlock()
+
+		if client == nil {
+			var err error
+			if client, err = newClient(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		return client, nil
+	}
+
+	push := func(ctx context.Context, rendered Rendered) error {
+		client, err := getClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		return Push(ctx, client, rendered)
+	}
+
+	if pushOnly {
+		templates, err := Discover(searchPaths)
+		if err != nil {
+			return err
+		}
+
+		if len(templates) == 0 {
+			return fmt.Errorf("no mail templates found")
+		}
+
+		return PushAll(ctx, templates, renderer, push)
+	}
+
+	server, err := NewServer(ServerOptions{
+		SearchPaths: searchPaths,
+		Renderer:    renderer,
+		Push:        push,
+	})
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", listen, err)
+	}
+
+	logging.FromContext(ctx).Infof("Serving %d mail templates on http://%s", server.Templates(), listener.Addr())
+
+	return server.ListenAndServe(ctx, listener)
+}
@@ -0,0 +1,503 @@
+package mailpreview
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// pushTokenHeader carries the push token of the gallery page, other websites
+// cannot read it and a custom header cannot be sent cross-origin without a preflight.
+const pushTokenHeader = "X-Push-Token"
+
+// PushFunc writes the compiled template to a shop.
+type PushFunc func(ctx context.Context, rendered Rendered) error
+
+// ServerOptions configures the gallery.
+type ServerOptions struct {
+	SearchPaths []string
+	Renderer    Renderer
+	// Push enables the push button, nil disables it
+	Push PushFunc
+	// PollInterval is how often the files are checked for changes
+	PollInterval time.Duration
+}
+
+// Server serves a gallery of all mail templates and reloads the browser when
+// a template or fixture changes.
+type Server struct {
+	opts ServerOptions
+	mux  *http.ServeMux
+	// pushToken is required to push, so only the gallery page can overwrite templates in the shop
+	pushToken string
+
+	mu        sync.Mutex
+	version   uint64
+	templates []galleryEntry
+	rendered  map[string]Rendered
+	listeners map[chan struct{}]bool
+}
+
+type galleryEntry struct {
+	ID       string
+	Template Template
+}
+
+// NewServer discovers the templates of the search paths.
+func NewServer(opts ServerOptions) (*Server, error) {
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 500 * time.Millisecond
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("generate push token: %w", err)
+	}
+
+	s := &Server{opts: opts, mux: http.NewServeMux(), pushToken: hex.EncodeToString(token), listeners: map[chan struct{}]bool{}}
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	s.mux.HandleFunc("GET /{$}", s.handleIndex)
+	s.mux.HandleFunc("GET /template/{id...}", s.handleTemplate)
+	s.mux.HandleFunc("GET /html/{id...}", s.handleHTML)
+	s.mux.HandleFunc("POST /push/{id...}", s.handlePush)
+	s.mux.HandleFunc("GET /events", s.handleEvents)
+
+	return s, nil
+}
+
+// ListenAndServe serves the gallery and watches for changes until the context
+// is done.
+func (s *Server) ListenAndServe(ctx context.Context, listener net.Listener) error {
+	httpServer := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go s.Watch(ctx)
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Templates returns the number of discovered templates.
+func (s *Server) Templates() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.templates)
+}
+
+// Watch polls the search paths and fixture directories until the context is
+// done and notifies the browsers about changes.
+func (s *Server) Watch(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.snapshot() == s.currentVersion() {
+				continue
+			}
+
+			if err := s.refresh(); err != nil {
+				logging.FromContext(ctx).Warnf("Could not discover mail templates: %v", err)
+				continue
+			}
+
+			s.notify()
+		}
+	}
+}
+
+func (s *Server) currentVersion() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.version
+}
+
+func (s *Server) refresh() error {
+	version := s.snapshot()
+
+	templates, err := Discover(s.opts.SearchPaths)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]galleryEntry, 0, len(templates))
+	seen := map[string]int{}
+
+	for _, t := range templates {
+		id := t.ID()
+		seen[id]++
+
+		// the same mail type in several extensions
+		if seen[id] > 1 {
+			id = fmt.Sprintf("%s~%d", id, seen[id])
+		}
+
+		entries = append(entries, galleryEntry{ID: id, Template: t})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.version = version
+	s.templates = entries
+	s.rendered = map[string]Rendered{}
+
+	return nil
+}
+
+// snapshot hashes path, size and modification time of all relevant files.
+func (s *Server) snapshot() uint64 {
+	hash := fnv.New64a()
+
+	roots := append(append([]string{}, s.opts.SearchPaths...), s.opts.Renderer.FixtureDirs...)
+
+	for _, root := range roots {
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+
+			if d.IsDir() {
+				switch d.Name() {
+				case "node_modules", "vendor", ".git":
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			switch filepath.Ext(path) {
+			case ".mjml", ".twig", ".json":
+			default:
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+
+			_, _ = fmt.Fprintf(hash, "%s:%d:%d\n", path, info.Size(), info.ModTime().UnixNano())
+
+			return nil
+		})
+	}
+
+	return hash.Sum64()
+}
+
+func (s *Server) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for listener := range s.listeners {
+		select {
+		case listener <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *Server) lookup(id string) (galleryEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.templates {
+		if entry.ID == id {
+			return entry, true
+		}
+	}
+
+	return galleryEntry{}, false
+}
+
+func (s *Server) render(ctx context.Context, entry galleryEntry) Rendered {
+	s.mu.Lock()
+	rendered, ok := s.rendered[entry.ID]
+	s.mu.Unlock()
+
+	if ok {
+		return rendered
+	}
+
+	rendered = s.opts.Renderer.Render(ctx, entry.Template)
+
+	s.mu.Lock()
+	s.rendered[entry.ID] = rendered
+	s.mu.Unlock()
+
+	return rendered
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	entries := append([]galleryEntry{}, s.templates...)
+	s.mu.Unlock()
+
+	if len(entries) > 0 {
+		http.Redirect(w, r, "/template/"+entries[0].ID, http.StatusFound)
+		return
+	}
+
+	s.writePage(w, pageData{SearchPaths: s.opts.SearchPaths})
+}
+
+func (s *Server) handleTemplate(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.lookup(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	entries := append([]galleryEntry{}, s.templates...)
+	s.mu.Unlock()
+
+	rendered := s.render(r.Context(), entry)
+
+	data := pageData{
+		Entries:     entries,
+		Current:     entry,
+		Rendered:    rendered,
+		CanPush:     s.opts.Push != nil,
+		PushToken:   s.pushToken,
+		SearchPaths: s.opts.SearchPaths,
+	}
+
+	if rendered.Err != nil {
+		data.Error = rendered.Err.Error()
+	}
+
+	if rel, err := filepath.Rel(entry.Template.Root, entry.Template.Dir); err == nil {
+		data.RelDir = rel
+	}
+
+	s.writePage(w, data)
+}
+
+func (s *Server) handleHTML(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.lookup(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	rendered := s.render(r.Context(), entry)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if rendered.Err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprintf(w, "<pre>%s</pre>", template.HTMLEscapeString(rendered.Err.Error()))
+
+		return
+	}
+
+	_, _ = w.Write([]byte(rendered.HTML))
+}
+
+func (s *Server) handlePush(w http.ResponseWriter, r *http.Request) {
+	if s.opts.Push == nil {
+		http.Error(w, "push is not configured", http.StatusNotFound)
+		return
+	}
+
+	if !s.allowPush(r) {
+		http.Error(w, "push is only allowed from the mail preview page", http.StatusForbidden)
+		return
+	}
+
+	entry, ok := s.lookup(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	rendered, err := s.opts.Renderer.Compile(r.Context(), entry.Template)
+	if err == nil {
+		err = s.opts.Push(r.Context(), rendered)
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Pushed %s to the shop", entry.ID)
+}
+
+// allowPush requires the push token and rejects requests of other origins.
+func (s *Server) allowPush(r *http.Request) bool {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(pushTokenHeader)), []byte(s.pushToken)) != 1 {
+		return false
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		parsed, err := url.Parse(origin)
+		if err != nil || parsed.Host != r.Host {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	listener := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	s.listeners[listener] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, listener)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-listener:
+			_, _ = fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+type pageData struct {
+	Entries     []galleryEntry
+	Current     galleryEntry
+	Rendered    Rendered
+	RelDir      string
+	Error       string
+	CanPush     bool
+	PushToken   string
+	SearchPaths []string
+}
+
+func (s *Server) writePage(w http.ResponseWriter, data pageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := pageTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ if .Current.ID }}{{ .Current.ID }} - {{ end }}Mail preview</title>
+<style>
+body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; display: flex; height: 100vh; color: #1a1a1a; }
+nav { width: 260px; border-right: 1px solid #ddd; overflow-y: auto; background: #f7f8fa; }
+nav h1 { font-size: 15px; margin: 16px; color: #076fff; }
+nav a { display: block; padding: 6px 16px; color: inherit; text-decoration: none; font-size: 13px; word-break: break-all; }
+nav a.active { background: #076fff; color: #fff; }
+main { flex: 1; display: flex; flex-direction: column; min-width: 0; }
+header { padding: 12px 16px; border-bottom: 1px solid #ddd; display: flex; gap: 16px; align-items: center; }
+header .subject { font-weight: 600; flex: 1; }
+header .dir { color: #666; font-size: 12px; }
+.panes { flex: 1; display: flex; min-height: 0; }
+.panes iframe { flex: 1; border: 0; border-right: 1px solid #ddd; }
+.panes pre { flex: 1; margin: 0; padding: 16px; overflow: auto; white-space: pre-wrap; background: #fafafa; font-size: 13px; }
+.error { background: #ffe9e9; color: #a40000; padding: 12px 16px; white-space: pre-wrap; font-family: monospace; }
+.warnings { background: #fff7e0; color: #7a5400; padding: 8px 16px; font-size: 12px; max-height: 120px; overflow-y: auto; }
+button { background: #076fff; color: #fff; border: 0; border-radius: 4px; padding: 6px 12px; cursor: pointer; }
+</style>
+</head>
+<body>
+<nav>
+<h1>Mail templates</h1>
+{{ range .Entries }}<a href="/template/{{ .ID }}"{{ if eq .ID $.Current.ID }} class="active"{{ end }}>{{ .ID }}</a>
+{{ end }}
+</nav>
+<main>
+{{ if .Current.ID }}
+<header>
+<span class="subject">{{ if .Rendered.Subject }}{{ .Rendered.Subject }}{{ else }}(no subject.twig){{ end }}</span>
+<span class="dir">{{ .RelDir }}</span>
+{{ if .CanPush }}<button id="push" data-id="{{ .Current.ID }}" data-token="{{ .PushToken }}">Push to shop</button>{{ end }}
+</header>
+{{ if .Error }}<div class="error">{{ .Error }}</div>{{ end }}
+{{ if .Rendered.Warnings }}<div class="warnings">{{ range .Rendered.Warnings }}<div>{{ . }}</div>{{ end }}</div>{{ end }}
+<div class="panes">
+<iframe src="/html/{{ .Current.ID }}" title="HTML"></iframe>
+<pre>{{ if .Rendered.Plain }}{{ .Rendered.Plain }}{{ else }}(no plain.twig){{ end }}</pre>
+</div>
+{{ else }}
+<div class="error">No mail templates found in {{ join .SearchPaths ", " }}. Templates are directories named after the mail template type containing html.mjml, html.twig, plain.twig or subject.twig.</div>
+{{ end }}
+</main>
+<script>
+new EventSource('/events').addEventListener('reload', () => location.reload());
+const push = document.getElementById('push');
+if (push) {
+    push.addEventListener('click', async () => {
+        push.disabled = true;
+        const response = await fetch('/push/' + push.dataset.id, { method: 'POST', headers: { 'X-Push-Token': push.dataset.token } });
+        alert(await response.text());
+        push.disabled = false;
+    });
+}
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,621 @@
+package mailpreview
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// The renderer implements the part of Twig that mail templates use: output,
+// if, for, set, filters, tests and the common operators. Tags like include or
+// macro are not supported and reported as warnings, so a preview is still
+// shown. It is a preview helper, the shop renders the real mails.
+
+type twigTokenType int
+
+const (
+	twigText twigTokenType = iota
+	twigPrint
+	twigTag
+)
+
+type twigToken struct {
+	typ   twigTokenType
+	value string
+	line  int
+}
+
+// lexTemplate splits the template into text, {{ }} and {% %} tokens. Comments
+// are dropped and the whitespace control modifier trims the adjacent text.
+func lexTemplate(src string) ([]twigToken, error) {
+	var tokens []twigToken
+
+	line := 1
+	trimNext := false
+
+	for len(src) > 0 {
+		start := strings.Index(src, "{")
+		for start != -1 && (start+1 >= len(src) || !strings.ContainsRune("{%#", rune(src[start+1]))) {
+			next := strings.Index(src[start+1:], "{")
+			if next == -1 {
+				start = -1
+			} else {
+				start += next + 1
+			}
+		}
+
+		if start == -1 {
+			tokens = appendText(tokens, src, line, trimNext, false)
+			break
+		}
+
+		opener := src[start+1]
+		closer := map[byte]string{'{': "}}", '%': "%}", '#': "#}"}[opener]
+
+		trimPrev := start+2 < len(src) && src[start+2] == '-'
+		tokens = appendText(tokens, src[:start], line, trimNext, trimPrev)
+		line += strings.Count(src[:start], "\n")
+
+		end := findCloser(src, start+2, closer)
+		if end == -1 {
+			return nil, fmt.Errorf("line %d: unclosed %s", line, src[start:start+2])
+		}
+
+		inner := src[start+2 : end]
+		inner = strings.TrimPrefix(inner, "-")
+		trimNext = strings.HasSuffix(inner, "-")
+		inner = strings.TrimSpace(strings.TrimSuffix(inner, "-"))
+
+		switch opener {
+		case '{':
+			tokens = append(tokens, twigToken{typ: twigPrint, value: inner, line: line})
+		case '%':
+			tokens = append(tokens, twigToken{typ: twigTag, value: inner, line: line})
+		}
+
+		line += strings.Count(src[start:end+2], "\n")
+		src = src[end+2:]
+	}
+
+	return tokens, nil
+}
+
+func appendText(tokens []twigToken, text string, line int, trimLeft, trimRight bool) []twigToken {
+	if trimLeft {
+		text = strings.TrimLeft(text, " \t\r\n")
+	}
+
+	if trimRight {
+		text = strings.TrimRight(text, " \t\r\n")
+	}
+
+	if text == "" {
+		return tokens
+	}
+
+	return append(tokens, twigToken{typ: twigText, value: text, line: line})
+}
+
+// findCloser returns the position of closer, skipping string literals.
+func findCloser(src string, from int, closer string) int {
+	var quote byte
+
+	for i := from; i < len(src)-1; i++ {
+		c := src[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(src[i:], closer):
+			return i
+		}
+	}
+
+	return -1
+}
+
+type twigNode interface {
+	render(r *renderer, out *strings.Builder) error
+}
+
+type textNode string
+
+type printNode struct {
+	expr expr
+	line int
+}
+
+type ifBranch struct {
+	cond expr
+	body []twigNode
+}
+
+type ifNode struct {
+	branches []ifBranch
+	orElse   []twigNode
+}
+
+type forNode struct {
+	key, value string
+	iter       expr
+	cond       expr
+	body       []twigNode
+	orElse     []twigNode
+}
+
+type setNode struct {
+	name  string
+	value expr
+	body  []twigNode
+}
+
+type containerNode struct {
+	body []twigNode
+}
+
+// transparentTags render their body, their arguments have no effect on a
+// preview.
+var transparentTags = map[string]string{
+	"block":                  "endblock",
+	"autoescape":             "endautoescape",
+	"apply":                  "endapply",
+	"spaceless":              "endspaceless",
+	"with":                   "endwith",
+	"sw_silent_feature_call": "endsw_silent_feature_call",
+}
+
+type templateParser struct {
+	tokens   []twigToken
+	pos      int
+	warnings *[]string
+}
+
+// parseTemplate parses the source. Unsupported tags are skipped and reported
+// through warnings.
+func parseTemplate(src string, warnings *[]string) ([]twigNode, error) {
+	tokens, err := lexTemplate(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &templateParser{tokens: tokens, warnings: warnings}
+
+	nodes, _, err := p.parseUntil()
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// parseUntil parses nodes until one of the given tags and returns it.
+func (p *templateParser) parseUntil(stops ...string) ([]twigNode, string, error) {
+	var nodes []twigNode
+
+	for p.pos < len(p.tokens) {
+		token := p.tokens[p.pos]
+		p.pos++
+
+		switch token.typ {
+		case twigText:
+			nodes = append(nodes, textNode(token.value))
+		case twigPrint:
+			e, err := parseExpression(token.value)
+			if err != nil {
+				return nil, "", fmt.Errorf("line %d: %w", token.line, err)
+			}
+
+			nodes = append(nodes, printNode{expr: e, line: token.line})
+		case twigTag:
+			name, rest, _ := strings.Cut(token.value, " ")
+			rest = strings.TrimSpace(rest)
+
+			if slices.Contains(stops, name) {
+				// the arguments of the stop tag (elseif) are read by the caller
+				p.pos--
+				return nodes, name, nil
+			}
+
+			node, err := p.parseTag(name, rest, token.line)
+			if err != nil {
+				return nil, "", fmt.Errorf("line %d: %w", token.line, err)
+			}
+
+			if node != nil {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	if len(stops) > 0 {
+		return nil, "", fmt.Errorf("missing {%% %s %%}", stops[len(stops)-1])
+	}
+
+	return nodes, "", nil
+}
+
+func (p *templateParser) tagArgs() string {
+	_, rest, _ := strings.Cut(p.tokens[p.pos].value, " ")
+	p.pos++
+
+	return strings.TrimSpace(rest)
+}
+
+func (p *templateParser) parseTag(name, args string, line int) (twigNode, error) {
+	switch name {
+	case "if":
+		return p.parseIf(args)
+	case "for":
+		return p.parseFor(args)
+	case "set":
+		return p.parseSet(args)
+	}
+
+	if end, ok := transparentTags[name]; ok {
+		body, _, err := p.parseUntil(end)
+		if err != nil {
+			return nil, err
+		}
+
+		p.pos++
+
+		return containerNode{body: body}, nil
+	}
+
+	// the closing tags of unsupported tags like endmacro are skipped as well
+	if isClosingTag(name) {
+		return nil, fmt.Errorf("unexpected {%% %s %%}", name)
+	}
+
+	*p.warnings = append(*p.warnings, fmt.Sprintf("line %d: the tag {%% %s %%} is not supported by the preview and was skipped", line, name))
+
+	return nil, nil
+}
+
+// isClosingTag reports whether the tag closes or continues a supported tag.
+func isClosingTag(name string) bool {
+	switch name {
+	case "elseif", "else", "endif", "endfor", "endset":
+		return true
+	}
+
+	for _, end := range transparentTags {
+		if name == end {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *templateParser) parseIf(args string) (twigNode, error) {
+	node := ifNode{}
+	cond := args
+
+	for {
+		e, err := parseExpression(cond)
+		if err != nil {
+			return nil, err
+		}
+
+		body, stop, err := p.parseUntil("elseif", "else", "endif")
+		if err != nil {
+			return nil, err
+		}
+
+		node.branches = append(node.branches, ifBranch{cond: e, body: body})
+
+		switch stop {
+		case "elseif":
+			cond = p.tagArgs()
+			continue
+		case "else":
+			p.pos++
+
+			node.orElse, _, err = p.parseUntil("endif")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		p.pos++
+
+		return node, nil
+	}
+}
+
+func (p *templateParser) parseFor(args string) (twigNode, error) {
+	vars, iterSource, ok := strings.Cut(args, " in ")
+	if !ok {
+		return nil, fmt.Errorf("invalid for loop %q", args)
+	}
+
+	node := forNode{}
+
+	if key, value, hasKey := strings.Cut(vars, ","); hasKey {
+		node.key = strings.TrimSpace(key)
+		node.value = strings.TrimSpace(value)
+	} else {
+		node.value = strings.TrimSpace(vars)
+	}
+
+	if iterExpr, cond, hasCond := strings.Cut(iterSource, " if "); hasCond {
+		iterSource = iterExpr
+
+		e, err := parseExpression(cond)
+		if err != nil {
+			return nil, err
+		}
+
+		node.cond = e
+	}
+
+	iter, err := parseExpression(iterSource)
+	if err != nil {
+		return nil, err
+	}
+
+	node.iter = iter
+
+	body, stop, err := p.parseUntil("else", "endfor")
+	if err != nil {
+		return nil, err
+	}
+
+	node.body = body
+
+	if stop == "else" {
+		p.pos++
+
+		if node.orElse, _, err = p.parseUntil("endfor"); err != nil {
+			return nil, err
+		}
+	}
+
+	p.pos++
+
+	return node, nil
+}
+
+func (p *templateParser) parseSet(args string) (twigNode, error) {
+	name, value, hasValue := strings.Cut(args, "=")
+	name = strings.TrimSpace(name)
+
+	if hasValue {
+		e, err := parseExpression(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return setNode{name: name, value: e}, nil
+	}
+
+	body, _, err := p.parseUntil("endset")
+	if err != nil {
+		return nil, err
+	}
+
+	p.pos++
+
+	return setNode{name: name, body: body}, nil
+}
+
+// renderer holds the variables of one render call.
+type renderer struct {
+	scopes   []map[string]any
+	escape   bool
+	warnings map[string]bool
+}
+
+// renderTemplate renders src with the variables. When escape is true, output is
+// HTML escaped unless the raw filter is used.
+func renderTemplate(src string, vars map[string]any, escape bool) (string, []string, error) {
+	var parseWarnings []string
+
+	nodes, err := parseTemplate(src, &parseWarnings)
+	if err != nil {
+		return "", parseWarnings, err
+	}
+
+	// the template scope keeps set from changing the fixture
+	r := &renderer{scopes: []map[string]any{vars, {}}, escape: escape, warnings: map[string]bool{}}
+
+	var out strings.Builder
+	if err := r.renderNodes(nodes, &out); err != nil {
+		return "", parseWarnings, err
+	}
+
+	warnings := parseWarnings
+	for warning := range r.warnings {
+		warnings = append(warnings, warning)
+	}
+
+	slices.Sort(warnings[len(parseWarnings):])
+
+	return out.String(), warnings, nil
+}
+
+func (r *renderer) warn(format string, args ...any) {
+	r.warnings[fmt.Sprintf(format, args...)] = true
+}
+
+func (r *renderer) lookup(name string) (any, bool) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if value, ok := r.scopes[i][name]; ok {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (r *renderer) set(name string, value any) {
+	r.scopes[len(r.scopes)-1][name] = value
+}
+
+func (r *renderer) renderNodes(nodes []twigNode, out *strings.Builder) error {
+	for _, node := range nodes {
+		if err := node.render(r, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n textNode) render(_ *renderer, out *strings.Builder) error {
+	out.WriteString(string(n))
+	return nil
+}
+
+func (n printNode) render(r *renderer, out *strings.Builder) error {
+	value, err := n.expr.eval(r)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", n.line, err)
+	}
+
+	if safe, ok := value.(safeString); ok {
+		out.WriteString(string(safe))
+		return nil
+	}
+
+	text := toString(value)
+	if r.escape {
+		text = html.EscapeString(text)
+	}
+
+	out.WriteString(text)
+
+	return nil
+}
+
+func (n ifNode) render(r *renderer, out *strings.Builder) error {
+	for _, branch := range n.branches {
+		value, err := branch.cond.eval(r)
+		if err != nil {
+			return err
+		}
+
+		if truthy(value) {
+			return r.renderNodes(branch.body, out)
+		}
+	}
+
+	return r.renderNodes(n.orElse, out)
+}
+
+func (n forNode) render(r *renderer, out *strings.Builder) error {
+	value, err := n.iter.eval(r)
+	if err != nil {
+		return err
+	}
+
+	keys, values := iterate(value)
+
+	r.scopes = append(r.scopes, map[string]any{})
+	defer func() {
+		r.scopes = r.scopes[:len(r.scopes)-1]
+	}()
+
+	type item struct {
+		key, value any
+	}
+
+	items := make([]item, 0, len(values))
+
+	for i := range values {
+		r.set(n.value, values[i])
+
+		if n.key != "" {
+			r.set(n.key, keys[i])
+		}
+
+		if n.cond != nil {
+			ok, err := n.cond.eval(r)
+			if err != nil {
+				return err
+			}
+
+			if !truthy(ok) {
+				continue
+			}
+		}
+
+		items = append(items, item{key: keys[i], value: values[i]})
+	}
+
+	if len(items) == 0 {
+		return r.renderNodes(n.orElse, out)
+	}
+
+	for i, it := range items {
+		r.set(n.value, it.value)
+
+		if n.key != "" {
+			r.set(n.key, it.key)
+		}
+
+		r.set("loop", map[string]any{
+			"index":     float64(i + 1),
+			"index0":    float64(i),
+			"revindex":  float64(len(items) - i),
+			"revindex0": float64(len(items) - i - 1),
+			"first":     i == 0,
+			"last":      i == len(items)-1,
+			"length":    float64(len(items)),
+		})
+
+		if err := r.renderNodes(n.body, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n setNode) render(r *renderer, out *strings.Builder) error {
+	if n.value == nil {
+		var captured strings.Builder
+		if err := r.renderNodes(n.body, &captured); err != nil {
+			return err
+		}
+
+		r.set(n.name, safeString(captured.String()))
+
+		return nil
+	}
+
+	value, err := n.value.eval(r)
+	if err != nil {
+		return err
+	}
+
+	// set inside a loop changes the variable of the template, like in Twig,
+	// new variables are scoped to the loop
+	for i := len(r.scopes) - 1; i > 0; i-- {
+		if _, ok := r.scopes[i][n.name]; ok {
+			r.scopes[i][n.name] = value
+			return nil
+		}
+	}
+
+	if _, ok := r.scopes[0][n.name]; ok {
+		r.scopes[1][n.name] = value
+		return nil
+	}
+
+	r.set(n.name, value)
+
+	return nil
+}
+
+func (n containerNode) render(r *renderer, out *strings.Builder) error {
+	return r.renderNodes(n.body, out)
+}
@@ -0,0 +1,1032 @@
+package mailpreview
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type expr interface {
+	eval(r *renderer) (any, error)
+}
+
+// safeString is output which must not be escaped again.
+type safeString string
+
+type literalExpr struct{ value any }
+
+type nameExpr struct{ name string }
+
+type attrExpr struct {
+	target expr
+	attr   expr
+	// path is the dotted variable path for warnings about missing fixture data
+	path string
+}
+
+type arrayExpr struct{ items []expr }
+
+type hashExpr struct {
+	keys   []expr
+	values []expr
+}
+
+type unaryExpr struct {
+	op      string
+	operand expr
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+type conditionalExpr struct {
+	cond, then, orElse expr
+}
+
+type filterExpr struct {
+	target expr
+	name   string
+	args   []expr
+}
+
+type callExpr struct {
+	name string
+	args []expr
+}
+
+type testExpr struct {
+	target expr
+	name   string
+	args   []expr
+	negate bool
+}
+
+type exprToken struct {
+	kind  string // name, number, string, op, punct, eof
+	value string
+}
+
+func tokenizeExpression(src string) ([]exprToken, error) {
+	var tokens []exprToken
+
+	operators := []string{"not in", "starts with", "ends with", "is not", "b-and", "b-or", "...", "==", "!=", "<=", ">=", "//", "**", "??", "?:", "..", "+", "-", "*", "/", "%", "~", "<", ">", "?", ":"}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			j := i + 1
+
+			var value strings.Builder
+
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' && j+1 < len(src) {
+					j++
+				}
+
+				value.WriteByte(src[j])
+				j++
+			}
+
+			if j >= len(src) {
+				return nil, fmt.Errorf("unclosed string in %q", src)
+			}
+
+			tokens = append(tokens, exprToken{kind: "string", value: value.String()})
+			i = j + 1
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' && j+1 < len(src) && src[j+1] >= '0' && src[j+1] <= '9') {
+				j++
+			}
+
+			tokens = append(tokens, exprToken{kind: "number", value: src[i:j]})
+			i = j
+		case isNameStart(c):
+			j := i
+			for j < len(src) && isNameRune(src[j]) {
+				j++
+			}
+
+			word := src[i:j]
+
+			// operators of several words or with a dash
+			matched := false
+
+			for _, op := range operators {
+				if isNameStart(op[0]) && strings.HasPrefix(src[i:], op) && (i+len(op) == len(src) || !isNameRune(src[i+len(op)])) {
+					tokens = append(tokens, exprToken{kind: "op", value: op})
+					i += len(op)
+					matched = true
+
+					break
+				}
+			}
+
+			if matched {
+				continue
+			}
+
+			switch word {
+			case "and", "or", "not", "in", "is", "matches":
+				tokens = append(tokens, exprToken{kind: "op", value: word})
+			default:
+				tokens = append(tokens, exprToken{kind: "name", value: word})
+			}
+
+			i = j
+		case strings.ContainsRune("()[]{},.|", rune(c)):
+			// the range operator starts with a dot too
+			if c == '.' && strings.HasPrefix(src[i:], "..") {
+				tokens = append(tokens, exprToken{kind: "op", value: ".."})
+				i += 2
+
+				continue
+			}
+
+			tokens = append(tokens, exprToken{kind: "punct", value: string(c)})
+			i++
+		default:
+			matched := false
+
+			for _, op := range operators {
+				if !isNameStart(op[0]) && strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, exprToken{kind: "op", value: op})
+					i += len(op)
+					matched = true
+
+					break
+				}
+			}
+
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q in %q", c, src)
+			}
+		}
+	}
+
+	return append(tokens, exprToken{kind: "eof"}), nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isNameRune(c byte) bool {
+	return isNameStart(c) || c >= '0' && c <= '9'
+}
+
+var binaryPrecedence = map[string]int{
+	"or": 10, "and": 15, "b-or": 16, "b-and": 18,
+	"==": 20, "!=": 20, "<": 20, ">": 20, "<=": 20, ">=": 20, "in": 20, "not in": 20, "matches": 20, "starts with": 20, "ends with": 20,
+	"..": 25, "+": 30, "-": 30, "~": 40, "*": 60, "/": 60, "//": 60, "%": 60, "is": 100, "is not": 100, "**": 200, "??": 300,
+}
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func parseExpression(src string) (expr, error) {
+	tokens, err := tokenizeExpression(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+
+	e, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != "eof" {
+		return nil, fmt.Errorf("unexpected %q in %q", p.peek().value, src)
+	}
+
+	return e, nil
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	token := p.tokens[p.pos]
+	if token.kind != "eof" {
+		p.pos++
+	}
+
+	return token
+}
+
+func (p *exprParser) expect(value string) error {
+	if token := p.next(); token.value != value || token.kind == "string" {
+		return fmt.Errorf("expected %q, got %q", value, token.value)
+	}
+
+	return nil
+}
+
+func (p *exprParser) isPunct(value string) bool {
+	token := p.peek()
+	return token.kind == "punct" && token.value == value
+}
+
+func (p *exprParser) parseConditional() (expr, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	token := p.peek()
+	if token.kind != "op" {
+		return cond, nil
+	}
+
+	switch token.value {
+	case "?:":
+		p.next()
+
+		orElse, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+
+		return conditionalExpr{cond: cond, then: cond, orElse: orElse}, nil
+	case "?":
+		p.next()
+
+		then, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+
+		var orElse expr = literalExpr{value: ""}
+
+		if next := p.peek(); next.kind == "op" && next.value == ":" {
+			p.next()
+
+			if orElse, err = p.parseConditional(); err != nil {
+				return nil, err
+			}
+		}
+
+		return conditionalExpr{cond: cond, then: then, orElse: orElse}, nil
+	}
+
+	return cond, nil
+}
+
+func (p *exprParser) parseBinary(minPrecedence int) (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		token := p.peek()
+		precedence, ok := binaryPrecedence[token.value]
+
+		if token.kind != "op" || !ok || precedence < minPrecedence {
+			return left, nil
+		}
+
+		p.next()
+
+		if token.value == "is" || token.value == "is not" {
+			test, err := p.parseTest(left, token.value == "is not")
+			if err != nil {
+				return nil, err
+			}
+
+			left = test
+
+			continue
+		}
+
+		// ** is right associative, everything else left associative
+		nextMin := precedence + 1
+		if token.value == "**" || token.value == "??" {
+			nextMin = precedence
+		}
+
+		right, err := p.parseBinary(nextMin)
+		if err != nil {
+			return nil, err
+		}
+
+		left = binaryExpr{op: token.value, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseTest(target expr, negate bool) (expr, error) {
+	if token := p.peek(); token.kind == "op" && token.value == "not" {
+		p.next()
+
+		negate = !negate
+	}
+
+	nameToken := p.next()
+	if nameToken.kind != "name" {
+		return nil, fmt.Errorf("expected test name after is, got %q", nameToken.value)
+	}
+
+	name := nameToken.value
+
+	// two word tests
+	if next := p.peek(); next.kind == "name" && (name == "same" && next.value == "as" || name == "divisible" && next.value == "by") {
+		p.next()
+
+		name += " " + next.value
+	}
+
+	test := testExpr{target: target, name: name, negate: negate}
+
+	if p.isPunct("(") {
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+
+		test.args = args
+	}
+
+	return test, nil
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	token := p.peek()
+
+	if token.kind == "op" && (token.value == "not" || token.value == "-" || token.value == "+") {
+		p.next()
+
+		// not binds weaker than comparisons, minus stronger than everything
+		precedence := 500
+		if token.value == "not" {
+			precedence = 50
+		}
+
+		operand, err := p.parseBinary(precedence)
+		if err != nil {
+			return nil, err
+		}
+
+		return unaryExpr{op: token.value, operand: operand}, nil
+	}
+
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (expr, error) {
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.isPunct("."):
+			p.next()
+
+			token := p.next()
+			if token.kind != "name" && token.kind != "number" {
+				return nil, fmt.Errorf("expected attribute name, got %q", token.value)
+			}
+
+			if p.isPunct("(") {
+				// method calls like entity.get('foo') read the attribute
+				args, err := p.parseArguments()
+				if err != nil {
+					return nil, err
+				}
+
+				if len(args) == 1 && (token.value == "get" || token.value == "getExtension") {
+					e = attrExpr{target: e, attr: args[0], path: pathOf(e) + "." + token.value + "()"}
+					continue
+				}
+			}
+
+			e = attrExpr{target: e, attr: literalExpr{value: token.value}, path: pathOf(e) + "." + token.value}
+		case p.isPunct("["):
+			p.next()
+
+			index, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+
+			e = attrExpr{target: e, attr: index, path: pathOf(e) + "[]"}
+		case p.isPunct("|"):
+			p.next()
+
+			name := p.next()
+			if name.kind != "name" {
+				return nil, fmt.Errorf("expected filter name, got %q", name.value)
+			}
+
+			filterName := name.value
+
+			// filters of extensions like u.wordwrap
+			for p.isPunct(".") {
+				p.next()
+				filterName += "." + p.next().value
+			}
+
+			filter := filterExpr{target: e, name: filterName}
+
+			if p.isPunct("(") {
+				args, err := p.parseArguments()
+				if err != nil {
+					return nil, err
+				}
+
+				filter.args = args
+			}
+
+			e = filter
+		default:
+			return e, nil
+		}
+	}
+}
+
+func pathOf(e expr) string {
+	switch v := e.(type) {
+	case nameExpr:
+		return v.name
+	case attrExpr:
+		return v.path
+	}
+
+	return "(expression)"
+}
+
+func (p *exprParser) parseArguments() ([]expr, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var args []expr
+
+	for !p.isPunct(")") {
+		// named arguments are passed positionally
+		if p.peek().kind == "name" && p.tokens[p.pos+1].kind == "op" && p.tokens[p.pos+1].value == ":" {
+			p.pos += 2
+		}
+
+		arg, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+
+		if !p.isPunct(",") {
+			break
+		}
+
+		p.next()
+	}
+
+	return args, p.expect(")")
+}
+
+func (p *exprParser) parsePrimary() (expr, error) {
+	token := p.next()
+
+	switch token.kind {
+	case "string":
+		return literalExpr{value: token.value}, nil
+	case "number":
+		value, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		return literalExpr{value: value}, nil
+	case "name":
+		switch strings.ToLower(token.value) {
+		case "true":
+			return literalExpr{value: true}, nil
+		case "false":
+			return literalExpr{value: false}, nil
+		case "null", "none":
+			return literalExpr{value: nil}, nil
+		}
+
+		if p.isPunct("(") {
+			args, err := p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+
+			return callExpr{name: token.value, args: args}, nil
+		}
+
+		return nameExpr{name: token.value}, nil
+	case "punct":
+		switch token.value {
+		case "(":
+			e, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+
+			return e, p.expect(")")
+		case "[":
+			var items []expr
+
+			for !p.isPunct("]") {
+				item, err := p.parseConditional()
+				if err != nil {
+					return nil, err
+				}
+
+				items = append(items, item)
+
+				if !p.isPunct(",") {
+					break
+				}
+
+				p.next()
+			}
+
+			return arrayExpr{items: items}, p.expect("]")
+		case "{":
+			hash := hashExpr{}
+
+			for !p.isPunct("}") {
+				keyToken := p.next()
+
+				var key expr
+
+				switch keyToken.kind {
+				case "name", "string", "number":
+					key = literalExpr{value: keyToken.value}
+				case "punct":
+					if keyToken.value != "(" {
+						return nil, fmt.Errorf("unexpected %q in hash", keyToken.value)
+					}
+
+					k, err := p.parseConditional()
+					if err != nil {
+						return nil, err
+					}
+
+					if err := p.expect(")"); err != nil {
+						return nil, err
+					}
+
+					key = k
+				default:
+					return nil, fmt.Errorf("unexpected %q in hash", keyToken.value)
+				}
+
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+
+				value, err := p.parseConditional()
+				if err != nil {
+					return nil, err
+				}
+
+				hash.keys = append(hash.keys, key)
+				hash.values = append(hash.values, value)
+
+				if !p.isPunct(",") {
+					break
+				}
+
+				p.next()
+			}
+
+			return hash, p.expect("}")
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %q", token.value)
+}
+
+func (e literalExpr) eval(*renderer) (any, error) {
+	return e.value, nil
+}
+
+func (e nameExpr) eval(r *renderer) (any, error) {
+	value, ok := r.lookup(e.name)
+	if !ok {
+		r.warn("the variable %s is not defined in the fixture", e.name)
+	}
+
+	return value, nil
+}
+
+func (e attrExpr) eval(r *renderer) (any, error) {
+	target, err := e.target.eval(r)
+	if err != nil {
+		return nil, err
+	}
+
+	attr, err := e.attr.eval(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if target == nil {
+		return nil, nil
+	}
+
+	value, ok := attribute(target, attr)
+	if !ok {
+		r.warn("the variable %s is not defined in the fixture", e.path)
+	}
+
+	return value, nil
+}
+
+// attribute reads a map key or list index. Getter style names like getName
+// fall back to the property.
+func attribute(target, attr any) (any, bool) {
+	switch t := target.(type) {
+	case map[string]any:
+		key := toString(attr)
+
+		if value, ok := t[key]; ok {
+			return value, true
+		}
+
+		for _, prefix := range []string{"get", "is", "has"} {
+			if name, ok := strings.CutPrefix(key, prefix); ok && name != "" {
+				property := strings.ToLower(name[:1]) + name[1:]
+				if value, ok := t[property]; ok {
+					return value, true
+				}
+			}
+		}
+
+		if key == "count" {
+			return float64(len(t)), true
+		}
+	case []any:
+		if key := toString(attr); key == "count" || key == "length" {
+			return float64(len(t)), true
+		}
+
+		index, ok := toNumber(attr)
+		if ok && index >= 0 && int(index) < len(t) {
+			return t[int(index)], true
+		}
+	}
+
+	return nil, false
+}
+
+func (e arrayExpr) eval(r *renderer) (any, error) {
+	items := make([]any, 0, len(e.items))
+
+	for _, item := range e.items {
+		value, err := item.eval(r)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, value)
+	}
+
+	return items, nil
+}
+
+func (e hashExpr) eval(r *renderer) (any, error) {
+	hash := make(map[string]any, len(e.keys))
+
+	for i := range e.keys {
+		key, err := e.keys[i].eval(r)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := e.values[i].eval(r)
+		if err != nil {
+			return nil, err
+		}
+
+		hash[toString(key)] = value
+	}
+
+	return hash, nil
+}
+
+func (e unaryExpr) eval(r *renderer) (any, error) {
+	value, err := e.operand.eval(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "not":
+		return !truthy(value), nil
+	case "-":
+		number, _ := toNumber(value)
+		return -number, nil
+	}
+
+	number, _ := toNumber(value)
+
+	return number, nil
+}
+
+func (e conditionalExpr) eval(r *renderer) (any, error) {
+	cond, err := e.cond.eval(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if truthy(cond) {
+		return e.then.eval(r)
+	}
+
+	return e.orElse.eval(r)
+}
+
+func (e binaryExpr) eval(r *renderer) (any, error) {
+	left, err := e.left.eval(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// short circuit operators
+	switch e.op {
+	case "and":
+		if !truthy(left) {
+			return false, nil
+		}
+
+		right, err := e.right.eval(r)
+
+		return truthy(right), err
+	case "or":
+		if truthy(left) {
+			return true, nil
+		}
+
+		right, err := e.right.eval(r)
+
+		return truthy(right), err
+	case "??":
+		if left != nil {
+			return left, nil
+		}
+
+		return e.right.eval(r)
+	}
+
+	right, err := e.right.eval(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "==":
+		return looseEquals(left, right), nil
+	case "!=":
+		return !looseEquals(left, right), nil
+	case "<", ">", "<=", ">=":
+		return compare(e.op, left, right), nil
+	case "in":
+		return contains(right, left), nil
+	case "not in":
+		return !contains(right, left), nil
+	case "starts with":
+		return strings.HasPrefix(toString(left), toString(right)), nil
+	case "ends with":
+		return strings.HasSuffix(toString(left), toString(right)), nil
+	case "matches":
+		return false, nil
+	case "~":
+		return toString(left) + toString(right), nil
+	case "..":
+		from, _ := toNumber(left)
+		to, _ := toNumber(right)
+
+		return numberRange(from, to), nil
+	}
+
+	a, _ := toNumber(left)
+	b, _ := toNumber(right)
+
+	switch e.op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+
+		return a / b, nil
+	case "//":
+		if b == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+
+		return math.Floor(a / b), nil
+	case "%":
+		if b == 0 {
+			return nil, fmt.Errorf("modulo by zero")
+		}
+
+		return math.Mod(a, b), nil
+	case "**":
+		return math.Pow(a, b), nil
+	case "b-and":
+		return float64(int64(a) & int64(b)), nil
+	case "b-or":
+		return float64(int64(a) | int64(b)), nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator %s", e.op)
+}
+
+func numberRange(from, to float64) []any {
+	var items []any
+
+	if from <= to {
+		for i := from; i <= to; i++ {
+			items = append(items, i)
+		}
+	} else {
+		for i := from; i >= to; i-- {
+			items = append(items, i)
+		}
+	}
+
+	return items
+}
+
+func (e testExpr) eval(r *renderer) (any, error) {
+	var result bool
+
+	if e.name == "defined" {
+		result = isDefined(r, e.target)
+	} else {
+		value, err := e.target.eval(r)
+		if err != nil {
+			return nil, err
+		}
+
+		args := make([]any, 0, len(e.args))
+
+		for _, arg := range e.args {
+			v, err := arg.eval(r)
+			if err != nil {
+				return nil, err
+			}
+
+			args = append(args, v)
+		}
+
+		switch e.name {
+		case "empty":
+			result = isEmpty(value)
+		case "null", "none":
+			result = value == nil
+		case "iterable":
+			switch value.(type) {
+			case []any, map[string]any:
+				result = true
+			}
+		case "even", "odd":
+			number, _ := toNumber(value)
+			result = int64(number)%2 == 0
+
+			if e.name == "odd" {
+				result = !result
+			}
+		case "same as":
+			result = len(args) == 1 && looseEquals(value, args[0])
+		case "divisible by":
+			number, _ := toNumber(value)
+			divisor := float64(0)
+
+			if len(args) == 1 {
+				divisor, _ = toNumber(args[0])
+			}
+
+			result = divisor != 0 && math.Mod(number, divisor) == 0
+		default:
+			return nil, fmt.Errorf("unknown test %s", e.name)
+		}
+	}
+
+	if e.negate {
+		return !result, nil
+	}
+
+	return result, nil
+}
+
+// isDefined evaluates the target without reporting it as missing.
+func isDefined(r *renderer, target expr) bool {
+	switch t := target.(type) {
+	case nameExpr:
+		_, ok := r.lookup(t.name)
+		return ok
+	case attrExpr:
+		if !isDefined(r, t.target) {
+			return false
+		}
+
+		parent, err := t.target.eval(r)
+		if err != nil || parent == nil {
+			return false
+		}
+
+		attr, err := t.attr.eval(r)
+		if err != nil {
+			return false
+		}
+
+		_, ok := attribute(parent, attr)
+
+		return ok
+	}
+
+	return true
+}
+
+func (e filterExpr) eval(r *renderer) (any, error) {
+	var value any
+
+	// default must not report missing variables
+	if e.name == "default" {
+		if isDefined(r, e.target) {
+			v, err := e.target.eval(r)
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+		}
+	} else {
+		v, err := e.target.eval(r)
+		if err != nil {
+			return nil, err
+		}
+
+		value = v
+	}
+
+	args := make([]any, 0, len(e.args))
+
+	for _, arg := range e.args {
+		v, err := arg.eval(r)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, v)
+	}
+
+	filter, ok := twigFilters[e.name]
+	if !ok {
+		r.warn("the filter %s is not supported by the preview and returns its input", e.name)
+		return value, nil
+	}
+
+	return filter(value, args)
+}
+
+func (e callExpr) eval(r *renderer) (any, error) {
+	args := make([]any, 0, len(e.args))
+
+	for _, arg := range e.args {
+		v, err := arg.eval(r)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, v)
+	}
+
+	function, ok := twigFunctions[e.name]
+	if !ok {
+		r.warn("the function %s() is not supported by the preview", e.name)
+		return nil, nil
+	}
+
+	return function(args)
+}
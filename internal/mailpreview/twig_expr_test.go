@@ -0,0 +1,149 @@
+package mailpreview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwigExpressionPrecedence(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"multiplication before addition", "{{ 1 + 2 * 3 }}", "7"},
+		{"left associative", "{{ 10 - 2 - 3 }} {{ 16 / 4 / 2 }}", "5 2"},
+		{"power is right associative", "{{ 2 ** 3 ** 2 }}", "512"},
+		{"unary minus binds stronger than power", "{{ -2 ** 2 }}", "4"},
+		{"floor division and modulo", "{{ 7 // 2 }} {{ 7 % 3 }} {{ -7 // 2 }}", "3 1 -4"},
+		{"concat binds stronger than addition", "{{ 1 + 2 ~ 3 }}", "24"},
+		{"arithmetic before comparison", "{{ 1 + 1 == 2 ? 'y' : 'n' }}", "y"},
+		{"not binds stronger than and", "{{ not false and false ? 'y' : 'n' }}", "n"},
+		{"not binds weaker than arithmetic", "{{ not 1 - 1 ? 'y' : 'n' }}", "y"},
+		{"and binds stronger than or", "{{ true or false and false ? 'y' : 'n' }} {{ false and true or true ? 'y' : 'n' }}", "y y"},
+		{"filters bind stronger than unary minus", "{{ -5|abs }} {{ (-5)|abs }}", "-5 5"},
+		{"filters bind stronger than range", "{{ (1..3)|join }} {{ 1..3|length }}", "123 Array"},
+		{"parentheses", "{{ (1 + 2) * 3 }}", "9"},
+		{"null coalescing chains", "{{ null ?? missing ?? 'a' ?? 'b' }}", "a"},
+		{"nested ternary", "{{ false ? 'a' : true ? 'b' : 'c' }}", "b"},
+		{"ternary without else", "{{ true ? 'yes' }}|{{ false ? 'yes' }}", "yes|"},
+		{"short ternary", "{{ '' ?: 'empty' }} {{ 'x' ?: 'empty' }}", "empty x"},
+		{"bitwise", "{{ 5 b-and 3 }} {{ 5 b-or 3 }}", "1 7"},
+		{"tests bind stronger than and", "{{ 2 is even and 3 is odd ? 'y' : 'n' }}", "y"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, _, err := renderTemplate(tc.template, nil, false)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestTwigExpressionOperators(t *testing.T) {
+	vars := map[string]any{
+		"list":   []any{"a", "b"},
+		"hash":   map[string]any{"x": "1", "y": "2"},
+		"entity": map[string]any{"name": "Shirt", "active": true, "items": []any{}},
+	}
+
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"literals", "{{ 1.5 }}|{{ true }}|{{ false }}|{{ null }}|{{ none }}|{{ [1] }}", "1.5|1||||Array"},
+		{"string escapes", `{{ 'it\'s' }} {{ "say \"hi\"" }}`, `it's say "hi"`},
+		{"equality", "{{ '10' == 10 ? 1 : 0 }}{{ '10' == '1e1' ? 1 : 0 }}{{ 1 != 2 ? 1 : 0 }}{{ null == '' ? 1 : 0 }}", "1011"},
+		{"comparison", "{{ 'abc' < 'abd' ? 1 : 0 }}{{ 2 <= 2 ? 1 : 0 }}{{ 3 >= 4 ? 1 : 0 }}{{ 10 > 9 ? 1 : 0 }}{{ '10' > '9' ? 1 : 0 }}", "11011"},
+		{"in", "{{ 2 in [1, 2] ? 1 : 0 }}{{ '1' in hash ? 1 : 0 }}{{ 'c' not in list ? 1 : 0 }}{{ 'x' in 5 ? 1 : 0 }}", "1110"},
+		{"starts and ends with", "{{ 'abc' starts with 'a' ? 1 : 0 }}{{ 'abc' ends with 'bc' ? 1 : 0 }}{{ 'abc' starts with 'b' ? 1 : 0 }}", "110"},
+		{"unary plus", "{{ +'3' + 1 }}", "4"},
+		{"list index and count", "{{ list[1] }} {{ list.count }} {{ list.length }} {{ hash.count }}", "b 2 2 2"},
+		{"dynamic attribute", "{% set key = 'y' %}{{ hash[key] }}", "2"},
+		{"getters", "{{ entity.getName() }} {{ entity.isActive ? 'active' }} {{ entity.hasItems is empty ? 'none' }}", "Shirt active none"},
+		{"get method", "{{ entity.get('name') }}", "Shirt"},
+		{"hash keys", "{{ {('a' ~ 'b'): 1}.ab }} {{ {1: 'one'}[1] }} {{ {'q': 2}.q }}", "1 one 2"},
+		{"named arguments", "{{ 'abc'|slice(start: 1) }}", "bc"},
+		{"attribute of null", "[{{ null.foo }}]", "[]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, _, err := renderTemplate(tc.template, vars, false)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestTwigTests(t *testing.T) {
+	vars := map[string]any{
+		"order": map[string]any{"items": []any{}, "customer": map[string]any{"name": "Max"}},
+	}
+
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"even and odd", "{{ 4 is even ? 1 : 0 }}{{ 3 is odd ? 1 : 0 }}{{ 5 is not even ? 1 : 0 }}", "111"},
+		{"divisible by", "{{ 9 is divisible by(3) ? 1 : 0 }}{{ 9 is divisible by(2) ? 1 : 0 }}{{ 9 is divisible by(0) ? 1 : 0 }}", "100"},
+		{"null", "{{ null is null ? 1 : 0 }}{{ order is none ? 1 : 0 }}{{ order is not null ? 1 : 0 }}", "101"},
+		{"empty", "{{ order.items is empty ? 1 : 0 }}{{ order.customer is empty ? 1 : 0 }}{{ '' is empty ? 1 : 0 }}", "101"},
+		{"iterable", "{{ order.items is iterable ? 1 : 0 }}{{ order is iterable ? 1 : 0 }}{{ 'x' is iterable ? 1 : 0 }}", "110"},
+		{"same as", "{{ 1 is same as(1) ? 1 : 0 }}{{ 1 is same as(2) ? 1 : 0 }}", "10"},
+		{"defined", "{{ order.customer.name is defined ? 1 : 0 }}{{ order.customer.email is defined ? 1 : 0 }}{{ order.missing.deep is defined ? 1 : 0 }}{{ missing is not defined ? 1 : 0 }}", "1001"},
+		{"double negation", "{{ 4 is not not even ? 1 : 0 }}", "1"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, warnings, err := renderTemplate(tc.template, vars, false)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+			assert.Empty(t, warnings, "the defined test must not report missing variables")
+		})
+	}
+
+	_, _, err := renderTemplate("{{ 1 is prime }}", nil, false)
+	assert.EqualError(t, err, "line 1: unknown test prime")
+}
+
+func TestTwigExpressionErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"division by zero", "a\n\n{{ 1 / 0 }}", "line 3: division by zero"},
+		{"floor division by zero", "{{ 1 // 0 }}", "line 1: division by zero"},
+		{"modulo by zero", "{{ 1 % 0 }}", "line 1: modulo by zero"},
+		{"error in if condition", "{% if 1 / 0 %}x{% endif %}", "division by zero"},
+		{"error in for iterable", "{% for i in 1 / 0 %}x{% endfor %}", "division by zero"},
+		{"error in set", "{% set x = 1 % 0 %}", "modulo by zero"},
+		{"error in filter argument", "{{ 'x'|default(1 / 0) }}", "line 1: division by zero"},
+		{"error in function argument", "{{ max(1 / 0) }}", "line 1: division by zero"},
+		{"missing operand", "{{ 1 + }}", `line 1: unexpected ""`},
+		{"two values", "{{ foo bar }}", `line 1: unexpected "bar" in "foo bar"`},
+		{"unexpected character", "{{ 1 @ 2 }}", `line 1: unexpected character '@' in "1 @ 2"`},
+		{"unclosed string", `{{ 'abc }}`, "line 1: unclosed {{"},
+		{"missing filter name", "{{ 'a'| }}", `line 1: expected filter name, got ""`},
+		{"missing attribute name", "{{ a. }}", `line 1: expected attribute name, got ""`},
+		{"unclosed list", "{{ [1, 2 }}", `line 1: expected "]", got ""`},
+		{"unclosed parenthesis", "{{ (1 + 2 }}", `line 1: expected ")", got ""`},
+		{"missing hash colon", "{{ {a 1} }}", `line 1: expected ":", got "1"`},
+		{"invalid hash key", "{{ {[1]: 2} }}", `line 1: unexpected "[" in hash`},
+		{"missing test name", "{{ x is 5 }}", `line 1: expected test name after is, got "5"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := renderTemplate(tc.template, nil, false)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,795 @@
+package mailpreview
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type twigFilter func(value any, args []any) (any, error)
+
+var twigFilters map[string]twigFilter
+
+var twigFunctions map[string]func(args []any) (any, error)
+
+// previewNow is the time "now" renders as, so previews do not change on every
+// reload.
+var previewNow = time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+var stripTagsRegExp = regexp.MustCompile(`<[^>]*>`)
+
+func init() {
+	twigFilters = map[string]twigFilter{
+		"raw": func(value any, _ []any) (any, error) {
+			return safeString(toString(value)), nil
+		},
+		"sw_sanitize": func(value any, _ []any) (any, error) {
+			return safeString(toString(value)), nil
+		},
+		"escape": escapeFilter,
+		"e":      escapeFilter,
+		"upper": func(value any, _ []any) (any, error) {
+			return strings.ToUpper(toString(value)), nil
+		},
+		"lower": func(value any, _ []any) (any, error) {
+			return strings.ToLower(toString(value)), nil
+		},
+		"capitalize": func(value any, _ []any) (any, error) {
+			s := strings.ToLower(toString(value))
+			if s == "" {
+				return s, nil
+			}
+
+			runes := []rune(s)
+			runes[0] = unicode.ToUpper(runes[0])
+
+			return string(runes), nil
+		},
+		"title": func(value any, _ []any) (any, error) {
+			words := strings.Fields(strings.ToLower(toString(value)))
+			for i, word := range words {
+				runes := []rune(word)
+				runes[0] = unicode.ToUpper(runes[0])
+				words[i] = string(runes)
+			}
+
+			return strings.Join(words, " "), nil
+		},
+		"trim": func(value any, args []any) (any, error) {
+			if len(args) > 0 {
+				return strings.Trim(toString(value), toString(args[0])), nil
+			}
+
+			return strings.TrimSpace(toString(value)), nil
+		},
+		"length": func(value any, _ []any) (any, error) {
+			switch v := value.(type) {
+			case []any:
+				return float64(len(v)), nil
+			case map[string]any:
+				return float64(len(v)), nil
+			case nil:
+				return float64(0), nil
+			}
+
+			return float64(len([]rune(toString(value)))), nil
+		},
+		"default": func(value any, args []any) (any, error) {
+			if isEmpty(value) {
+				if len(args) > 0 {
+					return args[0], nil
+				}
+
+				return "", nil
+			}
+
+			return value, nil
+		},
+		"join": func(value any, args []any) (any, error) {
+			_, values := iterate(value)
+
+			parts := make([]string, 0, len(values))
+			for _, v := range values {
+				parts = append(parts, toString(v))
+			}
+
+			separator := ""
+			if len(args) > 0 {
+				separator = toString(args[0])
+			}
+
+			return strings.Join(parts, separator), nil
+		},
+		"split": func(value any, args []any) (any, error) {
+			separator := ""
+			if len(args) > 0 {
+				separator = toString(args[0])
+			}
+
+			parts := strings.Split(toString(value), separator)
+			items := make([]any, len(parts))
+
+			for i, part := range parts {
+				items[i] = part
+			}
+
+			return items, nil
+		},
+		"first": func(value any, _ []any) (any, error) {
+			if s, ok := value.(string); ok {
+				if s == "" {
+					return "", nil
+				}
+
+				return string([]rune(s)[0]), nil
+			}
+
+			_, values := iterate(value)
+			if len(values) == 0 {
+				return nil, nil
+			}
+
+			return values[0], nil
+		},
+		"last": func(value any, _ []any) (any, error) {
+			if s, ok := value.(string); ok {
+				if s == "" {
+					return "", nil
+				}
+
+				runes := []rune(s)
+
+				return string(runes[len(runes)-1]), nil
+			}
+
+			_, values := iterate(value)
+			if len(values) == 0 {
+				return nil, nil
+			}
+
+			return values[len(values)-1], nil
+		},
+		"keys": func(value any, _ []any) (any, error) {
+			keys, _ := iterate(value)
+			return keys, nil
+		},
+		"reverse": func(value any, _ []any) (any, error) {
+			if s, ok := value.(string); ok {
+				runes := []rune(s)
+				slices.Reverse(runes)
+
+				return string(runes), nil
+			}
+
+			_, values := iterate(value)
+			reversed := slices.Clone(values)
+			slices.Reverse(reversed)
+
+			return reversed, nil
+		},
+		"sort": func(value any, _ []any) (any, error) {
+			_, values := iterate(value)
+			sorted := slices.Clone(values)
+			slices.SortStableFunc(sorted, func(a, b any) int {
+				switch {
+				case compare("<", a, b):
+					return -1
+				case compare(">", a, b):
+					return 1
+				}
+
+				return 0
+			})
+
+			return sorted, nil
+		},
+		"merge": func(value any, args []any) (any, error) {
+			if len(args) == 0 {
+				return value, nil
+			}
+
+			if m, ok := value.(map[string]any); ok {
+				merged := make(map[string]any, len(m))
+				for k, v := range m {
+					merged[k] = v
+				}
+
+				if other, ok := args[0].(map[string]any); ok {
+					for k, v := range other {
+						merged[k] = v
+					}
+				}
+
+				return merged, nil
+			}
+
+			_, values := iterate(value)
+			_, other := iterate(args[0])
+
+			return append(slices.Clone(values), other...), nil
+		},
+		"slice": func(value any, args []any) (any, error) {
+			start, length := 0, -1
+
+			if len(args) > 0 {
+				n, _ := toNumber(args[0])
+				start = int(n)
+			}
+
+			if len(args) > 1 && args[1] != nil {
+				n, _ := toNumber(args[1])
+				length = int(n)
+			}
+
+			if s, ok := value.(string); ok {
+				runes := []rune(s)
+				from, to := sliceBounds(len(runes), start, length)
+
+				return string(runes[from:to]), nil
+			}
+
+			_, values := iterate(value)
+			from, to := sliceBounds(len(values), start, length)
+
+			return values[from:to], nil
+		},
+		"replace": func(value any, args []any) (any, error) {
+			s := toString(value)
+
+			if len(args) > 0 {
+				if pairs, ok := args[0].(map[string]any); ok {
+					keys := make([]string, 0, len(pairs))
+					for key := range pairs {
+						keys = append(keys, key)
+					}
+
+					slices.Sort(keys)
+
+					for _, key := range keys {
+						s = strings.ReplaceAll(s, key, toString(pairs[key]))
+					}
+				}
+			}
+
+			return s, nil
+		},
+		"nl2br": func(value any, _ []any) (any, error) {
+			return safeString(strings.ReplaceAll(html.EscapeString(toString(value)), "\n", "<br />\n")), nil
+		},
+		"striptags": func(value any, _ []any) (any, error) {
+			return stripTagsRegExp.ReplaceAllString(toString(value), ""), nil
+		},
+		"abs": func(value any, _ []any) (any, error) {
+			n, _ := toNumber(value)
+			return math.Abs(n), nil
+		},
+		"round": func(value any, args []any) (any, error) {
+			n, _ := toNumber(value)
+			precision := 0.0
+
+			if len(args) > 0 {
+				precision, _ = toNumber(args[0])
+			}
+
+			factor := math.Pow(10, precision)
+
+			method := "common"
+			if len(args) > 1 {
+				method = toString(args[1])
+			}
+
+			switch method {
+			case "ceil":
+				return math.Ceil(n*factor) / factor, nil
+			case "floor":
+				return math.Floor(n*factor) / factor, nil
+			}
+
+			return math.Round(n*factor) / factor, nil
+		},
+		"number_format": func(value any, args []any) (any, error) {
+			n, _ := toNumber(value)
+			decimals, point, thousands := 0, ".", ","
+
+			if len(args) > 0 {
+				d, _ := toNumber(args[0])
+				decimals = int(d)
+			}
+
+			if len(args) > 1 {
+				point = toString(args[1])
+			}
+
+			if len(args) > 2 {
+				thousands = toString(args[2])
+			}
+
+			return formatNumber(n, decimals, point, thousands), nil
+		},
+		"currency": func(value any, args []any) (any, error) {
+			n, _ := toNumber(value)
+
+			symbol := "€"
+			if len(args) > 0 && toString(args[0]) != "" {
+				symbol = currencySymbol(toString(args[0]))
+			}
+
+			return formatNumber(n, 2, ",", ".") + " " + symbol, nil
+		},
+		"date":            dateFilter,
+		"format_date":     dateFilter,
+		"format_datetime": dateFilter,
+		"format_time":     dateFilter,
+		"json_encode": func(value any, _ []any) (any, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+		"url_encode": func(value any, _ []any) (any, error) {
+			return strings.ReplaceAll(toString(value), " ", "%20"), nil
+		},
+		"trans": func(value any, _ []any) (any, error) {
+			return value, nil
+		},
+		"u.wordwrap": func(value any, args []any) (any, error) {
+			width := 75
+			if len(args) > 0 {
+				w, _ := toNumber(args[0])
+				width = int(w)
+			}
+
+			return wordWrap(toString(value), width), nil
+		},
+		"u.truncate": func(value any, args []any) (any, error) {
+			s := []rune(toString(value))
+			length := len(s)
+
+			if len(args) > 0 {
+				l, _ := toNumber(args[0])
+				length = int(l)
+			}
+
+			if length >= len(s) {
+				return string(s), nil
+			}
+
+			suffix := ""
+			if len(args) > 1 {
+				suffix = toString(args[1])
+			}
+
+			return string(s[:max(length, 0)]) + suffix, nil
+		},
+	}
+
+	twigFunctions = map[string]func(args []any) (any, error){
+		"range": func(args []any) (any, error) {
+			if len(args) < 2 {
+				return nil, fmt.Errorf("range() requires two arguments")
+			}
+
+			from, _ := toNumber(args[0])
+			to, _ := toNumber(args[1])
+
+			return numberRange(from, to), nil
+		},
+		"date": func(args []any) (any, error) {
+			if len(args) == 0 {
+				return previewNow.Format(time.RFC3339), nil
+			}
+
+			return args[0], nil
+		},
+		"max": func(args []any) (any, error) {
+			return extremum(args, ">"), nil
+		},
+		"min": func(args []any) (any, error) {
+			return extremum(args, "<"), nil
+		},
+		// routes of the storefront, the preview links nowhere
+		"url":     routeFunction,
+		"path":    routeFunction,
+		"seoUrl":  routeFunction,
+		"rawUrl":  routeFunction,
+		"asset":   routeFunction,
+		"sw_icon": func([]any) (any, error) { return "", nil },
+	}
+}
+
+func escapeFilter(value any, _ []any) (any, error) {
+	if safe, ok := value.(safeString); ok {
+		return safe, nil
+	}
+
+	return safeString(html.EscapeString(toString(value))), nil
+}
+
+func routeFunction(args []any) (any, error) {
+	if len(args) == 0 {
+		return "#", nil
+	}
+
+	return "#" + toString(args[0]), nil
+}
+
+func extremum(args []any, op string) any {
+	if len(args) == 1 {
+		_, args = iterate(args[0])
+	}
+
+	var result any
+
+	for i, arg := range args {
+		if i == 0 || compare(op, arg, result) {
+			result = arg
+		}
+	}
+
+	return result
+}
+
+func sliceBounds(length, start, count int) (int, int) {
+	if start < 0 {
+		start = max(length+start, 0)
+	}
+
+	start = min(start, length)
+
+	end := length
+	if count >= 0 {
+		end = min(start+count, length)
+	}
+
+	return start, end
+}
+
+func currencySymbol(isoCode string) string {
+	switch strings.ToUpper(isoCode) {
+	case "EUR":
+		return "€"
+	case "USD":
+		return "$"
+	case "GBP":
+		return "£"
+	case "CHF":
+		return "CHF"
+	case "JPY":
+		return "¥"
+	}
+
+	return isoCode
+}
+
+func formatNumber(n float64, decimals int, point, thousands string) string {
+	formatted := strconv.FormatFloat(math.Abs(n), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	var grouped strings.Builder
+
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteString(thousands)
+		}
+
+		grouped.WriteRune(digit)
+	}
+
+	result := grouped.String()
+	if fraction != "" {
+		result += point + fraction
+	}
+
+	if n < 0 {
+		result = "-" + result
+	}
+
+	return result
+}
+
+func wordWrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	var lines []string
+
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case len([]rune(line))+1+len([]rune(word)) > width:
+				lines = append(lines, line)
+				line = word
+			default:
+				line += " " + word
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// dateFilter formats ISO dates and "now". The format is a PHP date format for
+// the date filter and ignored for the intl filters.
+func dateFilter(value any, args []any) (any, error) {
+	t := previewNow
+
+	if s := toString(value); s != "" && s != "now" {
+		parsed, err := parseDate(s)
+		if err != nil {
+			return s, nil
+		}
+
+		t = parsed
+	}
+
+	format := "d.m.Y H:i"
+	if len(args) > 0 {
+		if f := toString(args[0]); strings.ContainsAny(f, "dmYyHisjnDMlFG") && !slices.Contains([]string{"short", "medium", "long", "full", "none"}, f) {
+			format = f
+		}
+	}
+
+	return phpDate(t, format), nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000-07:00", "2006-01-02 15:04:05.000", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown date %s", s)
+}
+
+func phpDate(t time.Time, format string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		switch format[i] {
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'j':
+			fmt.Fprintf(&b, "%d", t.Day())
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		case 'n':
+			fmt.Fprintf(&b, "%d", int(t.Month()))
+		case 'Y':
+			fmt.Fprintf(&b, "%d", t.Year())
+		case 'y':
+			fmt.Fprintf(&b, "%02d", t.Year()%100)
+		case 'H':
+			fmt.Fprintf(&b, "%02d", t.Hour())
+		case 'G':
+			fmt.Fprintf(&b, "%d", t.Hour())
+		case 'i':
+			fmt.Fprintf(&b, "%02d", t.Minute())
+		case 's':
+			fmt.Fprintf(&b, "%02d", t.Second())
+		case 'D':
+			b.WriteString(t.Weekday().String()[:3])
+		case 'l':
+			b.WriteString(t.Weekday().String())
+		case 'M':
+			b.WriteString(t.Month().String()[:3])
+		case 'F':
+			b.WriteString(t.Month().String())
+		case '\\':
+			if i+1 < len(format) {
+				i++
+				b.WriteByte(format[i])
+			}
+		default:
+			b.WriteByte(format[i])
+		}
+	}
+
+	return b.String()
+}
+
+func toString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case safeString:
+		return string(v)
+	case bool:
+		if v {
+			return "1"
+		}
+
+		return ""
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case []any:
+		return "Array"
+	case map[string]any:
+		return "Array"
+	}
+
+	return fmt.Sprint(value)
+}
+
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+
+		return 0, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	case safeString:
+		n, err := strconv.ParseFloat(strings.TrimSpace(string(v)), 64)
+		return n, err == nil
+	}
+
+	return 0, false
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "0"
+	case safeString:
+		return v != "" && v != "0"
+	case float64:
+		return v != 0
+	case int:
+		return v != 0
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	}
+
+	return true
+}
+
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case safeString:
+		return v == ""
+	case bool:
+		return !v
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+func looseEquals(a, b any) bool {
+	if a == nil || b == nil {
+		return isEmpty(a) && isEmpty(b)
+	}
+
+	an, aNumber := toNumber(a)
+	bn, bNumber := toNumber(b)
+
+	_, aString := a.(string)
+	_, bString := b.(string)
+
+	if aNumber && bNumber && !(aString && bString) {
+		return an == bn
+	}
+
+	return toString(a) == toString(b)
+}
+
+func compare(op string, a, b any) bool {
+	an, aNumber := toNumber(a)
+	bn, bNumber := toNumber(b)
+
+	var result int
+
+	if aNumber && bNumber {
+		switch {
+		case an < bn:
+			result = -1
+		case an > bn:
+			result = 1
+		}
+	} else {
+		result = strings.Compare(toString(a), toString(b))
+	}
+
+	switch op {
+	case "<":
+		return result < 0
+	case ">":
+		return result > 0
+	case "<=":
+		return result <= 0
+	}
+
+	return result >= 0
+}
+
+func contains(haystack, needle any) bool {
+	switch h := haystack.(type) {
+	case string:
+		return strings.Contains(h, toString(needle))
+	case []any:
+		return slices.ContainsFunc(h, func(item any) bool {
+			return looseEquals(item, needle)
+		})
+	case map[string]any:
+		for _, item := range h {
+			if looseEquals(item, needle) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// iterate returns keys and values, maps sorted by key.
+func iterate(value any) ([]any, []any) {
+	switch v := value.(type) {
+	case []any:
+		keys := make([]any, len(v))
+		for i := range v {
+			keys[i] = float64(i)
+		}
+
+		return keys, v
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for key := range v {
+			names = append(names, key)
+		}
+
+		slices.Sort(names)
+
+		keys := make([]any, len(names))
+		values := make([]any, len(names))
+
+		for i, name := range names {
+			keys[i] = name
+			values[i] = v[name]
+		}
+
+		return keys, values
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,154 @@
+package mailpreview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwigFilters(t *testing.T) {
+	vars := map[string]any{
+		"text": "a\nb<",
+	}
+
+	cases := []struct {
+		name     string
+		template string
+		escape   bool
+		expected string
+	}{
+		{"raw", "{{ '<b>'|raw }}", true, "<b>"},
+		{"sw_sanitize", "{{ '<i>x</i>'|sw_sanitize }}", true, "<i>x</i>"},
+		{"escape", "{{ '<b>&'|escape }}", false, "&lt;b&gt;&amp;"},
+		{"escape is not applied twice", "{{ '<b>'|e }}", true, "&lt;b&gt;"},
+		{"escape keeps raw", "{{ '<b>'|raw|e }}", true, "<b>"},
+		{"upper and lower", "{{ 'aBc'|upper }} {{ 'aBc'|lower }}", false, "ABC abc"},
+		{"capitalize", "{{ 'hELLO world'|capitalize }}|{{ ''|capitalize }}", false, "Hello world|"},
+		{"title", "{{ 'hello  wORLD'|title }}", false, "Hello World"},
+		{"trim", "{{ '  x  '|trim }}|{{ '--x--'|trim('-') }}", false, "x|x"},
+		{"length", "{{ [1, 2, 3]|length }} {{ {a: 1}|length }} {{ null|length }} {{ 'äbc'|length }}", false, "3 1 0 3"},
+		{"default", "{{ ''|default('d') }} {{ 'v'|default('d') }} {{ 0|default('zero') }}|{{ missing|default }}", false, "d v 0|"},
+		{"join", "{{ [1, 'a', 2.5]|join(', ') }} {{ [1, 2]|join }} {{ {b: 2, a: 1}|join(',') }}", false, "1, a, 2.5 12 1,2"},
+		{"split", "{{ 'a,b,c'|split(',')|join('-') }} {{ 'abc'|split|length }}", false, "a-b-c 3"},
+		{"first", "{{ 'äbc'|first }} {{ [1, 2, 3]|first }} {{ []|first }}|{{ ''|first }}", false, "ä 1 |"},
+		{"last", "{{ 'abç'|last }} {{ [1, 2, 3]|last }} {{ []|last }}|{{ ''|last }}", false, "ç 3 |"},
+		{"keys", "{{ {b: 1, a: 2}|keys|join(',') }} {{ ['x', 'y']|keys|join(',') }}", false, "a,b 0,1"},
+		{"reverse", "{{ 'abc'|reverse }} {{ [1, 2, 3]|reverse|join }}", false, "cba 321"},
+		{"sort", "{{ [3, 1, 2]|sort|join }} {{ ['b', 'a']|sort|join }} {{ [10, 9]|sort|join(',') }}", false, "123 ab 9,10"},
+		{"merge lists", "{{ [1]|merge([2, 3])|join }} {{ [1]|merge|join }}", false, "123 1"},
+		{"merge hashes", "{{ {a: 1, b: 2}|merge({b: 3, c: 4})|join(',') }}", false, "1,3,4"},
+		{"slice string", "{{ 'abcdef'|slice(1, 2) }} {{ 'abcdef'|slice(-2) }} {{ 'abc'|slice(0, null) }}", false, "bc ef abc"},
+		{"slice list", "{{ [1, 2, 3, 4]|slice(1)|join }} {{ [1, 2, 3]|slice(5)|join }}|{{ [1, 2, 3]|slice(-2, 1)|join }}", false, "234 |2"},
+		{"replace", "{{ 'Hello %name%, %name%'|replace({'%name%': 'World'}) }} {{ 'x'|replace }}", false, "Hello World, World x"},
+		{"nl2br", "{{ text|nl2br }}", true, "a<br />\nb&lt;"},
+		{"striptags", "{{ '<p>Hi <b>there</b></p>'|striptags }}", false, "Hi there"},
+		{"abs", "{{ (-5)|abs }} {{ 2.5|abs }}", false, "5 2.5"},
+		{"round", "{{ 2.5|round }} {{ 3.14159|round(2) }} {{ 3.141|round(1, 'ceil') }} {{ 3.19|round(1, 'floor') }}", false, "3 3.14 3.2 3.1"},
+		{"number_format", "{{ 1234567.891|number_format(2, ',', '.') }} {{ 1234.4|number_format }} {{ (-1234.5)|number_format(1) }} {{ 12|number_format(2) }}", false, "1.234.567,89 1,234 -1,234.5 12.00"},
+		{"currency", "{{ 1234.5|currency('USD') }} {{ 5|currency }} {{ 5|currency('gbp') }} {{ 5|currency('XYZ') }}", false, "1.234,50 $ 5,00 € 5,00 £ 5,00 XYZ"},
+		{"date", "{{ '2024-03-01 08:05:09'|date('D, d.m.y H:i:s') }}", false, "Fri, 01.03.24 08:05:09"},
+		{"date names", "{{ '2024-03-01T07:00:00+00:00'|date('l M n G j F') }}", false, "Friday Mar 3 7 1 March"},
+		{"date default format", "{{ '2024-03-01'|date }}", false, "01.03.2024 00:00"},
+		{"date now", "{{ 'now'|date('Y-m-d') }} {{ null|date('Y') }}", false, "2024-01-15 2024"},
+		{"date escaped character", "{{ '2024-03-01'|date('j. F \\\\Y') }}", false, "1. March Y"},
+		{"date intl format", "{{ '2024-03-01'|format_date('medium') }} {{ '2024-03-01'|format_time('short') }}", false, "01.03.2024 00:00 01.03.2024 00:00"},
+		{"date unknown value", "{{ 'not a date'|date }}", false, "not a date"},
+		{"json_encode", "{{ {a: [1, 'x'], b: true}|json_encode }}", false, `{"a":[1,"x"],"b":true}`},
+		{"url_encode", "{{ 'a b'|url_encode }}", false, "a%20b"},
+		{"trans", "{{ 'mail.greeting'|trans }}", false, "mail.greeting"},
+		{"wordwrap", "{{ 'the quick brown fox'|u.wordwrap(10) }}", false, "the quick\nbrown fox"},
+		{"wordwrap keeps paragraphs", "{{ text|u.wordwrap }}", false, "a\nb<"},
+		{"truncate", "{{ 'Hello World'|u.truncate(5, '...') }} {{ 'Hi'|u.truncate(5) }} {{ 'Hello'|u.truncate(3) }}", false, "Hello... Hi Hel"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, _, err := renderTemplate(tc.template, vars, tc.escape)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestTwigFunctions(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"range", "{{ range(1, 3)|join }} {{ range(3, 1)|join }}", "123 321"},
+		{"date", "{{ date()|date('Y') }} {{ date('2023-05-06')|date('Y') }}", "2024 2023"},
+		{"max and min", "{{ max(1, 5, 3) }} {{ min([4, 2, 8]) }} {{ max('a', 'b') }}", "5 2 b"},
+		{"routes", "{{ path('frontend.home') }} {{ url() }} {{ seoUrl('frontend.detail.page', {productId: 1}) }} {{ asset('logo.png') }}", "#frontend.home # #frontend.detail.page #logo.png"},
+		{"sw_icon", "[{{ sw_icon('cart') }}]", "[]"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, _, err := renderTemplate(tc.template, nil, false)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+
+	_, _, err := renderTemplate("{{ range(1) }}", nil, false)
+	assert.EqualError(t, err, "line 1: range() requires two arguments")
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "0", formatNumber(0, 0, ".", ","))
+	assert.Equal(t, "999", formatNumber(999, 0, ".", ","))
+	assert.Equal(t, "1,000", formatNumber(1000, 0, ".", ","))
+	assert.Equal(t, "-12 345.60", formatNumber(-12345.6, 2, ".", " "))
+}
+
+func TestLooseEquals(t *testing.T) {
+	assert.True(t, looseEquals("10", float64(10)))
+	assert.True(t, looseEquals(nil, ""))
+	assert.True(t, looseEquals(nil, false))
+	assert.True(t, looseEquals(true, float64(1)))
+	assert.False(t, looseEquals("10", "1e1"), "two strings compare as strings")
+}
+
+func TestTruthy(t *testing.T) {
+	for _, value := range []any{true, "a", safeString("a"), float64(1), 1, []any{1}, map[string]any{"a": 1}, struct{}{}} {
+		assert.True(t, truthy(value), "%#v", value)
+	}
+
+	for _, value := range []any{nil, false, "", "0", safeString(""), safeString("0"), float64(0), 0, []any{}, map[string]any{}} {
+		assert.False(t, truthy(value), "%#v", value)
+	}
+}
+
+func TestToStringAndNumber(t *testing.T) {
+	assert.Equal(t, "", toString(nil))
+	assert.Equal(t, "1", toString(true))
+	assert.Equal(t, "", toString(false))
+	assert.Equal(t, "3", toString(float64(3)))
+	assert.Equal(t, "0.25", toString(0.25))
+	assert.Equal(t, "100000000000000000000", toString(1e20))
+	assert.Equal(t, "4", toString(4))
+	assert.Equal(t, "Array", toString(map[string]any{}))
+	assert.Equal(t, "x", toString(safeString("x")))
+
+	for value, expected := range map[any]float64{float64(2): 2, 3: 3, true: 1, false: 0, " 4.5 ": 4.5, safeString("6"): 6} {
+		n, ok := toNumber(value)
+		assert.True(t, ok, "%#v", value)
+		assert.Equal(t, expected, n)
+	}
+
+	for _, value := range []any{"abc", safeString("x"), nil, []any{}} {
+		_, ok := toNumber(value)
+		assert.False(t, ok, "%#v", value)
+	}
+}
+
+func TestCurrencySymbol(t *testing.T) {
+	assert.Equal(t, "€", currencySymbol("eur"))
+	assert.Equal(t, "$", currencySymbol("USD"))
+	assert.Equal(t, "£", currencySymbol("GBP"))
+	assert.Equal(t, "CHF", currencySymbol("CHF"))
+	assert.Equal(t, "¥", currencySymbol("JPY"))
+	assert.Equal(t, "SEK", currencySymbol("SEK"))
+}
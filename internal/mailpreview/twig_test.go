@@ -0,0 +1,173 @@
+package mailpreview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	vars := map[string]any{
+		"order": map[string]any{
+			"orderNumber":   "10001",
+			"amountTotal":   1234.5,
+			"orderDateTime": "2024-03-01T12:00:00.000+00:00",
+			"currency":      map[string]any{"isoCode": "EUR"},
+			"lineItems": []any{
+				map[string]any{"label": "Shirt", "quantity": float64(2)},
+				map[string]any{"label": "<b>Shoes</b>", "quantity": float64(1)},
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		template string
+		escape   bool
+		expected string
+	}{
+		{"print", "Order {{ order.orderNumber }}", false, "Order 10001"},
+		{"filters", "{{ order.amountTotal|currency(order.currency.isoCode) }} {{ 'abc'|upper }}", false, "1.234,50 € ABC"},
+		{"date", "{{ order.orderDateTime|format_datetime('d.m.Y') }}", false, "01.03.2024"},
+		{"for", "{% for item in order.lineItems %}{{ loop.index }}. {{ item.label }}{% if not loop.last %}, {% endif %}{% endfor %}", false, "1. Shirt, 2. <b>Shoes</b>"},
+		{"escape", "{{ order.lineItems[1].label }}|{{ order.lineItems[1].label|raw }}", true, "&lt;b&gt;Shoes&lt;/b&gt;|<b>Shoes</b>"},
+		{"if else", "{% if order.amountTotal > 2000 %}big{% elseif order.amountTotal > 1000 %}medium{% else %}small{% endif %}", false, "medium"},
+		{"set", "{% set total = 0 %}{% for item in order.lineItems %}{% set total = total + item.quantity %}{% endfor %}{{ total }}", false, "3"},
+		{"set block", "{% set greeting %}Hello {{ 'World' }}{% endset %}{{ greeting }}", true, "Hello World"},
+		{"default", "{{ order.missing|default('n/a') }} {{ missing ?? 'fallback' }}", false, "n/a fallback"},
+		{"tests", "{% if order.missing is not defined and order.lineItems is not empty %}ok{% endif %}", false, "ok"},
+		{"whitespace control", "a  {{- ' b ' -}}  c", false, "a b c"},
+		{"comment", "a{# comment #}b", false, "ab"},
+		{"ternary", "{{ order.lineItems|length > 1 ? 'many' : 'one' }}", false, "many"},
+		{"concat", "{{ 'Nr. ' ~ order.orderNumber }}", false, "Nr. 10001"},
+		{"in", "{{ 'Sh' in 'Shirt' ? 'yes' : 'no' }} {{ 3 not in [1, 2] ? 'yes' : 'no' }}", false, "yes yes"},
+		{"block", "{% block content %}inner{% endblock %}", false, "inner"},
+		{"hash", "{{ {foo: 'bar'}.foo }}", false, "bar"},
+		{"math", "{{ (1 + 2) * 3 - 4 / 2 }}", false, "7"},
+		{"getter", "{{ order.getOrderNumber() }}", false, "10001"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, _, err := renderTemplate(tc.template, vars, tc.escape)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestRenderTemplateWarnings(t *testing.T) {
+	_, warnings, err := renderTemplate("{% include 'foo.twig' %}{{ order.missing }}{{ foo|unknown }}", map[string]any{"order": map[string]any{}, "foo": "x"}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"line 1: the tag {% include %} is not supported by the preview and was skipped",
+		"the filter unknown is not supported by the preview and returns its input",
+		"the variable order.missing is not defined in the fixture",
+	}, warnings)
+}
+
+func TestRenderTemplateErrors(t *testing.T) {
+	_, _, err := renderTemplate("{% if true %}no end", nil, false)
+	assert.ErrorContains(t, err, "missing {% endif %}")
+
+	_, _, err = renderTemplate("{{ 'unclosed }}", nil, false)
+	assert.Error(t, err)
+}
+
+func TestRenderTemplateTags(t *testing.T) {
+	vars := map[string]any{
+		"items": []any{float64(5), float64(6), float64(7)},
+		"total": float64(10),
+	}
+
+	cases := []struct {
+		name     string
+		template string
+		escape   bool
+		expected string
+	}{
+		{"for with keys", "{% for k, v in {b: 2, a: 1} %}{{ k }}={{ v }};{% endfor %}", false, "a=1;b=2;"},
+		{"for with condition", "{% for i in 1..5 if i is odd %}{{ loop.index }}:{{ i }} {% endfor %}", false, "1:1 2:3 3:5 "},
+		{"for else", "{% for i in [] %}x{% else %}empty{% endfor %}", false, "empty"},
+		{"for else when every item is filtered", "{% for i in items if i > 10 %}x{% else %}none{% endfor %}", false, "none"},
+		{"loop variables", "{% for i in items %}{{ loop.index0 }}{{ loop.revindex }}{{ loop.revindex0 }}{{ loop.length }}{{ loop.first ? 'f' }}{{ loop.last ? 'l' }}|{% endfor %}", false, "0323f|1213|2103l|"},
+		{"nested loops", "{% for a in [1, 2] %}{% for b in ['x', 'y'] %}{{ a }}{{ b }}{% endfor %}{{ loop.index }} {% endfor %}", false, "1x1y1 2x2y2 "},
+		{"loop variable is scoped", "{% for i in items %}{% set inner = i %}{% endfor %}{{ i is defined ? 'i' }}{{ inner is defined ? 'inner' }}", false, ""},
+		{"set in loop changes template variable", "{% set sum = 0 %}{% for i in items %}{% set sum = sum + i %}{% endfor %}{{ sum }}", false, "18"},
+		{"set shadows fixture", "{% set total = total + 1 %}{{ total }}", false, "11"},
+		{"set block is not escaped again", "{% set b %}<b>{{ '<i>' }}</b>{% endset %}{{ b }}", true, "<b>&lt;i&gt;</b>"},
+		{"nested if", "{% if true %}{% if false %}a{% elseif 1 == 1 %}b{% endif %}{% else %}c{% endif %}", false, "b"},
+		{"if without match", "[{% if false %}a{% elseif false %}b{% endif %}]", false, "[]"},
+		{"transparent tags", "{% autoescape %}a{% endautoescape %}{% apply upper %}b{% endapply %}{% spaceless %}c{% endspaceless %}{% with {x: 1} %}d{% endwith %}{% sw_silent_feature_call 'v6.6' %}e{% endsw_silent_feature_call %}", false, "abcde"},
+		{"whitespace control on tags", "a {%- if true -%}  b  {%- endif -%} c", false, "abc"},
+		{"comment with tags", "a{# {{ x }} {% if %} #}b", false, "ab"},
+		{"lone braces", "a { b } {c}", false, "a { b } {c}"},
+		{"closer inside string", "{{ '}}' }}{{ \"%}\" }}", false, "}}%}"},
+		{"multiline", "a\n{{\n  'b'\n}}\nc", false, "a\nb\nc"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, _, err := renderTemplate(tc.template, vars, tc.escape)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+
+	out, _, err := renderTemplate("{% set x = 1 %}{{ x }}", nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "1", out, "set works without fixture")
+
+	_, _, err = renderTemplate("{% set total = 1 %}", vars, false)
+	require.NoError(t, err)
+	assert.Equal(t, float64(10), vars["total"], "set must not change the fixture")
+}
+
+func TestRenderTemplateUnsupported(t *testing.T) {
+	out, warnings, err := renderTemplate("a{% import 'macros.twig' as m %}\n{% macro foo() %}{% endmacro %}b{% embed 'x' %}{% sw_include 'y' %}{{ foo()|bar(1) }}{{ x.y }}", map[string]any{"x": map[string]any{}}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, "a\nb", out)
+	assert.Equal(t, []string{
+		"line 1: the tag {% import %} is not supported by the preview and was skipped",
+		"line 2: the tag {% macro %} is not supported by the preview and was skipped",
+		"line 2: the tag {% endmacro %} is not supported by the preview and was skipped",
+		"line 2: the tag {% embed %} is not supported by the preview and was skipped",
+		"line 2: the tag {% sw_include %} is not supported by the preview and was skipped",
+		"the filter bar is not supported by the preview and returns its input",
+		"the function foo() is not supported by the preview",
+		"the variable x.y is not defined in the fixture",
+	}, warnings)
+}
+
+func TestRenderTemplateTagErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"unclosed tag", "a\n{% if true", "line 2: unclosed {%"},
+		{"unclosed comment", "{# comment", "line 1: unclosed {#"},
+		{"invalid for", "{% for x %}{% endfor %}", `line 1: invalid for loop "x"`},
+		{"missing endfor", "{% for x in y %}", "line 1: missing {% endfor %}"},
+		{"missing endblock", "{% block a %}", "line 1: missing {% endblock %}"},
+		{"missing endset", "{% set x %}", "line 1: missing {% endset %}"},
+		{"missing endif after else", "{% if a %}{% else %}", "line 1: missing {% endif %}"},
+		{"unexpected end tag", "a\n{% endif %}", "line 2: unexpected {% endif %}"},
+		{"unexpected else", "{% for i in [1] %}{% endfor %}{% else %}", "line 1: unexpected {% else %}"},
+		{"unexpected end of transparent tag", "{% endblock %}", "line 1: unexpected {% endblock %}"},
+		{"invalid if condition", "{% if 1 + %}{% endif %}", `line 1: unexpected ""`},
+		{"invalid elseif condition", "\n{% if a %}{% elseif ( %}{% endif %}", `line 2: unexpected ""`},
+		{"invalid set", "{% set x = [ %}", `line 1: unexpected ""`},
+		{"invalid for condition", "{% for i in [1] if ( %}{% endfor %}", `line 1: unexpected ""`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := renderTemplate(tc.template, nil, false)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
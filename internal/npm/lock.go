@@ -0,0 +1,104 @@
+package npm
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LockPackage is a package installed by a package-lock.json.
+type LockPackage struct {
+	Name    string
+	Version string
+	License string
+	Dev     bool
+}
+
+type packageLock struct {
+	Packages     map[string]packageLockEntry `json:"packages"`
+	Dependencies map[string]packageLockEntry `json:"dependencies"`
+}
+
+type packageLockEntry struct {
+	Version      string                      `json:"version"`
+	License      json.RawMessage             `json:"license"`
+	Dev          bool                        `json:"dev"`
+	Link         bool                        `json:"link"`
+	Dependencies map[string]packageLockEntry `json:"dependencies"`
+}
+
+// ReadPackageLock returns the installed packages of a package-lock.json.
+// Lockfile version 2 and 3 list them in packages, version 1 nests them in dependencies.
+func ReadPackageLock(packageLockPath string) ([]LockPackage, error) {
+	body, err := os.ReadFile(packageLockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock packageLock
+	if err := json.Unmarshal(body, &lock); err != nil {
+		return nil, err
+	}
+
+	var packages []LockPackage
+
+	if len(lock.Packages) > 0 {
+		for key, entry := range lock.Packages {
+			index := strings.LastIndex(key, "node_modules/")
+			if index == -1 || entry.Link {
+				continue
+			}
+
+			packages = append(packages, LockPackage{
+				Name:    key[index+len("node_modules/"):],
+				Version: entry.Version,
+				License: lockLicense(entry.License),
+				Dev:     entry.Dev,
+			})
+		}
+	} else {
+		packages = collectLockDependencies(lock.Dependencies, packages)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+
+		return packages[i].Version < packages[j].Version
+	})
+
+	return packages, nil
+}
+
+func collectLockDependencies(dependencies map[string]packageLockEntry, packages []LockPackage) []LockPackage {
+	for name, entry := range dependencies {
+		packages = append(packages, LockPackage{Name: name, Version: entry.Version, License: lockLicense(entry.License), Dev: entry.Dev})
+		packages = collectLockDependencies(entry.Dependencies, packages)
+	}
+
+	return packages
+}
+
+// lockLicense reads the license field, which old packages set to an object with a type.
+func lockLicense(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var license string
+	if err := json.Unmarshal(raw, &license); err == nil {
+		return license
+	}
+
+	var object struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(raw, &object); err == nil {
+		return object.Type
+	}
+
+	return ""
+}
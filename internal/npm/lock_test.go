@@ -0,0 +1,55 @@
+package npm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPackageLock(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "package-lock.json")
+
+	require.NoError(t, os.WriteFile(lockPath, []byte(`{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "storefront"},
+    "node_modules/lodash": {"version": "4.17.20", "license": "MIT"},
+    "node_modules/foo/node_modules/lodash": {"version": "3.10.1", "license": {"type": "MIT"}},
+    "node_modules/@babel/core": {"version": "7.24.0", "license": "MIT", "dev": true},
+    "node_modules/local": {"resolved": "../local", "link": true}
+  }
+}`), 0o644))
+
+	packages, err := ReadPackageLock(lockPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, []LockPackage{
+		{Name: "@babel/core", Version: "7.24.0", License: "MIT", Dev: true},
+		{Name: "lodash", Version: "3.10.1", License: "MIT"},
+		{Name: "lodash", Version: "4.17.20", License: "MIT"},
+	}, packages)
+}
+
+func TestReadPackageLockVersion1(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "package-lock.json")
+
+	require.NoError(t, os.WriteFile(lockPath, []byte(`{
+  "lockfileVersion": 1,
+  "dependencies": {
+    "lodash": {"version": "4.17.20"},
+    "foo": {"version": "1.0.0", "dependencies": {"bar": {"version": "2.0.0", "dev": true}}}
+  }
+}`), 0o644))
+
+	packages, err := ReadPackageLock(lockPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, []LockPackage{
+		{Name: "bar", Version: "2.0.0", Dev: true},
+		{Name: "foo", Version: "1.0.0"},
+		{Name: "lodash", Version: "4.17.20"},
+	}, packages)
+}
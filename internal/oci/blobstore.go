@@ -0,0 +1,198 @@
+package oci
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BlobStore keeps content addressed blobs in a directory.
+type BlobStore struct {
+	dir string
+}
+
+func NewBlobStore(dir string) (*BlobStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "sha256"), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	return &BlobStore{dir: dir}, nil
+}
+
+// Path returns the file of the blob with the given digest.
+func (s *BlobStore) Path(digest string) string {
+	return filepath.Join(s.dir, "sha256", strings.TrimPrefix(digest, "sha256:"))
+}
+
+func (s *BlobStore) Has(digest string) bool {
+	_, err := os.Stat(s.Path(digest))
+	return err == nil
+}
+
+// Put stores data and returns its descriptor.
+func (s *BlobStore) Put(mediaType string, data []byte) (Descriptor, error) {
+	sum := sha256.Sum256(data)
+	desc := Descriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + hex.EncodeToString(sum[:]),
+		Size:      int64(len(data)),
+	}
+
+	if err := os.WriteFile(s.Path(desc.Digest), data, 0o644); err != nil {
+		return Descriptor{}, err
+	}
+
+	return desc, nil
+}
+
+// PutJSON stores the JSON encoding of value.
+func (s *BlobStore) PutJSON(mediaType string, value any) (Descriptor, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return Descriptor{}, err
+	}
+
+	return s.Put(mediaType, data)
+}
+
+// PutReader stores the content of r. The blob is only kept when it matches
+// expectedDigest, unless expectedDigest is empty.
+func (s *BlobStore) PutReader(mediaType string, r io.Reader, expectedDigest string) (Descriptor, error) {
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, "sha256"), ".upload-*")
+	if err != nil {
+		return Descriptor{}, err
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return Descriptor{}, err
+	}
+
+	desc := Descriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + hex.EncodeToString(hash.Sum(nil)),
+		Size:      size,
+	}
+
+	if expectedDigest != "" && desc.Digest != expectedDigest {
+		return Descriptor{}, fmt.Errorf("digest mismatch: expected %s, got %s", expectedDigest, desc.Digest)
+	}
+
+	if err := os.Rename(tmp.Name(), s.Path(desc.Digest)); err != nil {
+		return Descriptor{}, err
+	}
+
+	return desc, nil
+}
+
+func (s *BlobStore) ReadJSON(digest string, value any) error {
+	data, err := os.ReadFile(s.Path(digest))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, value)
+}
+
+// WriteLayout writes an OCI image layout tarball containing the index and the
+// given blobs, which are looked up in the stores in order. Entries are sorted and
+// carry a fixed timestamp, so the same image always produces the same tarball.
+func WriteLayout(w io.Writer, index Index, blobs []string, modTime time.Time, stores ...*BlobStore) error {
+	tw := tar.NewWriter(w)
+
+	indexData, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	writeFile := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg, Format: tar.FormatPAX}); err != nil {
+			return err
+		}
+
+		_, err := tw.Write(data)
+
+		return err
+	}
+
+	if err := writeFile("oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`)); err != nil {
+		return err
+	}
+
+	if err := writeFile("index.json", indexData); err != nil {
+		return err
+	}
+
+	for _, dir := range []string{"blobs/", "blobs/sha256/"} {
+		if err := tw.WriteHeader(&tar.Header{Name: dir, Mode: 0o755, ModTime: modTime, Typeflag: tar.TypeDir, Format: tar.FormatPAX}); err != nil {
+			return err
+		}
+	}
+
+	sorted := slices.Clone(blobs)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	for _, digest := range sorted {
+		idx := slices.IndexFunc(stores, func(s *BlobStore) bool { return s.Has(digest) })
+		if idx == -1 {
+			return fmt.Errorf("blob %s is missing", digest)
+		}
+
+		if err := stores[idx].writeBlob(tw, digest, modTime); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
+func (s *BlobStore) writeBlob(tw *tar.Writer, digest string, modTime time.Time) error {
+	file, err := os.Open(s.Path(digest))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:     "blobs/sha256/" + strings.TrimPrefix(digest, "sha256:"),
+		Mode:     0o644,
+		Size:     stat.Size(),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+		Format:   tar.FormatPAX,
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, file)
+
+	return err
+}
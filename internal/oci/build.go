@@ -0,0 +1,252 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	inTotoStatementType = "https://in-toto.io/Statement/v0.1"
+	// PredicateTypeCycloneDX is the in-toto predicate type of CycloneDX SBOM attestations.
+	PredicateTypeCycloneDX = "https://cyclonedx.org/bom"
+)
+
+// LayerSpec describes one layer built from paths relative to the build root.
+type LayerSpec struct {
+	Name  string
+	Paths []string
+}
+
+// BuildOptions configure Build.
+type BuildOptions struct {
+	// Base is the already pulled base image
+	Base *BaseImage
+	// BaseStore holds the blobs of the base image
+	BaseStore *BlobStore
+	// Store receives the blobs created by the build
+	Store *BlobStore
+	Root  string
+	// Layers are added in the given order on top of the base image
+	Layers       []LayerSpec
+	LayerOptions LayerOptions
+	Labels       map[string]string
+	// Name is the full image name including tag written into the index, optional
+	Name string
+	// SBOM is a CycloneDX JSON document attached as in-toto attestation, optional
+	SBOM []byte
+}
+
+// BuiltLayer reports a layer created by Build.
+type BuiltLayer struct {
+	Name string
+	Layer
+}
+
+// BuildResult is an image ready to be written as OCI layout.
+type BuildResult struct {
+	Index       Index
+	ImageDigest string
+	Layers      []BuiltLayer
+
+	created time.Time
+	blobs   []string
+	stores  []*BlobStore
+}
+
+// Build adds the layers to the base image and assembles the index. The creation
+// time of the image is the modification time of the layers, so builds of the same
+// tree with the same base image are byte for byte identical.
+func Build(ctx context.Context, opts BuildOptions) (*BuildResult, error) {
+	created := opts.LayerOptions.ModTime.UTC()
+	result := &BuildResult{created: created, stores: []*BlobStore{opts.Store, opts.BaseStore}}
+
+	config := opts.Base.Config
+	config.Created = &created
+	config.RootFS.DiffIDs = append([]string(nil), config.RootFS.DiffIDs...)
+	config.History = append([]History(nil), config.History...)
+	config.Config.Labels = maps.Clone(config.Config.Labels)
+
+	if config.Config.Labels == nil {
+		config.Config.Labels = map[string]string{}
+	}
+
+	maps.Copy(config.Config.Labels, opts.Labels)
+
+	if config.Config.WorkingDir == "" && opts.LayerOptions.Prefix != "" {
+		config.Config.WorkingDir = "/" + strings.Trim(opts.LayerOptions.Prefix, "/")
+	}
+
+	layers := append([]Descriptor(nil), opts.Base.Manifest.Layers...)
+
+	for _, spec := range opts.Layers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if len(spec.Paths) == 0 {
+			continue
+		}
+
+		layer, err := BuildLayer(opts.Store, opts.Root, spec.Paths, opts.LayerOptions)
+		if err != nil {
+			return nil, fmt.Errorf("build %s layer: %w", spec.Name, err)
+		}
+
+		result.Layers = append(result.Layers, BuiltLayer{Name: spec.Name, Layer: layer})
+		layers = append(layers, layer.Descriptor)
+		config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, layer.DiffID)
+
+		if len(opts.Base.Config.History) > 0 {
+			config.History = append(config.History, History{
+				Created:   &created,
+				CreatedBy: fmt.Sprintf("shopware-cli project image build: %s layer", spec.Name),
+			})
+		}
+	}
+
+	configDesc, err := opts.Store.PutJSON(MediaTypeImageConfig, config)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config:        configDesc,
+		Layers:        layers,
+		Annotations: map[string]string{
+			AnnotationCreated:    created.Format(time.RFC3339),
+			AnnotationBaseName:   opts.Base.Reference.String(),
+			AnnotationBaseDigest: opts.Base.Digest,
+		},
+	}
+
+	manifestDesc, err := opts.Store.PutJSON(MediaTypeImageManifest, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestDesc.Platform = &Platform{OS: config.OS, Architecture: config.Architecture, Variant: config.Variant}
+
+	result.ImageDigest = manifestDesc.Digest
+	result.blobs = append(result.blobs, configDesc.Digest, manifestDesc.Digest)
+
+	for _, layer := range layers {
+		result.blobs = append(result.blobs, layer.Digest)
+	}
+
+	if opts.Name != "" {
+		manifestDesc.Annotations = map[string]string{
+			annotationContainerdRef: opts.Name,
+			AnnotationRefName:       tagOf(opts.Name),
+		}
+	}
+
+	result.Index = Index{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageIndex,
+		Manifests:     []Descriptor{manifestDesc},
+	}
+
+	if len(opts.SBOM) > 0 {
+		attestation, blobs, err := buildSBOMAttestation(opts.Store, opts.SBOM, manifestDesc, opts.Name)
+		if err != nil {
+			return nil, fmt.Errorf("attach SBOM: %w", err)
+		}
+
+		result.Index.Manifests = append(result.Index.Manifests, attestation)
+		result.blobs = append(result.blobs, blobs...)
+	}
+
+	return result, nil
+}
+
+// WriteLayout writes the image as OCI image layout tarball.
+func (r *BuildResult) WriteLayout(w io.Writer) error {
+	return WriteLayout(w, r.Index, r.blobs, r.created, r.stores...)
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Subject       []inTotoSubject `json:"subject"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// buildSBOMAttestation creates an attestation manifest in the layout used by
+// BuildKit, so registries and docker buildx imagetools show the SBOM of the image.
+func buildSBOMAttestation(store *BlobStore, sbom []byte, image Descriptor, name string) (Descriptor, []string, error) {
+	if !json.Valid(sbom) {
+		return Descriptor{}, nil, fmt.Errorf("SBOM is not valid JSON")
+	}
+
+	subjectName := "pkg:docker/" + strings.TrimPrefix(name, "docker.io/")
+	if name == "" {
+		subjectName = "pkg:docker/image"
+	}
+
+	subjectName += "?platform=" + url.QueryEscape(image.Platform.String())
+
+	statement := inTotoStatement{
+		Type:          inTotoStatementType,
+		PredicateType: PredicateTypeCycloneDX,
+		Subject: []inTotoSubject{
+			{Name: subjectName, Digest: map[string]string{"sha256": strings.TrimPrefix(image.Digest, "sha256:")}},
+		},
+		Predicate: sbom,
+	}
+
+	layerDesc, err := store.PutJSON(MediaTypeInToto, statement)
+	if err != nil {
+		return Descriptor{}, nil, err
+	}
+
+	layerDesc.Annotations = map[string]string{"in-toto.io/predicate-type": PredicateTypeCycloneDX}
+
+	configDesc, err := store.PutJSON(MediaTypeImageConfig, ImageConfig{
+		Architecture: "unknown",
+		OS:           "unknown",
+		RootFS:       RootFS{Type: "layers", DiffIDs: []string{layerDesc.Digest}},
+	})
+	if err != nil {
+		return Descriptor{}, nil, err
+	}
+
+	manifestDesc, err := store.PutJSON(MediaTypeImageManifest, Manifest{
+		SchemaVersion: 2,
+		MediaType:     MediaTypeImageManifest,
+		Config:        configDesc,
+		Layers:        []Descriptor{layerDesc},
+	})
+	if err != nil {
+		return Descriptor{}, nil, err
+	}
+
+	manifestDesc.Platform = &Platform{OS: "unknown", Architecture: "unknown"}
+	manifestDesc.Annotations = map[string]string{
+		annotationReferenceDig:  image.Digest,
+		annotationReferenceType: "attestation-manifest",
+	}
+
+	return manifestDesc, []string{layerDesc.Digest, configDesc.Digest, manifestDesc.Digest}, nil
+}
+
+func tagOf(name string) string {
+	lastSlash := strings.LastIndex(name, "/")
+	if idx := strings.LastIndex(name, ":"); idx > lastSlash {
+		return name[idx+1:]
+	}
+
+	return "latest"
+}
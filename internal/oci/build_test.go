@@ -0,0 +1,196 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRegistry struct {
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	types     map[string]string
+}
+
+func (f *fakeRegistry) add(mediaType string, value any) Descriptor {
+	data, _ := json.Marshal(value)
+	sum := sha256.Sum256(data)
+	desc := Descriptor{MediaType: mediaType, Digest: "sha256:" + hex.EncodeToString(sum[:]), Size: int64(len(data))}
+	f.blobs[desc.Digest] = data
+
+	return desc
+}
+
+func newFakeRegistry(t *testing.T) (*httptest.Server, *fakeRegistry) {
+	t.Helper()
+
+	f := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}, types: map[string]string{}}
+
+	config := f.add(MediaTypeImageConfig, ImageConfig{
+		Architecture: "amd64",
+		OS:           "linux",
+		Config:       ContainerConfig{Labels: map[string]string{"base": "yes"}},
+		RootFS:       RootFS{Type: "layers", DiffIDs: []string{"sha256:base"}},
+	})
+	layer := f.add(mediaTypeDockerLayerGzip, "layer")
+
+	manifest := f.add(mediaTypeDockerManifest, Manifest{SchemaVersion: 2, MediaType: mediaTypeDockerManifest, Config: config, Layers: []Descriptor{layer}})
+	f.manifests[manifest.Digest] = f.blobs[manifest.Digest]
+	f.types[manifest.Digest] = mediaTypeDockerManifest
+
+	index := Index{SchemaVersion: 2, MediaType: MediaTypeImageIndex, Manifests: []Descriptor{
+		{MediaType: MediaTypeImageManifest, Digest: "sha256:arm", Platform: &Platform{OS: "linux", Architecture: "arm64"}},
+		{MediaType: MediaTypeImageManifest, Digest: manifest.Digest, Platform: &Platform{OS: "linux", Architecture: "amd64"}},
+	}}
+	indexData, _ := json.Marshal(index)
+	f.manifests["8.3"] = indexData
+	f.types["8.3"] = MediaTypeImageIndex
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"token":"secret"}`))
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test",scope="repository:shopware/base:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if ref, ok := strings.CutPrefix(r.URL.Path, "/v2/shopware/base/manifests/"); ok {
+			if data, found := f.manifests[ref]; found {
+				w.Header().Set("Content-Type", f.types[ref])
+				_, _ = w.Write(data)
+				return
+			}
+		}
+
+		if digest, ok := strings.CutPrefix(r.URL.Path, "/v2/shopware/base/blobs/"); ok {
+			if data, found := f.blobs[digest]; found {
+				_, _ = w.Write(data)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, f
+}
+
+func TestPullSelectsPlatform(t *testing.T) {
+	server, _ := newFakeRegistry(t)
+
+	ref, err := ParseReference(strings.TrimPrefix(server.URL, "http://") + "/shopware/base:8.3")
+	require.NoError(t, err)
+
+	store, err := NewBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	image, err := NewRegistry().Pull(t.Context(), ref, Platform{OS: "linux", Architecture: "amd64"}, store)
+	require.NoError(t, err)
+
+	assert.Equal(t, "yes", image.Config.Config.Labels["base"])
+	assert.Equal(t, MediaTypeLayerGzip, image.Manifest.Layers[0].MediaType)
+	assert.True(t, store.Has(image.Manifest.Layers[0].Digest))
+
+	_, err = NewRegistry().Pull(t.Context(), ref, Platform{OS: "linux", Architecture: "s390x"}, store)
+	assert.ErrorContains(t, err, "linux/arm64, linux/amd64")
+}
+
+func TestBuildWritesLayout(t *testing.T) {
+	server, _ := newFakeRegistry(t)
+
+	ref, err := ParseReference(strings.TrimPrefix(server.URL, "http://") + "/shopware/base:8.3")
+	require.NoError(t, err)
+
+	baseStore, err := NewBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	base, err := NewRegistry().Pull(t.Context(), ref, Platform{OS: "linux", Architecture: "amd64"}, baseStore)
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "public"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "public", "index.php"), []byte("<?php"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "composer.json"), []byte("{}"), 0o644))
+
+	build := func() ([]byte, *BuildResult) {
+		store, err := NewBlobStore(t.TempDir())
+		require.NoError(t, err)
+
+		result, err := Build(t.Context(), BuildOptions{
+			Base:         base,
+			BaseStore:    baseStore,
+			Store:        store,
+			Root:         root,
+			Layers:       []LayerSpec{{Name: "vendor"}, {Name: "public", Paths: []string{"public"}}, {Name: "code", Paths: []string{"composer.json"}}},
+			LayerOptions: LayerOptions{Prefix: "var/www/html", ModTime: time.Unix(0, 0)},
+			Labels:       map[string]string{"org.opencontainers.image.title": "shop"},
+			Name:         "ghcr.io/acme/shop:1.0.0",
+			SBOM:         []byte(`{"bomFormat":"CycloneDX"}`),
+		})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, result.WriteLayout(&buf))
+
+		return buf.Bytes(), result
+	}
+
+	first, result := build()
+	second, _ := build()
+
+	assert.Equal(t, first, second)
+	assert.Len(t, result.Layers, 2)
+	require.Len(t, result.Index.Manifests, 2)
+	assert.Equal(t, "1.0.0", result.Index.Manifests[0].Annotations[AnnotationRefName])
+	assert.Equal(t, result.ImageDigest, result.Index.Manifests[1].Annotations[annotationReferenceDig])
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(bytes.NewReader(first))
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = data
+	}
+
+	assert.Contains(t, files, "oci-layout")
+	assert.Contains(t, files, "index.json")
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(files["blobs/sha256/"+strings.TrimPrefix(result.ImageDigest, "sha256:")], &manifest))
+	assert.Len(t, manifest.Layers, 3)
+	assert.Equal(t, base.Digest, manifest.Annotations[AnnotationBaseDigest])
+
+	var config ImageConfig
+	require.NoError(t, json.Unmarshal(files["blobs/sha256/"+strings.TrimPrefix(manifest.Config.Digest, "sha256:")], &config))
+	assert.Equal(t, "yes", config.Config.Labels["base"])
+	assert.Equal(t, "shop", config.Config.Labels["org.opencontainers.image.title"])
+	assert.Equal(t, "/var/www/html", config.Config.WorkingDir)
+	assert.Len(t, config.RootFS.DiffIDs, 3)
+}
@@ -0,0 +1,199 @@
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// LayerOptions control how files are written into a layer.
+type LayerOptions struct {
+	// Prefix is the directory inside the image the files are placed in, e.g. var/www/html
+	Prefix string
+	UID    int
+	GID    int
+	// ModTime is used for all entries to keep the layer reproducible
+	ModTime time.Time
+	// Exclude skips the given slash separated path relative to the root and, for directories, all its children
+	Exclude func(rel string) bool
+}
+
+// Layer is a gzip compressed layer stored in a BlobStore.
+type Layer struct {
+	Descriptor Descriptor
+	// DiffID is the digest of the uncompressed tar
+	DiffID string
+	// Files is the number of entries in the layer
+	Files int
+}
+
+// BuildLayer writes the given paths relative to root with all their children into
+// a new layer. Entries are sorted, owned by the configured user and carry the same
+// modification time, so unchanged files always result in the same layer digest.
+func BuildLayer(store *BlobStore, root string, paths []string, opts LayerOptions) (Layer, error) {
+	tmp, err := os.CreateTemp(filepath.Join(store.dir, "sha256"), ".layer-*")
+	if err != nil {
+		return Layer{}, err
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	compressedHash := sha256.New()
+	compressedSize := &countingWriter{w: io.MultiWriter(tmp, compressedHash)}
+
+	gz, err := gzip.NewWriterLevel(compressedSize, gzip.DefaultCompression)
+	if err != nil {
+		return Layer{}, err
+	}
+
+	diffHash := sha256.New()
+	tw := tar.NewWriter(io.MultiWriter(gz, diffHash))
+
+	sorted := slices.Clone(paths)
+	slices.Sort(sorted)
+
+	files := 0
+
+	for _, rel := range sorted {
+		err := filepath.WalkDir(filepath.Join(root, filepath.FromSlash(rel)), func(current string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relPath, err := filepath.Rel(root, current)
+			if err != nil {
+				return err
+			}
+
+			relPath = filepath.ToSlash(relPath)
+
+			if opts.Exclude != nil && opts.Exclude(relPath) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			written, err := writeLayerEntry(tw, current, relPath, d, opts)
+			if written {
+				files++
+			}
+
+			return err
+		})
+		if err != nil {
+			return Layer{}, fmt.Errorf("add %s to layer: %w", rel, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return Layer{}, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return Layer{}, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return Layer{}, err
+	}
+
+	layer := Layer{
+		Descriptor: Descriptor{
+			MediaType: MediaTypeLayerGzip,
+			Digest:    "sha256:" + hex.EncodeToString(compressedHash.Sum(nil)),
+			Size:      compressedSize.n,
+		},
+		DiffID: "sha256:" + hex.EncodeToString(diffHash.Sum(nil)),
+		Files:  files,
+	}
+
+	if err := os.Rename(tmp.Name(), store.Path(layer.Descriptor.Digest)); err != nil {
+		return Layer{}, err
+	}
+
+	return layer, nil
+}
+
+func writeLayerEntry(tw *tar.Writer, current, relPath string, d fs.DirEntry, opts LayerOptions) (bool, error) {
+	info, err := d.Info()
+	if err != nil {
+		return false, err
+	}
+
+	header := &tar.Header{
+		Name:    path.Join(opts.Prefix, relPath),
+		Mode:    int64(info.Mode().Perm()),
+		Uid:     opts.UID,
+		Gid:     opts.GID,
+		ModTime: opts.ModTime,
+		Format:  tar.FormatPAX,
+	}
+
+	switch {
+	case info.Mode().IsDir():
+		header.Typeflag = tar.TypeDir
+		header.Name += "/"
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(current)
+		if err != nil {
+			return false, err
+		}
+
+		header.Typeflag = tar.TypeSymlink
+		header.Linkname = target
+	case info.Mode().IsRegular():
+		header.Typeflag = tar.TypeReg
+		header.Size = info.Size()
+	default:
+		// sockets, devices and pipes have no place in an image
+		return false, nil
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return false, err
+	}
+
+	if header.Typeflag != tar.TypeReg {
+		return true, nil
+	}
+
+	file, err := os.Open(current)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if _, err := io.Copy(tw, file); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,75 @@
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildLayerIsReproducible(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "vendor", "shopware"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "autoload.php"), []byte("<?php"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "shopware", "core.php"), []byte("<?php"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "shopware", "skip.log"), []byte("log"), 0o644))
+
+	opts := LayerOptions{
+		Prefix:  "var/www/html",
+		UID:     82,
+		GID:     82,
+		ModTime: time.Unix(0, 0),
+		Exclude: func(rel string) bool { return filepath.Ext(rel) == ".log" },
+	}
+
+	store, err := NewBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	first, err := BuildLayer(store, root, []string{"vendor"}, opts)
+	require.NoError(t, err)
+
+	// a different mtime on disk must not change the layer
+	require.NoError(t, os.Chtimes(filepath.Join(root, "vendor", "autoload.php"), time.Now(), time.Now()))
+
+	second, err := BuildLayer(store, root, []string{"vendor"}, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 4, first.Files)
+	assert.True(t, store.Has(first.Descriptor.Digest))
+
+	file, err := os.Open(store.Path(first.Descriptor.Digest))
+	require.NoError(t, err)
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	var names []string
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+
+		assert.Equal(t, 82, header.Uid)
+		names = append(names, header.Name)
+	}
+
+	assert.Equal(t, []string{
+		"var/www/html/vendor/",
+		"var/www/html/vendor/autoload.php",
+		"var/www/html/vendor/shopware/",
+		"var/www/html/vendor/shopware/core.php",
+	}, names)
+}
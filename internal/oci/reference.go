@@ -0,0 +1,108 @@
+package oci
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	dockerHubDomain   = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+)
+
+// Reference points to an image in a registry, like ghcr.io/shopware/docker-base:8.3-caddy.
+type Reference struct {
+	Registry   string
+	Repository string
+	// Tag or digest of the image
+	Reference string
+}
+
+// ParseReference parses image references the same way docker does: images without
+// registry are resolved against Docker Hub and official images get the library/ prefix.
+func ParseReference(value string) (Reference, error) {
+	if value == "" || strings.ContainsAny(value, " \t\n") {
+		return Reference{}, fmt.Errorf("invalid image reference %q", value)
+	}
+
+	ref := Reference{Registry: dockerHubDomain}
+	remainder := value
+
+	if first, rest, found := strings.Cut(value, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry = first
+		remainder = rest
+	}
+
+	if name, digest, found := strings.Cut(remainder, "@"); found {
+		if !strings.HasPrefix(digest, "sha256:") {
+			return Reference{}, fmt.Errorf("invalid digest in image reference %q", value)
+		}
+
+		remainder = name
+		ref.Reference = digest
+	}
+
+	if idx := strings.LastIndex(remainder, ":"); idx != -1 {
+		if ref.Reference == "" {
+			ref.Reference = remainder[idx+1:]
+		}
+
+		remainder = remainder[:idx]
+	}
+
+	if ref.Reference == "" {
+		ref.Reference = "latest"
+	}
+
+	if remainder == "" || remainder != strings.ToLower(remainder) {
+		return Reference{}, fmt.Errorf("invalid repository in image reference %q", value)
+	}
+
+	if ref.Registry == dockerHubDomain && !strings.Contains(remainder, "/") {
+		remainder = "library/" + remainder
+	}
+
+	ref.Repository = remainder
+
+	return ref, nil
+}
+
+// IsDigest reports whether the reference is pinned to a digest.
+func (r Reference) IsDigest() bool {
+	return strings.HasPrefix(r.Reference, "sha256:")
+}
+
+func (r Reference) String() string {
+	separator := ":"
+	if r.IsDigest() {
+		separator = "@"
+	}
+
+	return r.Registry + "/" + r.Repository + separator + r.Reference
+}
+
+// Name returns the reference without tag or digest.
+func (r Reference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+func (r Reference) registryHost() string {
+	if r.Registry == dockerHubDomain {
+		return dockerHubRegistry
+	}
+
+	return r.Registry
+}
+
+func (r Reference) scheme() string {
+	host := r.Registry
+	if idx := strings.LastIndex(host, ":"); idx != -1 {
+		host = host[:idx]
+	}
+
+	if host == "localhost" || host == "127.0.0.1" {
+		return "http"
+	}
+
+	return "https"
+}
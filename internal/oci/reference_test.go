@@ -0,0 +1,46 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected Reference
+	}{
+		{"alpine", Reference{Registry: "docker.io", Repository: "library/alpine", Reference: "latest"}},
+		{"shopware/docker-base:8.3", Reference{Registry: "docker.io", Repository: "shopware/docker-base", Reference: "8.3"}},
+		{"ghcr.io/shopware/docker-base:8.3-caddy", Reference{Registry: "ghcr.io", Repository: "shopware/docker-base", Reference: "8.3-caddy"}},
+		{"localhost:5000/app", Reference{Registry: "localhost:5000", Repository: "app", Reference: "latest"}},
+		{"ghcr.io/shopware/docker-base:8.3@sha256:abc", Reference{Registry: "ghcr.io", Repository: "shopware/docker-base", Reference: "sha256:abc"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			ref, err := ParseReference(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ref)
+		})
+	}
+}
+
+func TestParseReferenceInvalid(t *testing.T) {
+	for _, input := range []string{"", "Shopware/Base", "ghcr.io/a@md5:abc", "with space"} {
+		_, err := ParseReference(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestReferenceEndpoint(t *testing.T) {
+	hub, _ := ParseReference("alpine")
+	assert.Equal(t, "registry-1.docker.io", hub.registryHost())
+	assert.Equal(t, "https", hub.scheme())
+
+	local, _ := ParseReference("127.0.0.1:5000/app@sha256:abc")
+	assert.Equal(t, "http", local.scheme())
+	assert.Equal(t, "127.0.0.1:5000/app@sha256:abc", local.String())
+}
@@ -0,0 +1,307 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Registry pulls images using the OCI distribution API. Only anonymous access
+// with bearer token challenges is supported, which covers public base images.
+type Registry struct {
+	client *http.Client
+	tokens map[string]string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		client: &http.Client{Timeout: 10 * time.Minute},
+		tokens: map[string]string{},
+	}
+}
+
+// BaseImage is a pulled image for a single platform.
+type BaseImage struct {
+	Reference Reference
+	// Digest of the platform specific manifest
+	Digest   string
+	Manifest Manifest
+	Config   ImageConfig
+}
+
+var manifestAccept = strings.Join([]string{
+	MediaTypeImageIndex,
+	MediaTypeImageManifest,
+	mediaTypeDockerManifestList,
+	mediaTypeDockerManifest,
+}, ", ")
+
+// Pull downloads the manifest, config and layers of the image for the platform
+// into the store. Layers already present in the store are not downloaded again.
+func (r *Registry) Pull(ctx context.Context, ref Reference, platform Platform, store *BlobStore) (*BaseImage, error) {
+	mediaType, data, digest, err := r.fetchManifest(ctx, ref, ref.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	if mediaType == MediaTypeImageIndex || mediaType == mediaTypeDockerManifestList {
+		var index Index
+		if err := json.Unmarshal(data, &index); err != nil {
+			return nil, fmt.Errorf("decode index of %s: %w", ref, err)
+		}
+
+		var selected *Descriptor
+		var available []string
+
+		for i, m := range index.Manifests {
+			if m.Platform == nil {
+				continue
+			}
+
+			available = append(available, m.Platform.String())
+
+			if selected == nil && platform.matches(m.Platform) {
+				selected = &index.Manifests[i]
+			}
+		}
+
+		if selected == nil {
+			return nil, fmt.Errorf("image %s is not available for %s, available platforms: %s", ref, platform, strings.Join(available, ", "))
+		}
+
+		mediaType, data, digest, err = r.fetchManifest(ctx, ref, selected.Digest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mediaType != MediaTypeImageManifest && mediaType != mediaTypeDockerManifest {
+		return nil, fmt.Errorf("unsupported manifest type %q of %s", mediaType, ref)
+	}
+
+	image := &BaseImage{Reference: ref, Digest: digest}
+
+	if err := json.Unmarshal(data, &image.Manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest of %s: %w", ref, err)
+	}
+
+	if err := r.fetchBlob(ctx, ref, image.Manifest.Config, store); err != nil {
+		return nil, err
+	}
+
+	if err := store.ReadJSON(image.Manifest.Config.Digest, &image.Config); err != nil {
+		return nil, fmt.Errorf("decode config of %s: %w", ref, err)
+	}
+
+	for i, layer := range image.Manifest.Layers {
+		if layer.MediaType == mediaTypeDockerLayerGzip {
+			image.Manifest.Layers[i].MediaType = MediaTypeLayerGzip
+		}
+
+		if err := r.fetchBlob(ctx, ref, layer, store); err != nil {
+			return nil, err
+		}
+	}
+
+	return image, nil
+}
+
+func (r *Registry) fetchManifest(ctx context.Context, ref Reference, reference string) (string, []byte, string, error) {
+	resp, err := r.get(ctx, ref, "manifests/"+reference, manifestAccept)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	mediaType := resp.Header.Get("Content-Type")
+	if idx := strings.Index(mediaType, ";"); idx != -1 {
+		mediaType = mediaType[:idx]
+	}
+
+	if mediaType == "" || mediaType == "application/json" {
+		var probe struct {
+			MediaType string `json:"mediaType"`
+		}
+
+		_ = json.Unmarshal(data, &probe)
+		mediaType = probe.MediaType
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if strings.HasPrefix(reference, "sha256:") {
+		digest = reference
+	}
+
+	return mediaType, data, digest, nil
+}
+
+func (r *Registry) fetchBlob(ctx context.Context, ref Reference, desc Descriptor, store *BlobStore) error {
+	if store.Has(desc.Digest) {
+		return nil
+	}
+
+	resp, err := r.get(ctx, ref, "blobs/"+desc.Digest, "")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if _, err := store.PutReader(desc.MediaType, resp.Body, desc.Digest); err != nil {
+		return fmt.Errorf("download %s of %s: %w", desc.Digest, ref, err)
+	}
+
+	return nil
+}
+
+func (r *Registry) get(ctx context.Context, ref Reference, path, accept string) (*http.Response, error) {
+	endpoint := fmt.Sprintf("%s://%s/v2/%s/%s", ref.scheme(), ref.registryHost(), ref.Repository, path)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		if token := r.tokens[ref.Name()]; token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", endpoint, err)
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			_ = resp.Body.Close()
+
+			token, err := r.fetchToken(ctx, challenge)
+			if err != nil {
+				return nil, fmt.Errorf("authenticate against %s: %w", ref.Registry, err)
+			}
+
+			r.tokens[ref.Name()] = token
+
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("fetch %s: unexpected status %d", endpoint, resp.StatusCode)
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("fetch %s: access denied", endpoint)
+}
+
+func (r *Registry) fetchToken(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	values := parseChallengeParams(params)
+	realm := values["realm"]
+	if realm == "" {
+		return "", errors.New("authentication challenge without realm")
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", err
+	}
+
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint responded with %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	if body.Token != "" {
+		return body.Token, nil
+	}
+
+	return body.AccessToken, nil
+}
+
+func parseChallengeParams(params string) map[string]string {
+	values := map[string]string{}
+
+	for params != "" {
+		var pair string
+		inQuotes := false
+		end := len(params)
+
+		for i, c := range params {
+			if c == '"' {
+				inQuotes = !inQuotes
+			}
+
+			if c == ',' && !inQuotes {
+				end = i
+				break
+			}
+		}
+
+		pair, params = params[:end], strings.TrimPrefix(params[end:], ",")
+
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if found {
+			values[strings.ToLower(key)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,254 @@
+package php
+
+import (
+	"strings"
+)
+
+// TokenKind is the kind of a Token.
+type TokenKind int
+
+const (
+	TokenName TokenKind = iota
+	TokenVariable
+	TokenString
+	TokenNumber
+	TokenPunctuator
+	TokenDocComment
+	// TokenAttributeStart is #[
+	TokenAttributeStart
+	TokenInlineHTML
+)
+
+// Token is a PHP token. Comments other than doc comments and whitespace are
+// skipped.
+type Token struct {
+	Kind TokenKind
+	// Value is the source of the token. For strings it is the content without
+	// quotes, interpolation is kept as is.
+	Value string
+	Start int
+	End   int
+	Line  int
+}
+
+var phpPunctuators = []string{
+	"<=>", "**=", "...", "<<=", ">>=", "===", "!==", "??=", "?->",
+	"::", "->", "=>", "==", "!=", "<>", "<=", ">=", "&&", "||", "??", "++", "--", "+=", "-=", "*=", "/=", ".=", "%=", "&=", "|=", "^=", "<<", ">>", "**",
+}
+
+// Tokenize splits PHP source into tokens. Content outside of <?php tags is
+// returned as inline HTML.
+func Tokenize(src string) []Token {
+	l := &lexer{src: src, line: 1}
+	l.run()
+
+	return l.tokens
+}
+
+type lexer struct {
+	src    string
+	pos    int
+	line   int
+	tokens []Token
+}
+
+func (l *lexer) emit(kind TokenKind, start int, value string, line int) {
+	l.tokens = append(l.tokens, Token{Kind: kind, Value: value, Start: start, End: l.pos, Line: line})
+}
+
+func (l *lexer) advance(n int) {
+	end := min(l.pos+n, len(l.src))
+	l.line += strings.Count(l.src[l.pos:end], "\n")
+	l.pos = end
+}
+
+func (l *lexer) run() {
+	for l.pos < len(l.src) {
+		l.inlineHTML()
+		l.code()
+	}
+}
+
+// inlineHTML consumes everything up to the next opening tag.
+func (l *lexer) inlineHTML() {
+	idx := strings.Index(l.src[l.pos:], "<?")
+	if idx < 0 {
+		idx = len(l.src) - l.pos
+	}
+
+	start, line := l.pos, l.line
+	l.advance(idx)
+
+	if l.pos > start {
+		l.emit(TokenInlineHTML, start, l.src[start:l.pos], line)
+	}
+
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "<?php"):
+		l.advance(5)
+	case strings.HasPrefix(l.src[l.pos:], "<?="):
+		l.advance(3)
+	case strings.HasPrefix(l.src[l.pos:], "<?"):
+		l.advance(2)
+	}
+}
+
+// code consumes PHP code up to the closing tag.
+func (l *lexer) code() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		start, line := l.pos, l.line
+		rest := l.src[l.pos:]
+
+		switch {
+		case strings.HasPrefix(rest, "?>"):
+			l.advance(2)
+			return
+		case c == '\n' || c == ' ' || c == '\t' || c == '\r':
+			l.advance(1)
+		case strings.HasPrefix(rest, "#["):
+			l.advance(2)
+			l.emit(TokenAttributeStart, start, "#[", line)
+		case c == '#' || strings.HasPrefix(rest, "//"):
+			end := len(rest)
+			if idx := strings.IndexByte(rest, '\n'); idx >= 0 {
+				end = idx
+			}
+
+			if idx := strings.Index(rest[:end], "?>"); idx >= 0 {
+				end = idx
+			}
+
+			l.advance(end)
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += 4
+			}
+
+			l.advance(end)
+
+			if strings.HasPrefix(rest, "/**") && end > 4 {
+				l.emit(TokenDocComment, start, l.src[start:l.pos], line)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			l.advance(l.quotedLength(l.pos))
+
+			value := l.src[start+1 : l.pos]
+			if l.pos-start >= 2 && l.src[l.pos-1] == c {
+				value = l.src[start+1 : l.pos-1]
+			}
+
+			l.emit(TokenString, start, value, line)
+		case strings.HasPrefix(rest, "<<<"):
+			value, n := heredoc(rest)
+			l.advance(n)
+			l.emit(TokenString, start, value, line)
+		case c == '$' && l.pos+1 < len(l.src) && isNameStart(l.src[l.pos+1]):
+			end := l.pos + 1
+			for end < len(l.src) && isNamePart(l.src[end]) {
+				end++
+			}
+
+			l.advance(end - l.pos)
+			l.emit(TokenVariable, start, l.src[start:l.pos], line)
+		case isNameStart(c) || (c == '\\' && l.pos+1 < len(l.src) && isNameStart(l.src[l.pos+1])):
+			end := l.pos
+			for end < len(l.src) && (isNamePart(l.src[end]) || (l.src[end] == '\\' && end+1 < len(l.src) && isNameStart(l.src[end+1]))) {
+				end++
+			}
+
+			l.advance(end - l.pos)
+			l.emit(TokenName, start, l.src[start:l.pos], line)
+		case isDigit(c):
+			end := l.pos + 1
+			for end < len(l.src) && (isNamePart(l.src[end]) || l.src[end] == '.') {
+				end++
+			}
+
+			l.advance(end - l.pos)
+			l.emit(TokenNumber, start, l.src[start:l.pos], line)
+		default:
+			n := 1
+			for _, p := range phpPunctuators {
+				if strings.HasPrefix(rest, p) {
+					n = len(p)
+					break
+				}
+			}
+
+			l.advance(n)
+			l.emit(TokenPunctuator, start, l.src[start:l.pos], line)
+		}
+	}
+}
+
+// quotedLength returns the length of the quoted string starting at pos.
+func (l *lexer) quotedLength(pos int) int {
+	quote := l.src[pos]
+
+	for i := pos + 1; i < len(l.src); i++ {
+		switch l.src[i] {
+		case '\\':
+			i++
+		case quote:
+			return i - pos + 1
+		}
+	}
+
+	return len(l.src) - pos
+}
+
+// heredoc returns the body and the length of the heredoc or nowdoc at the
+// start of src.
+func heredoc(src string) (string, int) {
+	lineEnd := strings.IndexByte(src, '\n')
+	if lineEnd < 0 {
+		return "", len(src)
+	}
+
+	label := strings.Trim(strings.TrimSpace(src[3:lineEnd]), `'"`)
+	if label == "" {
+		return "", lineEnd
+	}
+
+	pos := lineEnd + 1
+	for pos <= len(src) {
+		next := strings.IndexByte(src[pos:], '\n')
+		lineText := src[pos:]
+		if next >= 0 {
+			lineText = src[pos : pos+next]
+		}
+
+		// Since PHP 7.3 the closing label may be indented and followed by code
+		if strings.HasPrefix(strings.TrimLeft(lineText, " \t"), label) {
+			rest := strings.TrimLeft(lineText, " \t")[len(label):]
+			if rest == "" || !isNamePart(rest[0]) {
+				indent := len(lineText) - len(strings.TrimLeft(lineText, " \t"))
+				return src[lineEnd+1 : pos], pos + indent + len(label)
+			}
+		}
+
+		if next < 0 {
+			break
+		}
+
+		pos += next + 1
+	}
+
+	return src[lineEnd+1:], len(src)
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,55 @@
+package php
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tokenValues(tokens []Token) []string {
+	values := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		values = append(values, tok.Value)
+	}
+
+	return values
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	tokens := Tokenize(`<?php
+// comment with 'quote'
+/** @internal */
+$a = \Foo\Bar::class ?? 'it\'s';
+# comment
+#[Route('/foo')]
+`)
+
+	assert.Equal(t, []string{"/** @internal */", "$a", "=", `\Foo\Bar`, "::", "class", "??", `it\'s`, ";", "#[", "Route", "(", "/foo", ")", "]"}, tokenValues(tokens))
+	assert.Equal(t, TokenDocComment, tokens[0].Kind)
+	assert.Equal(t, 3, tokens[0].Line)
+	assert.Equal(t, TokenName, tokens[3].Kind)
+	assert.Equal(t, TokenString, tokens[7].Kind)
+	assert.Equal(t, TokenAttributeStart, tokens[9].Kind)
+	assert.Equal(t, 6, tokens[9].Line)
+}
+
+func TestTokenizeHeredoc(t *testing.T) {
+	t.Parallel()
+
+	tokens := Tokenize("<?php\n$sql = <<<SQL\n    SELECT * FROM product\n    SQL;\n$b = 1;")
+
+	assert.Equal(t, []string{"$sql", "=", "    SELECT * FROM product\n", ";", "$b", "=", "1", ";"}, tokenValues(tokens))
+	assert.Equal(t, 5, tokens[4].Line)
+}
+
+func TestTokenizeInlineHTML(t *testing.T) {
+	t.Parallel()
+
+	tokens := Tokenize("<p><?= $name ?></p>")
+
+	assert.Equal(t, []string{"<p>", "$name", "</p>"}, tokenValues(tokens))
+	assert.Equal(t, TokenInlineHTML, tokens[0].Kind)
+	assert.Equal(t, TokenInlineHTML, tokens[2].Kind)
+}
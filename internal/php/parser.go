@@ -0,0 +1,467 @@
+package php
+
+import "strings"
+
+// File is a parsed PHP file. Only declarations are parsed, method bodies are
+// kept as token ranges for the callers to scan.
+type File struct {
+	Source    string
+	Tokens    []Token
+	Namespace string
+	Uses      []Use
+	Classes   []*Class
+}
+
+// Use is a class import, like use Foo\Bar as Baz.
+type Use struct {
+	Name  string
+	Alias string
+	Line  int
+}
+
+// Class is a class, interface, trait or enum declaration.
+type Class struct {
+	Kind       string
+	Name       string
+	Extends    []string
+	Implements []string
+	Doc        string
+	Attributes []Attribute
+	// Constants maps the constant names to their value, the value is only
+	// set for plain strings
+	Constants map[string]string
+	Methods   []*Method
+	Line      int
+	// BodyStart and BodyEnd are the indexes of the braces of the body
+	BodyStart int
+	BodyEnd   int
+}
+
+// Method is a function declared in a class.
+type Method struct {
+	Name       string
+	Doc        string
+	Attributes []Attribute
+	Params     []Param
+	Line       int
+	// BodyStart and BodyEnd are the indexes of the braces of the body, both
+	// are -1 for abstract methods
+	BodyStart int
+	BodyEnd   int
+}
+
+// Param is a method parameter. Type is the resolved class name of the last
+// type in a union.
+type Param struct {
+	Name       string
+	Type       string
+	Attributes []Attribute
+	Line       int
+}
+
+// Attribute is a PHP 8 attribute with the source of its arguments.
+type Attribute struct {
+	Name string
+	Args string
+	Line int
+}
+
+var builtinTypes = map[string]bool{
+	"array": true, "bool": true, "callable": true, "false": true, "float": true, "int": true, "iterable": true,
+	"mixed": true, "never": true, "null": true, "object": true, "parent": true, "self": true, "static": true,
+	"string": true, "true": true, "void": true,
+}
+
+// Parse tokenizes and parses the declarations of the source. It never
+// fails, unexpected code is skipped.
+func Parse(src string) *File {
+	f := &File{Source: src, Tokens: Tokenize(src)}
+	f.parse()
+
+	return f
+}
+
+// Resolve returns the fully qualified name of a class name used in the file.
+func (f *File) Resolve(name string) string {
+	if strings.HasPrefix(name, "\\") {
+		return name[1:]
+	}
+
+	if builtinTypes[strings.ToLower(name)] {
+		return name
+	}
+
+	first, rest, _ := strings.Cut(name, "\\")
+
+	for _, use := range f.Uses {
+		if strings.EqualFold(use.Alias, first) {
+			if rest == "" {
+				return use.Name
+			}
+
+			return use.Name + "\\" + rest
+		}
+	}
+
+	if f.Namespace == "" {
+		return name
+	}
+
+	return f.Namespace + "\\" + name
+}
+
+// FQCN returns the fully qualified name of the class.
+func (f *File) FQCN(c *Class) string {
+	if f.Namespace == "" {
+		return c.Name
+	}
+
+	return f.Namespace + "\\" + c.Name
+}
+
+// Matching returns the index of the bracket closing the one at index i.
+func (f *File) Matching(i int) int {
+	depth := 0
+
+	for j := i; j < len(f.Tokens); j++ {
+		tok := f.Tokens[j]
+		if tok.Kind == TokenAttributeStart {
+			depth++
+			continue
+		}
+
+		if tok.Kind != TokenPunctuator {
+			continue
+		}
+
+		switch tok.Value {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+
+	return len(f.Tokens) - 1
+}
+
+// IsPunctuator reports whether the token at index i is one of the values.
+func (f *File) IsPunctuator(i int, values ...string) bool {
+	if i < 0 || i >= len(f.Tokens) || f.Tokens[i].Kind != TokenPunctuator {
+		return false
+	}
+
+	for _, v := range values {
+		if f.Tokens[i].Value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsName reports whether the token at index i is the name, ignoring case.
+func (f *File) IsName(i int, name string) bool {
+	return i >= 0 && i < len(f.Tokens) && f.Tokens[i].Kind == TokenName && strings.EqualFold(f.Tokens[i].Value, name)
+}
+
+// Text returns the source of the tokens [start, end).
+func (f *File) Text(start, end int) string {
+	if start >= end || start < 0 || end > len(f.Tokens) {
+		return ""
+	}
+
+	return f.Source[f.Tokens[start].Start:f.Tokens[end-1].End]
+}
+
+func (f *File) parse() {
+	doc := ""
+	var attributes []Attribute
+
+	for i := 0; i < len(f.Tokens); i++ {
+		tok := f.Tokens[i]
+
+		switch {
+		case tok.Kind == TokenDocComment:
+			doc = tok.Value
+			continue
+		case tok.Kind == TokenAttributeStart:
+			var end int
+			attributes, end = f.parseAttributes(i, attributes)
+			i = end
+			continue
+		case f.IsName(i, "namespace") && !f.IsPunctuator(i+1, "\\") && i+1 < len(f.Tokens) && f.Tokens[i+1].Kind == TokenName:
+			f.Namespace = strings.TrimPrefix(f.Tokens[i+1].Value, "\\")
+			i++
+		case f.IsName(i, "use"):
+			i = f.parseUse(i)
+		case f.isClassKeyword(i):
+			if class, end := f.parseClass(i, doc, attributes); class != nil {
+				f.Classes = append(f.Classes, class)
+				i = end
+			}
+		case f.IsName(i, "final") || f.IsName(i, "abstract") || f.IsName(i, "readonly"):
+			continue
+		}
+
+		doc = ""
+		attributes = nil
+	}
+}
+
+func (f *File) isClassKeyword(i int) bool {
+	if !f.IsName(i, "class") && !f.IsName(i, "interface") && !f.IsName(i, "trait") && !f.IsName(i, "enum") {
+		return false
+	}
+
+	// Foo::class and new class
+	if f.IsPunctuator(i-1, "::") || f.IsName(i-1, "new") {
+		return false
+	}
+
+	return i+1 < len(f.Tokens) && f.Tokens[i+1].Kind == TokenName
+}
+
+// parseUse parses a use statement on the top level and returns the index of
+// its semicolon.
+func (f *File) parseUse(i int) int {
+	end := i
+	for end < len(f.Tokens) && !f.IsPunctuator(end, ";") {
+		end++
+	}
+
+	j := i + 1
+	if f.IsName(j, "function") || f.IsName(j, "const") {
+		return end
+	}
+
+	prefix := ""
+
+	for j < end {
+		tok := f.Tokens[j]
+
+		if tok.Kind != TokenName {
+			j++
+			continue
+		}
+
+		name := strings.TrimPrefix(tok.Value, "\\")
+
+		// Group use: use Foo\{Bar, Baz as Qux}
+		if f.IsPunctuator(j+1, "\\") {
+			prefix = name + "\\"
+			j += 2
+
+			continue
+		}
+
+		use := Use{Name: prefix + name, Line: tok.Line}
+		use.Alias = use.Name[strings.LastIndex(use.Name, "\\")+1:]
+
+		if f.IsName(j+1, "as") && j+2 < end {
+			use.Alias = f.Tokens[j+2].Value
+			j += 2
+		}
+
+		f.Uses = append(f.Uses, use)
+		j++
+	}
+
+	return end
+}
+
+// parseAttributes parses the attribute group starting at index i.
+func (f *File) parseAttributes(i int, attributes []Attribute) ([]Attribute, int) {
+	end := f.Matching(i)
+	j := i + 1
+
+	for j < end {
+		if f.Tokens[j].Kind != TokenName {
+			j++
+			continue
+		}
+
+		attr := Attribute{Name: f.Resolve(f.Tokens[j].Value), Line: f.Tokens[j].Line}
+		j++
+
+		if f.IsPunctuator(j, "(") {
+			closing := f.Matching(j)
+			attr.Args = f.Text(j+1, closing)
+			j = closing + 1
+		}
+
+		attributes = append(attributes, attr)
+
+		for j < end && !f.IsPunctuator(j, ",") {
+			j++
+		}
+	}
+
+	return attributes, end
+}
+
+// parseClass parses the class declared at index i and returns the index of
+// the closing brace of its body.
+func (f *File) parseClass(i int, doc string, attributes []Attribute) (*Class, int) {
+	class := &Class{
+		Kind:       strings.ToLower(f.Tokens[i].Value),
+		Name:       f.Tokens[i+1].Value,
+		Doc:        doc,
+		Attributes: attributes,
+		Constants:  map[string]string{},
+		Line:       f.Tokens[i].Line,
+	}
+
+	j := i + 2
+	var list *[]string
+
+	for ; j < len(f.Tokens) && !f.IsPunctuator(j, "{"); j++ {
+		switch {
+		case f.IsName(j, "extends"):
+			list = &class.Extends
+		case f.IsName(j, "implements"):
+			list = &class.Implements
+		case f.Tokens[j].Kind == TokenName && list != nil:
+			*list = append(*list, f.Resolve(f.Tokens[j].Value))
+		}
+	}
+
+	if j >= len(f.Tokens) {
+		return nil, i
+	}
+
+	class.BodyStart = j
+	class.BodyEnd = f.Matching(j)
+	f.parseMembers(class)
+
+	return class, class.BodyEnd
+}
+
+func (f *File) parseMembers(class *Class) {
+	doc := ""
+	var attributes []Attribute
+
+	for i := class.BodyStart + 1; i < class.BodyEnd; i++ {
+		tok := f.Tokens[i]
+
+		switch {
+		case tok.Kind == TokenDocComment:
+			doc = tok.Value
+			continue
+		case tok.Kind == TokenAttributeStart:
+			var end int
+			attributes, end = f.parseAttributes(i, attributes)
+			i = end
+			continue
+		case f.IsName(i, "const"):
+			end := i
+			for end < class.BodyEnd && !f.IsPunctuator(end, ";") {
+				end++
+			}
+
+			for j := i + 1; j < end; j++ {
+				if !f.IsPunctuator(j, "=") || f.Tokens[j-1].Kind != TokenName {
+					continue
+				}
+
+				value := ""
+				if f.Tokens[j+1].Kind == TokenString && f.IsPunctuator(j+2, ";", ",") {
+					value = f.Tokens[j+1].Value
+				}
+
+				class.Constants[f.Tokens[j-1].Value] = value
+			}
+
+			i = end
+		case f.IsName(i, "function") && i+1 < class.BodyEnd && f.Tokens[i+1].Kind == TokenName:
+			method, end := f.parseMethod(i, doc, attributes)
+			class.Methods = append(class.Methods, method)
+			i = end
+		case f.IsPunctuator(i, "{", "(", "["):
+			i = f.Matching(i)
+		case f.IsPunctuator(i, ";"):
+		default:
+			// modifiers and types keep the doc comment and attributes for the member
+			continue
+		}
+
+		doc = ""
+		attributes = nil
+	}
+}
+
+// parseMethod parses the method at index i and returns the index of the
+// last token of its declaration.
+func (f *File) parseMethod(i int, doc string, attributes []Attribute) (*Method, int) {
+	method := &Method{
+		Name:       f.Tokens[i+1].Value,
+		Doc:        doc,
+		Attributes: attributes,
+		Line:       f.Tokens[i+1].Line,
+		BodyStart:  -1,
+		BodyEnd:    -1,
+	}
+
+	j := i + 2
+	if !f.IsPunctuator(j, "(") {
+		return method, j
+	}
+
+	paramsEnd := f.Matching(j)
+	method.Params = f.parseParams(j+1, paramsEnd)
+
+	for j = paramsEnd + 1; j < len(f.Tokens); j++ {
+		if f.IsPunctuator(j, ";") {
+			return method, j
+		}
+
+		if f.IsPunctuator(j, "{") {
+			method.BodyStart = j
+			method.BodyEnd = f.Matching(j)
+
+			return method, method.BodyEnd
+		}
+	}
+
+	return method, len(f.Tokens) - 1
+}
+
+func (f *File) parseParams(start, end int) []Param {
+	var params []Param
+
+	param := Param{}
+
+	for j := start; j < end; j++ {
+		tok := f.Tokens[j]
+
+		switch {
+		case tok.Kind == TokenAttributeStart:
+			var closing int
+			param.Attributes, closing = f.parseAttributes(j, param.Attributes)
+			j = closing
+		case tok.Kind == TokenVariable && param.Name == "":
+			param.Name = tok.Value
+			param.Line = tok.Line
+		case tok.Kind == TokenName && param.Name == "":
+			switch strings.ToLower(tok.Value) {
+			case "public", "protected", "private", "readonly":
+			default:
+				param.Type = f.Resolve(tok.Value)
+			}
+		case f.IsPunctuator(j, "(", "["):
+			j = f.Matching(j)
+		case f.IsPunctuator(j, ","):
+			params = append(params, param)
+			param = Param{}
+		}
+	}
+
+	if param.Name != "" {
+		params = append(params, param)
+	}
+
+	return params
+}
@@ -0,0 +1,109 @@
+package php
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	f := Parse(`<?php declare(strict_types=1);
+
+namespace Acme\Controller;
+
+use Shopware\Core\Framework\DataAbstractionLayer\{EntityRepository, Search\Criteria as C};
+use Symfony\Component\Routing\Attribute\Route;
+use function sprintf;
+
+/**
+ * @internal
+ */
+#[Route(defaults: ['_routeScope' => ['storefront']])]
+final class FooController extends \Shopware\Storefront\Controller\StorefrontController implements Bar, \Countable
+{
+    public const string NAME = 'foo';
+    private const BAR = self::NAME, BAZ = 'baz';
+
+    public function __construct(
+        #[Autowire(service: 'product.repository')]
+        private readonly EntityRepository $productRepository,
+        ?C $criteria = null,
+        array $options = [],
+    ) {
+    }
+
+    /** Lists foos */
+    #[Route(path: '/foo', name: 'frontend.foo', methods: ['GET'])]
+    public function list(): Response
+    {
+        $fn = function () use ($criteria) { return new class {}; };
+    }
+
+    abstract protected function count(): int;
+}
+
+interface Bar {}
+`)
+
+	assert.Equal(t, "Acme\\Controller", f.Namespace)
+	assert.Equal(t, []Use{
+		{Name: "Shopware\\Core\\Framework\\DataAbstractionLayer\\EntityRepository", Alias: "EntityRepository", Line: 5},
+		{Name: "Shopware\\Core\\Framework\\DataAbstractionLayer\\Search\\Criteria", Alias: "C", Line: 5},
+		{Name: "Symfony\\Component\\Routing\\Attribute\\Route", Alias: "Route", Line: 6},
+	}, f.Uses)
+
+	require.Len(t, f.Classes, 2)
+
+	class := f.Classes[0]
+	assert.Equal(t, "class", class.Kind)
+	assert.Equal(t, "Acme\\Controller\\FooController", f.FQCN(class))
+	assert.Equal(t, []string{"Shopware\\Storefront\\Controller\\StorefrontController"}, class.Extends)
+	assert.Equal(t, []string{"Acme\\Controller\\Bar", "Countable"}, class.Implements)
+	assert.Contains(t, class.Doc, "@internal")
+	assert.Equal(t, []Attribute{{Name: "Symfony\\Component\\Routing\\Attribute\\Route", Args: "defaults: ['_routeScope' => ['storefront']]", Line: 12}}, class.Attributes)
+	assert.Equal(t, map[string]string{"NAME": "foo", "BAR": "", "BAZ": "baz"}, class.Constants)
+
+	require.Len(t, class.Methods, 3)
+
+	constructor := class.Methods[0]
+	assert.Equal(t, "__construct", constructor.Name)
+	require.Len(t, constructor.Params, 3)
+	assert.Equal(t, "$productRepository", constructor.Params[0].Name)
+	assert.Equal(t, "Shopware\\Core\\Framework\\DataAbstractionLayer\\EntityRepository", constructor.Params[0].Type)
+	assert.Equal(t, []Attribute{{Name: "Acme\\Controller\\Autowire", Args: "service: 'product.repository'", Line: 19}}, constructor.Params[0].Attributes)
+	assert.Equal(t, "Shopware\\Core\\Framework\\DataAbstractionLayer\\Search\\Criteria", constructor.Params[1].Type)
+	assert.Equal(t, "array", constructor.Params[2].Type)
+
+	list := class.Methods[1]
+	assert.Equal(t, "list", list.Name)
+	assert.Equal(t, "/** Lists foos */", list.Doc)
+	require.Len(t, list.Attributes, 1)
+	assert.Equal(t, 27, list.Attributes[0].Line)
+	assert.True(t, f.IsPunctuator(list.BodyStart, "{"))
+	assert.True(t, f.IsPunctuator(list.BodyEnd, "}"))
+	assert.Contains(t, f.Text(list.BodyStart, list.BodyEnd+1), "new class {}")
+
+	assert.Equal(t, "count", class.Methods[2].Name)
+	assert.Equal(t, -1, class.Methods[2].BodyStart)
+
+	assert.Equal(t, "interface", f.Classes[1].Kind)
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	f := Parse(`<?php
+namespace Acme;
+
+use Shopware\Core\Framework\Event as CoreEvent;
+`)
+
+	assert.Equal(t, "Shopware\\Core\\Framework\\Event\\NestedEvent", f.Resolve("CoreEvent\\NestedEvent"))
+	assert.Equal(t, "Shopware\\Core\\Framework\\Event", f.Resolve("coreevent"))
+	assert.Equal(t, "Acme\\Foo", f.Resolve("Foo"))
+	assert.Equal(t, "Foo", f.Resolve("\\Foo"))
+	assert.Equal(t, "self", f.Resolve("self"))
+}
@@ -0,0 +1,206 @@
+// Package secret resolves references to secrets, so credentials don't have
+// to be stored in plain text in configuration files or the environment.
+package secret
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	prefixEnv              = "env:"
+	prefixFile             = "file:"
+	prefixCmd              = "cmd:"
+	prefixCredentialHelper = "credential-helper:"
+	prefixLiteral          = "literal:"
+)
+
+// allowCommandsEnv has to be set to 1 to run cmd: references from the project file.
+const allowCommandsEnv = "SHOPWARE_CLI_ALLOW_SECRET_COMMANDS"
+
+type projectFileKey struct{}
+
+// FromProjectFile marks the values resolved with the returned context as read
+// from the project file. A project file is part of the checked out repository,
+// so its cmd: references are only run when SHOPWARE_CLI_ALLOW_SECRET_COMMANDS=1 is set.
+func FromProjectFile(ctx context.Context) context.Context {
+	return context.WithValue(ctx, projectFileKey{}, true)
+}
+
+func commandsAllowed(ctx context.Context) bool {
+	fromProjectFile, _ := ctx.Value(projectFileKey{}).(bool)
+
+	return !fromProjectFile || os.Getenv(allowCommandsEnv) == "1"
+}
+
+// IsReference reports whether the value refers to a secret instead of
+// containing it.
+func IsReference(value string) bool {
+	for _, prefix := range []string{prefixEnv, prefixFile, prefixCmd, prefixCredentialHelper} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve returns the secret the value refers to. Supported are
+//
+//	env:NAME                  the environment variable NAME
+//	file:path                 the content of the file
+//	cmd:command               the output of the command, e.g. cmd:pass show shop/staging
+//	credential-helper:name    the secret stored for server by a credential helper
+//	literal:value             value itself, for secrets starting with one of the prefixes
+//
+// Other values are returned unchanged.
+func Resolve(ctx context.Context, value, server string) (string, error) {
+	return resolve(ctx, value, server, false)
+}
+
+// ResolveIdentifier resolves a client id or username like Resolve, but a
+// credential helper returns the username stored for server instead of the secret.
+func ResolveIdentifier(ctx context.Context, value, server string) (string, error) {
+	return resolve(ctx, value, server, true)
+}
+
+func resolve(ctx context.Context, value, server string, identifier bool) (string, error) {
+	switch {
+	case strings.HasPrefix(value, prefixLiteral):
+		return strings.TrimPrefix(value, prefixLiteral), nil
+	case strings.HasPrefix(value, prefixEnv):
+		name := strings.TrimPrefix(value, prefixEnv)
+
+		resolved, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("secret %s: environment variable %s is not set", value, name)
+		}
+
+		return resolved, nil
+	case strings.HasPrefix(value, prefixFile):
+		path, err := expandHome(strings.TrimPrefix(value, prefixFile))
+		if err != nil {
+			return "", err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("secret %s: %w", value, err)
+		}
+
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case strings.HasPrefix(value, prefixCmd):
+		if !commandsAllowed(ctx) {
+			return "", fmt.Errorf("secret %s: commands from the project file are only run when %s=1 is set", value, allowCommandsEnv)
+		}
+
+		output, err := runCommand(ctx, strings.TrimPrefix(value, prefixCmd))
+		if err != nil {
+			return "", fmt.Errorf("secret %s: %w", value, err)
+		}
+
+		return strings.TrimRight(output, "\r\n"), nil
+	case strings.HasPrefix(value, prefixCredentialHelper):
+		credentials, err := GetFromCredentialHelper(ctx, strings.TrimPrefix(value, prefixCredentialHelper), server)
+		if err != nil {
+			return "", fmt.Errorf("secret %s: %w", value, err)
+		}
+
+		if identifier {
+			return credentials.Username, nil
+		}
+
+		return credentials.Secret, nil
+	}
+
+	return value, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+func runCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return string(output), nil
+}
+
+// Credentials are returned by a credential helper.
+type Credentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// GetFromCredentialHelper asks the credential helper for the credentials of
+// the server. The helpers follow the protocol of the docker credential
+// helpers: the binary is called with get, the server is written to stdin and
+// the credentials are read as JSON from stdout. The binary
+// shopware-cli-credential-<name> is preferred, existing
+// docker-credential-<name> helpers can be used as well.
+func GetFromCredentialHelper(ctx context.Context, name, server string) (*Credentials, error) {
+	if name == "" {
+		return nil, fmt.Errorf("credential helper name is empty")
+	}
+
+	binary, err := exec.LookPath("shopware-cli-credential-" + name)
+	if err != nil {
+		binary, err = exec.LookPath("docker-credential-" + name)
+		if err != nil {
+			return nil, fmt.Errorf("credential helper shopware-cli-credential-%s not found in PATH", name)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, binary, "get")
+	cmd.Stdin = strings.NewReader(server)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(string(output))
+		}
+
+		return nil, fmt.Errorf("credential helper %s: %s", name, message)
+	}
+
+	var credentials Credentials
+	if err := json.Unmarshal(output, &credentials); err != nil {
+		return nil, fmt.Errorf("credential helper %s: invalid output: %w", name, err)
+	}
+
+	return &credentials, nil
+}
@@ -0,0 +1,112 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePlainValue(t *testing.T) {
+	t.Parallel()
+
+	value, err := Resolve(t.Context(), "plain-secret", "")
+	require.NoError(t, err)
+	assert.Equal(t, "plain-secret", value)
+	assert.False(t, IsReference("plain-secret"))
+}
+
+func TestResolveEnv(t *testing.T) {
+	t.Setenv("SECRET_TEST_VALUE", "from-env")
+
+	value, err := Resolve(t.Context(), "env:SECRET_TEST_VALUE", "")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", value)
+
+	_, err = Resolve(t.Context(), "env:SECRET_TEST_MISSING", "")
+	assert.ErrorContains(t, err, "SECRET_TEST_MISSING is not set")
+}
+
+func TestResolveFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	value, err := Resolve(t.Context(), "file:"+path, "")
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", value)
+
+	_, err = Resolve(t.Context(), "file:"+path+".missing", "")
+	assert.Error(t, err)
+}
+
+func TestResolveCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	t.Parallel()
+
+	value, err := Resolve(t.Context(), "cmd:echo from-cmd", "")
+	require.NoError(t, err)
+	assert.Equal(t, "from-cmd", value)
+
+	_, err = Resolve(t.Context(), "cmd:echo failed >&2; exit 1", "")
+	assert.ErrorContains(t, err, "failed")
+}
+
+func TestResolveCmdFromProjectFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	ctx := FromProjectFile(t.Context())
+
+	t.Setenv("SHOPWARE_CLI_ALLOW_SECRET_COMMANDS", "")
+	_, err := Resolve(ctx, "cmd:echo from-cmd", "")
+	assert.ErrorContains(t, err, "only run when SHOPWARE_CLI_ALLOW_SECRET_COMMANDS=1 is set")
+
+	t.Setenv("SHOPWARE_CLI_ALLOW_SECRET_COMMANDS", "1")
+	value, err := Resolve(ctx, "cmd:echo from-cmd", "")
+	require.NoError(t, err)
+	assert.Equal(t, "from-cmd", value)
+}
+
+func TestResolveLiteral(t *testing.T) {
+	t.Parallel()
+
+	value, err := Resolve(t.Context(), "literal:cmd:rm -rf /", "")
+	require.NoError(t, err)
+	assert.Equal(t, "cmd:rm -rf /", value)
+	assert.False(t, IsReference("literal:env:NAME"))
+}
+
+func TestResolveCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as helper")
+	}
+
+	dir := t.TempDir()
+	helper := `#!/bin/sh
+[ "$1" = "get" ] || exit 1
+read server
+echo "{\"ServerURL\":\"$server\",\"Username\":\"admin\",\"Secret\":\"secret-for-$server\"}"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shopware-cli-credential-test"), []byte(helper), 0o700))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	value, err := Resolve(t.Context(), "credential-helper:test", "https://shop.example")
+	require.NoError(t, err)
+	assert.Equal(t, "secret-for-https://shop.example", value)
+
+	value, err = ResolveIdentifier(t.Context(), "credential-helper:test", "https://shop.example")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", value)
+
+	_, err = Resolve(t.Context(), "credential-helper:missing", "https://shop.example")
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,100 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	encryptedFileMagic = "swcli-enc-v1\n"
+	saltSize           = 16
+	keyIterations      = 600_000
+)
+
+// WriteEncryptedFile encrypts the content with a key derived from the
+// passphrase using AES-GCM and writes it with permissions only for the
+// current user.
+func WriteEncryptedFile(path, passphrase string, content []byte) error {
+	if passphrase == "" {
+		return fmt.Errorf("passphrase for %s is empty", path)
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data := []byte(encryptedFileMagic)
+	data = append(data, salt...)
+	data = append(data, nonce...)
+	data = gcm.Seal(data, nonce, content, []byte(encryptedFileMagic))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// ReadEncryptedFile reads a file written by WriteEncryptedFile.
+func ReadEncryptedFile(path, passphrase string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < len(encryptedFileMagic)+saltSize || string(data[:len(encryptedFileMagic)]) != encryptedFileMagic {
+		return nil, fmt.Errorf("%s is not an encrypted file", path)
+	}
+
+	data = data[len(encryptedFileMagic):]
+	salt, data := data[:saltSize], data[saltSize:]
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("%s is truncated", path)
+	}
+
+	nonce, data := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+
+	content, err := gcm.Open(nil, nonce, data, []byte(encryptedFileMagic))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt %s, is the passphrase correct?", path)
+	}
+
+	return content, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, keyIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,31 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "token.enc")
+
+	require.NoError(t, WriteEncryptedFile(path, "passphrase", []byte("token")))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "token")
+
+	content, err := ReadEncryptedFile(path, "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, "token", string(content))
+
+	_, err = ReadEncryptedFile(path, "wrong")
+	assert.ErrorContains(t, err, "cannot decrypt")
+
+	assert.Error(t, WriteEncryptedFile(path, "", []byte("token")))
+}
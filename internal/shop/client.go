@@ -8,30 +8,64 @@ import (
 	"os"
 
 	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+	"github.com/shopware/shopware-cli/internal/secret"
 )
 
-func newShopCredentials(config *Config) (adminSdk.OAuthCredentials, error) {
+// newShopCredentials builds the credentials from the environment or the
+// config. The values can be secret references, see secret.Resolve, they are
+// resolved only here when a client is needed.
+func newShopCredentials(ctx context.Context, config *Config, shopUrl string) (adminSdk.OAuthCredentials, error) {
 	clientId, clientSecret := os.Getenv("SHOPWARE_CLI_API_CLIENT_ID"), os.Getenv("SHOPWARE_CLI_API_CLIENT_SECRET")
 
 	if clientId != "" && clientSecret != "" {
-		return adminSdk.NewIntegrationCredentials(clientId, clientSecret, []string{"write"}), nil
+		return newIntegrationCredentials(ctx, shopUrl, clientId, clientSecret)
 	}
 
 	username, password := os.Getenv("SHOPWARE_CLI_API_USERNAME"), os.Getenv("SHOPWARE_CLI_API_PASSWORD")
 
 	if username != "" && password != "" {
-		return adminSdk.NewPasswordCredentials(username, password, []string{"write"}), nil
+		return newPasswordCredentials(ctx, shopUrl, username, password)
 	}
 
 	if config.AdminApi == nil {
 		return nil, fmt.Errorf("admin-api is not enabled in config")
 	}
 
+	ctx = secret.FromProjectFile(ctx)
+
 	if config.AdminApi.Username != "" {
-		return adminSdk.NewPasswordCredentials(config.AdminApi.Username, config.AdminApi.Password, []string{"write"}), nil
+		return newPasswordCredentials(ctx, shopUrl, config.AdminApi.Username, config.AdminApi.Password)
+	}
+
+	return newIntegrationCredentials(ctx, shopUrl, config.AdminApi.ClientId, config.AdminApi.ClientSecret)
+}
+
+func newIntegrationCredentials(ctx context.Context, shopUrl, clientId, clientSecret string) (adminSdk.OAuthCredentials, error) {
+	clientId, err := secret.ResolveIdentifier(ctx, clientId, shopUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	clientSecret, err = secret.Resolve(ctx, clientSecret, shopUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	return adminSdk.NewIntegrationCredentials(clientId, clientSecret, []string{"write"}), nil
+}
+
+func newPasswordCredentials(ctx context.Context, shopUrl, username, password string) (adminSdk.OAuthCredentials, error) {
+	username, err := secret.ResolveIdentifier(ctx, username, shopUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err = secret.Resolve(ctx, password, shopUrl)
+	if err != nil {
+		return nil, err
 	}
 
-	return adminSdk.NewIntegrationCredentials(config.AdminApi.ClientId, config.AdminApi.ClientSecret, []string{"write"}), nil
+	return adminSdk.NewPasswordCredentials(username, password, []string{"write"}), nil
 }
 
 func NewShopClient(ctx context.Context, config *Config) (*adminSdk.Client, error) {
@@ -59,7 +93,7 @@ func NewShopClient(ctx context.Context, config *Config) (*adminSdk.Client, error
 		shopUrl = config.URL
 	}
 
-	creds, err := newShopCredentials(config)
+	creds, err := newShopCredentials(ctx, config, shopUrl)
 	if err != nil {
 		return nil, fmt.Errorf("newShopCredentials: %v", err)
 	}
@@ -4,9 +4,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
 )
 
 func newTestServer(t *testing.T) *httptest.Server {
@@ -47,7 +53,7 @@ func Test_newShopCredentials_envIntegration(t *testing.T) {
 	t.Setenv("SHOPWARE_CLI_API_CLIENT_SECRET", "secret")
 
 	cfg := &Config{}
-	creds, err := newShopCredentials(cfg)
+	creds, err := newShopCredentials(t.Context(), cfg, "http://localhost")
 	assert.NoError(t, err)
 	assert.NotNil(t, creds)
 }
@@ -57,7 +63,7 @@ func Test_newShopCredentials_envPassword(t *testing.T) {
 	t.Setenv("SHOPWARE_CLI_API_PASSWORD", "pass")
 
 	cfg := &Config{}
-	creds, err := newShopCredentials(cfg)
+	creds, err := newShopCredentials(t.Context(), cfg, "http://localhost")
 	assert.NoError(t, err)
 	assert.NotNil(t, creds)
 }
@@ -75,7 +81,7 @@ func Test_newShopCredentials_configPassword(t *testing.T) {
 			Password: "pass",
 		},
 	}
-	creds, err := newShopCredentials(cfg)
+	creds, err := newShopCredentials(t.Context(), cfg, "http://localhost")
 	assert.NoError(t, err)
 	assert.NotNil(t, creds)
 }
@@ -93,11 +99,33 @@ func Test_newShopCredentials_configIntegration(t *testing.T) {
 			ClientSecret: "secret",
 		},
 	}
-	creds, err := newShopCredentials(cfg)
+	creds, err := newShopCredentials(t.Context(), cfg, "http://localhost")
 	assert.NoError(t, err)
 	assert.NotNil(t, creds)
 }
 
+func Test_newShopCredentials_configSecretReference(t *testing.T) {
+	t.Setenv("SHOPWARE_CLI_API_CLIENT_ID", "")
+	t.Setenv("SHOPWARE_CLI_API_CLIENT_SECRET", "")
+	t.Setenv("SHOPWARE_CLI_API_USERNAME", "")
+	t.Setenv("SHOPWARE_CLI_API_PASSWORD", "")
+	t.Setenv("SHOP_STAGING_SECRET", "resolved-secret")
+
+	cfg := &Config{
+		AdminApi: &ConfigAdminApi{
+			ClientId:     "id",
+			ClientSecret: "env:SHOP_STAGING_SECRET",
+		},
+	}
+	creds, err := newShopCredentials(t.Context(), cfg, "http://localhost")
+	assert.NoError(t, err)
+	assert.Equal(t, adminSdk.NewIntegrationCredentials("id", "resolved-secret", []string{"write"}), creds)
+
+	cfg.AdminApi.ClientSecret = "env:SHOP_MISSING_SECRET"
+	_, err = newShopCredentials(t.Context(), cfg, "http://localhost")
+	assert.ErrorContains(t, err, "SHOP_MISSING_SECRET is not set")
+}
+
 func Test_newShopCredentials_noConfig(t *testing.T) {
 	// Ensure clean env state
 	t.Setenv("SHOPWARE_CLI_API_CLIENT_ID", "")
@@ -106,7 +134,7 @@ func Test_newShopCredentials_noConfig(t *testing.T) {
 	t.Setenv("SHOPWARE_CLI_API_PASSWORD", "")
 
 	cfg := &Config{}
-	_, err := newShopCredentials(cfg)
+	_, err := newShopCredentials(t.Context(), cfg, "http://localhost")
 	assert.Error(t, err)
 }
 
@@ -207,3 +235,27 @@ func Test_NewShopClient_CredentialsError(t *testing.T) {
 	_, err := NewShopClient(t.Context(), cfg)
 	assert.Error(t, err)
 }
+
+func Test_newShopCredentials_credentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as helper")
+	}
+
+	dir := t.TempDir()
+	helper := `#!/bin/sh
+read server
+echo "{\"ServerURL\":\"$server\",\"Username\":\"admin\",\"Secret\":\"shopware\"}"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shopware-cli-credential-test"), []byte(helper), 0o700))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &Config{AdminApi: &ConfigAdminApi{Username: "credential-helper:test", Password: "credential-helper:test"}}
+	creds, err := newShopCredentials(t.Context(), cfg, "http://localhost")
+	require.NoError(t, err)
+	assert.Equal(t, adminSdk.NewPasswordCredentials("admin", "shopware", []string{"write"}), creds)
+
+	cfg = &Config{AdminApi: &ConfigAdminApi{ClientId: "credential-helper:test", ClientSecret: "credential-helper:test"}}
+	creds, err = newShopCredentials(t.Context(), cfg, "http://localhost")
+	require.NoError(t, err)
+	assert.Equal(t, adminSdk.NewIntegrationCredentials("admin", "shopware", []string{"write"}), creds)
+}
@@ -1,6 +1,15 @@
 package shop
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shyim/go-composer"
+
+	"github.com/shopware/shopware-cli/internal/compatibility"
+)
 
 const (
 	CompatibilityDevMode = "2026-03-01"
@@ -25,3 +34,75 @@ type CompatibilityError struct {
 func (e *CompatibilityError) Error() string {
 	return fmt.Sprintf("%s, requires compatibility date: %s. see https://developer.shopware.com/docs/products/cli/project-commands/build.html#compatibility-date for more", e.Message, e.date)
 }
+
+func init() {
+	compatibility.Register(compatibility.Change{
+		Date:        CompatibilityDevMode,
+		Target:      compatibility.TargetProject,
+		Title:       "Development mode",
+		Description: "project dev starts the environment configured in environments.local and sets up the shop with shopware/deployment-helper.",
+		Detect:      detectDeploymentHelper,
+		Migrate:     migrateDeploymentHelper,
+	})
+}
+
+func detectDeploymentHelper(_ context.Context, projectRoot string) ([]compatibility.Finding, error) {
+	composerPath := filepath.Join(projectRoot, "composer.json")
+	if _, err := os.Stat(composerPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	cj, err := composer.ReadJson(composerPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if cj.HasPackage("shopware/deployment-helper") || cj.HasPackageDev("shopware/deployment-helper") {
+		return nil, nil
+	}
+
+	return []compatibility.Finding{{Path: "composer.json", Message: "require shopware/deployment-helper"}}, nil
+}
+
+func migrateDeploymentHelper(_ context.Context, projectRoot string) ([]string, error) {
+	changed, err := EnsureDeploymentHelper(projectRoot)
+	if err != nil || !changed {
+		return nil, err
+	}
+
+	return []string{"Added shopware/deployment-helper to composer.json, run composer update shopware/deployment-helper to install it"}, nil
+}
+
+// EnsureDeploymentHelper adds shopware/deployment-helper to the project's
+// composer.json require block when it's missing. New projects created via
+// `shopware-cli project create` pin this package; older projects being
+// migrated to dev mode need it added so devtui can run
+// `vendor/bin/shopware-deployment-helper`.
+//
+// Returns true when composer.json was changed and the user should re-run
+// `composer install` (or `composer update`) to pull the package in.
+// Errors reading or writing composer.json are returned to the caller;
+// a missing composer.json is treated as nothing-to-do (returns false, nil).
+func EnsureDeploymentHelper(projectRoot string) (changed bool, err error) {
+	composerPath := filepath.Join(projectRoot, "composer.json")
+	if _, statErr := os.Stat(composerPath); statErr != nil {
+		if os.IsNotExist(statErr) {
+			return false, nil
+		}
+		return false, statErr
+	}
+
+	cj, err := composer.ReadJson(composerPath)
+	if err != nil {
+		return false, err
+	}
+
+	if !cj.EnsurePackage("shopware/deployment-helper", "*") {
+		return false, nil
+	}
+
+	if err := cj.Save(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
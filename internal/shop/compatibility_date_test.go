@@ -0,0 +1,87 @@
+package shop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/compatibility"
+)
+
+func TestEnsureDeploymentHelper_AddsWhenMissing(t *testing.T) {
+	dir := t.TempDir()
+	composer := `{
+  "name": "shopware/production",
+  "require": {
+    "shopware/core": "^6.6"
+  }
+}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(composer), 0o644))
+
+	changed, err := EnsureDeploymentHelper(dir)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	// Verify it was actually written
+	out, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `"shopware/deployment-helper": "*"`)
+}
+
+func TestEnsureDeploymentHelper_NoOpWhenAlreadyInRequire(t *testing.T) {
+	dir := t.TempDir()
+	composer := `{
+  "name": "shopware/production",
+  "require": {
+    "shopware/core": "^6.6",
+    "shopware/deployment-helper": "^1.0"
+  }
+}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(composer), 0o644))
+
+	changed, err := EnsureDeploymentHelper(dir)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// Existing pin must not be overwritten
+	out, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `"shopware/deployment-helper": "^1.0"`)
+}
+
+func TestEnsureDeploymentHelper_NoOpWhenInRequireDev(t *testing.T) {
+	dir := t.TempDir()
+	composer := `{
+  "name": "shopware/production",
+  "require": {"shopware/core": "^6.6"},
+  "require-dev": {"shopware/deployment-helper": "^1.0"}
+}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "composer.json"), []byte(composer), 0o644))
+
+	changed, err := EnsureDeploymentHelper(dir)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestEnsureDeploymentHelper_MissingComposerJson(t *testing.T) {
+	changed, err := EnsureDeploymentHelper(t.TempDir())
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestDevModeChangeIsRegistered(t *testing.T) {
+	cfg := &Config{CompatibilityDate: "2026-02-11"}
+
+	pending, err := compatibility.Pending(compatibility.TargetProject, cfg.IsCompatibilityDateAtLeast, "2026-06-01")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, CompatibilityDevMode, pending[0].Date)
+
+	cfg.CompatibilityDate = CompatibilityDevMode
+	pending, err = compatibility.Pending(compatibility.TargetProject, cfg.IsCompatibilityDateAtLeast, "2026-06-01")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
@@ -36,7 +36,11 @@ type Config struct {
 	ConfigDump        *ConfigDump       `yaml:"dump,omitempty"`
 	ConfigDeployment  *ConfigDeployment `yaml:"deployment,omitempty"`
 	Validation        *ConfigValidation `yaml:"validation,omitempty"`
-	ImageProxy        *ConfigImageProxy `yaml:"image_proxy,omitempty"`
+	// Vulnerability audit of the composer and npm dependencies
+	Audit *ConfigAudit `yaml:"audit,omitempty"`
+	// License policy of the composer and npm dependencies
+	LicensePolicy *ConfigLicensePolicy `yaml:"license_policy,omitempty"`
+	ImageProxy    *ConfigImageProxy    `yaml:"image_proxy,omitempty"`
 	// Docker dev environment configuration
 	Docker *ConfigDocker `yaml:"docker,omitempty"`
 	// Named environments for multi-environment management
@@ -119,6 +123,8 @@ type ConfigBuild struct {
 	MJML *ConfigBuildMJML `yaml:"mjml,omitempty"`
 	// When enabled, built assets are cached and restored on subsequent builds when sources haven't changed
 	AssetCaching bool `yaml:"asset_caching,omitempty"`
+	// When enabled, outputs of project ci steps like vendor/ are cached and the steps are skipped when their inputs haven't changed
+	StepCaching bool `yaml:"step_caching,omitempty"`
 	// Hooks to run at specific points during CI builds
 	Hooks *ConfigBuildHooks `yaml:"hooks,omitempty"`
 	// Shopware bundles to include in builds (alternative to composer.json extra.shopware-bundles)
@@ -147,6 +153,16 @@ type ConfigBuildHooks struct {
 	PreAssets []string `yaml:"pre-assets,omitempty"`
 	// Commands to run after asset build
 	PostAssets []string `yaml:"post-assets,omitempty"`
+	// Commands to run before and after a project ci step, keyed by the step name (composer, sbom, audit, assets, optimize, warmup, mjml, remove-extension-assets, checksums)
+	Steps map[string]ConfigBuildStepHooks `yaml:"steps,omitempty"`
+}
+
+// ConfigBuildStepHooks defines hooks to run around a single project ci step.
+type ConfigBuildStepHooks struct {
+	// Commands to run before the step
+	Pre []string `yaml:"pre,omitempty"`
+	// Commands to run after the step
+	Post []string `yaml:"post,omitempty"`
 }
 
 func (c ConfigBuild) IsMjmlEnabled() bool {
@@ -223,14 +239,15 @@ func (c ConfigBuildMJML) ResolveIncludePaths(projectRoot string) []string {
 	return resolved
 }
 
+// ConfigAdminApi holds the Admin API credentials, prefix a plain value with literal: when it starts like a secret reference.
 type ConfigAdminApi struct {
-	// Client ID of integration
+	// Client ID of integration, can be a reference like env:NAME, file:path, cmd:command (requires SHOPWARE_CLI_ALLOW_SECRET_COMMANDS=1) or credential-helper:name which returns the stored username
 	ClientId string `yaml:"client_id,omitempty"`
-	// Client Secret of integration
+	// Client Secret of integration, can be a reference like env:NAME, file:path, cmd:command (requires SHOPWARE_CLI_ALLOW_SECRET_COMMANDS=1) or credential-helper:name
 	ClientSecret string `yaml:"client_secret,omitempty"`
-	// Username of admin user
+	// Username of admin user, can be a reference like env:NAME, file:path, cmd:command (requires SHOPWARE_CLI_ALLOW_SECRET_COMMANDS=1) or credential-helper:name which returns the stored username
 	Username string `yaml:"username,omitempty"`
-	// Password of admin user
+	// Password of admin user, can be a reference like env:NAME, file:path, cmd:command (requires SHOPWARE_CLI_ALLOW_SECRET_COMMANDS=1) or credential-helper:name
 	Password string `yaml:"password,omitempty"`
 	// Disable SSL certificate check
 	DisableSSLCheck bool `yaml:"disable_ssl_check,omitempty"`
@@ -547,6 +564,10 @@ type ConfigValidation struct {
 
 	IgnoreExtensions []ConfigValidationIgnoreExtension `yaml:"ignore_extensions,omitempty"`
 
+	// Severity overrides the severity of results by identifier, like twig-linter/a11y-positive-tabindex: error.
+	// An identifier ending with * matches all identifiers with that prefix, off drops the results.
+	Severity map[string]string `yaml:"severity,omitempty"`
+
 	// PhpVersion overrides the PHP version used for linting (e.g. "8.4").
 	// When set, this takes precedence over the version derived from composer.json or the static Shopware-to-PHP mapping.
 	PhpVersion string `yaml:"php_version,omitempty"`
@@ -567,6 +588,51 @@ type ConfigValidationIgnoreExtension struct {
 	Name string `yaml:"name"`
 }
 
+// ConfigAudit is used to configure the vulnerability audit.
+type ConfigAudit struct {
+	// Path to the advisory database, a checkout of FriendsOfPHP/security-advisories or github/advisory-database, or a single advisory file.
+	// Relative paths are resolved against the project root.
+	Database string `yaml:"database,omitempty"`
+	// Include the dev dependencies of composer.lock and package-lock.json files.
+	IncludeDev bool `yaml:"include_dev,omitempty"`
+	// Advisories to ignore.
+	Ignore []ConfigAuditIgnore `yaml:"ignore,omitempty"`
+}
+
+// ConfigAuditIgnore is used to ignore an advisory until a date.
+type ConfigAuditIgnore struct {
+	// The ID of the advisory, like a CVE or GHSA identifier.
+	ID string `yaml:"id"`
+	// Only ignore the advisory for this package.
+	Package string `yaml:"package,omitempty"`
+	// The last day the advisory is ignored, formatted as YYYY-MM-DD.
+	Until string `yaml:"until,omitempty" jsonschema:"format=date"`
+	// Why the advisory is ignored.
+	Reason string `yaml:"reason,omitempty"`
+}
+
+// ConfigLicensePolicy is used to configure the licenses allowed in the shop.
+type ConfigLicensePolicy struct {
+	// SPDX license identifiers which are allowed, a trailing * matches a prefix like GPL-*. When empty, every license not denied is allowed.
+	Allow []string `yaml:"allow,omitempty"`
+	// SPDX license identifiers which are denied, a trailing * matches a prefix like AGPL-*.
+	Deny []string `yaml:"deny,omitempty"`
+	// Packages which are excepted from the policy.
+	Exceptions []ConfigLicensePolicyException `yaml:"exceptions,omitempty"`
+	// Include the dev dependencies of composer.lock and package-lock.json files.
+	IncludeDev bool `yaml:"include_dev,omitempty"`
+}
+
+// ConfigLicensePolicyException is used to except a package from the license policy.
+type ConfigLicensePolicyException struct {
+	// The package name, a trailing * matches a prefix like shopware/*.
+	Package string `yaml:"package"`
+	// Only except this SPDX license of the package, empty excepts every license.
+	License string `yaml:"license,omitempty"`
+	// Why the package is excepted.
+	Reason string `yaml:"reason,omitempty"`
+}
+
 type ConfigDocker struct {
 	// PHP configuration for the Docker dev image
 	PHP *ConfigDockerPHP `yaml:"php,omitempty"`
@@ -0,0 +1,223 @@
+package shop
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// The states of deployment.extension-management.overrides. Extensions without
+// override are installed, activated and updated.
+const (
+	ExtensionStateInactive  = "inactive"
+	ExtensionStateRemove    = "remove"
+	ExtensionStateIgnore    = "ignore"
+	ExtensionStateInstalled = "installed"
+)
+
+// ExtensionActionType is a lifecycle call of the extension manager.
+type ExtensionActionType string
+
+const (
+	ExtensionActionDownload   ExtensionActionType = "download"
+	ExtensionActionInstall    ExtensionActionType = "install"
+	ExtensionActionUpdate     ExtensionActionType = "update"
+	ExtensionActionActivate   ExtensionActionType = "activate"
+	ExtensionActionDeactivate ExtensionActionType = "deactivate"
+	ExtensionActionUninstall  ExtensionActionType = "uninstall"
+	ExtensionActionRemove     ExtensionActionType = "remove"
+)
+
+// DesiredExtensionState is the extension state a shop is reconciled to. It
+// has the format of deployment.extension-management.
+type DesiredExtensionState struct {
+	// Extensions which are not managed
+	Exclude []string `yaml:"exclude"`
+	// The state of single extensions
+	Overrides ConfigDeploymentOverrides `yaml:"overrides"`
+}
+
+// ExtensionAction is a single lifecycle call of the plan.
+type ExtensionAction struct {
+	Extension string              `json:"extension"`
+	Type      string              `json:"type"`
+	Action    ExtensionActionType `json:"action"`
+}
+
+// ExtensionStatePlan lists the lifecycle calls in the order they are run.
+type ExtensionStatePlan struct {
+	Actions []ExtensionAction `json:"actions"`
+	// Extensions with an override the shop does not know
+	Missing []string `json:"missing,omitempty"`
+}
+
+// DesiredExtensionStateFromConfig returns the extension management of the deployment config.
+func DesiredExtensionStateFromConfig(cfg *Config) DesiredExtensionState {
+	if cfg.ConfigDeployment == nil {
+		return DesiredExtensionState{}
+	}
+
+	return DesiredExtensionState{
+		Exclude:   cfg.ConfigDeployment.ExtensionManagement.Exclude,
+		Overrides: cfg.ConfigDeployment.ExtensionManagement.Overrides,
+	}
+}
+
+// ReadDesiredExtensionState reads a dedicated state file with exclude and overrides.
+func ReadDesiredExtensionState(path string) (DesiredExtensionState, error) {
+	var state DesiredExtensionState
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+
+	if err := yaml.Unmarshal(content, &state); err != nil {
+		return state, fmt.Errorf("cannot read extension state %s: %w", path, err)
+	}
+
+	for name, override := range state.Overrides {
+		if !slices.Contains([]string{ExtensionStateInactive, ExtensionStateRemove, ExtensionStateIgnore, ExtensionStateInstalled}, override.State) {
+			return state, fmt.Errorf("extension %s has the unknown state %q", name, override.State)
+		}
+	}
+
+	return state, nil
+}
+
+// PlanExtensionState diffs the extensions of the shop against the desired state.
+// Removals run first with dependents before their dependencies, then
+// installations and updates with dependencies first.
+func PlanExtensionState(installed adminSdk.ExtensionList, desired DesiredExtensionState, order []string) *ExtensionStatePlan {
+	plan := &ExtensionStatePlan{Actions: []ExtensionAction{}}
+
+	for name, override := range desired.Overrides {
+		if override.State != ExtensionStateIgnore && override.State != ExtensionStateRemove && installed.GetByName(name) == nil {
+			plan.Missing = append(plan.Missing, name)
+		}
+	}
+
+	slices.Sort(plan.Missing)
+
+	var teardown, setup []ExtensionAction
+
+	for _, name := range slices.Backward(order) {
+		if ext := installed.GetByName(name); ext != nil && !slices.Contains(desired.Exclude, name) {
+			teardown = append(teardown, teardownActions(ext, desired.Overrides[name].State)...)
+		}
+	}
+
+	for _, name := range order {
+		if ext := installed.GetByName(name); ext != nil && !slices.Contains(desired.Exclude, name) {
+			setup = append(setup, setupActions(ext, desired.Overrides[name].State)...)
+		}
+	}
+
+	plan.Actions = append(plan.Actions, teardown...)
+	plan.Actions = append(plan.Actions, setup...)
+
+	return plan
+}
+
+func teardownActions(ext *adminSdk.ExtensionDetail, state string) []ExtensionAction {
+	var actions []ExtensionActionType
+
+	switch {
+	case state == ExtensionStateRemove:
+		if ext.Active {
+			actions = append(actions, ExtensionActionDeactivate)
+		}
+
+		if ext.InstalledAt != nil {
+			actions = append(actions, ExtensionActionUninstall)
+		}
+
+		if ext.Source == "local" {
+			actions = append(actions, ExtensionActionRemove)
+		}
+	case state == ExtensionStateInactive && ext.Active:
+		actions = append(actions, ExtensionActionDeactivate)
+	}
+
+	return newExtensionActions(ext, actions)
+}
+
+func setupActions(ext *adminSdk.ExtensionDetail, state string) []ExtensionAction {
+	if state == ExtensionStateRemove || state == ExtensionStateIgnore {
+		return nil
+	}
+
+	var actions []ExtensionActionType
+
+	// extensions only available in the store are downloaded on request
+	if ext.Source == "store" {
+		if state == "" {
+			return nil
+		}
+
+		actions = append(actions, ExtensionActionDownload)
+	}
+
+	if ext.InstalledAt == nil {
+		actions = append(actions, ExtensionActionInstall)
+	} else if ext.IsUpdateAble() {
+		actions = append(actions, ExtensionActionUpdate)
+	}
+
+	if state == "" && !ext.Active {
+		actions = append(actions, ExtensionActionActivate)
+	}
+
+	return newExtensionActions(ext, actions)
+}
+
+func newExtensionActions(ext *adminSdk.ExtensionDetail, types []ExtensionActionType) []ExtensionAction {
+	actions := make([]ExtensionAction, 0, len(types))
+
+	for _, action := range types {
+		actions = append(actions, ExtensionAction{Extension: ext.Name, Type: ext.Type, Action: action})
+	}
+
+	return actions
+}
+
+// ApplyExtensionState runs the actions of the plan and stops at the first failure.
+func ApplyExtensionState(ctx context.Context, client *adminSdk.Client, plan *ExtensionStatePlan) error {
+	apiCtx := adminSdk.NewApiContext(ctx)
+	manager := client.ExtensionManager
+
+	for _, action := range plan.Actions {
+		var err error
+
+		switch action.Action {
+		case ExtensionActionDownload:
+			_, err = manager.DownloadExtension(apiCtx, action.Extension)
+		case ExtensionActionInstall:
+			_, err = manager.InstallExtension(apiCtx, action.Type, action.Extension)
+		case ExtensionActionUpdate:
+			_, err = manager.UpdateExtension(apiCtx, action.Type, action.Extension)
+		case ExtensionActionActivate:
+			_, err = manager.ActivateExtension(apiCtx, action.Type, action.Extension)
+		case ExtensionActionDeactivate:
+			_, err = manager.DeactivateExtension(apiCtx, action.Type, action.Extension)
+		case ExtensionActionUninstall:
+			_, err = manager.UninstallExtension(apiCtx, action.Type, action.Extension)
+		case ExtensionActionRemove:
+			_, err = manager.RemoveExtension(apiCtx, action.Type, action.Extension)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s of %s failed: %w", action.Action, action.Extension, err)
+		}
+
+		logging.FromContext(ctx).Infof("Ran %s for %s", action.Action, action.Extension)
+	}
+
+	return nil
+}
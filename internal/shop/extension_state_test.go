@@ -0,0 +1,99 @@
+package shop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adminSdk "github.com/shopware/shopware-cli/internal/admin-api"
+)
+
+func installedExtension(name string, active bool) *adminSdk.ExtensionDetail {
+	ext := &adminSdk.ExtensionDetail{Name: name, Type: "plugin", Source: "local", Active: active, Version: "1.0.0"}
+	ext.InstalledAt = &struct {
+		Date         string `json:"date"`
+		TimezoneType int    `json:"timezone_type"`
+		Timezone     string `json:"timezone"`
+	}{}
+
+	return ext
+}
+
+func TestPlanExtensionState(t *testing.T) {
+	base := installedExtension("SwagBase", false)
+	base.LatestVersion = "1.1.0"
+
+	installed := adminSdk.ExtensionList{
+		base,
+		{Name: "SwagAddon", Type: "plugin", Source: "local"},
+		installedExtension("SwagOld", true),
+		installedExtension("SwagOldAddon", true),
+		installedExtension("SwagPaused", true),
+		installedExtension("SwagExcluded", false),
+		installedExtension("SwagIgnored", false),
+		{Name: "SwagStore", Type: "plugin", Source: "store"},
+		{Name: "SwagOtherStore", Type: "plugin", Source: "store"},
+	}
+
+	desired := DesiredExtensionState{
+		Exclude: []string{"SwagExcluded"},
+		Overrides: ConfigDeploymentOverrides{
+			"SwagOld":      {State: ExtensionStateRemove},
+			"SwagOldAddon": {State: ExtensionStateRemove},
+			"SwagPaused":   {State: ExtensionStateInactive},
+			"SwagIgnored":  {State: ExtensionStateIgnore},
+			"SwagStore":    {State: ExtensionStateInstalled},
+			"SwagMissing":  {State: ExtensionStateInactive},
+		},
+	}
+
+	order := []string{"SwagBase", "SwagAddon", "SwagExcluded", "SwagIgnored", "SwagOld", "SwagOldAddon", "SwagOtherStore", "SwagPaused", "SwagStore"}
+
+	plan := PlanExtensionState(installed, desired, order)
+
+	assert.Equal(t, []string{"SwagMissing"}, plan.Missing)
+	assert.Equal(t, []ExtensionAction{
+		{Extension: "SwagPaused", Type: "plugin", Action: ExtensionActionDeactivate},
+		{Extension: "SwagOldAddon", Type: "plugin", Action: ExtensionActionDeactivate},
+		{Extension: "SwagOldAddon", Type: "plugin", Action: ExtensionActionUninstall},
+		{Extension: "SwagOldAddon", Type: "plugin", Action: ExtensionActionRemove},
+		{Extension: "SwagOld", Type: "plugin", Action: ExtensionActionDeactivate},
+		{Extension: "SwagOld", Type: "plugin", Action: ExtensionActionUninstall},
+		{Extension: "SwagOld", Type: "plugin", Action: ExtensionActionRemove},
+		{Extension: "SwagBase", Type: "plugin", Action: ExtensionActionUpdate},
+		{Extension: "SwagBase", Type: "plugin", Action: ExtensionActionActivate},
+		{Extension: "SwagAddon", Type: "plugin", Action: ExtensionActionInstall},
+		{Extension: "SwagAddon", Type: "plugin", Action: ExtensionActionActivate},
+		{Extension: "SwagStore", Type: "plugin", Action: ExtensionActionDownload},
+		{Extension: "SwagStore", Type: "plugin", Action: ExtensionActionInstall},
+	}, plan.Actions)
+}
+
+func TestPlanExtensionStateUpToDate(t *testing.T) {
+	installed := adminSdk.ExtensionList{installedExtension("SwagBase", true)}
+
+	plan := PlanExtensionState(installed, DesiredExtensionState{}, []string{"SwagBase"})
+
+	assert.Empty(t, plan.Actions)
+	assert.Empty(t, plan.Missing)
+}
+
+func TestReadDesiredExtensionState(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "extensions.yml")
+
+	require.NoError(t, os.WriteFile(file, []byte("exclude:\n  - SwagExcluded\noverrides:\n  SwagOld:\n    state: remove\n"), 0o644))
+
+	state, err := ReadDesiredExtensionState(file)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SwagExcluded"}, state.Exclude)
+	assert.Equal(t, ExtensionStateRemove, state.Overrides["SwagOld"].State)
+
+	require.NoError(t, os.WriteFile(file, []byte("overrides:\n  SwagOld:\n    state: gone\n"), 0o644))
+
+	_, err = ReadDesiredExtensionState(file)
+	assert.ErrorContains(t, err, `extension SwagOld has the unknown state "gone"`)
+}
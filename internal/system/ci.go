@@ -26,3 +26,21 @@ func IsCIEnvironment(getenv func(string) string) bool {
 
 	return false
 }
+
+// PullRequestBase returns the git ref a pull request is compared against in
+// GitHub Actions and GitLab CI, or an empty string outside of pull requests.
+func PullRequestBase(getenv func(string) string) string {
+	if sha := getenv("CI_MERGE_REQUEST_DIFF_BASE_SHA"); sha != "" {
+		return sha
+	}
+
+	if branch := getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME"); branch != "" {
+		return "origin/" + branch
+	}
+
+	if getenv("GITHUB_ACTIONS") != "" && getenv("GITHUB_BASE_REF") != "" {
+		return "origin/" + getenv("GITHUB_BASE_REF")
+	}
+
+	return ""
+}
@@ -26,6 +26,26 @@ func TestIsCIEnvironment(t *testing.T) {
 	}
 }
 
+func TestPullRequestBase(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "GitHub pull request", env: map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_BASE_REF": "main"}, want: "origin/main"},
+		{name: "GitHub push", env: map[string]string{"GITHUB_ACTIONS": "true"}, want: ""},
+		{name: "GitLab merge request", env: map[string]string{"GITLAB_CI": "true", "CI_MERGE_REQUEST_TARGET_BRANCH_NAME": "trunk"}, want: "origin/trunk"},
+		{name: "GitLab diff base", env: map[string]string{"CI_MERGE_REQUEST_TARGET_BRANCH_NAME": "trunk", "CI_MERGE_REQUEST_DIFF_BASE_SHA": "abc123"}, want: "abc123"},
+		{name: "no CI", env: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PullRequestBase(mapGetenv(tt.env)))
+		})
+	}
+}
+
 func mapGetenv(env map[string]string) func(string) string {
 	return func(key string) string {
 		return env[key]
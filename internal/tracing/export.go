@@ -0,0 +1,256 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type exporter interface {
+	export(ctx context.Context, request exportRequest) error
+}
+
+// The types below are the OTLP/JSON encoding of an ExportTraceServiceRequest.
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type spanData struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            spanStatus `json:"status"`
+}
+
+type spanStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string     `json:"stringValue,omitempty"`
+	IntValue    *string     `json:"intValue,omitempty"`
+	BoolValue   *bool       `json:"boolValue,omitempty"`
+	ArrayValue  *arrayValue `json:"arrayValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+const (
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+func buildRequest(version string, spans []*Span) exportRequest {
+	data := make([]spanData, 0, len(spans))
+
+	for _, s := range spans {
+		s.mu.Lock()
+		entry := spanData{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.id[:]),
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+			Attributes:        convertAttributes(s.attributes),
+		}
+
+		if s.parentID != (spanID{}) {
+			entry.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+
+		if s.err != "" {
+			entry.Status = spanStatus{Code: statusCodeError, Message: s.err}
+		}
+		s.mu.Unlock()
+
+		data = append(data, entry)
+	}
+
+	return exportRequest{
+		ResourceSpans: []resourceSpans{
+			{
+				Resource: resource{
+					Attributes: convertAttributes([]Attribute{
+						String("service.name", "shopware-cli"),
+						String("service.version", version),
+					}),
+				},
+				ScopeSpans: []scopeSpans{
+					{
+						Scope: scope{Name: "github.com/shopware/shopware-cli", Version: version},
+						Spans: data,
+					},
+				},
+			},
+		},
+	}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func convertAttributes(attributes []Attribute) []keyValue {
+	result := make([]keyValue, 0, len(attributes))
+
+	for _, attr := range attributes {
+		result = append(result, keyValue{Key: attr.Key, Value: convertValue(attr.Value)})
+	}
+
+	return result
+}
+
+func convertValue(value any) anyValue {
+	switch v := value.(type) {
+	case string:
+		return anyValue{StringValue: &v}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return anyValue{IntValue: &s}
+	case bool:
+		return anyValue{BoolValue: &v}
+	case []string:
+		values := make([]anyValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, convertValue(item))
+		}
+		return anyValue{ArrayValue: &arrayValue{Values: values}}
+	default:
+		s := fmt.Sprint(v)
+		return anyValue{StringValue: &s}
+	}
+}
+
+func newExporter(endpoint string) (exporter, error) {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+
+	if strings.HasPrefix(endpoint, "file://") {
+		path := strings.TrimPrefix(endpoint, "file://")
+		if path == "" {
+			return nil, fmt.Errorf("trace endpoint %q does not contain a file path", endpoint)
+		}
+
+		return &fileExporter{path: path}, nil
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("trace endpoint %q must be a http(s):// or file:// URL", endpoint)
+	}
+
+	if !strings.HasSuffix(parsed.Path, "/v1/traces") {
+		parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/v1/traces"
+	}
+
+	return &httpExporter{url: parsed.String(), client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// fileExporter appends each export request as one JSON line, the format of the
+// OpenTelemetry collector file exporter.
+type fileExporter struct {
+	path string
+}
+
+func (f *fileExporter) export(_ context.Context, request exportRequest) error {
+	content, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(content, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// httpExporter sends the spans to an OTLP/HTTP collector using the JSON encoding.
+type httpExporter struct {
+	url    string
+	client *http.Client
+}
+
+func (h *httpExporter) export(ctx context.Context, request exportRequest) error {
+	content, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("collector at %s responded with %d: %s", h.url, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+// Package tracing records OpenTelemetry compatible spans of a shopware-cli run.
+//
+// Tracing is opt-in: as long as Setup has not been called, Start returns a nil
+// span and all span methods are no-ops, so call sites don't need to check
+// whether tracing is enabled.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvEndpoint configures the endpoint spans are exported to.
+const EnvEndpoint = "SHOPWARE_CLI_OTEL_ENDPOINT"
+
+// DefaultEndpoint is the OTLP/HTTP endpoint of a locally running collector.
+const DefaultEndpoint = "http://localhost:4318"
+
+const envTraceParent = "TRACEPARENT"
+
+type traceID [16]byte
+
+type spanID [8]byte
+
+type spanContextKey struct{}
+
+type tracer struct {
+	exporter     exporter
+	version      string
+	remoteTrace  traceID
+	remoteParent spanID
+
+	mu    sync.Mutex
+	spans []*Span
+}
+
+var (
+	globalMu sync.Mutex
+	global   *tracer
+)
+
+// Attribute is a key value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func StringSlice(key string, value []string) Attribute {
+	return Attribute{Key: key, Value: append([]string(nil), value...)}
+}
+
+// Span is a single timed operation. A nil Span is valid and ignores all calls.
+type Span struct {
+	tracer   *tracer
+	traceID  traceID
+	id       spanID
+	parentID spanID
+
+	mu         sync.Mutex
+	name       string
+	start      time.Time
+	end        time.Time
+	attributes []Attribute
+	err        string
+	ended      bool
+}
+
+// Setup enables tracing for the current process. Spans are buffered and written to
+// the endpoint on Shutdown. The endpoint is either an OTLP/HTTP collector URL or a
+// file:// URL to append the spans as JSON lines to a local file.
+func Setup(endpoint, version string) error {
+	exp, err := newExporter(endpoint)
+	if err != nil {
+		return err
+	}
+
+	t := &tracer{exporter: exp, version: version}
+	t.remoteTrace, t.remoteParent = parseTraceParent(os.Getenv(envTraceParent))
+
+	globalMu.Lock()
+	global = t
+	globalMu.Unlock()
+
+	return nil
+}
+
+// Enabled reports whether Setup has been called.
+func Enabled() bool {
+	return current() != nil
+}
+
+// Shutdown exports all ended spans and disables tracing again.
+func Shutdown(ctx context.Context) error {
+	globalMu.Lock()
+	t := global
+	global = nil
+	globalMu.Unlock()
+
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	spans := t.spans
+	t.spans = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	if err := t.exporter.export(ctx, buildRequest(t.version, spans)); err != nil {
+		return fmt.Errorf("export traces: %w", err)
+	}
+
+	return nil
+}
+
+func current() *tracer {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	return global
+}
+
+// Start creates a new span as child of the span in ctx and returns a context holding it.
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	t := current()
+	if t == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer:     t,
+		name:       name,
+		start:      time.Now(),
+		attributes: attributes,
+	}
+
+	if parent := FromContext(ctx); parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.id
+	} else if t.remoteTrace != (traceID{}) {
+		span.traceID = t.remoteTrace
+		span.parentID = t.remoteParent
+	} else {
+		_, _ = rand.Read(span.traceID[:])
+	}
+
+	_, _ = rand.Read(span.id[:])
+
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// FromContext returns the active span of ctx or nil.
+func FromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+
+	return span
+}
+
+// SetName renames the span, e.g. once the executed command is known.
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.name = name
+	s.mu.Unlock()
+}
+
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.attributes = append(s.attributes, attributes...)
+	s.mu.Unlock()
+}
+
+// End finishes the span and marks it as failed when err is not nil. Calling End
+// more than once has no effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+
+	s.ended = true
+	s.end = time.Now()
+	if err != nil {
+		s.err = err.Error()
+	}
+	s.mu.Unlock()
+
+	s.tracer.mu.Lock()
+	s.tracer.spans = append(s.tracer.spans, s)
+	s.tracer.mu.Unlock()
+}
+
+// TraceParent returns the W3C traceparent header value of the span.
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(s.traceID[:]), hex.EncodeToString(s.id[:]))
+}
+
+// Environ returns the environment variables to hand the active span of ctx
+// to a child process, so tools supporting TRACEPARENT join the trace.
+func Environ(ctx context.Context) []string {
+	span := FromContext(ctx)
+	if span == nil {
+		return nil
+	}
+
+	return []string{envTraceParent + "=" + span.TraceParent()}
+}
+
+func parseTraceParent(value string) (traceID, spanID) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return traceID{}, spanID{}
+	}
+
+	var tid traceID
+	var sid spanID
+
+	if _, err := hex.Decode(tid[:], []byte(parts[1])); err != nil {
+		return traceID{}, spanID{}
+	}
+
+	if _, err := hex.Decode(sid[:], []byte(parts[2])); err != nil {
+		return traceID{}, spanID{}
+	}
+
+	return tid, sid
+}
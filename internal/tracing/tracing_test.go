@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartWithoutSetupIsNoop(t *testing.T) {
+	ctx, span := Start(t.Context(), "noop", String("foo", "bar"))
+
+	assert.Nil(t, span)
+	assert.Nil(t, FromContext(ctx))
+	assert.Nil(t, Environ(ctx))
+
+	span.SetAttributes(Int("exit_code", 1))
+	span.SetName("renamed")
+	span.End(errors.New("ignored"))
+
+	assert.NoError(t, Shutdown(t.Context()))
+}
+
+func TestFileExporterWritesNestedSpans(t *testing.T) {
+	t.Setenv(envTraceParent, "")
+	file := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	require.NoError(t, Setup("file://"+file, "1.0.0"))
+
+	ctx, root := Start(t.Context(), "command", StringSlice("args", []string{"project", "ci"}))
+	_, child := Start(ctx, "section")
+	child.SetAttributes(Int("process.exit_code", 2), Bool("cached", true))
+	child.End(errors.New("failed"))
+	child.End(nil)
+	root.End(nil)
+
+	require.NoError(t, Shutdown(t.Context()))
+	assert.False(t, Enabled())
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	var request exportRequest
+	require.NoError(t, json.Unmarshal(content, &request))
+
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "section", spans[0].Name)
+	assert.Equal(t, "command", spans[1].Name)
+	assert.Equal(t, spans[1].TraceID, spans[0].TraceID)
+	assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	assert.Empty(t, spans[1].ParentSpanID)
+	assert.Equal(t, statusCodeError, spans[0].Status.Code)
+	assert.Equal(t, "failed", spans[0].Status.Message)
+	assert.Equal(t, "2", *spans[0].Attributes[0].Value.IntValue)
+	assert.Len(t, spans[1].Attributes[0].Value.ArrayValue.Values, 2)
+}
+
+func TestHTTPExporterUsesTracesPath(t *testing.T) {
+	t.Setenv(envTraceParent, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	require.NoError(t, Setup(server.URL, "dev"))
+
+	ctx, span := Start(t.Context(), "command")
+	assert.Equal(t, []string{"TRACEPARENT=" + span.TraceParent()}, Environ(ctx))
+	span.End(nil)
+
+	require.NoError(t, Shutdown(t.Context()))
+
+	assert.Equal(t, "/v1/traces", path)
+	assert.True(t, strings.Contains(body, `"traceId":"0af7651916cd43dd8448eb211c80319c"`))
+	assert.True(t, strings.Contains(body, `"parentSpanId":"b7ad6b7169203331"`))
+}
+
+func TestSetupRejectsInvalidEndpoint(t *testing.T) {
+	assert.Error(t, Setup("ftp://localhost", "dev"))
+	assert.Error(t, Setup("file://", "dev"))
+	assert.False(t, Enabled())
+}
@@ -0,0 +1,118 @@
+package upgrade
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown prints the plan as Markdown.
+func (p Plan) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Upgrade plan: Shopware %s → %s\n\n", p.CurrentVersion, p.TargetVersion)
+
+	if p.HasBlockers() {
+		b.WriteString("> **Blocked:** resolve the items marked with ❌ before running the upgrade.\n\n")
+	}
+
+	b.WriteString("## Composer constraints\n\n")
+	if len(p.Composer) == 0 {
+		b.WriteString("No constraint changes needed.\n\n")
+	} else {
+		b.WriteString("| Package | From | To |\n|---|---|---|\n")
+		for _, change := range p.Composer {
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` |\n", change.Package, change.From, change.To)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(p.Recipes) > 0 {
+		b.WriteString("## Recipes\n\nReview the recipe updates with `composer recipes:update` after the upgrade.\n\n")
+		b.WriteString("| Package | Installed recipe |\n|---|---|\n")
+		for _, recipe := range p.Recipes {
+			fmt.Fprintf(&b, "| %s | %s |\n", recipe.Package, recipe.Version)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## PHP\n\n")
+	fmt.Fprintf(&b, "- Current requirement: `%s`\n- Target requirement: `%s`\n", orNone(p.PHP.Current), orNone(p.PHP.Target))
+	if p.PHP.Configured != "" {
+		mark := "✅"
+		if !p.PHP.Supported {
+			mark = "❌"
+		}
+		fmt.Fprintf(&b, "- Configured PHP version: %s %s\n", p.PHP.Configured, mark)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Extensions\n\n")
+	if len(p.Extensions) == 0 {
+		b.WriteString("No extensions installed.\n\n")
+	} else {
+		b.WriteString("| Extension | Version | Status |\n|---|---|---|\n")
+		for _, ext := range p.Extensions {
+			mark := "✅"
+			if ext.Blocker {
+				mark = "❌"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s %s |\n", ext.Name, ext.Version, mark, ext.Status)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Deprecated templates\n\n")
+	if len(p.DeprecatedTemplates) == 0 {
+		b.WriteString("No deprecated or removed templates and blocks are used.\n")
+	} else {
+		b.WriteString("| File | Line | Message |\n|---|---|---|\n")
+		for _, result := range p.DeprecatedTemplates {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", result.Path, result.Line, escapeCell(result.Message))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteMarkdown prints the report as Markdown.
+func (r Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Upgrade report: Shopware %s → %s\n\n", r.From, r.To)
+
+	if r.Branch != "" {
+		fmt.Fprintf(&b, "Branch: `%s`\n\n", r.Branch)
+	}
+
+	if r.Error != "" {
+		fmt.Fprintf(&b, "> **Failed:** %s\n\n", escapeCell(r.Error))
+	}
+
+	fmt.Fprintf(&b, "%d upgraded, %d skipped, %d failed\n\n", r.Count(StatusUpgraded), r.Count(StatusSkipped), r.Count(StatusFailed))
+
+	if len(r.Packages) > 0 {
+		b.WriteString("| Package | From | To | Status | Reason |\n|---|---|---|---|---|\n")
+		for _, p := range r.Packages {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", p.Name, p.From, p.To, p.Status, escapeCell(p.Reason))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+
+	return value
+}
+
+func escapeCell(value string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(value, "|", "\\|"), "\n", " ")
+}
@@ -0,0 +1,189 @@
+// Package upgrade plans and records upgrades of a project to another Shopware
+// version.
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shyim/go-composer"
+
+	"github.com/shopware/shopware-cli/internal/shop"
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+// versionedPackages follow the Shopware version directly.
+var versionedPackages = []string{"shopware/core", "shopware/platform"}
+
+// dependingPackages are usually required with * and follow shopware/core,
+// a pinned constraint has to be changed as well.
+var dependingPackages = []string{"shopware/administration", "shopware/storefront", "shopware/elasticsearch"}
+
+// Plan lists everything changing when upgrading the project.
+type Plan struct {
+	CurrentVersion      string                   `json:"currentVersion"`
+	TargetVersion       string                   `json:"targetVersion"`
+	Composer            []ConstraintChange       `json:"composer"`
+	Recipes             []Recipe                 `json:"recipes"`
+	Extensions          []ExtensionUpdate        `json:"extensions"`
+	PHP                 PHPRequirement           `json:"php"`
+	DeprecatedTemplates []validation.CheckResult `json:"deprecatedTemplates"`
+}
+
+// ConstraintChange is a changed requirement in the composer.json.
+type ConstraintChange struct {
+	Package string `json:"package"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// Recipe is a Symfony Flex recipe of a Shopware package installed in the
+// project, it can change with the package.
+type Recipe struct {
+	Package string `json:"package"`
+	Version string `json:"version"`
+}
+
+// ExtensionUpdate is the compatibility of an extension with the target version.
+type ExtensionUpdate struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	Blocker bool   `json:"blocker"`
+}
+
+// PHPRequirement compares the PHP requirement of the current and the target version.
+type PHPRequirement struct {
+	Current string `json:"current"`
+	Target  string `json:"target"`
+	// Configured is the PHP version of the docker environment, if configured
+	Configured string `json:"configured,omitempty"`
+	// Supported reports whether the configured version fulfills the target requirement
+	Supported bool `json:"supported"`
+}
+
+// HasBlockers reports whether the upgrade needs manual work first.
+func (p Plan) HasBlockers() bool {
+	if !p.PHP.Supported {
+		return true
+	}
+
+	return slices.ContainsFunc(p.Extensions, func(e ExtensionUpdate) bool {
+		return e.Blocker
+	})
+}
+
+// ComposerConstraintChanges returns the changes of the shopware/* requirements
+// for the target version.
+func ComposerConstraintChanges(require map[string]string, targetVersion string) []ConstraintChange {
+	var changes []ConstraintChange
+
+	for _, name := range slices.Concat(versionedPackages, dependingPackages) {
+		constraint, ok := require[name]
+		if !ok || constraint == targetVersion {
+			continue
+		}
+
+		if slices.Contains(dependingPackages, name) && constraint == "*" {
+			continue
+		}
+
+		changes = append(changes, ConstraintChange{Package: name, From: constraint, To: targetVersion})
+	}
+
+	return changes
+}
+
+// UpdatePackages returns the Shopware packages required by the project, they
+// are updated together.
+func UpdatePackages(require map[string]string) []string {
+	var packages []string
+
+	for _, name := range slices.Concat(versionedPackages, dependingPackages) {
+		if _, ok := require[name]; ok {
+			packages = append(packages, name)
+		}
+	}
+
+	return packages
+}
+
+// LockedVersions returns the versions of all packages in the composer.lock.
+func LockedVersions(lock *composer.Lock) map[string]string {
+	versions := map[string]string{}
+	if lock == nil {
+		return versions
+	}
+
+	for _, pkg := range slices.Concat(lock.Packages, lock.PackagesDev) {
+		versions[pkg.Name] = pkg.Version
+	}
+
+	return versions
+}
+
+// ApplyConstraintChanges sets the new constraints in the requirements.
+func ApplyConstraintChanges(require map[string]string, changes []ConstraintChange) {
+	for _, change := range changes {
+		require[change.Package] = change.To
+	}
+}
+
+// ReadRecipes returns the recipes of Shopware packages from the symfony.lock.
+func ReadRecipes(projectRoot string) ([]Recipe, error) {
+	content, err := os.ReadFile(filepath.Join(projectRoot, "symfony.lock"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var lock map[string]struct {
+		Recipe *struct {
+			Version string `json:"version"`
+		} `json:"recipe"`
+	}
+
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("cannot read symfony.lock: %w", err)
+	}
+
+	var recipes []Recipe
+
+	for name, entry := range lock {
+		if !strings.HasPrefix(name, "shopware/") || entry.Recipe == nil {
+			continue
+		}
+
+		recipes = append(recipes, Recipe{Package: name, Version: entry.Recipe.Version})
+	}
+
+	slices.SortFunc(recipes, func(a, b Recipe) int {
+		return strings.Compare(a.Package, b.Package)
+	})
+
+	return recipes, nil
+}
+
+// NewPHPRequirement compares the PHP constraints. configured is the PHP
+// version of the docker environment and can be empty.
+func NewPHPRequirement(current, target *shop.PHPConstraint, configured string) PHPRequirement {
+	requirement := PHPRequirement{
+		Current:    current.String(),
+		Target:     target.String(),
+		Configured: configured,
+		Supported:  true,
+	}
+
+	if configured != "" {
+		requirement.Supported = target.Check(configured + ".0")
+	}
+
+	return requirement
+}
@@ -0,0 +1,95 @@
+package upgrade
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/shop"
+)
+
+func TestComposerConstraintChanges(t *testing.T) {
+	requirements := map[string]string{
+		"shopware/core":           "6.5.8.0",
+		"shopware/administration": "*",
+		"shopware/storefront":     "6.5.8.0",
+		"symfony/flex":            "~2",
+	}
+
+	changes := ComposerConstraintChanges(requirements, "6.6.0.0")
+
+	assert.Equal(t, []ConstraintChange{
+		{Package: "shopware/core", From: "6.5.8.0", To: "6.6.0.0"},
+		{Package: "shopware/storefront", From: "6.5.8.0", To: "6.6.0.0"},
+	}, changes)
+
+	ApplyConstraintChanges(requirements, changes)
+	assert.Equal(t, "6.6.0.0", requirements["shopware/core"])
+	assert.Equal(t, "*", requirements["shopware/administration"])
+	assert.Empty(t, ComposerConstraintChanges(requirements, "6.6.0.0"))
+
+	assert.Equal(t, []string{"shopware/core", "shopware/administration", "shopware/storefront"}, UpdatePackages(requirements))
+}
+
+func TestReadRecipes(t *testing.T) {
+	dir := t.TempDir()
+
+	recipes, err := ReadRecipes(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, recipes)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "symfony.lock"), []byte(`{
+		"shopware/storefront": {"recipe": {"version": "6.5"}},
+		"shopware/core": {"recipe": {"version": "6.4"}},
+		"shopware/elasticsearch": {"version": "6.5.8.0"},
+		"symfony/flex": {"recipe": {"version": "1.0"}}
+	}`), 0o644))
+
+	recipes, err = ReadRecipes(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []Recipe{
+		{Package: "shopware/core", Version: "6.4"},
+		{Package: "shopware/storefront", Version: "6.5"},
+	}, recipes)
+}
+
+func TestNewPHPRequirement(t *testing.T) {
+	current := shop.NewPHPConstraint(">=8.1")
+	target := shop.NewPHPConstraint(">=8.2")
+
+	requirement := NewPHPRequirement(current, target, "8.1")
+	assert.Equal(t, ">=8.1", requirement.Current)
+	assert.Equal(t, ">=8.2", requirement.Target)
+	assert.False(t, requirement.Supported)
+	assert.True(t, Plan{PHP: requirement}.HasBlockers())
+
+	requirement = NewPHPRequirement(current, target, "8.3")
+	assert.True(t, requirement.Supported)
+
+	requirement = NewPHPRequirement(nil, nil, "")
+	assert.True(t, requirement.Supported)
+	assert.False(t, Plan{PHP: requirement}.HasBlockers())
+	assert.True(t, Plan{PHP: requirement, Extensions: []ExtensionUpdate{{Name: "SwagPayPal", Blocker: true}}}.HasBlockers())
+}
+
+func TestPlanWriteMarkdown(t *testing.T) {
+	plan := Plan{
+		CurrentVersion: "6.5.8.0",
+		TargetVersion:  "6.6.0.0",
+		Composer:       []ConstraintChange{{Package: "shopware/core", From: "6.5.8.0", To: "6.6.0.0"}},
+		Extensions:     []ExtensionUpdate{{Name: "SwagPayPal", Version: "8.0.0", Status: "Not compatible", Blocker: true}},
+		PHP:            PHPRequirement{Current: ">=8.1", Target: ">=8.2", Supported: true},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, plan.WriteMarkdown(&out))
+	assert.Contains(t, out.String(), "# Upgrade plan: Shopware 6.5.8.0 → 6.6.0.0")
+	assert.Contains(t, out.String(), "**Blocked:**")
+	assert.Contains(t, out.String(), "| shopware/core | `6.5.8.0` | `6.6.0.0` |")
+	assert.Contains(t, out.String(), "| SwagPayPal | 8.0.0 | ❌ Not compatible |")
+	assert.Contains(t, out.String(), "No deprecated or removed templates")
+}
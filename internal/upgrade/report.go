@@ -0,0 +1,182 @@
+package upgrade
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Status is the outcome of a package in the upgrade.
+type Status string
+
+const (
+	StatusUpgraded Status = "upgraded"
+	StatusSkipped  Status = "skipped"
+	StatusFailed   Status = "failed"
+)
+
+// Report records the outcome of an upgrade run.
+type Report struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Branch   string          `json:"branch,omitempty"`
+	Time     time.Time       `json:"time"`
+	Packages []PackageResult `json:"packages"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// PackageResult is the outcome of a single package.
+type PackageResult struct {
+	Name   string `json:"name"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Status Status `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReportPath is the file the last report of the project is stored in.
+func ReportPath(projectRoot string) string {
+	return filepath.Join(projectRoot, "var", "shopware-cli-upgrade.json")
+}
+
+// WriteReport stores the report of the project.
+func WriteReport(projectRoot string, report *Report) error {
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(ReportPath(projectRoot)), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(ReportPath(projectRoot), content, 0o644)
+}
+
+// ReadReport reads the last report of the project.
+func ReadReport(projectRoot string) (*Report, error) {
+	content, err := os.ReadFile(ReportPath(projectRoot))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no upgrade report found, run shopware-cli project upgrade run first")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var report Report
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", ReportPath(projectRoot), err)
+	}
+
+	return &report, nil
+}
+
+// ComparePackages builds the results from the locked versions before and
+// after the update. Changed packages are upgraded, the planned packages and
+// the skipped ones with their reason are reported as skipped.
+func ComparePackages(before, after map[string]string, planned []string, skipped map[string]string) []PackageResult {
+	var results []PackageResult
+
+	for name, to := range after {
+		from, ok := before[name]
+		if ok && from != to {
+			results = append(results, PackageResult{Name: name, From: from, To: to, Status: StatusUpgraded})
+		}
+	}
+
+	for _, name := range planned {
+		if from, ok := before[name]; ok && before[name] == after[name] {
+			results = append(results, PackageResult{Name: name, From: from, To: from, Status: StatusSkipped, Reason: "version did not change"})
+		}
+	}
+
+	for name, reason := range skipped {
+		if slices.ContainsFunc(results, func(r PackageResult) bool { return r.Name == name }) {
+			continue
+		}
+
+		results = append(results, PackageResult{Name: name, Status: StatusSkipped, Reason: reason})
+	}
+
+	sortResults(results)
+
+	return results
+}
+
+// FailedPackages reports the planned packages as failed.
+func FailedPackages(before map[string]string, planned []string, err error) []PackageResult {
+	results := make([]PackageResult, 0, len(planned))
+
+	for _, name := range planned {
+		results = append(results, PackageResult{Name: name, From: before[name], Status: StatusFailed, Reason: err.Error()})
+	}
+
+	sortResults(results)
+
+	return results
+}
+
+func sortResults(results []PackageResult) {
+	slices.SortFunc(results, func(a, b PackageResult) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// Count returns the number of packages with the status.
+func (r Report) Count(status Status) int {
+	count := 0
+
+	for _, p := range r.Packages {
+		if p.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// FileSnapshot keeps the content of files, so a failed upgrade can restore them.
+type FileSnapshot map[string][]byte
+
+// SnapshotFiles reads the files, missing files are removed again on restore.
+func SnapshotFiles(paths ...string) (FileSnapshot, error) {
+	snapshot := FileSnapshot{}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		snapshot[path] = content
+	}
+
+	return snapshot, nil
+}
+
+// Restore writes the files back to the content of the snapshot.
+func (s FileSnapshot) Restore() error {
+	for _, path := range slices.Sorted(maps.Keys(s)) {
+		content := s[path]
+
+		if content == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+
+			continue
+		}
+
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package upgrade
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparePackages(t *testing.T) {
+	before := map[string]string{"shopware/core": "6.5.8.0", "shopware/storefront": "6.5.8.0", "symfony/console": "6.3.0"}
+	after := map[string]string{"shopware/core": "6.6.0.0", "shopware/storefront": "6.5.8.0", "symfony/console": "7.0.0"}
+
+	results := ComparePackages(before, after, []string{"shopware/core", "shopware/storefront"}, map[string]string{"SwagPayPal": "not compatible"})
+
+	assert.Equal(t, []PackageResult{
+		{Name: "SwagPayPal", Status: StatusSkipped, Reason: "not compatible"},
+		{Name: "shopware/core", From: "6.5.8.0", To: "6.6.0.0", Status: StatusUpgraded},
+		{Name: "shopware/storefront", From: "6.5.8.0", To: "6.5.8.0", Status: StatusSkipped, Reason: "version did not change"},
+		{Name: "symfony/console", From: "6.3.0", To: "7.0.0", Status: StatusUpgraded},
+	}, results)
+
+	report := Report{Packages: results}
+	assert.Equal(t, 2, report.Count(StatusUpgraded))
+	assert.Equal(t, 2, report.Count(StatusSkipped))
+	assert.Equal(t, 0, report.Count(StatusFailed))
+}
+
+func TestFailedPackages(t *testing.T) {
+	results := FailedPackages(map[string]string{"shopware/core": "6.5.8.0"}, []string{"shopware/core"}, errors.New("composer update failed"))
+
+	assert.Equal(t, []PackageResult{
+		{Name: "shopware/core", From: "6.5.8.0", Status: StatusFailed, Reason: "composer update failed"},
+	}, results)
+}
+
+func TestWriteAndReadReport(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ReadReport(dir)
+	assert.ErrorContains(t, err, "no upgrade report found")
+
+	report := &Report{
+		From:     "6.5.8.0",
+		To:       "6.6.0.0",
+		Branch:   "shopware-upgrade-6.6.0.0",
+		Time:     time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Packages: []PackageResult{{Name: "shopware/core", From: "6.5.8.0", To: "6.6.0.0", Status: StatusUpgraded}},
+	}
+
+	require.NoError(t, WriteReport(dir, report))
+
+	read, err := ReadReport(dir)
+	require.NoError(t, err)
+	assert.Equal(t, report, read)
+
+	var out bytes.Buffer
+	assert.NoError(t, read.WriteMarkdown(&out))
+	assert.Contains(t, out.String(), "Branch: `shopware-upgrade-6.6.0.0`")
+	assert.Contains(t, out.String(), "1 upgraded, 0 skipped, 0 failed")
+	assert.Contains(t, out.String(), "| shopware/core | 6.5.8.0 | 6.6.0.0 | upgraded |  |")
+}
+
+func TestFileSnapshotRestore(t *testing.T) {
+	dir := t.TempDir()
+	composerJSON := filepath.Join(dir, "composer.json")
+	composerLock := filepath.Join(dir, "composer.lock")
+
+	require.NoError(t, os.WriteFile(composerJSON, []byte(`{"require": {"shopware/core": "6.5.8.0"}}`), 0o644))
+
+	snapshot, err := SnapshotFiles(composerJSON, composerLock)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(composerJSON, []byte(`{"require": {"shopware/core": "6.6.0.0"}}`), 0o644))
+	require.NoError(t, os.WriteFile(composerLock, []byte(`{"packages": []}`), 0o644))
+
+	require.NoError(t, snapshot.Restore())
+
+	content, err := os.ReadFile(composerJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"require": {"shopware/core": "6.5.8.0"}}`, string(content))
+	assert.NoFileExists(t, composerLock)
+}
@@ -14,16 +14,30 @@ import (
 
 func DetectDefaultReporter() string {
 	if os.Getenv("GITHUB_ACTIONS") == "true" {
-		return "github"
+		return ReporterForCISystem("github")
 	}
 
 	if os.Getenv("GITLAB_CI") == "true" {
-		return "gitlab"
+		return ReporterForCISystem("gitlab")
 	}
 
 	return "summary"
 }
 
+// ReporterForCISystem returns the reporting format a pipeline of the given CI
+// system should use. Systems without native annotations get JUnit XML, which all
+// of them can display as test report.
+func ReporterForCISystem(system string) string {
+	switch system {
+	case "github", "gitlab":
+		return system
+	case "bitbucket", "azure", "jenkins":
+		return "junit"
+	default:
+		return "summary"
+	}
+}
+
 func DoCheckReport(result Check, reportingFormat string) error {
 	switch reportingFormat {
 	case "summary":
@@ -50,6 +64,10 @@ func DoCheckReport(result Check, reportingFormat string) error {
 		if err := doJUnitReport(result); err != nil {
 			return err
 		}
+	case "sarif":
+		if err := doSARIFReport(result); err != nil {
+			return err
+		}
 	}
 
 	if result.HasErrors() {
@@ -433,3 +451,120 @@ func doJUnitReport(result Check) error {
 	encoder.Indent("", "  ")
 	return encoder.Encode(suite)
 }
+
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID string `json:"id"`
+}
+
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations,omitempty"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// doSARIFReport writes the results as SARIF 2.1.0, which code scanning tools can import.
+func doSARIFReport(result Check) error {
+	results := []SARIFResult{}
+	rules := []SARIFRule{}
+	seenRules := map[string]bool{}
+
+	for _, r := range result.GetResults() {
+		if !seenRules[r.Identifier] {
+			seenRules[r.Identifier] = true
+			rules = append(rules, SARIFRule{ID: r.Identifier})
+		}
+
+		message := r.Message
+		if r.Tip != "" {
+			message = message + "\n\nTip: " + r.Tip
+		}
+
+		level := "warning"
+		if r.Severity == SeverityError {
+			level = "error"
+		}
+
+		sarifResult := SARIFResult{
+			RuleID:  r.Identifier,
+			Level:   level,
+			Message: SARIFMessage{Text: message},
+		}
+
+		if r.Path != "" {
+			location := SARIFLocation{PhysicalLocation: SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: r.Path}}}
+			if r.Line > 0 {
+				location.PhysicalLocation.Region = &SARIFRegion{StartLine: r.Line}
+			}
+
+			sarifResult.Locations = []SARIFLocation{location}
+		}
+
+		results = append(results, sarifResult)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	log := SARIFLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []SARIFRun{
+			{
+				Tool: SARIFTool{Driver: SARIFDriver{
+					Name:           "shopware-cli",
+					InformationURI: "https://github.com/shopware/shopware-cli",
+					Rules:          rules,
+				}},
+				Results: results,
+			},
+		},
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
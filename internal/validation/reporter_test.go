@@ -513,3 +513,32 @@ func captureOutput(fn func()) string {
 	}
 	return buf.String()
 }
+
+func TestReporterForCISystem(t *testing.T) {
+	assert.Equal(t, "github", ReporterForCISystem("github"))
+	assert.Equal(t, "gitlab", ReporterForCISystem("gitlab"))
+	assert.Equal(t, "junit", ReporterForCISystem("bitbucket"))
+	assert.Equal(t, "junit", ReporterForCISystem("azure"))
+	assert.Equal(t, "junit", ReporterForCISystem("jenkins"))
+	assert.Equal(t, "summary", ReporterForCISystem("unknown"))
+}
+
+func TestSARIFReport(t *testing.T) {
+	check := &testCheck{Results: []CheckResult{
+		{Path: "composer.lock", Identifier: "audit/CVE-2024-1", Message: "Vulnerable", Severity: SeverityError},
+		{Path: "src/index.js", Line: 3, Identifier: "eslint/no-unused-vars", Message: "Unused", Severity: SeverityWarning, Tip: "Remove it"},
+	}}
+
+	output := captureOutput(func() {
+		assert.NoError(t, doSARIFReport(check))
+	})
+
+	var log SARIFLog
+	assert.NoError(t, json.Unmarshal([]byte(output), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, 2)
+	assert.Equal(t, "error", log.Runs[0].Results[0].Level)
+	assert.Nil(t, log.Runs[0].Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, 3, log.Runs[0].Results[1].Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Contains(t, log.Runs[0].Results[1].Message.Text, "Tip: Remove it")
+}
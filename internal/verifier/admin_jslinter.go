@@ -0,0 +1,185 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+	_ "github.com/shopware/shopware-cli/internal/verifier/jslinter/adminjslinter"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// AdminJSLinter checks the JavaScript side of administration extensions:
+// component overrides, mixins, injections and Vue 2 APIs. It complements the
+// template fixers of admin-twig.
+type AdminJSLinter struct{}
+
+func (a AdminJSLinter) Name() string {
+	return "admin-js"
+}
+
+type adminScript struct {
+	path string
+	file *jslinter.File
+}
+
+// parseAdminScripts parses the sources below src of the administration
+// directories and collects what the extension registers itself.
+func parseAdminScripts(config ToolConfig, check *Check) ([]adminScript, *jslinter.Registry, error) {
+	var scripts []adminScript
+
+	own := jslinter.NewRegistry()
+
+	for _, adminDirectory := range config.AdminDirectories {
+		srcDir := filepath.Join(adminDirectory, "src")
+		if _, err := os.Stat(srcDir); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if d.Name() == "node_modules" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !isAdministrationScript(filepath.ToSlash(path)) {
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			file, err := jslinter.Parse(path, string(content))
+			if err != nil {
+				line := 0
+				var pe *jslinter.ParseError
+				if errors.As(err, &pe) {
+					line = pe.Line
+				}
+
+				if check != nil {
+					check.AddResult(validation.CheckResult{
+						Path:       relativeResultPath(path, config.RootDir),
+						Message:    fmt.Sprintf("Failed to parse %s: %v", path, err),
+						Severity:   validation.SeverityWarning,
+						Identifier: "could-not-parse-js",
+						Line:       line,
+					})
+				}
+
+				return nil
+			}
+
+			own.Collect(file)
+			scripts = append(scripts, adminScript{path: path, file: file})
+
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].path < scripts[j].path })
+
+	return scripts, own, nil
+}
+
+// setRegistries sets the registry of Shopware and of the extension on the
+// scripts. Without the registry of Shopware only the version independent
+// rules can report.
+func setRegistries(ctx context.Context, config ToolConfig, scripts []adminScript, own *jslinter.Registry) {
+	var core *jslinter.Registry
+
+	if index, err := loadTemplateIndex(ctx, config); err == nil {
+		core = index.AdministrationRegistry
+	} else {
+		logging.FromContext(ctx).Debugf("Checking the administration without the registry of Shopware: %v", err)
+	}
+
+	for _, script := range scripts {
+		script.file.Core = core
+		script.file.Own = own
+	}
+}
+
+func (a AdminJSLinter) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	scripts, own, err := parseAdminScripts(config, check)
+	if err != nil || len(scripts) == 0 {
+		return err
+	}
+
+	setRegistries(ctx, config, scripts, own)
+
+	fixers := jslinter.GetAdministrationFixers(version.Must(version.NewVersion(config.MinShopwareVersion)))
+
+	for _, script := range scripts {
+		for _, fixer := range fixers {
+			for _, message := range fixer.Check(script.file) {
+				check.AddResult(validation.CheckResult{
+					Path:       relativeResultPath(script.path, config.RootDir),
+					Line:       message.Line,
+					Message:    message.Message,
+					Severity:   message.Severity,
+					Identifier: fmt.Sprintf("adminjslinter/%s", message.Identifier),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// Fix applies the rewrites of the rules, files without rewrites are left
+// untouched.
+func (a AdminJSLinter) Fix(ctx context.Context, config ToolConfig) error {
+	scripts, own, err := parseAdminScripts(config, nil)
+	if err != nil || len(scripts) == 0 {
+		return err
+	}
+
+	setRegistries(ctx, config, scripts, own)
+
+	fixers := jslinter.GetAdministrationFixers(version.Must(version.NewVersion(config.MinShopwareVersion)))
+
+	for _, script := range scripts {
+		for _, fixer := range fixers {
+			if err := fixer.Fix(script.file); err != nil {
+				return err
+			}
+		}
+
+		if result := script.file.Result(); result != script.file.Source {
+			if err := os.WriteFile(script.path, []byte(result), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a AdminJSLinter) Format(ctx context.Context, config ToolConfig, dryRun bool) error {
+	return nil
+}
+
+func init() {
+	AddTool(AdminJSLinter{})
+}
@@ -0,0 +1,75 @@
+package verifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminJSLinter(t *testing.T) {
+	root := t.TempDir()
+	adminDir := filepath.Join(root, "src", "Resources", "app", "administration")
+	componentDir := filepath.Join(adminDir, "src", "extension", "sw-product-detail")
+	require.NoError(t, os.MkdirAll(componentDir, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(adminDir, "src", "broken"), 0o755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(adminDir, "src", "main.js"), []byte(`import './extension/sw-product-detail';
+Shopware.Component.register('my-component', () => import('./my-component'));
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(componentDir, "index.js"), []byte(`Shopware.Component.override('sw-product-old', {
+    mixins: [Shopware.Mixin.getByName('notification')],
+    beforeDestroy() {},
+});
+Shopware.Component.override('my-component', {});
+Shopware.Component.override('sw-button', {});
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(componentDir, "index.spec.js"), []byte(`Shopware.Component.override('sw-spec-only', {});`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(adminDir, "src", "broken", "index.js"), []byte("const a = {\n"), 0o644))
+
+	original := loadTemplateIndex
+	t.Cleanup(func() { loadTemplateIndex = original })
+	loadTemplateIndex = func(context.Context, ToolConfig) (*templateIndex, error) {
+		index := newTemplateIndex("6.7.0.0")
+		index.AdministrationRegistry.Components["sw-product-detail"] = true
+		index.AdministrationRegistry.Components["mt-button"] = true
+		index.AdministrationRegistry.Mixins["notification"] = true
+
+		return index, nil
+	}
+
+	config := ToolConfig{
+		RootDir:            root,
+		MinShopwareVersion: "6.7.0.0",
+		AdminDirectories:   []string{adminDir},
+	}
+
+	check := NewCheck()
+	require.NoError(t, AdminJSLinter{}.Check(t.Context(), check, config))
+
+	identifiers := map[string]string{}
+	for _, result := range check.GetResults() {
+		identifiers[result.Identifier] = result.Path
+	}
+
+	assert.Equal(t, map[string]string{
+		"adminjslinter/removed-component":  "src/Resources/app/administration/src/extension/sw-product-detail/index.js",
+		"adminjslinter/vue-lifecycle-hook": "src/Resources/app/administration/src/extension/sw-product-detail/index.js",
+		"could-not-parse-js":               "src/Resources/app/administration/src/broken/index.js",
+	}, identifiers)
+
+	require.NoError(t, AdminJSLinter{}.Fix(t.Context(), config))
+
+	fixed, err := os.ReadFile(filepath.Join(componentDir, "index.js"))
+	require.NoError(t, err)
+	assert.Contains(t, string(fixed), "beforeUnmount() {},")
+	assert.Contains(t, string(fixed), "Shopware.Component.override('mt-button', {});", "the fixers see the registries like the checks")
+	assert.Contains(t, string(fixed), "Shopware.Component.override('my-component', {});")
+
+	broken, err := os.ReadFile(filepath.Join(adminDir, "src", "broken", "index.js"))
+	require.NoError(t, err)
+	assert.Equal(t, "const a = {\n", string(broken))
+}
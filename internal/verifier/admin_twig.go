@@ -36,7 +36,7 @@ func (a AdminTwigLinter) Check(ctx context.Context, check *Check, config ToolCon
 				return nil
 			}
 
-			if filepath.Ext(path) != twiglinter.TwigExtension {
+			if filepath.Ext(path) != twiglinter.TwigExtension || !config.IsChanged(path) {
 				return nil
 			}
 
@@ -0,0 +1,129 @@
+package verifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/git"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// ChangedSinceAuto detects the base of the pull request in CI.
+const ChangedSinceAuto = "auto"
+
+// ChangedFilesSince returns the files of the directory changed since the git
+// ref. With ChangedSinceAuto the base of the pull request is used, outside of
+// pull requests nil is returned to check everything.
+func ChangedFilesSince(ctx context.Context, dir, ref string) ([]string, error) {
+	if ref == ChangedSinceAuto {
+		ref = system.PullRequestBase(os.Getenv)
+
+		if ref == "" {
+			logging.FromContext(ctx).Infof("No pull request detected, validating all files")
+			return nil, nil
+		}
+	}
+
+	files, err := git.ChangedFiles(ctx, dir, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	logging.FromContext(ctx).Infof("Validating %d files changed since %s", len(files), ref)
+
+	return files, nil
+}
+
+// LimitToChangedFiles limits the checks to the changed files. The files are
+// absolute paths below sourceRoot, which can be a copy of RootDir.
+func (c *ToolConfig) LimitToChangedFiles(sourceRoot string, files []string) {
+	if files == nil {
+		return
+	}
+
+	if resolved, err := filepath.EvalSymlinks(sourceRoot); err == nil {
+		sourceRoot = resolved
+	}
+
+	c.ChangedFiles = make([]string, 0, len(files))
+
+	for _, file := range files {
+		rel, err := filepath.Rel(sourceRoot, file)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		c.ChangedFiles = append(c.ChangedFiles, filepath.Join(c.RootDir, rel))
+	}
+}
+
+// IsIncremental reports whether only the changed files are checked.
+func (c ToolConfig) IsIncremental() bool {
+	return c.ChangedFiles != nil
+}
+
+// ChangedFilesIn returns the changed files inside the directory with one of
+// the extensions.
+func (c ToolConfig) ChangedFilesIn(dir string, extensions ...string) []string {
+	files := []string{}
+
+	for _, file := range c.ChangedFiles {
+		if !strings.HasPrefix(file, dir+string(filepath.Separator)) {
+			continue
+		}
+
+		if len(extensions) > 0 && !slices.Contains(extensions, filepath.Ext(file)) {
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// IsChanged reports whether the file has to be checked. All files are
+// checked without incremental validation.
+func (c ToolConfig) IsChanged(path string) bool {
+	return !c.IsIncremental() || slices.Contains(c.ChangedFiles, path)
+}
+
+// RemoveUnchangedFiles drops the results of files which are not changed.
+// Results without path or with a path outside of the tree, like errors of
+// the tools themselves, are kept.
+func (c *Check) RemoveUnchangedFiles(config ToolConfig) *Check {
+	if !config.IsIncremental() {
+		return c
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	filtered := make([]validation.CheckResult, 0, len(c.Results))
+
+	for _, r := range c.Results {
+		path := r.Path
+		if path != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(config.RootDir, path)
+		}
+
+		if path == "" || config.IsChanged(path) || !fileExists(path) {
+			filtered = append(filtered, r)
+		}
+	}
+
+	c.Results = filtered
+
+	return c
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
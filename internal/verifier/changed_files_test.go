@@ -0,0 +1,64 @@
+package verifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+func TestLimitToChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	source := t.TempDir()
+	config := ToolConfig{RootDir: "/tmp/copy"}
+
+	config.LimitToChangedFiles(source, nil)
+	assert.False(t, config.IsIncremental())
+	assert.True(t, config.IsChanged("/tmp/copy/src/Foo.php"))
+
+	resolved, err := filepath.EvalSymlinks(source)
+	require.NoError(t, err)
+
+	config.LimitToChangedFiles(source, []string{
+		filepath.Join(resolved, "src", "Foo.php"),
+		filepath.Join(resolved, "src", "Resources", "app", "administration", "src", "main.js"),
+		filepath.Join(filepath.Dir(resolved), "outside.php"),
+	})
+
+	assert.True(t, config.IsIncremental())
+	assert.Equal(t, []string{"/tmp/copy/src/Foo.php", "/tmp/copy/src/Resources/app/administration/src/main.js"}, config.ChangedFiles)
+	assert.True(t, config.IsChanged("/tmp/copy/src/Foo.php"))
+	assert.False(t, config.IsChanged("/tmp/copy/src/Bar.php"))
+	assert.Equal(t, []string{"/tmp/copy/src/Foo.php"}, config.ChangedFilesIn("/tmp/copy/src", ".php"))
+	assert.Equal(t, []string{"/tmp/copy/src/Resources/app/administration/src/main.js"}, config.ChangedFilesIn("/tmp/copy/src/Resources/app/administration", eslintExtensions...))
+	assert.Empty(t, config.ChangedFilesIn("/tmp/copy/src/Resources/app/storefront"))
+
+	config.LimitToChangedFiles(source, []string{})
+	assert.True(t, config.IsIncremental())
+	assert.Empty(t, config.ChangedFiles)
+}
+
+func TestRemoveUnchangedFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "Changed.php"), []byte("<?php"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "Unchanged.php"), []byte("<?php"), 0o644))
+
+	check := NewCheck()
+	check.AddResult(validation.CheckResult{Path: "src/Changed.php", Identifier: "changed"})
+	check.AddResult(validation.CheckResult{Path: "src/Unchanged.php", Identifier: "unchanged"})
+	check.AddResult(validation.CheckResult{Path: "phpstan.neon", Identifier: "tool-error"})
+	check.AddResult(validation.CheckResult{Identifier: "no-path"})
+
+	assert.Len(t, check.RemoveUnchangedFiles(ToolConfig{RootDir: root}).GetResults(), 4)
+
+	results := check.RemoveUnchangedFiles(ToolConfig{RootDir: root, ChangedFiles: []string{filepath.Join(root, "src", "Changed.php")}}).GetResults()
+	assert.Equal(t, []string{"changed", "tool-error", "no-path"}, identifiers(results))
+}
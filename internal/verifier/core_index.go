@@ -0,0 +1,227 @@
+package verifier
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/php"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// coreIndexCacheVersion is part of the cache key, bump it when the layout of
+// coreIndex changes.
+const coreIndexCacheVersion = "1"
+
+// coreIndexPackages are the Shopware packages whose PHP classes are indexed.
+var coreIndexPackages = []string{"core", "storefront", "elasticsearch"}
+
+// coreIndex lists the PHP classes one Shopware version ships.
+type coreIndex struct {
+	Version string `json:"version"`
+	// Classes lists all class, interface, trait and enum names
+	Classes map[string]bool `json:"classes"`
+	// Internal lists the classes marked with @internal
+	Internal map[string]bool `json:"internal"`
+	// EntityTables lists the entity names of the definitions, which are also
+	// their table names
+	EntityTables map[string]bool `json:"entityTables"`
+	// EventConstants lists the constants of event classes as Class::CONSTANT
+	EventConstants map[string]bool `json:"eventConstants"`
+}
+
+func newCoreIndex(shopwareVersion string) *coreIndex {
+	return &coreIndex{
+		Version:        shopwareVersion,
+		Classes:        map[string]bool{},
+		Internal:       map[string]bool{},
+		EntityTables:   map[string]bool{},
+		EventConstants: map[string]bool{},
+	}
+}
+
+// addSource indexes a PHP file by its path in the package. Tests shipped
+// with the package are skipped.
+func (i *coreIndex) addSource(name, content string) {
+	if path.Ext(name) != ".php" || strings.Contains("/"+name, "/Test/") || strings.Contains("/"+name, "/Resources/") {
+		return
+	}
+
+	file := php.Parse(content)
+
+	for _, class := range file.Classes {
+		fqcn := file.FQCN(class)
+		i.Classes[fqcn] = true
+
+		if strings.Contains(class.Doc, "@internal") {
+			i.Internal[fqcn] = true
+		}
+
+		if entityName := class.Constants["ENTITY_NAME"]; entityName != "" {
+			i.EntityTables[entityName] = true
+		}
+
+		if strings.HasSuffix(class.Name, "Events") || strings.HasSuffix(class.Name, "Event") {
+			for constant := range class.Constants {
+				i.EventConstants[fqcn+"::"+constant] = true
+			}
+		}
+	}
+}
+
+// loadCoreIndex returns the class index of the Shopware version the
+// extension is checked against. It is a package variable so tests can
+// provide an index without vendor directory or network.
+var loadCoreIndex = func(ctx context.Context, config ToolConfig) (*coreIndex, error) {
+	coreVendor := filepath.Join(config.RootDir, "vendor", "shopware", "core")
+
+	if installed := installedComposerVersion(config.RootDir, "shopware/core"); installed != "" {
+		if _, err := os.Stat(coreVendor); err == nil {
+			return cachedIndex(ctx, "core-index", coreIndexCacheVersion, installed, func() (*coreIndex, error) {
+				return buildCoreIndexFromVendor(installed, filepath.Join(config.RootDir, "vendor", "shopware"))
+			})
+		}
+	}
+
+	shopwareVersion, err := checkedShopwareVersion(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return cachedIndex(ctx, "core-index", coreIndexCacheVersion, shopwareVersion, func() (*coreIndex, error) {
+		return downloadCoreIndex(ctx, shopwareVersion)
+	})
+}
+
+// buildCoreIndexFromVendor indexes the PHP classes of the Shopware packages
+// in vendor/shopware.
+func buildCoreIndexFromVendor(shopwareVersion, vendorDir string) (*coreIndex, error) {
+	index := newCoreIndex(shopwareVersion)
+
+	for _, pkg := range coreIndexPackages {
+		pkgDir := filepath.Join(vendorDir, pkg)
+		if _, err := os.Stat(pkgDir); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(pkgDir, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() || filepath.Ext(file) != ".php" {
+				return nil
+			}
+
+			rel, err := filepath.Rel(pkgDir, file)
+			if err != nil {
+				return err
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			index.addSource(filepath.ToSlash(rel), string(content))
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return index, nil
+}
+
+// downloadCoreIndex indexes the release archives of the split repositories.
+func downloadCoreIndex(ctx context.Context, shopwareVersion string) (*coreIndex, error) {
+	index := newCoreIndex(shopwareVersion)
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	logging.FromContext(ctx).Infof("Downloading the PHP classes of Shopware %s", shopwareVersion)
+
+	for _, repository := range coreIndexPackages {
+		if err := downloadCoreArchive(ctx, fmt.Sprintf(templateIndexArchiveURL, repository, shopwareVersion), index); err != nil {
+			return nil, fmt.Errorf("download %s classes of %s: %w", repository, shopwareVersion, err)
+		}
+	}
+
+	return index, nil
+}
+
+func downloadCoreArchive(ctx context.Context, url string, index *coreIndex) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return indexCoreArchive(resp.Body, index)
+}
+
+// indexCoreArchive reads the PHP files of a GitHub tarball, which contains
+// the repository in a single top level folder.
+func indexCoreArchive(r io.Reader, index *coreIndex) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		_, name, found := strings.Cut(header.Name, "/")
+		if !found || path.Ext(name) != ".php" {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+
+		index.addSource(name, string(content))
+	}
+}
@@ -0,0 +1,319 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// DeprecatedBlocks reports Storefront templates and Twig blocks the extension
+// extends, which are removed or deprecated in the Shopware version it is
+// checked against.
+type DeprecatedBlocks struct{}
+
+func (d DeprecatedBlocks) Name() string {
+	return "deprecated-blocks"
+}
+
+func (d DeprecatedBlocks) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	if !hasTwigTemplates(config) {
+		return nil
+	}
+
+	index, err := loadTemplateIndex(ctx, config)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Skipping the deprecated block check, the template index could not be loaded: %v", err)
+		return nil
+	}
+
+	for _, sourceDirectory := range config.SourceDirectories {
+		err := walkTwigFiles(filepath.Join(sourceDirectory, "Resources", "views"), func(path, content string) {
+			parsed, err := html.NewStorefrontParser(content)
+			if err != nil {
+				// reported by storefront-twig
+				return
+			}
+
+			for _, result := range checkStorefrontTemplate(index, parsed.Nodes) {
+				result.Path = relativeResultPath(path, config.RootDir)
+				check.AddResult(result)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	ownBlocks := map[string]bool{}
+	adminTemplates := map[string]html.NodeList{}
+
+	for _, adminDirectory := range config.AdminDirectories {
+		err := walkTwigFiles(adminDirectory, func(path, content string) {
+			parsed, err := html.NewAdminParser(content)
+			if err != nil {
+				return
+			}
+
+			adminTemplates[path] = parsed.Nodes
+
+			walkBlocks(parsed.Nodes, func(block *html.TwigBlockNode) {
+				if !callsParent(block.Children) {
+					ownBlocks[block.Name] = true
+				}
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for path, nodes := range adminTemplates {
+		for _, result := range checkAdministrationTemplate(index, nodes, ownBlocks) {
+			result.Path = relativeResultPath(path, config.RootDir)
+			check.AddResult(result)
+		}
+	}
+
+	return nil
+}
+
+func (d DeprecatedBlocks) Fix(ctx context.Context, config ToolConfig) error {
+	return nil
+}
+
+func (d DeprecatedBlocks) Format(ctx context.Context, config ToolConfig, dryRun bool) error {
+	return nil
+}
+
+// checkStorefrontTemplate checks the sw_extends target of the template and
+// the blocks it overrides. Only blocks on the top level have to exist in the
+// parent, nested blocks can be new ones.
+func checkStorefrontTemplate(index *templateIndex, nodes html.NodeList) []validation.CheckResult {
+	var results []validation.CheckResult
+
+	target := storefrontExtends(nodes)
+	if target == "" {
+		return nil
+	}
+
+	line := 0
+	for _, node := range nodes {
+		if tag, ok := node.(*html.TwigStandaloneTagNode); ok && (tag.Name == "sw_extends" || tag.Name == "extends") {
+			line = tag.Line
+			break
+		}
+	}
+
+	blocks, ok := index.storefrontBlocks(target)
+	if !ok {
+		return append(results, validation.CheckResult{
+			Line:       line,
+			Message:    fmt.Sprintf("The extended template @Storefront/%s does not exist in Shopware %s", target, index.Version),
+			Severity:   validation.SeverityError,
+			Identifier: "deprecated-blocks/template-removed",
+		})
+	}
+
+	if deprecated := index.Storefront[target].Deprecated; deprecated != "" {
+		results = append(results, validation.CheckResult{
+			Line:       line,
+			Message:    fmt.Sprintf("The extended template @Storefront/%s is deprecated in Shopware %s: %s", target, index.Version, deprecated),
+			Severity:   validation.SeverityWarning,
+			Identifier: "deprecated-blocks/template-deprecated",
+		})
+	}
+
+	for _, node := range nodes {
+		block, ok := node.(*html.TwigBlockNode)
+		if !ok {
+			continue
+		}
+
+		if _, exists := blocks[block.Name]; !exists {
+			results = append(results, validation.CheckResult{
+				Line:       block.Line,
+				Message:    fmt.Sprintf("The block %s does not exist in @Storefront/%s of Shopware %s, the override has no effect", block.Name, target, index.Version),
+				Severity:   validation.SeverityError,
+				Identifier: "deprecated-blocks/block-removed",
+			})
+		}
+	}
+
+	walkBlocks(nodes, func(block *html.TwigBlockNode) {
+		if deprecated := blocks[block.Name]; deprecated != "" {
+			results = append(results, validation.CheckResult{
+				Line:       block.Line,
+				Message:    fmt.Sprintf("The block %s of @Storefront/%s is deprecated in Shopware %s: %s", block.Name, target, index.Version, deprecated),
+				Severity:   validation.SeverityWarning,
+				Identifier: "deprecated-blocks/block-deprecated",
+			})
+		}
+	})
+
+	return results
+}
+
+// checkAdministrationTemplate checks the blocks of a component template. As
+// the component is only known to the JavaScript, a block counts as override
+// of Shopware when it calls {% parent %} and no template of the extension
+// defines it.
+func checkAdministrationTemplate(index *templateIndex, nodes html.NodeList, ownBlocks map[string]bool) []validation.CheckResult {
+	var results []validation.CheckResult
+
+	walkBlocks(nodes, func(block *html.TwigBlockNode) {
+		deprecated, exists := index.Administration[block.Name]
+
+		switch {
+		case !exists && callsParent(block.Children) && !ownBlocks[block.Name]:
+			results = append(results, validation.CheckResult{
+				Line:       block.Line,
+				Message:    fmt.Sprintf("The administration block %s does not exist in Shopware %s, the override has no effect", block.Name, index.Version),
+				Severity:   validation.SeverityError,
+				Identifier: "deprecated-blocks/block-removed",
+			})
+		case exists && deprecated != "":
+			results = append(results, validation.CheckResult{
+				Line:       block.Line,
+				Message:    fmt.Sprintf("The administration block %s is deprecated in Shopware %s: %s", block.Name, index.Version, deprecated),
+				Severity:   validation.SeverityWarning,
+				Identifier: "deprecated-blocks/block-deprecated",
+			})
+		}
+	})
+
+	return results
+}
+
+// walkBlocks calls f for every block in the nodes, outer blocks first.
+func walkBlocks(nodes html.NodeList, f func(block *html.TwigBlockNode)) {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *html.TwigBlockNode:
+			f(node)
+			walkBlocks(node.Children, f)
+		case *html.ElementNode:
+			walkBlocks(node.Children, f)
+		case *html.TwigIfNode:
+			for _, branch := range node.Branches {
+				walkBlocks(branch.Body, f)
+			}
+
+			walkBlocks(node.ElseChildren, f)
+		case *html.TwigGenericBlockNode:
+			walkBlocks(node.Body, f)
+			walkBlocks(node.Else, f)
+		}
+	}
+}
+
+// callsParent reports whether the block renders the overridden block with
+// {% parent %}, not counting nested blocks.
+func callsParent(nodes html.NodeList) bool {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *html.ParentNode:
+			return true
+		case *html.ElementNode:
+			if callsParent(node.Children) {
+				return true
+			}
+		case *html.TwigIfNode:
+			for _, branch := range node.Branches {
+				if callsParent(branch.Body) {
+					return true
+				}
+			}
+
+			if callsParent(node.ElseChildren) {
+				return true
+			}
+		case *html.TwigGenericBlockNode:
+			if callsParent(node.Body) || callsParent(node.Else) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasTwigTemplates(config ToolConfig) bool {
+	dirs := append([]string{}, config.AdminDirectories...)
+	for _, sourceDirectory := range config.SourceDirectories {
+		dirs = append(dirs, filepath.Join(sourceDirectory, "Resources", "views"))
+	}
+
+	found := false
+
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() && filepath.Ext(path) == twiglinter.TwigExtension {
+				found = true
+				return fs.SkipAll
+			}
+
+			return nil
+		})
+
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// walkTwigFiles calls f with the content of every Twig file in the directory.
+// Missing directories are skipped, as well as node_modules.
+func walkTwigFiles(dir string, f func(path, content string)) error {
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(path) != twiglinter.TwigExtension {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		f(path, string(content))
+
+		return nil
+	})
+}
+
+func relativeResultPath(path, rootDir string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, "/private"), rootDir+"/")
+}
+
+func init() {
+	AddTool(DeprecatedBlocks{})
+}
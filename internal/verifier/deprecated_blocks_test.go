@@ -0,0 +1,249 @@
+package verifier
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/html"
+)
+
+func testTemplateIndex() *templateIndex {
+	index := newTemplateIndex("6.7.0.0")
+
+	index.addStorefrontTemplate("storefront/base.html.twig", `{% block base_html %}<html>{% block base_body %}<body>{% block base_main %}{% endblock %}</body>{% endblock %}</html>{% endblock %}`)
+	index.addStorefrontTemplate("storefront/page/product-detail/index.html.twig", `{% sw_extends '@Storefront/storefront/base.html.twig' %}
+
+{# @deprecated tag:v6.8.0 - Will be removed, use page_product_detail_content instead #}
+{% block page_product_detail_inner %}
+    <div></div>
+{% endblock %}
+
+{% block page_product_detail_content %}
+    <div></div>
+    {# @deprecated tag:v6.8.0 - The buy block will be removed #}
+    {% block page_product_detail_buy %}{% endblock %}
+{% endblock %}
+
+{% block page_product_detail_tabs %}
+    {% deprecated 'The tabs are removed' %}
+    <div></div>
+{% endblock %}`)
+	index.addStorefrontTemplate("storefront/component/old.html.twig", `{# @deprecated tag:v6.8.0 - Template will be removed #}
+{% sw_extends '@Storefront/storefront/base.html.twig' %}
+
+{% block component_old %}{% endblock %}`)
+
+	index.addAdministrationTemplate(`{% block sw_product_detail %}<div>{% block sw_product_detail_content %}{% endblock %}</div>{% endblock %}`)
+	index.addAdministrationTemplate(`{# @deprecated tag:v6.8.0 - Will be removed #}
+{% block sw_order_list_old %}{% endblock %}`)
+
+	return index
+}
+
+func TestTemplateIndexCollectsBlocksAndDeprecations(t *testing.T) {
+	t.Parallel()
+
+	index := testTemplateIndex()
+
+	detail := index.Storefront["storefront/page/product-detail/index.html.twig"]
+	require.NotNil(t, detail)
+	assert.Equal(t, "storefront/base.html.twig", detail.Extends)
+	assert.Empty(t, detail.Deprecated)
+	assert.Equal(t, "@deprecated tag:v6.8.0 - Will be removed, use page_product_detail_content instead", detail.Blocks["page_product_detail_inner"])
+	assert.Equal(t, "", detail.Blocks["page_product_detail_content"])
+	assert.Equal(t, "@deprecated tag:v6.8.0 - The buy block will be removed", detail.Blocks["page_product_detail_buy"])
+	assert.Equal(t, "The tabs are removed", detail.Blocks["page_product_detail_tabs"])
+
+	assert.Equal(t, "@deprecated tag:v6.8.0 - Template will be removed", index.Storefront["storefront/component/old.html.twig"].Deprecated)
+	assert.Empty(t, index.Storefront["storefront/component/old.html.twig"].Blocks["component_old"])
+
+	blocks, ok := index.storefrontBlocks("storefront/page/product-detail/index.html.twig")
+	assert.True(t, ok)
+	assert.Contains(t, blocks, "base_main")
+	assert.Contains(t, blocks, "page_product_detail_buy")
+
+	assert.Equal(t, "", index.Administration["sw_product_detail_content"])
+	assert.Equal(t, "@deprecated tag:v6.8.0 - Will be removed", index.Administration["sw_order_list_old"])
+}
+
+func parseStorefront(t *testing.T, content string) html.NodeList {
+	t.Helper()
+
+	parsed, err := html.NewStorefrontParser(content)
+	require.NoError(t, err)
+
+	return parsed.Nodes
+}
+
+func TestCheckStorefrontTemplate(t *testing.T) {
+	t.Parallel()
+
+	index := testTemplateIndex()
+
+	cases := []struct {
+		name        string
+		content     string
+		identifiers []string
+	}{
+		{
+			name:    "not extending",
+			content: `{% block my_block %}{% endblock %}`,
+		},
+		{
+			name:    "existing inherited block with new nested block",
+			content: `{% sw_extends '@Storefront/storefront/page/product-detail/index.html.twig' %}{% block base_main %}{% block my_plugin_block %}{% endblock %}{% endblock %}`,
+		},
+		{
+			name:        "removed template",
+			content:     `{% sw_extends '@Storefront/storefront/page/gone.html.twig' %}{% block a %}{% endblock %}`,
+			identifiers: []string{"deprecated-blocks/template-removed"},
+		},
+		{
+			name:        "deprecated template",
+			content:     `{% sw_extends '@Storefront/storefront/component/old.html.twig' %}{% block component_old %}{% endblock %}`,
+			identifiers: []string{"deprecated-blocks/template-deprecated"},
+		},
+		{
+			name:        "removed block",
+			content:     `{% sw_extends '@Storefront/storefront/page/product-detail/index.html.twig' %}{% block page_product_detail_gone %}{% endblock %}`,
+			identifiers: []string{"deprecated-blocks/block-removed"},
+		},
+		{
+			name:        "deprecated blocks",
+			content:     `{% sw_extends { template: '@Storefront/storefront/page/product-detail/index.html.twig', scopes: ['default'] } %}{% block page_product_detail_inner %}{% endblock %}{% block page_product_detail_content %}{% block page_product_detail_buy %}{% endblock %}{% endblock %}`,
+			identifiers: []string{"deprecated-blocks/block-deprecated", "deprecated-blocks/block-deprecated"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var identifiers []string
+			for _, result := range checkStorefrontTemplate(index, parseStorefront(t, tc.content)) {
+				identifiers = append(identifiers, result.Identifier)
+			}
+
+			assert.Equal(t, tc.identifiers, identifiers)
+		})
+	}
+}
+
+func TestCheckAdministrationTemplate(t *testing.T) {
+	t.Parallel()
+
+	index := testTemplateIndex()
+
+	parsed, err := html.NewAdminParser(`{% block sw_product_detail_content %}{% parent %}{% endblock %}
+{% block sw_product_detail_gone %}{% parent %}{% endblock %}
+{% block my_plugin_override %}{% parent %}{% endblock %}
+{% block my_plugin_new %}<div></div>{% endblock %}
+{% block sw_order_list_old %}{% endblock %}`)
+	require.NoError(t, err)
+
+	results := checkAdministrationTemplate(index, parsed.Nodes, map[string]bool{"my_plugin_override": true})
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "deprecated-blocks/block-removed", results[0].Identifier)
+	assert.Equal(t, 2, results[0].Line)
+	assert.Equal(t, "deprecated-blocks/block-deprecated", results[1].Identifier)
+	assert.Equal(t, 5, results[1].Line)
+}
+
+func TestDeprecatedBlocksCheck(t *testing.T) {
+	root := t.TempDir()
+	views := filepath.Join(root, "src", "Resources", "views", "storefront", "page", "product-detail")
+	require.NoError(t, os.MkdirAll(views, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(views, "index.html.twig"), []byte(`{% sw_extends '@Storefront/storefront/page/product-detail/index.html.twig' %}
+
+{% block page_product_detail_gone %}{% endblock %}
+`), 0o644))
+
+	original := loadTemplateIndex
+	t.Cleanup(func() { loadTemplateIndex = original })
+	loadTemplateIndex = func(context.Context, ToolConfig) (*templateIndex, error) {
+		return testTemplateIndex(), nil
+	}
+
+	check := NewCheck()
+	err := DeprecatedBlocks{}.Check(t.Context(), check, ToolConfig{
+		RootDir:           root,
+		SourceDirectories: []string{filepath.Join(root, "src")},
+	})
+	require.NoError(t, err)
+
+	results := check.GetResults()
+	require.Len(t, results, 1)
+	assert.Equal(t, "src/Resources/views/storefront/page/product-detail/index.html.twig", results[0].Path)
+	assert.Equal(t, 3, results[0].Line)
+	assert.Equal(t, "deprecated-blocks/block-removed", results[0].Identifier)
+}
+
+func TestIndexTemplateArchive(t *testing.T) {
+	t.Parallel()
+
+	index := newTemplateIndex("6.7.0.0")
+
+	require.NoError(t, indexTemplateArchive(templateArchive(t, map[string]string{
+		"administration-6.7.0.0/Resources/app/administration/src/module/sw-product/page/index.html.twig": `{% block sw_product_page %}{% endblock %}`,
+		"administration-6.7.0.0/Resources/app/administration/src/module/sw-product/index.ts":             `Shopware.Component.register('sw-product-page', () => import('./page'));`,
+		"administration-6.7.0.0/Resources/app/administration/src/app/mixin/notification.mixin.ts":        `export default Mixin.register('notification', {});`,
+		"administration-6.7.0.0/Resources/app/administration/src/app/init/services.ts":                   `Application.addServiceProvider('productService', () => {});`,
+		"administration-6.7.0.0/Resources/app/administration/src/module/sw-product/index.spec.js":        `Shopware.Component.register('sw-test', {});`,
+		"administration-6.7.0.0/Resources/app/administration/test/fixtures/ignored.html.twig":            `{% block ignored %}{% endblock %}`,
+	}), "administration", index))
+
+	assert.Empty(t, index.Storefront)
+	assert.Equal(t, map[string]string{"sw_product_page": ""}, index.Administration)
+	assert.Equal(t, map[string]bool{"sw-product-page": true}, index.AdministrationRegistry.Components)
+	assert.Equal(t, map[string]bool{"notification": true}, index.AdministrationRegistry.Mixins)
+	assert.Equal(t, map[string]bool{"productService": true}, index.AdministrationRegistry.Services)
+
+	require.NoError(t, indexTemplateArchive(templateArchive(t, map[string]string{
+		"storefront-6.7.0.0/Resources/views/storefront/base.html.twig": `{% block base_body %}{% endblock %}`,
+		"storefront-6.7.0.0/Resources/app/storefront/src/main.js":      `export default {};`,
+	}), "storefront", index))
+
+	assert.Len(t, index.Storefront, 1)
+	assert.Contains(t, index.Storefront["storefront/base.html.twig"].Blocks, "base_body")
+	assert.Equal(t, map[string]string{"sw_product_page": ""}, index.Administration)
+}
+
+// templateArchive builds a tar.gz like the GitHub tarballs of the shopware packages.
+func templateArchive(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return &buf
+}
+
+func TestInstalledComposerVersion(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "vendor", "composer"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vendor", "composer", "installed.json"), []byte(`{"packages":[{"name":"shopware/storefront","version":"v6.6.10.4"}]}`), 0o644))
+
+	assert.Equal(t, "6.6.10.4", installedComposerVersion(root, "shopware/storefront"))
+	assert.Equal(t, "", installedComposerVersion(root, "shopware/core"))
+}
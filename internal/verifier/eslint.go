@@ -41,6 +41,10 @@ type EslintOutput []struct {
 	UsedDeprecatedRules []any  `json:"usedDeprecatedRules"`
 }
 
+// eslintExtensions are the files the ESLint configs lint, used to pass only
+// changed files to ESLint.
+var eslintExtensions = []string{".js", ".mjs", ".cjs", ".ts", ".vue", ".json"}
+
 type Eslint struct{}
 
 func (e Eslint) Name() string {
@@ -58,9 +62,18 @@ func (e Eslint) Check(ctx context.Context, check *Check, config ToolConfig) erro
 	for _, p := range paths {
 		p := p
 
+		var files []string
+
+		if config.IsIncremental() {
+			files = config.ChangedFilesIn(p, eslintExtensions...)
+
+			if len(files) == 0 {
+				continue
+			}
+		}
+
 		gr.Go(func() error {
-			eslint := exec.CommandContext(ctx,
-				"node",
+			args := []string{
 				path.Join(config.ToolDirectory, "js", "node_modules", ".bin", "eslint"),
 				"--format=json",
 				"--config", path.Join(config.ToolDirectory, "js", "configs", fmt.Sprintf("eslint.config.%s.mjs", path.Base(p))),
@@ -70,7 +83,9 @@ func (e Eslint) Check(ctx context.Context, check *Check, config ToolConfig) erro
 				"--ignore-pattern", "test/e2e/**",
 				"--ignore-pattern", "**/jest.config.js",
 				"--no-error-on-unmatched-pattern",
-			)
+			}
+
+			eslint := exec.CommandContext(ctx, "node", append(args, files...)...)
 			eslint.Dir = p
 			eslint.Env = env
 
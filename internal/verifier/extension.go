@@ -27,12 +27,17 @@ func ConvertExtensionToToolConfig(ext extension.Extension) (*ToolConfig, error)
 		ToolDirectory:         GetToolDirectory(),
 		Extension:             ext,
 		ValidationIgnores:     ignores,
+		SeverityOverrides:     ext.GetExtensionConfig().Validation.Severity,
 		RootDir:               ext.GetPath(),
 		SourceDirectories:     ext.GetSourceDirs(),
 		AdminDirectories:      getAdminFolders(ext),
 		StorefrontDirectories: getStorefrontFolders(ext),
 	}
 
+	if err := ValidateSeverityOverrides(cfg.SeverityOverrides); err != nil {
+		return nil, err
+	}
+
 	constraint, err := ext.GetShopwareVersionConstraint()
 	if err != nil {
 		return nil, err
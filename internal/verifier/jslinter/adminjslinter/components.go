@@ -0,0 +1,155 @@
+package adminjslinter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+// meteorReplacements are the components replaced by the Meteor component
+// library with 6.7, the templates are migrated by the admintwiglinter fixers.
+var meteorReplacements = map[string]string{
+	"sw-alert":          "mt-banner",
+	"sw-button":         "mt-button",
+	"sw-card":           "mt-card",
+	"sw-checkbox-field": "mt-checkbox",
+	"sw-colorpicker":    "mt-colorpicker",
+	"sw-datepicker":     "mt-datepicker",
+	"sw-email-field":    "mt-email-field",
+	"sw-external-link":  "mt-external-link",
+	"sw-icon":           "mt-icon",
+	"sw-loader":         "mt-loader",
+	"sw-number-field":   "mt-number-field",
+	"sw-password-field": "mt-password-field",
+	"sw-popover":        "mt-floating-ui",
+	"sw-progress-bar":   "mt-progress-bar",
+	"sw-select-field":   "mt-select",
+	"sw-skeleton-bar":   "mt-skeleton-bar",
+	"sw-switch-field":   "mt-switch",
+	"sw-text-field":     "mt-text-field",
+	"sw-textarea-field": "mt-textarea",
+	"sw-url-field":      "mt-url-field",
+}
+
+type componentReference struct {
+	name   string
+	action string
+	line   int
+	// token is the index of the name argument
+	token int
+}
+
+// componentReferences returns the components the file overrides, extends or
+// builds.
+func componentReferences(file *jslinter.File) []componentReference {
+	var refs []componentReference
+
+	for _, call := range file.Calls("Component.override", "Component.extend", "Component.build") {
+		action, arg := "override", 0
+
+		switch {
+		case strings.HasSuffix("."+call.Callee, ".Component.extend"):
+			action, arg = "extension", 1
+		case strings.HasSuffix("."+call.Callee, ".Component.build"):
+			action = "build"
+		}
+
+		if name, ok := file.StringArg(call, arg); ok {
+			refs = append(refs, componentReference{name: name, action: action, line: file.Tokens[call.Start].Line, token: call.Args[arg][0]})
+		}
+	}
+
+	return refs
+}
+
+// RemovedComponentCheck reports overrides and extensions of components the
+// checked Shopware version does not register anymore.
+type RemovedComponentCheck struct{}
+
+func init() {
+	jslinter.AddAdministrationFixer(RemovedComponentCheck{})
+	jslinter.AddAdministrationFixer(MeteorComponentCheck{})
+}
+
+func (r RemovedComponentCheck) Check(file *jslinter.File) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	for _, ref := range componentReferences(file) {
+		if file.HasComponent(ref.name) {
+			continue
+		}
+
+		message := fmt.Sprintf("The component %s does not exist in the administration, the %s has no effect.", ref.name, ref.action)
+		if replacement, ok := meteorReplacements[ref.name]; ok {
+			message += fmt.Sprintf(" It is replaced by %s.", replacement)
+		}
+
+		errors = append(errors, validation.CheckResult{
+			Message:    message,
+			Severity:   validation.SeverityError,
+			Identifier: "removed-component",
+			Line:       ref.line,
+		})
+	}
+
+	return errors
+}
+
+func (r RemovedComponentCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+// Fix renames removed components to their Meteor replacement. Only
+// references the checked Shopware version cannot resolve are renamed, and
+// only when it registers the replacement.
+func (r RemovedComponentCheck) Fix(file *jslinter.File) error {
+	for _, ref := range componentReferences(file) {
+		replacement, ok := meteorReplacements[ref.name]
+		if !ok || file.HasComponent(ref.name) || !file.Core.Components[replacement] {
+			continue
+		}
+
+		file.Replace(ref.token, ref.token+1, strings.Replace(file.Text(ref.token, ref.token+1), ref.name, replacement, 1))
+	}
+
+	return nil
+}
+
+// MeteorComponentCheck reports overrides of components replaced by Meteor
+// components, as the templates of Shopware do not use them anymore.
+type MeteorComponentCheck struct{}
+
+func (m MeteorComponentCheck) Check(file *jslinter.File) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	for _, ref := range componentReferences(file) {
+		replacement, ok := meteorReplacements[ref.name]
+		if !ok || !file.HasComponent(ref.name) {
+			continue
+		}
+
+		errors = append(errors, validation.CheckResult{
+			Message:    fmt.Sprintf("%s is replaced by %s, the %s only affects templates still using %s.", ref.name, replacement, ref.action, ref.name),
+			Severity:   validation.SeverityWarning,
+			Identifier: "deprecated-component",
+			Line:       ref.line,
+		})
+	}
+
+	return errors
+}
+
+func (m MeteorComponentCheck) Supports(v *version.Version) bool {
+	return twiglinter.Shopware67Constraint.Check(v)
+}
+
+// Fix leaves the references alone, the component still exists and the
+// override still applies to templates using it.
+func (m MeteorComponentCheck) Fix(file *jslinter.File) error {
+	return nil
+}
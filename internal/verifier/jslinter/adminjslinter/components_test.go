@@ -0,0 +1,103 @@
+package adminjslinter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+)
+
+func testRegistry() *jslinter.Registry {
+	registry := jslinter.NewRegistry()
+	registry.Components["sw-product-detail"] = true
+	registry.Components["sw-text-field"] = true
+	registry.Mixins["notification"] = true
+	registry.Services["repositoryFactory"] = true
+	registry.Services["systemConfigApiService"] = true
+
+	return registry
+}
+
+func TestRemovedComponentCheck(t *testing.T) {
+	t.Parallel()
+
+	checks, err := jslinter.RunCheckerOnString(RemovedComponentCheck{}, `Shopware.Component.override('sw-product-detail', {});
+Shopware.Component.override('sw-product-old', {});
+Component.extend('my-button', 'sw-button', {});
+Component.build('sw-product-detail');`, testRegistry())
+	assert.NoError(t, err)
+	assert.Len(t, checks, 2)
+	assert.Equal(t, "removed-component", checks[0].Identifier)
+	assert.Equal(t, 2, checks[0].Line)
+	assert.Contains(t, checks[1].Message, "It is replaced by mt-button")
+
+	checks, err = jslinter.RunCheckerOnString(RemovedComponentCheck{}, `Component.override('sw-product-old', {});`, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, checks, "Without registry nothing is known to be removed")
+}
+
+func TestRemovedComponentFix(t *testing.T) {
+	t.Parallel()
+
+	registry := testRegistry()
+	registry.Components["mt-button"] = true
+
+	fixed, err := jslinter.RunFixerOnString(RemovedComponentCheck{}, `Component.override('sw-button', {});
+Component.extend('my-button', "sw-button", {});
+Component.override('sw-card', {});
+Component.override('sw-text-field', {});
+Component.override('sw-product-old', {});`, registry)
+	assert.NoError(t, err)
+	assert.Equal(t, `Component.override('mt-button', {});
+Component.extend('my-button', "mt-button", {});
+Component.override('sw-card', {});
+Component.override('sw-text-field', {});
+Component.override('sw-product-old', {});`, fixed, "only removed components with a registered replacement are renamed")
+
+	fixed, err = jslinter.RunFixerOnString(RemovedComponentCheck{}, `Component.override('sw-button', {});`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `Component.override('sw-button', {});`, fixed, "Without registry nothing is known to be removed")
+}
+
+func TestMeteorComponentCheck(t *testing.T) {
+	t.Parallel()
+
+	checks, err := jslinter.RunCheckerOnString(MeteorComponentCheck{}, `Component.override('sw-text-field', {});
+Component.override('sw-button', {});
+Component.override('sw-product-detail', {});`, testRegistry())
+	assert.NoError(t, err)
+	assert.Len(t, checks, 1, "sw-button is reported as removed instead")
+	assert.Equal(t, "deprecated-component", checks[0].Identifier)
+	assert.Contains(t, checks[0].Message, "mt-text-field")
+}
+
+func TestRemovedMixinCheck(t *testing.T) {
+	t.Parallel()
+
+	checks, err := jslinter.RunCheckerOnString(RemovedMixinCheck{}, `export default {
+    mixins: [Mixin.getByName('notification'), Shopware.Mixin.getByName('sw-removed')],
+};`, testRegistry())
+	assert.NoError(t, err)
+	assert.Len(t, checks, 1)
+	assert.Equal(t, "removed-mixin", checks[0].Identifier)
+	assert.Equal(t, 2, checks[0].Line)
+}
+
+func TestInjectCheck(t *testing.T) {
+	t.Parallel()
+
+	checks, err := jslinter.RunCheckerOnString(InjectCheck{}, `export default {
+    inject: ['repositoryFactory', 'oldApiService', 'swProductDetail'],
+};
+const b = {
+    inject: {
+        config: 'systemConfigApiService',
+        other: { from: 'removedService', default: 'fallbackService' },
+    },
+};`, testRegistry())
+	assert.NoError(t, err)
+	assert.Len(t, checks, 2)
+	assert.Contains(t, checks[0].Message, "oldApiService")
+	assert.Contains(t, checks[1].Message, "removedService")
+}
@@ -0,0 +1,75 @@
+package adminjslinter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+)
+
+// InjectCheck reports injected services the checked Shopware version does
+// not register anymore. Only names ending with Service are checked, as
+// components can also inject what their parents provide.
+type InjectCheck struct{}
+
+func init() {
+	jslinter.AddAdministrationFixer(InjectCheck{})
+}
+
+// injectedNames returns the string tokens of inject: ['a'] and
+// inject: { a: 'a', b: { from: 'b', default: 'c' } }.
+func injectedNames(file *jslinter.File) []jslinter.Token {
+	var names []jslinter.Token
+
+	for i := range file.Tokens {
+		if !file.IsIdentifier(i, "inject") || !file.IsPunctuator(i-1, "{", ",") || !file.IsPunctuator(i+1, ":") || !file.IsPunctuator(i+2, "[", "{") {
+			continue
+		}
+
+		isArray := file.IsPunctuator(i+2, "[")
+
+		for j := i + 3; j < file.Closing(i+2); j++ {
+			tok := file.Tokens[j]
+			if tok.Kind != jslinter.TokenString {
+				continue
+			}
+
+			if isArray || (file.IsPunctuator(j-1, ":") && !file.IsIdentifier(j-2, "default")) {
+				names = append(names, tok)
+			}
+		}
+	}
+
+	return names
+}
+
+func (c InjectCheck) Check(file *jslinter.File) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	for _, tok := range injectedNames(file) {
+		if !strings.HasSuffix(tok.Value, "Service") || file.HasService(tok.Value) {
+			continue
+		}
+
+		errors = append(errors, validation.CheckResult{
+			Message:    fmt.Sprintf("The service %s is not registered in the administration, the injection is undefined.", tok.Value),
+			Severity:   validation.SeverityWarning,
+			Identifier: "unknown-injection",
+			Line:       tok.Line,
+		})
+	}
+
+	return errors
+}
+
+func (c InjectCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+// Fix leaves the injections alone, removed services have no replacement.
+func (c InjectCheck) Fix(file *jslinter.File) error {
+	return nil
+}
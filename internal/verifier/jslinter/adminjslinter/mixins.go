@@ -0,0 +1,47 @@
+package adminjslinter
+
+import (
+	"fmt"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+)
+
+// RemovedMixinCheck reports Mixin.getByName calls for mixins the checked
+// Shopware version does not register anymore.
+type RemovedMixinCheck struct{}
+
+func init() {
+	jslinter.AddAdministrationFixer(RemovedMixinCheck{})
+}
+
+func (r RemovedMixinCheck) Check(file *jslinter.File) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	for _, call := range file.Calls("Mixin.getByName") {
+		name, ok := file.StringArg(call, 0)
+		if !ok || file.HasMixin(name) {
+			continue
+		}
+
+		errors = append(errors, validation.CheckResult{
+			Message:    fmt.Sprintf("The mixin %s does not exist in the administration, Mixin.getByName throws an error.", name),
+			Severity:   validation.SeverityError,
+			Identifier: "removed-mixin",
+			Line:       file.Tokens[call.Start].Line,
+		})
+	}
+
+	return errors
+}
+
+func (r RemovedMixinCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+// Fix leaves the calls alone, removed mixins have no replacement.
+func (r RemovedMixinCheck) Fix(file *jslinter.File) error {
+	return nil
+}
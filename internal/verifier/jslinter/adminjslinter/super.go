@@ -0,0 +1,130 @@
+package adminjslinter
+
+import (
+	"fmt"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+)
+
+// SuperCallCheck reports this.$super calls for another method than the one
+// they are made in. $super resolves the method in the overridden component,
+// calling a different one is almost always a copy and paste mistake.
+type SuperCallCheck struct{}
+
+func init() {
+	jslinter.AddAdministrationFixer(SuperCallCheck{})
+}
+
+var controlKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "with": true, "function": true,
+}
+
+// enclosingMethods returns for each token the name of the method it is in.
+// Arrow functions and nested blocks keep the name of their method.
+func enclosingMethods(file *jslinter.File) []string {
+	names := make([]string, len(file.Tokens))
+	stack := []string{""}
+
+	for i, tok := range file.Tokens {
+		names[i] = stack[len(stack)-1]
+
+		if tok.Kind != jslinter.TokenPunctuator {
+			continue
+		}
+
+		switch tok.Value {
+		case "{":
+			stack = append(stack, methodName(file, i, stack[len(stack)-1]))
+		case "}":
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	return names
+}
+
+// methodName returns the name of the method whose body starts at the brace,
+// like name() {, async name() {, name: function () {.
+func methodName(file *jslinter.File, brace int, inherited string) string {
+	if !file.IsPunctuator(brace-1, ")") {
+		return inherited
+	}
+
+	open := -1
+	depth := 0
+
+	for j := brace - 1; j >= 0; j-- {
+		if file.IsPunctuator(j, ")") {
+			depth++
+		} else if file.IsPunctuator(j, "(") {
+			depth--
+			if depth == 0 {
+				open = j
+				break
+			}
+		}
+	}
+
+	if open < 1 || file.Tokens[open-1].Kind != jslinter.TokenIdentifier {
+		return inherited
+	}
+
+	name := file.Tokens[open-1].Value
+	if name == "function" {
+		if file.IsPunctuator(open-2, ":") && open >= 3 && file.Tokens[open-3].Kind == jslinter.TokenIdentifier {
+			return file.Tokens[open-3].Value
+		}
+
+		return inherited
+	}
+
+	if controlKeywords[name] {
+		return inherited
+	}
+
+	return name
+}
+
+func (s SuperCallCheck) Check(file *jslinter.File) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	calls := file.Calls("this.$super")
+	if len(calls) == 0 {
+		return nil
+	}
+
+	methods := enclosingMethods(file)
+
+	for _, call := range calls {
+		name, ok := file.StringArg(call, 0)
+		method := methods[call.Start]
+
+		if !ok || method == "" || name == method {
+			continue
+		}
+
+		errors = append(errors, validation.CheckResult{
+			Message:    fmt.Sprintf("this.$super('%s') is called in %s, $super should call the overridden %s.", name, method, method),
+			Severity:   validation.SeverityWarning,
+			Identifier: "super-call-mismatch",
+			Line:       file.Tokens[call.Start].Line,
+		})
+	}
+
+	return errors
+}
+
+func (s SuperCallCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+// Fix leaves the calls alone, the method meant by a mismatching call is
+// unknown.
+func (s SuperCallCheck) Fix(file *jslinter.File) error {
+	return nil
+}
@@ -0,0 +1,39 @@
+package adminjslinter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+)
+
+func TestSuperCallCheck(t *testing.T) {
+	t.Parallel()
+
+	checks, err := jslinter.RunCheckerOnString(SuperCallCheck{}, `Component.override('sw-product-detail', {
+    computed: {
+        productRepository() {
+            return this.$super('productRepository');
+        },
+    },
+    methods: {
+        async onSave() {
+            if (this.valid) {
+                await this.$super('onSave');
+            }
+
+            this.items.forEach((item) => {
+                this.$super('onSave', item);
+            });
+        },
+        createdComponent: function () {
+            this.$super('onSave');
+        },
+    },
+});`, nil)
+	assert.NoError(t, err)
+	assert.Len(t, checks, 1)
+	assert.Equal(t, "super-call-mismatch", checks[0].Identifier)
+	assert.Equal(t, 18, checks[0].Line)
+}
@@ -0,0 +1,151 @@
+package adminjslinter
+
+import (
+	"fmt"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+// Vue2APICheck reports Vue 2 APIs, which are gone since the administration
+// runs Vue 3 without the compatibility build in 6.7.
+type Vue2APICheck struct{}
+
+func init() {
+	jslinter.AddAdministrationFixer(Vue2APICheck{})
+}
+
+var renamedLifecycleHooks = map[string]string{
+	"beforeDestroy": "beforeUnmount",
+	"destroyed":     "unmounted",
+}
+
+// removedInstanceProperties cannot be rewritten automatically.
+var removedInstanceProperties = map[string]struct {
+	identifier string
+	message    string
+}{
+	"$listeners": {"vue-listeners", "$listeners is removed in Vue 3, the listeners are part of $attrs."},
+	"$children":  {"vue-children", "$children is removed in Vue 3, use template refs instead."},
+	"$on":        {"vue-event-api", "$on is removed in Vue 3, use Shopware.Utils.EventBus or component events instead."},
+	"$off":       {"vue-event-api", "$off is removed in Vue 3, use Shopware.Utils.EventBus or component events instead."},
+	"$once":      {"vue-event-api", "$once is removed in Vue 3, use Shopware.Utils.EventBus or component events instead."},
+}
+
+// isStatement reports whether the tokens [start, end] form a whole
+// expression statement, so they can be replaced by an assignment.
+func isStatement(file *jslinter.File, start, end int) bool {
+	before := start == 0 || file.IsPunctuator(start-1, ";", "{", "}")
+	after := end == len(file.Tokens)-1 || file.IsPunctuator(end+1, ";", "}") || file.Tokens[end+1].Line > file.Tokens[end].Line
+
+	return before && after
+}
+
+// lifecycleHooks returns the indexes of lifecycle hooks defined as object
+// keys, like destroyed() {} or destroyed: function () {}.
+func lifecycleHooks(file *jslinter.File) []int {
+	var hooks []int
+
+	for i, tok := range file.Tokens {
+		if _, ok := renamedLifecycleHooks[tok.Value]; !ok || tok.Kind != jslinter.TokenIdentifier {
+			continue
+		}
+
+		keyPosition := file.IsPunctuator(i-1, "{", ",") || (file.IsIdentifier(i-1, "async") && file.IsPunctuator(i-2, "{", ","))
+		if keyPosition && file.IsPunctuator(i+1, "(", ":") {
+			hooks = append(hooks, i)
+		}
+	}
+
+	return hooks
+}
+
+func (v Vue2APICheck) Check(file *jslinter.File) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	add := func(identifier, message string, line int) {
+		errors = append(errors, validation.CheckResult{
+			Message:    message,
+			Severity:   validation.SeverityWarning,
+			Identifier: identifier,
+			Line:       line,
+		})
+	}
+
+	for _, call := range file.Calls("this.$set") {
+		add("vue-set", "this.$set is removed in Vue 3, assign the property directly.", file.Tokens[call.Start].Line)
+	}
+
+	for _, call := range file.Calls("this.$delete") {
+		add("vue-delete", "this.$delete is removed in Vue 3, use the delete operator.", file.Tokens[call.Start].Line)
+	}
+
+	for _, i := range lifecycleHooks(file) {
+		hook := file.Tokens[i].Value
+		add("vue-lifecycle-hook", fmt.Sprintf("The lifecycle hook %s is renamed to %s in Vue 3.", hook, renamedLifecycleHooks[hook]), file.Tokens[i].Line)
+	}
+
+	for i, tok := range file.Tokens {
+		if tok.Kind != jslinter.TokenIdentifier || !file.IsPunctuator(i-1, ".", "?.") {
+			continue
+		}
+
+		if tok.Value == "$scopedSlots" {
+			add("vue-scoped-slots", "$scopedSlots is removed in Vue 3, use $slots.", tok.Line)
+			continue
+		}
+
+		if removed, ok := removedInstanceProperties[tok.Value]; ok {
+			add(removed.identifier, removed.message, tok.Line)
+		}
+	}
+
+	return errors
+}
+
+func (v Vue2APICheck) Supports(ver *version.Version) bool {
+	return twiglinter.Shopware67Constraint.Check(ver)
+}
+
+// Fix rewrites this.$set, this.$delete, $scopedSlots and the renamed
+// lifecycle hooks. this.$set is only rewritten as a whole statement, as an
+// assignment is not valid in every expression position.
+func (v Vue2APICheck) Fix(file *jslinter.File) error {
+	for _, call := range file.Calls("this.$set") {
+		if len(call.Args) != 3 || !isStatement(file, call.Start, call.Close) {
+			continue
+		}
+
+		file.Replace(call.Start, call.Close+1, fmt.Sprintf("%s[%s] = %s",
+			file.Text(call.Args[0][0], call.Args[0][1]),
+			file.Text(call.Args[1][0], call.Args[1][1]),
+			file.Text(call.Args[2][0], call.Args[2][1]),
+		))
+	}
+
+	for _, call := range file.Calls("this.$delete") {
+		if len(call.Args) != 2 {
+			continue
+		}
+
+		file.Replace(call.Start, call.Close+1, fmt.Sprintf("delete %s[%s]",
+			file.Text(call.Args[0][0], call.Args[0][1]),
+			file.Text(call.Args[1][0], call.Args[1][1]),
+		))
+	}
+
+	for _, i := range lifecycleHooks(file) {
+		file.Replace(i, i+1, renamedLifecycleHooks[file.Tokens[i].Value])
+	}
+
+	for i, tok := range file.Tokens {
+		if tok.Kind == jslinter.TokenIdentifier && tok.Value == "$scopedSlots" && file.IsPunctuator(i-1, ".", "?.") {
+			file.Replace(i, i+1, "$slots")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package adminjslinter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+)
+
+func TestVue2APICheck(t *testing.T) {
+	t.Parallel()
+
+	checks, err := jslinter.RunCheckerOnString(Vue2APICheck{}, `export default {
+    beforeDestroy() {
+        this.$off('event');
+    },
+    methods: {
+        a() {
+            this.$set(this.item, 'name', 'a');
+            this.$delete(this.item, 'name');
+            return this.$scopedSlots.default || this.$listeners;
+        },
+    },
+};`, nil)
+	assert.NoError(t, err)
+
+	var identifiers []string
+	for _, check := range checks {
+		identifiers = append(identifiers, check.Identifier)
+	}
+
+	assert.ElementsMatch(t, []string{"vue-set", "vue-delete", "vue-lifecycle-hook", "vue-event-api", "vue-scoped-slots", "vue-listeners"}, identifiers)
+}
+
+func TestVue2APIFix(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		before      string
+		after       string
+	}{
+		{
+			description: "set as statement",
+			before:      `function a() { this.$set(this.item, 'name', value); }`,
+			after:       `function a() { this.item['name'] = value; }`,
+		},
+		{
+			description: "set inside an expression is kept",
+			before:      `valid && this.$set(this.item, 'name', value);`,
+			after:       `valid && this.$set(this.item, 'name', value);`,
+		},
+		{
+			description: "delete",
+			before:      `this.$delete(this.items, index)`,
+			after:       `delete this.items[index]`,
+		},
+		{
+			description: "scoped slots",
+			before:      `const slot = this.$scopedSlots?.default;`,
+			after:       `const slot = this.$slots?.default;`,
+		},
+		{
+			description: "lifecycle hooks",
+			before:      `export default { async beforeDestroy() {}, destroyed: function () {}, destroyed: 1 }`,
+			after:       `export default { async beforeUnmount() {}, unmounted: function () {}, unmounted: 1 }`,
+		},
+		{
+			description: "unrelated identifiers are kept",
+			before:      `const destroyed = true; this.destroyed();`,
+			after:       `const destroyed = true; this.destroyed();`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+			after, err := jslinter.RunFixerOnString(Vue2APICheck{}, tc.before, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.after, after)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package jslinter
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/evanw/esbuild/pkg/api"
+)
+
+// File is a tokenized administration source file. Fixers record their
+// rewrites as edits, which are applied by Result.
+type File struct {
+	Source string
+	Tokens []Token
+	// Core lists what the checked Shopware version registers, nil when unknown
+	Core *Registry
+	// Own lists what the extension registers itself
+	Own *Registry
+
+	edits []edit
+}
+
+type edit struct {
+	start, end int
+	text       string
+}
+
+// ParseError is returned by Parse for sources esbuild cannot parse.
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Parse validates the source with esbuild and tokenizes it. TypeScript is
+// detected by the file name.
+func Parse(name, source string) (*File, error) {
+	loader := api.LoaderJS
+	if ext := filepath.Ext(name); ext == ".ts" || ext == ".mts" {
+		loader = api.LoaderTS
+	}
+
+	result := api.Transform(source, api.TransformOptions{
+		Loader:     loader,
+		Sourcefile: name,
+		LogLevel:   api.LogLevelSilent,
+	})
+
+	if len(result.Errors) > 0 {
+		line := 0
+		if result.Errors[0].Location != nil {
+			line = result.Errors[0].Location.Line
+		}
+
+		return nil, &ParseError{Line: line, Message: result.Errors[0].Text}
+	}
+
+	return NewFile(source), nil
+}
+
+// NewFile tokenizes the source without validating it.
+func NewFile(source string) *File {
+	return &File{Source: source, Tokens: Tokenize(source)}
+}
+
+// Call is a call expression like Shopware.Component.override('name', {}).
+type Call struct {
+	// Callee is the dotted path of the called function
+	Callee string
+	// Start is the index of the first token of the callee
+	Start int
+	// Open and Close are the indexes of the parentheses
+	Open  int
+	Close int
+	// Args are the token ranges [start, end) of the arguments
+	Args [][2]int
+}
+
+// Calls returns all calls to one of the paths. A path matches the end of the
+// callee, so Component.override matches Shopware.Component.override.
+func (f *File) Calls(paths ...string) []Call {
+	var calls []Call
+
+	for i, tok := range f.Tokens {
+		if tok.Kind != TokenPunctuator || tok.Value != "(" {
+			continue
+		}
+
+		start, callee := f.calleeBefore(i)
+		if callee == "" || !matchesPath(callee, paths) {
+			continue
+		}
+
+		closeIdx := f.Closing(i)
+		calls = append(calls, Call{
+			Callee: callee,
+			Start:  start,
+			Open:   i,
+			Close:  closeIdx,
+			Args:   f.splitArgs(i+1, closeIdx),
+		})
+	}
+
+	return calls
+}
+
+func matchesPath(callee string, paths []string) bool {
+	for _, p := range paths {
+		if callee == p || strings.HasSuffix(callee, "."+p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// calleeBefore collects the member chain ending before the token.
+func (f *File) calleeBefore(i int) (int, string) {
+	var parts []string
+
+	j := i - 1
+	for j >= 0 && f.Tokens[j].Kind == TokenIdentifier {
+		parts = append([]string{f.Tokens[j].Value}, parts...)
+
+		if j == 0 || !f.IsPunctuator(j-1, ".", "?.") {
+			break
+		}
+
+		j -= 2
+	}
+
+	if len(parts) == 0 {
+		return i, ""
+	}
+
+	return j, strings.Join(parts, ".")
+}
+
+// Closing returns the index of the bracket closing the one at index i, or
+// the last token for unbalanced sources.
+func (f *File) Closing(i int) int {
+	depth := 0
+
+	for j := i; j < len(f.Tokens); j++ {
+		if f.Tokens[j].Kind != TokenPunctuator {
+			continue
+		}
+
+		switch f.Tokens[j].Value {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+
+	return len(f.Tokens) - 1
+}
+
+// splitArgs splits the tokens between the brackets at the top level commas.
+func (f *File) splitArgs(start, end int) [][2]int {
+	var args [][2]int
+
+	argStart := start
+	depth := 0
+
+	for j := start; j < end; j++ {
+		if f.Tokens[j].Kind != TokenPunctuator {
+			continue
+		}
+
+		switch f.Tokens[j].Value {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		case ",":
+			if depth == 0 {
+				args = append(args, [2]int{argStart, j})
+				argStart = j + 1
+			}
+		}
+	}
+
+	if argStart < end {
+		args = append(args, [2]int{argStart, end})
+	}
+
+	return args
+}
+
+// StringArg returns the value of the argument when it is a plain string.
+func (f *File) StringArg(call Call, n int) (string, bool) {
+	if n >= len(call.Args) || call.Args[n][1]-call.Args[n][0] != 1 {
+		return "", false
+	}
+
+	tok := f.Tokens[call.Args[n][0]]
+
+	switch tok.Kind {
+	case TokenString:
+		return tok.Value, true
+	case TokenTemplate:
+		if !strings.Contains(tok.Value, "${") {
+			return strings.Trim(tok.Value, "`"), true
+		}
+	}
+
+	return "", false
+}
+
+// Text returns the source of the tokens [start, end).
+func (f *File) Text(start, end int) string {
+	if start >= end {
+		return ""
+	}
+
+	return f.Source[f.Tokens[start].Start:f.Tokens[end-1].End]
+}
+
+// IsPunctuator reports whether the token at index i is one of the values.
+func (f *File) IsPunctuator(i int, values ...string) bool {
+	if i < 0 || i >= len(f.Tokens) || f.Tokens[i].Kind != TokenPunctuator {
+		return false
+	}
+
+	for _, v := range values {
+		if f.Tokens[i].Value == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsIdentifier reports whether the token at index i is the identifier.
+func (f *File) IsIdentifier(i int, value string) bool {
+	return i >= 0 && i < len(f.Tokens) && f.Tokens[i].Kind == TokenIdentifier && f.Tokens[i].Value == value
+}
+
+// Replace rewrites the tokens [start, end) with the text.
+func (f *File) Replace(start, end int, text string) {
+	f.edits = append(f.edits, edit{start: f.Tokens[start].Start, end: f.Tokens[end-1].End, text: text})
+}
+
+// Result returns the source with all edits applied. Edits overlapping an
+// earlier one are dropped.
+func (f *File) Result() string {
+	edits := append([]edit{}, f.edits...)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+
+	pos := 0
+	for _, e := range edits {
+		if e.start < pos {
+			continue
+		}
+
+		b.WriteString(f.Source[pos:e.start])
+		b.WriteString(e.text)
+		pos = e.end
+	}
+
+	b.WriteString(f.Source[pos:])
+
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package jslinter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReportsSyntaxErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse("index.js", "const a = {\n  b: ;\n}")
+	var pe *ParseError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, 2, pe.Line)
+
+	file, err := Parse("index.ts", "const a: string = 'b';")
+	require.NoError(t, err)
+	assert.NotEmpty(t, file.Tokens)
+}
+
+func TestFileCalls(t *testing.T) {
+	t.Parallel()
+
+	file := NewFile(`Shopware.Component.override('sw-foo', { methods: { a() { fn(1, [2, 3], { c: 4 }) } } });
+Component.extend('my-foo', 'sw-bar', {});`)
+
+	calls := file.Calls("Component.override", "Component.extend")
+	require.Len(t, calls, 2)
+
+	assert.Equal(t, "Shopware.Component.override", calls[0].Callee)
+	assert.Len(t, calls[0].Args, 2)
+	name, ok := file.StringArg(calls[0], 0)
+	assert.True(t, ok)
+	assert.Equal(t, "sw-foo", name)
+
+	base, ok := file.StringArg(calls[1], 1)
+	assert.True(t, ok)
+	assert.Equal(t, "sw-bar", base)
+
+	inner := file.Calls("fn")
+	require.Len(t, inner, 1)
+	assert.Equal(t, "[2, 3]", file.Text(inner[0].Args[1][0], inner[0].Args[1][1]))
+}
+
+func TestFileResult(t *testing.T) {
+	t.Parallel()
+
+	file := NewFile(`a.b(c); d.e(f);`)
+	file.Replace(0, 3, "x")
+	file.Replace(1, 2, "overlapping")
+	file.Replace(7, 13, "y")
+
+	assert.Equal(t, `x(c); y;`, file.Result())
+}
+
+func TestRegistryCollect(t *testing.T) {
+	t.Parallel()
+
+	registry := NewRegistry()
+	registry.Collect(NewFile(`Shopware.Component.register('sw-a', () => import('./a'));
+Component.extend('sw-b', 'sw-a', {});
+Mixin.register('my-mixin', {});
+Shopware.Service().register('myService', () => {});
+Application.addServiceProvider('otherService', () => {});`))
+
+	assert.Equal(t, map[string]bool{"sw-a": true, "sw-b": true}, registry.Components)
+	assert.Equal(t, map[string]bool{"my-mixin": true}, registry.Mixins)
+	assert.Equal(t, map[string]bool{"myService": true, "otherService": true}, registry.Services)
+}
@@ -0,0 +1,35 @@
+package jslinter
+
+import (
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+// RunFixerOnString parses the content as JavaScript with the registries and
+// returns it after applying the fixer.
+func RunFixerOnString(fixer JSFixer, content string, core *Registry) (string, error) {
+	file, err := Parse("test.js", content)
+	if err != nil {
+		return "", err
+	}
+
+	file.Core = core
+
+	if err := fixer.Fix(file); err != nil {
+		return "", err
+	}
+
+	return file.Result(), nil
+}
+
+// RunCheckerOnString parses the content as JavaScript with the registries
+// and returns the results of the fixer.
+func RunCheckerOnString(fixer JSFixer, content string, core *Registry) ([]validation.CheckResult, error) {
+	file, err := Parse("test.js", content)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Core = core
+
+	return fixer.Check(file), nil
+}
@@ -0,0 +1,257 @@
+package jslinter
+
+import (
+	"strings"
+)
+
+type TokenKind int
+
+const (
+	TokenIdentifier TokenKind = iota
+	TokenPunctuator
+	TokenString
+	TokenTemplate
+	TokenNumber
+	TokenRegex
+)
+
+// Token is a JavaScript token. Comments and whitespace are skipped, their
+// offsets are still covered by Start and End of the surrounding tokens.
+type Token struct {
+	Kind TokenKind
+	// Value is the source of the token, for strings without the quotes
+	Value string
+	Start int
+	End   int
+	Line  int
+}
+
+var punctuators = []string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+// keywordsBeforeRegex are the keywords after which a slash starts a regular
+// expression instead of a division.
+var keywordsBeforeRegex = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true, "in": true, "instanceof": true,
+	"new": true, "delete": true, "void": true, "throw": true, "yield": true, "await": true, "of": true,
+}
+
+// Tokenize splits JavaScript or TypeScript source into tokens. It is
+// forgiving, unterminated strings or comments end at the end of the source.
+func Tokenize(src string) []Token {
+	l := &lexer{src: src, line: 1}
+	l.run()
+
+	return l.tokens
+}
+
+type lexer struct {
+	src    string
+	pos    int
+	line   int
+	tokens []Token
+}
+
+func (l *lexer) emit(kind TokenKind, start int, value string, line int) {
+	l.tokens = append(l.tokens, Token{Kind: kind, Value: value, Start: start, End: l.pos, Line: line})
+}
+
+func (l *lexer) advance(n int) {
+	end := min(l.pos+n, len(l.src))
+	l.line += strings.Count(l.src[l.pos:end], "\n")
+	l.pos = end
+}
+
+func (l *lexer) regexAllowed() bool {
+	if len(l.tokens) == 0 {
+		return true
+	}
+
+	last := l.tokens[len(l.tokens)-1]
+
+	switch last.Kind {
+	case TokenPunctuator:
+		return last.Value != ")" && last.Value != "]" && last.Value != "}"
+	case TokenIdentifier:
+		return keywordsBeforeRegex[last.Value]
+	default:
+		return false
+	}
+}
+
+func (l *lexer) run() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		start, line := l.pos, l.line
+
+		switch {
+		case c == '\n' || c == ' ' || c == '\t' || c == '\r':
+			l.advance(1)
+		case strings.HasPrefix(l.src[l.pos:], "//"):
+			end := strings.IndexByte(l.src[l.pos:], '\n')
+			if end < 0 {
+				end = len(l.src) - l.pos
+			}
+
+			l.advance(end)
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				l.advance(len(l.src) - l.pos)
+			} else {
+				l.advance(end + 4)
+			}
+		case c == '\'' || c == '"':
+			l.advance(l.stringLength(l.pos))
+			value := l.src[start+1 : l.pos]
+			if l.pos-start >= 2 && l.src[l.pos-1] == c {
+				value = l.src[start+1 : l.pos-1]
+			}
+
+			l.emit(TokenString, start, value, line)
+		case c == '`':
+			l.advance(l.templateLength(l.pos))
+			l.emit(TokenTemplate, start, l.src[start:l.pos], line)
+		case isIdentStart(c):
+			end := l.pos
+			for end < len(l.src) && isIdentPart(l.src[end]) {
+				end++
+			}
+
+			l.advance(end - l.pos)
+			l.emit(TokenIdentifier, start, l.src[start:l.pos], line)
+		case isDigit(c) || (c == '.' && l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1])):
+			end := l.pos + 1
+			for end < len(l.src) && (isIdentPart(l.src[end]) || l.src[end] == '.') {
+				end++
+			}
+
+			l.advance(end - l.pos)
+			l.emit(TokenNumber, start, l.src[start:l.pos], line)
+		case c == '/' && l.regexAllowed():
+			l.advance(l.regexLength(l.pos))
+			l.emit(TokenRegex, start, l.src[start:l.pos], line)
+		default:
+			n := 1
+			for _, p := range punctuators {
+				if strings.HasPrefix(l.src[l.pos:], p) {
+					n = len(p)
+					break
+				}
+			}
+
+			// a?.5 is a conditional, not optional chaining
+			if n == 2 && l.src[l.pos:l.pos+2] == "?." && l.pos+2 < len(l.src) && isDigit(l.src[l.pos+2]) {
+				n = 1
+			}
+
+			l.advance(n)
+			l.emit(TokenPunctuator, start, l.src[start:l.pos], line)
+		}
+	}
+}
+
+// stringLength returns the length of the quoted string starting at pos.
+func (l *lexer) stringLength(pos int) int {
+	quote := l.src[pos]
+
+	for i := pos + 1; i < len(l.src); i++ {
+		switch l.src[i] {
+		case '\\':
+			i++
+		case quote, '\n':
+			return i - pos + 1
+		}
+	}
+
+	return len(l.src) - pos
+}
+
+// templateLength returns the length of the template literal starting at
+// pos, including nested expressions and templates.
+func (l *lexer) templateLength(pos int) int {
+	for i := pos + 1; i < len(l.src); i++ {
+		switch l.src[i] {
+		case '\\':
+			i++
+		case '`':
+			return i - pos + 1
+		case '$':
+			if i+1 < len(l.src) && l.src[i+1] == '{' {
+				i = l.expressionEnd(i+2) - 1
+			}
+		}
+	}
+
+	return len(l.src) - pos
+}
+
+// expressionEnd returns the offset after the brace closing the template
+// expression starting at pos.
+func (l *lexer) expressionEnd(pos int) int {
+	depth := 1
+
+	for i := pos; i < len(l.src); i++ {
+		switch l.src[i] {
+		case '\'', '"':
+			i += l.stringLength(i) - 1
+		case '`':
+			i += l.templateLength(i) - 1
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return len(l.src)
+}
+
+// regexLength returns the length of the regular expression literal starting
+// at pos, including its flags.
+func (l *lexer) regexLength(pos int) int {
+	inClass := false
+
+	for i := pos + 1; i < len(l.src); i++ {
+		switch l.src[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '\n':
+			return i - pos
+		case '/':
+			if inClass {
+				continue
+			}
+
+			i++
+			for i < len(l.src) && isIdentPart(l.src[i]) {
+				i++
+			}
+
+			return i - pos
+		}
+	}
+
+	return len(l.src) - pos
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,48 @@
+package jslinter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tokenValues(tokens []Token) []string {
+	values := make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		values = append(values, tok.Value)
+	}
+
+	return values
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		source string
+		values []string
+	}{
+		{"member call", `this.$set(a, 'b', 1)`, []string{"this", ".", "$set", "(", "a", ",", "b", ",", "1", ")"}},
+		{"comments", "a // b\n/* c */ d", []string{"a", "d"}},
+		{"template with nested expression", "x = `a ${ { b: `c` }.b } d`;", []string{"x", "=", "`a ${ { b: `c` }.b } d`", ";"}},
+		{"regex after punctuator", `x = /a\/[/]b/g.test(y)`, []string{"x", "=", `/a\/[/]b/g`, ".", "test", "(", "y", ")"}},
+		{"division", `a / b / c`, []string{"a", "/", "b", "/", "c"}},
+		{"optional chaining and arrow", `a?.b ?? (() => 1)`, []string{"a", "?.", "b", "??", "(", "(", ")", "=>", "1", ")"}},
+		{"escaped quotes", `'it\'s'`, []string{`it\'s`}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.values, tokenValues(Tokenize(tc.source)))
+		})
+	}
+}
+
+func TestTokenizeLines(t *testing.T) {
+	t.Parallel()
+
+	tokens := Tokenize("a\n/* x\n y */\n`b\nc`\nd")
+	assert.Equal(t, []int{1, 4, 6}, []int{tokens[0].Line, tokens[1].Line, tokens[2].Line})
+}
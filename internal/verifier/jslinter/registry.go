@@ -0,0 +1,64 @@
+package jslinter
+
+// Registry lists the names of components, mixins and services registered in
+// the administration.
+type Registry struct {
+	Components map[string]bool `json:"components"`
+	Mixins     map[string]bool `json:"mixins"`
+	Services   map[string]bool `json:"services"`
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		Components: map[string]bool{},
+		Mixins:     map[string]bool{},
+		Services:   map[string]bool{},
+	}
+}
+
+// Collect adds the registrations of the file, like
+// Component.register('sw-foo', ...) or Application.addServiceProvider('fooService', ...).
+func (r *Registry) Collect(f *File) {
+	for _, call := range f.Calls("Component.register", "Component.extend") {
+		if name, ok := f.StringArg(call, 0); ok {
+			r.Components[name] = true
+		}
+	}
+
+	for _, call := range f.Calls("Mixin.register") {
+		if name, ok := f.StringArg(call, 0); ok {
+			r.Mixins[name] = true
+		}
+	}
+
+	for _, call := range f.Calls("addServiceProvider", "Service.register") {
+		if name, ok := f.StringArg(call, 0); ok {
+			r.Services[name] = true
+		}
+	}
+
+	// Shopware.Service().register('name', ...)
+	for i := range f.Tokens {
+		if f.IsIdentifier(i, "Service") && f.IsPunctuator(i+1, "(") && f.IsPunctuator(i+2, ")") && f.IsPunctuator(i+3, ".") && f.IsIdentifier(i+4, "register") && f.IsPunctuator(i+5, "(") {
+			if i+6 < len(f.Tokens) && f.Tokens[i+6].Kind == TokenString {
+				r.Services[f.Tokens[i+6].Value] = true
+			}
+		}
+	}
+}
+
+// HasComponent reports whether the component is registered by Shopware or
+// the extension. Without a known Shopware registry every component exists.
+func (f *File) HasComponent(name string) bool {
+	return f.Core == nil || len(f.Core.Components) == 0 || f.Core.Components[name] || (f.Own != nil && f.Own.Components[name])
+}
+
+// HasMixin is HasComponent for mixins.
+func (f *File) HasMixin(name string) bool {
+	return f.Core == nil || len(f.Core.Mixins) == 0 || f.Core.Mixins[name] || (f.Own != nil && f.Own.Mixins[name])
+}
+
+// HasService is HasComponent for services.
+func (f *File) HasService(name string) bool {
+	return f.Core == nil || len(f.Core.Services) == 0 || f.Core.Services[name] || (f.Own != nil && f.Own.Services[name])
+}
@@ -0,0 +1,33 @@
+package jslinter
+
+import (
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+var availableAdministrationFixers = []JSFixer{}
+
+func AddAdministrationFixer(fixer JSFixer) {
+	availableAdministrationFixers = append(availableAdministrationFixers, fixer)
+}
+
+func GetAdministrationFixers(version *version.Version) []JSFixer {
+	fixers := []JSFixer{}
+	for _, fixer := range availableAdministrationFixers {
+		if fixer.Supports(version) {
+			fixers = append(fixers, fixer)
+		}
+	}
+
+	return fixers
+}
+
+// JSFixer is the JavaScript counterpart of twiglinter.TwigFixer. Fix records
+// its rewrites with File.Replace, rules which cannot be fixed safely leave
+// the file untouched.
+type JSFixer interface {
+	Check(file *File) []validation.CheckResult
+	Supports(version *version.Version) bool
+	Fix(file *File) error
+}
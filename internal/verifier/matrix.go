@@ -0,0 +1,327 @@
+package verifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	cp "github.com/otiai10/copy"
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// MatrixComposerTool is the column of the dependency installation.
+const MatrixComposerTool = "composer"
+
+// matrixHashFile stores the hash of the composer.json the vendor of a
+// matrix directory was installed for.
+const matrixHashFile = ".shopware-cli-matrix-hash"
+
+// matrixPinnedPackages are set to the tested version in the composer.json.
+var matrixPinnedPackages = []string{"shopware/core", "shopware/administration", "shopware/storefront", "shopware/elasticsearch"}
+
+// MatrixCell is the outcome of a tool on a Shopware version.
+type MatrixCell struct {
+	Version  string `json:"version"`
+	Tool     string `json:"tool"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+	// Failure is set when the tool could not run
+	Failure string `json:"failure,omitempty"`
+}
+
+// Passed reports whether the tool ran without errors.
+func (c MatrixCell) Passed() bool {
+	return c.Failure == "" && c.Errors == 0
+}
+
+// Matrix is the result of the tools on every tested Shopware version.
+type Matrix struct {
+	Versions []string     `json:"versions"`
+	Tools    []string     `json:"tools"`
+	Cells    []MatrixCell `json:"cells"`
+}
+
+// Cell returns the outcome of the tool on the version.
+func (m Matrix) Cell(shopwareVersion, tool string) (MatrixCell, bool) {
+	for _, cell := range m.Cells {
+		if cell.Version == shopwareVersion && cell.Tool == tool {
+			return cell, true
+		}
+	}
+
+	return MatrixCell{}, false
+}
+
+// Passed reports whether every tool passed on the version.
+func (m Matrix) Passed(shopwareVersion string) bool {
+	for _, cell := range m.Cells {
+		if cell.Version == shopwareVersion && !cell.Passed() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PassingConstraint returns the narrowest constraint containing only the
+// passing versions. Every range of passing versions starts at its first
+// version and ends before the next failing one. Empty when no version passed.
+func (m Matrix) PassingConstraint() string {
+	var ranges []string
+
+	for i := 0; i < len(m.Versions); i++ {
+		if !m.Passed(m.Versions[i]) {
+			continue
+		}
+
+		start := i
+		for i+1 < len(m.Versions) && m.Passed(m.Versions[i+1]) {
+			i++
+		}
+
+		constraint := ">=" + matrixMinorStart(m.Versions[start])
+		if i+1 < len(m.Versions) {
+			constraint += " <" + matrixMinorStart(m.Versions[i+1])
+		}
+
+		ranges = append(ranges, constraint)
+	}
+
+	return strings.Join(ranges, " || ")
+}
+
+// matrixMinorStart returns the first patch of the minor, 6.6.10.4 becomes 6.6.10.0.
+func matrixMinorStart(shopwareVersion string) string {
+	parts := strings.Split(shopwareVersion, ".")
+	if len(parts) < 4 {
+		return shopwareVersion
+	}
+
+	return strings.Join(parts[:3], ".") + ".0"
+}
+
+// MatrixVersions returns the latest release of every minor in the versions,
+// sorted ascending. Pre-releases are skipped.
+func MatrixVersions(versions []string) []string {
+	latest := map[string]*version.Version{}
+
+	for _, v := range versions {
+		parsed, err := version.NewVersion(v)
+		if err != nil || parsed.IsPrerelease() {
+			continue
+		}
+
+		minor := matrixMinorStart(parsed.String())
+		if current, ok := latest[minor]; !ok || parsed.GreaterThan(current) {
+			latest[minor] = parsed
+		}
+	}
+
+	collection := make(version.Collection, 0, len(latest))
+	for _, v := range latest {
+		collection = append(collection, v)
+	}
+
+	sort.Sort(collection)
+
+	result := make([]string, 0, len(collection))
+	for _, v := range collection {
+		result = append(result, v.String())
+	}
+
+	return result
+}
+
+// RunMatrix runs the tools against every Shopware version. Each version gets
+// a working copy in the cache, its vendor is kept until the composer.json
+// changes.
+func RunMatrix(ctx context.Context, ext extension.Extension, versions []string, tools ToolList) (*Matrix, error) {
+	name, err := ext.GetName()
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &Matrix{Versions: versions, Tools: []string{MatrixComposerTool}}
+	for _, tool := range tools {
+		matrix.Tools = append(matrix.Tools, tool.Name())
+	}
+
+	for _, shopwareVersion := range versions {
+		logging.FromContext(ctx).Infof("Checking Shopware %s", shopwareVersion)
+
+		dir := filepath.Join(system.GetShopwareCliCacheDir(), "extension-matrix", name, shopwareVersion)
+
+		if err := prepareMatrixDirectory(ext.GetPath(), dir, shopwareVersion); err != nil {
+			return nil, fmt.Errorf("prepare Shopware %s: %w", shopwareVersion, err)
+		}
+
+		if err := installComposerDeps(ctx, dir, "highest"); err != nil {
+			// a partial vendor must not be reused by the next run
+			_ = os.RemoveAll(filepath.Join(dir, "vendor"))
+
+			matrix.Cells = append(matrix.Cells, MatrixCell{Version: shopwareVersion, Tool: MatrixComposerTool, Failure: err.Error()})
+
+			for _, tool := range tools {
+				matrix.Cells = append(matrix.Cells, MatrixCell{Version: shopwareVersion, Tool: tool.Name(), Failure: "dependencies could not be installed"})
+			}
+
+			continue
+		}
+
+		matrix.Cells = append(matrix.Cells, MatrixCell{Version: shopwareVersion, Tool: MatrixComposerTool})
+
+		cells, err := runMatrixTools(ctx, dir, shopwareVersion, tools)
+		if err != nil {
+			return nil, err
+		}
+
+		matrix.Cells = append(matrix.Cells, cells...)
+	}
+
+	return matrix, nil
+}
+
+func runMatrixTools(ctx context.Context, dir, shopwareVersion string, tools ToolList) ([]MatrixCell, error) {
+	ext, err := extension.GetExtensionByFolder(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	toolCfg, err := ConvertExtensionToToolConfig(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	toolCfg.InputWasDirectory = true
+	toolCfg.CheckAgainst = "highest"
+	toolCfg.MinShopwareVersion = shopwareVersion
+	toolCfg.MaxShopwareVersion = shopwareVersion
+
+	cells := make([]MatrixCell, 0, len(tools))
+
+	for _, tool := range tools {
+		cell := MatrixCell{Version: shopwareVersion, Tool: tool.Name()}
+		check := NewCheck()
+
+		if err := tool.Check(ctx, check, *toolCfg); err != nil {
+			cell.Failure = err.Error()
+		}
+
+		for _, result := range check.ApplySeverityOverrides(toolCfg.SeverityOverrides).RemoveByIdentifier(toolCfg.ValidationIgnores).GetResults() {
+			switch result.Severity {
+			case validation.SeverityError:
+				cell.Errors++
+			case validation.SeverityWarning:
+				cell.Warnings++
+			}
+		}
+
+		cells = append(cells, cell)
+	}
+
+	return cells, nil
+}
+
+// prepareMatrixDirectory syncs the extension into dir and pins the Shopware
+// packages to the version. The vendor is removed when the pinned composer.json
+// differs from the one it was installed for.
+func prepareMatrixDirectory(extensionDir, dir, shopwareVersion string) error {
+	keep := []string{"vendor", "composer.lock", matrixHashFile}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !slices.Contains(keep, entry.Name()) {
+			if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	err = cp.Copy(extensionDir, dir, cp.Options{
+		Skip: func(_ os.FileInfo, src, _ string) (bool, error) {
+			rel, err := filepath.Rel(extensionDir, src)
+			if err != nil {
+				return false, err
+			}
+
+			return rel == ".git" || rel == "vendor" || rel == "composer.lock", nil
+		},
+		OnSymlink: func(string) cp.SymlinkAction {
+			return cp.Skip
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	composerJsonPath := filepath.Join(dir, "composer.json")
+
+	content, err := os.ReadFile(composerJsonPath)
+	if os.IsNotExist(err) {
+		// apps have no dependencies
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var composerJson map[string]any
+	if err := json.Unmarshal(content, &composerJson); err != nil {
+		return fmt.Errorf("cannot read composer.json: %w", err)
+	}
+
+	require, _ := composerJson["require"].(map[string]any)
+	if require == nil {
+		require = map[string]any{}
+	}
+
+	for _, pkg := range matrixPinnedPackages {
+		if _, ok := require[pkg]; ok || pkg == "shopware/core" {
+			require[pkg] = shopwareVersion
+		}
+	}
+
+	composerJson["require"] = require
+
+	pinned, err := json.MarshalIndent(composerJson, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(composerJsonPath, pinned, 0o644); err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(pinned)
+	encodedHash := hex.EncodeToString(hash[:])
+
+	installedHash, _ := os.ReadFile(filepath.Join(dir, matrixHashFile))
+	if string(installedHash) != encodedHash {
+		if err := os.RemoveAll(filepath.Join(dir, "vendor")); err != nil {
+			return err
+		}
+
+		if err := os.RemoveAll(filepath.Join(dir, "composer.lock")); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(filepath.Join(dir, matrixHashFile), []byte(encodedHash), 0o644)
+}
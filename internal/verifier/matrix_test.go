@@ -0,0 +1,73 @@
+package verifier
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatrixVersions(t *testing.T) {
+	versions := MatrixVersions([]string{"6.6.10.4", "6.5.8.0", "6.6.10.2", "6.6.9.0", "6.7.0.0-rc1", "6.5.8.12"})
+
+	assert.Equal(t, []string{"6.5.8.12", "6.6.9.0", "6.6.10.4"}, versions)
+}
+
+func TestMatrixPassingConstraint(t *testing.T) {
+	matrix := Matrix{
+		Versions: []string{"6.6.8.2", "6.6.9.0", "6.6.10.4", "6.7.0.0"},
+		Cells: []MatrixCell{
+			{Version: "6.6.8.2", Tool: "phpstan", Errors: 2},
+			{Version: "6.6.9.0", Tool: "phpstan"},
+			{Version: "6.6.10.4", Tool: "phpstan", Failure: "crashed"},
+			{Version: "6.7.0.0", Tool: "phpstan", Warnings: 3},
+		},
+	}
+
+	assert.False(t, matrix.Passed("6.6.8.2"))
+	assert.True(t, matrix.Passed("6.7.0.0"))
+	assert.Equal(t, ">=6.6.9.0 <6.6.10.0 || >=6.7.0.0", matrix.PassingConstraint())
+
+	assert.Equal(t, "", Matrix{Versions: []string{"6.6.8.2"}, Cells: []MatrixCell{{Version: "6.6.8.2", Errors: 1}}}.PassingConstraint())
+}
+
+func TestPrepareMatrixDirectory(t *testing.T) {
+	extensionDir := t.TempDir()
+	dir := filepath.Join(t.TempDir(), "6.6.10.4")
+
+	require.NoError(t, os.WriteFile(filepath.Join(extensionDir, "composer.json"), []byte(`{"name": "frosh/test", "require": {"shopware/core": "~6.6.0", "shopware/storefront": "*"}}`), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(extensionDir, "vendor", "foo"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(extensionDir, "src"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(extensionDir, "src", "Plugin.php"), []byte("<?php"), 0o644))
+
+	require.NoError(t, prepareMatrixDirectory(extensionDir, dir, "6.6.10.4"))
+
+	assert.FileExists(t, filepath.Join(dir, "src", "Plugin.php"))
+	assert.NoDirExists(t, filepath.Join(dir, "vendor", "foo"))
+
+	content, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+	require.NoError(t, err)
+
+	var composerJson struct {
+		Require map[string]string `json:"require"`
+	}
+
+	require.NoError(t, json.Unmarshal(content, &composerJson))
+	assert.Equal(t, map[string]string{"shopware/core": "6.6.10.4", "shopware/storefront": "6.6.10.4"}, composerJson.Require)
+
+	// an installed vendor is kept while the composer.json does not change
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "vendor", "installed"), 0o755))
+	require.NoError(t, os.Remove(filepath.Join(extensionDir, "src", "Plugin.php")))
+	require.NoError(t, prepareMatrixDirectory(extensionDir, dir, "6.6.10.4"))
+
+	assert.DirExists(t, filepath.Join(dir, "vendor", "installed"))
+	assert.NoFileExists(t, filepath.Join(dir, "src", "Plugin.php"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(extensionDir, "composer.json"), []byte(`{"name": "frosh/test", "require": {"shopware/core": "~6.6.0", "symfony/yaml": "*"}}`), 0o644))
+	require.NoError(t, prepareMatrixDirectory(extensionDir, dir, "6.6.10.4"))
+
+	assert.NoDirExists(t, filepath.Join(dir, "vendor"))
+}
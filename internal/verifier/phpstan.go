@@ -68,7 +68,18 @@ func (p PhpStan) Check(ctx context.Context, check *Check, config ToolConfig) err
 	}
 
 	for _, sourceDirectory := range config.SourceDirectories {
-		phpstanArguments := []string{"-dmemory_limit=2G", path.Join(config.ToolDirectory, "php", "vendor", "bin", "phpstan"), "analyse", "--no-progress", "--no-interaction", "--error-format=json", sourceDirectory}
+		paths := []string{sourceDirectory}
+
+		if config.IsIncremental() {
+			paths = config.ChangedFilesIn(sourceDirectory, ".php")
+
+			if len(paths) == 0 {
+				continue
+			}
+		}
+
+		phpstanArguments := []string{"-dmemory_limit=2G", path.Join(config.ToolDirectory, "php", "vendor", "bin", "phpstan"), "analyse", "--no-progress", "--no-interaction", "--error-format=json"}
+		phpstanArguments = append(phpstanArguments, paths...)
 
 		if !p.configExists(config.RootDir) {
 			phpstanArguments = append(phpstanArguments, "--configuration", path.Join(config.ToolDirectory, "php", "configs", "phpstan.neon"))
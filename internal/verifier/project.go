@@ -191,8 +191,11 @@ func GetConfigFromProject(root string, onlyLocal bool) (*ToolConfig, error) {
 	}
 
 	var validationIgnores []validation.ToolConfigIgnore
+	var severityOverrides map[string]string
 
 	if shopCfg.Validation != nil {
+		severityOverrides = shopCfg.Validation.Severity
+
 		for _, ignore := range shopCfg.Validation.Ignore {
 			validationIgnores = append(validationIgnores, validation.ToolConfigIgnore{
 				Identifier: ignore.Identifier,
@@ -209,6 +212,11 @@ func GetConfigFromProject(root string, onlyLocal bool) (*ToolConfig, error) {
 		AdminDirectories:      adminDirectories,
 		StorefrontDirectories: storefrontDirectories,
 		ValidationIgnores:     validationIgnores,
+		SeverityOverrides:     severityOverrides,
+	}
+
+	if err := ValidateSeverityOverrides(severityOverrides); err != nil {
+		return nil, err
 	}
 
 	if err := determineVersionRange(toolCfg, constraint); err != nil {
@@ -1,6 +1,7 @@
 package verifier
 
 import (
+	"fmt"
 	"strings"
 	"sync"
 
@@ -78,3 +79,64 @@ func (c *Check) RemoveByIdentifier(ignores []validation.ToolConfigIgnore) valida
 
 	return c
 }
+
+// SeverityOff drops results in the severity overrides.
+const SeverityOff = "off"
+
+// ValidateSeverityOverrides checks that all overrides use a known severity.
+func ValidateSeverityOverrides(overrides map[string]string) error {
+	for identifier, severity := range overrides {
+		switch severity {
+		case validation.SeverityError, validation.SeverityWarning, SeverityOff:
+		default:
+			return fmt.Errorf("validation.severity: %s has the unknown severity %q, use error, warning or off", identifier, severity)
+		}
+	}
+
+	return nil
+}
+
+// ApplySeverityOverrides changes the severity of the results by identifier.
+// An exact identifier wins over a prefix ending with *, longer prefixes win
+// over shorter ones.
+func (c *Check) ApplySeverityOverrides(overrides map[string]string) *Check {
+	if len(overrides) == 0 {
+		return c
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	filtered := make([]validation.CheckResult, 0, len(c.Results))
+
+	for _, r := range c.Results {
+		severity, ok := overrides[r.Identifier]
+
+		if !ok {
+			longest := -1
+
+			for pattern, patternSeverity := range overrides {
+				prefix, isPrefix := strings.CutSuffix(pattern, "*")
+				if isPrefix && strings.HasPrefix(r.Identifier, prefix) && len(prefix) > longest {
+					severity = patternSeverity
+					longest = len(prefix)
+					ok = true
+				}
+			}
+		}
+
+		if ok {
+			if severity == SeverityOff {
+				continue
+			}
+
+			r.Severity = severity
+		}
+
+		filtered = append(filtered, r)
+	}
+
+	c.Results = filtered
+
+	return c
+}
@@ -266,3 +266,30 @@ func TestRemoveByMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestApplySeverityOverrides(t *testing.T) {
+	t.Parallel()
+	check := NewCheck()
+	check.AddResult(validation.CheckResult{Identifier: "twig-linter/a11y-positive-tabindex", Severity: validation.SeverityWarning})
+	check.AddResult(validation.CheckResult{Identifier: "twig-linter/a11y-media-autoplay", Severity: validation.SeverityWarning})
+	check.AddResult(validation.CheckResult{Identifier: "twig-linter/inline-style-tag", Severity: validation.SeverityWarning})
+	check.AddResult(validation.CheckResult{Identifier: "phpstan", Severity: validation.SeverityError})
+
+	check.ApplySeverityOverrides(map[string]string{
+		"twig-linter/*":                      SeverityOff,
+		"twig-linter/a11y-*":                 validation.SeverityError,
+		"twig-linter/a11y-positive-tabindex": validation.SeverityWarning,
+	})
+
+	assert.Equal(t, []validation.CheckResult{
+		{Identifier: "twig-linter/a11y-positive-tabindex", Severity: validation.SeverityWarning},
+		{Identifier: "twig-linter/a11y-media-autoplay", Severity: validation.SeverityError},
+		{Identifier: "phpstan", Severity: validation.SeverityError},
+	}, check.GetResults())
+}
+
+func TestValidateSeverityOverrides(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, ValidateSeverityOverrides(map[string]string{"a": "error", "b": "warning", "c": "off"}))
+	assert.EqualError(t, ValidateSeverityOverrides(map[string]string{"a": "fatal"}), `validation.severity: a has the unknown severity "fatal", use error, warning or off`)
+}
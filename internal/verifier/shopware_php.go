@@ -0,0 +1,484 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/php"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const (
+	entityRepositoryClass       = `Shopware\Core\Framework\DataAbstractionLayer\EntityRepository`
+	migrationStepClass          = `Shopware\Core\Framework\Migration\MigrationStep`
+	eventSubscriberInterface    = `Symfony\Component\EventDispatcher\EventSubscriberInterface`
+	autowireAttribute           = `Symfony\Component\DependencyInjection\Attribute\Autowire`
+	routeScopeMissingIdentifier = "php.route_scope_missing"
+	repositoryByClassIdentifier = "php.repository_injected_by_class"
+	connectionQueryIdentifier   = "php.connection_entity_table"
+	internalUsageIdentifier     = "php.internal_usage"
+	removedEventIdentifier      = "php.removed_event"
+	routeScopeAttributeArgument = "_routeScope"
+	routeScopeConstant          = "ATTRIBUTE_ROUTE_SCOPE"
+)
+
+var (
+	routeAttributes = map[string]bool{
+		`Symfony\Component\Routing\Attribute\Route`:  true,
+		`Symfony\Component\Routing\Annotation\Route`: true,
+	}
+
+	// sqlStatement detects strings which are SQL queries
+	sqlStatement = regexp.MustCompile(`(?is)^\s*\(?\s*(SELECT|INSERT|UPDATE|DELETE|REPLACE|WITH)\b`)
+	sqlTable     = regexp.MustCompile("(?i)\\b(KEY\\s+UPDATE|FROM|JOIN|INTO|UPDATE)\\s+`?([a-z_][a-z0-9_]*)`?")
+
+	// connectionTableArguments are the methods of the DBAL connection and
+	// query builder taking a table name, mapped to the index of the argument
+	connectionTableArguments = map[string]int{
+		"insert":    0,
+		"update":    0,
+		"delete":    0,
+		"from":      0,
+		"join":      1,
+		"innerJoin": 1,
+		"leftJoin":  1,
+		"rightJoin": 1,
+	}
+
+	autowiredClass        = regexp.MustCompile(`service:\s*(\\?[\w\\]+)::class`)
+	repositoryServiceXML  = regexp.MustCompile(`id="\\?Shopware\\Core\\Framework\\DataAbstractionLayer\\EntityRepository"`)
+	repositoryServiceYAML = regexp.MustCompile(`@\\{0,2}Shopware\\{1,2}Core\\{1,2}Framework\\{1,2}DataAbstractionLayer\\{1,2}EntityRepository\b`)
+)
+
+// checkShopwarePHP runs the Shopware specific PHP rules on the source
+// directories. Rules which need to know the Shopware classes are skipped
+// when the class index cannot be loaded.
+func checkShopwarePHP(ctx context.Context, check *Check, config ToolConfig) error {
+	phpFiles := map[string]string{}
+	configFiles := map[string]string{}
+
+	for _, sourceDirectory := range config.SourceDirectories {
+		err := walkShopwarePHPSources(sourceDirectory, func(path, content string) {
+			if filepath.Ext(path) == ".php" {
+				phpFiles[path] = content
+			} else {
+				configFiles[path] = content
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(phpFiles) == 0 {
+		return nil
+	}
+
+	index, err := loadCoreIndex(ctx, config)
+	if err != nil {
+		logging.FromContext(ctx).Warnf("Skipping the PHP checks which need the Shopware classes, the class index could not be loaded: %v", err)
+		index = nil
+	}
+
+	routeScopeConfigured := false
+
+	for _, path := range slices.Sorted(maps.Keys(configFiles)) {
+		content := configFiles[path]
+		name := filepath.Base(path)
+
+		if strings.HasPrefix(name, "routes") && strings.Contains(content, routeScopeAttributeArgument) {
+			routeScopeConfigured = true
+		}
+
+		if strings.HasPrefix(name, "services") {
+			for _, result := range checkServiceConfiguration(filepath.Ext(path), content) {
+				result.Path = relativeResultPath(path, config.RootDir)
+				check.AddResult(result)
+			}
+		}
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(phpFiles)) {
+		for _, result := range checkShopwarePHPFile(php.Parse(phpFiles[path]), index, routeScopeConfigured) {
+			result.Path = relativeResultPath(path, config.RootDir)
+			check.AddResult(result)
+		}
+	}
+
+	return nil
+}
+
+// walkShopwarePHPSources calls f with the PHP files and the Symfony
+// configuration files of the directory.
+func walkShopwarePHPSources(dir string, f func(path, content string)) error {
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == "node_modules" || d.Name() == "vendor" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		ext := filepath.Ext(path)
+		isConfig := (ext == ".xml" || ext == ".yaml" || ext == ".yml") && filepath.Base(filepath.Dir(path)) == "config"
+
+		if ext != ".php" && !isConfig {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		f(path, string(content))
+
+		return nil
+	})
+}
+
+// checkShopwarePHPFile runs the rules on one parsed file. The index is nil
+// when the Shopware classes are unknown.
+func checkShopwarePHPFile(file *php.File, index *coreIndex, routeScopeConfigured bool) []validation.CheckResult {
+	var results []validation.CheckResult
+
+	if !routeScopeConfigured {
+		results = append(results, checkRouteScopes(file)...)
+	}
+
+	results = append(results, checkAutowiredRepositories(file)...)
+
+	if index == nil {
+		return results
+	}
+
+	results = append(results, checkInternalUsage(file, index)...)
+	results = append(results, checkConnectionQueries(file, index)...)
+	results = append(results, checkRemovedEvents(file, index)...)
+
+	return results
+}
+
+// checkRouteScopes reports routes without _routeScope. The scope can be set
+// on the route of the method or of the class.
+func checkRouteScopes(file *php.File) []validation.CheckResult {
+	var results []validation.CheckResult
+
+	for _, class := range file.Classes {
+		if hasRouteScope(class.Attributes) {
+			continue
+		}
+
+		for _, method := range class.Methods {
+			for _, attr := range method.Attributes {
+				if !routeAttributes[attr.Name] || hasRouteScope(method.Attributes) {
+					continue
+				}
+
+				results = append(results, validation.CheckResult{
+					Line:       attr.Line,
+					Identifier: routeScopeMissingIdentifier,
+					Message:    fmt.Sprintf("The route of %s::%s has no _routeScope, Shopware rejects requests to it", class.Name, method.Name),
+					Severity:   validation.SeverityError,
+					Tip:        "Add defaults: ['_routeScope' => ['storefront']] to the Route attribute of the method or the class",
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+func hasRouteScope(attributes []php.Attribute) bool {
+	for _, attr := range attributes {
+		if routeAttributes[attr.Name] && (strings.Contains(attr.Args, routeScopeAttributeArgument) || strings.Contains(attr.Args, routeScopeConstant)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAutowiredRepositories reports #[Autowire(service: EntityRepository::class)].
+func checkAutowiredRepositories(file *php.File) []validation.CheckResult {
+	var results []validation.CheckResult
+
+	for _, class := range file.Classes {
+		for _, method := range class.Methods {
+			for _, param := range method.Params {
+				for _, attr := range param.Attributes {
+					if attr.Name != autowireAttribute {
+						continue
+					}
+
+					match := autowiredClass.FindStringSubmatch(attr.Args)
+					if match == nil || file.Resolve(match[1]) != entityRepositoryClass {
+						continue
+					}
+
+					results = append(results, repositoryByClassResult(attr.Line))
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// checkServiceConfiguration reports repositories injected by class in a
+// services.xml or services.yaml.
+func checkServiceConfiguration(ext, content string) []validation.CheckResult {
+	pattern := repositoryServiceYAML
+	if ext == ".xml" {
+		pattern = repositoryServiceXML
+	}
+
+	var results []validation.CheckResult
+
+	for i, line := range strings.Split(content, "\n") {
+		if pattern.MatchString(line) {
+			results = append(results, repositoryByClassResult(i+1))
+		}
+	}
+
+	return results
+}
+
+func repositoryByClassResult(line int) validation.CheckResult {
+	return validation.CheckResult{
+		Line:       line,
+		Identifier: repositoryByClassIdentifier,
+		Message:    "EntityRepository is injected by its class, there is no service with this id",
+		Severity:   validation.SeverityError,
+		Tip:        "Inject the repository of the entity by its id, like product.repository",
+	}
+}
+
+// checkInternalUsage reports imports and fully qualified names of Shopware
+// classes marked with @internal.
+func checkInternalUsage(file *php.File, index *coreIndex) []validation.CheckResult {
+	var results []validation.CheckResult
+
+	seen := map[string]bool{}
+	report := func(name string, line int) {
+		key := fmt.Sprintf("%s:%d", name, line)
+		if !index.Internal[name] || seen[key] {
+			return
+		}
+
+		seen[key] = true
+		results = append(results, validation.CheckResult{
+			Line:       line,
+			Identifier: internalUsageIdentifier,
+			Message:    fmt.Sprintf("The class %s is marked as @internal and can change in any Shopware release", name),
+			Severity:   validation.SeverityWarning,
+		})
+	}
+
+	for _, use := range file.Uses {
+		report(use.Name, use.Line)
+	}
+
+	for _, tok := range file.Tokens {
+		if tok.Kind == php.TokenName && strings.HasPrefix(tok.Value, `\Shopware\`) {
+			report(strings.TrimPrefix(tok.Value, `\`), tok.Line)
+		}
+	}
+
+	return results
+}
+
+// checkConnectionQueries reports SQL queries on the tables of Shopware
+// entities. Migrations are skipped, they have to use the connection.
+func checkConnectionQueries(file *php.File, index *coreIndex) []validation.CheckResult {
+	if !usesDBAL(file) {
+		return nil
+	}
+
+	var results []validation.CheckResult
+
+	seen := map[string]bool{}
+	report := func(table string, line int) {
+		key := fmt.Sprintf("%s:%d", table, line)
+		if !index.EntityTables[table] || seen[key] {
+			return
+		}
+
+		seen[key] = true
+		results = append(results, validation.CheckResult{
+			Line:       line,
+			Identifier: connectionQueryIdentifier,
+			Message:    fmt.Sprintf("The query on the entity table %s bypasses the DAL", table),
+			Severity:   validation.SeverityWarning,
+			Tip:        fmt.Sprintf("Queries on entity tables skip entity events, indexers and cache invalidation, use the %s.repository instead", table),
+		})
+	}
+
+	for _, class := range file.Classes {
+		if isMigration(file, class) {
+			continue
+		}
+
+		for i := class.BodyStart; i < class.BodyEnd; i++ {
+			tok := file.Tokens[i]
+
+			if tok.Kind == php.TokenString && sqlStatement.MatchString(tok.Value) {
+				for _, match := range sqlTable.FindAllStringSubmatch(tok.Value, -1) {
+					if !strings.HasPrefix(strings.ToUpper(match[1]), "KEY") {
+						report(strings.ToLower(match[2]), tok.Line)
+					}
+				}
+
+				continue
+			}
+
+			argument, ok := connectionTableArguments[tok.Value]
+			if tok.Kind != php.TokenName || !ok || !file.IsPunctuator(i-1, "->", "?->") || !file.IsPunctuator(i+1, "(") {
+				continue
+			}
+
+			if table, ok := stringArgument(file, i+1, argument); ok {
+				report(table, tok.Line)
+			}
+		}
+	}
+
+	return results
+}
+
+func usesDBAL(file *php.File) bool {
+	for _, use := range file.Uses {
+		if strings.HasPrefix(use.Name, `Doctrine\DBAL\`) {
+			return true
+		}
+	}
+
+	for _, tok := range file.Tokens {
+		if tok.Kind == php.TokenName && strings.HasPrefix(tok.Value, `\Doctrine\DBAL\`) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isMigration(file *php.File, class *php.Class) bool {
+	for _, parent := range class.Extends {
+		if parent == migrationStepClass || strings.HasSuffix(parent, "MigrationStep") {
+			return true
+		}
+	}
+
+	return strings.HasPrefix(class.Name, "Migration")
+}
+
+// stringArgument returns the nth argument of the call with the parenthesis
+// at index open when it is a plain string.
+func stringArgument(file *php.File, open, n int) (string, bool) {
+	closing := file.Matching(open)
+	argument := 0
+	start := open + 1
+
+	for i := open + 1; i <= closing; i++ {
+		if file.IsPunctuator(i, "(", "[", "{") {
+			i = file.Matching(i)
+			continue
+		}
+
+		if i != closing && !file.IsPunctuator(i, ",") {
+			continue
+		}
+
+		if argument == n {
+			if i-start == 1 && file.Tokens[start].Kind == php.TokenString {
+				return file.Tokens[start].Value, true
+			}
+
+			return "", false
+		}
+
+		argument++
+		start = i + 1
+	}
+
+	return "", false
+}
+
+// checkRemovedEvents reports events in getSubscribedEvents, whose class or
+// constant does not exist in the checked Shopware version.
+func checkRemovedEvents(file *php.File, index *coreIndex) []validation.CheckResult {
+	var results []validation.CheckResult
+
+	for _, class := range file.Classes {
+		if !implements(class, eventSubscriberInterface) {
+			continue
+		}
+
+		for _, method := range class.Methods {
+			if method.Name != "getSubscribedEvents" || method.BodyStart < 0 {
+				continue
+			}
+
+			for i := method.BodyStart; i < method.BodyEnd; i++ {
+				if file.Tokens[i].Kind != php.TokenName || !file.IsPunctuator(i+1, "::") || i+2 >= method.BodyEnd || file.Tokens[i+2].Kind != php.TokenName {
+					continue
+				}
+
+				eventClass := file.Resolve(file.Tokens[i].Value)
+				member := file.Tokens[i+2].Value
+
+				if !strings.HasPrefix(eventClass, `Shopware\`) {
+					continue
+				}
+
+				var message string
+
+				switch {
+				case !index.Classes[eventClass]:
+					message = fmt.Sprintf("The event class %s does not exist in Shopware %s", eventClass, index.Version)
+				case !strings.EqualFold(member, "class") && !index.EventConstants[eventClass+"::"+member] && (strings.HasSuffix(eventClass, "Events") || strings.HasSuffix(eventClass, "Event")):
+					message = fmt.Sprintf("The event %s::%s does not exist in Shopware %s", eventClass, member, index.Version)
+				default:
+					continue
+				}
+
+				results = append(results, validation.CheckResult{
+					Line:       file.Tokens[i].Line,
+					Identifier: removedEventIdentifier,
+					Message:    message,
+					Severity:   validation.SeverityError,
+					Tip:        "The subscriber is never called, check the upgrade notes for the replacement of the event",
+				})
+			}
+		}
+	}
+
+	return results
+}
+
+func implements(class *php.Class, name string) bool {
+	for _, implemented := range class.Implements {
+		if implemented == name {
+			return true
+		}
+	}
+
+	return false
+}
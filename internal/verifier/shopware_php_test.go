@@ -0,0 +1,292 @@
+package verifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/php"
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+func testCoreIndex() *coreIndex {
+	index := newCoreIndex("6.7.0.0")
+
+	index.addSource("Content/Product/ProductDefinition.php", `<?php
+namespace Shopware\Core\Content\Product;
+
+class ProductDefinition extends EntityDefinition
+{
+    final public const ENTITY_NAME = 'product';
+}
+`)
+	index.addSource("Content/Product/ProductEvents.php", `<?php
+namespace Shopware\Core\Content\Product;
+
+class ProductEvents
+{
+    final public const PRODUCT_WRITTEN_EVENT = 'product.written';
+}
+`)
+	index.addSource("Checkout/Cart/CartCalculator.php", `<?php
+namespace Shopware\Core\Checkout\Cart;
+
+/**
+ * @internal
+ */
+class CartCalculator {}
+`)
+	index.addSource("Framework/Test/TestDefaults.php", `<?php
+namespace Shopware\Core\Framework\Test;
+
+/** @internal */
+class TestDefaults {}
+`)
+
+	return index
+}
+
+func identifiers(results []validation.CheckResult) []string {
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.Identifier)
+	}
+
+	return ids
+}
+
+func TestCoreIndexAddSource(t *testing.T) {
+	t.Parallel()
+
+	index := testCoreIndex()
+
+	assert.True(t, index.Classes[`Shopware\Core\Content\Product\ProductDefinition`])
+	assert.Equal(t, map[string]bool{"product": true}, index.EntityTables)
+	assert.Equal(t, map[string]bool{`Shopware\Core\Checkout\Cart\CartCalculator`: true}, index.Internal)
+	assert.Equal(t, map[string]bool{`Shopware\Core\Content\Product\ProductEvents::PRODUCT_WRITTEN_EVENT`: true}, index.EventConstants)
+}
+
+func TestCheckRouteScopes(t *testing.T) {
+	t.Parallel()
+
+	results := checkRouteScopes(php.Parse(`<?php
+namespace Acme\Controller;
+
+use Symfony\Component\Routing\Attribute\Route;
+use Shopware\Core\PlatformRequest;
+
+class FooController
+{
+    #[Route(path: '/foo', name: 'frontend.foo')]
+    public function foo() {}
+
+    #[Route(path: '/bar', name: 'frontend.bar', defaults: ['_routeScope' => ['storefront']])]
+    public function bar() {}
+
+    #[Route(path: '/baz', name: 'frontend.baz', defaults: [PlatformRequest::ATTRIBUTE_ROUTE_SCOPE => ['storefront']])]
+    public function baz() {}
+}
+
+#[Route(defaults: ['_routeScope' => ['api']])]
+class ApiController
+{
+    #[Route(path: '/api/foo', name: 'api.foo')]
+    public function foo() {}
+}
+`))
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "php.route_scope_missing", results[0].Identifier)
+	assert.Equal(t, 9, results[0].Line)
+	assert.Contains(t, results[0].Message, "FooController::foo")
+}
+
+func TestCheckAutowiredRepositories(t *testing.T) {
+	t.Parallel()
+
+	results := checkAutowiredRepositories(php.Parse(`<?php
+namespace Acme\Service;
+
+use Shopware\Core\Framework\DataAbstractionLayer\EntityRepository;
+use Symfony\Component\DependencyInjection\Attribute\Autowire;
+
+class Foo
+{
+    public function __construct(
+        #[Autowire(service: EntityRepository::class)]
+        private EntityRepository $productRepository,
+        #[Autowire(service: 'category.repository')]
+        private EntityRepository $categoryRepository,
+    ) {}
+}
+`))
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "php.repository_injected_by_class", results[0].Identifier)
+	assert.Equal(t, 10, results[0].Line)
+}
+
+func TestCheckServiceConfiguration(t *testing.T) {
+	t.Parallel()
+
+	xml := checkServiceConfiguration(".xml", `<service id="Acme\Foo">
+    <argument type="service" id="Shopware\Core\Framework\DataAbstractionLayer\EntityRepository"/>
+    <argument type="service" id="product.repository"/>
+</service>`)
+	require.Len(t, xml, 1)
+	assert.Equal(t, 2, xml[0].Line)
+
+	yaml := checkServiceConfiguration(".yaml", `services:
+  Acme\Foo:
+    arguments:
+      - '@product.repository'
+      - "@Shopware\\Core\\Framework\\DataAbstractionLayer\\EntityRepository"
+`)
+	require.Len(t, yaml, 1)
+	assert.Equal(t, 5, yaml[0].Line)
+}
+
+func TestCheckInternalUsage(t *testing.T) {
+	t.Parallel()
+
+	results := checkInternalUsage(php.Parse(`<?php
+namespace Acme;
+
+use Shopware\Core\Checkout\Cart\CartCalculator;
+use Shopware\Core\Content\Product\ProductDefinition;
+
+class Foo
+{
+    public function foo(): void
+    {
+        new \Shopware\Core\Checkout\Cart\CartCalculator();
+    }
+}
+`), testCoreIndex())
+
+	require.Len(t, results, 2)
+	assert.Equal(t, []string{"php.internal_usage", "php.internal_usage"}, identifiers(results))
+	assert.Equal(t, 4, results[0].Line)
+	assert.Equal(t, 11, results[1].Line)
+}
+
+func TestCheckConnectionQueries(t *testing.T) {
+	t.Parallel()
+
+	index := testCoreIndex()
+
+	results := checkConnectionQueries(php.Parse(`<?php
+namespace Acme;
+
+use Doctrine\DBAL\Connection;
+
+class Foo
+{
+    public function foo(): void
+    {
+        $this->connection->fetchAllAssociative('SELECT id FROM product WHERE active = 1');
+        $this->connection->executeStatement('INSERT INTO acme_foo (id) VALUES (1) ON DUPLICATE KEY UPDATE product = 1');
+        $this->connection->insert('product', ['id' => 1]);
+        $this->connection->createQueryBuilder()->select('id')->from('acme_foo', 'f')->leftJoin('f', 'product', 'p', 'p.id = f.product_id');
+        $this->logger->info('Select the product from the list');
+    }
+}
+`), index)
+
+	require.Len(t, results, 3)
+	assert.Equal(t, []int{10, 12, 13}, []int{results[0].Line, results[1].Line, results[2].Line})
+	assert.Equal(t, "php.connection_entity_table", results[0].Identifier)
+
+	migration := checkConnectionQueries(php.Parse(`<?php
+namespace Acme\Migration;
+
+use Doctrine\DBAL\Connection;
+use Shopware\Core\Framework\Migration\MigrationStep;
+
+class Migration1700000000Foo extends MigrationStep
+{
+    public function update(Connection $connection): void
+    {
+        $connection->executeStatement('UPDATE product SET active = 1');
+    }
+}
+`), index)
+	assert.Empty(t, migration)
+
+	withoutDBAL := checkConnectionQueries(php.Parse(`<?php
+class Foo
+{
+    private string $sql = 'SELECT id FROM product';
+}
+`), index)
+	assert.Empty(t, withoutDBAL)
+}
+
+func TestCheckRemovedEvents(t *testing.T) {
+	t.Parallel()
+
+	results := checkRemovedEvents(php.Parse(`<?php
+namespace Acme\Subscriber;
+
+use Shopware\Core\Content\Product\ProductEvents;
+use Shopware\Core\Content\Product\ProductRemovedEvent;
+use Symfony\Component\EventDispatcher\EventSubscriberInterface;
+
+class Subscriber implements EventSubscriberInterface
+{
+    public static function getSubscribedEvents(): array
+    {
+        return [
+            ProductEvents::PRODUCT_WRITTEN_EVENT => 'onWritten',
+            ProductEvents::PRODUCT_GONE_EVENT => 'onGone',
+            ProductRemovedEvent::class => 'onRemoved',
+            AcmeEvent::class => 'onAcme',
+        ];
+    }
+}
+`), testCoreIndex())
+
+	require.Len(t, results, 2)
+	assert.Equal(t, []string{"php.removed_event", "php.removed_event"}, identifiers(results))
+	assert.Equal(t, 14, results[0].Line)
+	assert.Contains(t, results[0].Message, "ProductEvents::PRODUCT_GONE_EVENT")
+	assert.Equal(t, 15, results[1].Line)
+	assert.Contains(t, results[1].Message, "ProductRemovedEvent does not exist in Shopware 6.7.0.0")
+}
+
+func TestCheckShopwarePHP(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "Resources", "config"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "vendor"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "Foo.php"), []byte(`<?php
+use Shopware\Core\Checkout\Cart\CartCalculator;
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "vendor", "Ignored.php"), []byte(`<?php
+use Shopware\Core\Checkout\Cart\CartCalculator;
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "Resources", "config", "services.xml"), []byte(`<argument type="service" id="Shopware\Core\Framework\DataAbstractionLayer\EntityRepository"/>`), 0o644))
+
+	original := loadCoreIndex
+	t.Cleanup(func() { loadCoreIndex = original })
+	loadCoreIndex = func(context.Context, ToolConfig) (*coreIndex, error) {
+		return testCoreIndex(), nil
+	}
+
+	check := NewCheck()
+	require.NoError(t, SWCLI{}.Check(t.Context(), check, ToolConfig{
+		RootDir:           root,
+		SourceDirectories: []string{src},
+	}))
+
+	results := check.GetResults()
+	require.Len(t, results, 2)
+	assert.Equal(t, "src/Resources/config/services.xml", results[0].Path)
+	assert.Equal(t, "php.repository_injected_by_class", results[0].Identifier)
+	assert.Equal(t, "src/Foo.php", results[1].Path)
+	assert.Equal(t, "php.internal_usage", results[1].Identifier)
+}
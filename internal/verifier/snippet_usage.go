@@ -0,0 +1,682 @@
+package verifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+// SnippetUsage compares the snippet keys used in Twig, JS and PHP with the
+// keys defined in the snippet files. Keys are only reported as undefined when
+// their first segment is a namespace the extension defines itself, as all
+// other keys are most likely provided by Shopware.
+type SnippetUsage struct{}
+
+type snippetDomain string
+
+const (
+	snippetDomainStorefront     snippetDomain = "storefront"
+	snippetDomainAdministration snippetDomain = "administration"
+)
+
+var (
+	twigTransSingleQuoted = regexp.MustCompile(`'([^'\n]+)'\s*\|\s*trans\b`)
+	twigTransDoubleQuoted = regexp.MustCompile(`"([^"\n]+)"\s*\|\s*trans\b`)
+	twigTransPrefix       = regexp.MustCompile(`['"]([\w-]+(?:\.[\w-]+)*\.)['"]\s*~`)
+	adminTranslateCall    = regexp.MustCompile("\\$tc?\\(\\s*(?:'([^'\\n]+)'|\"([^\"\\n]+)\"|`([^`\\n]+)`)")
+	snippetKeyLiteral     = regexp.MustCompile(`['"]([\w-]+(?:\.[\w-]+)+)['"]`)
+	snippetLocale         = regexp.MustCompile(`(?:^|\.)([a-z]{2}(?:-[A-Z]{2})?)(?:\.|$)`)
+	jsonIndent            = regexp.MustCompile(`\n([ \t]+)\S`)
+)
+
+type snippetDefinition struct {
+	Key    string
+	File   string
+	Line   int
+	Locale string
+	Domain snippetDomain
+}
+
+type snippetReference struct {
+	Key    string
+	File   string
+	Line   int
+	Domain snippetDomain
+	// Prefix references are built dynamically, like ('prefix.' ~ name)|trans
+	Prefix bool
+	// Strict references are translation calls, all others are string
+	// literals looking like a snippet key
+	Strict bool
+}
+
+type snippetAnalysis struct {
+	definitions []snippetDefinition
+	references  []snippetReference
+	files       map[string]snippetDomain
+}
+
+func (s SnippetUsage) Name() string {
+	return "snippet-usage"
+}
+
+func (s SnippetUsage) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	analysis, err := analyzeSnippets(config)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range analysis.results(config.RootDir) {
+		check.AddResult(result)
+	}
+
+	return nil
+}
+
+// Fix removes the unused keys from all snippet files.
+func (s SnippetUsage) Fix(ctx context.Context, config ToolConfig) error {
+	analysis, err := analyzeSnippets(config)
+	if err != nil {
+		return err
+	}
+
+	unused := map[snippetDomain]map[string]bool{}
+	for _, def := range analysis.unused() {
+		if unused[def.Domain] == nil {
+			unused[def.Domain] = map[string]bool{}
+		}
+
+		unused[def.Domain][def.Key] = true
+	}
+
+	for file, domain := range analysis.files {
+		if len(unused[domain]) == 0 {
+			continue
+		}
+
+		if err := pruneSnippetFile(file, unused[domain]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s SnippetUsage) Format(ctx context.Context, config ToolConfig, dryRun bool) error {
+	return nil
+}
+
+func analyzeSnippets(config ToolConfig) (*snippetAnalysis, error) {
+	analysis := &snippetAnalysis{files: map[string]snippetDomain{}}
+
+	for _, sourceDir := range config.SourceDirectories {
+		if _, err := os.Stat(sourceDir); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(sourceDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if d.Name() == "node_modules" || d.Name() == "vendor" || d.Name() == ".git" || (d.Name() == "public" && filepath.Base(filepath.Dir(path)) == "Resources") {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			rel, err := filepath.Rel(sourceDir, path)
+			if err != nil {
+				return err
+			}
+
+			rel = filepath.ToSlash(rel)
+
+			return analysis.addFile(path, rel)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return analysis, nil
+}
+
+func (a *snippetAnalysis) addFile(path, rel string) error {
+	isAdmin := strings.HasPrefix(rel, "Resources/app/administration/")
+
+	switch filepath.Ext(path) {
+	case ".json":
+		switch {
+		case strings.HasPrefix(rel, "Resources/snippet/"):
+			return a.addSnippetFile(path, snippetDomainStorefront)
+		case isAdmin && filepath.Base(filepath.Dir(path)) == "snippet":
+			return a.addSnippetFile(path, snippetDomainAdministration)
+		}
+	case ".twig":
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if isAdmin {
+			a.addAdminReferences(path, string(content))
+			return nil
+		}
+
+		a.addStorefrontTwigReferences(path, string(content))
+	case ".js", ".ts", ".vue":
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if isAdmin {
+			a.addAdminReferences(path, string(content))
+			return nil
+		}
+
+		a.addLiteralReferences(path, string(content), 0)
+	case ".php":
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		a.addLiteralReferences(path, string(content), 0)
+	}
+
+	return nil
+}
+
+func (a *snippetAnalysis) addSnippetFile(path string, domain snippetDomain) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	obj, err := parseSnippetJSON(content)
+	if err != nil {
+		// invalid files are reported by the snippet validator
+		return nil //nolint:nilerr
+	}
+
+	a.files[path] = domain
+
+	locale := ""
+	if match := snippetLocale.FindStringSubmatch(strings.TrimSuffix(filepath.Base(path), ".json")); match != nil {
+		locale = match[1]
+	}
+
+	obj.walk("", func(key string, line int) {
+		a.definitions = append(a.definitions, snippetDefinition{Key: key, File: path, Line: line, Locale: locale, Domain: domain})
+	})
+
+	return nil
+}
+
+func (a *snippetAnalysis) addStorefrontTwigReferences(path, content string) {
+	nodes, err := html.NewStorefrontParser(content)
+	if err != nil {
+		// unparsable files are reported by the storefront twig linter, fall
+		// back to the literals to not report their keys as unused
+		a.addLiteralReferences(path, content, 0)
+		return
+	}
+
+	walkTwigText(nodes.Nodes, 0, func(text string, line int) {
+		a.addTwigExpression(path, text, line)
+	}, func(key string, line int) {
+		a.references = append(a.references, snippetReference{Key: key, File: path, Line: line, Domain: snippetDomainStorefront, Strict: true})
+	})
+}
+
+func (a *snippetAnalysis) addTwigExpression(path, text string, line int) {
+	for _, re := range []*regexp.Regexp{twigTransSingleQuoted, twigTransDoubleQuoted} {
+		for _, match := range re.FindAllStringSubmatchIndex(text, -1) {
+			a.references = append(a.references, snippetReference{
+				Key:    text[match[2]:match[3]],
+				File:   path,
+				Line:   line + strings.Count(text[:match[0]], "\n"),
+				Domain: snippetDomainStorefront,
+				Strict: true,
+			})
+		}
+	}
+
+	if strings.Contains(text, "trans") {
+		for _, match := range twigTransPrefix.FindAllStringSubmatch(text, -1) {
+			a.references = append(a.references, snippetReference{Key: strings.TrimSuffix(match[1], "."), File: path, Line: line, Domain: snippetDomainStorefront, Prefix: true})
+		}
+	}
+
+	a.addLiteralReferences(path, text, line-1)
+}
+
+func (a *snippetAnalysis) addAdminReferences(path, content string) {
+	for _, match := range adminTranslateCall.FindAllStringSubmatchIndex(content, -1) {
+		key := ""
+		for group := 1; group <= 3; group++ {
+			if match[group*2] >= 0 {
+				key = content[match[group*2]:match[group*2+1]]
+			}
+		}
+
+		ref := snippetReference{
+			Key:    key,
+			File:   path,
+			Line:   strings.Count(content[:match[0]], "\n") + 1,
+			Domain: snippetDomainAdministration,
+			Strict: true,
+		}
+
+		// template literal like `sw-example.${name}`
+		if idx := strings.Index(key, "${"); idx >= 0 {
+			ref.Key = strings.TrimSuffix(key[:idx], ".")
+			ref.Prefix = true
+			ref.Strict = false
+		}
+
+		a.references = append(a.references, ref)
+	}
+
+	a.addLiteralReferences(path, content, 0)
+}
+
+// addLiteralReferences marks all string literals looking like a snippet key
+// as used, like the title of Module.register or $this->trans() in PHP.
+func (a *snippetAnalysis) addLiteralReferences(path, content string, lineOffset int) {
+	for _, match := range snippetKeyLiteral.FindAllStringSubmatchIndex(content, -1) {
+		a.references = append(a.references, snippetReference{
+			Key:  content[match[2]:match[3]],
+			File: path,
+			Line: lineOffset + strings.Count(content[:match[0]], "\n") + 1,
+		})
+	}
+}
+
+// walkTwigText calls text for every piece of Twig code in the template and
+// trans for the body of {% trans %} tags.
+func walkTwigText(nodes html.NodeList, parentLine int, text func(string, int), trans func(string, int)) {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *html.TemplateExpressionNode:
+			text(node.Expression, node.Line)
+		case *html.RawNode:
+			text(node.Text, node.Line)
+		case *html.Attribute:
+			text(node.Value, parentLine)
+		case *html.ElementNode:
+			walkTwigText(node.Attributes, node.Line, text, trans)
+			walkTwigText(node.Children, node.Line, text, trans)
+		case *html.TwigBlockNode:
+			walkTwigText(node.Children, node.Line, text, trans)
+		case *html.TwigIfNode:
+			for _, branch := range node.Branches {
+				text(branch.Condition, node.Line)
+				walkTwigText(branch.Body, node.Line, text, trans)
+			}
+
+			walkTwigText(node.ElseChildren, node.Line, text, trans)
+		case *html.TwigGenericBlockNode:
+			if node.Name == "trans" {
+				var body strings.Builder
+				for _, child := range node.Body {
+					if raw, ok := child.(*html.RawNode); ok {
+						body.WriteString(raw.Text)
+					}
+				}
+
+				if key := strings.TrimSpace(body.String()); key != "" {
+					trans(key, node.Line)
+				}
+
+				continue
+			}
+
+			text(node.Args, node.Line)
+			walkTwigText(node.Body, node.Line, text, trans)
+			walkTwigText(node.Else, node.Line, text, trans)
+		case *html.TwigStandaloneTagNode:
+			text(node.Args, node.Line)
+		}
+	}
+}
+
+func (a *snippetAnalysis) isUsed(def snippetDefinition) bool {
+	for _, ref := range a.references {
+		if ref.Domain != "" && ref.Domain != def.Domain {
+			continue
+		}
+
+		if def.Key == ref.Key || strings.HasPrefix(def.Key, ref.Key+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unused returns one definition per unused key, preferring the English file.
+func (a *snippetAnalysis) unused() []snippetDefinition {
+	byKey := map[string]snippetDefinition{}
+
+	for _, def := range a.definitions {
+		id := string(def.Domain) + ":" + def.Key
+
+		existing, ok := byKey[id]
+		if !ok || (!isEnglishLocale(existing.Locale) && isEnglishLocale(def.Locale)) {
+			byKey[id] = def
+		}
+	}
+
+	var unused []snippetDefinition
+
+	for _, def := range byKey {
+		if !a.isUsed(def) {
+			unused = append(unused, def)
+		}
+	}
+
+	return unused
+}
+
+func (a *snippetAnalysis) results(rootDir string) []validation.CheckResult {
+	relPath := func(path string) string {
+		return strings.TrimPrefix(strings.TrimPrefix(path, "/private"), rootDir+"/")
+	}
+
+	defined := map[snippetDomain]map[string]bool{}
+	namespaces := map[snippetDomain]map[string]bool{}
+	english := map[snippetDomain]map[string]bool{}
+	hasEnglish := map[snippetDomain]bool{}
+
+	for _, def := range a.definitions {
+		if defined[def.Domain] == nil {
+			defined[def.Domain] = map[string]bool{}
+			namespaces[def.Domain] = map[string]bool{}
+			english[def.Domain] = map[string]bool{}
+		}
+
+		defined[def.Domain][def.Key] = true
+		namespaces[def.Domain][strings.SplitN(def.Key, ".", 2)[0]] = true
+
+		if isEnglishLocale(def.Locale) {
+			english[def.Domain][def.Key] = true
+			hasEnglish[def.Domain] = true
+		}
+	}
+
+	var results []validation.CheckResult
+
+	for _, ref := range a.references {
+		if !ref.Strict || defined[ref.Domain][ref.Key] || !namespaces[ref.Domain][strings.SplitN(ref.Key, ".", 2)[0]] {
+			continue
+		}
+
+		if hasDefinedChild(defined[ref.Domain], ref.Key) {
+			continue
+		}
+
+		results = append(results, validation.CheckResult{
+			Path:       relPath(ref.File),
+			Line:       ref.Line,
+			Identifier: "snippet/undefined-key",
+			Message:    fmt.Sprintf("Snippet key \"%s\" is used, but not defined in the %s snippet files", ref.Key, ref.Domain),
+			Severity:   validation.SeverityWarning,
+		})
+	}
+
+	for _, def := range a.unused() {
+		results = append(results, validation.CheckResult{
+			Path:       relPath(def.File),
+			Line:       def.Line,
+			Identifier: "snippet/unused-key",
+			Message:    fmt.Sprintf("Snippet key \"%s\" is not used in any template or script", def.Key),
+			Severity:   validation.SeverityWarning,
+		})
+	}
+
+	for _, def := range a.definitions {
+		if !isGermanLocale(def.Locale) || !hasEnglish[def.Domain] || english[def.Domain][def.Key] {
+			continue
+		}
+
+		results = append(results, validation.CheckResult{
+			Path:       relPath(def.File),
+			Line:       def.Line,
+			Identifier: "snippet/missing-in-en-GB",
+			Message:    fmt.Sprintf("Snippet key \"%s\" is defined in %s, but missing in en-GB", def.Key, def.Locale),
+			Severity:   validation.SeverityWarning,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Path != results[j].Path {
+			return results[i].Path < results[j].Path
+		}
+
+		return results[i].Line < results[j].Line
+	})
+
+	return results
+}
+
+func hasDefinedChild(defined map[string]bool, key string) bool {
+	for definedKey := range defined {
+		if strings.HasPrefix(definedKey, key+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEnglishLocale(locale string) bool {
+	return locale == "en-GB" || locale == "en"
+}
+
+func isGermanLocale(locale string) bool {
+	return locale == "de-DE" || locale == "de"
+}
+
+// snippetObject is a JSON object keeping the order of its keys and the raw
+// values of its leaves, so pruning keys does not reformat the rest of the file.
+type snippetObject struct {
+	keys     []string
+	leaves   map[string]json.RawMessage
+	children map[string]*snippetObject
+	lines    map[string]int
+}
+
+func parseSnippetJSON(data []byte) (*snippetObject, error) {
+	var lineStarts []int
+	for i, c := range data {
+		if c == '\n' {
+			lineStarts = append(lineStarts, i)
+		}
+	}
+
+	lineAt := func(offset int) int {
+		return sort.SearchInts(lineStarts, offset) + 1
+	}
+
+	return parseSnippetObject(data, 0, lineAt)
+}
+
+func parseSnippetObject(data []byte, base int, lineAt func(int) int) (*snippetObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	obj := &snippetObject{leaves: map[string]json.RawMessage{}, children: map[string]*snippetObject{}, lines: map[string]int{}}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an object key")
+		}
+
+		line := lineAt(base + int(dec.InputOffset()))
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		if _, exists := obj.lines[key]; !exists {
+			obj.keys = append(obj.keys, key)
+		}
+
+		obj.lines[key] = line
+
+		if len(raw) > 0 && raw[0] == '{' {
+			child, err := parseSnippetObject(raw, base+int(dec.InputOffset())-len(raw), lineAt)
+			if err != nil {
+				return nil, err
+			}
+
+			obj.children[key] = child
+			delete(obj.leaves, key)
+
+			continue
+		}
+
+		obj.leaves[key] = raw
+		delete(obj.children, key)
+	}
+
+	return obj, nil
+}
+
+func (o *snippetObject) walk(prefix string, fn func(key string, line int)) {
+	for _, key := range o.keys {
+		if child, ok := o.children[key]; ok {
+			child.walk(prefix+key+".", fn)
+			continue
+		}
+
+		fn(prefix+key, o.lines[key])
+	}
+}
+
+// prune removes the keys and parents left empty and reports whether anything
+// was removed.
+func (o *snippetObject) prune(prefix string, remove map[string]bool) bool {
+	changed := false
+
+	o.keys = slices.DeleteFunc(o.keys, func(key string) bool {
+		if child, ok := o.children[key]; ok {
+			childChanged := child.prune(prefix+key+".", remove)
+			if childChanged {
+				changed = true
+			}
+
+			return childChanged && len(child.keys) == 0
+		}
+
+		if remove[prefix+key] {
+			changed = true
+			return true
+		}
+
+		return false
+	})
+
+	return changed
+}
+
+func (o *snippetObject) write(buf *bytes.Buffer, indent string, depth int) {
+	if len(o.keys) == 0 {
+		buf.WriteString("{}")
+		return
+	}
+
+	buf.WriteString("{\n")
+
+	for i, key := range o.keys {
+		buf.WriteString(strings.Repeat(indent, depth+1))
+
+		encodedKey := &bytes.Buffer{}
+		enc := json.NewEncoder(encodedKey)
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(key)
+
+		buf.Write(bytes.TrimSpace(encodedKey.Bytes()))
+		buf.WriteString(": ")
+
+		if child, ok := o.children[key]; ok {
+			child.write(buf, indent, depth+1)
+		} else {
+			buf.Write(o.leaves[key])
+		}
+
+		if i < len(o.keys)-1 {
+			buf.WriteString(",")
+		}
+
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString(strings.Repeat(indent, depth))
+	buf.WriteString("}")
+}
+
+func pruneSnippetFile(path string, remove map[string]bool) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	obj, err := parseSnippetJSON(content)
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	if !obj.prune("", remove) {
+		return nil
+	}
+
+	indent := "    "
+	if match := jsonIndent.FindSubmatch(content); match != nil {
+		indent = string(match[1])
+	}
+
+	var buf bytes.Buffer
+	obj.write(&buf, indent, 0)
+
+	if bytes.HasSuffix(content, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+
+	return os.WriteFile(path, buf.Bytes(), os.ModePerm)
+}
+
+func init() {
+	html.RegisterBlockTag("trans", "endtrans")
+	AddTool(SnippetUsage{})
+}
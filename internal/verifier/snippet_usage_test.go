@@ -0,0 +1,163 @@
+package verifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+func writeSnippetUsageFile(t *testing.T, root, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(root, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func setupSnippetUsageExtension(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+
+	writeSnippetUsageFile(t, root, "src/Resources/snippet/storefront.en-GB.json", `{
+  "swag-example": {
+    "title": "Title",
+    "unused": "Unused",
+    "block": "Block",
+    "dynamic": {
+      "red": "Red"
+    }
+  }
+}
+`)
+	writeSnippetUsageFile(t, root, "src/Resources/snippet/storefront.de-DE.json", `{
+  "swag-example": {
+    "title": "Titel",
+    "unused": "Unbenutzt",
+    "block": "Block",
+    "onlyGerman": "Nur deutsch",
+    "dynamic": {
+      "red": "Rot"
+    }
+  }
+}
+`)
+	writeSnippetUsageFile(t, root, "src/Resources/views/storefront/page/index.html.twig", `{% block page %}
+    <h1 title="{{ 'swag-example.title'|trans }}">{{ "swag-example.title"|trans|sw_sanitize }}</h1>
+    {% trans %}swag-example.block{% endtrans %}
+    {{ ('swag-example.dynamic.' ~ color)|trans }}
+    {{ 'swag-example.missing'|trans }}
+    {{ 'general.homeLink'|trans }}
+{% endblock %}
+`)
+	writeSnippetUsageFile(t, root, "src/Resources/app/administration/src/module/swag-example/snippet/en-GB.json", `{
+    "swag-example-admin": {
+        "general": {
+            "mainMenuItem": "Example",
+            "unused": "Unused <b>HTML</b>"
+        },
+        "detail": {
+            "save": "Save"
+        }
+    }
+}
+`)
+	writeSnippetUsageFile(t, root, "src/Resources/app/administration/src/module/swag-example/index.js", `Module.register('swag-example', {
+    title: 'swag-example-admin.general.mainMenuItem',
+});
+`)
+	writeSnippetUsageFile(t, root, "src/Resources/app/administration/src/module/swag-example/page/swag-example-detail.html.twig", `<sw-button :label="$tc('swag-example-admin.detail.save')">
+    {{ $t('swag-example-admin.detail.cancel') }}
+    {{ $tc('sw-global.default.add') }}
+</sw-button>
+`)
+
+	return root
+}
+
+func TestSnippetUsageCheck(t *testing.T) {
+	root := setupSnippetUsageExtension(t)
+
+	check := NewCheck()
+	require.NoError(t, SnippetUsage{}.Check(t.Context(), check, ToolConfig{
+		RootDir:           root,
+		SourceDirectories: []string{filepath.Join(root, "src")},
+	}))
+
+	type result struct {
+		Path, Identifier string
+		Line             int
+	}
+
+	var results []result
+	for _, r := range check.GetResults() {
+		assert.Equal(t, validation.SeverityWarning, r.Severity)
+		results = append(results, result{r.Path, r.Identifier, r.Line})
+	}
+
+	assert.Equal(t, []result{
+		{"src/Resources/app/administration/src/module/swag-example/page/swag-example-detail.html.twig", "snippet/undefined-key", 2},
+		{"src/Resources/app/administration/src/module/swag-example/snippet/en-GB.json", "snippet/unused-key", 5},
+		{"src/Resources/snippet/storefront.de-DE.json", "snippet/unused-key", 6},
+		{"src/Resources/snippet/storefront.de-DE.json", "snippet/missing-in-en-GB", 6},
+		{"src/Resources/snippet/storefront.en-GB.json", "snippet/unused-key", 4},
+		{"src/Resources/views/storefront/page/index.html.twig", "snippet/undefined-key", 5},
+	}, results)
+}
+
+func TestSnippetUsageFix(t *testing.T) {
+	root := setupSnippetUsageExtension(t)
+
+	require.NoError(t, SnippetUsage{}.Fix(t.Context(), ToolConfig{
+		RootDir:           root,
+		SourceDirectories: []string{filepath.Join(root, "src")},
+	}))
+
+	storefrontDE, err := os.ReadFile(filepath.Join(root, "src/Resources/snippet/storefront.de-DE.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `{
+  "swag-example": {
+    "title": "Titel",
+    "block": "Block",
+    "dynamic": {
+      "red": "Rot"
+    }
+  }
+}
+`, string(storefrontDE))
+
+	admin, err := os.ReadFile(filepath.Join(root, "src/Resources/app/administration/src/module/swag-example/snippet/en-GB.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, `{
+    "swag-example-admin": {
+        "general": {
+            "mainMenuItem": "Example"
+        },
+        "detail": {
+            "save": "Save"
+        }
+    }
+}
+`, string(admin))
+}
+
+func TestSnippetObjectPruneRemovesEmptyParents(t *testing.T) {
+	obj, err := parseSnippetJSON([]byte(`{"a": {"b": {"c": "<x>"}}, "d": "e"}`))
+	require.NoError(t, err)
+
+	var keys []string
+	obj.walk("", func(key string, line int) {
+		keys = append(keys, key)
+	})
+	assert.Equal(t, []string{"a.b.c", "d"}, keys)
+
+	assert.True(t, obj.prune("", map[string]bool{"a.b.c": true}))
+	assert.Equal(t, []string{"d"}, obj.keys)
+}
@@ -42,7 +42,7 @@ func (s StorefrontTwigLinter) Check(ctx context.Context, check *Check, config To
 				return nil
 			}
 
-			if filepath.Ext(path) != twiglinter.TwigExtension {
+			if filepath.Ext(path) != twiglinter.TwigExtension || !config.IsChanged(path) {
 				return nil
 			}
 
@@ -92,7 +92,59 @@ func (s StorefrontTwigLinter) Check(ctx context.Context, check *Check, config To
 
 	return nil
 }
+
+// Fix applies the autofixes of the rules. Fixed files are written in the
+// style of the Twig formatter, files without fixes are left untouched.
 func (s StorefrontTwigLinter) Fix(ctx context.Context, config ToolConfig) error {
+	fixers := twiglinter.GetStorefrontFixers(version.Must(version.NewVersion(config.MinShopwareVersion)))
+
+	for _, p := range config.SourceDirectories {
+		twigDir := filepath.Join(p, "Resources", "views")
+
+		if _, err := os.Stat(twigDir); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(twigDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() || filepath.Ext(path) != twiglinter.TwigExtension {
+				return nil
+			}
+
+			file, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			parsed, err := html.NewStorefrontParser(string(file))
+			if err != nil {
+				// reported by Check
+				return nil //nolint:nilerr
+			}
+
+			before := parsed.Dump(0)
+
+			for _, fixer := range fixers {
+				if err := fixer.Fix(parsed.Nodes); err != nil {
+					return err
+				}
+			}
+
+			after := parsed.Dump(0)
+			if after == before {
+				return nil
+			}
+
+			return os.WriteFile(path, []byte(after), 0o644)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
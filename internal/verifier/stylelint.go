@@ -56,15 +56,27 @@ func (s StyleLint) Check(ctx context.Context, check *Check, config ToolConfig) e
 			continue
 		}
 
+		files := []string{fmt.Sprintf("%s/**/*.scss", p)}
+
+		if config.IsIncremental() {
+			files = config.ChangedFilesIn(p, ".scss")
+
+			if len(files) == 0 {
+				continue
+			}
+		}
+
 		gr.Go(func() error {
-			stylelint := exec.CommandContext(ctx, "node", path.Join(config.ToolDirectory, "js", "node_modules", ".bin", "stylelint"),
+			args := []string{
+				path.Join(config.ToolDirectory, "js", "node_modules", ".bin", "stylelint"),
 				"--formatter=json",
 				"--config", path.Join(config.ToolDirectory, "js", "configs", fmt.Sprintf("stylelint.config.%s.mjs", path.Base(p))),
 				"--ignore-pattern", "dist/**",
 				"--ignore-pattern", ".tmp/**",
 				"--ignore-pattern", "vendor/**",
-				fmt.Sprintf("%s/**/*.scss", p),
-			)
+			}
+
+			stylelint := exec.CommandContext(ctx, "node", append(args, files...)...)
 			stylelint.Dir = p
 
 			log, _ := stylelint.CombinedOutput()
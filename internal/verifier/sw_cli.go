@@ -14,6 +14,10 @@ func (s SWCLI) Name() string {
 }
 
 func (s SWCLI) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	if err := checkShopwarePHP(ctx, check, config); err != nil {
+		return err
+	}
+
 	if config.Extension == nil {
 		return nil
 	}
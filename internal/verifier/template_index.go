@@ -0,0 +1,539 @@
+package verifier
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/system"
+	"github.com/shopware/shopware-cli/internal/verifier/jslinter"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+const templateIndexArchiveURL = "https://codeload.github.com/shopware/%s/tar.gz/refs/tags/v%s"
+
+// templateIndexCacheVersion is part of the cache key, bump it when the
+// layout of templateIndex changes.
+const templateIndexCacheVersion = "2"
+
+const (
+	storefrontViewsDir       = "Resources/views/"
+	administrationSourcesDir = "Resources/app/administration/src/"
+)
+
+var (
+	storefrontExtendsTarget = regexp.MustCompile(`@Storefront/([^'"\s]+\.twig)`)
+	twigBlockFallback       = regexp.MustCompile(`\{%-?\s*block\s+([\w.]+)`)
+)
+
+// templateIndex lists the templates and blocks one Shopware version ships.
+// Blocks map to their deprecation note, which is empty for blocks that are
+// not deprecated.
+type templateIndex struct {
+	Version        string                      `json:"version"`
+	Storefront     map[string]*indexedTemplate `json:"storefront"`
+	Administration map[string]string           `json:"administration"`
+	// AdministrationRegistry lists the registered components, mixins and services
+	AdministrationRegistry *jslinter.Registry `json:"administrationRegistry"`
+}
+
+type indexedTemplate struct {
+	Extends    string            `json:"extends,omitempty"`
+	Deprecated string            `json:"deprecated,omitempty"`
+	Blocks     map[string]string `json:"blocks"`
+}
+
+func newTemplateIndex(shopwareVersion string) *templateIndex {
+	return &templateIndex{
+		Version:        shopwareVersion,
+		Storefront:     map[string]*indexedTemplate{},
+		Administration: map[string]string{},
+
+		AdministrationRegistry: jslinter.NewRegistry(),
+	}
+}
+
+// addSource indexes a file of the storefront or administration package by
+// its path in the package.
+func (i *templateIndex) addSource(pkg, name, content string) {
+	switch {
+	case pkg == "storefront" && strings.HasPrefix(name, storefrontViewsDir) && path.Ext(name) == ".twig":
+		i.addStorefrontTemplate(strings.TrimPrefix(name, storefrontViewsDir), content)
+	case pkg == "administration" && strings.HasPrefix(name, administrationSourcesDir) && path.Ext(name) == ".twig":
+		i.addAdministrationTemplate(content)
+	case pkg == "administration" && strings.HasPrefix(name, administrationSourcesDir) && isAdministrationScript(name):
+		i.AdministrationRegistry.Collect(jslinter.NewFile(content))
+	}
+}
+
+// isAdministrationScript reports whether the file is JavaScript or
+// TypeScript, but not a test.
+func isAdministrationScript(name string) bool {
+	ext := path.Ext(name)
+	if ext != ".js" && ext != ".ts" {
+		return false
+	}
+
+	return !strings.Contains(name, ".spec.") && !strings.Contains(name, "/node_modules/")
+}
+
+// addStorefrontTemplate indexes a template by its path below Resources/views,
+// like storefront/base.html.twig.
+func (i *templateIndex) addStorefrontTemplate(name, content string) {
+	tpl := &indexedTemplate{Blocks: map[string]string{}}
+	i.Storefront[name] = tpl
+
+	parsed, err := html.NewStorefrontParser(content)
+	if err != nil {
+		// Without an AST the deprecations are unknown, but the blocks are still worth indexing
+		for _, match := range twigBlockFallback.FindAllStringSubmatch(content, -1) {
+			tpl.Blocks[match[1]] = ""
+		}
+
+		if match := storefrontExtendsTarget.FindStringSubmatch(content); match != nil {
+			tpl.Extends = match[1]
+		}
+
+		return
+	}
+
+	tpl.Extends = storefrontExtends(parsed.Nodes)
+	tpl.Deprecated = collectTemplateBlocks(parsed.Nodes, true, tpl.Blocks)
+}
+
+// addAdministrationTemplate indexes the blocks of a component template. The
+// administration overrides blocks by name, so blocks are indexed globally.
+func (i *templateIndex) addAdministrationTemplate(content string) {
+	blocks := map[string]string{}
+
+	parsed, err := html.NewAdminParser(content)
+	if err != nil {
+		for _, match := range twigBlockFallback.FindAllStringSubmatch(content, -1) {
+			blocks[match[1]] = ""
+		}
+	} else {
+		collectTemplateBlocks(parsed.Nodes, false, blocks)
+	}
+
+	for name, deprecated := range blocks {
+		// A block is only deprecated when every component defining it deprecates it
+		if existing, ok := i.Administration[name]; ok && existing == "" {
+			continue
+		}
+
+		i.Administration[name] = deprecated
+	}
+}
+
+// storefrontBlocks returns the blocks available to a template extending the
+// given one, including those inherited from its parents.
+func (i *templateIndex) storefrontBlocks(name string) (map[string]string, bool) {
+	tpl, ok := i.Storefront[name]
+	if !ok {
+		return nil, false
+	}
+
+	blocks := map[string]string{}
+	seen := map[string]bool{}
+
+	for tpl != nil && !seen[name] {
+		seen[name] = true
+
+		for block, deprecated := range tpl.Blocks {
+			if _, ok := blocks[block]; !ok {
+				blocks[block] = deprecated
+			}
+		}
+
+		name = tpl.Extends
+		tpl = i.Storefront[name]
+	}
+
+	return blocks, true
+}
+
+// storefrontExtends returns the @Storefront template the template extends.
+func storefrontExtends(nodes html.NodeList) string {
+	for _, node := range nodes {
+		tag, ok := node.(*html.TwigStandaloneTagNode)
+		if !ok || (tag.Name != "sw_extends" && tag.Name != "extends") {
+			continue
+		}
+
+		if match := storefrontExtendsTarget.FindStringSubmatch(tag.Args); match != nil {
+			return match[1]
+		}
+	}
+
+	return ""
+}
+
+// collectTemplateBlocks adds all blocks to the map with their deprecation
+// note. Shopware marks deprecations with a {# @deprecated ... #} comment in
+// front of the block or a {% deprecated %} tag as first statement of it. On
+// the top level such a marker in front of anything else than a block
+// deprecates the whole template, which is returned.
+func collectTemplateBlocks(nodes html.NodeList, topLevel bool, blocks map[string]string) string {
+	templateDeprecation := ""
+	pending := ""
+
+	for _, node := range nodes {
+		if note, ok := deprecationMarker(node); ok {
+			pending = note
+			continue
+		}
+
+		if raw, ok := node.(*html.RawNode); ok && strings.TrimSpace(raw.Text) == "" {
+			continue
+		}
+
+		if block, ok := node.(*html.TwigBlockNode); ok {
+			deprecated := pending
+			if deprecated == "" {
+				deprecated = leadingDeprecation(block.Children)
+			}
+
+			blocks[block.Name] = deprecated
+			collectTemplateBlocks(block.Children, false, blocks)
+			pending = ""
+
+			continue
+		}
+
+		if topLevel && pending != "" && templateDeprecation == "" {
+			templateDeprecation = pending
+		}
+
+		pending = ""
+
+		switch node := node.(type) {
+		case *html.ElementNode:
+			collectTemplateBlocks(node.Children, false, blocks)
+		case *html.TwigIfNode:
+			for _, branch := range node.Branches {
+				collectTemplateBlocks(branch.Body, false, blocks)
+			}
+
+			collectTemplateBlocks(node.ElseChildren, false, blocks)
+		case *html.TwigGenericBlockNode:
+			collectTemplateBlocks(node.Body, false, blocks)
+			collectTemplateBlocks(node.Else, false, blocks)
+		}
+	}
+
+	if topLevel && pending != "" && templateDeprecation == "" {
+		templateDeprecation = pending
+	}
+
+	return templateDeprecation
+}
+
+// leadingDeprecation returns the deprecation marker a block starts with.
+func leadingDeprecation(nodes html.NodeList) string {
+	for _, node := range nodes {
+		if note, ok := deprecationMarker(node); ok {
+			return note
+		}
+
+		if raw, ok := node.(*html.RawNode); ok && strings.TrimSpace(raw.Text) == "" {
+			continue
+		}
+
+		if _, ok := node.(*html.TwigCommentNode); ok {
+			continue
+		}
+
+		return ""
+	}
+
+	return ""
+}
+
+func deprecationMarker(node html.Node) (string, bool) {
+	switch node := node.(type) {
+	case *html.TwigCommentNode:
+		if idx := strings.Index(node.Body, "@deprecated"); idx >= 0 {
+			return strings.Join(strings.Fields(node.Body[idx:]), " "), true
+		}
+	case *html.TwigStandaloneTagNode:
+		if node.Name == "deprecated" {
+			note := strings.Trim(strings.TrimSpace(node.Args), `'"`)
+			if note == "" {
+				note = "@deprecated"
+			}
+
+			return note, true
+		}
+	}
+
+	return "", false
+}
+
+// loadTemplateIndex returns the template index of the Shopware version the
+// extension is checked against. It is a package variable so tests can
+// provide an index without vendor directory or network.
+var loadTemplateIndex = func(ctx context.Context, config ToolConfig) (*templateIndex, error) {
+	if config.TemplateIndexVersion != "" {
+		return cachedTemplateIndex(ctx, config.TemplateIndexVersion, func() (*templateIndex, error) {
+			return downloadTemplateIndex(ctx, config.TemplateIndexVersion)
+		})
+	}
+
+	storefrontVendor := filepath.Join(config.RootDir, "vendor", "shopware", "storefront")
+
+	if installed := installedComposerVersion(config.RootDir, "shopware/storefront"); installed != "" {
+		if _, err := os.Stat(storefrontVendor); err == nil {
+			return cachedTemplateIndex(ctx, installed, func() (*templateIndex, error) {
+				return buildTemplateIndexFromVendor(installed, filepath.Join(config.RootDir, "vendor", "shopware"))
+			})
+		}
+	}
+
+	shopwareVersion, err := checkedShopwareVersion(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return cachedTemplateIndex(ctx, shopwareVersion, func() (*templateIndex, error) {
+		return downloadTemplateIndex(ctx, shopwareVersion)
+	})
+}
+
+// checkedShopwareVersion returns the Shopware version the indexes are built
+// for when no Shopware is installed in vendor.
+func checkedShopwareVersion(config ToolConfig) (string, error) {
+	shopwareVersion := config.MaxShopwareVersion
+	if config.CheckAgainst == "lowest" {
+		shopwareVersion = config.MinShopwareVersion
+	}
+
+	if shopwareVersion == "" {
+		return "", fmt.Errorf("cannot determine the Shopware version to check against")
+	}
+
+	return shopwareVersion, nil
+}
+
+// cachedTemplateIndex keeps built template indexes in the cache.
+func cachedTemplateIndex(ctx context.Context, shopwareVersion string, build func() (*templateIndex, error)) (*templateIndex, error) {
+	return cachedIndex(ctx, "template-index", templateIndexCacheVersion, shopwareVersion, build)
+}
+
+// cachedIndex keeps built indexes in the cache. Dev versions move, only
+// released versions are cached.
+func cachedIndex[T any](ctx context.Context, prefix, layoutVersion, shopwareVersion string, build func() (*T, error)) (*T, error) {
+	cacheable := !strings.Contains(shopwareVersion, "dev")
+	cache := system.GetCacheWithPrefix(prefix)
+	cacheKey := fmt.Sprintf("%s-%s-%s", prefix, layoutVersion, shopwareVersion)
+
+	if cacheable {
+		if reader, err := cache.Get(ctx, cacheKey); err == nil {
+			defer func() {
+				_ = reader.Close()
+			}()
+
+			var index T
+			if err := json.NewDecoder(reader).Decode(&index); err == nil {
+				return &index, nil
+			}
+		}
+	}
+
+	index, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheable {
+		data, err := json.Marshal(index)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := cache.Set(ctx, cacheKey, bytes.NewReader(data)); err != nil {
+			logging.FromContext(ctx).Debugf("Could not cache %s: %v", prefix, err)
+		}
+	}
+
+	return index, nil
+}
+
+// installedComposerVersion reads the version of an installed package from
+// vendor/composer/installed.json without leading v.
+func installedComposerVersion(rootDir, packageName string) string {
+	data, err := os.ReadFile(filepath.Join(rootDir, "vendor", "composer", "installed.json"))
+	if err != nil {
+		return ""
+	}
+
+	type installedPackage struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	var installed struct {
+		Packages []installedPackage `json:"packages"`
+	}
+
+	// Composer 1 writes a plain list of packages
+	if err := json.Unmarshal(data, &installed); err != nil {
+		if err := json.Unmarshal(data, &installed.Packages); err != nil {
+			return ""
+		}
+	}
+
+	for _, pkg := range installed.Packages {
+		if pkg.Name == packageName {
+			return strings.TrimPrefix(pkg.Version, "v")
+		}
+	}
+
+	return ""
+}
+
+// buildTemplateIndexFromVendor indexes vendor/shopware/storefront and
+// vendor/shopware/administration.
+func buildTemplateIndexFromVendor(shopwareVersion, vendorDir string) (*templateIndex, error) {
+	index := newTemplateIndex(shopwareVersion)
+
+	for _, pkg := range []string{"storefront", "administration"} {
+		pkgDir := filepath.Join(vendorDir, pkg)
+		if _, err := os.Stat(pkgDir); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(pkgDir, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if d.Name() == "node_modules" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			rel, err := filepath.Rel(pkgDir, file)
+			if err != nil {
+				return err
+			}
+
+			rel = filepath.ToSlash(rel)
+			if path.Ext(rel) != ".twig" && !isAdministrationScript(rel) {
+				return nil
+			}
+
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+
+			index.addSource(pkg, rel, string(content))
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return index, nil
+}
+
+// downloadTemplateIndex indexes the release archives of the storefront and
+// administration split repositories.
+func downloadTemplateIndex(ctx context.Context, shopwareVersion string) (*templateIndex, error) {
+	index := newTemplateIndex(shopwareVersion)
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	logging.FromContext(ctx).Infof("Downloading the templates of Shopware %s", shopwareVersion)
+
+	for _, repository := range []string{"storefront", "administration"} {
+		if err := downloadTemplateArchive(ctx, repository, fmt.Sprintf(templateIndexArchiveURL, repository, shopwareVersion), index); err != nil {
+			return nil, fmt.Errorf("download %s templates of %s: %w", repository, shopwareVersion, err)
+		}
+	}
+
+	return index, nil
+}
+
+func downloadTemplateArchive(ctx context.Context, pkg, url string, index *templateIndex) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return indexTemplateArchive(resp.Body, pkg, index)
+}
+
+// indexTemplateArchive reads the templates and administration sources of a
+// GitHub tarball, which contains the repository in a single top level folder.
+func indexTemplateArchive(r io.Reader, pkg string, index *templateIndex) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		_, name, found := strings.Cut(header.Name, "/")
+		if !found || (path.Ext(name) != ".twig" && !isAdministrationScript(name)) {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+
+		index.addSource(pkg, name, string(content))
+	}
+}
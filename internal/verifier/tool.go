@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/shopware/shopware-cli/internal/extension"
+	"github.com/shopware/shopware-cli/internal/tracing"
 	"github.com/shopware/shopware-cli/internal/validation"
 )
 
@@ -33,16 +36,25 @@ type ToolConfig struct {
 	MaxShopwareVersion string
 	// The version of Shopware that is checked against
 	CheckAgainst string
+	// The Shopware version the template index is built for, instead of the
+	// installed one. Used to check the templates against an upgrade target.
+	TemplateIndexVersion string
 	// The root directory of the extension/project
 	RootDir string
 	// Contains a list of directories that are considered as source code
 	SourceDirectories []string
 	// Contains a list of identifiers that are ignored
 	ValidationIgnores []validation.ToolConfigIgnore
+	// Maps identifiers to the severity to report them with
+	SeverityOverrides map[string]string
 	// Contains a list of directories that are considered as admin code
 	AdminDirectories []string
 	// Contains a list of directories that are considered as storefront code
 	StorefrontDirectories []string
+	// Contains the absolute paths of the changed files when only those are
+	// validated, nil validates everything. Tools which cannot check single
+	// files run on everything and their results are filtered.
+	ChangedFiles []string
 
 	Extension extension.Extension
 }
@@ -138,3 +150,44 @@ func (tl ToolList) PossibleString() string {
 
 	return strings.Join(possibleTools, ",")
 }
+
+// Check runs the check of all tools in parallel and records a trace span per tool.
+func (tl ToolList) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	return tl.run(ctx, "check", func(ctx context.Context, tool Tool) error {
+		return tool.Check(ctx, check, config)
+	})
+}
+
+// Fix runs the fixers of all tools in parallel and records a trace span per tool.
+func (tl ToolList) Fix(ctx context.Context, config ToolConfig) error {
+	return tl.run(ctx, "fix", func(ctx context.Context, tool Tool) error {
+		return tool.Fix(ctx, config)
+	})
+}
+
+// Format runs the formatters of all tools in parallel and records a trace span per tool.
+func (tl ToolList) Format(ctx context.Context, config ToolConfig, dryRun bool) error {
+	return tl.run(ctx, "format", func(ctx context.Context, tool Tool) error {
+		return tool.Format(ctx, config, dryRun)
+	})
+}
+
+func (tl ToolList) run(ctx context.Context, operation string, fn func(ctx context.Context, tool Tool) error) error {
+	var gr errgroup.Group
+
+	for _, tool := range tl {
+		gr.Go(func() error {
+			toolCtx, span := tracing.Start(ctx, fmt.Sprintf("verifier %s %s", operation, tool.Name()),
+				tracing.String("verifier.tool", tool.Name()),
+				tracing.String("verifier.operation", operation),
+			)
+
+			err := fn(toolCtx, tool)
+			span.End(err)
+
+			return err
+		})
+	}
+
+	return gr.Wait()
+}
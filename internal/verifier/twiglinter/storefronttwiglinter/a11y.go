@@ -0,0 +1,80 @@
+package storefronttwiglinter
+
+import (
+	"strings"
+
+	"github.com/shopware/shopware-cli/internal/html"
+)
+
+// findAttribute returns a static attribute of the element.
+func findAttribute(node *html.ElementNode, key string) (*html.Attribute, bool) {
+	for _, attr := range node.Attributes {
+		attrElement, ok := attr.(*html.Attribute)
+		if ok && attrElement.Key == key {
+			return attrElement, true
+		}
+	}
+
+	return nil, false
+}
+
+// getAttribute returns the value of a static attribute of the element.
+func getAttribute(node *html.ElementNode, key string) (string, bool) {
+	if attr, ok := findAttribute(node, key); ok {
+		return attr.Value, true
+	}
+
+	return "", false
+}
+
+// hasNonEmptyAttribute reports whether one of the attributes is set to a
+// non-empty value.
+func hasNonEmptyAttribute(node *html.ElementNode, keys ...string) bool {
+	for _, key := range keys {
+		if value, ok := getAttribute(node, key); ok && strings.TrimSpace(value) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasDynamicAttributes reports whether attributes are added by Twig, like
+// {% if %} inside the tag or {{ attributes }}. The accessibility checks skip
+// such elements, as the attribute could be among them.
+func hasDynamicAttributes(node *html.ElementNode) bool {
+	for _, attr := range node.Attributes {
+		if _, ok := attr.(*html.Attribute); !ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTwigValue reports whether the attribute value is computed by Twig.
+func isTwigValue(value string) bool {
+	return strings.Contains(value, "{{") || strings.Contains(value, "{%")
+}
+
+// traverseWithAncestors is html.TraverseNode with the enclosing elements of
+// each element, innermost last.
+func traverseWithAncestors(nodes html.NodeList, ancestors []*html.ElementNode, f func(node *html.ElementNode, ancestors []*html.ElementNode)) {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *html.ElementNode:
+			f(node, ancestors)
+			traverseWithAncestors(node.Children, append(ancestors, node), f)
+		case *html.TwigBlockNode:
+			traverseWithAncestors(node.Children, ancestors, f)
+		case *html.TwigIfNode:
+			for _, br := range node.Branches {
+				traverseWithAncestors(br.Body, ancestors, f)
+			}
+			traverseWithAncestors(node.ElseChildren, ancestors, f)
+		case *html.TwigGenericBlockNode:
+			traverseWithAncestors(node.Body, ancestors, f)
+			traverseWithAncestors(node.Else, ancestors, f)
+		}
+	}
+}
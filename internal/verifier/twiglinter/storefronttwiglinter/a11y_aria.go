@@ -0,0 +1,187 @@
+package storefronttwiglinter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+// ariaAttributes are the states and properties of WAI-ARIA 1.2 with their
+// allowed values, nil allows any value.
+var ariaAttributes = map[string][]string{
+	"aria-activedescendant":       nil,
+	"aria-atomic":                 {"true", "false"},
+	"aria-autocomplete":           {"inline", "list", "both", "none"},
+	"aria-braillelabel":           nil,
+	"aria-brailleroledescription": nil,
+	"aria-busy":                   {"true", "false"},
+	"aria-checked":                {"true", "false", "mixed", "undefined"},
+	"aria-colcount":               nil,
+	"aria-colindex":               nil,
+	"aria-colindextext":           nil,
+	"aria-colspan":                nil,
+	"aria-controls":               nil,
+	"aria-current":                {"page", "step", "location", "date", "time", "true", "false"},
+	"aria-describedby":            nil,
+	"aria-description":            nil,
+	"aria-details":                nil,
+	"aria-disabled":               {"true", "false"},
+	"aria-dropeffect":             nil,
+	"aria-errormessage":           nil,
+	"aria-expanded":               {"true", "false", "undefined"},
+	"aria-flowto":                 nil,
+	"aria-grabbed":                {"true", "false", "undefined"},
+	"aria-haspopup":               {"true", "false", "menu", "listbox", "tree", "grid", "dialog"},
+	"aria-hidden":                 {"true", "false", "undefined"},
+	"aria-invalid":                {"true", "false", "grammar", "spelling"},
+	"aria-keyshortcuts":           nil,
+	"aria-label":                  nil,
+	"aria-labelledby":             nil,
+	"aria-level":                  nil,
+	"aria-live":                   {"assertive", "off", "polite"},
+	"aria-modal":                  {"true", "false"},
+	"aria-multiline":              {"true", "false"},
+	"aria-multiselectable":        {"true", "false"},
+	"aria-orientation":            {"horizontal", "vertical", "undefined"},
+	"aria-owns":                   nil,
+	"aria-placeholder":            nil,
+	"aria-posinset":               nil,
+	"aria-pressed":                {"true", "false", "mixed", "undefined"},
+	"aria-readonly":               {"true", "false"},
+	"aria-relevant":               nil,
+	"aria-required":               {"true", "false"},
+	"aria-roledescription":        nil,
+	"aria-rowcount":               nil,
+	"aria-rowindex":               nil,
+	"aria-rowindextext":           nil,
+	"aria-rowspan":                nil,
+	"aria-selected":               {"true", "false", "undefined"},
+	"aria-setsize":                nil,
+	"aria-sort":                   {"ascending", "descending", "none", "other"},
+	"aria-valuemax":               nil,
+	"aria-valuemin":               nil,
+	"aria-valuenow":               nil,
+	"aria-valuetext":              nil,
+}
+
+// ariaRoles are the non-abstract roles of WAI-ARIA 1.2.
+var ariaRoles = map[string]bool{
+	"alert": true, "alertdialog": true, "application": true, "article": true, "banner": true,
+	"blockquote": true, "button": true, "caption": true, "cell": true, "checkbox": true,
+	"code": true, "columnheader": true, "combobox": true, "complementary": true, "contentinfo": true,
+	"definition": true, "deletion": true, "dialog": true, "directory": true, "document": true,
+	"emphasis": true, "feed": true, "figure": true, "form": true, "generic": true,
+	"grid": true, "gridcell": true, "group": true, "heading": true, "img": true,
+	"insertion": true, "link": true, "list": true, "listbox": true, "listitem": true,
+	"log": true, "main": true, "marquee": true, "math": true, "menu": true,
+	"menubar": true, "menuitem": true, "menuitemcheckbox": true, "menuitemradio": true, "meter": true,
+	"navigation": true, "none": true, "note": true, "option": true, "paragraph": true,
+	"presentation": true, "progressbar": true, "radio": true, "radiogroup": true, "region": true,
+	"row": true, "rowgroup": true, "rowheader": true, "scrollbar": true, "search": true,
+	"searchbox": true, "separator": true, "slider": true, "spinbutton": true, "status": true,
+	"strong": true, "subscript": true, "superscript": true, "switch": true, "tab": true,
+	"table": true, "tablist": true, "tabpanel": true, "term": true, "textbox": true,
+	"time": true, "timer": true, "toolbar": true, "tooltip": true, "tree": true,
+	"treegrid": true, "treeitem": true,
+}
+
+type AriaCheck struct{}
+
+func (a AriaCheck) Check(nodes []html.Node) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		for _, attr := range node.Attributes {
+			attrElement, ok := attr.(*html.Attribute)
+			if !ok {
+				continue
+			}
+
+			if attrElement.Key == "role" {
+				for _, role := range strings.Fields(attrElement.Value) {
+					if isTwigValue(role) || ariaRoles[role] || strings.HasPrefix(role, "doc-") || strings.HasPrefix(role, "graphics-") {
+						continue
+					}
+
+					errors = append(errors, validation.CheckResult{
+						Message:    fmt.Sprintf("The role %q is not a valid WAI-ARIA role", role),
+						Severity:   validation.SeverityWarning,
+						Identifier: "twig-linter/a11y-invalid-role",
+						Line:       node.Line,
+					})
+				}
+
+				continue
+			}
+
+			if !strings.HasPrefix(attrElement.Key, "aria-") || isTwigValue(attrElement.Key) {
+				continue
+			}
+
+			allowed, known := ariaAttributes[attrElement.Key]
+			if !known {
+				errors = append(errors, validation.CheckResult{
+					Message:    fmt.Sprintf("The attribute %s is not a valid WAI-ARIA attribute", attrElement.Key),
+					Severity:   validation.SeverityWarning,
+					Identifier: "twig-linter/a11y-invalid-aria-attribute",
+					Line:       node.Line,
+				})
+
+				continue
+			}
+
+			value := strings.TrimSpace(attrElement.Value)
+			if allowed == nil || isTwigValue(value) {
+				continue
+			}
+
+			if !slices.Contains(allowed, value) {
+				errors = append(errors, validation.CheckResult{
+					Message:    fmt.Sprintf("The value %q is not allowed for %s, use one of %s", value, attrElement.Key, strings.Join(allowed, ", ")),
+					Severity:   validation.SeverityWarning,
+					Identifier: "twig-linter/a11y-invalid-aria-value",
+					Line:       node.Line,
+				})
+			}
+		}
+	})
+
+	return errors
+}
+
+func (a AriaCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+// Fix lowercases values of enumerated attributes, like aria-hidden="True".
+func (a AriaCheck) Fix(nodes []html.Node) error {
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		for _, attr := range node.Attributes {
+			attrElement, ok := attr.(*html.Attribute)
+			if !ok {
+				continue
+			}
+
+			allowed := ariaAttributes[attrElement.Key]
+			if allowed == nil || isTwigValue(attrElement.Value) {
+				continue
+			}
+
+			if lower := strings.ToLower(strings.TrimSpace(attrElement.Value)); slices.Contains(allowed, lower) {
+				attrElement.Value = lower
+			}
+		}
+	})
+
+	return nil
+}
+
+func init() {
+	twiglinter.AddStorefrontFixer(AriaCheck{})
+}
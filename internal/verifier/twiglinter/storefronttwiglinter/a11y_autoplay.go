@@ -0,0 +1,71 @@
+package storefronttwiglinter
+
+import (
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+// AutoplayCheck reports media playing sound on its own. Muted videos are
+// allowed, as they are commonly used as backgrounds.
+type AutoplayCheck struct{}
+
+func isAutoplayingWithSound(node *html.ElementNode) bool {
+	if node.Tag != "video" && node.Tag != "audio" {
+		return false
+	}
+
+	if _, autoplay := getAttribute(node, "autoplay"); !autoplay {
+		return false
+	}
+
+	_, muted := getAttribute(node, "muted")
+
+	return !muted
+}
+
+func (a AutoplayCheck) Check(nodes []html.Node) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		if !isAutoplayingWithSound(node) {
+			return
+		}
+
+		message := "Videos with autoplay must be muted, sound playing on its own interferes with screen readers"
+		if node.Tag == "audio" {
+			message = "Avoid autoplay on audio, sound playing on its own interferes with screen readers"
+		}
+
+		errors = append(errors, validation.CheckResult{
+			Message:    message,
+			Severity:   validation.SeverityWarning,
+			Identifier: "twig-linter/a11y-media-autoplay",
+			Line:       node.Line,
+		})
+	})
+
+	return errors
+}
+
+func (a AutoplayCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+// Fix mutes autoplaying videos. Audio is left alone, muting it would make it
+// useless.
+func (a AutoplayCheck) Fix(nodes []html.Node) error {
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		if node.Tag == "video" && isAutoplayingWithSound(node) {
+			node.Attributes = append(node.Attributes, &html.Attribute{Key: "muted"})
+		}
+	})
+
+	return nil
+}
+
+func init() {
+	twiglinter.AddStorefrontFixer(AutoplayCheck{})
+}
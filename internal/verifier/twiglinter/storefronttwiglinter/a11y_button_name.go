@@ -0,0 +1,116 @@
+package storefronttwiglinter
+
+import (
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+type ButtonNameCheck struct{}
+
+func (b ButtonNameCheck) Check(nodes []html.Node) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		role, _ := getAttribute(node, "role")
+		inputType, _ := getAttribute(node, "type")
+
+		isButton := node.Tag == "button" || role == "button" || (node.Tag == "input" && inputType == "button")
+		if !isButton || hasDynamicAttributes(node) {
+			return
+		}
+
+		if hasNonEmptyAttribute(node, "aria-label", "aria-labelledby", "title") {
+			return
+		}
+
+		if node.Tag == "input" {
+			if hasNonEmptyAttribute(node, "value") {
+				return
+			}
+		} else if hasAccessibleContent(node.Children) {
+			return
+		}
+
+		errors = append(errors, validation.CheckResult{
+			Message:    "Buttons must have an accessible name: add a text, a visually-hidden text or aria-label, icons alone are not announced",
+			Severity:   validation.SeverityWarning,
+			Identifier: "twig-linter/a11y-button-missing-name",
+			Line:       node.Line,
+		})
+	})
+
+	return errors
+}
+
+// hasAccessibleContent reports whether the content gives an element a name.
+// Content from Twig, like expressions or includes, counts as a name, as it
+// cannot be resolved here.
+func hasAccessibleContent(nodes html.NodeList) bool {
+	for _, node := range nodes {
+		switch node := node.(type) {
+		case *html.RawNode:
+			if strings.TrimSpace(node.Text) != "" {
+				return true
+			}
+		case *html.TemplateExpressionNode, *html.ParentNode:
+			return true
+		case *html.ElementNode:
+			if node.Tag == "img" && hasNonEmptyAttribute(node, "alt") {
+				return true
+			}
+
+			if hasNonEmptyAttribute(node, "aria-label") {
+				return true
+			}
+
+			if value, _ := getAttribute(node, "aria-hidden"); value == "true" {
+				continue
+			}
+
+			if hasAccessibleContent(node.Children) {
+				return true
+			}
+		case *html.TwigBlockNode:
+			if hasAccessibleContent(node.Children) {
+				return true
+			}
+		case *html.TwigIfNode:
+			for _, br := range node.Branches {
+				if hasAccessibleContent(br.Body) {
+					return true
+				}
+			}
+
+			if hasAccessibleContent(node.ElseChildren) {
+				return true
+			}
+		case *html.TwigGenericBlockNode:
+			if node.Name == "trans" || hasAccessibleContent(node.Body) || hasAccessibleContent(node.Else) {
+				return true
+			}
+		case *html.TwigStandaloneTagNode:
+			if node.Name != "sw_icon" && node.Name != "set" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (b ButtonNameCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+func (b ButtonNameCheck) Fix(nodes []html.Node) error {
+	return nil // The name has to be written by hand
+}
+
+func init() {
+	twiglinter.AddStorefrontFixer(ButtonNameCheck{})
+}
@@ -0,0 +1,82 @@
+package storefronttwiglinter
+
+import (
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+// inputTypesWithoutLabel have their accessible name from the value or are
+// not shown at all.
+var inputTypesWithoutLabel = map[string]bool{
+	"hidden": true,
+	"submit": true,
+	"reset":  true,
+	"button": true,
+	"image":  true,
+}
+
+type FormLabelCheck struct{}
+
+func (f FormLabelCheck) Check(nodes []html.Node) []validation.CheckResult {
+	labelFor := map[string]bool{}
+
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		if node.Tag != "label" {
+			return
+		}
+
+		if target, ok := getAttribute(node, "for"); ok {
+			labelFor[target] = true
+		}
+	})
+
+	var errors []validation.CheckResult
+
+	traverseWithAncestors(nodes, nil, func(node *html.ElementNode, ancestors []*html.ElementNode) {
+		if node.Tag != "input" && node.Tag != "select" && node.Tag != "textarea" {
+			return
+		}
+
+		if inputType, _ := getAttribute(node, "type"); node.Tag == "input" && inputTypesWithoutLabel[inputType] {
+			return
+		}
+
+		if hasDynamicAttributes(node) || hasNonEmptyAttribute(node, "aria-label", "aria-labelledby", "title") {
+			return
+		}
+
+		if id, ok := getAttribute(node, "id"); ok && labelFor[id] {
+			return
+		}
+
+		for _, ancestor := range ancestors {
+			if ancestor.Tag == "label" {
+				return
+			}
+		}
+
+		errors = append(errors, validation.CheckResult{
+			Message:    "Form controls must have a label: add a <label for> matching the id, wrap it in a <label> or set aria-label",
+			Severity:   validation.SeverityWarning,
+			Identifier: "twig-linter/a11y-form-control-missing-label",
+			Line:       node.Line,
+		})
+	})
+
+	return errors
+}
+
+func (f FormLabelCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+func (f FormLabelCheck) Fix(nodes []html.Node) error {
+	return nil // The label text has to be written by hand
+}
+
+func init() {
+	twiglinter.AddStorefrontFixer(FormLabelCheck{})
+}
@@ -0,0 +1,56 @@
+package storefronttwiglinter
+
+import (
+	"fmt"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+var headingLevels = map[string]int{"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+
+// HeadingOrderCheck reports skipped heading levels like h2 followed by h4.
+// Templates are rendered into other templates, so the first heading may
+// start at any level.
+type HeadingOrderCheck struct{}
+
+func (h HeadingOrderCheck) Check(nodes []html.Node) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	previous := 0
+
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		level, ok := headingLevels[node.Tag]
+		if !ok {
+			return
+		}
+
+		if previous != 0 && level > previous+1 {
+			errors = append(errors, validation.CheckResult{
+				Message:    fmt.Sprintf("Heading levels should only increase by one, found <%s> after <h%d>", node.Tag, previous),
+				Severity:   validation.SeverityWarning,
+				Identifier: "twig-linter/a11y-heading-level-skipped",
+				Line:       node.Line,
+			})
+		}
+
+		previous = level
+	})
+
+	return errors
+}
+
+func (h HeadingOrderCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+func (h HeadingOrderCheck) Fix(nodes []html.Node) error {
+	return nil // Changing the level changes the styling
+}
+
+func init() {
+	twiglinter.AddStorefrontFixer(HeadingOrderCheck{})
+}
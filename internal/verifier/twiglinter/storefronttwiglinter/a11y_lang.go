@@ -0,0 +1,60 @@
+package storefronttwiglinter
+
+import (
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+// storefrontLangValue is what the base layout of the Storefront uses.
+const storefrontLangValue = "{{ page.metaInformation.xmlLang }}"
+
+type HTMLLangCheck struct{}
+
+func isMissingLang(node *html.ElementNode) bool {
+	return node.Tag == "html" && !hasDynamicAttributes(node) && !hasNonEmptyAttribute(node, "lang")
+}
+
+func (h HTMLLangCheck) Check(nodes []html.Node) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		if isMissingLang(node) {
+			errors = append(errors, validation.CheckResult{
+				Message:    "The <html> element must have a lang attribute, screen readers use it to pick the pronunciation",
+				Severity:   validation.SeverityWarning,
+				Identifier: "twig-linter/a11y-html-missing-lang",
+				Line:       node.Line,
+			})
+		}
+	})
+
+	return errors
+}
+
+func (h HTMLLangCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+func (h HTMLLangCheck) Fix(nodes []html.Node) error {
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		if !isMissingLang(node) {
+			return
+		}
+
+		if attr, ok := findAttribute(node, "lang"); ok {
+			attr.Value = storefrontLangValue
+			return
+		}
+
+		node.Attributes = append(node.Attributes, &html.Attribute{Key: "lang", Value: storefrontLangValue})
+	})
+
+	return nil
+}
+
+func init() {
+	twiglinter.AddStorefrontFixer(HTMLLangCheck{})
+}
@@ -0,0 +1,60 @@
+package storefronttwiglinter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/shyim/go-version"
+
+	"github.com/shopware/shopware-cli/internal/html"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+type TabindexCheck struct{}
+
+func positiveTabindex(node *html.ElementNode) (*html.Attribute, bool) {
+	attr, ok := findAttribute(node, "tabindex")
+	if !ok {
+		return nil, false
+	}
+
+	value, err := strconv.Atoi(strings.TrimSpace(attr.Value))
+
+	return attr, err == nil && value > 0
+}
+
+func (t TabindexCheck) Check(nodes []html.Node) []validation.CheckResult {
+	var errors []validation.CheckResult
+
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		if _, positive := positiveTabindex(node); positive {
+			errors = append(errors, validation.CheckResult{
+				Message:    "Avoid a positive tabindex, it breaks the natural tab order. Use tabindex=\"0\" instead",
+				Severity:   validation.SeverityWarning,
+				Identifier: "twig-linter/a11y-positive-tabindex",
+				Line:       node.Line,
+			})
+		}
+	})
+
+	return errors
+}
+
+func (t TabindexCheck) Supports(v *version.Version) bool {
+	return true
+}
+
+func (t TabindexCheck) Fix(nodes []html.Node) error {
+	html.TraverseNode(nodes, func(node *html.ElementNode) {
+		if attr, positive := positiveTabindex(node); positive {
+			attr.Value = "0"
+		}
+	})
+
+	return nil
+}
+
+func init() {
+	twiglinter.AddStorefrontFixer(TabindexCheck{})
+}
@@ -0,0 +1,146 @@
+package storefronttwiglinter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shopware/shopware-cli/internal/verifier/twiglinter"
+)
+
+type a11yCase struct {
+	name          string
+	content       string
+	expectedCount int
+}
+
+func runA11yCases(t *testing.T, fixer twiglinter.TwigFixer, identifier string, cases []a11yCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			checks, err := twiglinter.RunCheckerOnString(fixer, tc.content)
+			assert.NoError(t, err)
+			assert.Len(t, checks, tc.expectedCount, "Expected %d validation errors but got %d", tc.expectedCount, len(checks))
+
+			for _, check := range checks {
+				assert.Equal(t, identifier, check.Identifier)
+			}
+		})
+	}
+}
+
+func TestFormLabelCheck(t *testing.T) {
+	t.Parallel()
+	runA11yCases(t, FormLabelCheck{}, "twig-linter/a11y-form-control-missing-label", []a11yCase{
+		{"label for id", `<label for="email">Email</label><input type="email" id="email">`, 0},
+		{"wrapped in label", `<label>Email <input type="email"></label>`, 0},
+		{"aria-label", `<input type="search" aria-label="{{ 'header.searchPlaceholder'|trans }}">`, 0},
+		{"hidden and submit inputs", `<input type="hidden" name="a"><input type="submit" value="Send">`, 0},
+		{"placeholder only", `<input type="email" placeholder="Email">`, 1},
+		{"select and textarea", `<select name="a"></select><textarea id="b"></textarea>`, 2},
+		{"label for other id", `<label for="other">Other</label><input id="email">`, 1},
+		{"dynamic attributes", `<input type="text" {% if required %}required{% endif %}>`, 0},
+		{"label in block", `{% block a %}<label for="x">X</label>{% endblock %}{% block b %}<input id="x">{% endblock %}`, 0},
+	})
+}
+
+func TestButtonNameCheck(t *testing.T) {
+	t.Parallel()
+	runA11yCases(t, ButtonNameCheck{}, "twig-linter/a11y-button-missing-name", []a11yCase{
+		{"text", `<button>Save</button>`, 0},
+		{"twig expression", `<button>{{ 'general.save'|trans }}</button>`, 0},
+		{"icon only", `<button class="btn">{% sw_icon 'x' %}</button>`, 1},
+		{"icon with aria-label", `<button aria-label="Close">{% sw_icon 'x' %}</button>`, 0},
+		{"icon with hidden text", `<button>{% sw_icon 'x' %}<span class="visually-hidden">Close</span></button>`, 0},
+		{"aria-hidden text", `<button><span aria-hidden="true">×</span></button>`, 1},
+		{"image with alt", `<button><img src="a.png" alt="Close"></button>`, 0},
+		{"role button", `<div role="button"></div>`, 1},
+		{"input button without value", `<input type="button">`, 1},
+		{"input button with value", `<input type="button" value="Go">`, 0},
+		{"include", `<button>{% sw_include '@Storefront/storefront/utilities/icon.html.twig' %}</button>`, 0},
+	})
+}
+
+func TestAriaCheck(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		content    string
+		identifier string
+	}{
+		{"unknown attribute", `<div aria-labeledby="x"></div>`, "twig-linter/a11y-invalid-aria-attribute"},
+		{"invalid value", `<div aria-hidden="yes"></div>`, "twig-linter/a11y-invalid-aria-value"},
+		{"invalid role", `<div role="buton"></div>`, "twig-linter/a11y-invalid-role"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			checks, err := twiglinter.RunCheckerOnString(AriaCheck{}, tc.content)
+			assert.NoError(t, err)
+			assert.Len(t, checks, 1)
+			assert.Equal(t, tc.identifier, checks[0].Identifier)
+		})
+	}
+
+	checks, err := twiglinter.RunCheckerOnString(AriaCheck{}, `<nav role="navigation doc-toc" aria-label="Menu" aria-expanded="{{ open ? 'true' : 'false' }}" aria-current="page"></nav>`)
+	assert.NoError(t, err)
+	assert.Empty(t, checks)
+
+	fixed, err := twiglinter.RunFixerOnString(AriaCheck{}, `<div aria-hidden="True"></div>`)
+	assert.NoError(t, err)
+	assert.Equal(t, `<div aria-hidden="true"></div>`, fixed)
+}
+
+func TestHeadingOrderCheck(t *testing.T) {
+	t.Parallel()
+	runA11yCases(t, HeadingOrderCheck{}, "twig-linter/a11y-heading-level-skipped", []a11yCase{
+		{"in order", `<h2>A</h2><h3>B</h3><h2>C</h2><h3>D</h3>`, 0},
+		{"starting deep", `<h4>A</h4><h5>B</h5>`, 0},
+		{"skipped", `<h1>A</h1><h3>B</h3>`, 1},
+		{"skipped in nested markup", `<h2>A</h2><div><p><h5>B</h5></p></div>`, 1},
+	})
+}
+
+func TestTabindexCheck(t *testing.T) {
+	t.Parallel()
+	runA11yCases(t, TabindexCheck{}, "twig-linter/a11y-positive-tabindex", []a11yCase{
+		{"zero and negative", `<div tabindex="0"></div><div tabindex="-1"></div>`, 0},
+		{"positive", `<a href="#" tabindex="3">A</a>`, 1},
+		{"twig value", `<div tabindex="{{ index }}"></div>`, 0},
+	})
+
+	fixed, err := twiglinter.RunFixerOnString(TabindexCheck{}, `<a href="#" tabindex="3">A</a>`)
+	assert.NoError(t, err)
+	assert.Equal(t, "<a\n    href=\"#\"\n    tabindex=\"0\"\n>A</a>", fixed)
+}
+
+func TestAutoplayCheck(t *testing.T) {
+	t.Parallel()
+	runA11yCases(t, AutoplayCheck{}, "twig-linter/a11y-media-autoplay", []a11yCase{
+		{"muted video", `<video autoplay muted loop></video>`, 0},
+		{"video with sound", `<video autoplay></video>`, 1},
+		{"audio", `<audio autoplay src="a.mp3"></audio>`, 1},
+		{"no autoplay", `<audio controls></audio>`, 0},
+	})
+
+	fixed, err := twiglinter.RunFixerOnString(AutoplayCheck{}, `<video autoplay></video><audio autoplay></audio>`)
+	assert.NoError(t, err)
+	assert.Equal(t, "<video\n    autoplay\n    muted\n></video><audio autoplay></audio>", fixed)
+}
+
+func TestHTMLLangCheck(t *testing.T) {
+	t.Parallel()
+	runA11yCases(t, HTMLLangCheck{}, "twig-linter/a11y-html-missing-lang", []a11yCase{
+		{"with lang", `<html lang="{{ page.metaInformation.xmlLang }}"></html>`, 0},
+		{"without lang", `<html></html>`, 1},
+		{"empty lang", `<html lang=""></html>`, 1},
+	})
+
+	fixed, err := twiglinter.RunFixerOnString(HTMLLangCheck{}, `<html></html>`)
+	assert.NoError(t, err)
+	assert.Equal(t, `<html lang="{{ page.metaInformation.xmlLang }}"></html>`, fixed)
+}
@@ -0,0 +1,443 @@
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// watchedTools maps file extensions to the tools checking them.
+var watchedTools = map[string][]string{
+	".twig": {"admin-twig", "storefront-twig", "deprecated-blocks", "snippet-usage"},
+	".php":  {"sw-cli", "phpstan"},
+	".js":   {"eslint", "admin-js", "snippet-usage"},
+	".mjs":  {"eslint"},
+	".cjs":  {"eslint"},
+	".ts":   {"eslint", "admin-js", "snippet-usage"},
+	".vue":  {"eslint"},
+	".scss": {"stylelint"},
+	".css":  {"stylelint"},
+	".json": {"eslint", "snippet-usage", "sw-cli"},
+	".xml":  {"sw-cli", "symfony-xml"},
+	".yaml": {"sw-cli"},
+	".yml":  {"sw-cli"},
+}
+
+// watchSkippedDirectories are never watched, they are large and contain
+// generated or installed files.
+var watchSkippedDirectories = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	".git":         true,
+	"dist":         true,
+	".tmp":         true,
+}
+
+// ForChangedFiles returns the tools of the list checking one of the files.
+func (tl ToolList) ForChangedFiles(files []string) ToolList {
+	names := map[string]bool{}
+
+	for _, file := range files {
+		// the autoloading and the required Shopware version affect the analysis of all PHP files
+		if filepath.Base(file) == "composer.json" {
+			names["phpstan"] = true
+			names["sw-cli"] = true
+		}
+
+		for _, name := range watchedTools[filepath.Ext(file)] {
+			names[name] = true
+		}
+	}
+
+	var tools ToolList
+
+	for _, tool := range tl {
+		if names[tool.Name()] {
+			tools = append(tools, tool)
+		}
+	}
+
+	return tools
+}
+
+// WatchUpdate is reported after each run of the watcher.
+type WatchUpdate struct {
+	// Changed are the files which triggered the run, empty for the first run
+	Changed []string
+	// Tools are the names of the tools which ran
+	Tools    []string
+	Results  []validation.CheckResult
+	New      []validation.CheckResult
+	Resolved []validation.CheckResult
+	Duration time.Duration
+	Err      error
+}
+
+// Watcher re-runs the tools checking the changed files of the source
+// directories. Changes are received from file system notifications, mounted
+// directories in containers often do not deliver them and need polling.
+type Watcher struct {
+	Tools  ToolList
+	Config ToolConfig
+	// Poll walks the source directories every Interval instead of using file system notifications
+	Poll bool
+	// Interval is how long changes are collected before a run, or how often the directories are polled
+	Interval time.Duration
+	// OnUpdate is called after each run
+	OnUpdate func(WatchUpdate)
+
+	mu      sync.Mutex
+	results map[string][]validation.CheckResult
+}
+
+// Run checks everything once and then on every change until the context is
+// cancelled.
+func (w *Watcher) Run(ctx context.Context) error {
+	w.results = map[string][]validation.CheckResult{}
+
+	if w.Poll {
+		if w.Interval == 0 {
+			w.Interval = time.Second
+		}
+
+		return w.runPolling(ctx)
+	}
+
+	if w.Interval == 0 {
+		w.Interval = 200 * time.Millisecond
+	}
+
+	return w.runNotify(ctx)
+}
+
+func (w *Watcher) runNotify(ctx context.Context) error {
+	notify, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot watch for file changes, use polling instead: %w", err)
+	}
+
+	defer func() {
+		_ = notify.Close()
+	}()
+
+	// composer.json is watched on its own, the root directory is not added recursively
+	if err := notify.Add(w.Config.RootDir); err != nil {
+		return fmt.Errorf("cannot watch %s: %w", w.Config.RootDir, err)
+	}
+
+	for _, dir := range w.Config.SourceDirectories {
+		if _, err := w.addRecursive(notify, dir); err != nil {
+			return fmt.Errorf("cannot watch %s: %w", dir, err)
+		}
+	}
+
+	w.run(ctx, nil, w.Tools)
+
+	pending := map[string]bool{}
+
+	var debounce <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-notify.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Op == fsnotify.Chmod || !w.watches(event.Name) {
+				continue
+			}
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// files written before the directory was added are not notified
+					files, err := w.addRecursive(notify, event.Name)
+					if err != nil {
+						logging.FromContext(ctx).Warnf("Cannot watch %s: %v", event.Name, err)
+					}
+
+					for _, file := range files {
+						pending[file] = true
+					}
+
+					debounce = time.After(w.Interval)
+
+					continue
+				}
+			}
+
+			pending[event.Name] = true
+			debounce = time.After(w.Interval)
+		case err, ok := <-notify.Errors:
+			if !ok {
+				return nil
+			}
+
+			logging.FromContext(ctx).Warnf("File watcher error: %v", err)
+		case <-debounce:
+			debounce = nil
+
+			changed := slices.Sorted(maps.Keys(pending))
+			pending = map[string]bool{}
+
+			if tools := w.Tools.ForChangedFiles(changed); len(tools) > 0 {
+				w.run(ctx, changed, tools)
+			}
+		}
+	}
+}
+
+// addRecursive watches the directory and its subdirectories and returns the files found in them.
+func (w *Watcher) addRecursive(notify *fsnotify.Watcher, dir string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			//nolint: nilerr
+			return nil
+		}
+
+		if !d.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+
+		if path != dir && watchSkippedDirectories[d.Name()] {
+			return filepath.SkipDir
+		}
+
+		return notify.Add(path)
+	})
+
+	return files, err
+}
+
+// watches reports whether a changed path is the composer.json or inside a source directory.
+func (w *Watcher) watches(path string) bool {
+	if path == filepath.Join(w.Config.RootDir, "composer.json") {
+		return true
+	}
+
+	for _, dir := range w.Config.SourceDirectories {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		for _, part := range strings.Split(rel, string(filepath.Separator)) {
+			if watchSkippedDirectories[part] {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func (w *Watcher) runPolling(ctx context.Context) error {
+	snapshot := w.snapshot()
+	w.run(ctx, nil, w.Tools)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			current := w.snapshot()
+			changed := changedSnapshotFiles(snapshot, current)
+			snapshot = current
+
+			if len(changed) == 0 {
+				continue
+			}
+
+			if tools := w.Tools.ForChangedFiles(changed); len(tools) > 0 {
+				w.run(ctx, changed, tools)
+			}
+		}
+	}
+}
+
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+}
+
+func (w *Watcher) snapshot() map[string]watchedFile {
+	files := map[string]watchedFile{}
+
+	composerJSON := filepath.Join(w.Config.RootDir, "composer.json")
+	if info, err := os.Stat(composerJSON); err == nil {
+		files[composerJSON] = watchedFile{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	for _, dir := range w.Config.SourceDirectories {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				//nolint: nilerr
+				return nil
+			}
+
+			if d.IsDir() {
+				if watchSkippedDirectories[d.Name()] {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				//nolint: nilerr
+				return nil
+			}
+
+			files[path] = watchedFile{modTime: info.ModTime(), size: info.Size()}
+
+			return nil
+		})
+	}
+
+	return files
+}
+
+// changedSnapshotFiles returns the added, modified and removed files.
+func changedSnapshotFiles(before, after map[string]watchedFile) []string {
+	var changed []string
+
+	for path, file := range after {
+		if previous, ok := before[path]; !ok || previous != file {
+			changed = append(changed, path)
+		}
+	}
+
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+// run checks with every tool on its own, so the results of a tool replace
+// the ones of its previous run.
+func (w *Watcher) run(ctx context.Context, changed []string, tools ToolList) {
+	start := time.Now()
+	update := WatchUpdate{Changed: changed}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	previous := w.allResults()
+
+	var errs []string
+
+	for _, tool := range tools {
+		check := NewCheck()
+
+		update.Tools = append(update.Tools, tool.Name())
+
+		if err := (ToolList{tool}).Check(ctx, check, w.Config); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", tool.Name(), err))
+		}
+
+		check.ApplySeverityOverrides(w.Config.SeverityOverrides).RemoveByIdentifier(w.Config.ValidationIgnores)
+		w.results[tool.Name()] = check.GetResults()
+	}
+
+	if len(errs) > 0 {
+		update.Err = fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	update.Results = w.allResults()
+	update.New, update.Resolved = diffResults(previous, update.Results)
+	update.Duration = time.Since(start)
+
+	if changed == nil {
+		// everything is new on the first run
+		update.New = nil
+	}
+
+	if w.OnUpdate != nil {
+		w.OnUpdate(update)
+	}
+}
+
+func (w *Watcher) allResults() []validation.CheckResult {
+	var results []validation.CheckResult
+
+	for _, name := range slices.Sorted(maps.Keys(w.results)) {
+		results = append(results, w.results[name]...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Path != results[j].Path {
+			return results[i].Path < results[j].Path
+		}
+
+		return results[i].Line < results[j].Line
+	})
+
+	return results
+}
+
+// diffResults compares findings without their line, so findings moved by an
+// edit are neither new nor resolved.
+func diffResults(before, after []validation.CheckResult) (added, resolved []validation.CheckResult) {
+	key := findingKey
+
+	count := func(results []validation.CheckResult) map[string]int {
+		counts := map[string]int{}
+		for _, r := range results {
+			counts[key(r)]++
+		}
+
+		return counts
+	}
+
+	beforeCounts := count(before)
+	afterCounts := count(after)
+
+	for _, r := range after {
+		if beforeCounts[key(r)] > 0 {
+			beforeCounts[key(r)]--
+			continue
+		}
+
+		added = append(added, r)
+	}
+
+	for _, r := range before {
+		if afterCounts[key(r)] > 0 {
+			afterCounts[key(r)]--
+			continue
+		}
+
+		resolved = append(resolved, r)
+	}
+
+	return added, resolved
+}
@@ -0,0 +1,252 @@
+package verifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shopware/shopware-cli/internal/validation"
+)
+
+type watchTestTool struct {
+	name  string
+	check func(check *Check, config ToolConfig)
+}
+
+func (w watchTestTool) Name() string {
+	return w.name
+}
+
+func (w watchTestTool) Check(ctx context.Context, check *Check, config ToolConfig) error {
+	w.check(check, config)
+
+	return nil
+}
+
+func (w watchTestTool) Fix(ctx context.Context, config ToolConfig) error {
+	return nil
+}
+
+func (w watchTestTool) Format(ctx context.Context, config ToolConfig, dryRun bool) error {
+	return nil
+}
+
+func TestForChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	noop := func(*Check, ToolConfig) {}
+	tools := ToolList{
+		watchTestTool{name: "phpstan", check: noop},
+		watchTestTool{name: "eslint", check: noop},
+		watchTestTool{name: "stylelint", check: noop},
+		watchTestTool{name: "admin-twig", check: noop},
+		watchTestTool{name: "sw-cli", check: noop},
+		watchTestTool{name: "symfony-xml", check: noop},
+	}
+
+	names := func(tl ToolList) []string {
+		var result []string
+		for _, tool := range tl {
+			result = append(result, tool.Name())
+		}
+
+		return result
+	}
+
+	assert.Equal(t, []string{"phpstan", "sw-cli"}, names(tools.ForChangedFiles([]string{"/src/Foo.php"})))
+	assert.Equal(t, []string{"eslint", "stylelint"}, names(tools.ForChangedFiles([]string{"/src/main.js", "/src/base.scss"})))
+	assert.Equal(t, []string{"admin-twig"}, names(tools.ForChangedFiles([]string{"/src/index.html.twig"})))
+	assert.Equal(t, []string{"phpstan", "eslint", "sw-cli"}, names(tools.ForChangedFiles([]string{"/composer.json"})))
+	assert.Equal(t, []string{"sw-cli", "symfony-xml"}, names(tools.ForChangedFiles([]string{"/src/Resources/config/services.xml"})))
+	assert.Empty(t, tools.ForChangedFiles([]string{"/src/README.md"}))
+}
+
+func TestChangedSnapshotFiles(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	before := map[string]watchedFile{
+		"a.php": {modTime: now, size: 1},
+		"b.php": {modTime: now, size: 1},
+		"c.php": {modTime: now, size: 1},
+	}
+
+	after := map[string]watchedFile{
+		"a.php": {modTime: now, size: 1},
+		"b.php": {modTime: now.Add(time.Second), size: 1},
+		"d.php": {modTime: now, size: 1},
+	}
+
+	assert.Equal(t, []string{"b.php", "c.php", "d.php"}, changedSnapshotFiles(before, after))
+	assert.Empty(t, changedSnapshotFiles(before, before))
+}
+
+func TestDiffResults(t *testing.T) {
+	t.Parallel()
+
+	kept := validation.CheckResult{Path: "a.php", Line: 1, Identifier: "kept", Message: "kept"}
+	moved := kept
+	moved.Line = 5
+	fixed := validation.CheckResult{Path: "a.php", Line: 2, Identifier: "fixed", Message: "fixed"}
+	added := validation.CheckResult{Path: "b.php", Line: 3, Identifier: "added", Message: "added"}
+
+	newResults, resolved := diffResults([]validation.CheckResult{kept, fixed}, []validation.CheckResult{moved, added})
+
+	assert.Equal(t, []validation.CheckResult{added}, newResults)
+	assert.Equal(t, []validation.CheckResult{fixed}, resolved)
+}
+
+func TestWatcherRerunsToolsOfChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("notify", func(t *testing.T) {
+		t.Parallel()
+		testWatcherRerunsToolsOfChangedFiles(t, false)
+	})
+
+	t.Run("poll", func(t *testing.T) {
+		t.Parallel()
+		testWatcherRerunsToolsOfChangedFiles(t, true)
+	})
+}
+
+func testWatcherRerunsToolsOfChangedFiles(t *testing.T, poll bool) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "Foo.php")
+	require.NoError(t, os.WriteFile(file, []byte("<?php"), 0o644))
+
+	eslintRuns := 0
+
+	tools := ToolList{
+		watchTestTool{name: "phpstan", check: func(check *Check, config ToolConfig) {
+			content, _ := os.ReadFile(file)
+			if len(content) > len("<?php") {
+				check.AddResult(validation.CheckResult{Path: "Foo.php", Identifier: "phpstan/error", Message: "broken", Severity: validation.SeverityError})
+			}
+		}},
+		watchTestTool{name: "eslint", check: func(*Check, ToolConfig) {
+			eslintRuns++
+		}},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	updates := make(chan WatchUpdate, 2)
+
+	watcher := &Watcher{
+		Tools:    tools,
+		Config:   ToolConfig{RootDir: dir, SourceDirectories: []string{dir}},
+		Poll:     poll,
+		Interval: 10 * time.Millisecond,
+		OnUpdate: func(update WatchUpdate) {
+			updates <- update
+		},
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- watcher.Run(ctx)
+	}()
+
+	first := <-updates
+	assert.Nil(t, first.Changed)
+	assert.Equal(t, []string{"phpstan", "eslint"}, first.Tools)
+	assert.Empty(t, first.Results)
+
+	require.NoError(t, os.WriteFile(file, []byte("<?php echo"), 0o644))
+
+	second := <-updates
+	assert.Equal(t, []string{file}, second.Changed)
+	assert.Equal(t, []string{"phpstan"}, second.Tools)
+	assert.Len(t, second.New, 1)
+	assert.Empty(t, second.Resolved)
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, eslintRuns)
+}
+
+func TestWatcherWatchesNewDirectories(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules"), 0o755))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	updates := make(chan WatchUpdate, 2)
+
+	watcher := &Watcher{
+		Tools:    ToolList{watchTestTool{name: "phpstan", check: func(*Check, ToolConfig) {}}},
+		Config:   ToolConfig{RootDir: dir, SourceDirectories: []string{dir}},
+		Interval: 10 * time.Millisecond,
+		OnUpdate: func(update WatchUpdate) {
+			updates <- update
+		},
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- watcher.Run(ctx)
+	}()
+
+	<-updates
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "node_modules", "ignored.php"), []byte("<?php"), 0o644))
+
+	file := filepath.Join(dir, "src", "Controller", "Foo.php")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("<?php"), 0o644))
+
+	update := <-updates
+	assert.Equal(t, []string{file}, update.Changed)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestWatcherWatches(t *testing.T) {
+	t.Parallel()
+
+	watcher := &Watcher{Config: ToolConfig{RootDir: "/project", SourceDirectories: []string{"/project/src"}}}
+
+	assert.True(t, watcher.watches("/project/composer.json"))
+	assert.True(t, watcher.watches("/project/src/Foo.php"))
+	assert.False(t, watcher.watches("/project/src/Resources/app/administration/node_modules/foo/index.js"))
+	assert.False(t, watcher.watches("/project/README.md"))
+	assert.False(t, watcher.watches("/project/srcfoo/Foo.php"))
+}
+
+func TestWatchModelMarksNewFindings(t *testing.T) {
+	t.Parallel()
+
+	finding := validation.CheckResult{Path: "Foo.php", Line: 3, Identifier: "phpstan/error", Message: "broken", Severity: validation.SeverityError}
+	fixed := validation.CheckResult{Path: "Bar.php", Identifier: "phpstan/error", Message: "fixed", Severity: validation.SeverityError}
+
+	model := NewWatchModel("my-extension")
+	assert.Contains(t, model.View().Content, "Running all tools")
+
+	model.Update(WatchUpdateMsg{
+		Changed:  []string{"/ext/src/Foo.php"},
+		Tools:    []string{"phpstan"},
+		Results:  []validation.CheckResult{finding},
+		New:      []validation.CheckResult{finding},
+		Resolved: []validation.CheckResult{fixed},
+	})
+
+	view := model.View().Content
+	assert.Contains(t, view, "Foo.php:3")
+	assert.Contains(t, view, "+ ")
+	assert.Contains(t, view, "Resolved")
+	assert.Contains(t, view, "after changing Foo.php")
+}
@@ -0,0 +1,243 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/mattn/go-isatty"
+
+	"github.com/shopware/shopware-cli/internal/tui"
+	"github.com/shopware/shopware-cli/internal/validation"
+	"github.com/shopware/shopware-cli/logging"
+)
+
+// Watch runs the watcher with the TUI until it is quit. Without a terminal
+// the new and resolved findings are logged instead. Poll walks the directories
+// instead of using file system notifications.
+func Watch(ctx context.Context, tools ToolList, config ToolConfig, title string, poll bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	watcher := &Watcher{Tools: tools, Config: config, Poll: poll}
+
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		watcher.OnUpdate = func(update WatchUpdate) {
+			logWatchUpdate(ctx, update)
+		}
+
+		return watcher.Run(ctx)
+	}
+
+	program := tea.NewProgram(NewWatchModel(title), tea.WithContext(ctx))
+	watcher.OnUpdate = func(update WatchUpdate) {
+		program.Send(WatchUpdateMsg(update))
+	}
+
+	watchErr := make(chan error, 1)
+
+	go func() {
+		watchErr <- watcher.Run(ctx)
+	}()
+
+	if _, err := program.Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+		return err
+	}
+
+	cancel()
+
+	return <-watchErr
+}
+
+func logWatchUpdate(ctx context.Context, update WatchUpdate) {
+	logger := logging.FromContext(ctx)
+
+	if update.Err != nil {
+		logger.Errorf("%v", update.Err)
+	}
+
+	if update.Changed == nil {
+		for _, r := range update.Results {
+			logger.Infof("%s", formatFinding(r))
+		}
+	}
+
+	for _, r := range update.New {
+		logger.Infof("+ %s", formatFinding(r))
+	}
+
+	for _, r := range update.Resolved {
+		logger.Infof("- %s", formatFinding(r))
+	}
+
+	logger.Infof("%d findings, ran %s in %s", len(update.Results), strings.Join(update.Tools, ", "), update.Duration.Round(100*time.Millisecond))
+}
+
+// WatchUpdateMsg delivers a WatchUpdate to the TUI.
+type WatchUpdateMsg WatchUpdate
+
+// WatchModel shows the current findings of the watcher. Findings of the last
+// run are marked as new, resolved ones are listed below.
+type WatchModel struct {
+	title  string
+	update *WatchUpdate
+	new    map[string]bool
+	offset int
+	width  int
+	height int
+}
+
+// NewWatchModel creates the TUI model. The title is shown in the header,
+// e.g. the path of the extension.
+func NewWatchModel(title string) *WatchModel {
+	return &WatchModel{title: title}
+}
+
+func (m *WatchModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *WatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case WatchUpdateMsg:
+		update := WatchUpdate(msg)
+		m.update = &update
+		m.new = map[string]bool{}
+
+		for _, r := range update.New {
+			m.new[findingKey(r)] = true
+		}
+
+		m.offset = min(m.offset, max(len(update.Results)-1, 0))
+	case tea.KeyPressMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			m.offset = max(m.offset-1, 0)
+		case "down", "j":
+			if m.update != nil && m.offset < len(m.update.Results)-1 {
+				m.offset++
+			}
+		case "home", "g":
+			m.offset = 0
+		}
+	}
+
+	return m, nil
+}
+
+func (m *WatchModel) View() tea.View {
+	var b strings.Builder
+
+	b.WriteString(tui.SectionTitleStyle.Render("Validate") + " " + tui.DimStyle.Render(m.title) + "\n\n")
+
+	if m.update == nil {
+		b.WriteString(tui.DimStyle.Render("  Running all tools…") + "\n")
+
+		return tea.NewView(b.String())
+	}
+
+	b.WriteString(m.renderStatus() + "\n\n")
+
+	results := m.update.Results
+	if len(results) == 0 {
+		b.WriteString("  " + tui.Checkmark + " No findings\n")
+	}
+
+	listHeight := 20
+	if m.height > 0 {
+		listHeight = max(m.height-10-min(len(m.update.Resolved), 5), 3)
+	}
+
+	end := min(len(results), m.offset+listHeight)
+
+	for _, r := range results[m.offset:end] {
+		marker := "  "
+		if m.new[findingKey(r)] {
+			marker = lipgloss.NewStyle().Foreground(tui.WarnColor).Bold(true).Render("+ ")
+		}
+
+		b.WriteString(marker + formatFinding(r) + "\n")
+	}
+
+	if hidden := len(results) - end; hidden > 0 {
+		b.WriteString(tui.DimStyle.Render(fmt.Sprintf("  … %d more", hidden)) + "\n")
+	}
+
+	if len(m.update.Resolved) > 0 {
+		b.WriteString("\n" + tui.BoldStyle.Render("Resolved") + "\n")
+
+		for i, r := range m.update.Resolved {
+			if i == 5 {
+				b.WriteString(tui.DimStyle.Render(fmt.Sprintf("  … %d more", len(m.update.Resolved)-i)) + "\n")
+				break
+			}
+
+			b.WriteString(lipgloss.NewStyle().Foreground(tui.SuccessColor).Render("- ") + tui.DimStyle.Strikethrough(true).Render(formatFinding(r)) + "\n")
+		}
+	}
+
+	b.WriteString("\n" + tui.DimStyle.Render("↑/↓ scroll • q quit"))
+
+	return tea.NewView(b.String())
+}
+
+func (m *WatchModel) renderStatus() string {
+	errors, warnings := 0, 0
+
+	for _, r := range m.update.Results {
+		if r.Severity == validation.SeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	status := fmt.Sprintf("%s %s  %s %s",
+		lipgloss.NewStyle().Foreground(tui.ErrorColor).Render(fmt.Sprintf("%d", errors)), "errors",
+		lipgloss.NewStyle().Foreground(tui.WarnColor).Render(fmt.Sprintf("%d", warnings)), "warnings")
+
+	run := fmt.Sprintf("ran %s in %s", strings.Join(m.update.Tools, ", "), m.update.Duration.Round(100*time.Millisecond))
+	if len(m.update.Changed) > 0 {
+		run += " after changing " + filepath.Base(m.update.Changed[0])
+		if len(m.update.Changed) > 1 {
+			run += fmt.Sprintf(" and %d more", len(m.update.Changed)-1)
+		}
+	}
+
+	status += "  " + tui.DimStyle.Render(run)
+
+	if m.update.Err != nil {
+		status += "\n" + lipgloss.NewStyle().Foreground(tui.ErrorColor).Render(m.update.Err.Error())
+	}
+
+	return status
+}
+
+func formatFinding(r validation.CheckResult) string {
+	severity := lipgloss.NewStyle().Foreground(tui.WarnColor).Render("warning")
+	if r.Severity == validation.SeverityError {
+		severity = lipgloss.NewStyle().Foreground(tui.ErrorColor).Render("error  ")
+	}
+
+	location := r.Path
+	if r.Line > 0 {
+		location = fmt.Sprintf("%s:%d", r.Path, r.Line)
+	}
+
+	return fmt.Sprintf("%s %s %s %s", severity, location, r.Message, tui.DimStyle.Render(r.Identifier))
+}
+
+func findingKey(r validation.CheckResult) string {
+	return r.Path + "\x00" + r.Identifier + "\x00" + r.Message
+}